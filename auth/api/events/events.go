@@ -0,0 +1,24 @@
+// Package events declares the events the auth service publishes to the message bus, which other services subscribe
+// to. Payloads are encoded as JSON.
+package events
+
+import "time"
+
+// SubjectUserDeleted is the subject UserDeleted events are published under.
+const SubjectUserDeleted = "user.deleted"
+
+// UserDeleted is published after a user deleted their account. Services keeping data of the user must delete it.
+type UserDeleted struct {
+	UserID    string    `json:"user_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// SubjectTokenRevoked is the subject TokenRevoked events are published under.
+const SubjectTokenRevoked = "token.revoked"
+
+// TokenRevoked is published after an access token was revoked, on sign out or through the revocation endpoint.
+// Services validating access tokens themselves must reject the token with the ID (jti) until it expires at ExpiresAt.
+type TokenRevoked struct {
+	TokenID   string    `json:"token_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
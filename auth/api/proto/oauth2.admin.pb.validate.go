@@ -0,0 +1,1647 @@
+// Code generated by protoc-gen-validate. DO NOT EDIT.
+// source: oauth2.admin.proto
+
+package proto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ensure the imports are used
+var (
+	_ = bytes.MinRead
+	_ = errors.New("")
+	_ = fmt.Print
+	_ = utf8.UTFMax
+	_ = (*regexp.Regexp)(nil)
+	_ = (*strings.Reader)(nil)
+	_ = net.IPv4len
+	_ = time.Duration(0)
+	_ = (*url.URL)(nil)
+	_ = (*mail.Address)(nil)
+	_ = anypb.Any{}
+	_ = sort.Sort
+)
+
+// Validate checks the field values on AdministeredUser with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *AdministeredUser) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AdministeredUser with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// AdministeredUserMultiError, or nil if none found.
+func (m *AdministeredUser) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AdministeredUser) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for Username
+
+	// no validation rules for Email
+
+	// no validation rules for EmailVerified
+
+	// no validation rules for Role
+
+	// no validation rules for Disabled
+
+	if len(errors) > 0 {
+		return AdministeredUserMultiError(errors)
+	}
+
+	return nil
+}
+
+// AdministeredUserMultiError is an error wrapping multiple validation errors
+// returned by AdministeredUser.ValidateAll() if the designated constraints
+// aren't met.
+type AdministeredUserMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AdministeredUserMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AdministeredUserMultiError) AllErrors() []error { return m }
+
+// AdministeredUserValidationError is the validation error returned by
+// AdministeredUser.Validate if the designated constraints aren't met.
+type AdministeredUserValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AdministeredUserValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AdministeredUserValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AdministeredUserValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AdministeredUserValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AdministeredUserValidationError) ErrorName() string { return "AdministeredUserValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AdministeredUserValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAdministeredUser.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AdministeredUserValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AdministeredUserValidationError{}
+
+// Validate checks the field values on ListUsersRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *ListUsersRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListUsersRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListUsersRequestMultiError, or nil if none found.
+func (m *ListUsersRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListUsersRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AccessToken
+
+	// no validation rules for Query
+
+	// no validation rules for After
+
+	if m.GetLimit() < 0 {
+		err := ListUsersRequestValidationError{
+			field:  "Limit",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return ListUsersRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ListUsersRequestMultiError is an error wrapping multiple validation errors
+// returned by ListUsersRequest.ValidateAll() if the designated constraints
+// aren't met.
+type ListUsersRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListUsersRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListUsersRequestMultiError) AllErrors() []error { return m }
+
+// ListUsersRequestValidationError is the validation error returned by
+// ListUsersRequest.Validate if the designated constraints aren't met.
+type ListUsersRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListUsersRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListUsersRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListUsersRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListUsersRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListUsersRequestValidationError) ErrorName() string { return "ListUsersRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ListUsersRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListUsersRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListUsersRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListUsersRequestValidationError{}
+
+// Validate checks the field values on ListUsersResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *ListUsersResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListUsersResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListUsersResponseMultiError, or nil if none found.
+func (m *ListUsersResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListUsersResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetUsers() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ListUsersResponseValidationError{
+						field:  fmt.Sprintf("Users[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ListUsersResponseValidationError{
+						field:  fmt.Sprintf("Users[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ListUsersResponseValidationError{
+					field:  fmt.Sprintf("Users[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for Next
+
+	if len(errors) > 0 {
+		return ListUsersResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// ListUsersResponseMultiError is an error wrapping multiple validation errors
+// returned by ListUsersResponse.ValidateAll() if the designated constraints
+// aren't met.
+type ListUsersResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListUsersResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListUsersResponseMultiError) AllErrors() []error { return m }
+
+// ListUsersResponseValidationError is the validation error returned by
+// ListUsersResponse.Validate if the designated constraints aren't met.
+type ListUsersResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListUsersResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListUsersResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListUsersResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListUsersResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListUsersResponseValidationError) ErrorName() string {
+	return "ListUsersResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListUsersResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListUsersResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListUsersResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListUsersResponseValidationError{}
+
+// Validate checks the field values on UserSession with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *UserSession) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UserSession with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in UserSessionMultiError, or
+// nil if none found.
+func (m *UserSession) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UserSession) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for SessionId
+
+	// no validation rules for ClientId
+
+	// no validation rules for Scope
+
+	// no validation rules for ExpiresAt
+
+	if len(errors) > 0 {
+		return UserSessionMultiError(errors)
+	}
+
+	return nil
+}
+
+// UserSessionMultiError is an error wrapping multiple validation errors
+// returned by UserSession.ValidateAll() if the designated constraints aren't met.
+type UserSessionMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UserSessionMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UserSessionMultiError) AllErrors() []error { return m }
+
+// UserSessionValidationError is the validation error returned by
+// UserSession.Validate if the designated constraints aren't met.
+type UserSessionValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UserSessionValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UserSessionValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UserSessionValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UserSessionValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UserSessionValidationError) ErrorName() string { return "UserSessionValidationError" }
+
+// Error satisfies the builtin error interface
+func (e UserSessionValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUserSession.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UserSessionValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UserSessionValidationError{}
+
+// Validate checks the field values on ListUserSessionsRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ListUserSessionsRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListUserSessionsRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListUserSessionsRequestMultiError, or nil if none found.
+func (m *ListUserSessionsRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListUserSessionsRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AccessToken
+
+	if utf8.RuneCountInString(m.GetUserId()) < 1 {
+		err := ListUserSessionsRequestValidationError{
+			field:  "UserId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return ListUserSessionsRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ListUserSessionsRequestMultiError is an error wrapping multiple validation
+// errors returned by ListUserSessionsRequest.ValidateAll() if the designated
+// constraints aren't met.
+type ListUserSessionsRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListUserSessionsRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListUserSessionsRequestMultiError) AllErrors() []error { return m }
+
+// ListUserSessionsRequestValidationError is the validation error returned by
+// ListUserSessionsRequest.Validate if the designated constraints aren't met.
+type ListUserSessionsRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListUserSessionsRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListUserSessionsRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListUserSessionsRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListUserSessionsRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListUserSessionsRequestValidationError) ErrorName() string {
+	return "ListUserSessionsRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListUserSessionsRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListUserSessionsRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListUserSessionsRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListUserSessionsRequestValidationError{}
+
+// Validate checks the field values on ListUserSessionsResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ListUserSessionsResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListUserSessionsResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ListUserSessionsResponseMultiError, or nil if none found.
+func (m *ListUserSessionsResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListUserSessionsResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetSessions() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ListUserSessionsResponseValidationError{
+						field:  fmt.Sprintf("Sessions[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ListUserSessionsResponseValidationError{
+						field:  fmt.Sprintf("Sessions[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ListUserSessionsResponseValidationError{
+					field:  fmt.Sprintf("Sessions[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return ListUserSessionsResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// ListUserSessionsResponseMultiError is an error wrapping multiple validation
+// errors returned by ListUserSessionsResponse.ValidateAll() if the designated
+// constraints aren't met.
+type ListUserSessionsResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListUserSessionsResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListUserSessionsResponseMultiError) AllErrors() []error { return m }
+
+// ListUserSessionsResponseValidationError is the validation error returned by
+// ListUserSessionsResponse.Validate if the designated constraints aren't met.
+type ListUserSessionsResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListUserSessionsResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListUserSessionsResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListUserSessionsResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListUserSessionsResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListUserSessionsResponseValidationError) ErrorName() string {
+	return "ListUserSessionsResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListUserSessionsResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListUserSessionsResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListUserSessionsResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListUserSessionsResponseValidationError{}
+
+// Validate checks the field values on SetUserDisabledRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *SetUserDisabledRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SetUserDisabledRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SetUserDisabledRequestMultiError, or nil if none found.
+func (m *SetUserDisabledRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SetUserDisabledRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AccessToken
+
+	if utf8.RuneCountInString(m.GetUserId()) < 1 {
+		err := SetUserDisabledRequestValidationError{
+			field:  "UserId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Disabled
+
+	if len(errors) > 0 {
+		return SetUserDisabledRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// SetUserDisabledRequestMultiError is an error wrapping multiple validation
+// errors returned by SetUserDisabledRequest.ValidateAll() if the designated
+// constraints aren't met.
+type SetUserDisabledRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SetUserDisabledRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SetUserDisabledRequestMultiError) AllErrors() []error { return m }
+
+// SetUserDisabledRequestValidationError is the validation error returned by
+// SetUserDisabledRequest.Validate if the designated constraints aren't met.
+type SetUserDisabledRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SetUserDisabledRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SetUserDisabledRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SetUserDisabledRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SetUserDisabledRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SetUserDisabledRequestValidationError) ErrorName() string {
+	return "SetUserDisabledRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e SetUserDisabledRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSetUserDisabledRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SetUserDisabledRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SetUserDisabledRequestValidationError{}
+
+// Validate checks the field values on SetUserDisabledResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *SetUserDisabledResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SetUserDisabledResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SetUserDisabledResponseMultiError, or nil if none found.
+func (m *SetUserDisabledResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SetUserDisabledResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return SetUserDisabledResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// SetUserDisabledResponseMultiError is an error wrapping multiple validation
+// errors returned by SetUserDisabledResponse.ValidateAll() if the designated
+// constraints aren't met.
+type SetUserDisabledResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SetUserDisabledResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SetUserDisabledResponseMultiError) AllErrors() []error { return m }
+
+// SetUserDisabledResponseValidationError is the validation error returned by
+// SetUserDisabledResponse.Validate if the designated constraints aren't met.
+type SetUserDisabledResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SetUserDisabledResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SetUserDisabledResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SetUserDisabledResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SetUserDisabledResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SetUserDisabledResponseValidationError) ErrorName() string {
+	return "SetUserDisabledResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e SetUserDisabledResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSetUserDisabledResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SetUserDisabledResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SetUserDisabledResponseValidationError{}
+
+// Validate checks the field values on ForcePasswordResetRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ForcePasswordResetRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ForcePasswordResetRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ForcePasswordResetRequestMultiError, or nil if none found.
+func (m *ForcePasswordResetRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ForcePasswordResetRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AccessToken
+
+	if utf8.RuneCountInString(m.GetUserId()) < 1 {
+		err := ForcePasswordResetRequestValidationError{
+			field:  "UserId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return ForcePasswordResetRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ForcePasswordResetRequestMultiError is an error wrapping multiple validation
+// errors returned by ForcePasswordResetRequest.ValidateAll() if the
+// designated constraints aren't met.
+type ForcePasswordResetRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ForcePasswordResetRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ForcePasswordResetRequestMultiError) AllErrors() []error { return m }
+
+// ForcePasswordResetRequestValidationError is the validation error returned by
+// ForcePasswordResetRequest.Validate if the designated constraints aren't met.
+type ForcePasswordResetRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ForcePasswordResetRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ForcePasswordResetRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ForcePasswordResetRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ForcePasswordResetRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ForcePasswordResetRequestValidationError) ErrorName() string {
+	return "ForcePasswordResetRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ForcePasswordResetRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sForcePasswordResetRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ForcePasswordResetRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ForcePasswordResetRequestValidationError{}
+
+// Validate checks the field values on ForcePasswordResetResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ForcePasswordResetResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ForcePasswordResetResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ForcePasswordResetResponseMultiError, or nil if none found.
+func (m *ForcePasswordResetResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ForcePasswordResetResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return ForcePasswordResetResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// ForcePasswordResetResponseMultiError is an error wrapping multiple
+// validation errors returned by ForcePasswordResetResponse.ValidateAll() if
+// the designated constraints aren't met.
+type ForcePasswordResetResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ForcePasswordResetResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ForcePasswordResetResponseMultiError) AllErrors() []error { return m }
+
+// ForcePasswordResetResponseValidationError is the validation error returned
+// by ForcePasswordResetResponse.Validate if the designated constraints aren't met.
+type ForcePasswordResetResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ForcePasswordResetResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ForcePasswordResetResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ForcePasswordResetResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ForcePasswordResetResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ForcePasswordResetResponseValidationError) ErrorName() string {
+	return "ForcePasswordResetResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ForcePasswordResetResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sForcePasswordResetResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ForcePasswordResetResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ForcePasswordResetResponseValidationError{}
+
+// Validate checks the field values on RevokeUserSessionsRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RevokeUserSessionsRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RevokeUserSessionsRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RevokeUserSessionsRequestMultiError, or nil if none found.
+func (m *RevokeUserSessionsRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RevokeUserSessionsRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AccessToken
+
+	if utf8.RuneCountInString(m.GetUserId()) < 1 {
+		err := RevokeUserSessionsRequestValidationError{
+			field:  "UserId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return RevokeUserSessionsRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// RevokeUserSessionsRequestMultiError is an error wrapping multiple validation
+// errors returned by RevokeUserSessionsRequest.ValidateAll() if the
+// designated constraints aren't met.
+type RevokeUserSessionsRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RevokeUserSessionsRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RevokeUserSessionsRequestMultiError) AllErrors() []error { return m }
+
+// RevokeUserSessionsRequestValidationError is the validation error returned by
+// RevokeUserSessionsRequest.Validate if the designated constraints aren't met.
+type RevokeUserSessionsRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RevokeUserSessionsRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RevokeUserSessionsRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RevokeUserSessionsRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RevokeUserSessionsRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RevokeUserSessionsRequestValidationError) ErrorName() string {
+	return "RevokeUserSessionsRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RevokeUserSessionsRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRevokeUserSessionsRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RevokeUserSessionsRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RevokeUserSessionsRequestValidationError{}
+
+// Validate checks the field values on RevokeUserSessionsResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RevokeUserSessionsResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RevokeUserSessionsResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RevokeUserSessionsResponseMultiError, or nil if none found.
+func (m *RevokeUserSessionsResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RevokeUserSessionsResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return RevokeUserSessionsResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// RevokeUserSessionsResponseMultiError is an error wrapping multiple
+// validation errors returned by RevokeUserSessionsResponse.ValidateAll() if
+// the designated constraints aren't met.
+type RevokeUserSessionsResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RevokeUserSessionsResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RevokeUserSessionsResponseMultiError) AllErrors() []error { return m }
+
+// RevokeUserSessionsResponseValidationError is the validation error returned
+// by RevokeUserSessionsResponse.Validate if the designated constraints aren't met.
+type RevokeUserSessionsResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RevokeUserSessionsResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RevokeUserSessionsResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RevokeUserSessionsResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RevokeUserSessionsResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RevokeUserSessionsResponseValidationError) ErrorName() string {
+	return "RevokeUserSessionsResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RevokeUserSessionsResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRevokeUserSessionsResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RevokeUserSessionsResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RevokeUserSessionsResponseValidationError{}
+
+// Validate checks the field values on SetUserRoleRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *SetUserRoleRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SetUserRoleRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SetUserRoleRequestMultiError, or nil if none found.
+func (m *SetUserRoleRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SetUserRoleRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AccessToken
+
+	if utf8.RuneCountInString(m.GetUserId()) < 1 {
+		err := SetUserRoleRequestValidationError{
+			field:  "UserId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if _, ok := _SetUserRoleRequest_Role_InLookup[m.GetRole()]; !ok {
+		err := SetUserRoleRequestValidationError{
+			field:  "Role",
+			reason: "value must be in list [user admin]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return SetUserRoleRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// SetUserRoleRequestMultiError is an error wrapping multiple validation errors
+// returned by SetUserRoleRequest.ValidateAll() if the designated constraints
+// aren't met.
+type SetUserRoleRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SetUserRoleRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SetUserRoleRequestMultiError) AllErrors() []error { return m }
+
+// SetUserRoleRequestValidationError is the validation error returned by
+// SetUserRoleRequest.Validate if the designated constraints aren't met.
+type SetUserRoleRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SetUserRoleRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SetUserRoleRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SetUserRoleRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SetUserRoleRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SetUserRoleRequestValidationError) ErrorName() string {
+	return "SetUserRoleRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e SetUserRoleRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSetUserRoleRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SetUserRoleRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SetUserRoleRequestValidationError{}
+
+var _SetUserRoleRequest_Role_InLookup = map[string]struct{}{
+	"user":  {},
+	"admin": {},
+}
+
+// Validate checks the field values on SetUserRoleResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *SetUserRoleResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SetUserRoleResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SetUserRoleResponseMultiError, or nil if none found.
+func (m *SetUserRoleResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SetUserRoleResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return SetUserRoleResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// SetUserRoleResponseMultiError is an error wrapping multiple validation
+// errors returned by SetUserRoleResponse.ValidateAll() if the designated
+// constraints aren't met.
+type SetUserRoleResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SetUserRoleResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SetUserRoleResponseMultiError) AllErrors() []error { return m }
+
+// SetUserRoleResponseValidationError is the validation error returned by
+// SetUserRoleResponse.Validate if the designated constraints aren't met.
+type SetUserRoleResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SetUserRoleResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SetUserRoleResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SetUserRoleResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SetUserRoleResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SetUserRoleResponseValidationError) ErrorName() string {
+	return "SetUserRoleResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e SetUserRoleResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSetUserRoleResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SetUserRoleResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SetUserRoleResponseValidationError{}
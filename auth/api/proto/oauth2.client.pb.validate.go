@@ -0,0 +1,324 @@
+// Code generated by protoc-gen-validate. DO NOT EDIT.
+// source: oauth2.client.proto
+
+package proto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ensure the imports are used
+var (
+	_ = bytes.MinRead
+	_ = errors.New("")
+	_ = fmt.Print
+	_ = utf8.UTFMax
+	_ = (*regexp.Regexp)(nil)
+	_ = (*strings.Reader)(nil)
+	_ = net.IPv4len
+	_ = time.Duration(0)
+	_ = (*url.URL)(nil)
+	_ = (*mail.Address)(nil)
+	_ = anypb.Any{}
+	_ = sort.Sort
+)
+
+// Validate checks the field values on RegisterClientRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RegisterClientRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RegisterClientRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RegisterClientRequestMultiError, or nil if none found.
+func (m *RegisterClientRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RegisterClientRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AccessToken
+
+	if l := utf8.RuneCountInString(m.GetName()); l < 1 || l > 255 {
+		err := RegisterClientRequestValidationError{
+			field:  "Name",
+			reason: "value length must be between 1 and 255 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	for idx, item := range m.GetRedirectUris() {
+		_, _ = idx, item
+
+		if uri, err := url.Parse(item); err != nil {
+			err = RegisterClientRequestValidationError{
+				field:  fmt.Sprintf("RedirectUris[%v]", idx),
+				reason: "value must be a valid URI",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else if !uri.IsAbs() {
+			err := RegisterClientRequestValidationError{
+				field:  fmt.Sprintf("RedirectUris[%v]", idx),
+				reason: "value must be absolute",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	for idx, item := range m.GetScopes() {
+		_, _ = idx, item
+
+		if utf8.RuneCountInString(item) < 1 {
+			err := RegisterClientRequestValidationError{
+				field:  fmt.Sprintf("Scopes[%v]", idx),
+				reason: "value length must be at least 1 runes",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	for idx, item := range m.GetGrantTypes() {
+		_, _ = idx, item
+
+		if _, ok := _RegisterClientRequest_GrantTypes_InLookup[item]; !ok {
+			err := RegisterClientRequestValidationError{
+				field:  fmt.Sprintf("GrantTypes[%v]", idx),
+				reason: "value must be in list [authorization_code refresh_token password client_credentials]",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	// no validation rules for Public
+
+	if len(errors) > 0 {
+		return RegisterClientRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// RegisterClientRequestMultiError is an error wrapping multiple validation
+// errors returned by RegisterClientRequest.ValidateAll() if the designated
+// constraints aren't met.
+type RegisterClientRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RegisterClientRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RegisterClientRequestMultiError) AllErrors() []error { return m }
+
+// RegisterClientRequestValidationError is the validation error returned by
+// RegisterClientRequest.Validate if the designated constraints aren't met.
+type RegisterClientRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RegisterClientRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RegisterClientRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RegisterClientRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RegisterClientRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RegisterClientRequestValidationError) ErrorName() string {
+	return "RegisterClientRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RegisterClientRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRegisterClientRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RegisterClientRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RegisterClientRequestValidationError{}
+
+var _RegisterClientRequest_GrantTypes_InLookup = map[string]struct{}{
+	"authorization_code": {},
+	"refresh_token":      {},
+	"password":           {},
+	"client_credentials": {},
+}
+
+// Validate checks the field values on RegisterClientResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *RegisterClientResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RegisterClientResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// RegisterClientResponseMultiError, or nil if none found.
+func (m *RegisterClientResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RegisterClientResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for ClientId
+
+	// no validation rules for ClientSecret
+
+	if len(errors) > 0 {
+		return RegisterClientResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// RegisterClientResponseMultiError is an error wrapping multiple validation
+// errors returned by RegisterClientResponse.ValidateAll() if the designated
+// constraints aren't met.
+type RegisterClientResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RegisterClientResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RegisterClientResponseMultiError) AllErrors() []error { return m }
+
+// RegisterClientResponseValidationError is the validation error returned by
+// RegisterClientResponse.Validate if the designated constraints aren't met.
+type RegisterClientResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RegisterClientResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RegisterClientResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RegisterClientResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RegisterClientResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RegisterClientResponseValidationError) ErrorName() string {
+	return "RegisterClientResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RegisterClientResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRegisterClientResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RegisterClientResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RegisterClientResponseValidationError{}
@@ -28,48 +28,310 @@ var file_oauth2_proto_rawDesc = []byte{
 	0x69, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x14, 0x6f, 0x61, 0x75, 0x74, 0x68, 0x32,
 	0x2e, 0x73, 0x69, 0x67, 0x6e, 0x6f, 0x75, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x14,
 	0x6f, 0x61, 0x75, 0x74, 0x68, 0x32, 0x2e, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x32, 0xc1, 0x01, 0x0a, 0x0d, 0x4f, 0x41, 0x75, 0x74, 0x68, 0x32, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x55, 0x70,
-	0x12, 0x0e, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x55, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x0f, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x55, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x29, 0x0a, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x49, 0x6e, 0x12, 0x0e, 0x2e, 0x53, 0x69,
-	0x67, 0x6e, 0x49, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x53, 0x69,
-	0x67, 0x6e, 0x49, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x07,
-	0x53, 0x69, 0x67, 0x6e, 0x4f, 0x75, 0x74, 0x12, 0x0f, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x4f, 0x75,
-	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x4f,
-	0x75, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x52, 0x65,
-	0x66, 0x72, 0x65, 0x73, 0x68, 0x12, 0x0f, 0x2e, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x61, 0x7a, 0x61, 0x72, 0x73, 0x6c, 0x6f, 0x74,
-	0x61, 0x2f, 0x75, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x2f, 0x61, 0x75, 0x74, 0x68, 0x2f, 0x61, 0x70,
-	0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x11, 0x6f, 0x61, 0x75, 0x74, 0x68, 0x32, 0x2e, 0x74, 0x6f, 0x74,
+	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x15, 0x6f, 0x61, 0x75, 0x74, 0x68, 0x32, 0x2e,
+	0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x12,
+	0x6f, 0x61, 0x75, 0x74, 0x68, 0x32, 0x2e, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x13, 0x6f, 0x61, 0x75, 0x74, 0x68, 0x32, 0x2e, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x12, 0x6f, 0x61, 0x75, 0x74, 0x68, 0x32, 0x2e,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x14, 0x6f, 0x61, 0x75,
+	0x74, 0x68, 0x32, 0x2e, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x14, 0x6f, 0x61, 0x75, 0x74, 0x68, 0x32, 0x2e, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20, 0x6f, 0x61, 0x75, 0x74, 0x68, 0x32, 0x2e,
+	0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x61, 0x6c, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1a, 0x6f, 0x61, 0x75, 0x74, 0x68,
+	0x32, 0x2e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x12, 0x6f, 0x61, 0x75, 0x74, 0x68, 0x32, 0x2e, 0x61, 0x64,
+	0x6d, 0x69, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0xb0, 0x11, 0x0a, 0x0d, 0x4f, 0x41,
+	0x75, 0x74, 0x68, 0x32, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x53,
+	0x69, 0x67, 0x6e, 0x55, 0x70, 0x12, 0x0e, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x55, 0x70, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x55, 0x70, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x49, 0x6e,
+	0x12, 0x0e, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x49, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x0f, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x49, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x32, 0x0a, 0x09, 0x53, 0x69, 0x67, 0x6e, 0x49, 0x6e, 0x4d, 0x46, 0x41, 0x12, 0x11,
+	0x2e, 0x53, 0x69, 0x67, 0x6e, 0x49, 0x6e, 0x4d, 0x46, 0x41, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x12, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x49, 0x6e, 0x4d, 0x46, 0x41, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0c, 0x55, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x53,
+	0x69, 0x67, 0x6e, 0x49, 0x6e, 0x12, 0x14, 0x2e, 0x55, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x69,
+	0x67, 0x6e, 0x49, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x55, 0x6e,
+	0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x69, 0x67, 0x6e, 0x49, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x53, 0x69, 0x67, 0x6e, 0x4f, 0x75, 0x74, 0x12, 0x0f, 0x2e,
+	0x53, 0x69, 0x67, 0x6e, 0x4f, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10,
+	0x2e, 0x53, 0x69, 0x67, 0x6e, 0x4f, 0x75, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2c, 0x0a, 0x07, 0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x12, 0x0f, 0x2e, 0x52, 0x65,
+	0x66, 0x72, 0x65, 0x73, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x52,
+	0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35,
+	0x0a, 0x0a, 0x54, 0x4f, 0x54, 0x50, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x12, 0x12, 0x2e, 0x54,
+	0x4f, 0x54, 0x50, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x13, 0x2e, 0x54, 0x4f, 0x54, 0x50, 0x45, 0x6e, 0x72, 0x6f, 0x6c, 0x6c, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x0b, 0x54, 0x4f, 0x54, 0x50, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x72, 0x6d, 0x12, 0x13, 0x2e, 0x54, 0x4f, 0x54, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x72, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x54, 0x4f, 0x54, 0x50,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x38, 0x0a, 0x0b, 0x54, 0x4f, 0x54, 0x50, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x13,
+	0x2e, 0x54, 0x4f, 0x54, 0x50, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x54, 0x4f, 0x54, 0x50, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0e, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x16, 0x2e, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x53, 0x0a, 0x14,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52,
+	0x65, 0x73, 0x65, 0x74, 0x12, 0x1c, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x50, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x50, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x53, 0x0a, 0x14, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x50, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x65, 0x74, 0x12, 0x1c, 0x2e, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x72, 0x6d, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x65, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72,
+	0x6d, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x45, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x13, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x45, 0x6d,
+	0x61, 0x69, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x45, 0x6d, 0x61, 0x69, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x38, 0x0a, 0x0b, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x45, 0x6d, 0x61, 0x69, 0x6c, 0x12,
+	0x13, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x45, 0x6d, 0x61, 0x69, 0x6c, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x45, 0x6d, 0x61,
+	0x69, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x12, 0x52, 0x65,
+	0x73, 0x65, 0x6e, 0x64, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x1a, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x6e, 0x64, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x52,
+	0x65, 0x73, 0x65, 0x6e, 0x64, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0e, 0x52, 0x65, 0x67,
+	0x69, 0x73, 0x74, 0x65, 0x72, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x2e, 0x52, 0x65,
+	0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x43, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x0a,
+	0x49, 0x6e, 0x74, 0x72, 0x6f, 0x73, 0x70, 0x65, 0x63, 0x74, 0x12, 0x12, 0x2e, 0x49, 0x6e, 0x74,
+	0x72, 0x6f, 0x73, 0x70, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13,
+	0x2e, 0x49, 0x6e, 0x74, 0x72, 0x6f, 0x73, 0x70, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x12, 0x0e, 0x2e,
+	0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e,
+	0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35,
+	0x0a, 0x0a, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x2e, 0x47,
+	0x65, 0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x13, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x15, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0c, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x41,
+	0x76, 0x61, 0x74, 0x61, 0x72, 0x12, 0x14, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x41, 0x76,
+	0x61, 0x74, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x41, 0x76, 0x61, 0x74, 0x61, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x2f, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x12, 0x10,
+	0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x11, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x15, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x62, 0x0a, 0x19, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x65, 0x72,
+	0x73, 0x6f, 0x6e, 0x61, 0x6c, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
+	0x12, 0x21, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x61,
+	0x6c, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x65, 0x72, 0x73,
+	0x6f, 0x6e, 0x61, 0x6c, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5f, 0x0a, 0x18, 0x4c, 0x69, 0x73, 0x74, 0x50,
+	0x65, 0x72, 0x73, 0x6f, 0x6e, 0x61, 0x6c, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x73, 0x12, 0x20, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e,
+	0x61, 0x6c, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x72, 0x73,
+	0x6f, 0x6e, 0x61, 0x6c, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x62, 0x0a, 0x19, 0x52, 0x65, 0x76, 0x6f,
+	0x6b, 0x65, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x61, 0x6c, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x21, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x50, 0x65,
+	0x72, 0x73, 0x6f, 0x6e, 0x61, 0x6c, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b,
+	0x65, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x61, 0x6c, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x12,
+	0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x73, 0x12, 0x1a, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74,
+	0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0f, 0x4c,
+	0x69, 0x73, 0x74, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x17,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75,
+	0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x32, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x12, 0x11,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x12, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x65,
+	0x72, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x18, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44,
+	0x0a, 0x0f, 0x53, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65,
+	0x64, 0x12, 0x17, 0x2e, 0x53, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x44, 0x69, 0x73, 0x61, 0x62,
+	0x6c, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x53, 0x65, 0x74,
+	0x55, 0x73, 0x65, 0x72, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x12, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x50, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x65, 0x74, 0x12, 0x1a, 0x2e, 0x46, 0x6f, 0x72,
+	0x63, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x50, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x12, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x55, 0x73, 0x65,
+	0x72, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1a, 0x2e, 0x52, 0x65, 0x76, 0x6f,
+	0x6b, 0x65, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x55, 0x73,
+	0x65, 0x72, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x38, 0x0a, 0x0b, 0x53, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x6f, 0x6c,
+	0x65, 0x12, 0x13, 0x2e, 0x53, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x53, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x6f, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2d, 0x5a, 0x2b,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x61, 0x7a, 0x61, 0x72,
+	0x73, 0x6c, 0x6f, 0x74, 0x61, 0x2f, 0x75, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x2f, 0x61, 0x75, 0x74,
+	0x68, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var file_oauth2_proto_goTypes = []interface{}{
-	(*SignUpRequest)(nil),   // 0: SignUpRequest
-	(*SignInRequest)(nil),   // 1: SignInRequest
-	(*SignOutRequest)(nil),  // 2: SignOutRequest
-	(*RefreshRequest)(nil),  // 3: RefreshRequest
-	(*SignUpResponse)(nil),  // 4: SignUpResponse
-	(*SignInResponse)(nil),  // 5: SignInResponse
-	(*SignOutResponse)(nil), // 6: SignOutResponse
-	(*RefreshResponse)(nil), // 7: RefreshResponse
+	(*SignUpRequest)(nil),                     // 0: SignUpRequest
+	(*SignInRequest)(nil),                     // 1: SignInRequest
+	(*SignInMFARequest)(nil),                  // 2: SignInMFARequest
+	(*UnlockSignInRequest)(nil),               // 3: UnlockSignInRequest
+	(*SignOutRequest)(nil),                    // 4: SignOutRequest
+	(*RefreshRequest)(nil),                    // 5: RefreshRequest
+	(*TOTPEnrollRequest)(nil),                 // 6: TOTPEnrollRequest
+	(*TOTPConfirmRequest)(nil),                // 7: TOTPConfirmRequest
+	(*TOTPDisableRequest)(nil),                // 8: TOTPDisableRequest
+	(*ChangePasswordRequest)(nil),             // 9: ChangePasswordRequest
+	(*RequestPasswordResetRequest)(nil),       // 10: RequestPasswordResetRequest
+	(*ConfirmPasswordResetRequest)(nil),       // 11: ConfirmPasswordResetRequest
+	(*ChangeEmailRequest)(nil),                // 12: ChangeEmailRequest
+	(*VerifyEmailRequest)(nil),                // 13: VerifyEmailRequest
+	(*ResendVerificationRequest)(nil),         // 14: ResendVerificationRequest
+	(*RegisterClientRequest)(nil),             // 15: RegisterClientRequest
+	(*IntrospectRequest)(nil),                 // 16: IntrospectRequest
+	(*RevokeRequest)(nil),                     // 17: RevokeRequest
+	(*GetProfileRequest)(nil),                 // 18: GetProfileRequest
+	(*UpdateProfileRequest)(nil),              // 19: UpdateProfileRequest
+	(*UploadAvatarRequest)(nil),               // 20: UploadAvatarRequest
+	(*GetUsersRequest)(nil),                   // 21: GetUsersRequest
+	(*DeleteAccountRequest)(nil),              // 22: DeleteAccountRequest
+	(*CreatePersonalAccessTokenRequest)(nil),  // 23: CreatePersonalAccessTokenRequest
+	(*ListPersonalAccessTokensRequest)(nil),   // 24: ListPersonalAccessTokensRequest
+	(*RevokePersonalAccessTokenRequest)(nil),  // 25: RevokePersonalAccessTokenRequest
+	(*ListSecurityEventsRequest)(nil),         // 26: ListSecurityEventsRequest
+	(*ListAuditEventsRequest)(nil),            // 27: ListAuditEventsRequest
+	(*ListUsersRequest)(nil),                  // 28: ListUsersRequest
+	(*ListUserSessionsRequest)(nil),           // 29: ListUserSessionsRequest
+	(*SetUserDisabledRequest)(nil),            // 30: SetUserDisabledRequest
+	(*ForcePasswordResetRequest)(nil),         // 31: ForcePasswordResetRequest
+	(*RevokeUserSessionsRequest)(nil),         // 32: RevokeUserSessionsRequest
+	(*SetUserRoleRequest)(nil),                // 33: SetUserRoleRequest
+	(*SignUpResponse)(nil),                    // 34: SignUpResponse
+	(*SignInResponse)(nil),                    // 35: SignInResponse
+	(*SignInMFAResponse)(nil),                 // 36: SignInMFAResponse
+	(*UnlockSignInResponse)(nil),              // 37: UnlockSignInResponse
+	(*SignOutResponse)(nil),                   // 38: SignOutResponse
+	(*RefreshResponse)(nil),                   // 39: RefreshResponse
+	(*TOTPEnrollResponse)(nil),                // 40: TOTPEnrollResponse
+	(*TOTPConfirmResponse)(nil),               // 41: TOTPConfirmResponse
+	(*TOTPDisableResponse)(nil),               // 42: TOTPDisableResponse
+	(*ChangePasswordResponse)(nil),            // 43: ChangePasswordResponse
+	(*RequestPasswordResetResponse)(nil),      // 44: RequestPasswordResetResponse
+	(*ConfirmPasswordResetResponse)(nil),      // 45: ConfirmPasswordResetResponse
+	(*ChangeEmailResponse)(nil),               // 46: ChangeEmailResponse
+	(*VerifyEmailResponse)(nil),               // 47: VerifyEmailResponse
+	(*ResendVerificationResponse)(nil),        // 48: ResendVerificationResponse
+	(*RegisterClientResponse)(nil),            // 49: RegisterClientResponse
+	(*IntrospectResponse)(nil),                // 50: IntrospectResponse
+	(*RevokeResponse)(nil),                    // 51: RevokeResponse
+	(*GetProfileResponse)(nil),                // 52: GetProfileResponse
+	(*UpdateProfileResponse)(nil),             // 53: UpdateProfileResponse
+	(*UploadAvatarResponse)(nil),              // 54: UploadAvatarResponse
+	(*GetUsersResponse)(nil),                  // 55: GetUsersResponse
+	(*DeleteAccountResponse)(nil),             // 56: DeleteAccountResponse
+	(*CreatePersonalAccessTokenResponse)(nil), // 57: CreatePersonalAccessTokenResponse
+	(*ListPersonalAccessTokensResponse)(nil),  // 58: ListPersonalAccessTokensResponse
+	(*RevokePersonalAccessTokenResponse)(nil), // 59: RevokePersonalAccessTokenResponse
+	(*ListSecurityEventsResponse)(nil),        // 60: ListSecurityEventsResponse
+	(*ListAuditEventsResponse)(nil),           // 61: ListAuditEventsResponse
+	(*ListUsersResponse)(nil),                 // 62: ListUsersResponse
+	(*ListUserSessionsResponse)(nil),          // 63: ListUserSessionsResponse
+	(*SetUserDisabledResponse)(nil),           // 64: SetUserDisabledResponse
+	(*ForcePasswordResetResponse)(nil),        // 65: ForcePasswordResetResponse
+	(*RevokeUserSessionsResponse)(nil),        // 66: RevokeUserSessionsResponse
+	(*SetUserRoleResponse)(nil),               // 67: SetUserRoleResponse
 }
 var file_oauth2_proto_depIdxs = []int32{
-	0, // 0: OAuth2Service.SignUp:input_type -> SignUpRequest
-	1, // 1: OAuth2Service.SignIn:input_type -> SignInRequest
-	2, // 2: OAuth2Service.SignOut:input_type -> SignOutRequest
-	3, // 3: OAuth2Service.Refresh:input_type -> RefreshRequest
-	4, // 4: OAuth2Service.SignUp:output_type -> SignUpResponse
-	5, // 5: OAuth2Service.SignIn:output_type -> SignInResponse
-	6, // 6: OAuth2Service.SignOut:output_type -> SignOutResponse
-	7, // 7: OAuth2Service.Refresh:output_type -> RefreshResponse
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: OAuth2Service.SignUp:input_type -> SignUpRequest
+	1,  // 1: OAuth2Service.SignIn:input_type -> SignInRequest
+	2,  // 2: OAuth2Service.SignInMFA:input_type -> SignInMFARequest
+	3,  // 3: OAuth2Service.UnlockSignIn:input_type -> UnlockSignInRequest
+	4,  // 4: OAuth2Service.SignOut:input_type -> SignOutRequest
+	5,  // 5: OAuth2Service.Refresh:input_type -> RefreshRequest
+	6,  // 6: OAuth2Service.TOTPEnroll:input_type -> TOTPEnrollRequest
+	7,  // 7: OAuth2Service.TOTPConfirm:input_type -> TOTPConfirmRequest
+	8,  // 8: OAuth2Service.TOTPDisable:input_type -> TOTPDisableRequest
+	9,  // 9: OAuth2Service.ChangePassword:input_type -> ChangePasswordRequest
+	10, // 10: OAuth2Service.RequestPasswordReset:input_type -> RequestPasswordResetRequest
+	11, // 11: OAuth2Service.ConfirmPasswordReset:input_type -> ConfirmPasswordResetRequest
+	12, // 12: OAuth2Service.ChangeEmail:input_type -> ChangeEmailRequest
+	13, // 13: OAuth2Service.VerifyEmail:input_type -> VerifyEmailRequest
+	14, // 14: OAuth2Service.ResendVerification:input_type -> ResendVerificationRequest
+	15, // 15: OAuth2Service.RegisterClient:input_type -> RegisterClientRequest
+	16, // 16: OAuth2Service.Introspect:input_type -> IntrospectRequest
+	17, // 17: OAuth2Service.Revoke:input_type -> RevokeRequest
+	18, // 18: OAuth2Service.GetProfile:input_type -> GetProfileRequest
+	19, // 19: OAuth2Service.UpdateProfile:input_type -> UpdateProfileRequest
+	20, // 20: OAuth2Service.UploadAvatar:input_type -> UploadAvatarRequest
+	21, // 21: OAuth2Service.GetUsers:input_type -> GetUsersRequest
+	22, // 22: OAuth2Service.DeleteAccount:input_type -> DeleteAccountRequest
+	23, // 23: OAuth2Service.CreatePersonalAccessToken:input_type -> CreatePersonalAccessTokenRequest
+	24, // 24: OAuth2Service.ListPersonalAccessTokens:input_type -> ListPersonalAccessTokensRequest
+	25, // 25: OAuth2Service.RevokePersonalAccessToken:input_type -> RevokePersonalAccessTokenRequest
+	26, // 26: OAuth2Service.ListSecurityEvents:input_type -> ListSecurityEventsRequest
+	27, // 27: OAuth2Service.ListAuditEvents:input_type -> ListAuditEventsRequest
+	28, // 28: OAuth2Service.ListUsers:input_type -> ListUsersRequest
+	29, // 29: OAuth2Service.ListUserSessions:input_type -> ListUserSessionsRequest
+	30, // 30: OAuth2Service.SetUserDisabled:input_type -> SetUserDisabledRequest
+	31, // 31: OAuth2Service.ForcePasswordReset:input_type -> ForcePasswordResetRequest
+	32, // 32: OAuth2Service.RevokeUserSessions:input_type -> RevokeUserSessionsRequest
+	33, // 33: OAuth2Service.SetUserRole:input_type -> SetUserRoleRequest
+	34, // 34: OAuth2Service.SignUp:output_type -> SignUpResponse
+	35, // 35: OAuth2Service.SignIn:output_type -> SignInResponse
+	36, // 36: OAuth2Service.SignInMFA:output_type -> SignInMFAResponse
+	37, // 37: OAuth2Service.UnlockSignIn:output_type -> UnlockSignInResponse
+	38, // 38: OAuth2Service.SignOut:output_type -> SignOutResponse
+	39, // 39: OAuth2Service.Refresh:output_type -> RefreshResponse
+	40, // 40: OAuth2Service.TOTPEnroll:output_type -> TOTPEnrollResponse
+	41, // 41: OAuth2Service.TOTPConfirm:output_type -> TOTPConfirmResponse
+	42, // 42: OAuth2Service.TOTPDisable:output_type -> TOTPDisableResponse
+	43, // 43: OAuth2Service.ChangePassword:output_type -> ChangePasswordResponse
+	44, // 44: OAuth2Service.RequestPasswordReset:output_type -> RequestPasswordResetResponse
+	45, // 45: OAuth2Service.ConfirmPasswordReset:output_type -> ConfirmPasswordResetResponse
+	46, // 46: OAuth2Service.ChangeEmail:output_type -> ChangeEmailResponse
+	47, // 47: OAuth2Service.VerifyEmail:output_type -> VerifyEmailResponse
+	48, // 48: OAuth2Service.ResendVerification:output_type -> ResendVerificationResponse
+	49, // 49: OAuth2Service.RegisterClient:output_type -> RegisterClientResponse
+	50, // 50: OAuth2Service.Introspect:output_type -> IntrospectResponse
+	51, // 51: OAuth2Service.Revoke:output_type -> RevokeResponse
+	52, // 52: OAuth2Service.GetProfile:output_type -> GetProfileResponse
+	53, // 53: OAuth2Service.UpdateProfile:output_type -> UpdateProfileResponse
+	54, // 54: OAuth2Service.UploadAvatar:output_type -> UploadAvatarResponse
+	55, // 55: OAuth2Service.GetUsers:output_type -> GetUsersResponse
+	56, // 56: OAuth2Service.DeleteAccount:output_type -> DeleteAccountResponse
+	57, // 57: OAuth2Service.CreatePersonalAccessToken:output_type -> CreatePersonalAccessTokenResponse
+	58, // 58: OAuth2Service.ListPersonalAccessTokens:output_type -> ListPersonalAccessTokensResponse
+	59, // 59: OAuth2Service.RevokePersonalAccessToken:output_type -> RevokePersonalAccessTokenResponse
+	60, // 60: OAuth2Service.ListSecurityEvents:output_type -> ListSecurityEventsResponse
+	61, // 61: OAuth2Service.ListAuditEvents:output_type -> ListAuditEventsResponse
+	62, // 62: OAuth2Service.ListUsers:output_type -> ListUsersResponse
+	63, // 63: OAuth2Service.ListUserSessions:output_type -> ListUserSessionsResponse
+	64, // 64: OAuth2Service.SetUserDisabled:output_type -> SetUserDisabledResponse
+	65, // 65: OAuth2Service.ForcePasswordReset:output_type -> ForcePasswordResetResponse
+	66, // 66: OAuth2Service.RevokeUserSessions:output_type -> RevokeUserSessionsResponse
+	67, // 67: OAuth2Service.SetUserRole:output_type -> SetUserRoleResponse
+	34, // [34:68] is the sub-list for method output_type
+	0,  // [0:34] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_oauth2_proto_init() }
@@ -81,6 +343,16 @@ func file_oauth2_proto_init() {
 	file_oauth2_signin_proto_init()
 	file_oauth2_signout_proto_init()
 	file_oauth2_refresh_proto_init()
+	file_oauth2_totp_proto_init()
+	file_oauth2_password_proto_init()
+	file_oauth2_email_proto_init()
+	file_oauth2_client_proto_init()
+	file_oauth2_token_proto_init()
+	file_oauth2_profile_proto_init()
+	file_oauth2_account_proto_init()
+	file_oauth2_personalaccesstoken_proto_init()
+	file_oauth2_securityevent_proto_init()
+	file_oauth2_admin_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
@@ -0,0 +1,891 @@
+// Code generated by protoc-gen-validate. DO NOT EDIT.
+// source: oauth2.personalaccesstoken.proto
+
+package proto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ensure the imports are used
+var (
+	_ = bytes.MinRead
+	_ = errors.New("")
+	_ = fmt.Print
+	_ = utf8.UTFMax
+	_ = (*regexp.Regexp)(nil)
+	_ = (*strings.Reader)(nil)
+	_ = net.IPv4len
+	_ = time.Duration(0)
+	_ = (*url.URL)(nil)
+	_ = (*mail.Address)(nil)
+	_ = anypb.Any{}
+	_ = sort.Sort
+)
+
+// Validate checks the field values on PersonalAccessToken with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *PersonalAccessToken) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on PersonalAccessToken with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// PersonalAccessTokenMultiError, or nil if none found.
+func (m *PersonalAccessToken) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *PersonalAccessToken) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for Name
+
+	// no validation rules for CreatedAt
+
+	// no validation rules for ExpiresAt
+
+	// no validation rules for LastUsedAt
+
+	if len(errors) > 0 {
+		return PersonalAccessTokenMultiError(errors)
+	}
+
+	return nil
+}
+
+// PersonalAccessTokenMultiError is an error wrapping multiple validation
+// errors returned by PersonalAccessToken.ValidateAll() if the designated
+// constraints aren't met.
+type PersonalAccessTokenMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m PersonalAccessTokenMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m PersonalAccessTokenMultiError) AllErrors() []error { return m }
+
+// PersonalAccessTokenValidationError is the validation error returned by
+// PersonalAccessToken.Validate if the designated constraints aren't met.
+type PersonalAccessTokenValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PersonalAccessTokenValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PersonalAccessTokenValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PersonalAccessTokenValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PersonalAccessTokenValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PersonalAccessTokenValidationError) ErrorName() string {
+	return "PersonalAccessTokenValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e PersonalAccessTokenValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPersonalAccessToken.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PersonalAccessTokenValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PersonalAccessTokenValidationError{}
+
+// Validate checks the field values on CreatePersonalAccessTokenRequest with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the first error encountered is returned, or nil if there are
+// no violations.
+func (m *CreatePersonalAccessTokenRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CreatePersonalAccessTokenRequest with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the result is a list of violation errors wrapped in
+// CreatePersonalAccessTokenRequestMultiError, or nil if none found.
+func (m *CreatePersonalAccessTokenRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CreatePersonalAccessTokenRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AccessToken
+
+	if l := utf8.RuneCountInString(m.GetName()); l < 1 || l > 64 {
+		err := CreatePersonalAccessTokenRequestValidationError{
+			field:  "Name",
+			reason: "value length must be between 1 and 64 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(m.GetScopes()) < 1 {
+		err := CreatePersonalAccessTokenRequestValidationError{
+			field:  "Scopes",
+			reason: "value must contain at least 1 item(s)",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetExpiresIn() < 0 {
+		err := CreatePersonalAccessTokenRequestValidationError{
+			field:  "ExpiresIn",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return CreatePersonalAccessTokenRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// CreatePersonalAccessTokenRequestMultiError is an error wrapping multiple
+// validation errors returned by
+// CreatePersonalAccessTokenRequest.ValidateAll() if the designated
+// constraints aren't met.
+type CreatePersonalAccessTokenRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CreatePersonalAccessTokenRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CreatePersonalAccessTokenRequestMultiError) AllErrors() []error { return m }
+
+// CreatePersonalAccessTokenRequestValidationError is the validation error
+// returned by CreatePersonalAccessTokenRequest.Validate if the designated
+// constraints aren't met.
+type CreatePersonalAccessTokenRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CreatePersonalAccessTokenRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CreatePersonalAccessTokenRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CreatePersonalAccessTokenRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CreatePersonalAccessTokenRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CreatePersonalAccessTokenRequestValidationError) ErrorName() string {
+	return "CreatePersonalAccessTokenRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CreatePersonalAccessTokenRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCreatePersonalAccessTokenRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CreatePersonalAccessTokenRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CreatePersonalAccessTokenRequestValidationError{}
+
+// Validate checks the field values on CreatePersonalAccessTokenResponse with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the first error encountered is returned, or nil if there are
+// no violations.
+func (m *CreatePersonalAccessTokenResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CreatePersonalAccessTokenResponse
+// with the rules defined in the proto definition for this message. If any
+// rules are violated, the result is a list of violation errors wrapped in
+// CreatePersonalAccessTokenResponseMultiError, or nil if none found.
+func (m *CreatePersonalAccessTokenResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CreatePersonalAccessTokenResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetPersonalAccessToken()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, CreatePersonalAccessTokenResponseValidationError{
+					field:  "PersonalAccessToken",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, CreatePersonalAccessTokenResponseValidationError{
+					field:  "PersonalAccessToken",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetPersonalAccessToken()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return CreatePersonalAccessTokenResponseValidationError{
+				field:  "PersonalAccessToken",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Token
+
+	if len(errors) > 0 {
+		return CreatePersonalAccessTokenResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// CreatePersonalAccessTokenResponseMultiError is an error wrapping multiple
+// validation errors returned by
+// CreatePersonalAccessTokenResponse.ValidateAll() if the designated
+// constraints aren't met.
+type CreatePersonalAccessTokenResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CreatePersonalAccessTokenResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CreatePersonalAccessTokenResponseMultiError) AllErrors() []error { return m }
+
+// CreatePersonalAccessTokenResponseValidationError is the validation error
+// returned by CreatePersonalAccessTokenResponse.Validate if the designated
+// constraints aren't met.
+type CreatePersonalAccessTokenResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CreatePersonalAccessTokenResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CreatePersonalAccessTokenResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CreatePersonalAccessTokenResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CreatePersonalAccessTokenResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CreatePersonalAccessTokenResponseValidationError) ErrorName() string {
+	return "CreatePersonalAccessTokenResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CreatePersonalAccessTokenResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCreatePersonalAccessTokenResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CreatePersonalAccessTokenResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CreatePersonalAccessTokenResponseValidationError{}
+
+// Validate checks the field values on ListPersonalAccessTokensRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ListPersonalAccessTokensRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListPersonalAccessTokensRequest with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the result is a list of violation errors wrapped in
+// ListPersonalAccessTokensRequestMultiError, or nil if none found.
+func (m *ListPersonalAccessTokensRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListPersonalAccessTokensRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AccessToken
+
+	if len(errors) > 0 {
+		return ListPersonalAccessTokensRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// ListPersonalAccessTokensRequestMultiError is an error wrapping multiple
+// validation errors returned by ListPersonalAccessTokensRequest.ValidateAll()
+// if the designated constraints aren't met.
+type ListPersonalAccessTokensRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListPersonalAccessTokensRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListPersonalAccessTokensRequestMultiError) AllErrors() []error { return m }
+
+// ListPersonalAccessTokensRequestValidationError is the validation error
+// returned by ListPersonalAccessTokensRequest.Validate if the designated
+// constraints aren't met.
+type ListPersonalAccessTokensRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListPersonalAccessTokensRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListPersonalAccessTokensRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListPersonalAccessTokensRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListPersonalAccessTokensRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListPersonalAccessTokensRequestValidationError) ErrorName() string {
+	return "ListPersonalAccessTokensRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListPersonalAccessTokensRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListPersonalAccessTokensRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListPersonalAccessTokensRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListPersonalAccessTokensRequestValidationError{}
+
+// Validate checks the field values on ListPersonalAccessTokensResponse with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the first error encountered is returned, or nil if there are
+// no violations.
+func (m *ListPersonalAccessTokensResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ListPersonalAccessTokensResponse with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the result is a list of violation errors wrapped in
+// ListPersonalAccessTokensResponseMultiError, or nil if none found.
+func (m *ListPersonalAccessTokensResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ListPersonalAccessTokensResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetPersonalAccessTokens() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ListPersonalAccessTokensResponseValidationError{
+						field:  fmt.Sprintf("PersonalAccessTokens[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ListPersonalAccessTokensResponseValidationError{
+						field:  fmt.Sprintf("PersonalAccessTokens[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ListPersonalAccessTokensResponseValidationError{
+					field:  fmt.Sprintf("PersonalAccessTokens[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return ListPersonalAccessTokensResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// ListPersonalAccessTokensResponseMultiError is an error wrapping multiple
+// validation errors returned by
+// ListPersonalAccessTokensResponse.ValidateAll() if the designated
+// constraints aren't met.
+type ListPersonalAccessTokensResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ListPersonalAccessTokensResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ListPersonalAccessTokensResponseMultiError) AllErrors() []error { return m }
+
+// ListPersonalAccessTokensResponseValidationError is the validation error
+// returned by ListPersonalAccessTokensResponse.Validate if the designated
+// constraints aren't met.
+type ListPersonalAccessTokensResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ListPersonalAccessTokensResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ListPersonalAccessTokensResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ListPersonalAccessTokensResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ListPersonalAccessTokensResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ListPersonalAccessTokensResponseValidationError) ErrorName() string {
+	return "ListPersonalAccessTokensResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ListPersonalAccessTokensResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sListPersonalAccessTokensResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ListPersonalAccessTokensResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ListPersonalAccessTokensResponseValidationError{}
+
+// Validate checks the field values on RevokePersonalAccessTokenRequest with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the first error encountered is returned, or nil if there are
+// no violations.
+func (m *RevokePersonalAccessTokenRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RevokePersonalAccessTokenRequest with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the result is a list of violation errors wrapped in
+// RevokePersonalAccessTokenRequestMultiError, or nil if none found.
+func (m *RevokePersonalAccessTokenRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RevokePersonalAccessTokenRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AccessToken
+
+	if utf8.RuneCountInString(m.GetId()) < 1 {
+		err := RevokePersonalAccessTokenRequestValidationError{
+			field:  "Id",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return RevokePersonalAccessTokenRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// RevokePersonalAccessTokenRequestMultiError is an error wrapping multiple
+// validation errors returned by
+// RevokePersonalAccessTokenRequest.ValidateAll() if the designated
+// constraints aren't met.
+type RevokePersonalAccessTokenRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RevokePersonalAccessTokenRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RevokePersonalAccessTokenRequestMultiError) AllErrors() []error { return m }
+
+// RevokePersonalAccessTokenRequestValidationError is the validation error
+// returned by RevokePersonalAccessTokenRequest.Validate if the designated
+// constraints aren't met.
+type RevokePersonalAccessTokenRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RevokePersonalAccessTokenRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RevokePersonalAccessTokenRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RevokePersonalAccessTokenRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RevokePersonalAccessTokenRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RevokePersonalAccessTokenRequestValidationError) ErrorName() string {
+	return "RevokePersonalAccessTokenRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RevokePersonalAccessTokenRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRevokePersonalAccessTokenRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RevokePersonalAccessTokenRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RevokePersonalAccessTokenRequestValidationError{}
+
+// Validate checks the field values on RevokePersonalAccessTokenResponse with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the first error encountered is returned, or nil if there are
+// no violations.
+func (m *RevokePersonalAccessTokenResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RevokePersonalAccessTokenResponse
+// with the rules defined in the proto definition for this message. If any
+// rules are violated, the result is a list of violation errors wrapped in
+// RevokePersonalAccessTokenResponseMultiError, or nil if none found.
+func (m *RevokePersonalAccessTokenResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RevokePersonalAccessTokenResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return RevokePersonalAccessTokenResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// RevokePersonalAccessTokenResponseMultiError is an error wrapping multiple
+// validation errors returned by
+// RevokePersonalAccessTokenResponse.ValidateAll() if the designated
+// constraints aren't met.
+type RevokePersonalAccessTokenResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RevokePersonalAccessTokenResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RevokePersonalAccessTokenResponseMultiError) AllErrors() []error { return m }
+
+// RevokePersonalAccessTokenResponseValidationError is the validation error
+// returned by RevokePersonalAccessTokenResponse.Validate if the designated
+// constraints aren't met.
+type RevokePersonalAccessTokenResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RevokePersonalAccessTokenResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RevokePersonalAccessTokenResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RevokePersonalAccessTokenResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RevokePersonalAccessTokenResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RevokePersonalAccessTokenResponseValidationError) ErrorName() string {
+	return "RevokePersonalAccessTokenResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e RevokePersonalAccessTokenResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRevokePersonalAccessTokenResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RevokePersonalAccessTokenResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RevokePersonalAccessTokenResponseValidationError{}
@@ -183,6 +183,10 @@ func (m *SignInResponse) validate(all bool) error {
 
 	// no validation rules for RefreshToken
 
+	// no validation rules for MfaRequired
+
+	// no validation rules for MfaToken
+
 	if len(errors) > 0 {
 		return SignInResponseMultiError(errors)
 	}
@@ -260,3 +264,463 @@ var _ interface {
 	Cause() error
 	ErrorName() string
 } = SignInResponseValidationError{}
+
+// Validate checks the field values on SignInMFARequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *SignInMFARequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SignInMFARequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SignInMFARequestMultiError, or nil if none found.
+func (m *SignInMFARequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SignInMFARequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetMfaToken()) < 1 {
+		err := SignInMFARequestValidationError{
+			field:  "MfaToken",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if l := utf8.RuneCountInString(m.GetCode()); l < 6 || l > 16 {
+		err := SignInMFARequestValidationError{
+			field:  "Code",
+			reason: "value length must be between 6 and 16 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return SignInMFARequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// SignInMFARequestMultiError is an error wrapping multiple validation errors
+// returned by SignInMFARequest.ValidateAll() if the designated constraints
+// aren't met.
+type SignInMFARequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SignInMFARequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SignInMFARequestMultiError) AllErrors() []error { return m }
+
+// SignInMFARequestValidationError is the validation error returned by
+// SignInMFARequest.Validate if the designated constraints aren't met.
+type SignInMFARequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SignInMFARequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SignInMFARequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SignInMFARequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SignInMFARequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SignInMFARequestValidationError) ErrorName() string { return "SignInMFARequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e SignInMFARequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSignInMFARequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SignInMFARequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SignInMFARequestValidationError{}
+
+// Validate checks the field values on SignInMFAResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *SignInMFAResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SignInMFAResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SignInMFAResponseMultiError, or nil if none found.
+func (m *SignInMFAResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SignInMFAResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AccessToken
+
+	// no validation rules for RefreshToken
+
+	if len(errors) > 0 {
+		return SignInMFAResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// SignInMFAResponseMultiError is an error wrapping multiple validation errors
+// returned by SignInMFAResponse.ValidateAll() if the designated constraints
+// aren't met.
+type SignInMFAResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SignInMFAResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SignInMFAResponseMultiError) AllErrors() []error { return m }
+
+// SignInMFAResponseValidationError is the validation error returned by
+// SignInMFAResponse.Validate if the designated constraints aren't met.
+type SignInMFAResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SignInMFAResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SignInMFAResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SignInMFAResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SignInMFAResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SignInMFAResponseValidationError) ErrorName() string {
+	return "SignInMFAResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e SignInMFAResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSignInMFAResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SignInMFAResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SignInMFAResponseValidationError{}
+
+// Validate checks the field values on UnlockSignInRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UnlockSignInRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UnlockSignInRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UnlockSignInRequestMultiError, or nil if none found.
+func (m *UnlockSignInRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UnlockSignInRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AccessToken
+
+	if utf8.RuneCountInString(m.GetUsername()) > 32 {
+		err := UnlockSignInRequestValidationError{
+			field:  "Username",
+			reason: "value length must be at most 32 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetIp() != "" {
+
+		if ip := net.ParseIP(m.GetIp()); ip == nil {
+			err := UnlockSignInRequestValidationError{
+				field:  "Ip",
+				reason: "value must be a valid IP address",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return UnlockSignInRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// UnlockSignInRequestMultiError is an error wrapping multiple validation
+// errors returned by UnlockSignInRequest.ValidateAll() if the designated
+// constraints aren't met.
+type UnlockSignInRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UnlockSignInRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UnlockSignInRequestMultiError) AllErrors() []error { return m }
+
+// UnlockSignInRequestValidationError is the validation error returned by
+// UnlockSignInRequest.Validate if the designated constraints aren't met.
+type UnlockSignInRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UnlockSignInRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UnlockSignInRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UnlockSignInRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UnlockSignInRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UnlockSignInRequestValidationError) ErrorName() string {
+	return "UnlockSignInRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UnlockSignInRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUnlockSignInRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UnlockSignInRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UnlockSignInRequestValidationError{}
+
+// Validate checks the field values on UnlockSignInResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *UnlockSignInResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UnlockSignInResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// UnlockSignInResponseMultiError, or nil if none found.
+func (m *UnlockSignInResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UnlockSignInResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return UnlockSignInResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// UnlockSignInResponseMultiError is an error wrapping multiple validation
+// errors returned by UnlockSignInResponse.ValidateAll() if the designated
+// constraints aren't met.
+type UnlockSignInResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UnlockSignInResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UnlockSignInResponseMultiError) AllErrors() []error { return m }
+
+// UnlockSignInResponseValidationError is the validation error returned by
+// UnlockSignInResponse.Validate if the designated constraints aren't met.
+type UnlockSignInResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UnlockSignInResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UnlockSignInResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UnlockSignInResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UnlockSignInResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UnlockSignInResponseValidationError) ErrorName() string {
+	return "UnlockSignInResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e UnlockSignInResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUnlockSignInResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UnlockSignInResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UnlockSignInResponseValidationError{}
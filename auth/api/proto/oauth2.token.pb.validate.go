@@ -0,0 +1,512 @@
+// Code generated by protoc-gen-validate. DO NOT EDIT.
+// source: oauth2.token.proto
+
+package proto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ensure the imports are used
+var (
+	_ = bytes.MinRead
+	_ = errors.New("")
+	_ = fmt.Print
+	_ = utf8.UTFMax
+	_ = (*regexp.Regexp)(nil)
+	_ = (*strings.Reader)(nil)
+	_ = net.IPv4len
+	_ = time.Duration(0)
+	_ = (*url.URL)(nil)
+	_ = (*mail.Address)(nil)
+	_ = anypb.Any{}
+	_ = sort.Sort
+)
+
+// Validate checks the field values on IntrospectRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *IntrospectRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on IntrospectRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// IntrospectRequestMultiError, or nil if none found.
+func (m *IntrospectRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *IntrospectRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetClientId()) < 1 {
+		err := IntrospectRequestValidationError{
+			field:  "ClientId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for ClientSecret
+
+	if utf8.RuneCountInString(m.GetToken()) < 1 {
+		err := IntrospectRequestValidationError{
+			field:  "Token",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for TokenTypeHint
+
+	if len(errors) > 0 {
+		return IntrospectRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// IntrospectRequestMultiError is an error wrapping multiple validation errors
+// returned by IntrospectRequest.ValidateAll() if the designated constraints
+// aren't met.
+type IntrospectRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m IntrospectRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m IntrospectRequestMultiError) AllErrors() []error { return m }
+
+// IntrospectRequestValidationError is the validation error returned by
+// IntrospectRequest.Validate if the designated constraints aren't met.
+type IntrospectRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e IntrospectRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e IntrospectRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e IntrospectRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e IntrospectRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e IntrospectRequestValidationError) ErrorName() string {
+	return "IntrospectRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e IntrospectRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sIntrospectRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = IntrospectRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = IntrospectRequestValidationError{}
+
+// Validate checks the field values on IntrospectResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *IntrospectResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on IntrospectResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// IntrospectResponseMultiError, or nil if none found.
+func (m *IntrospectResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *IntrospectResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Active
+
+	// no validation rules for TokenType
+
+	// no validation rules for Jti
+
+	// no validation rules for Sub
+
+	// no validation rules for ClientId
+
+	// no validation rules for Scope
+
+	// no validation rules for SessionId
+
+	// no validation rules for EmailVerified
+
+	// no validation rules for Exp
+
+	// no validation rules for Role
+
+	if len(errors) > 0 {
+		return IntrospectResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// IntrospectResponseMultiError is an error wrapping multiple validation errors
+// returned by IntrospectResponse.ValidateAll() if the designated constraints
+// aren't met.
+type IntrospectResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m IntrospectResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m IntrospectResponseMultiError) AllErrors() []error { return m }
+
+// IntrospectResponseValidationError is the validation error returned by
+// IntrospectResponse.Validate if the designated constraints aren't met.
+type IntrospectResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e IntrospectResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e IntrospectResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e IntrospectResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e IntrospectResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e IntrospectResponseValidationError) ErrorName() string {
+	return "IntrospectResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e IntrospectResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sIntrospectResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = IntrospectResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = IntrospectResponseValidationError{}
+
+// Validate checks the field values on RevokeRequest with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *RevokeRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RevokeRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in RevokeRequestMultiError, or
+// nil if none found.
+func (m *RevokeRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RevokeRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetClientId()) < 1 {
+		err := RevokeRequestValidationError{
+			field:  "ClientId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for ClientSecret
+
+	if utf8.RuneCountInString(m.GetToken()) < 1 {
+		err := RevokeRequestValidationError{
+			field:  "Token",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for TokenTypeHint
+
+	if len(errors) > 0 {
+		return RevokeRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// RevokeRequestMultiError is an error wrapping multiple validation errors
+// returned by RevokeRequest.ValidateAll() if the designated constraints
+// aren't met.
+type RevokeRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RevokeRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RevokeRequestMultiError) AllErrors() []error { return m }
+
+// RevokeRequestValidationError is the validation error returned by
+// RevokeRequest.Validate if the designated constraints aren't met.
+type RevokeRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RevokeRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RevokeRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RevokeRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RevokeRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RevokeRequestValidationError) ErrorName() string { return "RevokeRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e RevokeRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRevokeRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RevokeRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RevokeRequestValidationError{}
+
+// Validate checks the field values on RevokeResponse with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *RevokeResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RevokeResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in RevokeResponseMultiError,
+// or nil if none found.
+func (m *RevokeResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RevokeResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return RevokeResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// RevokeResponseMultiError is an error wrapping multiple validation errors
+// returned by RevokeResponse.ValidateAll() if the designated constraints
+// aren't met.
+type RevokeResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RevokeResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RevokeResponseMultiError) AllErrors() []error { return m }
+
+// RevokeResponseValidationError is the validation error returned by
+// RevokeResponse.Validate if the designated constraints aren't met.
+type RevokeResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RevokeResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RevokeResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RevokeResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RevokeResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RevokeResponseValidationError) ErrorName() string { return "RevokeResponseValidationError" }
+
+// Error satisfies the builtin error interface
+func (e RevokeResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRevokeResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RevokeResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RevokeResponseValidationError{}
@@ -0,0 +1,682 @@
+// Code generated by protoc-gen-validate. DO NOT EDIT.
+// source: oauth2.totp.proto
+
+package proto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ensure the imports are used
+var (
+	_ = bytes.MinRead
+	_ = errors.New("")
+	_ = fmt.Print
+	_ = utf8.UTFMax
+	_ = (*regexp.Regexp)(nil)
+	_ = (*strings.Reader)(nil)
+	_ = net.IPv4len
+	_ = time.Duration(0)
+	_ = (*url.URL)(nil)
+	_ = (*mail.Address)(nil)
+	_ = anypb.Any{}
+	_ = sort.Sort
+)
+
+// Validate checks the field values on TOTPEnrollRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *TOTPEnrollRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on TOTPEnrollRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// TOTPEnrollRequestMultiError, or nil if none found.
+func (m *TOTPEnrollRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *TOTPEnrollRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AccessToken
+
+	if len(errors) > 0 {
+		return TOTPEnrollRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// TOTPEnrollRequestMultiError is an error wrapping multiple validation errors
+// returned by TOTPEnrollRequest.ValidateAll() if the designated constraints
+// aren't met.
+type TOTPEnrollRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TOTPEnrollRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TOTPEnrollRequestMultiError) AllErrors() []error { return m }
+
+// TOTPEnrollRequestValidationError is the validation error returned by
+// TOTPEnrollRequest.Validate if the designated constraints aren't met.
+type TOTPEnrollRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TOTPEnrollRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TOTPEnrollRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TOTPEnrollRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TOTPEnrollRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TOTPEnrollRequestValidationError) ErrorName() string {
+	return "TOTPEnrollRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e TOTPEnrollRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTOTPEnrollRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TOTPEnrollRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TOTPEnrollRequestValidationError{}
+
+// Validate checks the field values on TOTPEnrollResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *TOTPEnrollResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on TOTPEnrollResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// TOTPEnrollResponseMultiError, or nil if none found.
+func (m *TOTPEnrollResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *TOTPEnrollResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Secret
+
+	// no validation rules for Uri
+
+	if len(errors) > 0 {
+		return TOTPEnrollResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// TOTPEnrollResponseMultiError is an error wrapping multiple validation errors
+// returned by TOTPEnrollResponse.ValidateAll() if the designated constraints
+// aren't met.
+type TOTPEnrollResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TOTPEnrollResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TOTPEnrollResponseMultiError) AllErrors() []error { return m }
+
+// TOTPEnrollResponseValidationError is the validation error returned by
+// TOTPEnrollResponse.Validate if the designated constraints aren't met.
+type TOTPEnrollResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TOTPEnrollResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TOTPEnrollResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TOTPEnrollResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TOTPEnrollResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TOTPEnrollResponseValidationError) ErrorName() string {
+	return "TOTPEnrollResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e TOTPEnrollResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTOTPEnrollResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TOTPEnrollResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TOTPEnrollResponseValidationError{}
+
+// Validate checks the field values on TOTPConfirmRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *TOTPConfirmRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on TOTPConfirmRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// TOTPConfirmRequestMultiError, or nil if none found.
+func (m *TOTPConfirmRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *TOTPConfirmRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AccessToken
+
+	if utf8.RuneCountInString(m.GetCode()) != 6 {
+		err := TOTPConfirmRequestValidationError{
+			field:  "Code",
+			reason: "value length must be 6 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+
+	}
+
+	if len(errors) > 0 {
+		return TOTPConfirmRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// TOTPConfirmRequestMultiError is an error wrapping multiple validation errors
+// returned by TOTPConfirmRequest.ValidateAll() if the designated constraints
+// aren't met.
+type TOTPConfirmRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TOTPConfirmRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TOTPConfirmRequestMultiError) AllErrors() []error { return m }
+
+// TOTPConfirmRequestValidationError is the validation error returned by
+// TOTPConfirmRequest.Validate if the designated constraints aren't met.
+type TOTPConfirmRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TOTPConfirmRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TOTPConfirmRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TOTPConfirmRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TOTPConfirmRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TOTPConfirmRequestValidationError) ErrorName() string {
+	return "TOTPConfirmRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e TOTPConfirmRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTOTPConfirmRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TOTPConfirmRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TOTPConfirmRequestValidationError{}
+
+// Validate checks the field values on TOTPConfirmResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *TOTPConfirmResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on TOTPConfirmResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// TOTPConfirmResponseMultiError, or nil if none found.
+func (m *TOTPConfirmResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *TOTPConfirmResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return TOTPConfirmResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// TOTPConfirmResponseMultiError is an error wrapping multiple validation
+// errors returned by TOTPConfirmResponse.ValidateAll() if the designated
+// constraints aren't met.
+type TOTPConfirmResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TOTPConfirmResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TOTPConfirmResponseMultiError) AllErrors() []error { return m }
+
+// TOTPConfirmResponseValidationError is the validation error returned by
+// TOTPConfirmResponse.Validate if the designated constraints aren't met.
+type TOTPConfirmResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TOTPConfirmResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TOTPConfirmResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TOTPConfirmResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TOTPConfirmResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TOTPConfirmResponseValidationError) ErrorName() string {
+	return "TOTPConfirmResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e TOTPConfirmResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTOTPConfirmResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TOTPConfirmResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TOTPConfirmResponseValidationError{}
+
+// Validate checks the field values on TOTPDisableRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *TOTPDisableRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on TOTPDisableRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// TOTPDisableRequestMultiError, or nil if none found.
+func (m *TOTPDisableRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *TOTPDisableRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for AccessToken
+
+	if utf8.RuneCountInString(m.GetCode()) != 6 {
+		err := TOTPDisableRequestValidationError{
+			field:  "Code",
+			reason: "value length must be 6 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+
+	}
+
+	if len(errors) > 0 {
+		return TOTPDisableRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// TOTPDisableRequestMultiError is an error wrapping multiple validation errors
+// returned by TOTPDisableRequest.ValidateAll() if the designated constraints
+// aren't met.
+type TOTPDisableRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TOTPDisableRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TOTPDisableRequestMultiError) AllErrors() []error { return m }
+
+// TOTPDisableRequestValidationError is the validation error returned by
+// TOTPDisableRequest.Validate if the designated constraints aren't met.
+type TOTPDisableRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TOTPDisableRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TOTPDisableRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TOTPDisableRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TOTPDisableRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TOTPDisableRequestValidationError) ErrorName() string {
+	return "TOTPDisableRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e TOTPDisableRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTOTPDisableRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TOTPDisableRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TOTPDisableRequestValidationError{}
+
+// Validate checks the field values on TOTPDisableResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *TOTPDisableResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on TOTPDisableResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// TOTPDisableResponseMultiError, or nil if none found.
+func (m *TOTPDisableResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *TOTPDisableResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return TOTPDisableResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// TOTPDisableResponseMultiError is an error wrapping multiple validation
+// errors returned by TOTPDisableResponse.ValidateAll() if the designated
+// constraints aren't met.
+type TOTPDisableResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TOTPDisableResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TOTPDisableResponseMultiError) AllErrors() []error { return m }
+
+// TOTPDisableResponseValidationError is the validation error returned by
+// TOTPDisableResponse.Validate if the designated constraints aren't met.
+type TOTPDisableResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TOTPDisableResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TOTPDisableResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TOTPDisableResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TOTPDisableResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TOTPDisableResponseValidationError) ErrorName() string {
+	return "TOTPDisableResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e TOTPDisableResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTOTPDisableResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TOTPDisableResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TOTPDisableResponseValidationError{}
@@ -2,20 +2,33 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
 	"io"
 	"net"
 	"os"
 	"time"
+	_ "time/tzdata" // Timezones of user profiles are validated against it, and the runtime image has none.
 
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/jmoiron/sqlx"
+	"github.com/nazarslota/unotes/auth/internal/audit"
 	"github.com/nazarslota/unotes/auth/internal/config"
 	"github.com/nazarslota/unotes/auth/internal/handler/grpc"
 	"github.com/nazarslota/unotes/auth/internal/handler/rest"
+	"github.com/nazarslota/unotes/auth/internal/identityprovider"
+	"github.com/nazarslota/unotes/auth/internal/mailer"
+	"github.com/nazarslota/unotes/auth/internal/outbox"
+	"github.com/nazarslota/unotes/auth/internal/passkey"
 	"github.com/nazarslota/unotes/auth/internal/service"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
 	"github.com/nazarslota/unotes/auth/internal/storage"
 	"github.com/nazarslota/unotes/auth/internal/storage/postgres"
-	"github.com/nazarslota/unotes/auth/internal/storage/redis"
 	"github.com/nazarslota/unotes/auth/pkg/jwt"
 	"github.com/nazarslota/unotes/auth/pkg/logger"
+	"github.com/nazarslota/unotes/auth/pkg/password"
+	"github.com/nazarslota/unotes/auth/pkg/ratelimit"
 	"github.com/nazarslota/unotes/auth/pkg/utils"
 )
 
@@ -32,42 +45,90 @@ func init() {
 }
 
 func main() {
-	log.Info("Connecting to the PostgreSQL database...")
-	postgresDB, err := postgres.NewPostgreSQL(context.Background(), postgres.Config{
-		Host:     config.C().PostgreSQL.Host,
-		Port:     config.C().PostgreSQL.Port,
-		Username: config.C().PostgreSQL.Username,
-		Password: config.C().PostgreSQL.Password,
-		DBName:   config.C().PostgreSQL.DBName,
-		SSLMode:  config.C().PostgreSQL.SSLMode,
-	})
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
+	repositories := storage.NewRepositoryProvider(
+		storage.WithFilesystemBlobRepository(config.C().Auth.AvatarDir),
+	)
+	if repositories.FilesystemBlobRepository == nil {
+		log.FatalFields("Failed to create avatar directory.", map[string]any{"dir": config.C().Auth.AvatarDir})
+	}
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC(config.C().Auth.AccessTokenSecret)
+	refreshTokenManager := jwt.NewRefreshTokenManagerHMAC(config.C().Auth.RefreshTokenSecret)
+	mfaTokenManager := jwt.NewMFATokenManagerHMAC(config.C().Auth.MFATokenSecret)
+
+	idTokenKey, err := oidcPrivateKey(config.C().Auth.OIDCPrivateKeyFile)
 	if err != nil {
-		log.FatalFields("Failed to connect to PostgreSQL database.", map[string]any{"error": err})
-	} else {
-		log.Info("Successfully connected to PostgreSQL database.")
+		log.FatalFields("Failed to load OpenID Connect private key.", map[string]any{"error": err})
+	}
+	idTokenManager := jwt.NewIDTokenManagerRSA(idTokenKey)
+
+	identityProviders := make(map[string]serviceoauth2.IdentityProvider)
+	if len(config.C().Auth.IdentityProviderIssuer) != 0 {
+		log.Info("Discovering the external identity provider...")
+		provider, err := identityprovider.NewOIDCProvider(context.Background(), identityprovider.Config{
+			Issuer:       config.C().Auth.IdentityProviderIssuer,
+			ClientID:     config.C().Auth.IdentityProviderClientID,
+			ClientSecret: config.C().Auth.IdentityProviderClientSecret,
+			RedirectURL:  config.C().Auth.IdentityProviderRedirectURL,
+		})
+		if err != nil {
+			log.FatalFields("Failed to discover external identity provider.", map[string]any{"error": err})
+		}
+		identityProviders[config.C().Auth.IdentityProviderName] = provider
 	}
 
-	log.Info("Connecting to the Redis database...")
-	redisDB, err := redis.NewRedis(context.Background(), redis.Config{
-		Addr:     config.C().Redis.Addr,
-		Password: config.C().Redis.Password,
-		DB:       config.C().Redis.DB,
+	relyingParty, err := passkey.NewRelyingParty(passkey.Config{
+		RPID:          config.C().Auth.WebAuthnRPID,
+		RPDisplayName: config.C().Auth.WebAuthnRPDisplayName,
+		RPOrigins:     config.C().Auth.WebAuthnRPOrigins,
 	})
 	if err != nil {
-		log.FatalFields("Failed to connect to Redis database.", map[string]any{"error": err})
+		log.FatalFields("Failed to create WebAuthn relying party.", map[string]any{"error": err})
+	}
+
+	var passwordHasher serviceoauth2.PasswordHasher
+	if config.C().Auth.PasswordHasher == "bcrypt" {
+		passwordHasher, err = password.NewBcryptHasher(config.C().Auth.BcryptCost)
 	} else {
-		log.Info("Successfully connected to Redis database.")
+		passwordHasher, err = password.NewArgon2idHasher(password.Argon2idParams{
+			Memory:  config.C().Auth.Argon2Memory,
+			Time:    config.C().Auth.Argon2Time,
+			Threads: config.C().Auth.Argon2Threads,
+		})
+	}
+	if err != nil {
+		log.FatalFields("Failed to create password hasher.", map[string]any{"error": err})
 	}
 
-	repositories := storage.NewRepositoryProvider(
-		storage.WithPostgreSQLUserRepository(postgresDB),
-		storage.WithRedisRefreshTokenRepository(redisDB),
-	)
+	passwordPolicy := serviceoauth2.PasswordPolicy{MinEntropy: config.C().Auth.PasswordMinEntropy}
+	if len(config.C().Auth.PasswordBreachedListFile) != 0 {
+		breached, err := password.LoadBreachedList(config.C().Auth.PasswordBreachedListFile)
+		if err != nil {
+			log.FatalFields("Failed to load breached password list.", map[string]any{"error": err})
+		}
+		log.InfoFields("Loaded breached password list.", map[string]any{"passwords": breached.Len()})
+		passwordPolicy.BreachedPasswords = breached
+	}
 
-	accessTokenManager := jwt.NewAccessTokenManagerHMAC(config.C().Auth.AccessTokenSecret)
-	refreshTokenManager := jwt.NewRefreshTokenManagerHMAC(config.C().Auth.RefreshTokenSecret)
+	var mail serviceoauth2.Mailer = mailer.NewLogMailer(os.Stdout)
+	if config.C().Auth.Mailer == "smtp" {
+		mail, err = mailer.NewSMTPMailer(mailer.Config{
+			Host:     config.C().SMTP.Host,
+			Port:     config.C().SMTP.Port,
+			Username: config.C().SMTP.Username,
+			Password: config.C().SMTP.Password,
+			From:     config.C().SMTP.From,
+		})
+		if err != nil {
+			log.FatalFields("Failed to create SMTP mailer.", map[string]any{"error": err})
+		}
+	}
 
-	services := service.NewServices(service.OAuth2ServiceOptions{
+	options := service.OAuth2ServiceOptions{
 		AccessTokenCreator:   accessTokenManager,
 		AccessTokenParser:    accessTokenManager,
 		AccessTokenExpiresIn: config.C().Auth.AccessTokenExpiresIn,
@@ -76,22 +137,89 @@ func main() {
 		RefreshTokenParser:    refreshTokenManager,
 		RefreshTokenExpiresIn: config.C().Auth.RefreshTokenExpiresIn,
 
-		RefreshTokenSaver:    repositories.RedisRefreshTokenRepository,
-		RefreshTokenDeleter:  repositories.RedisRefreshTokenRepository,
-		RefreshTokensDeleter: repositories.RedisRefreshTokenRepository,
-		RefreshTokenGetter:   repositories.RedisRefreshTokenRepository,
+		MFATokenCreator:   mfaTokenManager,
+		MFATokenParser:    mfaTokenManager,
+		MFATokenExpiresIn: config.C().Auth.MFATokenExpiresIn,
 
-		UserSaver:  repositories.PostgresUserRepository,
-		UserFinder: repositories.PostgresUserRepository,
-	})
+		IDTokenCreator: idTokenManager,
+		IDTokenKeySet:  idTokenManager,
+		Issuer:         config.C().Auth.OIDCIssuer,
+
+		TOTPIssuer: config.C().Auth.TOTPIssuer,
+
+		LockoutPolicy: serviceoauth2.LockoutPolicy{
+			MaxAttempts:      config.C().Auth.SignInMaxAttempts,
+			MaxAttemptsPerIP: config.C().Auth.SignInMaxAttemptsPerIP,
+			Backoff:          config.C().Auth.SignInBackoff,
+			Lockout:          config.C().Auth.SignInLockout,
+			Window:           config.C().Auth.SignInAttemptsWindow,
+		},
+
+		PasswordHasher: passwordHasher,
+		PasswordPolicy: passwordPolicy,
+
+		PasswordResetURL:            config.C().Auth.PasswordResetURL,
+		PasswordResetTokenExpiresIn: config.C().Auth.PasswordResetTokenExpiresIn,
+
+		MagicLinkURL:            config.C().Auth.MagicLinkURL,
+		MagicLinkTokenExpiresIn: config.C().Auth.MagicLinkTokenExpiresIn,
+		MagicLinkRateLimit: serviceoauth2.MagicLinkRateLimit{
+			MaxRequests:      config.C().Auth.MagicLinkMaxRequests,
+			MaxRequestsPerIP: config.C().Auth.MagicLinkMaxRequestsPerIP,
+			Window:           config.C().Auth.MagicLinkRequestsWindow,
+		},
+
+		EmailVerificationURL:            config.C().Auth.EmailVerificationURL,
+		EmailVerificationTokenExpiresIn: config.C().Auth.EmailVerificationTokenExpiresIn,
+
+		AuthorizationCodeExpiresIn: config.C().Auth.AuthorizationCodeExpiresIn,
+
+		IdentityProviders:      identityProviders,
+		IdentityStateExpiresIn: config.C().Auth.IdentityStateExpiresIn,
+
+		PasskeyRelyingParty:     relyingParty,
+		PasskeySessionExpiresIn: config.C().Auth.WebAuthnSessionExpiresIn,
+
+		AvatarURL:     config.C().Auth.AvatarURL,
+		AvatarMaxSize: config.C().Auth.AvatarMaxSize,
+
+		BlobSaver:   repositories.FilesystemBlobRepository,
+		BlobFinder:  repositories.FilesystemBlobRepository,
+		BlobDeleter: repositories.FilesystemBlobRepository,
+
+		Mailer: mail,
+	}
+
+	backend := openStorage(&options)
+	services := service.NewServices(options)
+
+	rateLimiter := ratelimit.NewLimiter(config.C().Auth.RateLimitRequests, config.C().Auth.RateLimitPeriod)
+
+	var trustedProxies []*net.IPNet
+	for _, proxy := range config.C().Auth.TrustedProxies {
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			log.FatalFields("Failed to parse trusted proxy.", map[string]any{"proxy": proxy, "error": err})
+		}
+		trustedProxies = append(trustedProxies, ipNet)
+	}
 
 	restAddress := net.JoinHostPort(config.C().Auth.HostREST, config.C().Auth.PortREST)
-	restServer := rest.NewHandler(
+	restOptions := []rest.HandlerOption{
 		rest.WithServices(services),
 		rest.WithAddress(restAddress),
 		rest.WithLogger(log),
 		rest.WithDebug(config.C().Auth.Debug),
-	).Server()
+		rest.WithRateLimiter(rateLimiter),
+		rest.WithCORSAllowOrigins(config.C().Auth.CORSAllowedOrigins),
+		rest.WithTrustedProxies(trustedProxies),
+	}
+	if config.C().Auth.SessionCookies {
+		restOptions = append(restOptions, rest.WithSessionCookies(
+			config.C().Auth.SessionCookiePath, config.C().Auth.RefreshTokenExpiresIn,
+		))
+	}
+	restServer := rest.NewHandler(restOptions...).Server()
 
 	log.InfoFields("Starting a REST server...", map[string]any{"address": restAddress})
 	go func() {
@@ -108,6 +236,7 @@ func main() {
 		grpc.WithService(services),
 		grpc.WithAddress(grpcAddress),
 		grpc.WithLogger(log),
+		grpc.WithRateLimiter(rateLimiter),
 	).Server()
 
 	log.InfoFields("Starting a gRPC server...", map[string]any{"address": grpcAddress})
@@ -120,6 +249,33 @@ func main() {
 	time.Sleep(time.Second)
 	log.Info("The gRPC server is successfully started.")
 
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
+	relay := outbox.NewRelay(
+		backend.outboxMessages, backend.outboxMessages, backend.bus,
+		config.C().Auth.OutboxInterval, log,
+	)
+	go func() {
+		defer close(relayDone)
+		relay.Run(relayCtx)
+	}()
+	log.InfoFields("The outbox relay is started.", map[string]any{"interval": config.C().Auth.OutboxInterval.String()})
+
+	pruneCtx, stopPruner := context.WithCancel(context.Background())
+	prunerDone := make(chan struct{})
+	pruner := audit.NewPruner(
+		backend.auditEvents,
+		config.C().Auth.AuditRetention, config.C().Auth.AuditPruneInterval, log,
+	)
+	go func() {
+		defer close(prunerDone)
+		pruner.Run(pruneCtx)
+	}()
+	log.InfoFields("The audit log pruner is started.", map[string]any{
+		"retention": config.C().Auth.AuditRetention.String(),
+		"interval":  config.C().Auth.AuditPruneInterval.String(),
+	})
+
 	<-utils.GracefulShutdown()
 	log.Info("Shutdown of the REST server...")
 	if err := restServer.Shutdown(context.Background()); err != nil {
@@ -135,17 +291,40 @@ func main() {
 		log.Info("gRPC server was successfully shut down.")
 	}
 
-	log.Info("Closing the connection to PostgreSQL...")
-	if err := postgresDB.Close(); err != nil {
-		log.ErrorFields("Failed to close connection to PostgreSQL.", map[string]any{"error": err})
-	} else {
-		log.Info("The connection to PostgreSQL is successfully closed.")
+	log.Info("Stopping the outbox relay...")
+	stopRelay()
+	<-relayDone
+
+	log.Info("Stopping the audit log pruner...")
+	stopPruner()
+	<-prunerDone
+
+	backend.close()
+}
+
+// oidcPrivateKey reads the PEM encoded RSA private key ID tokens are signed with. Without a key file, a new key is
+// generated, so ID tokens can't be verified anymore once the service restarts.
+func oidcPrivateKey(file string) (*rsa.PrivateKey, error) {
+	if len(file) == 0 {
+		log.Warn("No OpenID Connect private key file is configured, generating a temporary key.")
+		return rsa.GenerateKey(rand.Reader, 2048)
 	}
 
-	log.Info("Closing the connection to Redis...")
-	if err := redisDB.Close(); err != nil {
-		log.ErrorFields("Failed to close connection to Redis.", map[string]any{"error": err})
-	} else {
-		log.Info("The connection to Redis is successfully closed.")
+	key, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
 	}
+	return gojwt.ParseRSAPrivateKeyFromPEM(key)
+}
+
+// connectPostgreSQL connects to the PostgreSQL database of the configuration.
+func connectPostgreSQL() (*sqlx.DB, error) {
+	return postgres.NewPostgreSQL(context.Background(), postgres.Config{
+		Host:     config.C().PostgreSQL.Host,
+		Port:     config.C().PostgreSQL.Port,
+		Username: config.C().PostgreSQL.Username,
+		Password: config.C().PostgreSQL.Password,
+		DBName:   config.C().PostgreSQL.DBName,
+		SSLMode:  config.C().PostgreSQL.SSLMode,
+	})
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nazarslota/unotes/auth/internal/config"
+	"github.com/nazarslota/unotes/auth/internal/storage/postgres"
+	"github.com/nazarslota/unotes/auth/internal/storage/sqlite"
+	"github.com/nazarslota/unotes/auth/pkg/migrate"
+)
+
+// runMigrate runs the migrate subcommand, `auth migrate up|down|status|force`, with args, the arguments that follow
+// it, and returns the exit code of the process.
+func runMigrate(args []string) int {
+	db, err := connectDatabase()
+	if err != nil {
+		log.ErrorFields("Failed to connect to the database.", map[string]any{"error": err})
+		return 1
+	}
+	defer func() { _ = db.Close() }()
+
+	migrator, err := newMigrator(db)
+	if err != nil {
+		log.ErrorFields("Failed to create the migrator.", map[string]any{"error": err})
+		return 1
+	}
+	defer func() { _ = migrator.Close() }()
+
+	if err := migrate.Run(migrator, args, os.Stdout); errors.Is(err, migrate.ErrUsage) {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 2
+	} else if err != nil {
+		log.ErrorFields("Failed to migrate the database.", map[string]any{"error": err})
+		return 1
+	}
+	return 0
+}
+
+// autoMigrate applies the migrations that are not applied yet to the database at startup. Replicas started at the
+// same time wait for each other, the migrations are applied under a lock of the database.
+func autoMigrate(db *sqlx.DB) error {
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return fmt.Errorf("failed to create migrator: %w", err)
+	}
+	defer func() { _ = migrator.Close() }()
+
+	if err := migrator.Up(); err != nil {
+		return err
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	log.InfoFields("The database schema is up to date.", map[string]any{"version": status.Version})
+	return nil
+}
+
+// connectDatabase connects to the relational database of the configuration, PostgreSQL or SQLite as chosen by
+// AUTH_DATABASE_DRIVER.
+func connectDatabase() (*sqlx.DB, error) {
+	if config.C().Auth.DatabaseDriver == "sqlite" {
+		return sqlite.NewSQLite(context.Background(), sqlite.Config{Path: config.C().SQLite.Path})
+	}
+	return connectPostgreSQL()
+}
+
+// newMigrator returns the migrator of the schema of the relational database of the configuration.
+func newMigrator(db *sqlx.DB) (*migrate.Migrator, error) {
+	if config.C().Auth.DatabaseDriver == "sqlite" {
+		return sqlite.NewMigrator(db)
+	}
+	return postgres.NewMigrator(context.Background(), db)
+}
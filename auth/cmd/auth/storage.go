@@ -0,0 +1,434 @@
+package main
+
+import (
+	"context"
+
+	"github.com/nazarslota/unotes/auth/internal/audit"
+	"github.com/nazarslota/unotes/auth/internal/config"
+	"github.com/nazarslota/unotes/auth/internal/outbox"
+	"github.com/nazarslota/unotes/auth/internal/service"
+	"github.com/nazarslota/unotes/auth/internal/storage"
+	"github.com/nazarslota/unotes/auth/internal/storage/memory"
+	"github.com/nazarslota/unotes/auth/internal/storage/redis"
+	"github.com/nazarslota/unotes/auth/pkg/messagebus"
+)
+
+// storageBackend is the storage the service runs with, as chosen by AUTH_STORAGE. The repositories of the service are
+// set on its options, the rest is used to run the outbox relay and the audit log pruner.
+type storageBackend struct {
+	outboxMessages interface {
+		outbox.MessageFinder
+		outbox.MessageDeleter
+	}
+	auditEvents audit.EventsDeleter
+	bus         outbox.Publisher
+
+	// close closes the connections to the databases.
+	close func()
+}
+
+// openStorage opens the storage of the configuration and sets the repositories of options to it.
+func openStorage(options *service.OAuth2ServiceOptions) storageBackend {
+	if config.C().Auth.Storage == "memory" {
+		return openMemoryStorage(options)
+	}
+	return openDatabaseStorage(options)
+}
+
+// openDatabaseStorage connects to the relational database, PostgreSQL or SQLite as chosen by AUTH_DATABASE_DRIVER, and
+// to the Redis database, and sets the repositories of options to them.
+func openDatabaseStorage(options *service.OAuth2ServiceOptions) storageBackend {
+	var relational storageBackend
+	if config.C().Auth.DatabaseDriver == "sqlite" {
+		relational = openSQLiteStorage(options)
+	} else {
+		relational = openPostgreSQLStorage(options)
+	}
+
+	log.Info("Connecting to the Redis database...")
+	redisDB, err := redis.NewRedis(context.Background(), redis.Config{
+		Addr:     config.C().Redis.Addr,
+		Password: config.C().Redis.Password,
+		DB:       config.C().Redis.DB,
+	})
+	if err != nil {
+		log.FatalFields("Failed to connect to Redis database.", map[string]any{"error": err})
+	} else {
+		log.Info("Successfully connected to Redis database.")
+	}
+
+	repositories := storage.NewRepositoryProvider(
+		storage.WithRedisRefreshTokenRepository(redisDB),
+		storage.WithRedisSignInAttemptRepository(redisDB),
+		storage.WithRedisRevokedTokenRepository(redisDB),
+	)
+
+	options.RefreshTokenSaver = repositories.RedisRefreshTokenRepository
+	options.RefreshTokenDeleter = repositories.RedisRefreshTokenRepository
+	options.RefreshTokensDeleter = repositories.RedisRefreshTokenRepository
+	options.RefreshTokenGetter = repositories.RedisRefreshTokenRepository
+
+	options.RevokedTokenSaver = repositories.RedisRevokedTokenRepository
+	options.RevokedTokenChecker = repositories.RedisRevokedTokenRepository
+
+	options.SignInFailureSaver = repositories.RedisSignInAttemptRepository
+	options.SignInLockSaver = repositories.RedisSignInAttemptRepository
+	options.SignInLockFinder = repositories.RedisSignInAttemptRepository
+	options.SignInFailuresDeleter = repositories.RedisSignInAttemptRepository
+	options.MagicLinkRequestSaver = repositories.RedisSignInAttemptRepository
+
+	// Events are published on the Redis database the service already uses, other services subscribe to them there.
+	bus, err := messagebus.NewRedisBus(redisDB)
+	if err != nil {
+		log.FatalFields("Failed to create message bus.", map[string]any{"error": err})
+	}
+
+	// Revocations of access tokens are published right away rather than through the outbox, since they aren't part of
+	// a change to the relational database.
+	options.RevokedTokenPublisher = bus
+
+	return storageBackend{
+		outboxMessages: relational.outboxMessages,
+		auditEvents:    relational.auditEvents,
+		bus:            bus,
+		close: func() {
+			relational.close()
+
+			log.Info("Closing the connection to Redis...")
+			if err := redisDB.Close(); err != nil {
+				log.ErrorFields("Failed to close connection to Redis.", map[string]any{"error": err})
+			} else {
+				log.Info("The connection to Redis is successfully closed.")
+			}
+		},
+	}
+}
+
+// openPostgreSQLStorage connects to the PostgreSQL database, applies the migrations if enabled and sets the
+// repositories of options that are kept in the relational database to it. The returned backend has no message bus.
+func openPostgreSQLStorage(options *service.OAuth2ServiceOptions) storageBackend {
+	log.Info("Connecting to the PostgreSQL database...")
+	db, err := connectPostgreSQL()
+	if err != nil {
+		log.FatalFields("Failed to connect to PostgreSQL database.", map[string]any{"error": err})
+	} else {
+		log.Info("Successfully connected to PostgreSQL database.")
+	}
+
+	if config.C().Auth.AutoMigrate {
+		log.Info("Migrating the database schema...")
+		if err := autoMigrate(db); err != nil {
+			log.FatalFields("Failed to migrate the database schema.", map[string]any{"error": err})
+		}
+	}
+
+	repositories := storage.NewRepositoryProvider(
+		storage.WithPostgreSQLUserRepository(db),
+		storage.WithPostgreSQLTOTPRepository(db),
+		storage.WithPostgreSQLPasswordResetTokenRepository(db),
+		storage.WithPostgreSQLMagicLinkTokenRepository(db),
+		storage.WithPostgreSQLEmailVerificationTokenRepository(db),
+		storage.WithPostgreSQLClientRepository(db),
+		storage.WithPostgreSQLAuthorizationCodeRepository(db),
+		storage.WithPostgreSQLIdentityRepository(db),
+		storage.WithPostgreSQLOutboxRepository(db),
+		storage.WithPostgreSQLPersonalAccessTokenRepository(db),
+		storage.WithPostgreSQLPasskeyRepository(db),
+		storage.WithPostgreSQLAuditEventRepository(db),
+	)
+
+	options.UserSaver = repositories.PostgresUserRepository
+	options.UserFinder = repositories.PostgresUserRepository
+	options.UserUpdater = repositories.PostgresUserRepository
+	options.UserDeleter = repositories.PostgresUserRepository
+	options.UsersFinder = repositories.PostgresUserRepository
+	options.UserRoleUpdater = repositories.PostgresUserRepository
+	options.UserDisabledUpdater = repositories.PostgresUserRepository
+
+	options.ProfileFinder = repositories.PostgresUserRepository
+	options.ProfilesFinder = repositories.PostgresUserRepository
+	options.ProfileUpdater = repositories.PostgresUserRepository
+
+	options.DeletedUsersFinder = repositories.PostgresUserRepository
+
+	options.TOTPSaver = repositories.PostgresTOTPRepository
+	options.TOTPFinder = repositories.PostgresTOTPRepository
+	options.TOTPUpdater = repositories.PostgresTOTPRepository
+	options.TOTPLastUsedStepUpdater = repositories.PostgresTOTPRepository
+	options.TOTPDeleter = repositories.PostgresTOTPRepository
+	options.RecoveryCodesSaver = repositories.PostgresTOTPRepository
+	options.RecoveryCodeDeleter = repositories.PostgresTOTPRepository
+	options.RecoveryCodesDeleter = repositories.PostgresTOTPRepository
+
+	options.PersonalAccessTokenSaver = repositories.PostgresPersonalAccessTokenRepository
+	options.PersonalAccessTokensFinder = repositories.PostgresPersonalAccessTokenRepository
+	options.PersonalAccessTokenFinder = repositories.PostgresPersonalAccessTokenRepository
+	options.PersonalAccessTokenUsageUpdater = repositories.PostgresPersonalAccessTokenRepository
+	options.PersonalAccessTokenDeleter = repositories.PostgresPersonalAccessTokenRepository
+
+	options.PasskeySaver = repositories.PostgresPasskeyRepository
+	options.PasskeysFinder = repositories.PostgresPasskeyRepository
+	options.PasskeyFinder = repositories.PostgresPasskeyRepository
+	options.PasskeyUsageUpdater = repositories.PostgresPasskeyRepository
+	options.PasskeyDeleter = repositories.PostgresPasskeyRepository
+	options.PasskeySessionSaver = repositories.PostgresPasskeyRepository
+	options.PasskeySessionConsumer = repositories.PostgresPasskeyRepository
+
+	options.PasswordResetTokenSaver = repositories.PostgresPasswordResetTokenRepository
+	options.PasswordResetTokenFinder = repositories.PostgresPasswordResetTokenRepository
+	options.PasswordResetTokenConsumer = repositories.PostgresPasswordResetTokenRepository
+	options.PasswordResetTokensDeleter = repositories.PostgresPasswordResetTokenRepository
+	options.MagicLinkTokenSaver = repositories.PostgresMagicLinkTokenRepository
+	options.MagicLinkTokenConsumer = repositories.PostgresMagicLinkTokenRepository
+	options.MagicLinkTokensDeleter = repositories.PostgresMagicLinkTokenRepository
+
+	options.EmailVerificationTokenSaver = repositories.PostgresEmailVerificationTokenRepository
+	options.EmailVerificationTokenConsumer = repositories.PostgresEmailVerificationTokenRepository
+	options.EmailVerificationTokensDeleter = repositories.PostgresEmailVerificationTokenRepository
+
+	options.ClientSaver = repositories.PostgresClientRepository
+	options.ClientFinder = repositories.PostgresClientRepository
+	options.AuthorizationCodeSaver = repositories.PostgresAuthorizationCodeRepository
+	options.AuthorizationCodeConsumer = repositories.PostgresAuthorizationCodeRepository
+
+	options.IdentitySaver = repositories.PostgresIdentityRepository
+	options.IdentityFinder = repositories.PostgresIdentityRepository
+	options.IdentityStateSaver = repositories.PostgresIdentityRepository
+	options.IdentityStateConsumer = repositories.PostgresIdentityRepository
+
+	options.AuditLogger = repositories.PostgresAuditEventRepository
+	options.AuditEventsFinder = repositories.PostgresAuditEventRepository
+
+	return storageBackend{
+		outboxMessages: repositories.PostgresOutboxRepository,
+		auditEvents:    repositories.PostgresAuditEventRepository,
+		close: func() {
+			log.Info("Closing the connection to PostgreSQL...")
+			if err := db.Close(); err != nil {
+				log.ErrorFields("Failed to close connection to PostgreSQL.", map[string]any{"error": err})
+			} else {
+				log.Info("The connection to PostgreSQL is successfully closed.")
+			}
+		},
+	}
+}
+
+// openSQLiteStorage connects to the SQLite database, applies the migrations if enabled and sets the repositories
+// of options that are kept in the relational database to it. The returned backend has no message bus.
+func openSQLiteStorage(options *service.OAuth2ServiceOptions) storageBackend {
+	log.Info("Connecting to the SQLite database...")
+	db, err := connectDatabase()
+	if err != nil {
+		log.FatalFields("Failed to connect to SQLite database.", map[string]any{"error": err})
+	} else {
+		log.Info("Successfully connected to SQLite database.")
+	}
+
+	if config.C().Auth.AutoMigrate {
+		log.Info("Migrating the database schema...")
+		if err := autoMigrate(db); err != nil {
+			log.FatalFields("Failed to migrate the database schema.", map[string]any{"error": err})
+		}
+	}
+
+	repositories := storage.NewRepositoryProvider(
+		storage.WithSQLiteUserRepository(db),
+		storage.WithSQLiteTOTPRepository(db),
+		storage.WithSQLitePasswordResetTokenRepository(db),
+		storage.WithSQLiteMagicLinkTokenRepository(db),
+		storage.WithSQLiteEmailVerificationTokenRepository(db),
+		storage.WithSQLiteClientRepository(db),
+		storage.WithSQLiteAuthorizationCodeRepository(db),
+		storage.WithSQLiteIdentityRepository(db),
+		storage.WithSQLiteOutboxRepository(db),
+		storage.WithSQLitePersonalAccessTokenRepository(db),
+		storage.WithSQLitePasskeyRepository(db),
+		storage.WithSQLiteAuditEventRepository(db),
+	)
+
+	options.UserSaver = repositories.SQLiteUserRepository
+	options.UserFinder = repositories.SQLiteUserRepository
+	options.UserUpdater = repositories.SQLiteUserRepository
+	options.UserDeleter = repositories.SQLiteUserRepository
+	options.UsersFinder = repositories.SQLiteUserRepository
+	options.UserRoleUpdater = repositories.SQLiteUserRepository
+	options.UserDisabledUpdater = repositories.SQLiteUserRepository
+
+	options.ProfileFinder = repositories.SQLiteUserRepository
+	options.ProfilesFinder = repositories.SQLiteUserRepository
+	options.ProfileUpdater = repositories.SQLiteUserRepository
+
+	options.DeletedUsersFinder = repositories.SQLiteUserRepository
+
+	options.TOTPSaver = repositories.SQLiteTOTPRepository
+	options.TOTPFinder = repositories.SQLiteTOTPRepository
+	options.TOTPUpdater = repositories.SQLiteTOTPRepository
+	options.TOTPLastUsedStepUpdater = repositories.SQLiteTOTPRepository
+	options.TOTPDeleter = repositories.SQLiteTOTPRepository
+	options.RecoveryCodesSaver = repositories.SQLiteTOTPRepository
+	options.RecoveryCodeDeleter = repositories.SQLiteTOTPRepository
+	options.RecoveryCodesDeleter = repositories.SQLiteTOTPRepository
+
+	options.PersonalAccessTokenSaver = repositories.SQLitePersonalAccessTokenRepository
+	options.PersonalAccessTokensFinder = repositories.SQLitePersonalAccessTokenRepository
+	options.PersonalAccessTokenFinder = repositories.SQLitePersonalAccessTokenRepository
+	options.PersonalAccessTokenUsageUpdater = repositories.SQLitePersonalAccessTokenRepository
+	options.PersonalAccessTokenDeleter = repositories.SQLitePersonalAccessTokenRepository
+
+	options.PasskeySaver = repositories.SQLitePasskeyRepository
+	options.PasskeysFinder = repositories.SQLitePasskeyRepository
+	options.PasskeyFinder = repositories.SQLitePasskeyRepository
+	options.PasskeyUsageUpdater = repositories.SQLitePasskeyRepository
+	options.PasskeyDeleter = repositories.SQLitePasskeyRepository
+	options.PasskeySessionSaver = repositories.SQLitePasskeyRepository
+	options.PasskeySessionConsumer = repositories.SQLitePasskeyRepository
+
+	options.PasswordResetTokenSaver = repositories.SQLitePasswordResetTokenRepository
+	options.PasswordResetTokenFinder = repositories.SQLitePasswordResetTokenRepository
+	options.PasswordResetTokenConsumer = repositories.SQLitePasswordResetTokenRepository
+	options.PasswordResetTokensDeleter = repositories.SQLitePasswordResetTokenRepository
+	options.MagicLinkTokenSaver = repositories.SQLiteMagicLinkTokenRepository
+	options.MagicLinkTokenConsumer = repositories.SQLiteMagicLinkTokenRepository
+	options.MagicLinkTokensDeleter = repositories.SQLiteMagicLinkTokenRepository
+
+	options.EmailVerificationTokenSaver = repositories.SQLiteEmailVerificationTokenRepository
+	options.EmailVerificationTokenConsumer = repositories.SQLiteEmailVerificationTokenRepository
+	options.EmailVerificationTokensDeleter = repositories.SQLiteEmailVerificationTokenRepository
+
+	options.ClientSaver = repositories.SQLiteClientRepository
+	options.ClientFinder = repositories.SQLiteClientRepository
+	options.AuthorizationCodeSaver = repositories.SQLiteAuthorizationCodeRepository
+	options.AuthorizationCodeConsumer = repositories.SQLiteAuthorizationCodeRepository
+
+	options.IdentitySaver = repositories.SQLiteIdentityRepository
+	options.IdentityFinder = repositories.SQLiteIdentityRepository
+	options.IdentityStateSaver = repositories.SQLiteIdentityRepository
+	options.IdentityStateConsumer = repositories.SQLiteIdentityRepository
+
+	options.AuditLogger = repositories.SQLiteAuditEventRepository
+	options.AuditEventsFinder = repositories.SQLiteAuditEventRepository
+
+	return storageBackend{
+		outboxMessages: repositories.SQLiteOutboxRepository,
+		auditEvents:    repositories.SQLiteAuditEventRepository,
+		close: func() {
+			log.Info("Closing the connection to SQLite...")
+			if err := db.Close(); err != nil {
+				log.ErrorFields("Failed to close connection to SQLite.", map[string]any{"error": err})
+			} else {
+				log.Info("The connection to SQLite is successfully closed.")
+			}
+		},
+	}
+}
+
+// openMemoryStorage sets the repositories of options to an in-memory database, so that the service runs without any
+// database. Everything is lost once the service stops, and events are only published within the process.
+func openMemoryStorage(options *service.OAuth2ServiceOptions) storageBackend {
+	log.Warn("The in-memory storage is used, all the data is lost once the service stops.")
+
+	db := memory.NewDB()
+	repositories := storage.NewRepositoryProvider(
+		storage.WithMemoryUserRepository(db),
+		storage.WithMemoryTOTPRepository(db),
+		storage.WithMemoryPasswordResetTokenRepository(db),
+		storage.WithMemoryMagicLinkTokenRepository(db),
+		storage.WithMemoryEmailVerificationTokenRepository(db),
+		storage.WithMemoryClientRepository(db),
+		storage.WithMemoryAuthorizationCodeRepository(db),
+		storage.WithMemoryIdentityRepository(db),
+		storage.WithMemoryOutboxRepository(db),
+		storage.WithMemoryPersonalAccessTokenRepository(db),
+		storage.WithMemoryPasskeyRepository(db),
+		storage.WithMemoryAuditEventRepository(db),
+		storage.WithMemoryRefreshTokenRepository(db),
+		storage.WithMemorySignInAttemptRepository(db),
+		storage.WithMemoryRevokedTokenRepository(db),
+	)
+
+	options.RefreshTokenSaver = repositories.MemoryRefreshTokenRepository
+	options.RefreshTokenDeleter = repositories.MemoryRefreshTokenRepository
+	options.RefreshTokensDeleter = repositories.MemoryRefreshTokenRepository
+	options.RefreshTokenGetter = repositories.MemoryRefreshTokenRepository
+
+	options.RevokedTokenSaver = repositories.MemoryRevokedTokenRepository
+	options.RevokedTokenChecker = repositories.MemoryRevokedTokenRepository
+
+	options.UserSaver = repositories.MemoryUserRepository
+	options.UserFinder = repositories.MemoryUserRepository
+	options.UserUpdater = repositories.MemoryUserRepository
+	options.UserDeleter = repositories.MemoryUserRepository
+	options.UsersFinder = repositories.MemoryUserRepository
+	options.UserRoleUpdater = repositories.MemoryUserRepository
+	options.UserDisabledUpdater = repositories.MemoryUserRepository
+
+	options.ProfileFinder = repositories.MemoryUserRepository
+	options.ProfilesFinder = repositories.MemoryUserRepository
+	options.ProfileUpdater = repositories.MemoryUserRepository
+
+	options.DeletedUsersFinder = repositories.MemoryUserRepository
+
+	options.TOTPSaver = repositories.MemoryTOTPRepository
+	options.TOTPFinder = repositories.MemoryTOTPRepository
+	options.TOTPUpdater = repositories.MemoryTOTPRepository
+	options.TOTPLastUsedStepUpdater = repositories.MemoryTOTPRepository
+	options.TOTPDeleter = repositories.MemoryTOTPRepository
+	options.RecoveryCodesSaver = repositories.MemoryTOTPRepository
+	options.RecoveryCodeDeleter = repositories.MemoryTOTPRepository
+	options.RecoveryCodesDeleter = repositories.MemoryTOTPRepository
+
+	options.SignInFailureSaver = repositories.MemorySignInAttemptRepository
+	options.SignInLockSaver = repositories.MemorySignInAttemptRepository
+	options.SignInLockFinder = repositories.MemorySignInAttemptRepository
+	options.SignInFailuresDeleter = repositories.MemorySignInAttemptRepository
+	options.MagicLinkRequestSaver = repositories.MemorySignInAttemptRepository
+
+	options.PersonalAccessTokenSaver = repositories.MemoryPersonalAccessTokenRepository
+	options.PersonalAccessTokensFinder = repositories.MemoryPersonalAccessTokenRepository
+	options.PersonalAccessTokenFinder = repositories.MemoryPersonalAccessTokenRepository
+	options.PersonalAccessTokenUsageUpdater = repositories.MemoryPersonalAccessTokenRepository
+	options.PersonalAccessTokenDeleter = repositories.MemoryPersonalAccessTokenRepository
+
+	options.PasskeySaver = repositories.MemoryPasskeyRepository
+	options.PasskeysFinder = repositories.MemoryPasskeyRepository
+	options.PasskeyFinder = repositories.MemoryPasskeyRepository
+	options.PasskeyUsageUpdater = repositories.MemoryPasskeyRepository
+	options.PasskeyDeleter = repositories.MemoryPasskeyRepository
+	options.PasskeySessionSaver = repositories.MemoryPasskeyRepository
+	options.PasskeySessionConsumer = repositories.MemoryPasskeyRepository
+
+	options.PasswordResetTokenSaver = repositories.MemoryPasswordResetTokenRepository
+	options.PasswordResetTokenFinder = repositories.MemoryPasswordResetTokenRepository
+	options.PasswordResetTokenConsumer = repositories.MemoryPasswordResetTokenRepository
+	options.PasswordResetTokensDeleter = repositories.MemoryPasswordResetTokenRepository
+	options.MagicLinkTokenSaver = repositories.MemoryMagicLinkTokenRepository
+	options.MagicLinkTokenConsumer = repositories.MemoryMagicLinkTokenRepository
+	options.MagicLinkTokensDeleter = repositories.MemoryMagicLinkTokenRepository
+
+	options.EmailVerificationTokenSaver = repositories.MemoryEmailVerificationTokenRepository
+	options.EmailVerificationTokenConsumer = repositories.MemoryEmailVerificationTokenRepository
+	options.EmailVerificationTokensDeleter = repositories.MemoryEmailVerificationTokenRepository
+
+	options.ClientSaver = repositories.MemoryClientRepository
+	options.ClientFinder = repositories.MemoryClientRepository
+	options.AuthorizationCodeSaver = repositories.MemoryAuthorizationCodeRepository
+	options.AuthorizationCodeConsumer = repositories.MemoryAuthorizationCodeRepository
+
+	options.IdentitySaver = repositories.MemoryIdentityRepository
+	options.IdentityFinder = repositories.MemoryIdentityRepository
+	options.IdentityStateSaver = repositories.MemoryIdentityRepository
+	options.IdentityStateConsumer = repositories.MemoryIdentityRepository
+
+	options.AuditLogger = repositories.MemoryAuditEventRepository
+	options.AuditEventsFinder = repositories.MemoryAuditEventRepository
+
+	bus := messagebus.NewMemoryBus()
+	options.RevokedTokenPublisher = bus
+
+	return storageBackend{
+		outboxMessages: repositories.MemoryOutboxRepository,
+		auditEvents:    repositories.MemoryAuditEventRepository,
+		bus:            bus,
+		close:          func() {},
+	}
+}
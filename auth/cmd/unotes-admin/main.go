@@ -0,0 +1,195 @@
+// Command unotes-admin administers the users of the auth service over its gRPC API. It is authorized by the access
+// token of an admin, passed by -token or UNOTES_ADMIN_TOKEN.
+//
+// Usage:
+//
+//	unotes-admin [-addr host:port] [-token token] <command> [arguments]
+//
+// The commands are:
+//
+//	users [-query query] [-after username] [-limit n]  list users, optionally matching query
+//	sessions <user-id>                                 list the sessions of a user
+//	disable <user-id>                                  disable a user and sign them out
+//	enable <user-id>                                   enable a disabled user
+//	reset-password <user-id>                           force a user to reset their password
+//	revoke-sessions <user-id>                          sign a user out of all their sessions
+//	role <user-id> <user|admin>                        assign a role to a user
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/nazarslota/unotes/auth/api/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const usage = `usage: unotes-admin [-addr host:port] [-token token] <command> [arguments]
+
+commands:
+  users [-query query] [-after username] [-limit n]
+  sessions <user-id>
+  disable <user-id>
+  enable <user-id>
+  reset-password <user-id>
+  revoke-sessions <user-id>
+  role <user-id> <user|admin>
+`
+
+// errUsage is returned by the commands for invalid arguments.
+var errUsage = errors.New(usage)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs unotes-admin with args, the arguments that follow the program name, and returns the exit code of the
+// process.
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("unotes-admin", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { _, _ = fmt.Fprint(stderr, usage) }
+
+	addr := flags.String("addr", getenv("UNOTES_ADMIN_ADDR", "localhost:8091"), "address of the auth gRPC API")
+	token := flags.String("token", os.Getenv("UNOTES_ADMIN_TOKEN"), "access token of an admin")
+	timeout := flags.Duration("timeout", 10*time.Second, "timeout of a request")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 || *token == "" {
+		_, _ = fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	conn, err := grpc.Dial(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "unotes-admin: failed to dial %s: %v\n", *addr, err)
+		return 1
+	}
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	c := command{client: pb.NewOAuth2ServiceClient(conn), token: *token, out: stdout}
+	if err := c.run(ctx, flags.Arg(0), flags.Args()[1:]); errors.Is(err, errUsage) {
+		_, _ = fmt.Fprint(stderr, usage)
+		return 2
+	} else if err != nil {
+		if s, ok := status.FromError(err); ok {
+			err = errors.New(s.Message())
+		}
+		_, _ = fmt.Fprintf(stderr, "unotes-admin: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// command runs the commands of unotes-admin against the auth service.
+type command struct {
+	client pb.OAuth2ServiceClient
+	token  string
+	out    io.Writer
+}
+
+func (c command) run(ctx context.Context, name string, args []string) error {
+	if name == "users" {
+		return c.users(ctx, args)
+	}
+
+	if name == "role" {
+		if len(args) != 2 {
+			return errUsage
+		}
+		_, err := c.client.SetUserRole(ctx, &pb.SetUserRoleRequest{AccessToken: c.token, UserId: args[0], Role: args[1]})
+		return err
+	}
+
+	if len(args) != 1 {
+		return errUsage
+	}
+	userID := args[0]
+
+	var err error
+	switch name {
+	case "sessions":
+		return c.sessions(ctx, userID)
+	case "disable", "enable":
+		_, err = c.client.SetUserDisabled(ctx, &pb.SetUserDisabledRequest{
+			AccessToken: c.token, UserId: userID, Disabled: name == "disable",
+		})
+	case "reset-password":
+		_, err = c.client.ForcePasswordReset(ctx, &pb.ForcePasswordResetRequest{AccessToken: c.token, UserId: userID})
+	case "revoke-sessions":
+		_, err = c.client.RevokeUserSessions(ctx, &pb.RevokeUserSessionsRequest{AccessToken: c.token, UserId: userID})
+	default:
+		return errUsage
+	}
+	return err
+}
+
+// users prints a page of users, and the argument of -after that lists the next page if there is one.
+func (c command) users(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("users", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	query := flags.String("query", "", "")
+	after := flags.String("after", "", "")
+	limit := flags.Int("limit", 0, "")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 {
+		return errUsage
+	}
+
+	response, err := c.client.ListUsers(ctx, &pb.ListUsersRequest{
+		AccessToken: c.token, Query: *query, After: *after, Limit: int32(*limit),
+	})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tUSERNAME\tEMAIL\tVERIFIED\tROLE\tDISABLED")
+	for _, user := range response.Users {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\t%t\n",
+			user.Id, user.Username, user.Email, user.EmailVerified, user.Role, user.Disabled)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if response.Next != "" {
+		_, _ = fmt.Fprintf(c.out, "\nnext page: -after %s\n", response.Next)
+	}
+	return nil
+}
+
+// sessions prints the sessions of the user with userID.
+func (c command) sessions(ctx context.Context, userID string) error {
+	response, err := c.client.ListUserSessions(ctx, &pb.ListUserSessionsRequest{AccessToken: c.token, UserId: userID})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "SESSION\tCLIENT\tSCOPE\tEXPIRES")
+	for _, session := range response.Sessions {
+		expiresAt := time.Unix(session.ExpiresAt, 0).Format(time.RFC3339)
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", session.SessionId, session.ClientId, session.Scope, expiresAt)
+	}
+	return w.Flush()
+}
+
+// getenv returns the value of the environment variable key, or fallback if it is empty.
+func getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
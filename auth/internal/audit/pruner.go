@@ -0,0 +1,63 @@
+// Package audit prunes the events of the audit log once they are older than the retention period.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type EventsDeleter interface {
+	DeleteAuditEventsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type Logger interface {
+	WarnFields(msg string, fields map[string]any)
+}
+
+// Pruner deletes the events of the audit log that are older than Retention.
+type Pruner struct {
+	EventsDeleter EventsDeleter
+
+	Retention time.Duration
+	Interval  time.Duration
+	Logger    Logger
+}
+
+// NewPruner creates a new Pruner that deletes events older than retention every interval.
+func NewPruner(eventsDeleter EventsDeleter, retention, interval time.Duration, logger Logger) *Pruner {
+	return &Pruner{
+		EventsDeleter: eventsDeleter,
+
+		Retention: retention,
+		Interval:  interval,
+		Logger:    logger,
+	}
+}
+
+// Run prunes events until the context is done. Failures are logged and retried at the next interval.
+func (p *Pruner) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Prune(ctx); err != nil && ctx.Err() == nil {
+			p.Logger.WarnFields("Failed to prune audit events.", map[string]any{"error": err})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune deletes the events older than the retention period and returns the number of deleted events.
+func (p *Pruner) Prune(ctx context.Context) (int64, error) {
+	deleted, err := p.EventsDeleter.DeleteAuditEventsBefore(ctx, time.Now().Add(-p.Retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete audit events: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryEvents keeps the creation times of events.
+type memoryEvents struct {
+	createdAt []time.Time
+}
+
+func (e *memoryEvents) DeleteAuditEventsBefore(_ context.Context, before time.Time) (int64, error) {
+	var kept []time.Time
+	for _, createdAt := range e.createdAt {
+		if !createdAt.Before(before) {
+			kept = append(kept, createdAt)
+		}
+	}
+
+	deleted := int64(len(e.createdAt) - len(kept))
+	e.createdAt = kept
+	return deleted, nil
+}
+
+func TestPruner_Prune(t *testing.T) {
+	t.Run("should delete only events older than the retention period", func(t *testing.T) {
+		now := time.Now()
+		events := &memoryEvents{createdAt: []time.Time{
+			now.Add(-48 * time.Hour),
+			now.Add(-25 * time.Hour),
+			now.Add(-time.Hour),
+			now,
+		}}
+
+		deleted, err := NewPruner(events, 24*time.Hour, 0, nil).Prune(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+		assert.Equal(t, []time.Time{now.Add(-time.Hour), now}, events.createdAt)
+	})
+}
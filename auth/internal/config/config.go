@@ -11,16 +11,69 @@ import (
 
 type Config struct {
 	Auth struct {
-		HostREST              string        `mapstructure:"AUTH_HOST_REST"`
-		PortREST              string        `mapstructure:"AUTH_PORT_REST"`
-		HostGRPC              string        `mapstructure:"AUTH_HOST_GRPC"`
-		PortGRPC              string        `mapstructure:"AUTH_PORT_GRPC"`
-		AccessTokenSecret     string        `mapstructure:"AUTH_ACCESS_TOKEN_SECRET"`
-		AccessTokenExpiresIn  time.Duration `mapstructure:"AUTH_ACCESS_TOKEN_EXPIRES_IN"`
-		RefreshTokenSecret    string        `mapstructure:"AUTH_REFRESH_TOKEN_SECRET"`
-		RefreshTokenExpiresIn time.Duration `mapstructure:"AUTH_REFRESH_TOKEN_EXPIRES_IN"`
-		Debug                 bool          `mapstructure:"AUTH_DEBUG"`
-		Log                   string        `mapstructure:"AUTH_LOG"`
+		HostREST                        string        `mapstructure:"AUTH_HOST_REST"`
+		PortREST                        string        `mapstructure:"AUTH_PORT_REST"`
+		HostGRPC                        string        `mapstructure:"AUTH_HOST_GRPC"`
+		PortGRPC                        string        `mapstructure:"AUTH_PORT_GRPC"`
+		AccessTokenSecret               string        `mapstructure:"AUTH_ACCESS_TOKEN_SECRET"`
+		AccessTokenExpiresIn            time.Duration `mapstructure:"AUTH_ACCESS_TOKEN_EXPIRES_IN"`
+		RefreshTokenSecret              string        `mapstructure:"AUTH_REFRESH_TOKEN_SECRET"`
+		RefreshTokenExpiresIn           time.Duration `mapstructure:"AUTH_REFRESH_TOKEN_EXPIRES_IN"`
+		MFATokenSecret                  string        `mapstructure:"AUTH_MFA_TOKEN_SECRET"`
+		MFATokenExpiresIn               time.Duration `mapstructure:"AUTH_MFA_TOKEN_EXPIRES_IN"`
+		TOTPIssuer                      string        `mapstructure:"AUTH_TOTP_ISSUER"`
+		PasswordHasher                  string        `mapstructure:"AUTH_PASSWORD_HASHER" validate:"oneof=argon2id bcrypt"`
+		Argon2Memory                    uint32        `mapstructure:"AUTH_ARGON2_MEMORY"`
+		Argon2Time                      uint32        `mapstructure:"AUTH_ARGON2_TIME"`
+		Argon2Threads                   uint8         `mapstructure:"AUTH_ARGON2_THREADS"`
+		BcryptCost                      int           `mapstructure:"AUTH_BCRYPT_COST"`
+		PasswordMinEntropy              float64       `mapstructure:"AUTH_PASSWORD_MIN_ENTROPY"`
+		PasswordBreachedListFile        string        `mapstructure:"AUTH_PASSWORD_BREACHED_LIST_FILE"`
+		PasswordResetURL                string        `mapstructure:"AUTH_PASSWORD_RESET_URL"`
+		PasswordResetTokenExpiresIn     time.Duration `mapstructure:"AUTH_PASSWORD_RESET_TOKEN_EXPIRES_IN"`
+		MagicLinkURL                    string        `mapstructure:"AUTH_MAGIC_LINK_URL"`
+		MagicLinkTokenExpiresIn         time.Duration `mapstructure:"AUTH_MAGIC_LINK_TOKEN_EXPIRES_IN"`
+		MagicLinkMaxRequests            int           `mapstructure:"AUTH_MAGIC_LINK_MAX_REQUESTS"`
+		MagicLinkMaxRequestsPerIP       int           `mapstructure:"AUTH_MAGIC_LINK_MAX_REQUESTS_PER_IP"`
+		MagicLinkRequestsWindow         time.Duration `mapstructure:"AUTH_MAGIC_LINK_REQUESTS_WINDOW"`
+		EmailVerificationURL            string        `mapstructure:"AUTH_EMAIL_VERIFICATION_URL"`
+		EmailVerificationTokenExpiresIn time.Duration `mapstructure:"AUTH_EMAIL_VERIFICATION_TOKEN_EXPIRES_IN"`
+		AuthorizationCodeExpiresIn      time.Duration `mapstructure:"AUTH_AUTHORIZATION_CODE_EXPIRES_IN"`
+		OIDCIssuer                      string        `mapstructure:"AUTH_OIDC_ISSUER" validate:"omitempty,url"`
+		OIDCPrivateKeyFile              string        `mapstructure:"AUTH_OIDC_PRIVATE_KEY_FILE"`
+		IdentityProviderName            string        `mapstructure:"AUTH_IDENTITY_PROVIDER_NAME" validate:"required_with=IdentityProviderIssuer"`
+		IdentityProviderIssuer          string        `mapstructure:"AUTH_IDENTITY_PROVIDER_ISSUER" validate:"omitempty,url"`
+		IdentityProviderClientID        string        `mapstructure:"AUTH_IDENTITY_PROVIDER_CLIENT_ID" validate:"required_with=IdentityProviderIssuer"`
+		IdentityProviderClientSecret    string        `mapstructure:"AUTH_IDENTITY_PROVIDER_CLIENT_SECRET"`
+		IdentityProviderRedirectURL     string        `mapstructure:"AUTH_IDENTITY_PROVIDER_REDIRECT_URL" validate:"required_with=IdentityProviderIssuer,omitempty,url"`
+		IdentityStateExpiresIn          time.Duration `mapstructure:"AUTH_IDENTITY_STATE_EXPIRES_IN"`
+		WebAuthnRPID                    string        `mapstructure:"AUTH_WEBAUTHN_RP_ID" validate:"required"`
+		WebAuthnRPDisplayName           string        `mapstructure:"AUTH_WEBAUTHN_RP_DISPLAY_NAME"`
+		WebAuthnRPOrigins               []string      `mapstructure:"AUTH_WEBAUTHN_RP_ORIGINS" validate:"min=1,dive,url"`
+		WebAuthnSessionExpiresIn        time.Duration `mapstructure:"AUTH_WEBAUTHN_SESSION_EXPIRES_IN"`
+		AvatarURL                       string        `mapstructure:"AUTH_AVATAR_URL"`
+		AvatarMaxSize                   int64         `mapstructure:"AUTH_AVATAR_MAX_SIZE"`
+		AvatarDir                       string        `mapstructure:"AUTH_AVATAR_DIR"`
+		Storage                         string        `mapstructure:"AUTH_STORAGE" validate:"oneof=postgres memory"`
+		DatabaseDriver                  string        `mapstructure:"AUTH_DATABASE_DRIVER" validate:"oneof=postgres sqlite"`
+		AutoMigrate                     bool          `mapstructure:"AUTH_AUTO_MIGRATE"`
+		OutboxInterval                  time.Duration `mapstructure:"AUTH_OUTBOX_INTERVAL" validate:"gt=0"`
+		AuditRetention                  time.Duration `mapstructure:"AUTH_AUDIT_RETENTION" validate:"gt=0"`
+		AuditPruneInterval              time.Duration `mapstructure:"AUTH_AUDIT_PRUNE_INTERVAL" validate:"gt=0"`
+		Mailer                          string        `mapstructure:"AUTH_MAILER" validate:"oneof=log smtp"`
+		SignInMaxAttempts               int           `mapstructure:"AUTH_SIGN_IN_MAX_ATTEMPTS"`
+		SignInMaxAttemptsPerIP          int           `mapstructure:"AUTH_SIGN_IN_MAX_ATTEMPTS_PER_IP"`
+		SignInBackoff                   time.Duration `mapstructure:"AUTH_SIGN_IN_BACKOFF"`
+		SignInLockout                   time.Duration `mapstructure:"AUTH_SIGN_IN_LOCKOUT"`
+		SignInAttemptsWindow            time.Duration `mapstructure:"AUTH_SIGN_IN_ATTEMPTS_WINDOW"`
+		RateLimitRequests               int           `mapstructure:"AUTH_RATE_LIMIT_REQUESTS"`
+		RateLimitPeriod                 time.Duration `mapstructure:"AUTH_RATE_LIMIT_PERIOD"`
+		SessionCookies                  bool          `mapstructure:"AUTH_SESSION_COOKIES"`
+		SessionCookiePath               string        `mapstructure:"AUTH_SESSION_COOKIE_PATH" validate:"required_if=SessionCookies true"`
+		CORSAllowedOrigins              []string      `mapstructure:"AUTH_CORS_ALLOWED_ORIGINS" validate:"dive,url"`
+		TrustedProxies                  []string      `mapstructure:"AUTH_TRUSTED_PROXIES" validate:"dive,cidr"`
+		Debug                           bool          `mapstructure:"AUTH_DEBUG"`
+		Log                             string        `mapstructure:"AUTH_LOG"`
 	} `mapstructure:",squash"`
 	PostgreSQL struct {
 		Host     string `mapstructure:"AUTH_POSTGRESQL_HOST"`
@@ -30,11 +83,21 @@ type Config struct {
 		DBName   string `mapstructure:"AUTH_POSTGRESQL_DBNAME"`
 		SSLMode  string `mapstructure:"AUTH_POSTGRESQL_SSLMODE"`
 	} `mapstructure:",squash"`
+	SQLite struct {
+		Path string `mapstructure:"AUTH_SQLITE_PATH"`
+	} `mapstructure:",squash"`
 	Redis struct {
 		Addr     string `mapstructure:"AUTH_REDIS_ADDR"`
 		Password string `mapstructure:"AUTH_REDIS_PASSWORD"`
 		DB       int    `mapstructure:"AUTH_REDIS_DB"`
 	} `mapstructure:",squash"`
+	SMTP struct {
+		Host     string `mapstructure:"AUTH_SMTP_HOST"`
+		Port     string `mapstructure:"AUTH_SMTP_PORT"`
+		Username string `mapstructure:"AUTH_SMTP_USERNAME"`
+		Password string `mapstructure:"AUTH_SMTP_PASSWORD"`
+		From     string `mapstructure:"AUTH_SMTP_FROM"`
+	} `mapstructure:",squash"`
 }
 
 var (
@@ -88,8 +151,12 @@ func C() *Config {
 func bindEnvAuth(v *viper.Viper) {
 	_ = v.BindEnv("AUTH_ACCESS_TOKEN_SECRET")
 	_ = v.BindEnv("AUTH_REFRESH_TOKEN_SECRET")
+	_ = v.BindEnv("AUTH_MFA_TOKEN_SECRET")
+	_ = v.BindEnv("AUTH_OIDC_PRIVATE_KEY_FILE")
+	_ = v.BindEnv("AUTH_IDENTITY_PROVIDER_CLIENT_SECRET")
 	bindEnvPostgreSQL(v)
 	bindEnvRedis(v)
+	bindEnvSMTP(v)
 }
 
 func bindEnvPostgreSQL(v *viper.Viper) {
@@ -106,3 +173,11 @@ func bindEnvRedis(v *viper.Viper) {
 	_ = v.BindEnv("AUTH_REDIS_PASSWORD")
 	_ = v.BindEnv("AUTH_REDIS_DB")
 }
+
+func bindEnvSMTP(v *viper.Viper) {
+	_ = v.BindEnv("AUTH_SMTP_HOST")
+	_ = v.BindEnv("AUTH_SMTP_PORT")
+	_ = v.BindEnv("AUTH_SMTP_USERNAME")
+	_ = v.BindEnv("AUTH_SMTP_PASSWORD")
+	_ = v.BindEnv("AUTH_SMTP_FROM")
+}
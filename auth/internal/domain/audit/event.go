@@ -0,0 +1,81 @@
+package audit
+
+import "time"
+
+// Event is a security event of the authentication service, such as a sign in or a password change. Events are only
+// ever appended to the audit log, and deleted once they are older than the retention period. UserID is empty when
+// the event can't be attributed to a user, such as a sign in with an unknown username, and Reason is empty for
+// successful events.
+type Event struct {
+	ID        string    `db:"id"`
+	Type      string    `db:"type"`
+	UserID    string    `db:"user_id"`
+	IP        string    `db:"ip"`
+	UserAgent string    `db:"user_agent"`
+	SessionID string    `db:"session_id"`
+	Outcome   string    `db:"outcome"`
+	Reason    string    `db:"reason"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Types of events. Requests that only read, such as getting a profile or listing passkeys, are not recorded.
+const (
+	TypeSignUp                    = "sign_up"
+	TypeSignIn                    = "sign_in"
+	TypeSignInMFA                 = "sign_in_mfa"
+	TypeSignInUnlock              = "sign_in_unlock"
+	TypeExternalAuthorize         = "external_authorize"
+	TypeExternalSignIn            = "external_sign_in"
+	TypeIdentityLinkBegin         = "identity_link_begin"
+	TypeIdentityLink              = "identity_link"
+	TypeRefresh                   = "refresh"
+	TypeSignOut                   = "sign_out"
+	TypeTOTPEnroll                = "totp_enroll"
+	TypeTOTPConfirm               = "totp_confirm"
+	TypeTOTPDisable               = "totp_disable"
+	TypePasswordChange            = "password_change"
+	TypePasswordResetRequest      = "password_reset_request"
+	TypePasswordReset             = "password_reset"
+	TypeMagicLinkRequest          = "magic_link_request"
+	TypeMagicLinkSignIn           = "magic_link_sign_in"
+	TypePasskeyRegisterBegin      = "passkey_register_begin"
+	TypePasskeyRegister           = "passkey_register"
+	TypePasskeyDelete             = "passkey_delete"
+	TypePasskeySignIn             = "passkey_sign_in"
+	TypePasskeySignInMFA          = "passkey_sign_in_mfa"
+	TypeEmailChange               = "email_change"
+	TypeEmailVerify               = "email_verify"
+	TypeEmailVerificationResend   = "email_verification_resend"
+	TypeProfileUpdate             = "profile_update"
+	TypeAvatarUpload              = "avatar_upload"
+	TypeConsent                   = "consent"
+	TypeToken                     = "token"
+	TypeTokenRevoke               = "token_revoke"
+	TypeClientRegister            = "client_register"
+	TypeAccountDelete             = "account_delete"
+	TypePersonalAccessTokenCreate = "personal_access_token_create"
+	TypePersonalAccessTokenRevoke = "personal_access_token_revoke"
+	TypeAdminUserDisable          = "admin_user_disable"
+	TypeAdminUserEnable           = "admin_user_enable"
+	TypeAdminPasswordReset        = "admin_password_reset"
+	TypeAdminSessionsRevoke       = "admin_sessions_revoke"
+	TypeAdminRoleAssign           = "admin_role_assign"
+)
+
+// Outcomes of events.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Filter selects events by the fields that are set, the zero value selects all events. Since is inclusive and Until
+// is exclusive. Limit is the maximum number of events, 0 means no limit.
+type Filter struct {
+	UserID  string
+	Type    string
+	Outcome string
+	IP      string
+	Since   time.Time
+	Until   time.Time
+	Limit   int
+}
@@ -0,0 +1,24 @@
+package authorizationcode
+
+import (
+	"errors"
+	"time"
+)
+
+// Code is an authorization code issued to a client after the user consented. RedirectURI is the redirect URI of the
+// authorization request as sent, empty if the client left it out to be sent to its only registered one, and must then
+// be sent unchanged when exchanging the code. Scope is space-delimited and CodeChallenge is the PKCE S256 challenge
+// the client has to answer when exchanging the code. Nonce is the OpenID Connect nonce of the authorization request,
+// passed on to the ID token.
+type Code struct {
+	CodeHash      string    `db:"code_hash"`
+	ClientID      string    `db:"client_id"`
+	UserID        string    `db:"user_id"`
+	RedirectURI   string    `db:"redirect_uri"`
+	Scope         string    `db:"scope"`
+	CodeChallenge string    `db:"code_challenge"`
+	Nonce         string    `db:"nonce"`
+	ExpiresAt     time.Time `db:"expires_at"`
+}
+
+var ErrCodeNotFound = errors.New("code not found")
@@ -0,0 +1,11 @@
+package blob
+
+import "errors"
+
+// Blob is a file kept in the blob store, such as an avatar image.
+type Blob struct {
+	ContentType string
+	Data        []byte
+}
+
+var ErrBlobNotFound = errors.New("blob not found")
@@ -0,0 +1,20 @@
+package client
+
+import "errors"
+
+// Client is an OAuth2 client registered to act on behalf of users. Public clients, such as single-page and native
+// applications, can't keep a secret and have an empty SecretHash.
+type Client struct {
+	ID           string
+	SecretHash   string
+	Name         string
+	RedirectURIs []string
+	Scopes       []string
+	GrantTypes   []string
+	Public       bool
+}
+
+var (
+	ErrClientNotFound      = errors.New("client not found")
+	ErrClientAlreadyExists = errors.New("client already exists")
+)
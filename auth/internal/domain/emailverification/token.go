@@ -0,0 +1,17 @@
+package emailverification
+
+import (
+	"errors"
+	"time"
+)
+
+// Token is an email verification token. Email is the address the token was sent to, so that a token sent before the
+// user changed their email can not verify the new one.
+type Token struct {
+	TokenHash string    `db:"token_hash"`
+	UserID    string    `db:"user_id"`
+	Email     string    `db:"email"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+var ErrTokenNotFound = errors.New("token not found")
@@ -0,0 +1,39 @@
+package identity
+
+import (
+	"errors"
+	"time"
+)
+
+// Identity links a user to their account at an external OpenID Connect identity provider. Subject is the user ID at
+// the provider, so the user can be found again however they change their profile there.
+type Identity struct {
+	Provider string `db:"provider"`
+	Subject  string `db:"subject"`
+	UserID   string `db:"user_id"`
+}
+
+// Profile holds the claims an external identity provider asserts about the user who signed in.
+type Profile struct {
+	Subject           string
+	PreferredUsername string
+	Email             string
+	EmailVerified     bool
+}
+
+// State is a sign in with an external identity provider in progress, stored under the hash of the state parameter
+// until the provider redirects back. UserID is set when a signed in user links the identity to their account.
+type State struct {
+	StateHash    string    `db:"state_hash"`
+	Provider     string    `db:"provider"`
+	Nonce        string    `db:"nonce"`
+	CodeVerifier string    `db:"code_verifier"`
+	UserID       string    `db:"user_id"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
+
+var (
+	ErrIdentityNotFound      = errors.New("identity not found")
+	ErrIdentityAlreadyExists = errors.New("identity already exists")
+	ErrStateNotFound         = errors.New("state not found")
+)
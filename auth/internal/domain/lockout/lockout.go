@@ -0,0 +1,5 @@
+package lockout
+
+import "errors"
+
+var ErrLockNotFound = errors.New("lock not found")
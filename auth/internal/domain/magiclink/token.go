@@ -0,0 +1,15 @@
+package magiclink
+
+import (
+	"errors"
+	"time"
+)
+
+type Token struct {
+	TokenHash string    `db:"token_hash"`
+	UserID    string    `db:"user_id"`
+	NonceHash string    `db:"nonce_hash"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+var ErrTokenNotFound = errors.New("token not found")
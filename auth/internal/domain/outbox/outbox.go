@@ -0,0 +1,17 @@
+package outbox
+
+import (
+	"errors"
+	"time"
+)
+
+// Message is an event saved to the outbox in the same transaction as the change it describes, and published to the
+// message bus under Subject afterwards. Messages are published at least once, in the order they were created.
+type Message struct {
+	ID        string    `db:"id"`
+	Subject   string    `db:"subject"`
+	Payload   []byte    `db:"payload"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+var ErrMessageNotFound = errors.New("message not found")
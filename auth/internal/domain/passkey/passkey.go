@@ -0,0 +1,45 @@
+package passkey
+
+import (
+	"errors"
+	"time"
+)
+
+// Credential is a WebAuthn credential, a passkey, a user registered to sign in with. ID is the base64url-encoded
+// credential ID the authenticator chose and SignCount the signature counter it reported last, which is checked to
+// detect cloned authenticators. LastUsedAt is nil until the credential is used.
+type Credential struct {
+	ID         string
+	UserID     string
+	Name       string
+	PublicKey  []byte
+	SignCount  uint32
+	Transports []string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+// Purposes of sessions.
+const (
+	PurposeRegistration = "registration"
+	PurposeSignIn       = "sign_in"
+	PurposeMFA          = "mfa"
+)
+
+// Session is a WebAuthn registration or authentication ceremony in progress, stored under the hash of its token until
+// the client finishes it. UserID is empty for a sign in with a discoverable credential, before the user is known. Data
+// holds the challenge along with the rest of the state of the ceremony, it is opaque to everything but the relying
+// party.
+type Session struct {
+	SessionHash string    `db:"session_hash"`
+	Purpose     string    `db:"purpose"`
+	UserID      string    `db:"user_id"`
+	Data        []byte    `db:"data"`
+	ExpiresAt   time.Time `db:"expires_at"`
+}
+
+var (
+	ErrCredentialNotFound      = errors.New("passkey not found")
+	ErrCredentialAlreadyExists = errors.New("passkey already exists")
+	ErrSessionNotFound         = errors.New("session not found")
+)
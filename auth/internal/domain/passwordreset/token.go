@@ -0,0 +1,14 @@
+package passwordreset
+
+import (
+	"errors"
+	"time"
+)
+
+type Token struct {
+	TokenHash string    `db:"token_hash"`
+	UserID    string    `db:"user_id"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+var ErrTokenNotFound = errors.New("token not found")
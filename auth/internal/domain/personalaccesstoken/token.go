@@ -0,0 +1,22 @@
+package personalaccesstoken
+
+import (
+	"errors"
+	"time"
+)
+
+// Token is a long-lived personal access token a user created for scripts and command line tools. Only the hash of
+// the token is stored, the token itself is shown once when it is created. ExpiresAt is nil for tokens that never
+// expire and LastUsedAt is nil until the token is used.
+type Token struct {
+	ID         string
+	UserID     string
+	Name       string
+	TokenHash  string
+	Scopes     []string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+}
+
+var ErrTokenNotFound = errors.New("personal access token not found")
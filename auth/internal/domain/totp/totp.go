@@ -0,0 +1,21 @@
+package totp
+
+import "errors"
+
+type TOTP struct {
+	UserID       string `db:"user_id"`
+	Secret       string `db:"secret"`
+	Enabled      bool   `db:"enabled"`
+	LastUsedStep int64  `db:"last_used_step"`
+}
+
+type RecoveryCode struct {
+	UserID   string `db:"user_id"`
+	CodeHash string `db:"code_hash"`
+}
+
+var (
+	ErrTOTPNotFound         = errors.New("totp not found")
+	ErrTOTPStepUsed         = errors.New("totp time step already used")
+	ErrRecoveryCodeNotFound = errors.New("recovery code not found")
+)
@@ -2,13 +2,47 @@ package user
 
 import "errors"
 
+// User is a user account. Email is optional and empty if the user has not set one. Role is one of the roles below,
+// an empty role is taken for RoleUser. A disabled user can't sign in until an admin enables them again.
 type User struct {
-	ID           string `db:"id"`
-	Username     string `db:"username"`
-	PasswordHash string `db:"password_hash"`
+	ID            string `db:"id"`
+	Username      string `db:"username"`
+	PasswordHash  string `db:"password_hash"`
+	Email         string `db:"email"`
+	EmailVerified bool   `db:"email_verified"`
+	Role          string `db:"role"`
+	Disabled      bool   `db:"disabled"`
+}
+
+// Roles of users. Admins can manage the service and other users.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Filter selects users for admins. Query matches a part of the username or the email, ignoring case, and the zero
+// value selects all users. Users are ordered by username, After is the username to list the users after and Limit is
+// the maximum number of users, 0 means no limit.
+type Filter struct {
+	Query string
+	After string
+	Limit int
+}
+
+// Profile is the public profile of a user, shown to other users and services. Avatar is the key of the avatar image in
+// the blob store and empty if the user has not uploaded one. Locale is a BCP 47 language tag and Timezone is a name
+// from the IANA time zone database, such as "Europe/Kyiv".
+type Profile struct {
+	UserID      string `db:"id"`
+	Username    string `db:"username"`
+	DisplayName string `db:"display_name"`
+	Avatar      string `db:"avatar"`
+	Locale      string `db:"locale"`
+	Timezone    string `db:"timezone"`
 }
 
 var (
-	ErrUserNotFound      = errors.New("user not found")
-	ErrUserAlreadyExists = errors.New("user already exists")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserAlreadyExists  = errors.New("user already exists")
+	ErrEmailAlreadyExists = errors.New("email already exists")
 )
@@ -5,6 +5,7 @@ import (
 
 	pb "github.com/nazarslota/unotes/auth/api/proto"
 	"github.com/nazarslota/unotes/auth/internal/service"
+	"github.com/nazarslota/unotes/auth/pkg/ratelimit"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
@@ -15,6 +16,7 @@ type Handler struct {
 
 	services            service.Services
 	oAuth2ServiceServer oAuth2ServiceServer
+	rateLimiter         *ratelimit.Limiter
 }
 
 func NewHandler(options ...HandlerOption) *Handler {
@@ -33,7 +35,22 @@ type Server interface {
 
 func (h *Handler) Server() Server {
 	server := grpc.NewServer(
-		grpc.UnaryInterceptor(newGRPCLoggerUnaryInterceptor(h.logger)),
+		grpc.ChainUnaryInterceptor(
+			newGRPCLoggerUnaryInterceptor(h.logger),
+			newGRPCRemoteUnaryInterceptor(),
+			newGRPCRateLimiterUnaryInterceptor(h.rateLimiter,
+				pb.OAuth2Service_SignInMFA_FullMethodName,
+				pb.OAuth2Service_TOTPConfirm_FullMethodName,
+				pb.OAuth2Service_TOTPDisable_FullMethodName,
+				pb.OAuth2Service_ChangePassword_FullMethodName,
+				pb.OAuth2Service_RequestPasswordReset_FullMethodName,
+				pb.OAuth2Service_ConfirmPasswordReset_FullMethodName,
+				pb.OAuth2Service_ChangeEmail_FullMethodName,
+				pb.OAuth2Service_VerifyEmail_FullMethodName,
+				pb.OAuth2Service_ResendVerification_FullMethodName,
+				pb.OAuth2Service_DeleteAccount_FullMethodName,
+			),
+		),
 		grpc.StreamInterceptor(newGRPCLoggerStreamInterceptor(h.logger)),
 	)
 	pb.RegisterOAuth2ServiceServer(server, &h.oAuth2ServiceServer)
@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"errors"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -29,12 +30,21 @@ func (s oAuth2ServiceServer) SignUp(ctx context.Context, in *pb.SignUpRequest) (
 	request := serviceoauth2.SignUpRequest{
 		Username: in.Username,
 		Password: in.Password,
+		Email:    in.Email,
 	}
 	_, err := s.services.OAuth2Service.SignUpRequestHandler.Handler(ctx, request)
-	if errors.Is(err, serviceoauth2.ErrSignUpInvalidUsername) || errors.Is(err, serviceoauth2.ErrSignUpInvalidPassword) {
+
+	var policy serviceoauth2.PasswordPolicyError
+	if errors.As(err, &policy) {
+		return nil, passwordPolicyViolation(policy)
+	} else if errors.Is(err, serviceoauth2.ErrSignUpInvalidUsername) || errors.Is(err, serviceoauth2.ErrSignUpInvalidPassword) {
 		return nil, status.Error(codes.InvalidArgument, "invalid username or password")
+	} else if errors.Is(err, serviceoauth2.ErrSignUpInvalidEmail) {
+		return nil, status.Error(codes.InvalidArgument, "invalid email")
 	} else if errors.Is(err, serviceoauth2.ErrSignUpUserAlreadyExists) {
 		return nil, status.Error(codes.AlreadyExists, "already exists")
+	} else if errors.Is(err, serviceoauth2.ErrSignUpEmailAlreadyExists) {
+		return nil, status.Error(codes.AlreadyExists, "email already exists")
 	} else if err != nil {
 		return nil, status.Error(codes.Internal, "internal")
 	}
@@ -49,16 +59,68 @@ func (s oAuth2ServiceServer) SignIn(ctx context.Context, in *pb.SignInRequest) (
 	request := serviceoauth2.SignInRequest{
 		Username: in.Username,
 		Password: in.Password,
+		IP:       peerIP(ctx),
 	}
 	response, err := s.services.OAuth2Service.SingInRequestHandler.Handle(ctx, request)
-	if errors.Is(err, serviceoauth2.ErrSignInInvalidUsername) || errors.Is(err, serviceoauth2.ErrSignInInvalidPassword) {
+
+	var locked serviceoauth2.SignInLockedError
+	if errors.As(err, &locked) {
+		return nil, resourceExhausted(ctx, "too many failed attempts", locked.RetryAfter)
+	} else if errors.Is(err, serviceoauth2.ErrSignInInvalidUsername) || errors.Is(err, serviceoauth2.ErrSignInInvalidPassword) {
 		return nil, status.Error(codes.InvalidArgument, "invalid username or password")
 	} else if errors.Is(err, serviceoauth2.ErrSignInUserNotFound) {
 		return nil, status.Error(codes.NotFound, "not found")
+	} else if errors.Is(err, serviceoauth2.ErrSignInDisabled) {
+		return nil, status.Error(codes.PermissionDenied, "account disabled")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.SignInResponse{
+		AccessToken:  response.AccessToken,
+		RefreshToken: response.RefreshToken,
+		MfaRequired:  response.MFARequired,
+		MfaToken:     response.MFAToken,
+	}, nil
+}
+
+func (s oAuth2ServiceServer) UnlockSignIn(ctx context.Context, in *pb.UnlockSignInRequest) (*pb.UnlockSignInResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.UnlockSignInRequest{AccessToken: in.AccessToken, Username: in.Username, IP: in.Ip}
+	_, err := s.services.OAuth2Service.UnlockSignInRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrUnlockSignInInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrUnlockSignInPermissionDenied) {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	} else if errors.Is(err, serviceoauth2.ErrUnlockSignInInvalidRequest) {
+		return nil, status.Error(codes.InvalidArgument, "username or ip is required")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.UnlockSignInResponse{}, nil
+}
+
+func (s oAuth2ServiceServer) SignInMFA(ctx context.Context, in *pb.SignInMFARequest) (*pb.SignInMFAResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.SignInMFARequest{MFAToken: in.MfaToken, Code: in.Code, IP: peerIP(ctx)}
+	response, err := s.services.OAuth2Service.SignInMFARequestHandler.Handle(ctx, request)
+
+	var locked serviceoauth2.SignInLockedError
+	if errors.As(err, &locked) {
+		return nil, resourceExhausted(ctx, "too many failed attempts", locked.RetryAfter)
+	} else if errors.Is(err, serviceoauth2.ErrSignInMFAInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrSignInMFAInvalidCode) {
+		return nil, status.Error(codes.InvalidArgument, "invalid code")
 	} else if err != nil {
 		return nil, status.Error(codes.Internal, "internal")
 	}
-	return &pb.SignInResponse{AccessToken: response.AccessToken, RefreshToken: response.RefreshToken}, nil
+	return &pb.SignInMFAResponse{AccessToken: response.AccessToken, RefreshToken: response.RefreshToken}, nil
 }
 
 func (s oAuth2ServiceServer) SignOut(ctx context.Context, in *pb.SignOutRequest) (*pb.SignOutResponse, error) {
@@ -90,3 +152,741 @@ func (s oAuth2ServiceServer) Refresh(ctx context.Context, in *pb.RefreshRequest)
 	}
 	return &pb.RefreshResponse{AccessToken: response.AccessToken, RefreshToken: response.RefreshToken}, nil
 }
+
+func (s oAuth2ServiceServer) TOTPEnroll(ctx context.Context, in *pb.TOTPEnrollRequest) (*pb.TOTPEnrollResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.TOTPEnrollRequest{AccessToken: in.AccessToken}
+	response, err := s.services.OAuth2Service.TOTPEnrollRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrTOTPEnrollInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrTOTPEnrollAlreadyEnabled) {
+		return nil, status.Error(codes.AlreadyExists, "already enabled")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.TOTPEnrollResponse{Secret: response.Secret, Uri: response.URI}, nil
+}
+
+func (s oAuth2ServiceServer) TOTPConfirm(ctx context.Context, in *pb.TOTPConfirmRequest) (*pb.TOTPConfirmResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.TOTPConfirmRequest{AccessToken: in.AccessToken, Code: in.Code}
+	response, err := s.services.OAuth2Service.TOTPConfirmRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrTOTPConfirmInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrTOTPConfirmNotEnrolled) {
+		return nil, status.Error(codes.FailedPrecondition, "not enrolled")
+	} else if errors.Is(err, serviceoauth2.ErrTOTPConfirmAlreadyEnabled) {
+		return nil, status.Error(codes.AlreadyExists, "already enabled")
+	} else if errors.Is(err, serviceoauth2.ErrTOTPConfirmInvalidCode) {
+		return nil, status.Error(codes.InvalidArgument, "invalid code")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.TOTPConfirmResponse{RecoveryCodes: response.RecoveryCodes}, nil
+}
+
+func (s oAuth2ServiceServer) TOTPDisable(ctx context.Context, in *pb.TOTPDisableRequest) (*pb.TOTPDisableResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.TOTPDisableRequest{AccessToken: in.AccessToken, Code: in.Code, IP: peerIP(ctx)}
+	_, err := s.services.OAuth2Service.TOTPDisableRequestHandler.Handle(ctx, request)
+
+	var locked serviceoauth2.SignInLockedError
+	if errors.As(err, &locked) {
+		return nil, resourceExhausted(ctx, "too many failed attempts", locked.RetryAfter)
+	} else if errors.Is(err, serviceoauth2.ErrTOTPDisableInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrTOTPDisableNotEnabled) {
+		return nil, status.Error(codes.FailedPrecondition, "not enabled")
+	} else if errors.Is(err, serviceoauth2.ErrTOTPDisableInvalidCode) {
+		return nil, status.Error(codes.InvalidArgument, "invalid code")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.TOTPDisableResponse{}, nil
+}
+
+func (s oAuth2ServiceServer) ChangePassword(ctx context.Context, in *pb.ChangePasswordRequest) (*pb.ChangePasswordResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.ChangePasswordRequest{
+		AccessToken:     in.AccessToken,
+		RefreshToken:    in.RefreshToken,
+		CurrentPassword: in.CurrentPassword,
+		NewPassword:     in.NewPassword,
+	}
+	_, err := s.services.OAuth2Service.ChangePasswordRequestHandler.Handle(ctx, request)
+
+	var policy serviceoauth2.PasswordPolicyError
+	if errors.As(err, &policy) {
+		return nil, passwordPolicyViolation(policy)
+	} else if errors.Is(err, serviceoauth2.ErrChangePasswordInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrChangePasswordInvalidPassword) {
+		return nil, status.Error(codes.InvalidArgument, "invalid password")
+	} else if errors.Is(err, serviceoauth2.ErrChangePasswordInvalidNewPassword) {
+		return nil, status.Error(codes.InvalidArgument, "invalid new password")
+	} else if errors.Is(err, serviceoauth2.ErrChangePasswordUserNotFound) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.ChangePasswordResponse{}, nil
+}
+
+func (s oAuth2ServiceServer) RequestPasswordReset(ctx context.Context, in *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.RequestPasswordResetRequest{Username: in.GetUsername(), Email: in.GetEmail()}
+	_, err := s.services.OAuth2Service.RequestPasswordResetRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrRequestPasswordResetInvalidUser) {
+		return nil, status.Error(codes.InvalidArgument, "invalid username or email")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.RequestPasswordResetResponse{}, nil
+}
+
+func (s oAuth2ServiceServer) ConfirmPasswordReset(ctx context.Context, in *pb.ConfirmPasswordResetRequest) (*pb.ConfirmPasswordResetResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.ConfirmPasswordResetRequest{Token: in.Token, NewPassword: in.NewPassword}
+	_, err := s.services.OAuth2Service.ConfirmPasswordResetRequestHandler.Handle(ctx, request)
+
+	var policy serviceoauth2.PasswordPolicyError
+	if errors.As(err, &policy) {
+		return nil, passwordPolicyViolation(policy)
+	} else if errors.Is(err, serviceoauth2.ErrConfirmPasswordResetInvalidToken) {
+		return nil, status.Error(codes.InvalidArgument, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrConfirmPasswordResetInvalidNewPassword) {
+		return nil, status.Error(codes.InvalidArgument, "invalid new password")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.ConfirmPasswordResetResponse{}, nil
+}
+
+func (s oAuth2ServiceServer) ChangeEmail(ctx context.Context, in *pb.ChangeEmailRequest) (*pb.ChangeEmailResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.ChangeEmailRequest{AccessToken: in.AccessToken, Password: in.Password, Email: in.Email}
+	_, err := s.services.OAuth2Service.ChangeEmailRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrChangeEmailInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrChangeEmailInvalidPassword) {
+		return nil, status.Error(codes.InvalidArgument, "invalid password")
+	} else if errors.Is(err, serviceoauth2.ErrChangeEmailInvalidEmail) {
+		return nil, status.Error(codes.InvalidArgument, "invalid email")
+	} else if errors.Is(err, serviceoauth2.ErrChangeEmailEmailAlreadyExists) {
+		return nil, status.Error(codes.AlreadyExists, "email already exists")
+	} else if errors.Is(err, serviceoauth2.ErrChangeEmailUserNotFound) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.ChangeEmailResponse{}, nil
+}
+
+func (s oAuth2ServiceServer) VerifyEmail(ctx context.Context, in *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.VerifyEmailRequest{Token: in.Token}
+	_, err := s.services.OAuth2Service.VerifyEmailRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrVerifyEmailInvalidOrExpiredToken) {
+		return nil, status.Error(codes.InvalidArgument, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrVerifyEmailEmailAlreadyExists) {
+		return nil, status.Error(codes.AlreadyExists, "email already exists")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.VerifyEmailResponse{}, nil
+}
+
+func (s oAuth2ServiceServer) ResendVerification(ctx context.Context, in *pb.ResendVerificationRequest) (*pb.ResendVerificationResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.ResendVerificationRequest{AccessToken: in.AccessToken}
+	_, err := s.services.OAuth2Service.ResendVerificationRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrResendVerificationInvalidToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrResendVerificationNoEmail) {
+		return nil, status.Error(codes.FailedPrecondition, "no email")
+	} else if errors.Is(err, serviceoauth2.ErrResendVerificationAlreadyVerified) {
+		return nil, status.Error(codes.FailedPrecondition, "already verified")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.ResendVerificationResponse{}, nil
+}
+
+func (s oAuth2ServiceServer) RegisterClient(ctx context.Context, in *pb.RegisterClientRequest) (*pb.RegisterClientResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.RegisterClientRequest{
+		AccessToken: in.AccessToken,
+
+		Name:         in.Name,
+		RedirectURIs: in.RedirectUris,
+		Scopes:       in.Scopes,
+		GrantTypes:   in.GrantTypes,
+		Public:       in.Public,
+	}
+	response, err := s.services.OAuth2Service.RegisterClientRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrRegisterClientInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrRegisterClientPermissionDenied) {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	} else if errors.Is(err, serviceoauth2.ErrRegisterClientInvalidRedirectURI) {
+		return nil, status.Error(codes.InvalidArgument, "invalid redirect uri")
+	} else if errors.Is(err, serviceoauth2.ErrRegisterClientInvalidScope) {
+		return nil, status.Error(codes.InvalidArgument, "invalid scope")
+	} else if errors.Is(err, serviceoauth2.ErrRegisterClientInvalidGrantType) {
+		return nil, status.Error(codes.InvalidArgument, "invalid grant type")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.RegisterClientResponse{ClientId: response.ClientID, ClientSecret: response.ClientSecret}, nil
+}
+
+func (s oAuth2ServiceServer) Introspect(ctx context.Context, in *pb.IntrospectRequest) (*pb.IntrospectResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.IntrospectRequest{
+		ClientID:     in.ClientId,
+		ClientSecret: in.ClientSecret,
+
+		Token:         in.Token,
+		TokenTypeHint: in.TokenTypeHint,
+	}
+	response, err := s.services.OAuth2Service.IntrospectRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrIntrospectInvalidClient) {
+		return nil, status.Error(codes.Unauthenticated, "invalid client")
+	} else if errors.Is(err, serviceoauth2.ErrIntrospectInvalidRequest) {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+
+	out := &pb.IntrospectResponse{
+		Active:        response.Active,
+		TokenType:     response.TokenType,
+		Jti:           response.TokenID,
+		Sub:           response.Subject,
+		ClientId:      response.ClientID,
+		Scope:         response.Scope,
+		SessionId:     response.SessionID,
+		EmailVerified: response.EmailVerified,
+		Role:          response.Role,
+	}
+	if !response.ExpiresAt.IsZero() {
+		out.Exp = response.ExpiresAt.Unix()
+	}
+	return out, nil
+}
+
+func (s oAuth2ServiceServer) Revoke(ctx context.Context, in *pb.RevokeRequest) (*pb.RevokeResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.RevokeRequest{
+		ClientID:     in.ClientId,
+		ClientSecret: in.ClientSecret,
+
+		Token:         in.Token,
+		TokenTypeHint: in.TokenTypeHint,
+	}
+	_, err := s.services.OAuth2Service.RevokeRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrRevokeInvalidClient) {
+		return nil, status.Error(codes.Unauthenticated, "invalid client")
+	} else if errors.Is(err, serviceoauth2.ErrRevokeInvalidRequest) {
+		return nil, status.Error(codes.InvalidArgument, "invalid request")
+	} else if errors.Is(err, serviceoauth2.ErrRevokeUnauthorizedClient) {
+		return nil, status.Error(codes.PermissionDenied, "unauthorized client")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.RevokeResponse{}, nil
+}
+
+func (s oAuth2ServiceServer) GetProfile(ctx context.Context, in *pb.GetProfileRequest) (*pb.GetProfileResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.GetProfileRequest{AccessToken: in.AccessToken}
+	response, err := s.services.OAuth2Service.GetProfileRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrGetProfileInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrGetProfileUserNotFound) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.GetProfileResponse{Profile: newProfile(response.UserProfile)}, nil
+}
+
+func (s oAuth2ServiceServer) UpdateProfile(ctx context.Context, in *pb.UpdateProfileRequest) (*pb.UpdateProfileResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.UpdateProfileRequest{
+		AccessToken: in.AccessToken,
+		DisplayName: in.DisplayName,
+		Locale:      in.Locale,
+		Timezone:    in.Timezone,
+	}
+	response, err := s.services.OAuth2Service.UpdateProfileRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrUpdateProfileInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrUpdateProfileInvalidDisplayName) {
+		return nil, status.Error(codes.InvalidArgument, "invalid display name")
+	} else if errors.Is(err, serviceoauth2.ErrUpdateProfileInvalidLocale) {
+		return nil, status.Error(codes.InvalidArgument, "invalid locale")
+	} else if errors.Is(err, serviceoauth2.ErrUpdateProfileInvalidTimezone) {
+		return nil, status.Error(codes.InvalidArgument, "invalid timezone")
+	} else if errors.Is(err, serviceoauth2.ErrUpdateProfileUserNotFound) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.UpdateProfileResponse{Profile: newProfile(response.UserProfile)}, nil
+}
+
+func (s oAuth2ServiceServer) UploadAvatar(ctx context.Context, in *pb.UploadAvatarRequest) (*pb.UploadAvatarResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.UploadAvatarRequest{AccessToken: in.AccessToken, Data: in.Avatar}
+	response, err := s.services.OAuth2Service.UploadAvatarRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrUploadAvatarInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrUploadAvatarTooLarge) {
+		return nil, status.Error(codes.InvalidArgument, "avatar is too large")
+	} else if errors.Is(err, serviceoauth2.ErrUploadAvatarUnsupportedType) {
+		return nil, status.Error(codes.InvalidArgument, "unsupported avatar type")
+	} else if errors.Is(err, serviceoauth2.ErrUploadAvatarUserNotFound) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.UploadAvatarResponse{Profile: newProfile(response.UserProfile)}, nil
+}
+
+func (s oAuth2ServiceServer) GetUsers(ctx context.Context, in *pb.GetUsersRequest) (*pb.GetUsersResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.GetUsersRequest{
+		ClientID:     in.ClientId,
+		ClientSecret: in.ClientSecret,
+
+		UserIDs: in.UserIds,
+	}
+	response, err := s.services.OAuth2Service.GetUsersRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrGetUsersInvalidClient) {
+		return nil, status.Error(codes.Unauthenticated, "invalid client")
+	} else if errors.Is(err, serviceoauth2.ErrGetUsersTooManyUserIDs) {
+		return nil, status.Error(codes.InvalidArgument, "too many user ids")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+
+	users := make([]*pb.Profile, 0, len(response.Users))
+	for _, user := range response.Users {
+		users = append(users, newProfile(user))
+	}
+	return &pb.GetUsersResponse{Users: users, DeletedUserIds: response.DeletedUserIDs}, nil
+}
+
+func newProfile(profile serviceoauth2.UserProfile) *pb.Profile {
+	return &pb.Profile{
+		UserId:      profile.UserID,
+		Username:    profile.Username,
+		DisplayName: profile.DisplayName,
+		AvatarUrl:   profile.AvatarURL,
+		Locale:      profile.Locale,
+		Timezone:    profile.Timezone,
+	}
+}
+
+func (s oAuth2ServiceServer) DeleteAccount(ctx context.Context, in *pb.DeleteAccountRequest) (*pb.DeleteAccountResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.DeleteAccountRequest{AccessToken: in.AccessToken, Password: in.Password}
+	_, err := s.services.OAuth2Service.DeleteAccountRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrDeleteAccountInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrDeleteAccountInvalidPassword) {
+		return nil, status.Error(codes.InvalidArgument, "invalid password")
+	} else if errors.Is(err, serviceoauth2.ErrDeleteAccountUserNotFound) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.DeleteAccountResponse{}, nil
+}
+
+func (s oAuth2ServiceServer) CreatePersonalAccessToken(
+	ctx context.Context, in *pb.CreatePersonalAccessTokenRequest,
+) (*pb.CreatePersonalAccessTokenResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.CreatePersonalAccessTokenRequest{
+		AccessToken: in.AccessToken,
+		Name:        in.Name,
+		Scopes:      in.Scopes,
+		ExpiresIn:   time.Duration(in.ExpiresIn) * time.Second,
+	}
+	response, err := s.services.OAuth2Service.CreatePersonalAccessTokenRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrCreatePersonalAccessTokenInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrCreatePersonalAccessTokenInvalidName) {
+		return nil, status.Error(codes.InvalidArgument, "invalid name")
+	} else if errors.Is(err, serviceoauth2.ErrCreatePersonalAccessTokenInvalidScope) {
+		return nil, status.Error(codes.InvalidArgument, "invalid scope")
+	} else if errors.Is(err, serviceoauth2.ErrCreatePersonalAccessTokenInvalidExpiresIn) {
+		return nil, status.Error(codes.InvalidArgument, "invalid expires in")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+
+	return &pb.CreatePersonalAccessTokenResponse{
+		PersonalAccessToken: newPersonalAccessToken(response.PersonalAccessToken),
+		Token:               response.Token,
+	}, nil
+}
+
+func (s oAuth2ServiceServer) ListPersonalAccessTokens(
+	ctx context.Context, in *pb.ListPersonalAccessTokensRequest,
+) (*pb.ListPersonalAccessTokensResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.ListPersonalAccessTokensRequest{AccessToken: in.AccessToken}
+	response, err := s.services.OAuth2Service.ListPersonalAccessTokensRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrListPersonalAccessTokensInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+
+	out := &pb.ListPersonalAccessTokensResponse{
+		PersonalAccessTokens: make([]*pb.PersonalAccessToken, 0, len(response.Tokens)),
+	}
+	for _, token := range response.Tokens {
+		out.PersonalAccessTokens = append(out.PersonalAccessTokens, newPersonalAccessToken(token))
+	}
+	return out, nil
+}
+
+func (s oAuth2ServiceServer) RevokePersonalAccessToken(
+	ctx context.Context, in *pb.RevokePersonalAccessTokenRequest,
+) (*pb.RevokePersonalAccessTokenResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.RevokePersonalAccessTokenRequest{AccessToken: in.AccessToken, ID: in.Id}
+	_, err := s.services.OAuth2Service.RevokePersonalAccessTokenRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrRevokePersonalAccessTokenInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrRevokePersonalAccessTokenNotFound) {
+		return nil, status.Error(codes.NotFound, "personal access token not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.RevokePersonalAccessTokenResponse{}, nil
+}
+
+func (s oAuth2ServiceServer) ListSecurityEvents(
+	ctx context.Context, in *pb.ListSecurityEventsRequest,
+) (*pb.ListSecurityEventsResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.ListSecurityEventsRequest{
+		AccessToken: in.AccessToken,
+		Limit:       int(in.Limit),
+	}
+	if in.Before != 0 {
+		request.Before = time.Unix(in.Before, 0)
+	}
+	response, err := s.services.OAuth2Service.ListSecurityEventsRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrListSecurityEventsInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrListSecurityEventsInvalidLimit) {
+		return nil, status.Error(codes.InvalidArgument, "invalid limit")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.ListSecurityEventsResponse{Events: newSecurityEvents(response.Events)}, nil
+}
+
+func (s oAuth2ServiceServer) ListAuditEvents(
+	ctx context.Context, in *pb.ListAuditEventsRequest,
+) (*pb.ListAuditEventsResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.ListAuditEventsRequest{
+		AccessToken: in.AccessToken,
+		UserID:      in.UserId,
+		Type:        in.Type,
+		Outcome:     in.Outcome,
+		IP:          in.Ip,
+		Limit:       int(in.Limit),
+	}
+	if in.Since != 0 {
+		request.Since = time.Unix(in.Since, 0)
+	}
+	if in.Until != 0 {
+		request.Until = time.Unix(in.Until, 0)
+	}
+	response, err := s.services.OAuth2Service.ListAuditEventsRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrListAuditEventsInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrListAuditEventsPermissionDenied) {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	} else if errors.Is(err, serviceoauth2.ErrListAuditEventsInvalidLimit) {
+		return nil, status.Error(codes.InvalidArgument, "invalid limit")
+	} else if errors.Is(err, serviceoauth2.ErrListAuditEventsInvalidOutcome) {
+		return nil, status.Error(codes.InvalidArgument, "invalid outcome")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.ListAuditEventsResponse{Events: newSecurityEvents(response.Events)}, nil
+}
+
+func (s oAuth2ServiceServer) ListUsers(ctx context.Context, in *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.ListUsersRequest{
+		AccessToken: in.AccessToken,
+		Query:       in.Query,
+		After:       in.After,
+		Limit:       int(in.Limit),
+	}
+	response, err := s.services.OAuth2Service.ListUsersRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrListUsersInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrListUsersPermissionDenied) {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	} else if errors.Is(err, serviceoauth2.ErrListUsersInvalidLimit) {
+		return nil, status.Error(codes.InvalidArgument, "invalid limit")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+
+	users := make([]*pb.AdministeredUser, 0, len(response.Users))
+	for _, user := range response.Users {
+		users = append(users, &pb.AdministeredUser{
+			Id:            user.ID,
+			Username:      user.Username,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			Role:          user.Role,
+			Disabled:      user.Disabled,
+		})
+	}
+	return &pb.ListUsersResponse{Users: users, Next: response.Next}, nil
+}
+
+func (s oAuth2ServiceServer) ListUserSessions(
+	ctx context.Context, in *pb.ListUserSessionsRequest,
+) (*pb.ListUserSessionsResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.ListUserSessionsRequest{AccessToken: in.AccessToken, UserID: in.UserId}
+	response, err := s.services.OAuth2Service.ListUserSessionsRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrListUserSessionsInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrListUserSessionsPermissionDenied) {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	} else if errors.Is(err, serviceoauth2.ErrListUserSessionsUserNotFound) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+
+	sessions := make([]*pb.UserSession, 0, len(response.Sessions))
+	for _, session := range response.Sessions {
+		sessions = append(sessions, &pb.UserSession{
+			SessionId: session.SessionID,
+			ClientId:  session.ClientID,
+			Scope:     session.Scope,
+			ExpiresAt: session.ExpiresAt.Unix(),
+		})
+	}
+	return &pb.ListUserSessionsResponse{Sessions: sessions}, nil
+}
+
+func (s oAuth2ServiceServer) SetUserDisabled(
+	ctx context.Context, in *pb.SetUserDisabledRequest,
+) (*pb.SetUserDisabledResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.SetUserDisabledRequest{
+		AccessToken: in.AccessToken,
+		UserID:      in.UserId,
+		Disabled:    in.Disabled,
+	}
+	_, err := s.services.OAuth2Service.SetUserDisabledRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrSetUserDisabledInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrSetUserDisabledPermissionDenied) {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	} else if errors.Is(err, serviceoauth2.ErrSetUserDisabledUserNotFound) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	} else if errors.Is(err, serviceoauth2.ErrSetUserDisabledSelf) {
+		return nil, status.Error(codes.FailedPrecondition, "can't disable yourself")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.SetUserDisabledResponse{}, nil
+}
+
+func (s oAuth2ServiceServer) ForcePasswordReset(
+	ctx context.Context, in *pb.ForcePasswordResetRequest,
+) (*pb.ForcePasswordResetResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.ForcePasswordResetRequest{AccessToken: in.AccessToken, UserID: in.UserId}
+	_, err := s.services.OAuth2Service.ForcePasswordResetRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrForcePasswordResetInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrForcePasswordResetPermissionDenied) {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	} else if errors.Is(err, serviceoauth2.ErrForcePasswordResetUserNotFound) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	} else if errors.Is(err, serviceoauth2.ErrForcePasswordResetNoVerifiedEmail) {
+		return nil, status.Error(codes.FailedPrecondition, "user has no verified email")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.ForcePasswordResetResponse{}, nil
+}
+
+func (s oAuth2ServiceServer) RevokeUserSessions(
+	ctx context.Context, in *pb.RevokeUserSessionsRequest,
+) (*pb.RevokeUserSessionsResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.RevokeUserSessionsRequest{AccessToken: in.AccessToken, UserID: in.UserId}
+	_, err := s.services.OAuth2Service.RevokeUserSessionsRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrRevokeUserSessionsInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrRevokeUserSessionsPermissionDenied) {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	} else if errors.Is(err, serviceoauth2.ErrRevokeUserSessionsUserNotFound) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.RevokeUserSessionsResponse{}, nil
+}
+
+func (s oAuth2ServiceServer) SetUserRole(
+	ctx context.Context, in *pb.SetUserRoleRequest,
+) (*pb.SetUserRoleResponse, error) {
+	if err := in.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	request := serviceoauth2.SetUserRoleRequest{AccessToken: in.AccessToken, UserID: in.UserId, Role: in.Role}
+	_, err := s.services.OAuth2Service.SetUserRoleRequestHandler.Handle(ctx, request)
+	if errors.Is(err, serviceoauth2.ErrSetUserRoleInvalidOrExpiredToken) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	} else if errors.Is(err, serviceoauth2.ErrSetUserRolePermissionDenied) {
+		return nil, status.Error(codes.PermissionDenied, "permission denied")
+	} else if errors.Is(err, serviceoauth2.ErrSetUserRoleUserNotFound) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	} else if errors.Is(err, serviceoauth2.ErrSetUserRoleInvalidRole) {
+		return nil, status.Error(codes.InvalidArgument, "invalid role")
+	} else if errors.Is(err, serviceoauth2.ErrSetUserRoleSelf) {
+		return nil, status.Error(codes.FailedPrecondition, "can't change your own role")
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "internal")
+	}
+	return &pb.SetUserRoleResponse{}, nil
+}
+
+// newSecurityEvents converts security events, with times as Unix timestamps.
+func newSecurityEvents(events []serviceoauth2.SecurityEvent) []*pb.SecurityEvent {
+	out := make([]*pb.SecurityEvent, 0, len(events))
+	for _, event := range events {
+		out = append(out, &pb.SecurityEvent{
+			Id:        event.ID,
+			Type:      event.Type,
+			UserId:    event.UserID,
+			Ip:        event.IP,
+			UserAgent: event.UserAgent,
+			SessionId: event.SessionID,
+			Outcome:   event.Outcome,
+			Reason:    event.Reason,
+			CreatedAt: event.CreatedAt.Unix(),
+		})
+	}
+	return out
+}
+
+// newPersonalAccessToken converts a personal access token, with times as Unix timestamps that are 0 when not set.
+func newPersonalAccessToken(token serviceoauth2.PersonalAccessToken) *pb.PersonalAccessToken {
+	out := &pb.PersonalAccessToken{
+		Id:        token.ID,
+		Name:      token.Name,
+		Scopes:    token.Scopes,
+		CreatedAt: token.CreatedAt.Unix(),
+	}
+	if token.ExpiresAt != nil {
+		out.ExpiresAt = token.ExpiresAt.Unix()
+	}
+	if token.LastUsedAt != nil {
+		out.LastUsedAt = token.LastUsedAt.Unix()
+	}
+	return out
+}
@@ -1,6 +1,9 @@
 package grpc
 
-import "github.com/nazarslota/unotes/auth/internal/service"
+import (
+	"github.com/nazarslota/unotes/auth/internal/service"
+	"github.com/nazarslota/unotes/auth/pkg/ratelimit"
+)
 
 type HandlerOption func(h *Handler)
 
@@ -21,3 +24,9 @@ func WithLogger(logger Logger) HandlerOption {
 		h.logger = logger
 	}
 }
+
+func WithRateLimiter(limiter *ratelimit.Limiter) HandlerOption {
+	return func(h *Handler) {
+		h.rateLimiter = limiter
+	}
+}
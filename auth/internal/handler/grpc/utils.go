@@ -2,9 +2,20 @@ package grpc
 
 import (
 	"context"
+	"math"
+	"net"
+	"strconv"
 	"time"
 
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+	"github.com/nazarslota/unotes/auth/pkg/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 type Logger interface {
@@ -40,3 +51,81 @@ func newGRPCLoggerStreamInterceptor(logger Logger) grpc.StreamServerInterceptor
 		return err
 	}
 }
+
+// newGRPCRateLimiterUnaryInterceptor limits calls of the given methods per client IP address and method. If limiter
+// is nil, calls are not limited.
+func newGRPCRateLimiterUnaryInterceptor(limiter *ratelimit.Limiter, methods ...string) grpc.UnaryServerInterceptor {
+	limited := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		limited[method] = true
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if limiter == nil || !limited[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		if !limiter.Allow(peerIP(ctx) + " " + info.FullMethod) {
+			return nil, status.Error(codes.ResourceExhausted, "too many requests")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// newGRPCRemoteUnaryInterceptor puts the client IP address and user agent of the call into its context, where the
+// request handlers of the service record them in the audit log.
+func newGRPCRemoteUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		remote := serviceoauth2.Remote{IP: peerIP(ctx)}
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if userAgent := md.Get("user-agent"); len(userAgent) != 0 {
+				remote.UserAgent = userAgent[0]
+			}
+		}
+		return handler(serviceoauth2.ContextWithRemote(ctx, remote), req)
+	}
+}
+
+// peerIP returns the IP address of the client, or an empty string if it is unknown.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	ip := p.Addr.String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return ip
+}
+
+// resourceExhausted returns a ResourceExhausted status error that tells the client when to retry, both as a RetryInfo
+// detail and as a "retry-after" header in seconds.
+func resourceExhausted(ctx context.Context, msg string, retryAfter time.Duration) error {
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))))
+
+	st := status.New(codes.ResourceExhausted, msg)
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// passwordPolicyViolation returns an invalid argument error with the violations of the password policy as field
+// violations of its bad request details.
+func passwordPolicyViolation(policy serviceoauth2.PasswordPolicyError) error {
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(policy.Violations))
+	for _, violation := range policy.Violations {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Description: violation.Description,
+		})
+	}
+
+	st := status.New(codes.InvalidArgument, "password violates the password policy")
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
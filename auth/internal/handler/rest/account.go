@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+)
+
+type oAuth2DeleteAccountModel struct {
+	Password string `json:"password" validate:"required,max=64" example:"password"`
+}
+
+// @Summary		oAuth2 Delete Account
+// @Description	Delete the account of the signed-in user, requires the password. All sessions are revoked and the
+// @Description	data of the user is deleted by the other services as well.
+// @Tags			oAuth2
+// @Accept			json
+// @Produce		json
+// @Param			Authorization	header	string						true	"Bearer access token"
+// @Param			input			body	oAuth2DeleteAccountModel	true	"Password"
+// @Success		204
+// @Failure		400		{object}	errors.HTTPError
+// @Failure		401		{object}	errors.HTTPError
+// @Failure		404		{object}	errors.HTTPError
+// @Failure		429		{object}	errors.HTTPError
+// @Failure		500		{object}	errors.HTTPError
+// @Failure		default	{object}	errors.HTTPError
+// @Router			/oauth2/account/delete [post]
+func (h *Handler) oAuth2DeleteAccount(c echo.Context) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	input := new(oAuth2DeleteAccountModel)
+	if err := c.Bind(input); err != nil {
+		return err
+	}
+
+	if err := c.Validate(input); err != nil {
+		return err
+	}
+
+	request := serviceoauth2.DeleteAccountRequest{AccessToken: accessToken, Password: input.Password}
+	_, err = h.services.OAuth2Service.DeleteAccountRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrDeleteAccountInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrDeleteAccountInvalidPassword) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid password").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrDeleteAccountUserNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "user not found").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
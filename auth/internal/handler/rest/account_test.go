@@ -0,0 +1,109 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/nazarslota/unotes/auth/api/events"
+	domainblob "github.com/nazarslota/unotes/auth/internal/domain/blob"
+	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/internal/service"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/nazarslota/unotes/auth/pkg/logger"
+	"github.com/nazarslota/unotes/auth/pkg/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestDeleteAccount deletes an account and checks that its sessions are gone and the deletion is in the outbox.
+func TestDeleteAccount(t *testing.T) {
+	passwordHasher, err := password.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+	passwordHash, err := passwordHasher.Hash("password")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	store := newMemoryStore(t)
+	user := domainuser.User{ID: "user-id", Username: "username", PasswordHash: passwordHash}
+	require.NoError(t, store.SaveUser(ctx, user))
+	require.NoError(t, store.UpdateProfile(ctx, domainuser.Profile{UserID: "user-id", Avatar: "user-id-avatar"}))
+	require.NoError(t, store.SaveBlob(ctx, "user-id-avatar", domainblob.Blob{Data: []byte("avatar")}))
+	require.NoError(t, store.SaveRefreshToken(ctx, "user-id", "refresh-token"))
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenParser: accessTokenManager,
+		PasswordHasher:    passwordHasher,
+
+		RefreshTokensDeleter: store,
+		RefreshTokenGetter:   store,
+		RevokedTokenSaver:    store,
+
+		UserFinder:    store,
+		UserDeleter:   store,
+		ProfileFinder: store,
+		BlobDeleter:   store,
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	tokenID := uuid.New().String()
+	accessToken, err := accessTokenManager.New(jwt.AccessTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{
+			ID:        tokenID,
+			ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		UserID: "user-id",
+	})
+	require.NoError(t, err)
+
+	deleteAccount := func(t *testing.T, password string) int {
+		body := `{"password":"` + password + `"}`
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/account/delete", strings.NewReader(body))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		request.Header.Set(echo.HeaderAuthorization, "Bearer "+accessToken)
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, deleteAccount(t, "wrong-password"))
+	_, err = store.FindUserByUserID(ctx, "user-id")
+	assert.NoError(t, err)
+	messages, err := store.FindOutboxMessages(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	assert.Equal(t, http.StatusNoContent, deleteAccount(t, "password"))
+	_, err = store.FindUserByUserID(ctx, "user-id")
+	assert.ErrorIs(t, err, domainuser.ErrUserNotFound)
+	_, err = store.FindBlob(ctx, "user-id-avatar")
+	assert.ErrorIs(t, err, domainblob.ErrBlobNotFound)
+	_, err = store.GetRefreshTokens(ctx, "user-id")
+	assert.ErrorIs(t, err, domainrefresh.ErrTokenNotFound)
+	revoked, err := store.IsTokenRevoked(ctx, tokenID)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	messages, err = store.FindOutboxMessages(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, events.SubjectUserDeleted, messages[0].Subject)
+
+	var event events.UserDeleted
+	require.NoError(t, json.Unmarshal(messages[0].Payload, &event))
+	assert.Equal(t, "user-id", event.UserID)
+
+	assert.Equal(t, http.StatusNotFound, deleteAccount(t, "password"))
+}
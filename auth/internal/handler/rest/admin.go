@@ -0,0 +1,335 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+)
+
+type oAuth2AdministeredUserResult struct {
+	ID            string `json:"id" example:"8e6b9e26-8a5b-4bb8-9e0e-54b4d6a4e6f1"`
+	Username      string `json:"username" example:"username"`
+	Email         string `json:"email,omitempty" example:"user@example.com"`
+	EmailVerified bool   `json:"email_verified" example:"true"`
+	Role          string `json:"role" example:"user"`
+	Disabled      bool   `json:"disabled" example:"false"`
+}
+
+type oAuth2ListUsersResult struct {
+	Users []oAuth2AdministeredUserResult `json:"users"`
+	Next  string                         `json:"next,omitempty" example:"username"`
+}
+
+type oAuth2ListUsersModel struct {
+	Query string `query:"query" example:"example.com"`
+	After string `query:"after" example:"username"`
+	Limit int    `query:"limit" validate:"min=0" example:"50"`
+}
+
+// @Summary		oAuth2 List Users
+// @Description	List the users whose username or email contains the query, ignoring case, ordered by username. Pass
+// @Description	the next of a page as after to get the next page. Admins only.
+// @Tags			oAuth2
+// @Produce		json
+// @Param			Authorization	header		string	true	"Bearer access token"
+// @Param			query			query		string	false	"Part of the username or email"
+// @Param			after			query		string	false	"Username to list the users after"
+// @Param			limit			query		int		false	"Maximum number of users, 50 by default"
+// @Success		200				{object}	oAuth2ListUsersResult
+// @Failure		400				{object}	errors.HTTPError
+// @Failure		401				{object}	errors.HTTPError
+// @Failure		403				{object}	errors.HTTPError
+// @Failure		500				{object}	errors.HTTPError
+// @Failure		default			{object}	errors.HTTPError
+// @Router			/oauth2/admin/users [get]
+func (h *Handler) oAuth2ListUsers(c echo.Context) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	input := new(oAuth2ListUsersModel)
+	if err := c.Bind(input); err != nil {
+		return err
+	}
+
+	if err := c.Validate(input); err != nil {
+		return err
+	}
+
+	request := serviceoauth2.ListUsersRequest{
+		AccessToken: accessToken,
+		Query:       input.Query,
+		After:       input.After,
+		Limit:       input.Limit,
+	}
+	response, err := h.services.OAuth2Service.ListUsersRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrListUsersInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrListUsersPermissionDenied) {
+		return echo.NewHTTPError(http.StatusForbidden, "permission denied").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrListUsersInvalidLimit) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid limit").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+
+	result := oAuth2ListUsersResult{
+		Users: make([]oAuth2AdministeredUserResult, 0, len(response.Users)),
+		Next:  response.Next,
+	}
+	for _, user := range response.Users {
+		result.Users = append(result.Users, oAuth2AdministeredUserResult{
+			ID:            user.ID,
+			Username:      user.Username,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			Role:          user.Role,
+			Disabled:      user.Disabled,
+		})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+type oAuth2UserSessionResult struct {
+	SessionID string    `json:"session_id,omitempty" example:"5b0e2c1a-7d3f-4e8b-9a6c-1f2e3d4c5b6a"`
+	ClientID  string    `json:"client_id,omitempty" example:"2f1e0d9c-8b7a-4c6d-9e5f-4a3b2c1d0e9f"`
+	Scope     string    `json:"scope,omitempty" example:"notes:read"`
+	ExpiresAt time.Time `json:"expires_at" example:"2023-05-01T12:00:00Z"`
+}
+
+type oAuth2ListUserSessionsResult struct {
+	Sessions []oAuth2UserSessionResult `json:"sessions"`
+}
+
+// @Summary		oAuth2 List User Sessions
+// @Description	List the sessions of a user, one for every refresh token they hold, the one that expires last first.
+// @Description	Admins only.
+// @Tags			oAuth2
+// @Produce		json
+// @Param			Authorization	header		string	true	"Bearer access token"
+// @Param			id				path		string	true	"User ID"
+// @Success		200				{object}	oAuth2ListUserSessionsResult
+// @Failure		401				{object}	errors.HTTPError
+// @Failure		403				{object}	errors.HTTPError
+// @Failure		404				{object}	errors.HTTPError
+// @Failure		500				{object}	errors.HTTPError
+// @Failure		default			{object}	errors.HTTPError
+// @Router			/oauth2/admin/users/{id}/sessions [get]
+func (h *Handler) oAuth2ListUserSessions(c echo.Context) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	request := serviceoauth2.ListUserSessionsRequest{AccessToken: accessToken, UserID: c.Param("id")}
+	response, err := h.services.OAuth2Service.ListUserSessionsRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrListUserSessionsInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrListUserSessionsPermissionDenied) {
+		return echo.NewHTTPError(http.StatusForbidden, "permission denied").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrListUserSessionsUserNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "user not found").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+
+	result := oAuth2ListUserSessionsResult{Sessions: make([]oAuth2UserSessionResult, 0, len(response.Sessions))}
+	for _, session := range response.Sessions {
+		result.Sessions = append(result.Sessions, oAuth2UserSessionResult{
+			SessionID: session.SessionID,
+			ClientID:  session.ClientID,
+			Scope:     session.Scope,
+			ExpiresAt: session.ExpiresAt,
+		})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// @Summary		oAuth2 Revoke User Sessions
+// @Description	Sign a user out of all their sessions. Access tokens already issued to them stay valid until they
+// @Description	expire. Admins only.
+// @Tags			oAuth2
+// @Produce		json
+// @Param			Authorization	header	string	true	"Bearer access token"
+// @Param			id				path	string	true	"User ID"
+// @Success		204
+// @Failure		401		{object}	errors.HTTPError
+// @Failure		403		{object}	errors.HTTPError
+// @Failure		404		{object}	errors.HTTPError
+// @Failure		500		{object}	errors.HTTPError
+// @Failure		default	{object}	errors.HTTPError
+// @Router			/oauth2/admin/users/{id}/sessions [delete]
+func (h *Handler) oAuth2RevokeUserSessions(c echo.Context) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	request := serviceoauth2.RevokeUserSessionsRequest{AccessToken: accessToken, UserID: c.Param("id")}
+	_, err = h.services.OAuth2Service.RevokeUserSessionsRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrRevokeUserSessionsInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrRevokeUserSessionsPermissionDenied) {
+		return echo.NewHTTPError(http.StatusForbidden, "permission denied").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrRevokeUserSessionsUserNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "user not found").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @Summary		oAuth2 Disable User
+// @Description	Disable a user and sign them out of all their sessions. They can't sign in or refresh their tokens
+// @Description	until they are enabled again, access tokens already issued to them stay valid until they expire.
+// @Description	Admins only, who can't disable themselves.
+// @Tags			oAuth2
+// @Produce		json
+// @Param			Authorization	header	string	true	"Bearer access token"
+// @Param			id				path	string	true	"User ID"
+// @Success		204
+// @Failure		401		{object}	errors.HTTPError
+// @Failure		403		{object}	errors.HTTPError
+// @Failure		404		{object}	errors.HTTPError
+// @Failure		409		{object}	errors.HTTPError
+// @Failure		500		{object}	errors.HTTPError
+// @Failure		default	{object}	errors.HTTPError
+// @Router			/oauth2/admin/users/{id}/disable [post]
+func (h *Handler) oAuth2DisableUser(c echo.Context) error {
+	return h.setUserDisabled(c, true)
+}
+
+// @Summary		oAuth2 Enable User
+// @Description	Enable a disabled user, so that they can sign in again. Admins only.
+// @Tags			oAuth2
+// @Produce		json
+// @Param			Authorization	header	string	true	"Bearer access token"
+// @Param			id				path	string	true	"User ID"
+// @Success		204
+// @Failure		401		{object}	errors.HTTPError
+// @Failure		403		{object}	errors.HTTPError
+// @Failure		404		{object}	errors.HTTPError
+// @Failure		500		{object}	errors.HTTPError
+// @Failure		default	{object}	errors.HTTPError
+// @Router			/oauth2/admin/users/{id}/enable [post]
+func (h *Handler) oAuth2EnableUser(c echo.Context) error {
+	return h.setUserDisabled(c, false)
+}
+
+func (h *Handler) setUserDisabled(c echo.Context, disabled bool) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	request := serviceoauth2.SetUserDisabledRequest{AccessToken: accessToken, UserID: c.Param("id"), Disabled: disabled}
+	_, err = h.services.OAuth2Service.SetUserDisabledRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrSetUserDisabledInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrSetUserDisabledPermissionDenied) {
+		return echo.NewHTTPError(http.StatusForbidden, "permission denied").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrSetUserDisabledUserNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "user not found").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrSetUserDisabledSelf) {
+		return echo.NewHTTPError(http.StatusConflict, "can't disable yourself").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @Summary		oAuth2 Force Password Reset
+// @Description	Replace the password of a user by a random one, sign them out of all their sessions and mail a
+// @Description	password reset link to their verified email. Admins only.
+// @Tags			oAuth2
+// @Produce		json
+// @Param			Authorization	header	string	true	"Bearer access token"
+// @Param			id				path	string	true	"User ID"
+// @Success		204
+// @Failure		401		{object}	errors.HTTPError
+// @Failure		403		{object}	errors.HTTPError
+// @Failure		404		{object}	errors.HTTPError
+// @Failure		409		{object}	errors.HTTPError
+// @Failure		500		{object}	errors.HTTPError
+// @Failure		default	{object}	errors.HTTPError
+// @Router			/oauth2/admin/users/{id}/password-reset [post]
+func (h *Handler) oAuth2ForcePasswordReset(c echo.Context) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	request := serviceoauth2.ForcePasswordResetRequest{AccessToken: accessToken, UserID: c.Param("id")}
+	_, err = h.services.OAuth2Service.ForcePasswordResetRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrForcePasswordResetInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrForcePasswordResetPermissionDenied) {
+		return echo.NewHTTPError(http.StatusForbidden, "permission denied").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrForcePasswordResetUserNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "user not found").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrForcePasswordResetNoVerifiedEmail) {
+		return echo.NewHTTPError(http.StatusConflict, "user has no verified email").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+type oAuth2SetUserRoleModel struct {
+	Role string `json:"role" validate:"required,oneof=user admin" example:"admin"`
+}
+
+// @Summary		oAuth2 Set User Role
+// @Description	Assign the user or the admin role to a user, which they get once their access token is refreshed.
+// @Description	Admins only, who can't change their own role.
+// @Tags			oAuth2
+// @Accept			json
+// @Produce		json
+// @Param			Authorization	header	string					true	"Bearer access token"
+// @Param			id				path	string					true	"User ID"
+// @Param			input			body	oAuth2SetUserRoleModel	true	"Role"
+// @Success		204
+// @Failure		400		{object}	errors.HTTPError
+// @Failure		401		{object}	errors.HTTPError
+// @Failure		403		{object}	errors.HTTPError
+// @Failure		404		{object}	errors.HTTPError
+// @Failure		409		{object}	errors.HTTPError
+// @Failure		500		{object}	errors.HTTPError
+// @Failure		default	{object}	errors.HTTPError
+// @Router			/oauth2/admin/users/{id}/role [put]
+func (h *Handler) oAuth2SetUserRole(c echo.Context) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	input := new(oAuth2SetUserRoleModel)
+	if err := c.Bind(input); err != nil {
+		return err
+	}
+
+	if err := c.Validate(input); err != nil {
+		return err
+	}
+
+	request := serviceoauth2.SetUserRoleRequest{AccessToken: accessToken, UserID: c.Param("id"), Role: input.Role}
+	_, err = h.services.OAuth2Service.SetUserRoleRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrSetUserRoleInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrSetUserRolePermissionDenied) {
+		return echo.NewHTTPError(http.StatusForbidden, "permission denied").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrSetUserRoleUserNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "user not found").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrSetUserRoleInvalidRole) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid role").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrSetUserRoleSelf) {
+		return echo.NewHTTPError(http.StatusConflict, "can't change your own role").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,268 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	domainclient "github.com/nazarslota/unotes/auth/internal/domain/client"
+	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/internal/mailer"
+	"github.com/nazarslota/unotes/auth/internal/service"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/nazarslota/unotes/auth/pkg/logger"
+	"github.com/nazarslota/unotes/auth/pkg/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestAdmin administers users as an admin: lists them, their sessions, disables them, resets their passwords and
+// assigns them roles.
+func TestAdmin(t *testing.T) {
+	const (
+		adminID = "0c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+		userID  = "1d2e3f4a-5b6c-4d7e-9f8a-0b1c2d3e4f5a"
+		otherID = "2e3f4a5b-6c7d-4e8f-8a9b-1c2d3e4f5a6b"
+	)
+
+	passwordHasher, err := password.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+	passwordHash, err := passwordHasher.Hash("password")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveUser(ctx, domainuser.User{ID: adminID, Username: "admin", Role: domainuser.RoleAdmin}))
+	require.NoError(t, store.SaveUser(ctx, domainuser.User{
+		ID: userID, Username: "username", PasswordHash: passwordHash, Email: "username@example.com", EmailVerified: true,
+	}))
+	require.NoError(t, store.SaveUser(ctx, domainuser.User{ID: otherID, Username: "other", Role: domainuser.RoleUser}))
+	require.NoError(t, store.SaveClient(ctx, domainclient.Client{
+		ID: "client", GrantTypes: []string{serviceoauth2.GrantTypePassword}, Public: true,
+	}))
+
+	findUser := func(t *testing.T, userID string) domainuser.User {
+		user, err := store.FindUserByUserID(ctx, userID)
+		require.NoError(t, err)
+		return user
+	}
+	disable := func(t *testing.T, userID string) {
+		require.NoError(t, store.UpdateUserDisabled(ctx, userID, true))
+		t.Cleanup(func() { require.NoError(t, store.UpdateUserDisabled(ctx, userID, false)) })
+	}
+
+	var mails bytes.Buffer
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	refreshTokenManager := jwt.NewRefreshTokenManagerHMAC("refresh-token-secret")
+
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenCreator:    accessTokenManager,
+		AccessTokenParser:     accessTokenManager,
+		AccessTokenExpiresIn:  time.Minute,
+		RefreshTokenCreator:   refreshTokenManager,
+		RefreshTokenParser:    refreshTokenManager,
+		RefreshTokenExpiresIn: time.Hour,
+
+		LockoutPolicy:  serviceoauth2.LockoutPolicy{MaxAttempts: 5, MaxAttemptsPerIP: 5, Window: time.Minute},
+		PasswordHasher: passwordHasher,
+
+		PasswordResetURL:            "https://unotes.example/password-reset",
+		PasswordResetTokenExpiresIn: time.Minute,
+
+		RefreshTokenSaver:    store,
+		RefreshTokensDeleter: store,
+		RefreshTokenGetter:   store,
+
+		UserFinder:            store,
+		UserUpdater:           store,
+		UsersFinder:           store,
+		UserRoleUpdater:       store,
+		UserDisabledUpdater:   store,
+		TOTPFinder:            store,
+		SignInFailureSaver:    store,
+		SignInLockSaver:       store,
+		SignInLockFinder:      store,
+		SignInFailuresDeleter: store,
+
+		PasswordResetTokenSaver: store,
+		ClientFinder:            store,
+
+		Mailer: mailer.NewLogMailer(&mails),
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	newAccessToken := func(t *testing.T, userID, role string) string {
+		accessToken, err := accessTokenManager.New(jwt.AccessTokenClaims{
+			RegisteredClaims: gojwt.RegisteredClaims{ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Minute))},
+			UserID:           userID,
+			Role:             role,
+		})
+		require.NoError(t, err)
+		return accessToken
+	}
+	adminToken := newAccessToken(t, adminID, domainuser.RoleAdmin)
+
+	serve := func(t *testing.T, method, path, body, accessToken string, result any) int {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		request.Header.Set(echo.HeaderAuthorization, "Bearer "+accessToken)
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		if result != nil && recorder.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(result))
+		}
+		return recorder.Code
+	}
+
+	signIn := func(t *testing.T) int {
+		body := `{"username":"username","password":"password"}`
+		return serve(t, http.MethodPost, "/api/oauth2/sign-in", body, "", nil)
+	}
+
+	t.Run("should deny users without admin role", func(t *testing.T) {
+		userToken := newAccessToken(t, userID, domainuser.RoleUser)
+		assert.Equal(t, http.StatusForbidden, serve(t, http.MethodGet, "/api/oauth2/admin/users", "", userToken, nil))
+
+		path := "/api/oauth2/admin/users/" + otherID + "/disable"
+		assert.Equal(t, http.StatusForbidden, serve(t, http.MethodPost, path, "", userToken, nil))
+		assert.Equal(t, http.StatusUnauthorized, serve(t, http.MethodPost, path, "", "invalid", nil))
+	})
+
+	t.Run("should list users page by page", func(t *testing.T) {
+		var page oAuth2ListUsersResult
+		code := serve(t, http.MethodGet, "/api/oauth2/admin/users?limit=2", "", adminToken, &page)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, page.Users, 2)
+		assert.Equal(t, "admin", page.Users[0].Username)
+		assert.Equal(t, domainuser.RoleAdmin, page.Users[0].Role)
+		assert.Equal(t, "other", page.Users[1].Username)
+		assert.Equal(t, "other", page.Next)
+
+		var next oAuth2ListUsersResult
+		code = serve(t, http.MethodGet, "/api/oauth2/admin/users?limit=2&after="+page.Next, "", adminToken, &next)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, next.Users, 1)
+		assert.Equal(t, userID, next.Users[0].ID)
+		assert.Empty(t, next.Next)
+
+		var found oAuth2ListUsersResult
+		code = serve(t, http.MethodGet, "/api/oauth2/admin/users?query=EXAMPLE.com", "", adminToken, &found)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, found.Users, 1)
+		assert.Equal(t, "username@example.com", found.Users[0].Email)
+	})
+
+	t.Run("should list and revoke sessions", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, signIn(t))
+
+		var sessions oAuth2ListUserSessionsResult
+		path := "/api/oauth2/admin/users/" + userID + "/sessions"
+		require.Equal(t, http.StatusOK, serve(t, http.MethodGet, path, "", adminToken, &sessions))
+		require.Len(t, sessions.Sessions, 1)
+		assert.NotEmpty(t, sessions.Sessions[0].SessionID)
+		assert.True(t, sessions.Sessions[0].ExpiresAt.After(time.Now()))
+
+		assert.Equal(t, http.StatusNoContent, serve(t, http.MethodDelete, path, "", adminToken, nil))
+		require.Equal(t, http.StatusOK, serve(t, http.MethodGet, path, "", adminToken, &sessions))
+		assert.Empty(t, sessions.Sessions)
+	})
+
+	t.Run("should disable and enable user", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, signIn(t))
+
+		path := "/api/oauth2/admin/users/" + userID
+		require.Equal(t, http.StatusNoContent, serve(t, http.MethodPost, path+"/disable", "", adminToken, nil))
+		assert.True(t, findUser(t, userID).Disabled)
+		_, err := store.GetRefreshTokens(ctx, userID)
+		assert.ErrorIs(t, err, domainrefresh.ErrTokenNotFound)
+		assert.Equal(t, http.StatusForbidden, signIn(t))
+
+		require.Equal(t, http.StatusNoContent, serve(t, http.MethodPost, path+"/enable", "", adminToken, nil))
+		assert.False(t, findUser(t, userID).Disabled)
+		assert.Equal(t, http.StatusOK, signIn(t))
+
+		path = "/api/oauth2/admin/users/" + adminID + "/disable"
+		assert.Equal(t, http.StatusConflict, serve(t, http.MethodPost, path, "", adminToken, nil))
+	})
+
+	t.Run("should reject password grant of disabled user", func(t *testing.T) {
+		disable(t, userID)
+
+		form := url.Values{
+			"grant_type": {serviceoauth2.GrantTypePassword},
+			"client_id":  {"client"},
+			"username":   {"username"},
+			"password":   {"password"},
+		}
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/token", strings.NewReader(form.Encode()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "invalid_grant")
+	})
+
+	t.Run("should reject refresh of disabled user", func(t *testing.T) {
+		refreshToken, err := refreshTokenManager.New(jwt.RefreshTokenClaims{
+			RegisteredClaims: gojwt.RegisteredClaims{ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Hour))},
+			UserID:           otherID,
+		})
+		require.NoError(t, err)
+		require.NoError(t, store.SaveRefreshToken(ctx, otherID, domainrefresh.Token(refreshToken)))
+		disable(t, otherID)
+
+		assert.Equal(t, http.StatusBadRequest, serve(t, http.MethodGet, "/api/oauth2/refresh?t="+refreshToken, "", "", nil))
+	})
+
+	t.Run("should force password reset", func(t *testing.T) {
+		mails.Reset()
+
+		path := "/api/oauth2/admin/users/" + userID + "/password-reset"
+		require.Equal(t, http.StatusNoContent, serve(t, http.MethodPost, path, "", adminToken, nil))
+		link := regexp.MustCompile(`https://unotes\.example/password-reset\?token=([\w-]+)`)
+		match := link.FindStringSubmatch(mails.String())
+		require.Len(t, match, 2)
+		sum := sha256.Sum256([]byte(match[1]))
+		_, err := store.FindPasswordResetToken(ctx, hex.EncodeToString(sum[:]))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, signIn(t))
+
+		path = "/api/oauth2/admin/users/" + otherID + "/password-reset"
+		assert.Equal(t, http.StatusConflict, serve(t, http.MethodPost, path, "", adminToken, nil))
+	})
+
+	t.Run("should assign role", func(t *testing.T) {
+		path := "/api/oauth2/admin/users/" + userID + "/role"
+		require.Equal(t, http.StatusNoContent, serve(t, http.MethodPut, path, `{"role":"admin"}`, adminToken, nil))
+		assert.Equal(t, domainuser.RoleAdmin, findUser(t, userID).Role)
+
+		assert.Equal(t, http.StatusBadRequest, serve(t, http.MethodPut, path, `{"role":"owner"}`, adminToken, nil))
+
+		path = "/api/oauth2/admin/users/" + adminID + "/role"
+		assert.Equal(t, http.StatusConflict, serve(t, http.MethodPut, path, `{"role":"user"}`, adminToken, nil))
+	})
+
+	t.Run("should return not found for unknown user", func(t *testing.T) {
+		for _, id := range []string{"3f4a5b6c-7d8e-4f9a-9b0c-2d3e4f5a6b7c", "not-a-uuid"} {
+			path := "/api/oauth2/admin/users/" + id + "/sessions"
+			assert.Equal(t, http.StatusNotFound, serve(t, http.MethodDelete, path, "", adminToken, nil))
+		}
+	})
+}
@@ -0,0 +1,249 @@
+package rest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	domainclient "github.com/nazarslota/unotes/auth/internal/domain/client"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/internal/service"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/nazarslota/unotes/auth/pkg/logger"
+	"github.com/nazarslota/unotes/auth/pkg/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestAuthorizationCode authorizes a public client with the authorization code flow and PKCE, consenting on the
+// consent page and exchanging the code for tokens.
+func TestAuthorizationCode(t *testing.T) {
+	const (
+		redirectURI = "https://client.example/callback"
+		verifier    = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	)
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	passwordHasher, err := password.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+	passwordHash, err := passwordHasher.Hash("password")
+	require.NoError(t, err)
+
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveUser(context.Background(), domainuser.User{
+		ID: "user-id", Username: "username", PasswordHash: passwordHash,
+	}))
+	require.NoError(t, store.SaveClient(context.Background(), domainclient.Client{
+		ID:           "web",
+		Name:         "Web",
+		RedirectURIs: []string{redirectURI, "https://client.example/other"},
+		Scopes:       []string{"notes:read", "notes:write"},
+		GrantTypes:   []string{serviceoauth2.GrantTypeAuthorizationCode},
+		Public:       true,
+	}))
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenCreator:   accessTokenManager,
+		AccessTokenParser:    accessTokenManager,
+		AccessTokenExpiresIn: time.Minute,
+
+		LockoutPolicy:              serviceoauth2.LockoutPolicy{MaxAttempts: 5, MaxAttemptsPerIP: 5, Window: time.Minute},
+		PasswordHasher:             passwordHasher,
+		AuthorizationCodeExpiresIn: time.Minute,
+
+		UserFinder:                store,
+		TOTPFinder:                store,
+		SignInFailureSaver:        store,
+		SignInLockSaver:           store,
+		SignInLockFinder:          store,
+		SignInFailuresDeleter:     store,
+		ClientFinder:              store,
+		AuthorizationCodeSaver:    store,
+		AuthorizationCodeConsumer: store,
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	authorization := func() url.Values {
+		return url.Values{
+			"response_type":         {"code"},
+			"client_id":             {"web"},
+			"redirect_uri":          {redirectURI},
+			"state":                 {"state"},
+			"code_challenge":        {challenge},
+			"code_challenge_method": {"S256"},
+		}
+	}
+
+	authorize := func(t *testing.T, params url.Values) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/api/oauth2/authorize?"+params.Encode(), nil)
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// consent signs in on the consent page and allows access, returning the redirect back to the client.
+	consent := func(t *testing.T, params url.Values) *url.URL {
+		form := url.Values{"username": {"username"}, "password": {"password"}, "decision": {"allow"}}
+		for key, values := range params {
+			form[key] = values
+		}
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/authorize", strings.NewReader(form.Encode()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusFound, recorder.Code)
+
+		location, err := url.Parse(recorder.Header().Get(echo.HeaderLocation))
+		require.NoError(t, err)
+		return location
+	}
+
+	code := func(t *testing.T, params url.Values) string {
+		location := consent(t, params)
+		require.Empty(t, location.Query().Get("error"))
+		assert.Equal(t, "state", location.Query().Get("state"))
+		return location.Query().Get("code")
+	}
+
+	exchange := func(t *testing.T, form url.Values) (int, oAuth2TokenResult, oAuth2TokenError) {
+		form.Set("grant_type", serviceoauth2.GrantTypeAuthorizationCode)
+		form.Set("client_id", "web")
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/token", strings.NewReader(form.Encode()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+
+		var result oAuth2TokenResult
+		var tokenError oAuth2TokenError
+		if recorder.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&result))
+		} else {
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&tokenError))
+		}
+		return recorder.Code, result, tokenError
+	}
+
+	t.Run("should render consent page", func(t *testing.T) {
+		recorder := authorize(t, authorization())
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "notes:read")
+		assert.Contains(t, recorder.Body.String(), "notes:write")
+	})
+
+	t.Run("should not redirect to unregistered redirect uri", func(t *testing.T) {
+		params := authorization()
+		params.Set("redirect_uri", "https://attacker.example/callback")
+
+		recorder := authorize(t, params)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Empty(t, recorder.Header().Get(echo.HeaderLocation))
+	})
+
+	t.Run("should require pkce with s256", func(t *testing.T) {
+		for _, method := range []string{"", "plain"} {
+			params := authorization()
+			params.Set("code_challenge_method", method)
+
+			recorder := authorize(t, params)
+			require.Equal(t, http.StatusFound, recorder.Code)
+			location, err := url.Parse(recorder.Header().Get(echo.HeaderLocation))
+			require.NoError(t, err)
+			assert.Equal(t, "invalid_request", location.Query().Get("error"))
+			assert.Equal(t, "state", location.Query().Get("state"))
+		}
+	})
+
+	t.Run("should issue tokens for code", func(t *testing.T) {
+		form := url.Values{"code": {code(t, authorization())}, "redirect_uri": {redirectURI}, "code_verifier": {verifier}}
+		status, result, _ := exchange(t, form)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "notes:read notes:write", result.Scope)
+
+		claims, err := accessTokenManager.Parse(result.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "user-id", claims.UserID)
+		assert.Equal(t, "web", claims.ClientID)
+	})
+
+	t.Run("should narrow scope at consent", func(t *testing.T) {
+		params := authorization()
+		params.Set("scope", "notes:read")
+
+		form := url.Values{"code": {code(t, params)}, "redirect_uri": {redirectURI}, "code_verifier": {verifier}}
+		status, result, _ := exchange(t, form)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "notes:read", result.Scope)
+
+		claims, err := accessTokenManager.Parse(result.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "notes:read", claims.Scope)
+	})
+
+	t.Run("should reject scope not allowed to client at consent", func(t *testing.T) {
+		params := authorization()
+		params.Set("scope", "notes:read notes:admin")
+
+		location := consent(t, params)
+		assert.Equal(t, "invalid_scope", location.Query().Get("error"))
+		assert.Empty(t, location.Query().Get("code"))
+	})
+
+	t.Run("should reject redirect uri mismatch", func(t *testing.T) {
+		form := url.Values{
+			"code": {code(t, authorization())}, "redirect_uri": {"https://client.example/other"}, "code_verifier": {verifier},
+		}
+		status, _, tokenError := exchange(t, form)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_grant", tokenError.Error)
+	})
+
+	t.Run("should reject missing code verifier", func(t *testing.T) {
+		form := url.Values{"code": {code(t, authorization())}, "redirect_uri": {redirectURI}}
+		status, _, tokenError := exchange(t, form)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_request", tokenError.Error)
+	})
+
+	t.Run("should reject wrong code verifier", func(t *testing.T) {
+		form := url.Values{
+			"code": {code(t, authorization())}, "redirect_uri": {redirectURI}, "code_verifier": {verifier + "-wrong"},
+		}
+		status, _, tokenError := exchange(t, form)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_grant", tokenError.Error)
+	})
+
+	t.Run("should not accept code twice", func(t *testing.T) {
+		form := url.Values{"code": {code(t, authorization())}, "redirect_uri": {redirectURI}, "code_verifier": {verifier}}
+		status, _, _ := exchange(t, form)
+		require.Equal(t, http.StatusOK, status)
+
+		status, _, tokenError := exchange(t, form)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "invalid_grant", tokenError.Error)
+	})
+
+	t.Run("should redirect with access denied when user denies", func(t *testing.T) {
+		params := authorization()
+		params.Set("decision", "deny")
+
+		location := consent(t, params)
+		assert.Equal(t, "access_denied", location.Query().Get("error"))
+		assert.Empty(t, location.Query().Get("code"))
+	})
+}
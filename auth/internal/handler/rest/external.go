@@ -0,0 +1,143 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+)
+
+// @Summary		oAuth2 External Authorize
+// @Description	Sign in with an external OpenID Connect identity provider. Redirects to the provider, which redirects
+// @Description	back to the callback endpoint.
+// @Tags			oAuth2
+// @Param			provider	path	string	true	"Identity provider name"
+// @Success		302
+// @Failure		404	{object}	errors.HTTPError
+// @Failure		500	{object}	errors.HTTPError
+// @Router			/oauth2/external/{provider}/authorize [get]
+func (h *Handler) oAuth2ExternalAuthorize(c echo.Context) error {
+	request := serviceoauth2.ExternalAuthorizeRequest{Provider: c.Param("provider")}
+	result, err := h.services.OAuth2Service.ExternalAuthorizeRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrExternalAuthorizeUnknownProvider) {
+		return echo.NewHTTPError(http.StatusNotFound, "unknown identity provider").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+	return c.Redirect(http.StatusFound, result.AuthorizationURL)
+}
+
+type oAuth2ExternalLinkResult struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// @Summary		oAuth2 External Link
+// @Description	Link an identity at an external OpenID Connect identity provider to the account. Returns the URL of
+// @Description	the provider to send the user to, which redirects back to the callback endpoint.
+// @Tags			oAuth2
+// @Produce		json
+// @Param			Authorization	header		string	true	"Bearer access token"
+// @Param			provider		path		string	true	"Identity provider name"
+// @Success		200				{object}	oAuth2ExternalLinkResult
+// @Failure		401				{object}	errors.HTTPError
+// @Failure		404				{object}	errors.HTTPError
+// @Failure		500				{object}	errors.HTTPError
+// @Failure		default			{object}	errors.HTTPError
+// @Router			/oauth2/external/{provider}/link [post]
+func (h *Handler) oAuth2ExternalLink(c echo.Context) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	request := serviceoauth2.ExternalAuthorizeRequest{Provider: c.Param("provider"), AccessToken: accessToken}
+	result, err := h.services.OAuth2Service.ExternalAuthorizeRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrExternalAuthorizeUnknownProvider) {
+		return echo.NewHTTPError(http.StatusNotFound, "unknown identity provider").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrExternalAuthorizeInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, oAuth2ExternalLinkResult{AuthorizationURL: result.AuthorizationURL})
+}
+
+type oAuth2ExternalCallbackModel struct {
+	Code  string `query:"code"`
+	State string `query:"state"`
+	Error string `query:"error"`
+}
+
+type oAuth2ExternalCallbackResult struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	CSRFToken    string `json:"csrf_token,omitempty"`
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+	Linked       bool   `json:"linked,omitempty"`
+}
+
+// @Summary		oAuth2 External Callback
+// @Description	Complete a sign in with an external OpenID Connect identity provider, which redirects here. Signs the
+// @Description	user in like the sign in endpoint, creating an account the first time the identity is used. If the
+// @Description	sign in was started to link the identity, reports that it is linked instead.
+// @Tags			oAuth2
+// @Produce		json
+// @Param			provider	path		string	true	"Identity provider name"
+// @Param			code		query		string	true	"Authorization code from the provider"
+// @Param			state		query		string	true	"State"
+// @Success		200			{object}	oAuth2ExternalCallbackResult
+// @Failure		400			{object}	errors.HTTPError
+// @Failure		403			{object}	errors.HTTPError
+// @Failure		404			{object}	errors.HTTPError
+// @Failure		409			{object}	errors.HTTPError
+// @Failure		500			{object}	errors.HTTPError
+// @Failure		default		{object}	errors.HTTPError
+// @Router			/oauth2/external/{provider}/callback [get]
+func (h *Handler) oAuth2ExternalCallback(c echo.Context) error {
+	input := new(oAuth2ExternalCallbackModel)
+	if err := c.Bind(input); err != nil {
+		return err
+	}
+
+	if len(input.Error) != 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "sign in with the identity provider failed: "+input.Error)
+	}
+
+	request := serviceoauth2.ExternalCallbackRequest{
+		Provider: c.Param("provider"),
+		Code:     input.Code,
+		State:    input.State,
+	}
+	result, err := h.services.OAuth2Service.ExternalCallbackRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrExternalCallbackUnknownProvider) {
+		return echo.NewHTTPError(http.StatusNotFound, "unknown identity provider").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrExternalCallbackInvalidState) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid or expired state").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrExternalCallbackInvalidCode) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid code").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrExternalCallbackIdentityAlreadyLinked) {
+		return echo.NewHTTPError(http.StatusConflict, "identity is already linked to another account").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrExternalCallbackEmailAlreadyExists) {
+		message := "email already exists, sign in and link the identity to the account"
+		return echo.NewHTTPError(http.StatusConflict, message).SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrExternalCallbackDisabled) {
+		return echo.NewHTTPError(http.StatusForbidden, "account disabled").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+
+	refreshToken, csrfToken, err := h.issueRefreshToken(c, result.RefreshToken)
+	if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, oAuth2ExternalCallbackResult{
+		AccessToken:  result.AccessToken,
+		RefreshToken: refreshToken,
+		CSRFToken:    csrfToken,
+		MFARequired:  result.MFARequired,
+		MFAToken:     result.MFAToken,
+		Linked:       result.Linked,
+	})
+}
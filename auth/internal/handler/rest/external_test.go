@@ -0,0 +1,322 @@
+package rest
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	domainidentity "github.com/nazarslota/unotes/auth/internal/domain/identity"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/internal/identityprovider"
+	"github.com/nazarslota/unotes/auth/internal/service"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/nazarslota/unotes/auth/pkg/logger"
+	"github.com/nazarslota/unotes/auth/pkg/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExternalSignIn signs in with an external identity provider, played by an in-process fake OpenID Connect issuer.
+func TestExternalSignIn(t *testing.T) {
+	issuer := newFakeIssuer(t, "unotes")
+	defer issuer.server.Close()
+
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveUser(context.Background(), domainuser.User{
+		ID:            "local-user-id",
+		Username:      "local",
+		Email:         "local@example.com",
+		EmailVerified: true,
+	}))
+
+	server := httptest.NewUnstartedServer(nil)
+	baseURL := "http://" + server.Listener.Addr().String() + "/api/oauth2/external/fake"
+
+	ctx := context.Background()
+	provider, err := identityprovider.NewOIDCProvider(ctx, identityprovider.Config{
+		Issuer:       issuer.server.URL,
+		ClientID:     issuer.clientID,
+		ClientSecret: "secret",
+		RedirectURL:  baseURL + "/callback",
+	})
+	require.NoError(t, err)
+
+	passwordHasher, err := password.NewArgon2idHasher(password.Argon2idParams{Memory: 1024, Time: 1, Threads: 1})
+	require.NoError(t, err)
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	refreshTokenManager := jwt.NewRefreshTokenManagerHMAC("refresh-token-secret")
+
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenCreator:    accessTokenManager,
+		AccessTokenParser:     accessTokenManager,
+		AccessTokenExpiresIn:  time.Minute,
+		RefreshTokenCreator:   refreshTokenManager,
+		RefreshTokenParser:    refreshTokenManager,
+		RefreshTokenExpiresIn: time.Hour,
+
+		PasswordHasher: passwordHasher,
+
+		IdentityProviders:      map[string]serviceoauth2.IdentityProvider{"fake": provider},
+		IdentityStateExpiresIn: time.Minute,
+
+		RefreshTokenSaver:     store,
+		UserSaver:             store,
+		UserFinder:            store,
+		TOTPFinder:            store,
+		IdentitySaver:         store,
+		IdentityFinder:        store,
+		IdentityStateSaver:    store,
+		IdentityStateConsumer: store,
+	})
+
+	h := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard)))
+	server.Config.Handler = h.echo()
+	server.Start()
+	defer server.Close()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	// signIn follows the redirects from the authorization URL through the fake issuer back to the callback endpoint.
+	signIn := func(t *testing.T, authorizationURL string) (int, oAuth2ExternalCallbackResult) {
+		response, err := client.Get(authorizationURL)
+		require.NoError(t, err)
+		for response.StatusCode == http.StatusFound {
+			_ = response.Body.Close()
+			response, err = client.Get(response.Header.Get(echo.HeaderLocation))
+			require.NoError(t, err)
+		}
+		defer func() { _ = response.Body.Close() }()
+
+		var result oAuth2ExternalCallbackResult
+		if response.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(response.Body).Decode(&result))
+		}
+		return response.StatusCode, result
+	}
+
+	userID := func(t *testing.T, accessToken string) string {
+		claims, err := accessTokenManager.Parse(accessToken)
+		require.NoError(t, err)
+		return claims.UserID
+	}
+
+	t.Run("should create user on first sign in", func(t *testing.T) {
+		issuer.signIn(domainidentity.Profile{
+			Subject:           "subject-a",
+			PreferredUsername: "alice",
+			Email:             "alice@example.com",
+			EmailVerified:     true,
+		})
+
+		code, result := signIn(t, baseURL+"/authorize")
+		require.Equal(t, http.StatusOK, code)
+		require.NotEmpty(t, result.AccessToken)
+		require.NotEmpty(t, result.RefreshToken)
+
+		user, err := store.FindUserByUserID(ctx, userID(t, result.AccessToken))
+		require.NoError(t, err)
+		assert.Equal(t, "alice", user.Username)
+		assert.Equal(t, "alice@example.com", user.Email)
+		assert.True(t, user.EmailVerified)
+
+		code, again := signIn(t, baseURL+"/authorize")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, user.ID, userID(t, again.AccessToken))
+	})
+
+	t.Run("should not take over email of existing user", func(t *testing.T) {
+		issuer.signIn(domainidentity.Profile{
+			Subject:       "subject-b",
+			Email:         "local@example.com",
+			EmailVerified: true,
+		})
+
+		code, _ := signIn(t, baseURL+"/authorize")
+		assert.Equal(t, http.StatusConflict, code)
+	})
+
+	t.Run("should link identity to signed in user", func(t *testing.T) {
+		accessToken, err := accessTokenManager.New(jwt.AccessTokenClaims{
+			RegisteredClaims: gojwt.RegisteredClaims{
+				ID:        uuid.New().String(),
+				ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+			UserID: "local-user-id",
+		})
+		require.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, baseURL+"/link", nil)
+		require.NoError(t, err)
+		request.Header.Set(echo.HeaderAuthorization, "Bearer "+accessToken)
+
+		response, err := client.Do(request)
+		require.NoError(t, err)
+		defer func() { _ = response.Body.Close() }()
+		require.Equal(t, http.StatusOK, response.StatusCode)
+
+		var link oAuth2ExternalLinkResult
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&link))
+
+		code, result := signIn(t, link.AuthorizationURL)
+		require.Equal(t, http.StatusOK, code)
+		assert.True(t, result.Linked)
+		assert.Empty(t, result.AccessToken)
+
+		code, result = signIn(t, baseURL+"/authorize")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "local-user-id", userID(t, result.AccessToken))
+	})
+
+	t.Run("should reject unknown state", func(t *testing.T) {
+		response, err := client.Get(baseURL + "/callback?code=code&state=unknown")
+		require.NoError(t, err)
+		_ = response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
+
+// fakeIssuer is a minimal OpenID Connect issuer that signs in whichever user it was last told to, without asking.
+type fakeIssuer struct {
+	server   *httptest.Server
+	clientID string
+	key      *jwt.IDTokenManagerRSA
+
+	mu             sync.Mutex
+	profile        domainidentity.Profile
+	authorizations map[string]fakeAuthorization
+}
+
+type fakeAuthorization struct {
+	profile       domainidentity.Profile
+	nonce         string
+	codeChallenge string
+}
+
+func newFakeIssuer(t *testing.T, clientID string) *fakeIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issuer := &fakeIssuer{
+		clientID:       clientID,
+		key:            jwt.NewIDTokenManagerRSA(key),
+		authorizations: make(map[string]fakeAuthorization),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.configuration)
+	mux.HandleFunc("/jwks", issuer.keySet)
+	mux.HandleFunc("/authorize", issuer.authorize)
+	mux.HandleFunc("/token", issuer.token)
+	issuer.server = httptest.NewServer(mux)
+	return issuer
+}
+
+func (i *fakeIssuer) signIn(profile domainidentity.Profile) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.profile = profile
+}
+
+func (i *fakeIssuer) configuration(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                i.server.URL,
+		"authorization_endpoint":                i.server.URL + "/authorize",
+		"token_endpoint":                        i.server.URL + "/token",
+		"jwks_uri":                              i.server.URL + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (i *fakeIssuer) keySet(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, i.key.KeySet())
+}
+
+func (i *fakeIssuer) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != i.clientID || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	code := uuid.New().String()
+
+	i.mu.Lock()
+	i.authorizations[code] = fakeAuthorization{
+		profile:       i.profile,
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+	}
+	i.mu.Unlock()
+
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid redirect uri", http.StatusBadRequest)
+		return
+	}
+	redirectURI.RawQuery = url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (i *fakeIssuer) token(w http.ResponseWriter, r *http.Request) {
+	code := r.PostFormValue("code")
+
+	i.mu.Lock()
+	authorization, ok := i.authorizations[code]
+	delete(i.authorizations, code)
+	i.mu.Unlock()
+
+	challenge := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(challenge[:]) != authorization.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	idToken, err := i.key.New(jwt.IDTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{
+			Issuer:    i.server.URL,
+			Subject:   authorization.profile.Subject,
+			Audience:  gojwt.ClaimStrings{i.clientID},
+			ExpiresAt: gojwt.NewNumericDate(now.Add(time.Minute)),
+			IssuedAt:  gojwt.NewNumericDate(now),
+		},
+		Nonce:             authorization.nonce,
+		PreferredUsername: authorization.profile.PreferredUsername,
+		Email:             authorization.profile.Email,
+		EmailVerified:     authorization.profile.EmailVerified,
+	})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": uuid.New().String(),
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -3,6 +3,7 @@ package rest
 import (
 	"context"
 	"io"
+	"net"
 	"net/http"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/labstack/echo/v4"
 	_ "github.com/nazarslota/unotes/auth/api/swagger"
 	"github.com/nazarslota/unotes/auth/internal/service"
+	"github.com/nazarslota/unotes/auth/pkg/ratelimit"
 	swagger "github.com/swaggo/echo-swagger"
 )
 
@@ -18,7 +20,12 @@ type Handler struct {
 	logger Logger
 	debug  bool
 
-	services service.Services
+	services    service.Services
+	rateLimiter *ratelimit.Limiter
+
+	sessionCookies   *sessionCookies
+	corsAllowOrigins []string
+	trustedProxies   []*net.IPNet
 }
 
 func NewHandler(options ...HandlerOption) *Handler {
@@ -35,6 +42,17 @@ type Server interface {
 }
 
 func (h *Handler) Server() Server {
+	server := &http.Server{
+		Addr:           h.addr,
+		Handler:        h.echo(),
+		ReadTimeout:    10 * time.Second,
+		WriteTimeout:   10 * time.Second,
+		MaxHeaderBytes: 1 << 20, // 1 MB
+	}
+	return newServer(h.addr, server)
+}
+
+func (h *Handler) echo() *echo.Echo {
 	e := echo.New()
 
 	e.Debug = h.debug
@@ -45,32 +63,134 @@ func (h *Handler) Server() Server {
 	e.Logger.SetOutput(io.Discard)
 	e.StdLogger.SetOutput(io.Discard)
 
+	// The client IP address, which the rate limits and sign in lockouts are keyed by, is the address the request comes
+	// from. Only trusted proxies may forward the address of the client in X-Forwarded-For, otherwise any client could
+	// pick its own.
+	e.IPExtractor = echo.ExtractIPDirect()
+	if len(h.trustedProxies) != 0 {
+		options := []echo.TrustOption{
+			echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false),
+		}
+		for _, proxy := range h.trustedProxies {
+			options = append(options, echo.TrustIPRange(proxy))
+		}
+		e.IPExtractor = echo.ExtractIPFromXFFHeader(options...)
+	}
+
 	e.Use(newLoggerMiddleware(h.logger))
 	e.Use(newRequestLoggerMiddleware(h.logger))
-	e.Use(newCORSMiddleware())
+	if len(h.corsAllowOrigins) != 0 {
+		e.Use(newCORSMiddleware(h.corsAllowOrigins))
+	}
+	e.Use(newRemoteMiddleware())
 
 	h.registerEndpoints(e)
-
-	server := &http.Server{
-		Addr:           h.addr,
-		Handler:        e,
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		MaxHeaderBytes: 1 << 20, // 1 MB
-	}
-	return newServer(h.addr, server)
+	return e
 }
 
 func (h *Handler) registerEndpoints(e *echo.Echo) {
 	api := e.Group("/api")
 	{
 		api.GET("/swagger/*", swagger.WrapHandler)
+		api.GET("/.well-known/openid-configuration", h.oidcConfiguration)
+		api.GET("/.well-known/jwks.json", h.oidcKeySet)
+		api.GET("/avatars/:key", h.avatar)
+
 		oAuth2 := api.Group("/oauth2")
 		{
 			oAuth2.POST("/sign-up", h.oAuth2SignUp)
 			oAuth2.POST("/sign-in", h.oAuth2SignIn)
-			oAuth2.POST("/sign-out", h.oAuth2SignOut)
-			oAuth2.GET("/refresh", h.oAuth2Refresh)
+			oAuth2.POST("/sign-in/mfa", h.oAuth2SignInMFA, newRateLimiterMiddleware(h.rateLimiter))
+			oAuth2.POST("/sign-in/mfa/passkey/begin", h.oAuth2BeginPasskeyMFA)
+			oAuth2.POST("/sign-in/mfa/passkey/finish", h.oAuth2FinishPasskeyMFA)
+			oAuth2.POST("/sign-in/passkey/begin", h.oAuth2BeginPasskeySignIn)
+			oAuth2.POST("/sign-in/passkey/finish", h.oAuth2FinishPasskeySignIn)
+			oAuth2.POST("/sign-in/unlock", h.oAuth2UnlockSignIn)
+			oAuth2.POST("/sign-out", h.oAuth2SignOut, newCSRFMiddleware(h.sessionCookies != nil))
+			oAuth2.GET("/refresh", h.oAuth2Refresh, newCSRFMiddleware(h.sessionCookies != nil))
+
+			oAuth2.GET("/authorize", h.oAuth2Authorize)
+			oAuth2.POST("/authorize", h.oAuth2Consent, newRateLimiterMiddleware(h.rateLimiter))
+			oAuth2.POST("/token", h.oAuth2Token)
+			oAuth2.POST("/introspect", h.oAuth2Introspect)
+			oAuth2.POST("/revoke", h.oAuth2Revoke)
+			oAuth2.GET("/userinfo", h.oidcUserInfo)
+			oAuth2.POST("/userinfo", h.oidcUserInfo)
+			oAuth2.POST("/clients", h.oAuth2RegisterClient)
+			oAuth2.GET("/security-events", h.oAuth2ListSecurityEvents)
+			oAuth2.GET("/audit-events", h.oAuth2ListAuditEvents)
+
+			external := oAuth2.Group("/external/:provider")
+			{
+				external.GET("/authorize", h.oAuth2ExternalAuthorize)
+				external.POST("/link", h.oAuth2ExternalLink)
+				external.GET("/callback", h.oAuth2ExternalCallback)
+			}
+
+			account := oAuth2.Group("/account", newRateLimiterMiddleware(h.rateLimiter))
+			{
+				account.POST("/delete", h.oAuth2DeleteAccount)
+			}
+
+			profile := oAuth2.Group("/profile")
+			{
+				profile.GET("", h.oAuth2GetProfile)
+				profile.PUT("", h.oAuth2UpdateProfile)
+				profile.PUT("/avatar", h.oAuth2UploadAvatar)
+			}
+
+			personalAccessTokens := oAuth2.Group("/personal-access-tokens")
+			{
+				personalAccessTokens.POST("", h.oAuth2CreatePersonalAccessToken)
+				personalAccessTokens.GET("", h.oAuth2ListPersonalAccessTokens)
+				personalAccessTokens.DELETE("/:id", h.oAuth2RevokePersonalAccessToken)
+			}
+
+			passkeys := oAuth2.Group("/passkeys")
+			{
+				passkeys.GET("", h.oAuth2ListPasskeys)
+				passkeys.DELETE("/:id", h.oAuth2DeletePasskey)
+				passkeys.POST("/register/begin", h.oAuth2BeginPasskeyRegistration)
+				passkeys.POST("/register/finish", h.oAuth2FinishPasskeyRegistration)
+			}
+
+			admin := oAuth2.Group("/admin")
+			{
+				admin.GET("/users", h.oAuth2ListUsers)
+				admin.GET("/users/:id/sessions", h.oAuth2ListUserSessions)
+				admin.DELETE("/users/:id/sessions", h.oAuth2RevokeUserSessions)
+				admin.POST("/users/:id/disable", h.oAuth2DisableUser)
+				admin.POST("/users/:id/enable", h.oAuth2EnableUser)
+				admin.POST("/users/:id/password-reset", h.oAuth2ForcePasswordReset)
+				admin.PUT("/users/:id/role", h.oAuth2SetUserRole)
+			}
+
+			totp := oAuth2.Group("/totp", newRateLimiterMiddleware(h.rateLimiter))
+			{
+				totp.POST("/enroll", h.oAuth2TOTPEnroll)
+				totp.POST("/confirm", h.oAuth2TOTPConfirm)
+				totp.POST("/disable", h.oAuth2TOTPDisable)
+			}
+
+			password := oAuth2.Group("/password", newRateLimiterMiddleware(h.rateLimiter))
+			{
+				password.POST("/change", h.oAuth2ChangePassword)
+				password.POST("/reset", h.oAuth2RequestPasswordReset)
+				password.POST("/reset/confirm", h.oAuth2ConfirmPasswordReset)
+			}
+
+			magicLink := oAuth2.Group("/magic-link", newRateLimiterMiddleware(h.rateLimiter))
+			{
+				magicLink.POST("/request", h.oAuth2RequestMagicLink)
+				magicLink.POST("/confirm", h.oAuth2ConfirmMagicLink)
+			}
+
+			email := oAuth2.Group("/email", newRateLimiterMiddleware(h.rateLimiter))
+			{
+				email.POST("/change", h.oAuth2ChangeEmail)
+				email.POST("/verify", h.oAuth2VerifyEmail)
+				email.POST("/resend-verification", h.oAuth2ResendVerification)
+			}
 		}
 	}
 }
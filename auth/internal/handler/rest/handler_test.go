@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"testing"
+
+	storagefilesystem "github.com/nazarslota/unotes/auth/internal/storage/filesystem"
+	storagememory "github.com/nazarslota/unotes/auth/internal/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore holds the in-memory repositories the tests of the handler run on, all on the same database, so that
+// it can be passed as every repository of the services. Avatars are kept in a temporary directory.
+type memoryStore struct {
+	*storagememory.UserRepository
+	*storagememory.TOTPRepository
+	*storagememory.PasswordResetTokenRepository
+	*storagememory.MagicLinkTokenRepository
+	*storagememory.EmailVerificationTokenRepository
+	*storagememory.ClientRepository
+	*storagememory.AuthorizationCodeRepository
+	*storagememory.IdentityRepository
+	*storagememory.OutboxRepository
+	*storagememory.PersonalAccessTokenRepository
+	*storagememory.PasskeyRepository
+	*storagememory.AuditEventRepository
+	*storagememory.RefreshTokenRepository
+	*storagememory.SignInAttemptRepository
+	*storagememory.RevokedTokenRepository
+	*storagefilesystem.BlobRepository
+}
+
+func newMemoryStore(t *testing.T) *memoryStore {
+	db := storagememory.NewDB()
+	store := &memoryStore{}
+
+	var err error
+	store.UserRepository, err = storagememory.NewUserRepository(db)
+	require.NoError(t, err)
+	store.TOTPRepository, err = storagememory.NewTOTPRepository(db)
+	require.NoError(t, err)
+	store.PasswordResetTokenRepository, err = storagememory.NewPasswordResetTokenRepository(db)
+	require.NoError(t, err)
+	store.MagicLinkTokenRepository, err = storagememory.NewMagicLinkTokenRepository(db)
+	require.NoError(t, err)
+	store.EmailVerificationTokenRepository, err = storagememory.NewEmailVerificationTokenRepository(db)
+	require.NoError(t, err)
+	store.ClientRepository, err = storagememory.NewClientRepository(db)
+	require.NoError(t, err)
+	store.AuthorizationCodeRepository, err = storagememory.NewAuthorizationCodeRepository(db)
+	require.NoError(t, err)
+	store.IdentityRepository, err = storagememory.NewIdentityRepository(db)
+	require.NoError(t, err)
+	store.OutboxRepository, err = storagememory.NewOutboxRepository(db)
+	require.NoError(t, err)
+	store.PersonalAccessTokenRepository, err = storagememory.NewPersonalAccessTokenRepository(db)
+	require.NoError(t, err)
+	store.PasskeyRepository, err = storagememory.NewPasskeyRepository(db)
+	require.NoError(t, err)
+	store.AuditEventRepository, err = storagememory.NewAuditEventRepository(db)
+	require.NoError(t, err)
+	store.RefreshTokenRepository, err = storagememory.NewRefreshTokenRepository(db)
+	require.NoError(t, err)
+	store.SignInAttemptRepository, err = storagememory.NewSignInAttemptRepository(db)
+	require.NoError(t, err)
+	store.RevokedTokenRepository, err = storagememory.NewRevokedTokenRepository(db)
+	require.NoError(t, err)
+	store.BlobRepository, err = storagefilesystem.NewBlobRepository(t.TempDir())
+	require.NoError(t, err)
+	return store
+}
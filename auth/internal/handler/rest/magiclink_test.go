@@ -0,0 +1,192 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/internal/mailer"
+	"github.com/nazarslota/unotes/auth/internal/service"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/nazarslota/unotes/auth/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMagicLink requests magic links and signs in with them.
+func TestMagicLink(t *testing.T) {
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveUser(context.Background(), domainuser.User{
+		ID: "user-id", Username: "username", Email: "username@example.com", EmailVerified: true,
+	}))
+
+	var mails bytes.Buffer
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	refreshTokenManager := jwt.NewRefreshTokenManagerHMAC("refresh-token-secret")
+
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenCreator:    accessTokenManager,
+		AccessTokenParser:     accessTokenManager,
+		AccessTokenExpiresIn:  time.Minute,
+		RefreshTokenCreator:   refreshTokenManager,
+		RefreshTokenParser:    refreshTokenManager,
+		RefreshTokenExpiresIn: time.Hour,
+
+		MagicLinkURL:            "https://unotes.example/magic-link",
+		MagicLinkTokenExpiresIn: time.Minute,
+		MagicLinkRateLimit:      serviceoauth2.MagicLinkRateLimit{MaxRequests: 2, MaxRequestsPerIP: 5, Window: time.Hour},
+
+		RefreshTokenSaver:      store,
+		UserFinder:             store,
+		TOTPFinder:             store,
+		MagicLinkTokenSaver:    store,
+		MagicLinkRequestSaver:  store,
+		MagicLinkTokenConsumer: store,
+		MagicLinkTokensDeleter: store,
+
+		Mailer: mailer.NewLogMailer(&mails),
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	requestLink := func(t *testing.T, body, ip string) (*httptest.ResponseRecorder, *http.Cookie, string) {
+		mails.Reset()
+
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/magic-link/request", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.RemoteAddr = ip + ":1234"
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+
+		var nonce *http.Cookie
+		for _, cookie := range recorder.Result().Cookies() {
+			if cookie.Name == magicLinkNonceCookieName {
+				nonce = cookie
+			}
+		}
+
+		var token string
+		if match := regexp.MustCompile(`token=([A-Za-z0-9_-]+)`).FindStringSubmatch(mails.String()); match != nil {
+			token = match[1]
+		}
+		return recorder, nonce, token
+	}
+
+	confirmLink := func(t *testing.T, token string, nonce *http.Cookie) *httptest.ResponseRecorder {
+		body := `{"token":"` + token + `"}`
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/magic-link/confirm", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		if nonce != nil {
+			request.AddCookie(nonce)
+		}
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("should sign in with link from the requesting browser only once", func(t *testing.T) {
+		recorder, nonce, token := requestLink(t, `{"email":"username@example.com"}`, "203.0.113.1")
+		require.Equal(t, http.StatusAccepted, recorder.Code)
+		require.NotNil(t, nonce)
+		require.NotEmpty(t, token)
+		assert.True(t, nonce.HttpOnly)
+		assert.True(t, nonce.Secure)
+		assert.Equal(t, http.SameSiteLaxMode, nonce.SameSite)
+
+		recorder = confirmLink(t, token, nonce)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var result oAuth2SignInUserResult
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&result))
+		assert.NotEmpty(t, result.AccessToken)
+		assert.NotEmpty(t, result.RefreshToken)
+
+		assert.Equal(t, http.StatusBadRequest, confirmLink(t, token, nonce).Code)
+	})
+
+	t.Run("should reject link forwarded to another browser", func(t *testing.T) {
+		recorder, nonce, token := requestLink(t, `{"username":"username"}`, "203.0.113.2")
+		require.Equal(t, http.StatusAccepted, recorder.Code)
+		require.NotEmpty(t, token)
+
+		assert.Equal(t, http.StatusBadRequest, confirmLink(t, token, nil).Code)
+
+		other := &http.Cookie{Name: magicLinkNonceCookieName, Value: "other-nonce"}
+		assert.Equal(t, http.StatusBadRequest, confirmLink(t, token, other).Code)
+
+		// The link was consumed by the first attempt, so it is of no use even to the browser that requested it.
+		assert.Equal(t, http.StatusBadRequest, confirmLink(t, token, nonce).Code)
+	})
+
+	t.Run("should not mail link to unknown user", func(t *testing.T) {
+		recorder, _, token := requestLink(t, `{"username":"unknown"}`, "203.0.113.3")
+		assert.Equal(t, http.StatusAccepted, recorder.Code)
+		assert.Empty(t, token)
+	})
+
+	t.Run("should stop mailing links to account silently after too many requests", func(t *testing.T) {
+		recorder, _, token := requestLink(t, `{"username":"username"}`, "203.0.113.4")
+		assert.Equal(t, http.StatusAccepted, recorder.Code)
+		assert.Empty(t, token)
+	})
+
+	t.Run("should limit requests per ip", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			recorder, _, _ := requestLink(t, `{"username":"unknown"}`, "203.0.113.5")
+			require.Equal(t, http.StatusAccepted, recorder.Code)
+		}
+
+		recorder, _, _ := requestLink(t, `{"username":"unknown"}`, "203.0.113.5")
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+
+		// A client can't pick another address by sending the headers of a proxy.
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/magic-link/request",
+			strings.NewReader(`{"username":"unknown"}`))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set(echo.HeaderXForwardedFor, "203.0.113.6")
+		request.Header.Set(echo.HeaderXRealIP, "203.0.113.6")
+		request.RemoteAddr = "203.0.113.5:1234"
+
+		recorder = httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	})
+
+	t.Run("should take ip forwarded by trusted proxy", func(t *testing.T) {
+		_, proxies, err := net.ParseCIDR("10.0.0.0/8")
+		require.NoError(t, err)
+
+		e := NewHandler(
+			WithServices(services),
+			WithLogger(logger.NewLogger(io.Discard)),
+			WithTrustedProxies([]*net.IPNet{proxies}),
+		).echo()
+
+		request := func(ip string) int {
+			request := httptest.NewRequest(http.MethodPost, "/api/oauth2/magic-link/request",
+				strings.NewReader(`{"username":"unknown"}`))
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set(echo.HeaderXForwardedFor, ip)
+			request.RemoteAddr = "10.0.0.1:1234"
+
+			recorder := httptest.NewRecorder()
+			e.ServeHTTP(recorder, request)
+			return recorder.Code
+		}
+
+		assert.Equal(t, http.StatusTooManyRequests, request("203.0.113.5"))
+		assert.Equal(t, http.StatusAccepted, request("203.0.113.7"))
+	})
+}
@@ -0,0 +1,884 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/nazarslota/unotes/auth/api/events"
+	domainclient "github.com/nazarslota/unotes/auth/internal/domain/client"
+	domainpasswordreset "github.com/nazarslota/unotes/auth/internal/domain/passwordreset"
+	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	domaintotp "github.com/nazarslota/unotes/auth/internal/domain/totp"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/internal/mailer"
+	"github.com/nazarslota/unotes/auth/internal/service"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/nazarslota/unotes/auth/pkg/logger"
+	"github.com/nazarslota/unotes/auth/pkg/messagebus"
+	"github.com/nazarslota/unotes/auth/pkg/password"
+	"github.com/nazarslota/unotes/auth/pkg/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestSignInRehash signs in a user whose password was hashed with bcrypt while the service hashes with argon2id.
+func TestSignInRehash(t *testing.T) {
+	bcryptHasher, err := password.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+	passwordHash, err := bcryptHasher.Hash("password")
+	require.NoError(t, err)
+
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveUser(context.Background(), domainuser.User{
+		ID: "user-id", Username: "username", PasswordHash: passwordHash,
+	}))
+
+	passwordHasher, err := password.NewArgon2idHasher(password.Argon2idParams{Memory: 1024, Time: 1, Threads: 1})
+	require.NoError(t, err)
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	refreshTokenManager := jwt.NewRefreshTokenManagerHMAC("refresh-token-secret")
+
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenCreator:    accessTokenManager,
+		AccessTokenParser:     accessTokenManager,
+		AccessTokenExpiresIn:  time.Minute,
+		RefreshTokenCreator:   refreshTokenManager,
+		RefreshTokenParser:    refreshTokenManager,
+		RefreshTokenExpiresIn: time.Hour,
+
+		LockoutPolicy:  serviceoauth2.LockoutPolicy{MaxAttempts: 5, MaxAttemptsPerIP: 5, Window: time.Minute},
+		PasswordHasher: passwordHasher,
+
+		RefreshTokenSaver:     store,
+		UserFinder:            store,
+		UserUpdater:           store,
+		TOTPFinder:            store,
+		SignInFailureSaver:    store,
+		SignInLockSaver:       store,
+		SignInLockFinder:      store,
+		SignInFailuresDeleter: store,
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	signIn := func(t *testing.T, password string) int {
+		body := `{"username":"username","password":"` + password + `"}`
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/sign-in", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, signIn(t, "wrong-password"))
+	user, err := store.FindUserByUserID(context.Background(), "user-id")
+	require.NoError(t, err)
+	assert.Equal(t, passwordHash, user.PasswordHash)
+
+	assert.Equal(t, http.StatusOK, signIn(t, "password"))
+	user, err = store.FindUserByUserID(context.Background(), "user-id")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(user.PasswordHash, "$argon2id$"))
+	assert.False(t, passwordHasher.NeedsRehash(user.PasswordHash))
+
+	assert.Equal(t, http.StatusOK, signIn(t, "password"))
+}
+
+// TestSignInLockout signs in with wrong passwords until sign in is locked.
+func TestSignInLockout(t *testing.T) {
+	passwordHasher, err := password.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+	passwordHash, err := passwordHasher.Hash("password")
+	require.NoError(t, err)
+
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveUser(context.Background(), domainuser.User{
+		ID: "user-id", Username: "username", PasswordHash: passwordHash,
+	}))
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	refreshTokenManager := jwt.NewRefreshTokenManagerHMAC("refresh-token-secret")
+
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenCreator:    accessTokenManager,
+		AccessTokenParser:     accessTokenManager,
+		AccessTokenExpiresIn:  time.Minute,
+		RefreshTokenCreator:   refreshTokenManager,
+		RefreshTokenParser:    refreshTokenManager,
+		RefreshTokenExpiresIn: time.Hour,
+
+		LockoutPolicy: serviceoauth2.LockoutPolicy{
+			MaxAttempts: 3, MaxAttemptsPerIP: 10, Lockout: 90 * time.Second, Window: time.Minute,
+		},
+		PasswordHasher: passwordHasher,
+
+		RefreshTokenSaver:     store,
+		UserFinder:            store,
+		UserUpdater:           store,
+		TOTPFinder:            store,
+		SignInFailureSaver:    store,
+		SignInLockSaver:       store,
+		SignInLockFinder:      store,
+		SignInFailuresDeleter: store,
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	signIn := func(t *testing.T, password string) *httptest.ResponseRecorder {
+		body := `{"username":"username","password":"` + password + `"}`
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/sign-in", strings.NewReader(body))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("should reset failures on success", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, signIn(t, "wrong-password").Code)
+		assert.Equal(t, http.StatusBadRequest, signIn(t, "wrong-password").Code)
+		assert.Equal(t, http.StatusOK, signIn(t, "password").Code)
+
+		// Were the failures above still counted, this one would lock sign in.
+		assert.Equal(t, http.StatusBadRequest, signIn(t, "wrong-password").Code)
+		assert.Equal(t, http.StatusOK, signIn(t, "password").Code)
+	})
+
+	t.Run("should lock after too many failures", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusBadRequest, signIn(t, "wrong-password").Code)
+		}
+
+		// The right password is rejected as well until the lock expires.
+		recorder := signIn(t, "password")
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		assert.Equal(t, "90", recorder.Header().Get(echo.HeaderRetryAfter))
+	})
+}
+
+// TestSignInMFA signs in with two-factor authentication, guessing codes and replaying a used one.
+func TestSignInMFA(t *testing.T) {
+	passwordHasher, err := password.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+	passwordHash, err := passwordHasher.Hash("password")
+	require.NoError(t, err)
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveUser(context.Background(), domainuser.User{
+		ID: "user-id", Username: "username", PasswordHash: passwordHash,
+	}))
+	require.NoError(t, store.SaveTOTP(context.Background(), domaintotp.TOTP{
+		UserID: "user-id", Secret: secret, Enabled: true,
+	}))
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	refreshTokenManager := jwt.NewRefreshTokenManagerHMAC("refresh-token-secret")
+	mfaTokenManager := jwt.NewMFATokenManagerHMAC("mfa-token-secret")
+
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenCreator:    accessTokenManager,
+		AccessTokenParser:     accessTokenManager,
+		AccessTokenExpiresIn:  time.Minute,
+		RefreshTokenCreator:   refreshTokenManager,
+		RefreshTokenParser:    refreshTokenManager,
+		RefreshTokenExpiresIn: time.Hour,
+		MFATokenCreator:       mfaTokenManager,
+		MFATokenParser:        mfaTokenManager,
+		MFATokenExpiresIn:     time.Minute,
+
+		LockoutPolicy: serviceoauth2.LockoutPolicy{
+			MaxAttempts: 10, MaxAttemptsPerIP: 20, Lockout: time.Minute, Window: time.Minute,
+		},
+		PasswordHasher: passwordHasher,
+
+		RefreshTokenSaver:       store,
+		UserFinder:              store,
+		UserUpdater:             store,
+		TOTPFinder:              store,
+		TOTPLastUsedStepUpdater: store,
+		RecoveryCodeDeleter:     store,
+		SignInFailureSaver:      store,
+		SignInLockSaver:         store,
+		SignInLockFinder:        store,
+		SignInFailuresDeleter:   store,
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	serve := func(t *testing.T, path, body string, result any) int {
+		request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		if result != nil && recorder.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(result))
+		}
+		return recorder.Code
+	}
+
+	signIn := func(t *testing.T) string {
+		var result oAuth2SignInUserResult
+		code := serve(t, "/api/oauth2/sign-in", `{"username":"username","password":"password"}`, &result)
+		require.Equal(t, http.StatusOK, code)
+		require.True(t, result.MFARequired)
+		return result.MFAToken
+	}
+
+	signInMFA := func(t *testing.T, mfaToken, code string) int {
+		return serve(t, "/api/oauth2/sign-in/mfa", `{"mfa_token":"`+mfaToken+`","code":"`+code+`"}`, nil)
+	}
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+
+	// A code that matches none of the time steps accepted around now.
+	wrongCode := "000000"
+	for i := 0; ; i++ {
+		wrongCode = fmt.Sprintf("%06d", i)
+		if _, ok, err := totp.Validate(secret, wrongCode, time.Now()); err == nil && !ok {
+			break
+		}
+	}
+
+	t.Run("should not accept the same code twice", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, signInMFA(t, signIn(t), code))
+		assert.Equal(t, http.StatusBadRequest, signInMFA(t, signIn(t), code))
+	})
+
+	t.Run("should reject mfa token after too many wrong codes", func(t *testing.T) {
+		mfaToken := signIn(t)
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, http.StatusBadRequest, signInMFA(t, mfaToken, wrongCode))
+		}
+		assert.Equal(t, http.StatusUnauthorized, signInMFA(t, mfaToken, wrongCode))
+	})
+
+	t.Run("should lock second factor after too many wrong codes", func(t *testing.T) {
+		// Along with the replayed code and the wrong codes above, these make ten wrong codes.
+		mfaToken := signIn(t)
+		for i := 0; i < 4; i++ {
+			assert.Equal(t, http.StatusBadRequest, signInMFA(t, mfaToken, wrongCode))
+		}
+
+		// Signing in with the right password again doesn't reset the wrong codes.
+		code, err := totp.GenerateCode(secret, time.Now().Add(totp.Period))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, signInMFA(t, signIn(t), code))
+	})
+}
+
+// TestSignUpPasswordPolicy signs up with passwords that violate the password policy.
+func TestSignUpPasswordPolicy(t *testing.T) {
+	// The SHA-1 hash of "password".
+	breached, err := password.NewBreachedList(strings.NewReader("5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8\n"))
+	require.NoError(t, err)
+
+	passwordHasher, err := password.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+
+	store := newMemoryStore(t)
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		PasswordHasher: passwordHasher,
+		PasswordPolicy: serviceoauth2.PasswordPolicy{MinEntropy: 50, BreachedPasswords: breached},
+		UserSaver:      store,
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	signUp := func(t *testing.T, username, password string) (int, []fieldViolation) {
+		body := `{"username":"` + username + `","password":"` + password + `"}`
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/sign-up", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+
+		var response struct {
+			Error struct {
+				Details []fieldViolation `json:"details"`
+			} `json:"error"`
+		}
+		if recorder.Code != http.StatusNoContent {
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+		}
+		return recorder.Code, response.Error.Details
+	}
+
+	reasons := func(violations []fieldViolation) []string {
+		var reasons []string
+		for _, violation := range violations {
+			assert.Equal(t, "password", violation.Field)
+			assert.NotEmpty(t, violation.Description)
+			reasons = append(reasons, violation.Reason)
+		}
+		return reasons
+	}
+
+	code, violations := signUp(t, "username", "password")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, []string{serviceoauth2.PasswordViolationTooWeak, serviceoauth2.PasswordViolationBreached},
+		reasons(violations))
+
+	code, violations = signUp(t, "username", "my-Username-2023")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, []string{serviceoauth2.PasswordViolationContainsUsername}, reasons(violations))
+
+	code, _ = signUp(t, "username", "correct horse battery staple")
+	assert.Equal(t, http.StatusNoContent, code)
+}
+
+// TestConfirmPasswordResetPasswordPolicy confirms a password reset with a password that violates the password policy,
+// and checks that the token can still be used with a valid one.
+func TestConfirmPasswordResetPasswordPolicy(t *testing.T) {
+	passwordHasher, err := password.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveUser(context.Background(), domainuser.User{ID: "user-id", Username: "username"}))
+
+	const token = "password-reset-token"
+	sum := sha256.Sum256([]byte(token))
+	require.NoError(t, store.SavePasswordResetToken(context.Background(), domainpasswordreset.Token{
+		TokenHash: hex.EncodeToString(sum[:]), UserID: "user-id", ExpiresAt: time.Now().Add(time.Hour),
+	}))
+
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		PasswordHasher:             passwordHasher,
+		PasswordPolicy:             serviceoauth2.PasswordPolicy{MinEntropy: 50},
+		RefreshTokensDeleter:       store,
+		RefreshTokenGetter:         store,
+		UserFinder:                 store,
+		UserUpdater:                store,
+		PasswordResetTokenFinder:   store,
+		PasswordResetTokenConsumer: store,
+		PasswordResetTokensDeleter: store,
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	confirm := func(t *testing.T, newPassword string) int {
+		body := `{"token":"` + token + `","new_password":"` + newPassword + `"}`
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/password/reset/confirm", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, confirm(t, "password"))
+	assert.Equal(t, http.StatusNoContent, confirm(t, "correct horse battery staple"))
+	assert.Equal(t, http.StatusBadRequest, confirm(t, "correct horse battery staple"))
+
+	user, err := store.FindUserByUserID(context.Background(), "user-id")
+	require.NoError(t, err)
+	assert.NoError(t, passwordHasher.Verify("correct horse battery staple", user.PasswordHash))
+}
+
+// TestChangeEmail changes the verified email of a user, which is kept until the new email is verified.
+func TestChangeEmail(t *testing.T) {
+	passwordHasher, err := password.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+	passwordHash, err := passwordHasher.Hash("password")
+	require.NoError(t, err)
+
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveUser(context.Background(), domainuser.User{
+		ID: "user-id", Username: "username", PasswordHash: passwordHash, Email: "old@example.com", EmailVerified: true,
+	}))
+
+	var mails bytes.Buffer
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenParser: accessTokenManager,
+		PasswordHasher:    passwordHasher,
+
+		EmailVerificationURL:            "https://unotes.example/verify-email",
+		EmailVerificationTokenExpiresIn: time.Hour,
+
+		UserFinder:                     store,
+		UserUpdater:                    store,
+		EmailVerificationTokenSaver:    store,
+		EmailVerificationTokenConsumer: store,
+		EmailVerificationTokensDeleter: store,
+
+		Mailer: mailer.NewLogMailer(&mails),
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	accessToken, err := accessTokenManager.New(jwt.AccessTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Minute))},
+		UserID:           "user-id",
+	})
+	require.NoError(t, err)
+
+	post := func(t *testing.T, path, body string) int {
+		request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set(echo.HeaderAuthorization, "Bearer "+accessToken)
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	code := post(t, "/api/oauth2/email/change", `{"email":"new@example.com","password":"wrong-password"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Empty(t, mails.String())
+
+	code = post(t, "/api/oauth2/email/change", `{"email":"new@example.com","password":"password"}`)
+	require.Equal(t, http.StatusNoContent, code)
+	assert.Contains(t, mails.String(), "new@example.com")
+
+	user, err := store.FindUserByUserID(context.Background(), "user-id")
+	require.NoError(t, err)
+	assert.Equal(t, "old@example.com", user.Email)
+	assert.True(t, user.EmailVerified)
+
+	match := regexp.MustCompile(`token=([A-Za-z0-9_-]+)`).FindStringSubmatch(mails.String())
+	require.NotNil(t, match)
+
+	code = post(t, "/api/oauth2/email/verify", `{"token":"`+match[1]+`"}`)
+	require.Equal(t, http.StatusNoContent, code)
+
+	user, err = store.FindUserByUserID(context.Background(), "user-id")
+	require.NoError(t, err)
+	assert.Equal(t, "new@example.com", user.Email)
+	assert.True(t, user.EmailVerified)
+}
+
+// TestClientCredentials issues access tokens to clients with the client credentials grant.
+func TestClientCredentials(t *testing.T) {
+	secretHash := func(secret string) string {
+		sum := sha256.Sum256([]byte(secret))
+		return hex.EncodeToString(sum[:])
+	}
+
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveClient(context.Background(), domainclient.Client{
+		ID:         "service",
+		SecretHash: secretHash("secret"),
+		Scopes:     []string{"notes:read", "notes:write"},
+		GrantTypes: []string{serviceoauth2.GrantTypeClientCredentials, serviceoauth2.GrantTypeRefreshToken},
+	}))
+	require.NoError(t, store.SaveClient(context.Background(), domainclient.Client{
+		ID: "public", GrantTypes: []string{serviceoauth2.GrantTypeClientCredentials}, Public: true,
+	}))
+	require.NoError(t, store.SaveClient(context.Background(), domainclient.Client{
+		ID:         "web",
+		SecretHash: secretHash("secret"),
+		GrantTypes: []string{serviceoauth2.GrantTypeAuthorizationCode},
+	}))
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenCreator:   accessTokenManager,
+		AccessTokenParser:    accessTokenManager,
+		AccessTokenExpiresIn: time.Minute,
+		ClientFinder:         store,
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	token := func(t *testing.T, clientID, clientSecret, scope string) (int, oAuth2TokenResult, oAuth2TokenError) {
+		form := url.Values{"grant_type": {serviceoauth2.GrantTypeClientCredentials}, "scope": {scope}}
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/token", strings.NewReader(form.Encode()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		request.SetBasicAuth(clientID, clientSecret)
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+
+		var result oAuth2TokenResult
+		var tokenError oAuth2TokenError
+		if recorder.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&result))
+		} else {
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&tokenError))
+		}
+		return recorder.Code, result, tokenError
+	}
+
+	t.Run("should issue access token without refresh token", func(t *testing.T) {
+		code, result, _ := token(t, "service", "secret", "notes:read")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "notes:read", result.Scope)
+		assert.Empty(t, result.RefreshToken)
+
+		claims, err := accessTokenManager.Parse(result.AccessToken)
+		require.NoError(t, err)
+		assert.Empty(t, claims.UserID)
+		assert.Equal(t, "service", claims.Subject)
+		assert.Equal(t, "service", claims.ClientID)
+		assert.Equal(t, "notes:read", claims.Scope)
+	})
+
+	t.Run("should reject scope not allowed to client", func(t *testing.T) {
+		code, _, tokenError := token(t, "service", "secret", "notes:read openid")
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "invalid_scope", tokenError.Error)
+	})
+
+	t.Run("should reject public client", func(t *testing.T) {
+		code, _, tokenError := token(t, "public", "", "")
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "unauthorized_client", tokenError.Error)
+	})
+
+	t.Run("should reject client without grant type", func(t *testing.T) {
+		code, _, tokenError := token(t, "web", "secret", "")
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "unauthorized_client", tokenError.Error)
+	})
+
+	t.Run("should reject invalid client secret", func(t *testing.T) {
+		code, _, tokenError := token(t, "service", "wrong-secret", "")
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Equal(t, "invalid_client", tokenError.Error)
+	})
+}
+
+// TestIntrospect introspects access and refresh tokens as a resource server, as described in RFC 7662.
+func TestIntrospect(t *testing.T) {
+	secretHash := func(secret string) string {
+		sum := sha256.Sum256([]byte(secret))
+		return hex.EncodeToString(sum[:])
+	}
+
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveClient(context.Background(), domainclient.Client{
+		ID: "note", SecretHash: secretHash("secret"),
+	}))
+	require.NoError(t, store.SaveClient(context.Background(), domainclient.Client{ID: "public", Public: true}))
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	refreshTokenManager := jwt.NewRefreshTokenManagerHMAC("refresh-token-secret")
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenParser:   accessTokenManager,
+		RefreshTokenParser:  refreshTokenManager,
+		RefreshTokenGetter:  store,
+		RevokedTokenChecker: store,
+		ClientFinder:        store,
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	expiresAt := time.Now().Add(time.Minute).Truncate(time.Second)
+	accessToken, err := accessTokenManager.New(jwt.AccessTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{ID: "access-token-id", ExpiresAt: gojwt.NewNumericDate(expiresAt)},
+		UserID:           "user-id",
+		ClientID:         "web",
+		Scope:            "notes:read",
+		Role:             domainuser.RoleUser,
+	})
+	require.NoError(t, err)
+	refreshToken, err := refreshTokenManager.New(jwt.RefreshTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{ID: "refresh-token-id", ExpiresAt: gojwt.NewNumericDate(expiresAt)},
+		UserID:           "user-id",
+		ClientID:         "web",
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.SaveRefreshToken(context.Background(), "user-id", domainrefresh.Token(refreshToken)))
+
+	introspect := func(t *testing.T, form url.Values, basic bool) (int, oAuth2IntrospectResult, oAuth2TokenError) {
+		if basic {
+			form.Del("client_id")
+			form.Del("client_secret")
+		}
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/introspect", strings.NewReader(form.Encode()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		if basic {
+			request.SetBasicAuth("note", "secret")
+		}
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		assert.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+
+		var result oAuth2IntrospectResult
+		var tokenError oAuth2TokenError
+		if recorder.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&result))
+		} else {
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&tokenError))
+		}
+		return recorder.Code, result, tokenError
+	}
+
+	form := func(token, hint string) url.Values {
+		return url.Values{"client_id": {"note"}, "client_secret": {"secret"}, "token": {token}, "token_type_hint": {hint}}
+	}
+
+	t.Run("should report active access token", func(t *testing.T) {
+		code, result, _ := introspect(t, form(accessToken, ""), true)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, oAuth2IntrospectResult{
+			Active:    true,
+			TokenType: "access_token",
+			JTI:       "access-token-id",
+			Sub:       "user-id",
+			ClientID:  "web",
+			Scope:     "notes:read",
+			Role:      domainuser.RoleUser,
+			Exp:       expiresAt.Unix(),
+		}, result)
+	})
+
+	t.Run("should report active refresh token", func(t *testing.T) {
+		code, result, _ := introspect(t, form(refreshToken, "refresh_token"), false)
+		require.Equal(t, http.StatusOK, code)
+		assert.True(t, result.Active)
+		assert.Equal(t, "refresh_token", result.TokenType)
+		assert.Equal(t, "refresh-token-id", result.JTI)
+		assert.Equal(t, "user-id", result.Sub)
+	})
+
+	t.Run("should find token despite wrong or unknown hint", func(t *testing.T) {
+		_, result, _ := introspect(t, form(accessToken, "refresh_token"), false)
+		assert.True(t, result.Active)
+		assert.Equal(t, "access_token", result.TokenType)
+
+		_, result, _ = introspect(t, form(refreshToken, "access_token"), false)
+		assert.True(t, result.Active)
+		assert.Equal(t, "refresh_token", result.TokenType)
+
+		_, result, _ = introspect(t, form(refreshToken, "id_token"), false)
+		assert.True(t, result.Active)
+		assert.Equal(t, "refresh_token", result.TokenType)
+	})
+
+	t.Run("should report invalid token as inactive", func(t *testing.T) {
+		code, result, _ := introspect(t, form("invalid-token", ""), false)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, oAuth2IntrospectResult{Active: false}, result)
+	})
+
+	t.Run("should report revoked tokens as inactive", func(t *testing.T) {
+		require.NoError(t, store.SaveRevokedToken(context.Background(), "access-token-id", time.Minute))
+		_, result, _ := introspect(t, form(accessToken, ""), false)
+		assert.Equal(t, oAuth2IntrospectResult{Active: false}, result)
+
+		err := store.DeleteRefreshToken(context.Background(), "user-id", domainrefresh.Token(refreshToken))
+		require.NoError(t, err)
+		_, result, _ = introspect(t, form(refreshToken, "refresh_token"), false)
+		assert.Equal(t, oAuth2IntrospectResult{Active: false}, result)
+	})
+
+	t.Run("should reject client that is not authenticated", func(t *testing.T) {
+		wrongSecret := form(accessToken, "")
+		wrongSecret.Set("client_secret", "wrong-secret")
+		code, _, tokenError := introspect(t, wrongSecret, false)
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Equal(t, "invalid_client", tokenError.Error)
+
+		unknown := form(accessToken, "")
+		unknown.Set("client_id", "unknown")
+		code, _, tokenError = introspect(t, unknown, false)
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Equal(t, "invalid_client", tokenError.Error)
+
+		public := url.Values{"client_id": {"public"}, "token": {accessToken}}
+		code, _, tokenError = introspect(t, public, false)
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Equal(t, "invalid_client", tokenError.Error)
+	})
+
+	t.Run("should reject client using both authentication methods", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/introspect",
+			strings.NewReader(form(accessToken, "").Encode()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		request.SetBasicAuth("note", "secret")
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "invalid_request")
+	})
+
+	t.Run("should reject request without token or client", func(t *testing.T) {
+		code, _, tokenError := introspect(t, form("", ""), false)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "invalid_request", tokenError.Error)
+
+		code, _, tokenError = introspect(t, url.Values{"token": {accessToken}}, false)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "invalid_request", tokenError.Error)
+	})
+}
+
+// TestRevoke revokes access and refresh tokens as their client, as described in RFC 7009.
+func TestRevoke(t *testing.T) {
+	secretHash := func(secret string) string {
+		sum := sha256.Sum256([]byte(secret))
+		return hex.EncodeToString(sum[:])
+	}
+
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveClient(context.Background(), domainclient.Client{
+		ID: "web", SecretHash: secretHash("secret"),
+	}))
+	require.NoError(t, store.SaveClient(context.Background(), domainclient.Client{
+		ID: "other", SecretHash: secretHash("secret"),
+	}))
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	refreshTokenManager := jwt.NewRefreshTokenManagerHMAC("refresh-token-secret")
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenParser:   accessTokenManager,
+		RefreshTokenParser:  refreshTokenManager,
+		RefreshTokenDeleter: store,
+		RevokedTokenSaver:   store,
+		ClientFinder:        store,
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	expiresAt := gojwt.NewNumericDate(time.Now().Add(time.Minute))
+	accessToken, err := accessTokenManager.New(jwt.AccessTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{ID: "access-token-id", ExpiresAt: expiresAt},
+		UserID:           "user-id",
+		ClientID:         "web",
+	})
+	require.NoError(t, err)
+	refreshToken, err := refreshTokenManager.New(jwt.RefreshTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{ID: "refresh-token-id", ExpiresAt: expiresAt},
+		UserID:           "user-id",
+		ClientID:         "web",
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.SaveRefreshToken(context.Background(), "user-id", domainrefresh.Token(refreshToken)))
+
+	revoke := func(t *testing.T, clientID, clientSecret, token, hint string) (int, oAuth2TokenError) {
+		form := url.Values{"token": {token}, "token_type_hint": {hint}}
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/revoke", strings.NewReader(form.Encode()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		request.SetBasicAuth(clientID, clientSecret)
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+
+		var tokenError oAuth2TokenError
+		if recorder.Code != http.StatusOK {
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&tokenError))
+		}
+		return recorder.Code, tokenError
+	}
+
+	t.Run("should reject client that is not authenticated", func(t *testing.T) {
+		code, tokenError := revoke(t, "web", "wrong-secret", accessToken, "")
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Equal(t, "invalid_client", tokenError.Error)
+
+		code, tokenError = revoke(t, "unknown", "secret", accessToken, "")
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Equal(t, "invalid_client", tokenError.Error)
+	})
+
+	t.Run("should not revoke token of another client", func(t *testing.T) {
+		code, tokenError := revoke(t, "other", "secret", accessToken, "")
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "unauthorized_client", tokenError.Error)
+
+		revoked, err := store.IsTokenRevoked(context.Background(), "access-token-id")
+		require.NoError(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("should revoke access token despite wrong hint", func(t *testing.T) {
+		code, _ := revoke(t, "web", "secret", accessToken, "refresh_token")
+		require.Equal(t, http.StatusOK, code)
+
+		revoked, err := store.IsTokenRevoked(context.Background(), "access-token-id")
+		require.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("should revoke refresh token", func(t *testing.T) {
+		code, _ := revoke(t, "web", "secret", refreshToken, "refresh_token")
+		require.Equal(t, http.StatusOK, code)
+
+		_, err := store.GetRefreshToken(context.Background(), "user-id", domainrefresh.Token(refreshToken))
+		assert.ErrorIs(t, err, domainrefresh.ErrTokenNotFound)
+	})
+
+	t.Run("should succeed for unknown or already revoked token", func(t *testing.T) {
+		code, _ := revoke(t, "web", "secret", "unknown-token", "")
+		assert.Equal(t, http.StatusOK, code)
+
+		code, _ = revoke(t, "web", "secret", refreshToken, "refresh_token")
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("should reject request without token", func(t *testing.T) {
+		code, tokenError := revoke(t, "web", "secret", "", "")
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "invalid_request", tokenError.Error)
+	})
+}
+
+// TestSignOutRevokesAccessToken signs out and checks that the access token used is revoked, rejected by the account
+// endpoints, and the revocation is published.
+func TestSignOutRevokesAccessToken(t *testing.T) {
+	store := newMemoryStore(t)
+	bus := messagebus.NewMemoryBus()
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenParser:     accessTokenManager,
+		RefreshTokensDeleter:  store,
+		RefreshTokenGetter:    store,
+		RevokedTokenSaver:     store,
+		RevokedTokenChecker:   store,
+		RevokedTokenPublisher: bus,
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	var revoked []events.TokenRevoked
+	handler := func(_ context.Context, message messagebus.Message) {
+		var event events.TokenRevoked
+		require.NoError(t, json.Unmarshal(message.Data, &event))
+		revoked = append(revoked, event)
+	}
+	_, err := bus.Subscribe(context.Background(), events.SubjectTokenRevoked, handler)
+	require.NoError(t, err)
+
+	expiresAt := time.Now().Add(time.Minute).Truncate(time.Second)
+	accessToken, err := accessTokenManager.New(jwt.AccessTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{ID: "token-id", ExpiresAt: gojwt.NewNumericDate(expiresAt)},
+		UserID:           "user-id",
+	})
+	require.NoError(t, err)
+
+	body := `{"access_token":"` + accessToken + `"}`
+	request := httptest.NewRequest(http.MethodPost, "/api/oauth2/sign-out", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+
+	isRevoked, err := store.IsTokenRevoked(context.Background(), "token-id")
+	require.NoError(t, err)
+	assert.True(t, isRevoked)
+	assert.Equal(t, []events.TokenRevoked{{TokenID: "token-id", ExpiresAt: expiresAt.UTC()}}, revoked)
+
+	request = httptest.NewRequest(http.MethodGet, "/api/oauth2/personal-access-tokens", nil)
+	request.Header.Set(echo.HeaderAuthorization, "Bearer "+accessToken)
+
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
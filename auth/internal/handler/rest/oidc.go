@@ -0,0 +1,153 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+)
+
+type oidcConfigurationResult struct {
+	Issuer                            string   `json:"issuer" example:"http://localhost:8081/api"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	ResponseTypesSupported            []string `json:"response_types_supported" example:"code"`
+	SubjectTypesSupported             []string `json:"subject_types_supported" example:"public"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported" example:"RS256"`
+	ScopesSupported                   []string `json:"scopes_supported" example:"openid"`
+	GrantTypesSupported               []string `json:"grant_types_supported" example:"authorization_code"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported" example:"client_secret_basic"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported" example:"S256"`
+	ClaimsSupported                   []string `json:"claims_supported" example:"sub"`
+}
+
+// @Summary		OpenID Connect Discovery
+// @Description	Describe the OpenID Connect provider, as described in OpenID Connect Discovery 1.0.
+// @Tags			OpenID Connect
+// @Produce		json
+// @Success		200	{object}	oidcConfigurationResult
+// @Failure		404	{object}	errors.HTTPError
+// @Failure		500	{object}	errors.HTTPError
+// @Router			/.well-known/openid-configuration [get]
+func (h *Handler) oidcConfiguration(c echo.Context) error {
+	request := serviceoauth2.DiscoveryRequest{}
+	response, err := h.services.OAuth2Service.DiscoveryRequestHandler.Handle(c.Request().Context(), request)
+	if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	} else if len(response.Issuer) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "OpenID Connect is not configured")
+	}
+
+	issuer := response.Issuer
+	return c.JSON(http.StatusOK, oidcConfigurationResult{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             issuer + "/oauth2/authorize",
+		TokenEndpoint:                     issuer + "/oauth2/token",
+		UserInfoEndpoint:                  issuer + "/oauth2/userinfo",
+		JWKSURI:                           issuer + "/.well-known/jwks.json",
+		IntrospectionEndpoint:             issuer + "/oauth2/introspect",
+		RevocationEndpoint:                issuer + "/oauth2/revoke",
+		ResponseTypesSupported:            []string{"code"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{"RS256"},
+		ScopesSupported:                   response.ScopesSupported,
+		GrantTypesSupported:               response.GrantTypesSupported,
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "nonce", "preferred_username", "email", "email_verified"},
+	})
+}
+
+type oidcKeySetResult struct {
+	Keys []oidcKeyResult `json:"keys"`
+}
+
+type oidcKeyResult struct {
+	Kty string `json:"kty" example:"RSA"`
+	Use string `json:"use" example:"sig"`
+	Alg string `json:"alg" example:"RS256"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e" example:"AQAB"`
+}
+
+// @Summary		OpenID Connect JWKS
+// @Description	Get the public keys ID tokens are signed with, as a JSON Web Key Set.
+// @Tags			OpenID Connect
+// @Produce		json
+// @Success		200	{object}	oidcKeySetResult
+// @Failure		500	{object}	errors.HTTPError
+// @Router			/.well-known/jwks.json [get]
+func (h *Handler) oidcKeySet(c echo.Context) error {
+	request := serviceoauth2.DiscoveryRequest{}
+	response, err := h.services.OAuth2Service.DiscoveryRequestHandler.Handle(c.Request().Context(), request)
+	if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+
+	keys := make([]oidcKeyResult, 0, len(response.KeySet.Keys))
+	for _, key := range response.KeySet.Keys {
+		keys = append(keys, oidcKeyResult{
+			Kty: key.KeyType,
+			Use: key.Use,
+			Alg: key.Algorithm,
+			Kid: key.KeyID,
+			N:   key.Modulus,
+			E:   key.Exponent,
+		})
+	}
+	return c.JSON(http.StatusOK, oidcKeySetResult{Keys: keys})
+}
+
+type oidcUserInfoResult struct {
+	Sub               string `json:"sub"`
+	PreferredUsername string `json:"preferred_username" example:"username"`
+	Email             string `json:"email,omitempty" example:"user@example.com"`
+	EmailVerified     *bool  `json:"email_verified,omitempty"`
+}
+
+// @Summary		OpenID Connect UserInfo
+// @Description	Get the claims about the user the access token was issued for. Tokens issued to a client need the
+// @Description	openid scope, and the email claims are released only with the email scope. Errors follow RFC 6750.
+// @Tags			OpenID Connect
+// @Produce		json
+// @Param			Authorization	header		string	true	"Bearer access token"
+// @Success		200				{object}	oidcUserInfoResult
+// @Failure		401				{object}	oAuth2TokenError
+// @Failure		403				{object}	oAuth2TokenError
+// @Failure		500				{object}	oAuth2TokenError
+// @Router			/oauth2/userinfo [get]
+// @Router			/oauth2/userinfo [post]
+func (h *Handler) oidcUserInfo(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "no-store")
+
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer`)
+		return c.JSON(http.StatusUnauthorized, oAuth2TokenError{Error: "invalid_request"})
+	}
+
+	request := serviceoauth2.UserInfoRequest{AccessToken: accessToken}
+	result, err := h.services.OAuth2Service.UserInfoRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrUserInfoInvalidOrExpiredToken) {
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		return c.JSON(http.StatusUnauthorized, oAuth2TokenError{Error: "invalid_token"})
+	} else if errors.Is(err, serviceoauth2.ErrUserInfoInsufficientScope) {
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="insufficient_scope", scope="openid"`)
+		return c.JSON(http.StatusForbidden, oAuth2TokenError{Error: "insufficient_scope"})
+	} else if err != nil {
+		h.logger.WarnFields("An error occurred while getting user info.", map[string]any{"error": err})
+		return c.JSON(http.StatusInternalServerError, oAuth2TokenError{Error: "server_error"})
+	}
+
+	response := oidcUserInfoResult{Sub: result.Subject, PreferredUsername: result.PreferredUsername}
+	if len(result.Email) != 0 {
+		response.Email, response.EmailVerified = result.Email, &result.EmailVerified
+	}
+	return c.JSON(http.StatusOK, response)
+}
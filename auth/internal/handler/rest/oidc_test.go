@@ -0,0 +1,163 @@
+package rest
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	domainclient "github.com/nazarslota/unotes/auth/internal/domain/client"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/internal/service"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/nazarslota/unotes/auth/pkg/logger"
+	pkgpassword "github.com/nazarslota/unotes/auth/pkg/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/oauth2"
+)
+
+// TestOIDC signs in against the OpenID Connect provider with go-oidc as the relying party.
+func TestOIDC(t *testing.T) {
+	const (
+		clientID    = "relying-party"
+		redirectURI = "http://client.example.com/callback"
+		username    = "username"
+		password    = "password"
+	)
+
+	passwordHasher, err := pkgpassword.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+	passwordHash, err := passwordHasher.Hash(password)
+	require.NoError(t, err)
+
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveUser(context.Background(), domainuser.User{
+		ID:            "user-id",
+		Username:      username,
+		PasswordHash:  passwordHash,
+		Email:         "user@example.com",
+		EmailVerified: true,
+	}))
+	require.NoError(t, store.SaveClient(context.Background(), domainclient.Client{
+		ID:           clientID,
+		Name:         "Relying Party",
+		RedirectURIs: []string{redirectURI},
+		Scopes:       []string{oidc.ScopeOpenID, "email"},
+		GrantTypes:   []string{serviceoauth2.GrantTypeAuthorizationCode, serviceoauth2.GrantTypeRefreshToken},
+		Public:       true,
+	}))
+
+	// The issuer has to be known before the services are created, so the server listens before it is started.
+	server := httptest.NewUnstartedServer(nil)
+	issuer := "http://" + server.Listener.Addr().String() + "/api"
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	idTokenManager := jwt.NewIDTokenManagerRSA(key)
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	refreshTokenManager := jwt.NewRefreshTokenManagerHMAC("refresh-token-secret")
+
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenCreator:    accessTokenManager,
+		AccessTokenParser:     accessTokenManager,
+		AccessTokenExpiresIn:  time.Minute,
+		RefreshTokenCreator:   refreshTokenManager,
+		RefreshTokenParser:    refreshTokenManager,
+		RefreshTokenExpiresIn: time.Hour,
+
+		IDTokenCreator: idTokenManager,
+		IDTokenKeySet:  idTokenManager,
+		Issuer:         issuer,
+
+		LockoutPolicy:              serviceoauth2.LockoutPolicy{MaxAttempts: 5, MaxAttemptsPerIP: 5, Window: time.Minute},
+		PasswordHasher:             passwordHasher,
+		AuthorizationCodeExpiresIn: time.Minute,
+
+		RefreshTokenSaver:         store,
+		RevokedTokenChecker:       store,
+		UserFinder:                store,
+		TOTPFinder:                store,
+		SignInFailureSaver:        store,
+		SignInLockSaver:           store,
+		SignInLockFinder:          store,
+		SignInFailuresDeleter:     store,
+		ClientFinder:              store,
+		AuthorizationCodeSaver:    store,
+		AuthorizationCodeConsumer: store,
+	})
+
+	h := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard)))
+	server.Config.Handler = h.echo()
+	server.Start()
+	defer server.Close()
+
+	ctx := context.Background()
+	provider, err := oidc.NewProvider(ctx, issuer)
+	require.NoError(t, err)
+
+	config := oauth2.Config{
+		ClientID:    clientID,
+		Endpoint:    provider.Endpoint(),
+		RedirectURL: redirectURI,
+		Scopes:      []string{oidc.ScopeOpenID, "email"},
+	}
+	config.Endpoint.AuthStyle = oauth2.AuthStyleInParams
+
+	const state, nonce = "state", "nonce"
+	verifier := oauth2.GenerateVerifier()
+	authCodeURL, err := url.Parse(config.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)))
+	require.NoError(t, err)
+
+	// The consent page posts the authorization request back along with the credentials of the user.
+	form := authCodeURL.Query()
+	form.Set("username", username)
+	form.Set("password", password)
+	form.Set("decision", "allow")
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	response, err := client.PostForm(config.Endpoint.AuthURL, form)
+	require.NoError(t, err)
+	_ = response.Body.Close()
+	require.Equal(t, http.StatusFound, response.StatusCode)
+
+	location, err := response.Location()
+	require.NoError(t, err)
+	require.Equal(t, state, location.Query().Get("state"))
+
+	token, err := config.Exchange(ctx, location.Query().Get("code"), oauth2.VerifierOption(verifier))
+	require.NoError(t, err)
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	require.True(t, ok, "id_token is missing from the token response")
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: clientID}).Verify(ctx, rawIDToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-id", idToken.Subject)
+	assert.Equal(t, nonce, idToken.Nonce)
+	assert.False(t, idToken.IssuedAt.IsZero())
+
+	var claims struct {
+		PreferredUsername string `json:"preferred_username"`
+		Email             string `json:"email"`
+	}
+	require.NoError(t, idToken.Claims(&claims))
+	assert.Equal(t, username, claims.PreferredUsername)
+	assert.Equal(t, "user@example.com", claims.Email)
+
+	userInfo, err := provider.UserInfo(ctx, config.TokenSource(ctx, token))
+	require.NoError(t, err)
+	assert.Equal(t, "user-id", userInfo.Subject)
+	assert.Equal(t, "user@example.com", userInfo.Email)
+	assert.True(t, userInfo.EmailVerified)
+}
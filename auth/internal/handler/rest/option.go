@@ -1,6 +1,12 @@
 package rest
 
-import "github.com/nazarslota/unotes/auth/internal/service"
+import (
+	"net"
+	"time"
+
+	"github.com/nazarslota/unotes/auth/internal/service"
+	"github.com/nazarslota/unotes/auth/pkg/ratelimit"
+)
 
 type HandlerOption func(h *Handler)
 
@@ -27,3 +33,34 @@ func WithDebug(debug bool) HandlerOption {
 		h.debug = debug
 	}
 }
+
+func WithRateLimiter(limiter *ratelimit.Limiter) HandlerOption {
+	return func(h *Handler) {
+		h.rateLimiter = limiter
+	}
+}
+
+// WithSessionCookies turns on the cookie session mode: the refresh token is kept in a cookie scoped to path, the path
+// the oauth2 routes are served at by the client, which is kept for maxAge, and refresh and sign out require a CSRF
+// token.
+func WithSessionCookies(path string, maxAge time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.sessionCookies = &sessionCookies{path: path, maxAge: maxAge}
+	}
+}
+
+// WithCORSAllowOrigins sets the origins that are allowed to make cross-origin requests with credentials. If origins
+// is empty, cross-origin requests are not allowed.
+func WithCORSAllowOrigins(origins []string) HandlerOption {
+	return func(h *Handler) {
+		h.corsAllowOrigins = origins
+	}
+}
+
+// WithTrustedProxies sets the address ranges of the proxies in front of the service, which are trusted to forward the
+// IP address of the client in X-Forwarded-For. If proxies is empty, the address the request comes from is used.
+func WithTrustedProxies(proxies []*net.IPNet) HandlerOption {
+	return func(h *Handler) {
+		h.trustedProxies = proxies
+	}
+}
@@ -0,0 +1,206 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/internal/passkey"
+	"github.com/nazarslota/unotes/auth/internal/passkey/passkeytest"
+	"github.com/nazarslota/unotes/auth/internal/service"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/nazarslota/unotes/auth/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPasskeys registers a passkey with a software authenticator and signs in with it, on its own and as a second
+// factor.
+func TestPasskeys(t *testing.T) {
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveUser(context.Background(), domainuser.User{ID: "user-id", Username: "username"}))
+
+	relyingParty, err := passkey.NewRelyingParty(passkey.Config{
+		RPID: "unotes.example", RPDisplayName: "unotes", RPOrigins: []string{"https://unotes.example"},
+	})
+	require.NoError(t, err)
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	refreshTokenManager := jwt.NewRefreshTokenManagerHMAC("refresh-token-secret")
+	mfaTokenManager := jwt.NewMFATokenManagerHMAC("mfa-token-secret")
+
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenCreator:    accessTokenManager,
+		AccessTokenParser:     accessTokenManager,
+		AccessTokenExpiresIn:  time.Minute,
+		RefreshTokenCreator:   refreshTokenManager,
+		RefreshTokenParser:    refreshTokenManager,
+		RefreshTokenExpiresIn: time.Hour,
+		MFATokenParser:        mfaTokenManager,
+		RevokedTokenChecker:   store,
+
+		PasskeyRelyingParty:     relyingParty,
+		PasskeySessionExpiresIn: time.Minute,
+
+		RefreshTokenSaver: store,
+		UserFinder:        store,
+
+		PasskeySaver:           store,
+		PasskeysFinder:         store,
+		PasskeyFinder:          store,
+		PasskeyUsageUpdater:    store,
+		PasskeyDeleter:         store,
+		PasskeySessionSaver:    store,
+		PasskeySessionConsumer: store,
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	accessToken, err := accessTokenManager.New(jwt.AccessTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Minute))},
+		UserID:           "user-id",
+	})
+	require.NoError(t, err)
+
+	mfaToken, err := mfaTokenManager.New(jwt.MFATokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Minute))},
+		UserID:           "user-id",
+	})
+	require.NoError(t, err)
+
+	serve := func(t *testing.T, method, path, body string, authorized bool, result any) int {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if authorized {
+			request.Header.Set(echo.HeaderAuthorization, "Bearer "+accessToken)
+		}
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		if result != nil && recorder.Code < http.StatusMultipleChoices {
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(result))
+		}
+		return recorder.Code
+	}
+
+	finishBody := func(t *testing.T, fields map[string]any) string {
+		body, err := json.Marshal(fields)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	register := func(t *testing.T, authenticator *passkeytest.Authenticator, name string) (oAuth2PasskeyResult, int) {
+		var begun oAuth2PasskeyCeremonyResult
+		code := serve(t, http.MethodPost, "/api/oauth2/passkeys/register/begin", "", true, &begun)
+		require.Equal(t, http.StatusOK, code)
+		require.NotEmpty(t, begun.SessionToken)
+
+		credential, err := authenticator.Register(begun.Options)
+		require.NoError(t, err)
+
+		var registered oAuth2PasskeyResult
+		body := finishBody(t, map[string]any{
+			"session_token": begun.SessionToken, "name": name, "credential": json.RawMessage(credential),
+		})
+		code = serve(t, http.MethodPost, "/api/oauth2/passkeys/register/finish", body, true, &registered)
+		return registered, code
+	}
+
+	authenticator := passkeytest.NewAuthenticator("https://unotes.example")
+	registered, code := register(t, authenticator, "laptop")
+	require.Equal(t, http.StatusCreated, code)
+
+	t.Run("should list registered passkey", func(t *testing.T) {
+		assert.NotEmpty(t, registered.ID)
+		assert.Equal(t, "laptop", registered.Name)
+		assert.Equal(t, []string{"internal"}, registered.Transports)
+
+		var listed oAuth2ListPasskeysResult
+		require.Equal(t, http.StatusOK, serve(t, http.MethodGet, "/api/oauth2/passkeys", "", true, &listed))
+		require.Len(t, listed.Passkeys, 1)
+		assert.Equal(t, registered.ID, listed.Passkeys[0].ID)
+		assert.Nil(t, listed.Passkeys[0].LastUsedAt)
+	})
+
+	t.Run("should reject registration from another origin", func(t *testing.T) {
+		_, code := register(t, passkeytest.NewAuthenticator("https://evil.example"), "phishing")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("should sign in with passkey once per session", func(t *testing.T) {
+		var begun oAuth2PasskeyCeremonyResult
+		code := serve(t, http.MethodPost, "/api/oauth2/sign-in/passkey/begin", "", false, &begun)
+		require.Equal(t, http.StatusOK, code)
+
+		credential, err := authenticator.Login(begun.Options)
+		require.NoError(t, err)
+
+		var result oAuth2SignInMFAResult
+		body := finishBody(t, map[string]any{
+			"session_token": begun.SessionToken, "credential": json.RawMessage(credential),
+		})
+		require.Equal(t, http.StatusOK, serve(t, http.MethodPost, "/api/oauth2/sign-in/passkey/finish", body, false, &result))
+		assert.NotEmpty(t, result.AccessToken)
+		assert.NotEmpty(t, result.RefreshToken)
+
+		code = serve(t, http.MethodPost, "/api/oauth2/sign-in/passkey/finish", body, false, nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		var listed oAuth2ListPasskeysResult
+		require.Equal(t, http.StatusOK, serve(t, http.MethodGet, "/api/oauth2/passkeys", "", true, &listed))
+		require.Len(t, listed.Passkeys, 1)
+		assert.NotNil(t, listed.Passkeys[0].LastUsedAt)
+	})
+
+	t.Run("should verify second factor with passkey", func(t *testing.T) {
+		var begun oAuth2PasskeyCeremonyResult
+		body := finishBody(t, map[string]any{"mfa_token": mfaToken})
+		require.Equal(t, http.StatusOK, serve(t, http.MethodPost, "/api/oauth2/sign-in/mfa/passkey/begin", body, false, &begun))
+
+		credential, err := authenticator.Login(begun.Options)
+		require.NoError(t, err)
+
+		var result oAuth2SignInMFAResult
+		body = finishBody(t, map[string]any{
+			"mfa_token": mfaToken, "session_token": begun.SessionToken, "credential": json.RawMessage(credential),
+		})
+		require.Equal(t, http.StatusOK, serve(t, http.MethodPost, "/api/oauth2/sign-in/mfa/passkey/finish", body, false, &result))
+		assert.NotEmpty(t, result.AccessToken)
+		assert.NotEmpty(t, result.RefreshToken)
+	})
+
+	t.Run("should reject assertion of unknown passkey", func(t *testing.T) {
+		other := passkeytest.NewAuthenticator("https://unotes.example")
+		_, err := other.Register([]byte(`{"publicKey":{"rp":{"id":"unotes.example"},"user":{"id":"dXNlci1pZA"}}}`))
+		require.NoError(t, err)
+
+		var begun oAuth2PasskeyCeremonyResult
+		code := serve(t, http.MethodPost, "/api/oauth2/sign-in/passkey/begin", "", false, &begun)
+		require.Equal(t, http.StatusOK, code)
+
+		credential, err := other.Login(begun.Options)
+		require.NoError(t, err)
+
+		body := finishBody(t, map[string]any{
+			"session_token": begun.SessionToken, "credential": json.RawMessage(credential),
+		})
+		assert.Equal(t, http.StatusBadRequest, serve(t, http.MethodPost, "/api/oauth2/sign-in/passkey/finish", body, false, nil))
+	})
+
+	t.Run("should delete passkey", func(t *testing.T) {
+		path := "/api/oauth2/passkeys/" + registered.ID
+		assert.Equal(t, http.StatusNoContent, serve(t, http.MethodDelete, path, "", true, nil))
+		assert.Equal(t, http.StatusNotFound, serve(t, http.MethodDelete, path, "", true, nil))
+
+		var begun oAuth2PasskeyCeremonyResult
+		body := finishBody(t, map[string]any{"mfa_token": mfaToken})
+		assert.Equal(t, http.StatusBadRequest, serve(t, http.MethodPost, "/api/oauth2/sign-in/mfa/passkey/begin", body, false, &begun))
+	})
+}
@@ -0,0 +1,162 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+)
+
+type oAuth2PersonalAccessTokenResult struct {
+	ID         string     `json:"id" example:"5b0e2c1a-7d3f-4e8b-9a6c-1f2e3d4c5b6a"`
+	Name       string     `json:"name" example:"backup script"`
+	Scopes     []string   `json:"scopes" example:"notes:read"`
+	CreatedAt  time.Time  `json:"created_at" example:"2023-05-01T12:00:00Z"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" example:"2024-05-01T12:00:00Z"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"2023-05-02T08:30:00Z"`
+}
+
+func newOAuth2PersonalAccessTokenResult(token serviceoauth2.PersonalAccessToken) oAuth2PersonalAccessTokenResult {
+	return oAuth2PersonalAccessTokenResult{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scopes:     token.Scopes,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+	}
+}
+
+type oAuth2CreatePersonalAccessTokenModel struct {
+	Name      string   `json:"name" validate:"required,max=64" example:"backup script"`
+	Scopes    []string `json:"scopes" validate:"required,dive,required" example:"notes:read"`
+	ExpiresIn int64    `json:"expires_in" validate:"min=0" example:"2592000"`
+}
+
+type oAuth2CreatePersonalAccessTokenResult struct {
+	oAuth2PersonalAccessTokenResult
+	Token string `json:"token" example:"unp_q3yGHk1mZ0v8R2x4cJ7nT5bW9eL6sA1dF3gH8kP0uY2"`
+}
+
+// @Summary		oAuth2 Create Personal Access Token
+// @Description	Create a long-lived personal access token for scripts and command line tools, which the note service
+// @Description	accepts as a bearer token. The token is shown only once. It expires after expires_in seconds, or never
+// @Description	if expires_in is 0.
+// @Tags			oAuth2
+// @Accept			json
+// @Produce		json
+// @Param			Authorization	header		string									true	"Bearer access token"
+// @Param			input			body		oAuth2CreatePersonalAccessTokenModel	true	"Token"
+// @Success		201				{object}	oAuth2CreatePersonalAccessTokenResult
+// @Failure		400				{object}	errors.HTTPError
+// @Failure		401				{object}	errors.HTTPError
+// @Failure		500				{object}	errors.HTTPError
+// @Failure		default			{object}	errors.HTTPError
+// @Router			/oauth2/personal-access-tokens [post]
+func (h *Handler) oAuth2CreatePersonalAccessToken(c echo.Context) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	input := new(oAuth2CreatePersonalAccessTokenModel)
+	if err := c.Bind(input); err != nil {
+		return err
+	}
+
+	if err := c.Validate(input); err != nil {
+		return err
+	}
+
+	request := serviceoauth2.CreatePersonalAccessTokenRequest{
+		AccessToken: accessToken,
+		Name:        input.Name,
+		Scopes:      input.Scopes,
+		ExpiresIn:   time.Duration(input.ExpiresIn) * time.Second,
+	}
+	response, err := h.services.OAuth2Service.CreatePersonalAccessTokenRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrCreatePersonalAccessTokenInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrCreatePersonalAccessTokenInvalidName) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid name").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrCreatePersonalAccessTokenInvalidScope) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid scope").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrCreatePersonalAccessTokenInvalidExpiresIn) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid expires in").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+
+	return c.JSON(http.StatusCreated, oAuth2CreatePersonalAccessTokenResult{
+		oAuth2PersonalAccessTokenResult: newOAuth2PersonalAccessTokenResult(response.PersonalAccessToken),
+		Token:                           response.Token,
+	})
+}
+
+type oAuth2ListPersonalAccessTokensResult struct {
+	Tokens []oAuth2PersonalAccessTokenResult `json:"tokens"`
+}
+
+// @Summary		oAuth2 List Personal Access Tokens
+// @Description	List the personal access tokens of the signed-in user, without the tokens themselves
+// @Tags			oAuth2
+// @Produce		json
+// @Param			Authorization	header		string	true	"Bearer access token"
+// @Success		200				{object}	oAuth2ListPersonalAccessTokensResult
+// @Failure		401				{object}	errors.HTTPError
+// @Failure		500				{object}	errors.HTTPError
+// @Failure		default			{object}	errors.HTTPError
+// @Router			/oauth2/personal-access-tokens [get]
+func (h *Handler) oAuth2ListPersonalAccessTokens(c echo.Context) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	request := serviceoauth2.ListPersonalAccessTokensRequest{AccessToken: accessToken}
+	response, err := h.services.OAuth2Service.ListPersonalAccessTokensRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrListPersonalAccessTokensInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+
+	result := oAuth2ListPersonalAccessTokensResult{Tokens: make([]oAuth2PersonalAccessTokenResult, 0, len(response.Tokens))}
+	for _, token := range response.Tokens {
+		result.Tokens = append(result.Tokens, newOAuth2PersonalAccessTokenResult(token))
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// @Summary		oAuth2 Revoke Personal Access Token
+// @Description	Revoke a personal access token of the signed-in user. The note service may accept the token until its
+// @Description	cached introspection result expires.
+// @Tags			oAuth2
+// @Produce		json
+// @Param			Authorization	header	string	true	"Bearer access token"
+// @Param			id				path	string	true	"Token ID"
+// @Success		204
+// @Failure		401		{object}	errors.HTTPError
+// @Failure		404		{object}	errors.HTTPError
+// @Failure		500		{object}	errors.HTTPError
+// @Failure		default	{object}	errors.HTTPError
+// @Router			/oauth2/personal-access-tokens/{id} [delete]
+func (h *Handler) oAuth2RevokePersonalAccessToken(c echo.Context) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	request := serviceoauth2.RevokePersonalAccessTokenRequest{AccessToken: accessToken, ID: c.Param("id")}
+	_, err = h.services.OAuth2Service.RevokePersonalAccessTokenRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrRevokePersonalAccessTokenInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrRevokePersonalAccessTokenNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "personal access token not found").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
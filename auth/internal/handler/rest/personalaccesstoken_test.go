@@ -0,0 +1,128 @@
+package rest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	domainclient "github.com/nazarslota/unotes/auth/internal/domain/client"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/internal/service"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/nazarslota/unotes/auth/pkg/logger"
+	"github.com/nazarslota/unotes/auth/pkg/pat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPersonalAccessTokens creates a personal access token, introspects it as the note service does and revokes it.
+func TestPersonalAccessTokens(t *testing.T) {
+	secretHash := sha256.Sum256([]byte("client-secret"))
+
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveUser(context.Background(), domainuser.User{
+		ID: "user-id", Username: "username", Role: domainuser.RoleAdmin,
+	}))
+	require.NoError(t, store.SaveClient(context.Background(), domainclient.Client{
+		ID: "note", SecretHash: hex.EncodeToString(secretHash[:]),
+	}))
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenParser:   accessTokenManager,
+		RevokedTokenChecker: store,
+
+		UserFinder:   store,
+		ClientFinder: store,
+
+		PersonalAccessTokenSaver:        store,
+		PersonalAccessTokensFinder:      store,
+		PersonalAccessTokenFinder:       store,
+		PersonalAccessTokenUsageUpdater: store,
+		PersonalAccessTokenDeleter:      store,
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	accessToken, err := accessTokenManager.New(jwt.AccessTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Minute))},
+		UserID:           "user-id",
+	})
+	require.NoError(t, err)
+
+	serve := func(t *testing.T, request *http.Request, result any) int {
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		if result != nil && recorder.Code < http.StatusMultipleChoices {
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(result))
+		}
+		return recorder.Code
+	}
+
+	create := func(t *testing.T, body string, result any) int {
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/personal-access-tokens", strings.NewReader(body))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		request.Header.Set(echo.HeaderAuthorization, "Bearer "+accessToken)
+		return serve(t, request, result)
+	}
+
+	introspect := func(t *testing.T, token string) oAuth2IntrospectResult {
+		form := url.Values{"client_id": {"note"}, "client_secret": {"client-secret"}, "token": {token}}
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/introspect", strings.NewReader(form.Encode()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+
+		var result oAuth2IntrospectResult
+		require.Equal(t, http.StatusOK, serve(t, request, &result))
+		return result
+	}
+
+	assert.Equal(t, http.StatusBadRequest, create(t, `{"name":"script","scopes":["notes:admin"]}`, nil))
+	assert.Equal(t, http.StatusBadRequest, create(t, `{"name":"script","scopes":["notes:read"],"expires_in":-1}`, nil))
+
+	var created oAuth2CreatePersonalAccessTokenResult
+	require.Equal(t, http.StatusCreated,
+		create(t, `{"name":"script","scopes":["notes:read","notes:read"],"expires_in":3600}`, &created))
+	assert.True(t, strings.HasPrefix(created.Token, pat.Prefix))
+	assert.Equal(t, []string{"notes:read"}, created.Scopes)
+	require.NotNil(t, created.ExpiresAt)
+
+	tokens, err := store.FindPersonalAccessTokens(context.Background(), "user-id")
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.NotEqual(t, created.Token, tokens[0].TokenHash)
+
+	result := introspect(t, created.Token)
+	assert.True(t, result.Active)
+	assert.Equal(t, "access_token", result.TokenType)
+	assert.Equal(t, "user-id", result.Sub)
+	assert.Equal(t, "notes:read", result.Scope)
+	assert.Equal(t, domainuser.RoleAdmin, result.Role)
+	assert.Equal(t, created.ExpiresAt.Unix(), result.Exp)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/oauth2/personal-access-tokens", nil)
+	request.Header.Set(echo.HeaderAuthorization, "Bearer "+accessToken)
+	var listed oAuth2ListPersonalAccessTokensResult
+	require.Equal(t, http.StatusOK, serve(t, request, &listed))
+	require.Len(t, listed.Tokens, 1)
+	assert.Equal(t, created.ID, listed.Tokens[0].ID)
+	assert.NotNil(t, listed.Tokens[0].LastUsedAt)
+
+	revoke := func(t *testing.T) int {
+		request := httptest.NewRequest(http.MethodDelete, "/api/oauth2/personal-access-tokens/"+created.ID, nil)
+		request.Header.Set(echo.HeaderAuthorization, "Bearer "+accessToken)
+		return serve(t, request, nil)
+	}
+	assert.Equal(t, http.StatusNoContent, revoke(t))
+	assert.Equal(t, http.StatusNotFound, revoke(t))
+
+	assert.False(t, introspect(t, created.Token).Active)
+}
@@ -0,0 +1,196 @@
+package rest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+)
+
+type oAuth2ProfileResult struct {
+	UserID      string `json:"user_id" example:"3b241101-e2bb-4255-8caf-4136c566a962"`
+	Username    string `json:"username" example:"username"`
+	DisplayName string `json:"display_name" example:"John Doe"`
+	AvatarURL   string `json:"avatar_url,omitempty" example:"http://localhost:8081/api/avatars/3b241101-e2bb-4255-8caf-4136c566a962-q3yGHk1m"`
+	Locale      string `json:"locale" example:"en-US"`
+	Timezone    string `json:"timezone" example:"Europe/Kyiv"`
+}
+
+func newOAuth2ProfileResult(profile serviceoauth2.UserProfile) oAuth2ProfileResult {
+	return oAuth2ProfileResult{
+		UserID:      profile.UserID,
+		Username:    profile.Username,
+		DisplayName: profile.DisplayName,
+		AvatarURL:   profile.AvatarURL,
+		Locale:      profile.Locale,
+		Timezone:    profile.Timezone,
+	}
+}
+
+// @Summary		oAuth2 Get Profile
+// @Description	Get the profile of the signed-in user
+// @Tags			oAuth2
+// @Produce		json
+// @Param			Authorization	header		string	true	"Bearer access token"
+// @Success		200				{object}	oAuth2ProfileResult
+// @Failure		401				{object}	errors.HTTPError
+// @Failure		404				{object}	errors.HTTPError
+// @Failure		500				{object}	errors.HTTPError
+// @Failure		default			{object}	errors.HTTPError
+// @Router			/oauth2/profile [get]
+func (h *Handler) oAuth2GetProfile(c echo.Context) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	request := serviceoauth2.GetProfileRequest{AccessToken: accessToken}
+	response, err := h.services.OAuth2Service.GetProfileRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrGetProfileInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrGetProfileUserNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "user not found").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, newOAuth2ProfileResult(response.UserProfile))
+}
+
+type oAuth2UpdateProfileModel struct {
+	DisplayName string `json:"display_name" validate:"max=64" example:"John Doe"`
+	Locale      string `json:"locale" validate:"required,max=35" example:"en-US"`
+	Timezone    string `json:"timezone" validate:"required,max=64" example:"Europe/Kyiv"`
+}
+
+// @Summary		oAuth2 Update Profile
+// @Description	Replace the display name, locale and timezone of the signed-in user. The locale is a BCP 47 language
+// @Description	tag and the timezone a name from the IANA time zone database.
+// @Tags			oAuth2
+// @Accept			json
+// @Produce		json
+// @Param			Authorization	header		string						true	"Bearer access token"
+// @Param			input			body		oAuth2UpdateProfileModel	true	"Profile"
+// @Success		200				{object}	oAuth2ProfileResult
+// @Failure		400				{object}	errors.HTTPError
+// @Failure		401				{object}	errors.HTTPError
+// @Failure		404				{object}	errors.HTTPError
+// @Failure		500				{object}	errors.HTTPError
+// @Failure		default			{object}	errors.HTTPError
+// @Router			/oauth2/profile [put]
+func (h *Handler) oAuth2UpdateProfile(c echo.Context) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	input := new(oAuth2UpdateProfileModel)
+	if err := c.Bind(input); err != nil {
+		return err
+	}
+
+	if err := c.Validate(input); err != nil {
+		return err
+	}
+
+	request := serviceoauth2.UpdateProfileRequest{
+		AccessToken: accessToken,
+		DisplayName: input.DisplayName,
+		Locale:      input.Locale,
+		Timezone:    input.Timezone,
+	}
+	response, err := h.services.OAuth2Service.UpdateProfileRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrUpdateProfileInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrUpdateProfileInvalidDisplayName) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid display name").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrUpdateProfileInvalidLocale) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid locale").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrUpdateProfileInvalidTimezone) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid timezone").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrUpdateProfileUserNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "user not found").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, newOAuth2ProfileResult(response.UserProfile))
+}
+
+// @Summary		oAuth2 Upload Avatar
+// @Description	Replace the avatar of the signed-in user with a PNG, JPEG, GIF or WebP image
+// @Tags			oAuth2
+// @Accept			multipart/form-data
+// @Produce		json
+// @Param			Authorization	header		string	true	"Bearer access token"
+// @Param			avatar			formData	file	true	"Image"
+// @Success		200				{object}	oAuth2ProfileResult
+// @Failure		400				{object}	errors.HTTPError
+// @Failure		401				{object}	errors.HTTPError
+// @Failure		404				{object}	errors.HTTPError
+// @Failure		413				{object}	errors.HTTPError
+// @Failure		415				{object}	errors.HTTPError
+// @Failure		500				{object}	errors.HTTPError
+// @Failure		default			{object}	errors.HTTPError
+// @Router			/oauth2/profile/avatar [put]
+func (h *Handler) oAuth2UploadAvatar(c echo.Context) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	header, err := c.FormFile("avatar")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "avatar is not provided").SetInternal(err)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+	defer func() { _ = file.Close() }()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+
+	request := serviceoauth2.UploadAvatarRequest{AccessToken: accessToken, Data: data}
+	response, err := h.services.OAuth2Service.UploadAvatarRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrUploadAvatarInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrUploadAvatarTooLarge) {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "avatar is too large").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrUploadAvatarUnsupportedType) {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, "unsupported avatar type").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrUploadAvatarUserNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "user not found").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, newOAuth2ProfileResult(response.UserProfile))
+}
+
+// @Summary		Avatar
+// @Description	Get an avatar image. Every upload gets a new URL, so avatars can be cached indefinitely.
+// @Tags			Profile
+// @Produce		image/png,image/jpeg,image/gif,image/webp
+// @Param			key	path	string	true	"Avatar key"
+// @Success		200
+// @Failure		404		{object}	errors.HTTPError
+// @Failure		500		{object}	errors.HTTPError
+// @Failure		default	{object}	errors.HTTPError
+// @Router			/avatars/{key} [get]
+func (h *Handler) avatar(c echo.Context) error {
+	request := serviceoauth2.GetAvatarRequest{Key: c.Param("key")}
+	response, err := h.services.OAuth2Service.GetAvatarRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrGetAvatarNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "avatar not found").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	c.Response().Header().Set("X-Content-Type-Options", "nosniff")
+	return c.Blob(http.StatusOK, response.ContentType, response.Data)
+}
@@ -0,0 +1,143 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/internal/service"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/nazarslota/unotes/auth/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProfile updates the profile of a user and uploads avatars for it.
+func TestProfile(t *testing.T) {
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveUser(context.Background(), domainuser.User{ID: "user-id", Username: "username"}))
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenParser: accessTokenManager,
+
+		AvatarURL:     "http://localhost:8081/api/avatars",
+		AvatarMaxSize: 64,
+
+		ProfileFinder:  store,
+		ProfileUpdater: store,
+
+		BlobSaver:   store,
+		BlobFinder:  store,
+		BlobDeleter: store,
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	accessToken, err := accessTokenManager.New(jwt.AccessTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Minute))},
+		UserID:           "user-id",
+	})
+	require.NoError(t, err)
+
+	serve := func(t *testing.T, request *http.Request) (int, oAuth2ProfileResult) {
+		request.Header.Set(echo.HeaderAuthorization, "Bearer "+accessToken)
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+
+		var result oAuth2ProfileResult
+		if recorder.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&result))
+		}
+		return recorder.Code, result
+	}
+
+	updateProfile := func(t *testing.T, body string) (int, oAuth2ProfileResult) {
+		request := httptest.NewRequest(http.MethodPut, "/api/oauth2/profile", strings.NewReader(body))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		return serve(t, request)
+	}
+
+	uploadAvatar := func(t *testing.T, data []byte) (int, oAuth2ProfileResult) {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("avatar", "avatar")
+		require.NoError(t, err)
+		_, err = part.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		request := httptest.NewRequest(http.MethodPut, "/api/oauth2/profile/avatar", body)
+		request.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		return serve(t, request)
+	}
+
+	t.Run("should get default profile", func(t *testing.T) {
+		code, result := serve(t, httptest.NewRequest(http.MethodGet, "/api/oauth2/profile", nil))
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, oAuth2ProfileResult{UserID: "user-id", Username: "username", Locale: "en", Timezone: "UTC"}, result)
+	})
+
+	t.Run("should update profile", func(t *testing.T) {
+		code, result := updateProfile(t, `{"display_name":" John Doe ","locale":"en-us","timezone":"Europe/Kyiv"}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "John Doe", result.DisplayName)
+		assert.Equal(t, "en-US", result.Locale)
+		assert.Equal(t, "Europe/Kyiv", result.Timezone)
+
+		code, result = serve(t, httptest.NewRequest(http.MethodGet, "/api/oauth2/profile", nil))
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "John Doe", result.DisplayName)
+	})
+
+	t.Run("should reject invalid locale and timezone", func(t *testing.T) {
+		code, _ := updateProfile(t, `{"locale":"not a locale","timezone":"UTC"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		code, _ = updateProfile(t, `{"locale":"en","timezone":"Mars/Olympus_Mons"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		code, _ = updateProfile(t, `{"locale":"en","timezone":"Local"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("should upload and replace avatar", func(t *testing.T) {
+		png := []byte("\x89PNG\r\n\x1a\n")
+		code, result := uploadAvatar(t, png)
+		require.Equal(t, http.StatusOK, code)
+		require.True(t, strings.HasPrefix(result.AvatarURL, "http://localhost:8081/api/avatars/user-id-"))
+		first := strings.TrimPrefix(result.AvatarURL, "http://localhost:8081")
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, first, nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "image/png", recorder.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, png, recorder.Body.Bytes())
+
+		code, result = uploadAvatar(t, png)
+		require.Equal(t, http.StatusOK, code)
+		assert.NotEqual(t, first, strings.TrimPrefix(result.AvatarURL, "http://localhost:8081"))
+
+		recorder = httptest.NewRecorder()
+		e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, first, nil))
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("should reject too large and unsupported avatars", func(t *testing.T) {
+		code, _ := uploadAvatar(t, bytes.Repeat([]byte{0}, 65))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+
+		code, _ = uploadAvatar(t, []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+		assert.Equal(t, http.StatusUnsupportedMediaType, code)
+	})
+}
@@ -0,0 +1,177 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+)
+
+type oAuth2SecurityEventResult struct {
+	ID        string    `json:"id" example:"0f6a2c3e-8b1d-4c5f-9e7a-2b3c4d5e6f70"`
+	Type      string    `json:"type" example:"sign_in"`
+	UserID    string    `json:"user_id,omitempty" example:"8e6b9e26-8a5b-4bb8-9e0e-54b4d6a4e6f1"`
+	IP        string    `json:"ip,omitempty" example:"192.0.2.1"`
+	UserAgent string    `json:"user_agent,omitempty" example:"Mozilla/5.0"`
+	SessionID string    `json:"session_id,omitempty" example:"5b0e2c1a-7d3f-4e8b-9a6c-1f2e3d4c5b6a"`
+	Outcome   string    `json:"outcome" example:"failure"`
+	Reason    string    `json:"reason,omitempty" example:"invalid password"`
+	CreatedAt time.Time `json:"created_at" example:"2023-05-01T12:00:00Z"`
+}
+
+func newOAuth2SecurityEventResult(event serviceoauth2.SecurityEvent) oAuth2SecurityEventResult {
+	return oAuth2SecurityEventResult{
+		ID:        event.ID,
+		Type:      event.Type,
+		UserID:    event.UserID,
+		IP:        event.IP,
+		UserAgent: event.UserAgent,
+		SessionID: event.SessionID,
+		Outcome:   event.Outcome,
+		Reason:    event.Reason,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+type oAuth2SecurityEventsResult struct {
+	Events []oAuth2SecurityEventResult `json:"events"`
+}
+
+func newOAuth2SecurityEventsResult(events []serviceoauth2.SecurityEvent) oAuth2SecurityEventsResult {
+	result := oAuth2SecurityEventsResult{Events: make([]oAuth2SecurityEventResult, 0, len(events))}
+	for _, event := range events {
+		result.Events = append(result.Events, newOAuth2SecurityEventResult(event))
+	}
+	return result
+}
+
+// unixTime returns the time of a Unix timestamp in seconds, 0 is the zero time.
+func unixTime(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
+
+type oAuth2ListSecurityEventsModel struct {
+	Before int64 `query:"before" validate:"min=0" example:"1682942400"`
+	Limit  int   `query:"limit" validate:"min=0" example:"50"`
+}
+
+// @Summary		oAuth2 List Security Events
+// @Description	List the security events of the account of the signed-in user, such as sign ins, failed sign ins and
+// @Description	password changes, newest first. To page through older events, set before to the Unix time of the
+// @Description	oldest event listed so far.
+// @Tags			oAuth2
+// @Produce		json
+// @Param			Authorization	header		string	true	"Bearer access token"
+// @Param			before			query		int		false	"Unix time, only events created before it are listed"
+// @Param			limit			query		int		false	"Maximum number of events, 50 by default"
+// @Success		200				{object}	oAuth2SecurityEventsResult
+// @Failure		400				{object}	errors.HTTPError
+// @Failure		401				{object}	errors.HTTPError
+// @Failure		500				{object}	errors.HTTPError
+// @Failure		default			{object}	errors.HTTPError
+// @Router			/oauth2/security-events [get]
+func (h *Handler) oAuth2ListSecurityEvents(c echo.Context) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	input := new(oAuth2ListSecurityEventsModel)
+	if err := c.Bind(input); err != nil {
+		return err
+	}
+
+	if err := c.Validate(input); err != nil {
+		return err
+	}
+
+	request := serviceoauth2.ListSecurityEventsRequest{
+		AccessToken: accessToken,
+		Before:      unixTime(input.Before),
+		Limit:       input.Limit,
+	}
+	response, err := h.services.OAuth2Service.ListSecurityEventsRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrListSecurityEventsInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrListSecurityEventsInvalidLimit) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid limit").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, newOAuth2SecurityEventsResult(response.Events))
+}
+
+type oAuth2ListAuditEventsModel struct {
+	UserID  string `query:"user_id" example:"8e6b9e26-8a5b-4bb8-9e0e-54b4d6a4e6f1"`
+	Type    string `query:"type" example:"sign_in"`
+	Outcome string `query:"outcome" validate:"omitempty,oneof=success failure" example:"failure"`
+	IP      string `query:"ip" example:"192.0.2.1"`
+	Since   int64  `query:"since" validate:"min=0" example:"1682942400"`
+	Until   int64  `query:"until" validate:"min=0" example:"1683028800"`
+	Limit   int    `query:"limit" validate:"min=0" example:"50"`
+}
+
+// @Summary		oAuth2 List Audit Events
+// @Description	List the security events of all users, filtered by the given query parameters, newest first. Admins
+// @Description	only.
+// @Tags			oAuth2
+// @Produce		json
+// @Param			Authorization	header		string	true	"Bearer access token"
+// @Param			user_id			query		string	false	"User ID"
+// @Param			type			query		string	false	"Event type, such as sign_in"
+// @Param			outcome			query		string	false	"success or failure"
+// @Param			ip				query		string	false	"Client IP address"
+// @Param			since			query		int		false	"Unix time, only events created at or after it are listed"
+// @Param			until			query		int		false	"Unix time, only events created before it are listed"
+// @Param			limit			query		int		false	"Maximum number of events, 50 by default"
+// @Success		200				{object}	oAuth2SecurityEventsResult
+// @Failure		400				{object}	errors.HTTPError
+// @Failure		401				{object}	errors.HTTPError
+// @Failure		403				{object}	errors.HTTPError
+// @Failure		500				{object}	errors.HTTPError
+// @Failure		default			{object}	errors.HTTPError
+// @Router			/oauth2/audit-events [get]
+func (h *Handler) oAuth2ListAuditEvents(c echo.Context) error {
+	accessToken, err := bearerToken(c)
+	if err != nil {
+		return err
+	}
+
+	input := new(oAuth2ListAuditEventsModel)
+	if err := c.Bind(input); err != nil {
+		return err
+	}
+
+	if err := c.Validate(input); err != nil {
+		return err
+	}
+
+	request := serviceoauth2.ListAuditEventsRequest{
+		AccessToken: accessToken,
+		UserID:      input.UserID,
+		Type:        input.Type,
+		Outcome:     input.Outcome,
+		IP:          input.IP,
+		Since:       unixTime(input.Since),
+		Until:       unixTime(input.Until),
+		Limit:       input.Limit,
+	}
+	response, err := h.services.OAuth2Service.ListAuditEventsRequestHandler.Handle(c.Request().Context(), request)
+	if errors.Is(err, serviceoauth2.ErrListAuditEventsInvalidOrExpiredToken) {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired token").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrListAuditEventsPermissionDenied) {
+		return echo.NewHTTPError(http.StatusForbidden, "permission denied").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrListAuditEventsInvalidLimit) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid limit").SetInternal(err)
+	} else if errors.Is(err, serviceoauth2.ErrListAuditEventsInvalidOutcome) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid outcome").SetInternal(err)
+	} else if err != nil {
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+	return c.JSON(http.StatusOK, newOAuth2SecurityEventsResult(response.Events))
+}
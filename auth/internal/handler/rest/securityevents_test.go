@@ -0,0 +1,150 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/internal/service"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/nazarslota/unotes/auth/pkg/logger"
+	"github.com/nazarslota/unotes/auth/pkg/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestSecurityEvents signs in with a wrong and the right password, updates the profile, and reviews the recorded
+// events as the user and as an admin.
+func TestSecurityEvents(t *testing.T) {
+	passwordHasher, err := password.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+	passwordHash, err := passwordHasher.Hash("password")
+	require.NoError(t, err)
+
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveUser(context.Background(), domainuser.User{
+		ID: "user-id", Username: "username", PasswordHash: passwordHash,
+	}))
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	refreshTokenManager := jwt.NewRefreshTokenManagerHMAC("refresh-token-secret")
+
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenCreator:    accessTokenManager,
+		AccessTokenParser:     accessTokenManager,
+		AccessTokenExpiresIn:  time.Minute,
+		RefreshTokenCreator:   refreshTokenManager,
+		RefreshTokenParser:    refreshTokenManager,
+		RefreshTokenExpiresIn: time.Hour,
+
+		LockoutPolicy:  serviceoauth2.LockoutPolicy{MaxAttempts: 5, MaxAttemptsPerIP: 5, Window: time.Minute},
+		PasswordHasher: passwordHasher,
+
+		RefreshTokenSaver:     store,
+		UserFinder:            store,
+		UserUpdater:           store,
+		TOTPFinder:            store,
+		SignInFailureSaver:    store,
+		SignInLockSaver:       store,
+		SignInLockFinder:      store,
+		SignInFailuresDeleter: store,
+		ProfileFinder:         store,
+		ProfileUpdater:        store,
+
+		AuditLogger:       store,
+		AuditEventsFinder: store,
+	})
+	e := NewHandler(WithServices(services), WithLogger(logger.NewLogger(io.Discard))).echo()
+
+	serve := func(t *testing.T, request *http.Request, result any) int {
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		if result != nil && recorder.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(result))
+		}
+		return recorder.Code
+	}
+
+	signIn := func(t *testing.T, password string) (int, string) {
+		body := `{"username":"username","password":"` + password + `"}`
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/sign-in", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("User-Agent", "unotes-test")
+
+		var result struct {
+			AccessToken string `json:"access_token"`
+		}
+		return serve(t, request, &result), result.AccessToken
+	}
+
+	list := func(t *testing.T, target, accessToken string) (int, oAuth2SecurityEventsResult) {
+		request := httptest.NewRequest(http.MethodGet, target, nil)
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+
+		var result oAuth2SecurityEventsResult
+		return serve(t, request, &result), result
+	}
+
+	code, _ := signIn(t, "wrong-password")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, accessToken := signIn(t, "password")
+	require.Equal(t, http.StatusOK, code)
+
+	body := `{"display_name":"User","locale":"en-US","timezone":"Europe/Kyiv"}`
+	request := httptest.NewRequest(http.MethodPut, "/api/oauth2/profile", strings.NewReader(body))
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+	request.Header.Set("Content-Type", "application/json")
+	require.Equal(t, http.StatusOK, serve(t, request, nil))
+
+	code, result := list(t, "/api/oauth2/security-events", accessToken)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, result.Events, 3)
+
+	update, success, failure := result.Events[0], result.Events[1], result.Events[2]
+	assert.Equal(t, domainaudit.TypeProfileUpdate, update.Type)
+	assert.Equal(t, domainaudit.OutcomeSuccess, update.Outcome)
+	assert.Equal(t, success.SessionID, update.SessionID)
+
+	assert.Equal(t, domainaudit.TypeSignIn, success.Type)
+	assert.Equal(t, domainaudit.OutcomeSuccess, success.Outcome)
+	assert.Equal(t, "user-id", success.UserID)
+	assert.Equal(t, "192.0.2.1", success.IP)
+	assert.Equal(t, "unotes-test", success.UserAgent)
+	assert.NotEmpty(t, success.SessionID)
+	assert.Empty(t, success.Reason)
+
+	assert.Equal(t, domainaudit.OutcomeFailure, failure.Outcome)
+	assert.Equal(t, "invalid password", failure.Reason)
+
+	code, result = list(t, "/api/oauth2/security-events?limit=1", accessToken)
+	require.Equal(t, http.StatusOK, code)
+	assert.Len(t, result.Events, 1)
+
+	code, _ = list(t, "/api/oauth2/audit-events", accessToken)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	adminAccessToken, err := accessTokenManager.New(jwt.AccessTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Minute))},
+		UserID:           "admin-id",
+		Role:             domainuser.RoleAdmin,
+	})
+	require.NoError(t, err)
+
+	code, result = list(t, "/api/oauth2/audit-events?outcome=failure", adminAccessToken)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, result.Events, 1)
+	assert.Equal(t, failure.ID, result.Events[0].ID)
+
+	code, _ = list(t, "/api/oauth2/audit-events?outcome=unknown", adminAccessToken)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
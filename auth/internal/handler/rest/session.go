@@ -0,0 +1,134 @@
+package rest
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// In the cookie session mode the refresh token is kept in an HttpOnly cookie instead of being handed to the client, so
+// scripts can't read it. The routes that read the cookie are protected from cross-site requests with a double-submit
+// CSRF token: it is set in a cookie of its own and returned to the client, which sends it back in the X-CSRF-Token
+// header. A cross-site page can neither read the cookie nor the response, so it can't set the header.
+const (
+	refreshTokenCookieName = "refresh_token"
+	csrfTokenCookieName    = "csrf_token"
+	csrfTokenHeader        = "X-CSRF-Token"
+)
+
+// The magic link nonce binds a magic link to the browser that requested it. It is kept in an HttpOnly cookie, so only
+// the browser that requested the link sends it along when the link is followed.
+const magicLinkNonceCookieName = "magic_link_nonce"
+
+// sessionCookies is the configuration of the cookie session mode.
+type sessionCookies struct {
+	path   string        // path is the path the cookies are scoped to, the path of the oauth2 routes.
+	maxAge time.Duration // maxAge is how long the cookies are kept, the lifetime of refresh tokens.
+}
+
+// setSessionCookies sets the refresh token cookie and a new CSRF token cookie, and returns the CSRF token.
+func (h *Handler) setSessionCookies(c echo.Context, refreshToken string) (string, error) {
+	csrfToken, err := newCookieToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate csrf token: %w", err)
+	}
+
+	c.SetCookie(h.sessionCookie(refreshTokenCookieName, refreshToken, int(h.sessionCookies.maxAge.Seconds()), true))
+	c.SetCookie(h.sessionCookie(csrfTokenCookieName, csrfToken, int(h.sessionCookies.maxAge.Seconds()), false))
+	return csrfToken, nil
+}
+
+// clearSessionCookies expires the refresh token and CSRF token cookies.
+func (h *Handler) clearSessionCookies(c echo.Context) {
+	c.SetCookie(h.sessionCookie(refreshTokenCookieName, "", -1, true))
+	c.SetCookie(h.sessionCookie(csrfTokenCookieName, "", -1, false))
+}
+
+func (h *Handler) sessionCookie(name, value string, maxAge int, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     h.sessionCookies.path,
+		MaxAge:   maxAge,
+		Secure:   true,
+		HttpOnly: httpOnly,
+		SameSite: http.SameSiteStrictMode,
+	}
+}
+
+// setMagicLinkNonceCookie sets a new magic link nonce cookie and returns the nonce. The cookie has no path, so browsers
+// scope it to the directory of the magic link routes, whatever prefix the service is served under. It lasts for the
+// browser session, the magic link itself expires much sooner. SameSite=Lax still keeps it from cross-site POSTs.
+func setMagicLinkNonceCookie(c echo.Context) (string, error) {
+	nonce, err := newCookieToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate magic link nonce: %w", err)
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     magicLinkNonceCookieName,
+		Value:    nonce,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nonce, nil
+}
+
+// clearMagicLinkNonceCookie expires the magic link nonce cookie.
+func clearMagicLinkNonceCookie(c echo.Context) {
+	c.SetCookie(&http.Cookie{
+		Name:     magicLinkNonceCookieName,
+		MaxAge:   -1,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// newCookieToken returns a random token to be kept in a cookie.
+func newCookieToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// newCSRFMiddleware rejects requests whose X-CSRF-Token header doesn't match their CSRF token cookie. If enabled is
+// false, the cookie session mode is off and requests are not checked.
+func newCSRFMiddleware(enabled bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !enabled {
+				return next(c)
+			}
+
+			cookie, err := c.Cookie(csrfTokenCookieName)
+			header := c.Request().Header.Get(csrfTokenHeader)
+			if err != nil || len(cookie.Value) == 0 || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(header)) != 1 {
+				return echo.NewHTTPError(http.StatusForbidden, "invalid csrf token")
+			}
+			return next(c)
+		}
+	}
+}
+
+// issueRefreshToken returns the refresh token to hand to the client in the response and the CSRF token. In the cookie
+// session mode the refresh token is set in its cookie instead, and only the CSRF token is returned.
+func (h *Handler) issueRefreshToken(c echo.Context, refreshToken string) (string, string, error) {
+	if h.sessionCookies == nil || len(refreshToken) == 0 {
+		return refreshToken, "", nil
+	}
+
+	csrfToken, err := h.setSessionCookies(c, refreshToken)
+	if err != nil {
+		return "", "", err
+	}
+	return "", csrfToken, nil
+}
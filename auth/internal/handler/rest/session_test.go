@@ -0,0 +1,202 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/internal/service"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/nazarslota/unotes/auth/pkg/logger"
+	"github.com/nazarslota/unotes/auth/pkg/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestSessionCookies signs in, refreshes, changes the password and signs out in the cookie session mode.
+func TestSessionCookies(t *testing.T) {
+	passwordHasher, err := password.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+	passwordHash, err := passwordHasher.Hash("password")
+	require.NoError(t, err)
+
+	store := newMemoryStore(t)
+	require.NoError(t, store.SaveUser(context.Background(), domainuser.User{
+		ID: "user-id", Username: "username", PasswordHash: passwordHash,
+	}))
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	refreshTokenManager := jwt.NewRefreshTokenManagerHMAC("refresh-token-secret")
+
+	services := service.NewServices(service.OAuth2ServiceOptions{
+		AccessTokenCreator:    accessTokenManager,
+		AccessTokenParser:     accessTokenManager,
+		AccessTokenExpiresIn:  time.Minute,
+		RefreshTokenCreator:   refreshTokenManager,
+		RefreshTokenParser:    refreshTokenManager,
+		RefreshTokenExpiresIn: time.Hour,
+
+		LockoutPolicy:  serviceoauth2.LockoutPolicy{MaxAttempts: 5, MaxAttemptsPerIP: 5, Window: time.Minute},
+		PasswordHasher: passwordHasher,
+
+		RefreshTokenSaver:     store,
+		RefreshTokenDeleter:   store,
+		RefreshTokensDeleter:  store,
+		RefreshTokenGetter:    store,
+		RevokedTokenSaver:     store,
+		UserFinder:            store,
+		UserUpdater:           store,
+		TOTPFinder:            store,
+		SignInFailureSaver:    store,
+		SignInLockSaver:       store,
+		SignInLockFinder:      store,
+		SignInFailuresDeleter: store,
+	})
+	e := NewHandler(
+		WithServices(services),
+		WithLogger(logger.NewLogger(io.Discard)),
+		WithSessionCookies("/api/oauth2", time.Hour),
+		WithCORSAllowOrigins([]string{"https://unotes.example"}),
+	).echo()
+
+	cookie := func(recorder *httptest.ResponseRecorder, name string) *http.Cookie {
+		for _, cookie := range recorder.Result().Cookies() {
+			if cookie.Name == name {
+				return cookie
+			}
+		}
+		return nil
+	}
+
+	signIn := httptest.NewRequest(http.MethodPost, "/api/oauth2/sign-in",
+		strings.NewReader(`{"username":"username","password":"password"}`))
+	signIn.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, signIn)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var signInResult oAuth2SignInUserResult
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&signInResult))
+	assert.NotEmpty(t, signInResult.AccessToken)
+	assert.Empty(t, signInResult.RefreshToken)
+	require.NotEmpty(t, signInResult.CSRFToken)
+
+	refreshToken, csrfToken := cookie(recorder, refreshTokenCookieName), cookie(recorder, csrfTokenCookieName)
+	require.NotNil(t, refreshToken)
+	require.NotNil(t, csrfToken)
+	assert.True(t, refreshToken.HttpOnly)
+	assert.True(t, refreshToken.Secure)
+	assert.Equal(t, http.SameSiteStrictMode, refreshToken.SameSite)
+	assert.Equal(t, "/api/oauth2", refreshToken.Path)
+	assert.False(t, csrfToken.HttpOnly)
+	assert.Equal(t, signInResult.CSRFToken, csrfToken.Value)
+
+	refresh := func(t *testing.T, header string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/api/oauth2/refresh", nil)
+		request.AddCookie(refreshToken)
+		request.AddCookie(csrfToken)
+		if len(header) != 0 {
+			request.Header.Set(csrfTokenHeader, header)
+		}
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("should reject refresh without csrf token", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, refresh(t, "").Code)
+	})
+
+	t.Run("should reject refresh with mismatched csrf token", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, refresh(t, "invalid-csrf-token").Code)
+	})
+
+	t.Run("should refresh with cookie", func(t *testing.T) {
+		recorder := refresh(t, csrfToken.Value)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var result oAuth2RefreshResult
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&result))
+		assert.NotEmpty(t, result.AccessToken)
+		assert.Empty(t, result.RefreshToken)
+		assert.NotEmpty(t, result.CSRFToken)
+
+		refreshToken, csrfToken = cookie(recorder, refreshTokenCookieName), cookie(recorder, csrfTokenCookieName)
+		require.NotNil(t, refreshToken)
+		require.NotNil(t, csrfToken)
+		assert.Equal(t, result.CSRFToken, csrfToken.Value)
+	})
+
+	t.Run("should keep session of cookie on password change", func(t *testing.T) {
+		// Another session, which is revoked by the change.
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/sign-in",
+			strings.NewReader(`{"username":"username","password":"password"}`))
+		request.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		other := cookie(recorder, refreshTokenCookieName)
+		require.NotNil(t, other)
+
+		body := `{"current_password":"password","new_password":"new-password"}`
+		request = httptest.NewRequest(http.MethodPost, "/api/oauth2/password/change", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Authorization", "Bearer "+signInResult.AccessToken)
+		request.AddCookie(refreshToken)
+
+		recorder = httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+
+		_, err := store.GetRefreshToken(context.Background(), "user-id", domainrefresh.Token(other.Value))
+		assert.ErrorIs(t, err, domainrefresh.ErrTokenNotFound)
+
+		recorder = refresh(t, csrfToken.Value)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		refreshToken, csrfToken = cookie(recorder, refreshTokenCookieName), cookie(recorder, csrfTokenCookieName)
+		require.NotNil(t, refreshToken)
+		require.NotNil(t, csrfToken)
+	})
+
+	t.Run("should clear cookies on sign out", func(t *testing.T) {
+		body := `{"access_token":"` + signInResult.AccessToken + `"}`
+		request := httptest.NewRequest(http.MethodPost, "/api/oauth2/sign-out", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set(csrfTokenHeader, csrfToken.Value)
+		request.AddCookie(csrfToken)
+
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+
+		cleared := cookie(recorder, refreshTokenCookieName)
+		require.NotNil(t, cleared)
+		assert.Empty(t, cleared.Value)
+		assert.Negative(t, cleared.MaxAge)
+	})
+
+	t.Run("should allow only configured origins", func(t *testing.T) {
+		preflight := func(origin string) string {
+			request := httptest.NewRequest(http.MethodOptions, "/api/oauth2/refresh", nil)
+			request.Header.Set("Origin", origin)
+			request.Header.Set("Access-Control-Request-Method", http.MethodGet)
+
+			recorder := httptest.NewRecorder()
+			e.ServeHTTP(recorder, request)
+			return recorder.Header().Get("Access-Control-Allow-Origin")
+		}
+
+		assert.Equal(t, "https://unotes.example", preflight("https://unotes.example"))
+		assert.Empty(t, preflight("https://attacker.example"))
+	})
+}
@@ -2,12 +2,15 @@ package rest
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	serviceoauth2 "github.com/nazarslota/unotes/auth/internal/service/oauth2"
 	"github.com/nazarslota/unotes/auth/pkg/errors"
+	"github.com/nazarslota/unotes/auth/pkg/ratelimit"
 )
 
 type validate struct {
@@ -25,6 +28,34 @@ func (v *validate) Validate(i any) error {
 	return nil
 }
 
+// bearerToken extracts the access token from the "Authorization: Bearer <token>" request header.
+func bearerToken(c echo.Context) (string, error) {
+	scheme, token, ok := strings.Cut(c.Request().Header.Get(echo.HeaderAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || len(token) == 0 {
+		return "", echo.NewHTTPError(http.StatusUnauthorized, "authorization header is not provided")
+	}
+	return token, nil
+}
+
+type fieldViolation struct {
+	Field       string `json:"field"`
+	Reason      string `json:"reason"`
+	Description string `json:"description"`
+}
+
+// passwordPolicyError returns a bad request error with the violations of the password policy as its details.
+func passwordPolicyError(policy serviceoauth2.PasswordPolicyError) *errors.HTTPError {
+	details := make([]fieldViolation, 0, len(policy.Violations))
+	for _, violation := range policy.Violations {
+		details = append(details, fieldViolation{
+			Field:       violation.Field,
+			Reason:      violation.Reason,
+			Description: violation.Description,
+		})
+	}
+	return errors.NewHTTPError(http.StatusBadRequest, "password violates the password policy").SetDetails(details)
+}
+
 func newHTTPErrorHandler(e *echo.Echo, logger Logger) echo.HTTPErrorHandler {
 	return func(err error, c echo.Context) {
 		if c.Response().Committed {
@@ -43,6 +74,9 @@ func newHTTPErrorHandler(e *echo.Echo, logger Logger) echo.HTTPErrorHandler {
 			}
 		case *errors.HTTPError:
 			res = echo.Map{"code": err.Code, "message": err.Message}
+			if err.Details != nil {
+				res["details"] = err.Details
+			}
 			if s, ok := err.Message.(string); ok {
 				res["message"] = s
 				if e.Debug && err.Internal != nil {
@@ -111,9 +145,35 @@ func newRequestLoggerMiddleware(_ Logger) echo.MiddlewareFunc {
 	})
 }
 
-func newCORSMiddleware() echo.MiddlewareFunc {
+// newRateLimiterMiddleware limits requests per client IP address and route. If limiter is nil, requests are not
+// limited.
+func newRateLimiterMiddleware(limiter *ratelimit.Limiter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if limiter != nil && !limiter.Allow(c.RealIP()+" "+c.Path()) {
+				return echo.NewHTTPError(http.StatusTooManyRequests, "too many requests")
+			}
+			return next(c)
+		}
+	}
+}
+
+// newRemoteMiddleware puts the client IP address and user agent of the request into its context, where the request
+// handlers of the service record them in the audit log.
+func newRemoteMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			remote := serviceoauth2.Remote{IP: c.RealIP(), UserAgent: c.Request().UserAgent()}
+			c.SetRequest(c.Request().WithContext(serviceoauth2.ContextWithRemote(c.Request().Context(), remote)))
+			return next(c)
+		}
+	}
+}
+
+// newCORSMiddleware allows cross-origin requests with credentials from origins, and only from them.
+func newCORSMiddleware(origins []string) echo.MiddlewareFunc {
 	return middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins:     []string{"https://*", "http://*"},
+		AllowOrigins:     origins,
 		AllowMethods:     []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete},
 		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
 		AllowCredentials: true,
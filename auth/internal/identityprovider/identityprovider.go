@@ -0,0 +1,96 @@
+// Package identityprovider provides external OpenID Connect identity providers users can sign in with, such as
+// Google or a corporate single sign-on.
+package identityprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	domainidentity "github.com/nazarslota/unotes/auth/internal/domain/identity"
+	"golang.org/x/oauth2"
+)
+
+// Config stores the configuration information required to sign in with an OpenID Connect identity provider.
+type Config struct {
+	Issuer       string // Issuer is the issuer URL of the provider, its configuration is discovered from it.
+	ClientID     string // ClientID is the ID of the client registered at the provider.
+	ClientSecret string // ClientSecret is the secret of the client registered at the provider.
+	RedirectURL  string // RedirectURL is the callback URL the provider redirects back to, registered at the provider.
+}
+
+// OIDCProvider signs users in with an OpenID Connect identity provider using the authorization code flow with PKCE.
+type OIDCProvider struct {
+	config   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// NewOIDCProvider creates a new OIDCProvider, discovering the configuration of the provider from its issuer URL.
+//
+// Returns an error if the issuer or the client ID is empty, or if the discovery fails.
+func NewOIDCProvider(ctx context.Context, config Config) (*OIDCProvider, error) {
+	if len(config.Issuer) == 0 {
+		return nil, fmt.Errorf("issuer is empty")
+	} else if len(config.ClientID) == 0 {
+		return nil, fmt.Errorf("client id is empty")
+	}
+
+	provider, err := oidc.NewProvider(ctx, config.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover provider: %w", err)
+	}
+
+	return &OIDCProvider{
+		config: oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			Endpoint:     provider.Endpoint(),
+			RedirectURL:  config.RedirectURL,
+			Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: config.ClientID}),
+	}, nil
+}
+
+// AuthCodeURL returns the URL of the provider to redirect the user to for signing in.
+func (p OIDCProvider) AuthCodeURL(state, nonce, codeVerifier string) string {
+	return p.config.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier))
+}
+
+// Exchange exchanges the authorization code the provider redirected back with for an ID token, verifies the ID token
+// and returns the profile of the user it asserts.
+func (p OIDCProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (domainidentity.Profile, error) {
+	token, err := p.config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return domainidentity.Profile{}, fmt.Errorf("failed to exchange code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return domainidentity.Profile{}, errors.New("id token is missing")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return domainidentity.Profile{}, fmt.Errorf("failed to verify id token: %w", err)
+	} else if idToken.Nonce != nonce {
+		return domainidentity.Profile{}, errors.New("id token nonce does not match")
+	}
+
+	var claims struct {
+		PreferredUsername string `json:"preferred_username"`
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return domainidentity.Profile{}, fmt.Errorf("failed to parse id token claims: %w", err)
+	}
+
+	return domainidentity.Profile{
+		Subject:           idToken.Subject,
+		PreferredUsername: claims.PreferredUsername,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified,
+	}, nil
+}
@@ -0,0 +1,22 @@
+package identityprovider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOIDCProvider(t *testing.T) {
+	t.Run("should return an error if issuer is empty", func(t *testing.T) {
+		provider, err := NewOIDCProvider(context.Background(), Config{ClientID: "client"})
+		assert.EqualError(t, err, "issuer is empty")
+		assert.Nil(t, provider)
+	})
+
+	t.Run("should return an error if client id is empty", func(t *testing.T) {
+		provider, err := NewOIDCProvider(context.Background(), Config{Issuer: "https://accounts.example.com"})
+		assert.EqualError(t, err, "client id is empty")
+		assert.Nil(t, provider)
+	})
+}
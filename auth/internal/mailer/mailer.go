@@ -0,0 +1,100 @@
+// Package mailer provides implementations for delivering emails, either through an SMTP server or, for development,
+// by writing them to a log.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config stores the configuration information required to deliver emails through an SMTP server.
+type Config struct {
+	Host     string // Host is the address of the SMTP server.
+	Port     string // Port is the port number to connect to on the SMTP server.
+	Username string // Username is the username used to authenticate against the SMTP server (leave blank if none).
+	Password string // Password is the password used to authenticate against the SMTP server.
+	From     string // From is the address emails are sent from.
+}
+
+// SMTPMailer delivers emails through an SMTP server.
+type SMTPMailer struct {
+	config Config
+}
+
+// NewSMTPMailer creates a new SMTPMailer with the provided configuration.
+//
+// Returns an error if the host or the sender address is empty.
+func NewSMTPMailer(config Config) (*SMTPMailer, error) {
+	if len(config.Host) == 0 {
+		return nil, fmt.Errorf("host is empty")
+	} else if len(config.From) == 0 {
+		return nil, fmt.Errorf("from is empty")
+	}
+	return &SMTPMailer{config: config}, nil
+}
+
+// SendMail sends a plain text email to the given recipient.
+func (m SMTPMailer) SendMail(ctx context.Context, to, subject, body string) error {
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("invalid context: %w", ctx.Err())
+	default:
+	}
+
+	var auth smtp.Auth
+	if len(m.config.Username) > 0 {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	addr := net.JoinHostPort(m.config.Host, m.config.Port)
+	if err := smtp.SendMail(addr, auth, m.config.From, []string{to}, message(m.config.From, to, subject, body)); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return nil
+}
+
+// LogMailer writes emails to a writer instead of delivering them, it is meant for development.
+type LogMailer struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewLogMailer creates a new LogMailer that writes emails to out.
+func NewLogMailer(out io.Writer) *LogMailer {
+	return &LogMailer{out: out}
+}
+
+// SendMail writes the email to the underlying writer.
+func (m *LogMailer) SendMail(_ context.Context, to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.out.Write(append(message("", to, subject, body), '\n')); err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+	return nil
+}
+
+func message(from, to, subject, body string) []byte {
+	var b bytes.Buffer
+	if len(from) > 0 {
+		b.WriteString("From: " + from + "\r\n")
+	}
+	b.WriteString("To: " + to + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().UTC().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
@@ -0,0 +1,54 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSMTPMailer(t *testing.T) {
+	t.Run("should create a new smtp mailer", func(t *testing.T) {
+		mailer, err := NewSMTPMailer(Config{Host: "localhost", Port: "25", From: "no-reply@unotes.local"})
+		assert.NoError(t, err)
+		assert.NotNil(t, mailer)
+	})
+
+	t.Run("should return an error if host is empty", func(t *testing.T) {
+		mailer, err := NewSMTPMailer(Config{From: "no-reply@unotes.local"})
+		assert.EqualError(t, err, "host is empty")
+		assert.Nil(t, mailer)
+	})
+
+	t.Run("should return an error if from is empty", func(t *testing.T) {
+		mailer, err := NewSMTPMailer(Config{Host: "localhost"})
+		assert.EqualError(t, err, "from is empty")
+		assert.Nil(t, mailer)
+	})
+}
+
+func TestSMTPMailer_SendMail(t *testing.T) {
+	t.Run("should return an error if context is invalid", func(t *testing.T) {
+		mailer, err := NewSMTPMailer(Config{Host: "localhost", Port: "25", From: "no-reply@unotes.local"})
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err = mailer.SendMail(ctx, "user@unotes.local", "Subject", "Body")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestLogMailer_SendMail(t *testing.T) {
+	var out bytes.Buffer
+	mailer := NewLogMailer(&out)
+
+	err := mailer.SendMail(context.Background(), "user@unotes.local", "Reset your password", "Line 1\nLine 2")
+	assert.NoError(t, err)
+
+	assert.Contains(t, out.String(), "To: user@unotes.local\r\n")
+	assert.Contains(t, out.String(), "Subject: Reset your password\r\n")
+	assert.Contains(t, out.String(), "\r\n\r\nLine 1\r\nLine 2\r\n")
+}
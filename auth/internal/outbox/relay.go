@@ -0,0 +1,100 @@
+// Package outbox relays the messages saved to the outbox to the message bus.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainoutbox "github.com/nazarslota/unotes/auth/internal/domain/outbox"
+)
+
+type MessageFinder interface {
+	FindOutboxMessages(ctx context.Context, limit int) ([]domainoutbox.Message, error)
+}
+
+type MessageDeleter interface {
+	DeleteOutboxMessage(ctx context.Context, messageID string) error
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+}
+
+type Logger interface {
+	WarnFields(msg string, fields map[string]any)
+}
+
+// batchSize is the number of messages read from the outbox at once.
+const batchSize = 100
+
+// Relay publishes the messages in the outbox and deletes them once they are published. A message is deleted only
+// after it is published, so a message may be published more than once if the relay fails in between, and subscribers
+// must handle messages idempotently.
+type Relay struct {
+	MessageFinder  MessageFinder
+	MessageDeleter MessageDeleter
+	Publisher      Publisher
+
+	Interval time.Duration
+	Logger   Logger
+}
+
+// NewRelay creates a new Relay that checks the outbox for messages every interval.
+func NewRelay(
+	messageFinder MessageFinder, messageDeleter MessageDeleter, publisher Publisher,
+	interval time.Duration, logger Logger,
+) *Relay {
+	return &Relay{
+		MessageFinder:  messageFinder,
+		MessageDeleter: messageDeleter,
+		Publisher:      publisher,
+
+		Interval: interval,
+		Logger:   logger,
+	}
+}
+
+// Run relays messages until the context is done. Failures are logged and retried at the next interval.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Relay(ctx); err != nil && ctx.Err() == nil {
+			r.Logger.WarnFields("Failed to relay outbox messages.", map[string]any{"error": err})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Relay publishes all messages in the outbox, oldest first, and returns the number of published messages. It stops
+// at the first message that cannot be published, so that messages are not published out of order.
+func (r *Relay) Relay(ctx context.Context) (int, error) {
+	var published int
+	for {
+		messages, err := r.MessageFinder.FindOutboxMessages(ctx, batchSize)
+		if err != nil {
+			return published, fmt.Errorf("failed to find outbox messages: %w", err)
+		}
+
+		for _, message := range messages {
+			if err := r.Publisher.Publish(ctx, message.Subject, message.Payload); err != nil {
+				return published, fmt.Errorf("failed to publish message: %w", err)
+			}
+			if err := r.MessageDeleter.DeleteOutboxMessage(ctx, message.ID); err != nil {
+				return published, fmt.Errorf("failed to delete outbox message: %w", err)
+			}
+			published++
+		}
+
+		if len(messages) < batchSize {
+			return published, nil
+		}
+	}
+}
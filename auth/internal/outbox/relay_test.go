@@ -0,0 +1,101 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	domainoutbox "github.com/nazarslota/unotes/auth/internal/domain/outbox"
+	"github.com/nazarslota/unotes/auth/pkg/messagebus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryOutbox keeps messages in the order they were saved.
+type memoryOutbox struct {
+	messages []domainoutbox.Message
+}
+
+func (o *memoryOutbox) FindOutboxMessages(_ context.Context, limit int) ([]domainoutbox.Message, error) {
+	if len(o.messages) < limit {
+		limit = len(o.messages)
+	}
+	return append([]domainoutbox.Message(nil), o.messages[:limit]...), nil
+}
+
+func (o *memoryOutbox) DeleteOutboxMessage(_ context.Context, messageID string) error {
+	for i, message := range o.messages {
+		if message.ID == messageID {
+			o.messages = append(o.messages[:i], o.messages[i+1:]...)
+			return nil
+		}
+	}
+	return domainoutbox.ErrMessageNotFound
+}
+
+type failingPublisher struct {
+	publisher Publisher
+	failAt    int
+	calls     int
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, subject string, data []byte) error {
+	p.calls++
+	if p.calls == p.failAt {
+		return errors.New("bus is down")
+	}
+	return p.publisher.Publish(ctx, subject, data)
+}
+
+func TestRelay_Relay(t *testing.T) {
+	newOutbox := func(n int) *memoryOutbox {
+		outbox := new(memoryOutbox)
+		for i := 0; i < n; i++ {
+			outbox.messages = append(outbox.messages, domainoutbox.Message{
+				ID:      fmt.Sprint(i),
+				Subject: "subject",
+				Payload: []byte(fmt.Sprint(i)),
+			})
+		}
+		return outbox
+	}
+
+	subscribe := func(t *testing.T, bus *messagebus.MemoryBus) *[]string {
+		received := new([]string)
+		_, err := bus.Subscribe(context.Background(), "subject", func(_ context.Context, message messagebus.Message) {
+			*received = append(*received, string(message.Data))
+		})
+		require.NoError(t, err)
+		return received
+	}
+
+	t.Run("should publish and delete all messages in order", func(t *testing.T) {
+		outbox, bus := newOutbox(batchSize+1), messagebus.NewMemoryBus()
+		received := subscribe(t, bus)
+
+		published, err := NewRelay(outbox, outbox, bus, 0, nil).Relay(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, batchSize+1, published)
+		assert.Empty(t, outbox.messages)
+		require.Len(t, *received, batchSize+1)
+		assert.Equal(t, "0", (*received)[0])
+		assert.Equal(t, fmt.Sprint(batchSize), (*received)[batchSize])
+	})
+
+	t.Run("should keep messages that were not published", func(t *testing.T) {
+		outbox, bus := newOutbox(3), messagebus.NewMemoryBus()
+		received := subscribe(t, bus)
+		publisher := &failingPublisher{publisher: bus, failAt: 2}
+
+		published, err := NewRelay(outbox, outbox, publisher, 0, nil).Relay(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, 1, published)
+		assert.Len(t, outbox.messages, 2)
+
+		published, err = NewRelay(outbox, outbox, publisher, 0, nil).Relay(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, published)
+		assert.Equal(t, []string{"0", "1", "2"}, *received)
+	})
+}
@@ -0,0 +1,270 @@
+// Package passkey provides the WebAuthn relying party users register passkeys with and sign in with them, either
+// instead of a password or as a second factor.
+package passkey
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	domainpasskey "github.com/nazarslota/unotes/auth/internal/domain/passkey"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+)
+
+// Config stores the configuration information of the relying party.
+type Config struct {
+	RPID          string   // RPID is the domain passkeys are scoped to, such as example.com. It can't be an IP address.
+	RPDisplayName string   // RPDisplayName is the name of the service authenticators show to users.
+	RPOrigins     []string // RPOrigins are the origins of the web applications allowed to use the passkeys.
+}
+
+// RelyingParty runs the WebAuthn registration and authentication ceremonies, verifying attestations and assertions of
+// authenticators. Options and responses are the JSON the browser passes to and gets from the WebAuthn API, session
+// data is opaque JSON to store until the ceremony finishes.
+type RelyingParty struct {
+	webAuthn *webauthn.WebAuthn
+}
+
+// NewRelyingParty creates a new RelyingParty.
+//
+// Returns an error if the relying party ID is empty or an IP address, or if no origins are configured.
+func NewRelyingParty(config Config) (*RelyingParty, error) {
+	if len(config.RPID) == 0 {
+		return nil, fmt.Errorf("rp id is empty")
+	} else if net.ParseIP(config.RPID) != nil {
+		return nil, fmt.Errorf("rp id is an ip address")
+	} else if len(config.RPOrigins) == 0 {
+		return nil, fmt.Errorf("rp origins are empty")
+	}
+
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          config.RPID,
+		RPDisplayName: config.RPDisplayName,
+		RPOrigins:     config.RPOrigins,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create relying party: %w", err)
+	}
+	return &RelyingParty{webAuthn: webAuthn}, nil
+}
+
+// BeginRegistration starts registering a new passkey of a user. The authenticator is asked for a discoverable
+// credential and to verify the user, so that the passkey can be used on its own to sign in. Credentials are the
+// passkeys the user already has, authenticators holding one of them are excluded.
+func (rp RelyingParty) BeginRegistration(
+	user domainuser.User, credentials []domainpasskey.Credential,
+) (options []byte, session []byte, err error) {
+	webAuthnUser, err := newUser(user, credentials)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(webAuthnUser.credentials))
+	for _, credential := range webAuthnUser.credentials {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+
+	creation, sessionData, err := rp.webAuthn.BeginRegistration(webAuthnUser,
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			UserVerification:   protocol.VerificationRequired,
+		}),
+		webauthn.WithExclusions(exclusions),
+		webauthn.WithConveyancePreference(protocol.PreferNoAttestation),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin registration: %w", err)
+	}
+	return marshal(creation, sessionData)
+}
+
+// FinishRegistration verifies the attestation the authenticator responded with to a registration begun for the user
+// and returns the new passkey. The caller names it and sets the time it was created.
+func (rp RelyingParty) FinishRegistration(
+	user domainuser.User, session []byte, response []byte,
+) (domainpasskey.Credential, error) {
+	webAuthnUser, err := newUser(user, nil)
+	if err != nil {
+		return domainpasskey.Credential{}, err
+	}
+
+	var sessionData webauthn.SessionData
+	if err := json.Unmarshal(session, &sessionData); err != nil {
+		return domainpasskey.Credential{}, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return domainpasskey.Credential{}, fmt.Errorf("failed to parse response: %w", describe(err))
+	}
+
+	credential, err := rp.webAuthn.CreateCredential(webAuthnUser, sessionData, parsed)
+	if err != nil {
+		return domainpasskey.Credential{}, fmt.Errorf("failed to verify attestation: %w", describe(err))
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+
+	return domainpasskey.Credential{
+		ID:         base64.RawURLEncoding.EncodeToString(credential.ID),
+		UserID:     user.ID,
+		PublicKey:  credential.PublicKey,
+		SignCount:  credential.Authenticator.SignCount,
+		Transports: transports,
+	}, nil
+}
+
+// BeginLogin starts signing in a user with one of their passkeys, as a second factor. The user is verified if the
+// authenticator is able to.
+func (rp RelyingParty) BeginLogin(
+	user domainuser.User, credentials []domainpasskey.Credential,
+) (options []byte, session []byte, err error) {
+	webAuthnUser, err := newUser(user, credentials)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	assertion, sessionData, err := rp.webAuthn.BeginLogin(webAuthnUser,
+		webauthn.WithUserVerification(protocol.VerificationPreferred),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin login: %w", err)
+	}
+	return marshal(assertion, sessionData)
+}
+
+// BeginDiscoverableLogin starts signing in with a passkey on its own, without a password. The user is not known until
+// the authenticator responds with a discoverable credential, and is required to be verified by it.
+func (rp RelyingParty) BeginDiscoverableLogin() (options []byte, session []byte, err error) {
+	assertion, sessionData, err := rp.webAuthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin login: %w", err)
+	}
+	return marshal(assertion, sessionData)
+}
+
+// CredentialID returns the ID of the passkey the authenticator responded to a login with, so that the caller can find
+// the passkey, and the user it belongs to, before finishing the login. The response is not verified.
+func (rp RelyingParty) CredentialID(response []byte) (string, error) {
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", describe(err))
+	}
+	return base64.RawURLEncoding.EncodeToString(parsed.RawID), nil
+}
+
+// FinishLogin verifies the assertion the authenticator responded with to a login begun by BeginLogin or
+// BeginDiscoverableLogin. User is the owner of the passkey and credentials are all of their passkeys. Returns the
+// passkey used with its signature counter updated.
+//
+// Returns an error if the signature counter did not increase, which signals a cloned authenticator.
+func (rp RelyingParty) FinishLogin(
+	user domainuser.User, credentials []domainpasskey.Credential, session []byte, response []byte,
+) (domainpasskey.Credential, error) {
+	webAuthnUser, err := newUser(user, credentials)
+	if err != nil {
+		return domainpasskey.Credential{}, err
+	}
+
+	var sessionData webauthn.SessionData
+	if err := json.Unmarshal(session, &sessionData); err != nil {
+		return domainpasskey.Credential{}, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return domainpasskey.Credential{}, fmt.Errorf("failed to parse response: %w", describe(err))
+	}
+
+	var credential *webauthn.Credential
+	if sessionData.UserID == nil {
+		credential, err = rp.webAuthn.ValidateDiscoverableLogin(
+			func(_, _ []byte) (webauthn.User, error) { return webAuthnUser, nil }, sessionData, parsed,
+		)
+	} else {
+		credential, err = rp.webAuthn.ValidateLogin(webAuthnUser, sessionData, parsed)
+	}
+	if err != nil {
+		return domainpasskey.Credential{}, fmt.Errorf("failed to verify assertion: %w", describe(err))
+	} else if credential.Authenticator.CloneWarning {
+		return domainpasskey.Credential{}, errors.New("signature counter did not increase")
+	}
+
+	id := base64.RawURLEncoding.EncodeToString(credential.ID)
+	for _, used := range credentials {
+		if used.ID == id {
+			used.SignCount = credential.Authenticator.SignCount
+			return used, nil
+		}
+	}
+	return domainpasskey.Credential{}, errors.New("credential not found")
+}
+
+// user adapts a user and their passkeys to the webauthn.User interface. The user handle is the user ID.
+type user struct {
+	user        domainuser.User
+	credentials []webauthn.Credential
+}
+
+func newUser(u domainuser.User, credentials []domainpasskey.Credential) (user, error) {
+	webAuthnCredentials := make([]webauthn.Credential, 0, len(credentials))
+	for _, credential := range credentials {
+		id, err := base64.RawURLEncoding.DecodeString(credential.ID)
+		if err != nil {
+			return user{}, fmt.Errorf("failed to decode credential id: %w", err)
+		}
+
+		transports := make([]protocol.AuthenticatorTransport, 0, len(credential.Transports))
+		for _, transport := range credential.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+
+		webAuthnCredentials = append(webAuthnCredentials, webauthn.Credential{
+			ID:            id,
+			PublicKey:     credential.PublicKey,
+			Transport:     transports,
+			Authenticator: webauthn.Authenticator{SignCount: credential.SignCount},
+		})
+	}
+	return user{user: u, credentials: webAuthnCredentials}, nil
+}
+
+func (u user) WebAuthnID() []byte                         { return []byte(u.user.ID) }
+func (u user) WebAuthnName() string                       { return u.user.Username }
+func (u user) WebAuthnDisplayName() string                { return u.user.Username }
+func (u user) WebAuthnCredentials() []webauthn.Credential { return u.credentials }
+func (u user) WebAuthnIcon() string                       { return "" }
+
+// marshal marshals the options to pass to the browser and the session data to store.
+func marshal(options any, sessionData *webauthn.SessionData) ([]byte, []byte, error) {
+	rawOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal options: %w", err)
+	}
+
+	rawSession, err := json.Marshal(sessionData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal session: %w", err)
+	}
+	return rawOptions, rawSession, nil
+}
+
+// describe adds the details of a protocol error to its message, which on its own only names the kind of error.
+func describe(err error) error {
+	protocolErr := new(protocol.Error)
+	if errors.As(err, &protocolErr) && len(protocolErr.DevInfo) > 0 {
+		return fmt.Errorf("%w: %s", err, protocolErr.DevInfo)
+	}
+	return err
+}
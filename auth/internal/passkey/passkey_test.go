@@ -0,0 +1,134 @@
+package passkey
+
+import (
+	"testing"
+
+	domainpasskey "github.com/nazarslota/unotes/auth/internal/domain/passkey"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/internal/passkey/passkeytest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRelyingParty(t *testing.T) {
+	t.Run("should return an error if rp id is empty", func(t *testing.T) {
+		rp, err := NewRelyingParty(Config{RPDisplayName: "unotes", RPOrigins: []string{"https://unotes.example"}})
+		assert.EqualError(t, err, "rp id is empty")
+		assert.Nil(t, rp)
+	})
+
+	t.Run("should return an error if rp id is an ip address", func(t *testing.T) {
+		rp, err := NewRelyingParty(Config{
+			RPID: "127.0.0.1", RPDisplayName: "unotes", RPOrigins: []string{"https://127.0.0.1"},
+		})
+		assert.EqualError(t, err, "rp id is an ip address")
+		assert.Nil(t, rp)
+	})
+
+	t.Run("should return an error if rp origins are empty", func(t *testing.T) {
+		rp, err := NewRelyingParty(Config{RPID: "unotes.example", RPDisplayName: "unotes"})
+		assert.EqualError(t, err, "rp origins are empty")
+		assert.Nil(t, rp)
+	})
+}
+
+func TestRelyingParty(t *testing.T) {
+	rp, err := NewRelyingParty(Config{
+		RPID: "unotes.example", RPDisplayName: "unotes", RPOrigins: []string{"https://unotes.example"},
+	})
+	require.NoError(t, err)
+
+	user := domainuser.User{ID: "5b0e2c1a-7d3f-4e8b-9a6c-1f2e3d4c5b6a", Username: "username"}
+
+	register := func(t *testing.T, authenticator *passkeytest.Authenticator) domainpasskey.Credential {
+		options, session, err := rp.BeginRegistration(user, nil)
+		require.NoError(t, err)
+
+		response, err := authenticator.Register(options)
+		require.NoError(t, err)
+
+		credential, err := rp.FinishRegistration(user, session, response)
+		require.NoError(t, err)
+		return credential
+	}
+
+	t.Run("should register passkey and sign in with it", func(t *testing.T) {
+		authenticator := passkeytest.NewAuthenticator("https://unotes.example")
+		credential := register(t, authenticator)
+		assert.NotEmpty(t, credential.ID)
+		assert.Equal(t, user.ID, credential.UserID)
+		assert.NotEmpty(t, credential.PublicKey)
+		assert.Equal(t, []string{"internal"}, credential.Transports)
+
+		options, session, err := rp.BeginLogin(user, []domainpasskey.Credential{credential})
+		require.NoError(t, err)
+
+		response, err := authenticator.Login(options)
+		require.NoError(t, err)
+
+		used, err := rp.FinishLogin(user, []domainpasskey.Credential{credential}, session, response)
+		require.NoError(t, err)
+		assert.Equal(t, credential.ID, used.ID)
+		assert.Equal(t, uint32(1), used.SignCount)
+	})
+
+	t.Run("should sign in with discoverable passkey", func(t *testing.T) {
+		authenticator := passkeytest.NewAuthenticator("https://unotes.example")
+		credential := register(t, authenticator)
+
+		options, session, err := rp.BeginDiscoverableLogin()
+		require.NoError(t, err)
+
+		response, err := authenticator.Login(options)
+		require.NoError(t, err)
+
+		credentialID, err := rp.CredentialID(response)
+		require.NoError(t, err)
+		assert.Equal(t, credential.ID, credentialID)
+
+		_, err = rp.FinishLogin(user, []domainpasskey.Credential{credential}, session, response)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject registration from another origin", func(t *testing.T) {
+		options, session, err := rp.BeginRegistration(user, nil)
+		require.NoError(t, err)
+
+		response, err := passkeytest.NewAuthenticator("https://evil.example").Register(options)
+		require.NoError(t, err)
+
+		_, err = rp.FinishRegistration(user, session, response)
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject assertion signed for another challenge", func(t *testing.T) {
+		authenticator := passkeytest.NewAuthenticator("https://unotes.example")
+		credential := register(t, authenticator)
+
+		options, _, err := rp.BeginLogin(user, []domainpasskey.Credential{credential})
+		require.NoError(t, err)
+		_, session, err := rp.BeginLogin(user, []domainpasskey.Credential{credential})
+		require.NoError(t, err)
+
+		response, err := authenticator.Login(options)
+		require.NoError(t, err)
+
+		_, err = rp.FinishLogin(user, []domainpasskey.Credential{credential}, session, response)
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject cloned authenticator", func(t *testing.T) {
+		authenticator := passkeytest.NewAuthenticator("https://unotes.example")
+		credential := register(t, authenticator)
+		credential.SignCount = 5
+
+		options, session, err := rp.BeginLogin(user, []domainpasskey.Credential{credential})
+		require.NoError(t, err)
+
+		response, err := authenticator.Login(options)
+		require.NoError(t, err)
+
+		_, err = rp.FinishLogin(user, []domainpasskey.Credential{credential}, session, response)
+		assert.EqualError(t, err, "signature counter did not increase")
+	})
+}
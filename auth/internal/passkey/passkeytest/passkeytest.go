@@ -0,0 +1,238 @@
+// Package passkeytest provides a software WebAuthn authenticator, so that passkeys can be registered and used in tests
+// without hardware. It plays the part of both the browser and the authenticator: it takes the options the relying
+// party passes to the WebAuthn API and returns the JSON the browser would send back.
+package passkeytest
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+// Flags of the authenticator data.
+const (
+	flagUserPresent            = 0x01
+	flagUserVerified           = 0x04
+	flagAttestedCredentialData = 0x40
+)
+
+// Authenticator is a software authenticator holding discoverable ECDSA P-256 credentials that always verifies the
+// user. It is not safe for concurrent use.
+type Authenticator struct {
+	origin      string
+	credentials []*credential
+}
+
+type credential struct {
+	id         []byte
+	rpID       string
+	userHandle []byte
+	key        *ecdsa.PrivateKey
+	signCount  uint32
+}
+
+// NewAuthenticator creates a new Authenticator without credentials, used from a browser at origin.
+func NewAuthenticator(origin string) *Authenticator {
+	return &Authenticator{origin: origin}
+}
+
+// creationOptions is the part of the options of navigator.credentials.create the authenticator needs.
+type creationOptions struct {
+	PublicKey struct {
+		Challenge string `json:"challenge"`
+		RP        struct {
+			ID string `json:"id"`
+		} `json:"rp"`
+		User struct {
+			ID protocol.URLEncodedBase64 `json:"id"`
+		} `json:"user"`
+	} `json:"publicKey"`
+}
+
+// requestOptions is the part of the options of navigator.credentials.get the authenticator needs.
+type requestOptions struct {
+	PublicKey struct {
+		Challenge        string `json:"challenge"`
+		RPID             string `json:"rpId"`
+		AllowCredentials []struct {
+			ID protocol.URLEncodedBase64 `json:"id"`
+		} `json:"allowCredentials"`
+	} `json:"publicKey"`
+}
+
+// Register creates a new credential for the options of a registration and returns the response with a "none"
+// attestation.
+func (a *Authenticator) Register(options []byte) ([]byte, error) {
+	var opts creationOptions
+	if err := json.Unmarshal(options, &opts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal options: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate credential id: %w", err)
+	}
+
+	cred := &credential{id: id, rpID: opts.PublicKey.RP.ID, userHandle: opts.PublicKey.User.ID, key: key}
+	authData, err := cred.authData(true)
+	if err != nil {
+		return nil, err
+	}
+
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal attestation object: %w", err)
+	}
+
+	clientDataJSON, err := a.clientDataJSON(protocol.CreateCeremony, opts.PublicKey.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	a.credentials = append(a.credentials, cred)
+	return json.Marshal(protocol.CredentialCreationResponse{
+		PublicKeyCredential: publicKeyCredential(id),
+		AttestationResponse: protocol.AuthenticatorAttestationResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{ClientDataJSON: clientDataJSON},
+			AttestationObject:     attestationObject,
+			Transports:            []string{string(protocol.Internal)},
+		},
+	})
+}
+
+// Login signs the challenge of the options of a login with a credential of the relying party and returns the
+// response. The credential is the first one allowed by the options, or the first one of the relying party if the
+// options allow any discoverable credential.
+func (a *Authenticator) Login(options []byte) ([]byte, error) {
+	var opts requestOptions
+	if err := json.Unmarshal(options, &opts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal options: %w", err)
+	}
+
+	var cred *credential
+	for _, c := range a.credentials {
+		if c.rpID != opts.PublicKey.RPID {
+			continue
+		}
+
+		allowed := len(opts.PublicKey.AllowCredentials) == 0
+		for _, allowCredential := range opts.PublicKey.AllowCredentials {
+			allowed = allowed || bytes.Equal(allowCredential.ID, c.id)
+		}
+		if allowed {
+			cred = c
+			break
+		}
+	}
+	if cred == nil {
+		return nil, errors.New("no credential")
+	}
+
+	cred.signCount++
+	authData, err := cred.authData(false)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataJSON, err := a.clientDataJSON(protocol.AssertCeremony, opts.PublicKey.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, cred.key, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+
+	return json.Marshal(protocol.CredentialAssertionResponse{
+		PublicKeyCredential: publicKeyCredential(cred.id),
+		AssertionResponse: protocol.AuthenticatorAssertionResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{ClientDataJSON: clientDataJSON},
+			AuthenticatorData:     authData,
+			Signature:             signature,
+			UserHandle:            cred.userHandle,
+		},
+	})
+}
+
+func (a *Authenticator) clientDataJSON(ceremony protocol.CeremonyType, challenge string) ([]byte, error) {
+	clientDataJSON, err := json.Marshal(protocol.CollectedClientData{
+		Type:      ceremony,
+		Challenge: challenge,
+		Origin:    a.origin,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal client data: %w", err)
+	}
+	return clientDataJSON, nil
+}
+
+// authData returns the authenticator data, including the attested credential data with the public key when attested
+// is true.
+func (c *credential) authData(attested bool) ([]byte, error) {
+	rpIDHash := sha256.Sum256([]byte(c.rpID))
+
+	var authData bytes.Buffer
+	authData.Write(rpIDHash[:])
+
+	flags := byte(flagUserPresent | flagUserVerified)
+	if attested {
+		flags |= flagAttestedCredentialData
+	}
+	authData.WriteByte(flags)
+	_ = binary.Write(&authData, binary.BigEndian, c.signCount)
+
+	if !attested {
+		return authData.Bytes(), nil
+	}
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  1, // P-256
+		XCoord: c.key.X.FillBytes(make([]byte, 32)),
+		YCoord: c.key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	authData.Write(make([]byte, 16)) // AAGUID
+	_ = binary.Write(&authData, binary.BigEndian, uint16(len(c.id)))
+	authData.Write(c.id)
+	authData.Write(publicKey)
+	return authData.Bytes(), nil
+}
+
+func publicKeyCredential(id []byte) protocol.PublicKeyCredential {
+	return protocol.PublicKeyCredential{
+		Credential: protocol.Credential{
+			ID:   protocol.URLEncodedBase64(id).String(),
+			Type: string(protocol.PublicKeyCredentialType),
+		},
+		RawID: id,
+	}
+}
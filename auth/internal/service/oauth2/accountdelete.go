@@ -0,0 +1,149 @@
+package oauth2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nazarslota/unotes/auth/api/events"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainoutbox "github.com/nazarslota/unotes/auth/internal/domain/outbox"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+)
+
+// DeleteAccountRequest deletes the account of the signed-in user, who has to enter their password again.
+type DeleteAccountRequest struct {
+	AccessToken string
+	Password    string
+}
+
+type DeleteAccountResponse struct{}
+
+// DeleteAccountRequestHandler deletes the user and publishes a `user.deleted` event through the outbox, so that other
+// services delete the data of the user as well. All sessions of the user are revoked.
+type DeleteAccountRequestHandler interface {
+	Handle(ctx context.Context, request DeleteAccountRequest) (DeleteAccountResponse, error)
+}
+
+type deleteAccountRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	PasswordHasher PasswordHasher
+
+	RefreshTokensDeleter  RefreshTokensDeleter
+	RefreshTokenGetter    RefreshTokenGetter
+	RevokedTokenSaver     RevokedTokenSaver
+	RevokedTokenPublisher RevokedTokenPublisher
+
+	UserFinder    UserFinder
+	UserDeleter   UserDeleter
+	ProfileFinder ProfileFinder
+	BlobDeleter   BlobDeleter
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrDeleteAccountInvalidOrExpiredToken = errDeleteAccountInvalidOrExpiredToken()
+	ErrDeleteAccountInvalidPassword       = errDeleteAccountInvalidPassword()
+	ErrDeleteAccountUserNotFound          = errDeleteAccountUserNotFound()
+)
+
+func errDeleteAccountInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errDeleteAccountInvalidPassword() error       { return errors.New("invalid password") }
+func errDeleteAccountUserNotFound() error          { return domainuser.ErrUserNotFound }
+
+func NewDeleteAccountRequestHandler(
+	accessTokenParser AccessTokenParser,
+	passwordHasher PasswordHasher,
+	refreshTokensDeleter RefreshTokensDeleter, refreshTokenGetter RefreshTokenGetter,
+	revokedTokenSaver RevokedTokenSaver, revokedTokenPublisher RevokedTokenPublisher,
+	userFinder UserFinder, userDeleter UserDeleter, profileFinder ProfileFinder, blobDeleter BlobDeleter,
+	auditLogger AuditLogger,
+) DeleteAccountRequestHandler {
+	return &deleteAccountRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		PasswordHasher: passwordHasher,
+
+		RefreshTokensDeleter:  refreshTokensDeleter,
+		RefreshTokenGetter:    refreshTokenGetter,
+		RevokedTokenSaver:     revokedTokenSaver,
+		RevokedTokenPublisher: revokedTokenPublisher,
+
+		UserFinder:    userFinder,
+		UserDeleter:   userDeleter,
+		ProfileFinder: profileFinder,
+		BlobDeleter:   blobDeleter,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h deleteAccountRequestHandler) Handle(ctx context.Context, request DeleteAccountRequest) (_ DeleteAccountResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeAccountDelete}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return DeleteAccountResponse{}, errors.Join(err, ErrDeleteAccountInvalidOrExpiredToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+
+	user, err := h.UserFinder.FindUserByUserID(ctx, claims.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return DeleteAccountResponse{}, errors.Join(err, ErrDeleteAccountUserNotFound)
+	} else if err != nil {
+		return DeleteAccountResponse{}, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if err := h.PasswordHasher.Verify(request.Password, user.PasswordHash); err != nil {
+		err = fmt.Errorf("failed to compare password hash and password: %w", err)
+		return DeleteAccountResponse{}, errors.Join(err, ErrDeleteAccountInvalidPassword)
+	}
+
+	profile, err := h.ProfileFinder.FindProfile(ctx, user.ID)
+	if err != nil {
+		return DeleteAccountResponse{}, fmt.Errorf("failed to find profile: %w", err)
+	}
+
+	now := time.Now().UTC()
+	payload, err := json.Marshal(events.UserDeleted{UserID: user.ID, DeletedAt: now})
+	if err != nil {
+		return DeleteAccountResponse{}, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	message := domainoutbox.Message{
+		ID:        uuid.New().String(),
+		Subject:   events.SubjectUserDeleted,
+		Payload:   payload,
+		CreatedAt: now,
+	}
+	if err := h.UserDeleter.DeleteUser(ctx, user.ID, message); errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to delete user: %w", err)
+		return DeleteAccountResponse{}, errors.Join(err, ErrDeleteAccountUserNotFound)
+	} else if err != nil {
+		return DeleteAccountResponse{}, fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	// The user is gone, so failing to delete the avatar only leaves an unused blob behind.
+	if len(profile.Avatar) != 0 {
+		_ = h.BlobDeleter.DeleteBlob(ctx, profile.Avatar)
+	}
+
+	err = revokeRefreshTokens(ctx, h.RefreshTokenGetter, h.RefreshTokensDeleter, user.ID, "")
+	if err != nil {
+		return DeleteAccountResponse{}, err
+	}
+
+	// Other access tokens of the user expire on their own, the one used here is revoked right away.
+	if err := revokeAccessToken(ctx, h.RevokedTokenSaver, h.RevokedTokenPublisher, claims); err != nil {
+		return DeleteAccountResponse{}, err
+	}
+	return DeleteAccountResponse{}, nil
+}
@@ -0,0 +1,686 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainpasswordreset "github.com/nazarslota/unotes/auth/internal/domain/passwordreset"
+	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+)
+
+// The request handlers in this file administer the accounts of other users. They are available to users with the
+// admin role only, as carried by the role claim of the access token, so a user promoted or demoted gets their new
+// permissions once their access token is refreshed.
+
+// isAdmin reports whether the access token is issued to an administrator.
+func isAdmin(claims jwt.AccessTokenClaims) bool {
+	return claims.Role == domainuser.RoleAdmin
+}
+
+// findAdministeredUser finds the user an administrator acts on. The user ID column is a UUID, anything else can't
+// match any user.
+func findAdministeredUser(ctx context.Context, userFinder UserFinder, userID string) (domainuser.User, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return domainuser.User{}, domainuser.ErrUserNotFound
+	}
+
+	user, err := userFinder.FindUserByUserID(ctx, userID)
+	if err != nil {
+		return domainuser.User{}, fmt.Errorf("failed to find user: %w", err)
+	}
+	return user, nil
+}
+
+// AdministeredUser is a user as listed to administrators.
+type AdministeredUser struct {
+	ID            string
+	Username      string
+	Email         string
+	EmailVerified bool
+	Role          string
+	Disabled      bool
+}
+
+func newAdministeredUser(user domainuser.User) AdministeredUser {
+	role := user.Role
+	if len(role) == 0 {
+		role = domainuser.RoleUser
+	}
+
+	return AdministeredUser{
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		Role:          role,
+		Disabled:      user.Disabled,
+	}
+}
+
+// Limits of the number of users listed at once.
+const (
+	defaultUsersLimit = 50
+	maxUsersLimit     = 500
+)
+
+// usersLimit returns the number of users to list for the requested limit, 0 means the default.
+func usersLimit(limit int) (int, bool) {
+	if limit == 0 {
+		return defaultUsersLimit, true
+	}
+	return limit, limit > 0 && limit <= maxUsersLimit
+}
+
+// ListUsersRequest lists the users whose username or email contains Query, ignoring case, ordered by username. After
+// pages through the users: only users whose username comes after it are listed, it is set to the Next of the previous
+// page. Limit is the maximum number of users, 0 means the default.
+type ListUsersRequest struct {
+	AccessToken string
+
+	Query string
+	After string
+	Limit int
+}
+
+// ListUsersResponse lists a page of users. Next is the After of the next page, it is empty on the last page.
+type ListUsersResponse struct {
+	Users []AdministeredUser
+	Next  string
+}
+
+type ListUsersRequestHandler interface {
+	Handle(ctx context.Context, request ListUsersRequest) (ListUsersResponse, error)
+}
+
+type listUsersRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	UsersFinder UsersFinder
+}
+
+var (
+	ErrListUsersInvalidOrExpiredToken = errListUsersInvalidOrExpiredToken()
+	ErrListUsersPermissionDenied      = errListUsersPermissionDenied()
+	ErrListUsersInvalidLimit          = errListUsersInvalidLimit()
+)
+
+func errListUsersInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errListUsersPermissionDenied() error      { return errors.New("permission denied") }
+func errListUsersInvalidLimit() error          { return errors.New("invalid limit") }
+
+func NewListUsersRequestHandler(
+	accessTokenParser AccessTokenParser,
+	usersFinder UsersFinder,
+) ListUsersRequestHandler {
+	return &listUsersRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		UsersFinder: usersFinder,
+	}
+}
+
+func (h listUsersRequestHandler) Handle(ctx context.Context, request ListUsersRequest) (ListUsersResponse, error) {
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return ListUsersResponse{}, errors.Join(err, ErrListUsersInvalidOrExpiredToken)
+	} else if !isAdmin(claims) {
+		return ListUsersResponse{}, ErrListUsersPermissionDenied
+	}
+
+	limit, ok := usersLimit(request.Limit)
+	if !ok {
+		return ListUsersResponse{}, ErrListUsersInvalidLimit
+	}
+
+	// One more user than asked for is found to tell whether there is a next page.
+	users, err := h.UsersFinder.FindUsers(ctx, domainuser.Filter{
+		Query: request.Query,
+		After: request.After,
+		Limit: limit + 1,
+	})
+	if err != nil {
+		return ListUsersResponse{}, fmt.Errorf("failed to find users: %w", err)
+	}
+
+	response := ListUsersResponse{Users: make([]AdministeredUser, 0, min(len(users), limit))}
+	if len(users) > limit {
+		users = users[:limit]
+		response.Next = users[limit-1].Username
+	}
+	for _, user := range users {
+		response.Users = append(response.Users, newAdministeredUser(user))
+	}
+	return response, nil
+}
+
+// UserSession is a session of a user, one for every refresh token they hold. ClientID and Scope are set for sessions
+// of OAuth2 clients.
+type UserSession struct {
+	SessionID string
+	ClientID  string
+	Scope     string
+	ExpiresAt time.Time
+}
+
+// ListUserSessionsRequest lists the sessions of the user with the given user ID, the one that expires last first.
+type ListUserSessionsRequest struct {
+	AccessToken string
+	UserID      string
+}
+
+type ListUserSessionsResponse struct {
+	Sessions []UserSession
+}
+
+type ListUserSessionsRequestHandler interface {
+	Handle(ctx context.Context, request ListUserSessionsRequest) (ListUserSessionsResponse, error)
+}
+
+type listUserSessionsRequestHandler struct {
+	AccessTokenParser  AccessTokenParser
+	RefreshTokenParser RefreshTokenParser
+
+	RefreshTokenGetter RefreshTokenGetter
+
+	UserFinder UserFinder
+}
+
+var (
+	ErrListUserSessionsInvalidOrExpiredToken = errListUserSessionsInvalidOrExpiredToken()
+	ErrListUserSessionsPermissionDenied      = errListUserSessionsPermissionDenied()
+	ErrListUserSessionsUserNotFound          = errListUserSessionsUserNotFound()
+)
+
+func errListUserSessionsInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errListUserSessionsPermissionDenied() error      { return errors.New("permission denied") }
+func errListUserSessionsUserNotFound() error          { return errors.New("user not found") }
+
+func NewListUserSessionsRequestHandler(
+	accessTokenParser AccessTokenParser, refreshTokenParser RefreshTokenParser,
+	refreshTokenGetter RefreshTokenGetter,
+	userFinder UserFinder,
+) ListUserSessionsRequestHandler {
+	return &listUserSessionsRequestHandler{
+		AccessTokenParser:  accessTokenParser,
+		RefreshTokenParser: refreshTokenParser,
+
+		RefreshTokenGetter: refreshTokenGetter,
+
+		UserFinder: userFinder,
+	}
+}
+
+func (h listUserSessionsRequestHandler) Handle(
+	ctx context.Context, request ListUserSessionsRequest,
+) (ListUserSessionsResponse, error) {
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return ListUserSessionsResponse{}, errors.Join(err, ErrListUserSessionsInvalidOrExpiredToken)
+	} else if !isAdmin(claims) {
+		return ListUserSessionsResponse{}, ErrListUserSessionsPermissionDenied
+	}
+
+	user, err := findAdministeredUser(ctx, h.UserFinder, request.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		return ListUserSessionsResponse{}, errors.Join(err, ErrListUserSessionsUserNotFound)
+	} else if err != nil {
+		return ListUserSessionsResponse{}, err
+	}
+
+	tokens, err := h.RefreshTokenGetter.GetRefreshTokens(ctx, user.ID)
+	if err != nil && !errors.Is(err, domainrefresh.ErrTokenNotFound) {
+		return ListUserSessionsResponse{}, fmt.Errorf("failed to get refresh tokens: %w", err)
+	}
+
+	// Expired refresh tokens may still be stored until they are cleaned up, they are not sessions anymore.
+	response := ListUserSessionsResponse{Sessions: make([]UserSession, 0, len(tokens))}
+	for _, token := range tokens {
+		claims, err := h.RefreshTokenParser.Parse(string(token))
+		if err != nil || claims.ExpiresAt == nil {
+			continue
+		}
+
+		response.Sessions = append(response.Sessions, UserSession{
+			SessionID: claims.SessionID,
+			ClientID:  claims.ClientID,
+			Scope:     claims.Scope,
+			ExpiresAt: claims.ExpiresAt.Time,
+		})
+	}
+
+	sort.Slice(response.Sessions, func(i, j int) bool {
+		return response.Sessions[i].ExpiresAt.After(response.Sessions[j].ExpiresAt)
+	})
+	return response, nil
+}
+
+// SetUserDisabledRequest disables or enables the user with the given user ID. Disabling a user signs them out of all
+// their sessions, and they can't sign in or refresh their tokens until they are enabled again. Access tokens already
+// issued to them stay valid until they expire. Administrators can't disable themselves.
+type SetUserDisabledRequest struct {
+	AccessToken string
+	UserID      string
+	Disabled    bool
+}
+
+type SetUserDisabledResponse struct{}
+
+type SetUserDisabledRequestHandler interface {
+	Handle(ctx context.Context, request SetUserDisabledRequest) (SetUserDisabledResponse, error)
+}
+
+type setUserDisabledRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	RefreshTokensDeleter RefreshTokensDeleter
+	RefreshTokenGetter   RefreshTokenGetter
+
+	UserFinder          UserFinder
+	UserDisabledUpdater UserDisabledUpdater
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrSetUserDisabledInvalidOrExpiredToken = errSetUserDisabledInvalidOrExpiredToken()
+	ErrSetUserDisabledPermissionDenied      = errSetUserDisabledPermissionDenied()
+	ErrSetUserDisabledUserNotFound          = errSetUserDisabledUserNotFound()
+	ErrSetUserDisabledSelf                  = errSetUserDisabledSelf()
+)
+
+func errSetUserDisabledInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errSetUserDisabledPermissionDenied() error      { return errors.New("permission denied") }
+func errSetUserDisabledUserNotFound() error          { return errors.New("user not found") }
+func errSetUserDisabledSelf() error                  { return errors.New("can't disable yourself") }
+
+func NewSetUserDisabledRequestHandler(
+	accessTokenParser AccessTokenParser,
+	refreshTokensDeleter RefreshTokensDeleter, refreshTokenGetter RefreshTokenGetter,
+	userFinder UserFinder, userDisabledUpdater UserDisabledUpdater,
+	auditLogger AuditLogger,
+) SetUserDisabledRequestHandler {
+	return &setUserDisabledRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		RefreshTokensDeleter: refreshTokensDeleter,
+		RefreshTokenGetter:   refreshTokenGetter,
+
+		UserFinder:          userFinder,
+		UserDisabledUpdater: userDisabledUpdater,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h setUserDisabledRequestHandler) Handle(
+	ctx context.Context, request SetUserDisabledRequest,
+) (_ SetUserDisabledResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeAdminUserEnable}
+	if request.Disabled {
+		event.Type = domainaudit.TypeAdminUserDisable
+	}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return SetUserDisabledResponse{}, errors.Join(err, ErrSetUserDisabledInvalidOrExpiredToken)
+	} else if !isAdmin(claims) {
+		return SetUserDisabledResponse{}, ErrSetUserDisabledPermissionDenied
+	}
+
+	user, err := findAdministeredUser(ctx, h.UserFinder, request.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		return SetUserDisabledResponse{}, errors.Join(err, ErrSetUserDisabledUserNotFound)
+	} else if err != nil {
+		return SetUserDisabledResponse{}, err
+	}
+	event.UserID = user.ID
+
+	if request.Disabled && user.ID == claims.UserID {
+		return SetUserDisabledResponse{}, ErrSetUserDisabledSelf
+	}
+
+	if err := h.UserDisabledUpdater.UpdateUserDisabled(ctx, user.ID, request.Disabled); err != nil {
+		return SetUserDisabledResponse{}, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if request.Disabled {
+		if err := revokeRefreshTokens(ctx, h.RefreshTokenGetter, h.RefreshTokensDeleter, user.ID, ""); err != nil {
+			return SetUserDisabledResponse{}, err
+		}
+	}
+	return SetUserDisabledResponse{}, nil
+}
+
+// ForcePasswordResetRequest forces the user with the given user ID to reset their password: the password is replaced
+// by a random one, the user is signed out of all their sessions and a password reset link is mailed to their verified
+// email. The other ways of signing in, such as passkeys, are left alone.
+type ForcePasswordResetRequest struct {
+	AccessToken string
+	UserID      string
+}
+
+type ForcePasswordResetResponse struct{}
+
+type ForcePasswordResetRequestHandler interface {
+	Handle(ctx context.Context, request ForcePasswordResetRequest) (ForcePasswordResetResponse, error)
+}
+
+type forcePasswordResetRequestHandler struct {
+	PasswordResetURL            string
+	PasswordResetTokenExpiresIn time.Duration
+
+	AccessTokenParser AccessTokenParser
+
+	PasswordHasher PasswordHasher
+
+	RefreshTokensDeleter RefreshTokensDeleter
+	RefreshTokenGetter   RefreshTokenGetter
+
+	UserFinder              UserFinder
+	UserUpdater             UserUpdater
+	PasswordResetTokenSaver PasswordResetTokenSaver
+
+	Mailer Mailer
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrForcePasswordResetInvalidOrExpiredToken = errForcePasswordResetInvalidOrExpiredToken()
+	ErrForcePasswordResetPermissionDenied      = errForcePasswordResetPermissionDenied()
+	ErrForcePasswordResetUserNotFound          = errForcePasswordResetUserNotFound()
+	ErrForcePasswordResetNoVerifiedEmail       = errForcePasswordResetNoVerifiedEmail()
+)
+
+func errForcePasswordResetInvalidOrExpiredToken() error {
+	return errors.New("invalid or expired token")
+}
+
+func errForcePasswordResetPermissionDenied() error { return errors.New("permission denied") }
+func errForcePasswordResetUserNotFound() error     { return errors.New("user not found") }
+func errForcePasswordResetNoVerifiedEmail() error  { return errors.New("user has no verified email") }
+
+func NewForcePasswordResetRequestHandler(
+	passwordResetURL string, passwordResetTokenExpiresIn time.Duration,
+	accessTokenParser AccessTokenParser,
+	passwordHasher PasswordHasher,
+	refreshTokensDeleter RefreshTokensDeleter, refreshTokenGetter RefreshTokenGetter,
+	userFinder UserFinder, userUpdater UserUpdater, passwordResetTokenSaver PasswordResetTokenSaver,
+	mailer Mailer,
+	auditLogger AuditLogger,
+) ForcePasswordResetRequestHandler {
+	return &forcePasswordResetRequestHandler{
+		PasswordResetURL:            passwordResetURL,
+		PasswordResetTokenExpiresIn: passwordResetTokenExpiresIn,
+
+		AccessTokenParser: accessTokenParser,
+
+		PasswordHasher: passwordHasher,
+
+		RefreshTokensDeleter: refreshTokensDeleter,
+		RefreshTokenGetter:   refreshTokenGetter,
+
+		UserFinder:              userFinder,
+		UserUpdater:             userUpdater,
+		PasswordResetTokenSaver: passwordResetTokenSaver,
+
+		Mailer: mailer,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h forcePasswordResetRequestHandler) Handle(
+	ctx context.Context, request ForcePasswordResetRequest,
+) (_ ForcePasswordResetResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeAdminPasswordReset}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return ForcePasswordResetResponse{}, errors.Join(err, ErrForcePasswordResetInvalidOrExpiredToken)
+	} else if !isAdmin(claims) {
+		return ForcePasswordResetResponse{}, ErrForcePasswordResetPermissionDenied
+	}
+
+	user, err := findAdministeredUser(ctx, h.UserFinder, request.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		return ForcePasswordResetResponse{}, errors.Join(err, ErrForcePasswordResetUserNotFound)
+	} else if err != nil {
+		return ForcePasswordResetResponse{}, err
+	}
+	event.UserID = user.ID
+
+	// Without a verified email the user could not set a new password anymore.
+	if len(user.Email) == 0 || !user.EmailVerified {
+		return ForcePasswordResetResponse{}, ErrForcePasswordResetNoVerifiedEmail
+	}
+
+	password, err := newOpaqueToken()
+	if err != nil {
+		return ForcePasswordResetResponse{}, fmt.Errorf("failed to create password: %w", err)
+	}
+	user.PasswordHash, err = h.PasswordHasher.Hash(password)
+	if err != nil {
+		return ForcePasswordResetResponse{}, fmt.Errorf("failed to generate password hash: %w", err)
+	}
+
+	if err := h.UserUpdater.UpdateUser(ctx, user); err != nil {
+		return ForcePasswordResetResponse{}, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if err := revokeRefreshTokens(ctx, h.RefreshTokenGetter, h.RefreshTokensDeleter, user.ID, ""); err != nil {
+		return ForcePasswordResetResponse{}, err
+	}
+
+	token, err := newOpaqueToken()
+	if err != nil {
+		return ForcePasswordResetResponse{}, fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	if err := h.PasswordResetTokenSaver.SavePasswordResetToken(ctx, domainpasswordreset.Token{
+		TokenHash: hashOpaqueToken(token),
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(h.PasswordResetTokenExpiresIn),
+	}); err != nil {
+		return ForcePasswordResetResponse{}, fmt.Errorf("failed to save password reset token: %w", err)
+	}
+
+	link, err := tokenLink(h.PasswordResetURL, token)
+	if err != nil {
+		return ForcePasswordResetResponse{}, err
+	}
+
+	body := fmt.Sprintf("An administrator has reset your password and signed you out. To choose a new password, "+
+		"follow the link below within %s:\n\n%s\n", h.PasswordResetTokenExpiresIn, link)
+	if err := h.Mailer.SendMail(ctx, user.Email, "Reset your password", body); err != nil {
+		return ForcePasswordResetResponse{}, fmt.Errorf("failed to send mail: %w", err)
+	}
+	return ForcePasswordResetResponse{}, nil
+}
+
+// RevokeUserSessionsRequest signs the user with the given user ID out of all their sessions. Access tokens already
+// issued to them stay valid until they expire.
+type RevokeUserSessionsRequest struct {
+	AccessToken string
+	UserID      string
+}
+
+type RevokeUserSessionsResponse struct{}
+
+type RevokeUserSessionsRequestHandler interface {
+	Handle(ctx context.Context, request RevokeUserSessionsRequest) (RevokeUserSessionsResponse, error)
+}
+
+type revokeUserSessionsRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	RefreshTokensDeleter RefreshTokensDeleter
+	RefreshTokenGetter   RefreshTokenGetter
+
+	UserFinder UserFinder
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrRevokeUserSessionsInvalidOrExpiredToken = errRevokeUserSessionsInvalidOrExpiredToken()
+	ErrRevokeUserSessionsPermissionDenied      = errRevokeUserSessionsPermissionDenied()
+	ErrRevokeUserSessionsUserNotFound          = errRevokeUserSessionsUserNotFound()
+)
+
+func errRevokeUserSessionsInvalidOrExpiredToken() error {
+	return errors.New("invalid or expired token")
+}
+
+func errRevokeUserSessionsPermissionDenied() error { return errors.New("permission denied") }
+func errRevokeUserSessionsUserNotFound() error     { return errors.New("user not found") }
+
+func NewRevokeUserSessionsRequestHandler(
+	accessTokenParser AccessTokenParser,
+	refreshTokensDeleter RefreshTokensDeleter, refreshTokenGetter RefreshTokenGetter,
+	userFinder UserFinder,
+	auditLogger AuditLogger,
+) RevokeUserSessionsRequestHandler {
+	return &revokeUserSessionsRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		RefreshTokensDeleter: refreshTokensDeleter,
+		RefreshTokenGetter:   refreshTokenGetter,
+
+		UserFinder: userFinder,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h revokeUserSessionsRequestHandler) Handle(
+	ctx context.Context, request RevokeUserSessionsRequest,
+) (_ RevokeUserSessionsResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeAdminSessionsRevoke}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return RevokeUserSessionsResponse{}, errors.Join(err, ErrRevokeUserSessionsInvalidOrExpiredToken)
+	} else if !isAdmin(claims) {
+		return RevokeUserSessionsResponse{}, ErrRevokeUserSessionsPermissionDenied
+	}
+
+	user, err := findAdministeredUser(ctx, h.UserFinder, request.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		return RevokeUserSessionsResponse{}, errors.Join(err, ErrRevokeUserSessionsUserNotFound)
+	} else if err != nil {
+		return RevokeUserSessionsResponse{}, err
+	}
+	event.UserID = user.ID
+
+	if err := revokeRefreshTokens(ctx, h.RefreshTokenGetter, h.RefreshTokensDeleter, user.ID, ""); err != nil {
+		return RevokeUserSessionsResponse{}, err
+	}
+	return RevokeUserSessionsResponse{}, nil
+}
+
+// SetUserRoleRequest assigns the role, "user" or "admin", to the user with the given user ID. The user gets the
+// permissions of the role once their access token is refreshed. Administrators can't change their own role, so there
+// is always an administrator left.
+type SetUserRoleRequest struct {
+	AccessToken string
+	UserID      string
+	Role        string
+}
+
+type SetUserRoleResponse struct{}
+
+type SetUserRoleRequestHandler interface {
+	Handle(ctx context.Context, request SetUserRoleRequest) (SetUserRoleResponse, error)
+}
+
+type setUserRoleRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	UserFinder      UserFinder
+	UserRoleUpdater UserRoleUpdater
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrSetUserRoleInvalidOrExpiredToken = errSetUserRoleInvalidOrExpiredToken()
+	ErrSetUserRolePermissionDenied      = errSetUserRolePermissionDenied()
+	ErrSetUserRoleUserNotFound          = errSetUserRoleUserNotFound()
+	ErrSetUserRoleInvalidRole           = errSetUserRoleInvalidRole()
+	ErrSetUserRoleSelf                  = errSetUserRoleSelf()
+)
+
+func errSetUserRoleInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errSetUserRolePermissionDenied() error      { return errors.New("permission denied") }
+func errSetUserRoleUserNotFound() error          { return errors.New("user not found") }
+func errSetUserRoleInvalidRole() error           { return errors.New("invalid role") }
+func errSetUserRoleSelf() error                  { return errors.New("can't change your own role") }
+
+func NewSetUserRoleRequestHandler(
+	accessTokenParser AccessTokenParser,
+	userFinder UserFinder, userRoleUpdater UserRoleUpdater,
+	auditLogger AuditLogger,
+) SetUserRoleRequestHandler {
+	return &setUserRoleRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		UserFinder:      userFinder,
+		UserRoleUpdater: userRoleUpdater,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h setUserRoleRequestHandler) Handle(
+	ctx context.Context, request SetUserRoleRequest,
+) (_ SetUserRoleResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeAdminRoleAssign}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return SetUserRoleResponse{}, errors.Join(err, ErrSetUserRoleInvalidOrExpiredToken)
+	} else if !isAdmin(claims) {
+		return SetUserRoleResponse{}, ErrSetUserRolePermissionDenied
+	}
+
+	switch request.Role {
+	case domainuser.RoleUser, domainuser.RoleAdmin:
+	default:
+		return SetUserRoleResponse{}, ErrSetUserRoleInvalidRole
+	}
+
+	user, err := findAdministeredUser(ctx, h.UserFinder, request.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		return SetUserRoleResponse{}, errors.Join(err, ErrSetUserRoleUserNotFound)
+	} else if err != nil {
+		return SetUserRoleResponse{}, err
+	}
+	event.UserID = user.ID
+
+	if user.ID == claims.UserID {
+		return SetUserRoleResponse{}, ErrSetUserRoleSelf
+	}
+
+	if err := h.UserRoleUpdater.UpdateUserRole(ctx, user.ID, request.Role); err != nil {
+		return SetUserRoleResponse{}, fmt.Errorf("failed to update user: %w", err)
+	}
+	return SetUserRoleResponse{}, nil
+}
@@ -0,0 +1,78 @@
+package oauth2
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+)
+
+// Remote describes where a request comes from: the IP address and the user agent of the client. The transport puts
+// it into the context of every request with ContextWithRemote, so that request handlers can record it in the audit
+// log without every request carrying it.
+type Remote struct {
+	IP        string
+	UserAgent string
+}
+
+type remoteKey struct{}
+
+// ContextWithRemote returns a copy of ctx that carries the remote of the request.
+func ContextWithRemote(ctx context.Context, remote Remote) context.Context {
+	return context.WithValue(ctx, remoteKey{}, remote)
+}
+
+// remoteFromContext returns the remote of the request put into ctx, or the zero Remote if there is none.
+func remoteFromContext(ctx context.Context) Remote {
+	remote, _ := ctx.Value(remoteKey{}).(Remote)
+	return remote
+}
+
+// maxAuditUserAgentLength is the number of bytes of the user agent recorded in the audit log, longer user agents are
+// cut.
+const maxAuditUserAgentLength = 512
+
+// recordAuditEvent appends event to the audit log with the outcome of err, the error the request handler returns, and
+// the remote of the request from ctx. The audit log must not fail the request it records, so an error while saving
+// the event is dropped. If logger is nil, nothing is recorded.
+func recordAuditEvent(ctx context.Context, logger AuditLogger, event domainaudit.Event, err error) {
+	if logger == nil {
+		return
+	}
+
+	remote := remoteFromContext(ctx)
+	event.ID = uuid.New().String()
+	event.IP = remote.IP
+	event.UserAgent = remote.UserAgent
+	if len(event.UserAgent) > maxAuditUserAgentLength {
+		event.UserAgent = strings.ToValidUTF8(event.UserAgent[:maxAuditUserAgentLength], "")
+	}
+	event.Outcome = domainaudit.OutcomeSuccess
+	if err != nil {
+		event.Outcome = domainaudit.OutcomeFailure
+		event.Reason = auditReason(err)
+	}
+	event.CreatedAt = time.Now()
+
+	_ = logger.SaveAuditEvent(ctx, event)
+}
+
+// auditReason returns the reason a request failed with err. Request handlers return their errors either on their own
+// or joined after the internal errors that caused them, so the reason is the message of the last joined error.
+// Internal errors, which are wrapped with what failed, are recorded as such only, since users can read the events of
+// their account.
+func auditReason(err error) string {
+	for {
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			errs := e.Unwrap()
+			err = errs[len(errs)-1]
+		case interface{ Unwrap() error }:
+			return "internal error"
+		default:
+			return err.Error()
+		}
+	}
+}
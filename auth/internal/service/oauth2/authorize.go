@@ -0,0 +1,112 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	domainclient "github.com/nazarslota/unotes/auth/internal/domain/client"
+	"golang.org/x/exp/slices"
+)
+
+// AuthorizeRequest is an authorization request of the OAuth2 authorization code flow. PKCE with the S256 method is
+// required from every client. Nonce is the optional OpenID Connect nonce, returned in the ID token.
+type AuthorizeRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Nonce               string
+}
+
+// AuthorizeResponse describes what the user is asked to consent to. RedirectURI is where the user is sent back to,
+// the registered one if the request doesn't specify it.
+type AuthorizeResponse struct {
+	ClientName  string
+	RedirectURI string
+	Scopes      []string
+}
+
+// AuthorizeRequestHandler validates an authorization request before the user is asked for consent.
+// ErrAuthorizeInvalidClient and ErrAuthorizeInvalidRedirectURI mean the user must not be redirected back to the
+// client, any other error is reported to the client through the redirect URI, which the response holds even then.
+type AuthorizeRequestHandler interface {
+	Handle(ctx context.Context, request AuthorizeRequest) (AuthorizeResponse, error)
+}
+
+type authorizeRequestHandler struct {
+	ClientFinder ClientFinder
+}
+
+var (
+	ErrAuthorizeInvalidClient           = errAuthorizeInvalidClient()
+	ErrAuthorizeInvalidRedirectURI      = errAuthorizeInvalidRedirectURI()
+	ErrAuthorizeInvalidRequest          = errAuthorizeInvalidRequest()
+	ErrAuthorizeUnauthorizedClient      = errAuthorizeUnauthorizedClient()
+	ErrAuthorizeUnsupportedResponseType = errAuthorizeUnsupportedResponseType()
+	ErrAuthorizeInvalidScope            = errAuthorizeInvalidScope()
+)
+
+func errAuthorizeInvalidClient() error           { return errors.New("invalid client") }
+func errAuthorizeInvalidRedirectURI() error      { return errors.New("invalid redirect uri") }
+func errAuthorizeInvalidRequest() error          { return errors.New("pkce with s256 is required") }
+func errAuthorizeUnauthorizedClient() error      { return errors.New("unauthorized client") }
+func errAuthorizeUnsupportedResponseType() error { return errors.New("unsupported response type") }
+func errAuthorizeInvalidScope() error            { return errors.New("invalid scope") }
+
+// codeChallengePattern matches a base64url encoded SHA-256 hash, which is what an S256 code challenge is.
+var codeChallengePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`)
+
+func NewAuthorizeRequestHandler(clientFinder ClientFinder) AuthorizeRequestHandler {
+	return &authorizeRequestHandler{ClientFinder: clientFinder}
+}
+
+func (h authorizeRequestHandler) Handle(ctx context.Context, request AuthorizeRequest) (AuthorizeResponse, error) {
+	client, redirectURI, scope, err := authorize(ctx, h.ClientFinder, request)
+	if err != nil {
+		return AuthorizeResponse{RedirectURI: redirectURI}, err
+	}
+	return AuthorizeResponse{ClientName: client.Name, RedirectURI: redirectURI, Scopes: strings.Fields(scope)}, nil
+}
+
+// authorize validates an authorization request and returns the client, the redirect URI and the scope to grant. Once
+// the redirect URI is validated, it is returned along with any error.
+func authorize(ctx context.Context, clientFinder ClientFinder, request AuthorizeRequest) (
+	client domainclient.Client, redirectURI string, scope string, err error,
+) {
+	client, err = clientFinder.FindClientByClientID(ctx, request.ClientID)
+	if errors.Is(err, domainclient.ErrClientNotFound) {
+		err = fmt.Errorf("failed to find client: %w", err)
+		return domainclient.Client{}, "", "", errors.Join(err, ErrAuthorizeInvalidClient)
+	} else if err != nil {
+		return domainclient.Client{}, "", "", fmt.Errorf("failed to find client: %w", err)
+	}
+
+	redirectURI = request.RedirectURI
+	if len(redirectURI) == 0 && len(client.RedirectURIs) == 1 {
+		redirectURI = client.RedirectURIs[0]
+	} else if !slices.Contains(client.RedirectURIs, redirectURI) {
+		return domainclient.Client{}, "", "", ErrAuthorizeInvalidRedirectURI
+	}
+
+	if request.ResponseType != "code" {
+		return domainclient.Client{}, redirectURI, "", ErrAuthorizeUnsupportedResponseType
+	} else if !slices.Contains(client.GrantTypes, GrantTypeAuthorizationCode) {
+		return domainclient.Client{}, redirectURI, "", ErrAuthorizeUnauthorizedClient
+	}
+
+	if request.CodeChallengeMethod != CodeChallengeMethodS256 || !codeChallengePattern.MatchString(request.CodeChallenge) {
+		return domainclient.Client{}, redirectURI, "", ErrAuthorizeInvalidRequest
+	}
+
+	scope, ok := grantedScope(client.Scopes, request.Scope)
+	if !ok {
+		return domainclient.Client{}, redirectURI, "", ErrAuthorizeInvalidScope
+	}
+	return client, redirectURI, scope, nil
+}
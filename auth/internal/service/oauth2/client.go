@@ -0,0 +1,143 @@
+package oauth2
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	domainclient "github.com/nazarslota/unotes/auth/internal/domain/client"
+	domaintotp "github.com/nazarslota/unotes/auth/internal/domain/totp"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	pkgpassword "github.com/nazarslota/unotes/auth/pkg/password"
+	"golang.org/x/exp/slices"
+)
+
+// Grant types a client can be allowed to use at the token endpoint.
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypePassword          = "password"
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+// CodeChallengeMethodS256 is the only supported PKCE code challenge method.
+const CodeChallengeMethodS256 = "S256"
+
+var (
+	errClientNotAuthenticated = errors.New("client is not authenticated")
+	errInvalidCredentials     = errors.New("invalid username or password")
+	errAccountDisabled        = errors.New("account disabled")
+)
+
+// findClient finds a client and, unless it is public, checks its secret.
+//
+// If the client is not found or the secret doesn't match, returns errClientNotAuthenticated.
+func findClient(ctx context.Context, finder ClientFinder, clientID, clientSecret string) (domainclient.Client, error) {
+	client, err := finder.FindClientByClientID(ctx, clientID)
+	if errors.Is(err, domainclient.ErrClientNotFound) {
+		err = fmt.Errorf("failed to find client: %w", err)
+		return domainclient.Client{}, errors.Join(err, errClientNotAuthenticated)
+	} else if err != nil {
+		return domainclient.Client{}, fmt.Errorf("failed to find client: %w", err)
+	}
+
+	if client.Public {
+		return client, nil
+	}
+
+	secretHash := hashOpaqueToken(clientSecret)
+	if len(clientSecret) == 0 || subtle.ConstantTimeCompare([]byte(secretHash), []byte(client.SecretHash)) != 1 {
+		return domainclient.Client{}, errClientNotAuthenticated
+	}
+	return client, nil
+}
+
+// grantedScope returns the scope to grant for the requested one, which defaults to all allowed scopes, or false if
+// any of the requested scopes is not allowed.
+func grantedScope(allowed []string, requested string) (string, bool) {
+	scopes := strings.Fields(requested)
+	if len(scopes) == 0 {
+		return strings.Join(allowed, " "), true
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(allowed, scope) {
+			return "", false
+		}
+	}
+	return strings.Join(scopes, " "), true
+}
+
+// verifyCodeChallenge reports whether a PKCE code verifier matches an S256 code challenge.
+func verifyCodeChallenge(verifier, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// authenticateUser checks the username and password of a user on behalf of a client, counting failed attempts against
+// the lockout policy the same way sign in does.
+//
+// If the username or password is wrong, or the user is disabled, returns errInvalidCredentials. If sign in is locked,
+// returns a SignInLockedError.
+func authenticateUser(
+	ctx context.Context,
+	passwordHasher PasswordHasher,
+	userFinder UserFinder,
+	policy LockoutPolicy,
+	failureSaver SignInFailureSaver, lockSaver SignInLockSaver,
+	lockFinder SignInLockFinder, failuresDeleter SignInFailuresDeleter,
+	username, password, ip string,
+) (domainuser.User, error) {
+	if err := checkSignInLock(ctx, lockFinder, policy, username, ip); err != nil {
+		return domainuser.User{}, err
+	}
+
+	failure := func(err error) error {
+		err = errors.Join(err, errInvalidCredentials)
+		return errors.Join(err, registerSignInFailure(ctx, failureSaver, lockSaver, policy, username, ip))
+	}
+
+	user, err := userFinder.FindUserByUsername(ctx, username)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		return domainuser.User{}, failure(fmt.Errorf("failed to find user: %w", err))
+	} else if err != nil {
+		return domainuser.User{}, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	err = passwordHasher.Verify(password, user.PasswordHash)
+	if errors.Is(err, pkgpassword.ErrMismatchedHashAndPassword) {
+		return domainuser.User{}, failure(fmt.Errorf("failed to compare passwords: %w", err))
+	} else if err != nil {
+		return domainuser.User{}, fmt.Errorf("failed to compare passwords: %w", err)
+	}
+
+	if err := failuresDeleter.DeleteSignInFailures(ctx, usernameLockoutKey(username)); err != nil {
+		return domainuser.User{}, fmt.Errorf("failed to delete sign in failures: %w", err)
+	}
+
+	// Only once the password is verified, so that it doesn't reveal which accounts are disabled.
+	if user.Disabled {
+		return domainuser.User{}, errors.Join(errAccountDisabled, errInvalidCredentials)
+	}
+	return user, nil
+}
+
+// totpEnabled reports whether the user has two-factor authentication enabled.
+func totpEnabled(ctx context.Context, finder TOTPFinder, userID string) (domaintotp.TOTP, bool, error) {
+	totp, err := finder.FindTOTPByUserID(ctx, userID)
+	if errors.Is(err, domaintotp.ErrTOTPNotFound) {
+		return domaintotp.TOTP{}, false, nil
+	} else if err != nil {
+		return domaintotp.TOTP{}, false, fmt.Errorf("failed to find totp: %w", err)
+	}
+	return totp, totp.Enabled, nil
+}
@@ -0,0 +1,143 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainclient "github.com/nazarslota/unotes/auth/internal/domain/client"
+	"golang.org/x/exp/slices"
+)
+
+// RegisterClientRequest registers a new OAuth2 client. It is available to administrators only. If GrantTypes is empty,
+// the client is allowed the authorization code and refresh token grants. A confidential client allowed the client
+// credentials grant is a service account, which acts on its own behalf with the scopes assigned to it.
+type RegisterClientRequest struct {
+	AccessToken string
+
+	Name         string
+	RedirectURIs []string
+	Scopes       []string
+	GrantTypes   []string
+	Public       bool
+}
+
+// RegisterClientResponse holds the credentials of the new client. ClientSecret is shown only once and is empty for
+// public clients.
+type RegisterClientResponse struct {
+	ClientID     string
+	ClientSecret string
+}
+
+type RegisterClientRequestHandler interface {
+	Handle(ctx context.Context, request RegisterClientRequest) (RegisterClientResponse, error)
+}
+
+type registerClientRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	ClientSaver ClientSaver
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrRegisterClientInvalidOrExpiredToken = errRegisterClientInvalidOrExpiredToken()
+	ErrRegisterClientPermissionDenied      = errRegisterClientPermissionDenied()
+	ErrRegisterClientInvalidRedirectURI    = errRegisterClientInvalidRedirectURI()
+	ErrRegisterClientInvalidScope          = errRegisterClientInvalidScope()
+	ErrRegisterClientInvalidGrantType      = errRegisterClientInvalidGrantType()
+)
+
+func errRegisterClientInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errRegisterClientPermissionDenied() error      { return errors.New("permission denied") }
+func errRegisterClientInvalidRedirectURI() error    { return errors.New("invalid redirect uri") }
+func errRegisterClientInvalidScope() error          { return errors.New("invalid scope") }
+func errRegisterClientInvalidGrantType() error      { return errors.New("invalid grant type") }
+
+func NewRegisterClientRequestHandler(
+	accessTokenParser AccessTokenParser,
+	clientSaver ClientSaver,
+	auditLogger AuditLogger,
+) RegisterClientRequestHandler {
+	return &registerClientRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		ClientSaver: clientSaver,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h registerClientRequestHandler) Handle(ctx context.Context, request RegisterClientRequest) (_ RegisterClientResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeClientRegister}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return RegisterClientResponse{}, errors.Join(err, ErrRegisterClientInvalidOrExpiredToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+	if !isAdmin(claims) {
+		return RegisterClientResponse{}, ErrRegisterClientPermissionDenied
+	}
+
+	grantTypes := request.GrantTypes
+	if len(grantTypes) == 0 {
+		grantTypes = []string{GrantTypeAuthorizationCode, GrantTypeRefreshToken}
+	}
+	for _, grantType := range grantTypes {
+		switch grantType {
+		case GrantTypeAuthorizationCode, GrantTypeRefreshToken, GrantTypePassword:
+		case GrantTypeClientCredentials:
+			// A public client can't keep a secret, so anyone could act as it.
+			if request.Public {
+				return RegisterClientResponse{}, ErrRegisterClientInvalidGrantType
+			}
+		default:
+			return RegisterClientResponse{}, ErrRegisterClientInvalidGrantType
+		}
+	}
+
+	if slices.Contains(grantTypes, GrantTypeAuthorizationCode) && len(request.RedirectURIs) == 0 {
+		return RegisterClientResponse{}, ErrRegisterClientInvalidRedirectURI
+	}
+	for _, redirectURI := range request.RedirectURIs {
+		if u, err := url.Parse(redirectURI); err != nil || !u.IsAbs() || len(u.Fragment) != 0 {
+			return RegisterClientResponse{}, ErrRegisterClientInvalidRedirectURI
+		}
+	}
+
+	for _, scope := range request.Scopes {
+		if len(strings.Fields(scope)) != 1 || scope != strings.TrimSpace(scope) {
+			return RegisterClientResponse{}, ErrRegisterClientInvalidScope
+		}
+	}
+
+	client := domainclient.Client{
+		ID:           uuid.New().String(),
+		Name:         request.Name,
+		RedirectURIs: request.RedirectURIs,
+		Scopes:       request.Scopes,
+		GrantTypes:   grantTypes,
+		Public:       request.Public,
+	}
+
+	var secret string
+	if !client.Public {
+		if secret, err = newOpaqueToken(); err != nil {
+			return RegisterClientResponse{}, fmt.Errorf("failed to create client secret: %w", err)
+		}
+		client.SecretHash = hashOpaqueToken(secret)
+	}
+
+	if err := h.ClientSaver.SaveClient(ctx, client); err != nil {
+		return RegisterClientResponse{}, fmt.Errorf("failed to save client: %w", err)
+	}
+	return RegisterClientResponse{ClientID: client.ID, ClientSecret: secret}, nil
+}
@@ -0,0 +1,171 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainauthorizationcode "github.com/nazarslota/unotes/auth/internal/domain/authorizationcode"
+)
+
+// ConsentRequest approves an authorization request. The user authenticates on the consent page itself, so the client
+// never sees their password. Code is the TOTP or a recovery code and is required only if the user has two-factor
+// authentication enabled.
+type ConsentRequest struct {
+	AuthorizeRequest
+
+	Username string
+	Password string
+	Code     string
+	IP       string
+}
+
+// ConsentResponse holds the authorization code to send back to the client at RedirectURI, along with the state of the
+// authorization request.
+type ConsentResponse struct {
+	RedirectURI string
+	Code        string
+	State       string
+}
+
+// ConsentRequestHandler issues an authorization code once the user consents. Besides its own errors it returns the
+// errors of AuthorizeRequestHandler, and a SignInLockedError when sign in is locked.
+type ConsentRequestHandler interface {
+	Handle(ctx context.Context, request ConsentRequest) (ConsentResponse, error)
+}
+
+type consentRequestHandler struct {
+	AuthorizationCodeExpiresIn time.Duration
+
+	LockoutPolicy  LockoutPolicy
+	PasswordHasher PasswordHasher
+
+	ClientFinder           ClientFinder
+	AuthorizationCodeSaver AuthorizationCodeSaver
+
+	UserFinder              UserFinder
+	TOTPFinder              TOTPFinder
+	TOTPLastUsedStepUpdater TOTPLastUsedStepUpdater
+	RecoveryCodeDeleter     RecoveryCodeDeleter
+
+	SignInFailureSaver    SignInFailureSaver
+	SignInLockSaver       SignInLockSaver
+	SignInLockFinder      SignInLockFinder
+	SignInFailuresDeleter SignInFailuresDeleter
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrConsentInvalidCredentials = errConsentInvalidCredentials()
+	ErrConsentMFARequired        = errConsentMFARequired()
+	ErrConsentInvalidCode        = errConsentInvalidCode()
+)
+
+func errConsentInvalidCredentials() error { return errors.New("invalid username or password") }
+func errConsentMFARequired() error        { return errors.New("two-factor code is required") }
+func errConsentInvalidCode() error        { return errors.New("invalid code") }
+
+func NewConsentRequestHandler(
+	authorizationCodeExpiresIn time.Duration,
+	lockoutPolicy LockoutPolicy, passwordHasher PasswordHasher,
+	clientFinder ClientFinder, authorizationCodeSaver AuthorizationCodeSaver,
+	userFinder UserFinder, totpFinder TOTPFinder,
+	totpLastUsedStepUpdater TOTPLastUsedStepUpdater, recoveryCodeDeleter RecoveryCodeDeleter,
+	signInFailureSaver SignInFailureSaver, signInLockSaver SignInLockSaver,
+	signInLockFinder SignInLockFinder, signInFailuresDeleter SignInFailuresDeleter,
+	auditLogger AuditLogger,
+) ConsentRequestHandler {
+	return &consentRequestHandler{
+		AuthorizationCodeExpiresIn: authorizationCodeExpiresIn,
+
+		LockoutPolicy:  lockoutPolicy,
+		PasswordHasher: passwordHasher,
+
+		ClientFinder:           clientFinder,
+		AuthorizationCodeSaver: authorizationCodeSaver,
+
+		UserFinder:              userFinder,
+		TOTPFinder:              totpFinder,
+		TOTPLastUsedStepUpdater: totpLastUsedStepUpdater,
+		RecoveryCodeDeleter:     recoveryCodeDeleter,
+
+		SignInFailureSaver:    signInFailureSaver,
+		SignInLockSaver:       signInLockSaver,
+		SignInLockFinder:      signInLockFinder,
+		SignInFailuresDeleter: signInFailuresDeleter,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h consentRequestHandler) Handle(ctx context.Context, request ConsentRequest) (_ ConsentResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeConsent}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	client, redirectURI, scope, err := authorize(ctx, h.ClientFinder, request.AuthorizeRequest)
+	if err != nil {
+		return ConsentResponse{}, err
+	}
+
+	user, err := authenticateUser(ctx,
+		h.PasswordHasher,
+		h.UserFinder,
+		h.LockoutPolicy,
+		h.SignInFailureSaver, h.SignInLockSaver,
+		h.SignInLockFinder, h.SignInFailuresDeleter,
+		request.Username, request.Password, request.IP,
+	)
+	if errors.Is(err, errInvalidCredentials) {
+		return ConsentResponse{}, errors.Join(err, ErrConsentInvalidCredentials)
+	} else if err != nil {
+		return ConsentResponse{}, err
+	}
+	event.UserID = user.ID
+
+	totp, enabled, err := totpEnabled(ctx, h.TOTPFinder, user.ID)
+	if err != nil {
+		return ConsentResponse{}, err
+	} else if enabled && len(request.Code) == 0 {
+		return ConsentResponse{}, ErrConsentMFARequired
+	} else if enabled {
+		if err := checkSecondFactorLock(ctx, h.SignInLockFinder, h.LockoutPolicy, user.ID, request.IP); err != nil {
+			return ConsentResponse{}, err
+		}
+
+		valid, err := verifySecondFactor(ctx, h.TOTPLastUsedStepUpdater, h.RecoveryCodeDeleter, totp, request.Code)
+		if err != nil {
+			return ConsentResponse{}, err
+		} else if !valid {
+			err := registerSecondFactorFailure(ctx,
+				h.SignInFailureSaver, h.SignInLockSaver, h.LockoutPolicy, user.ID, request.IP)
+			return ConsentResponse{}, errors.Join(ErrConsentInvalidCode, err)
+		}
+
+		if err := h.SignInFailuresDeleter.DeleteSignInFailures(ctx, mfaUserLockoutKey(user.ID)); err != nil {
+			return ConsentResponse{}, fmt.Errorf("failed to delete sign in failures: %w", err)
+		}
+	}
+
+	code, err := newOpaqueToken()
+	if err != nil {
+		return ConsentResponse{}, fmt.Errorf("failed to create authorization code: %w", err)
+	}
+
+	err = h.AuthorizationCodeSaver.SaveAuthorizationCode(ctx, domainauthorizationcode.Code{
+		CodeHash:      hashOpaqueToken(code),
+		ClientID:      client.ID,
+		UserID:        user.ID,
+		RedirectURI:   request.RedirectURI,
+		Scope:         scope,
+		CodeChallenge: request.CodeChallenge,
+		Nonce:         request.Nonce,
+		ExpiresAt:     time.Now().Add(h.AuthorizationCodeExpiresIn),
+	})
+	if err != nil {
+		return ConsentResponse{}, fmt.Errorf("failed to save authorization code: %w", err)
+	}
+	return ConsentResponse{RedirectURI: redirectURI, Code: code, State: request.State}, nil
+}
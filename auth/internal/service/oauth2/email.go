@@ -0,0 +1,68 @@
+package oauth2
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"time"
+
+	domainemailverification "github.com/nazarslota/unotes/auth/internal/domain/emailverification"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+)
+
+// validEmail reports whether the email is a bare address such as "user@example.com", without a display name.
+func validEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email
+}
+
+// tokenLink returns the link with the token appended as the "token" query parameter.
+func tokenLink(link string, token string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse url: %w", err)
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// sendEmailVerification creates a new email verification token for the email and mails the verification link to it.
+// The email is either the user's current email or the one they are changing to, which replaces the current email
+// once the link is followed.
+func sendEmailVerification(
+	ctx context.Context,
+	emailVerificationURL string, emailVerificationTokenExpiresIn time.Duration,
+	emailVerificationTokenSaver EmailVerificationTokenSaver,
+	mailer Mailer,
+	user domainuser.User, email string,
+) error {
+	token, err := newOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("failed to create email verification token: %w", err)
+	}
+
+	if err := emailVerificationTokenSaver.SaveEmailVerificationToken(ctx, domainemailverification.Token{
+		TokenHash: hashOpaqueToken(token),
+		UserID:    user.ID,
+		Email:     email,
+		ExpiresAt: time.Now().Add(emailVerificationTokenExpiresIn),
+	}); err != nil {
+		return fmt.Errorf("failed to save email verification token: %w", err)
+	}
+
+	link, err := tokenLink(emailVerificationURL, token)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hi %s,\n\nTo verify your email address, follow the link below within %s:\n\n%s\n\n"+
+		"If you did not create an account or change your email, you can ignore this email.\n",
+		user.Username, emailVerificationTokenExpiresIn, link)
+	if err := mailer.SendMail(ctx, email, "Verify your email address", body); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return nil
+}
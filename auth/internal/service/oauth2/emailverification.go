@@ -0,0 +1,315 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainemailverification "github.com/nazarslota/unotes/auth/internal/domain/emailverification"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+)
+
+// ChangeEmailRequest sets a new email for the signed-in user, who confirms the change with their password. A verified
+// email is kept until the link mailed to the new email is followed, an unverified one is replaced by the new email
+// right away, which stays unverified until then.
+type ChangeEmailRequest struct {
+	AccessToken string
+	Password    string
+	Email       string
+}
+
+type ChangeEmailResponse struct{}
+
+type ChangeEmailRequestHandler interface {
+	Handle(ctx context.Context, request ChangeEmailRequest) (ChangeEmailResponse, error)
+}
+
+type changeEmailRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+	PasswordHasher    PasswordHasher
+
+	EmailVerificationURL            string
+	EmailVerificationTokenExpiresIn time.Duration
+
+	UserFinder                     UserFinder
+	UserUpdater                    UserUpdater
+	EmailVerificationTokenSaver    EmailVerificationTokenSaver
+	EmailVerificationTokensDeleter EmailVerificationTokensDeleter
+
+	Mailer Mailer
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrChangeEmailInvalidOrExpiredToken = errChangeEmailInvalidOrExpiredToken()
+	ErrChangeEmailInvalidPassword       = errChangeEmailInvalidPassword()
+	ErrChangeEmailInvalidEmail          = errChangeEmailInvalidEmail()
+	ErrChangeEmailEmailAlreadyExists    = errChangeEmailEmailAlreadyExists()
+	ErrChangeEmailUserNotFound          = errChangeEmailUserNotFound()
+)
+
+func errChangeEmailInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errChangeEmailInvalidPassword() error       { return errors.New("invalid password") }
+func errChangeEmailInvalidEmail() error          { return errors.New("invalid email") }
+func errChangeEmailEmailAlreadyExists() error    { return domainuser.ErrEmailAlreadyExists }
+func errChangeEmailUserNotFound() error          { return domainuser.ErrUserNotFound }
+
+func NewChangeEmailRequestHandler(
+	accessTokenParser AccessTokenParser, passwordHasher PasswordHasher,
+	emailVerificationURL string, emailVerificationTokenExpiresIn time.Duration,
+	userFinder UserFinder, userUpdater UserUpdater,
+	emailVerificationTokenSaver EmailVerificationTokenSaver, emailVerificationTokensDeleter EmailVerificationTokensDeleter,
+	mailer Mailer,
+	auditLogger AuditLogger,
+) ChangeEmailRequestHandler {
+	return &changeEmailRequestHandler{
+		AccessTokenParser: accessTokenParser,
+		PasswordHasher:    passwordHasher,
+
+		EmailVerificationURL:            emailVerificationURL,
+		EmailVerificationTokenExpiresIn: emailVerificationTokenExpiresIn,
+
+		UserFinder:                     userFinder,
+		UserUpdater:                    userUpdater,
+		EmailVerificationTokenSaver:    emailVerificationTokenSaver,
+		EmailVerificationTokensDeleter: emailVerificationTokensDeleter,
+
+		Mailer: mailer,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h changeEmailRequestHandler) Handle(ctx context.Context, request ChangeEmailRequest) (_ ChangeEmailResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeEmailChange}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return ChangeEmailResponse{}, errors.Join(err, ErrChangeEmailInvalidOrExpiredToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+
+	if !validEmail(request.Email) {
+		return ChangeEmailResponse{}, ErrChangeEmailInvalidEmail
+	}
+
+	user, err := h.UserFinder.FindUserByUserID(ctx, claims.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return ChangeEmailResponse{}, errors.Join(err, ErrChangeEmailUserNotFound)
+	} else if err != nil {
+		return ChangeEmailResponse{}, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if err := h.PasswordHasher.Verify(request.Password, user.PasswordHash); err != nil {
+		err = fmt.Errorf("failed to compare password hash and password: %w", err)
+		return ChangeEmailResponse{}, errors.Join(err, ErrChangeEmailInvalidPassword)
+	} else if user.Email == request.Email && user.EmailVerified {
+		return ChangeEmailResponse{}, nil
+	}
+
+	if user.EmailVerified {
+		// The new email replaces the verified one once it is verified, but whether it is taken is checked now already.
+		other, err := h.UserFinder.FindUserByEmail(ctx, request.Email)
+		if err == nil && other.ID != user.ID {
+			return ChangeEmailResponse{}, ErrChangeEmailEmailAlreadyExists
+		} else if err != nil && !errors.Is(err, domainuser.ErrUserNotFound) {
+			return ChangeEmailResponse{}, fmt.Errorf("failed to find user: %w", err)
+		}
+	}
+
+	if err := h.EmailVerificationTokensDeleter.DeleteEmailVerificationTokens(ctx, user.ID); err != nil {
+		return ChangeEmailResponse{}, fmt.Errorf("failed to delete email verification tokens: %w", err)
+	}
+
+	if !user.EmailVerified {
+		user.Email = request.Email
+		if err := h.UserUpdater.UpdateUser(ctx, user); err != nil {
+			return ChangeEmailResponse{}, fmt.Errorf("failed to update user: %w", err)
+		}
+	}
+
+	if err := sendEmailVerification(ctx,
+		h.EmailVerificationURL, h.EmailVerificationTokenExpiresIn,
+		h.EmailVerificationTokenSaver,
+		h.Mailer,
+		user, request.Email,
+	); err != nil {
+		return ChangeEmailResponse{}, err
+	}
+	return ChangeEmailResponse{}, nil
+}
+
+type VerifyEmailRequest struct {
+	Token string
+}
+
+type VerifyEmailResponse struct{}
+
+type VerifyEmailRequestHandler interface {
+	Handle(ctx context.Context, request VerifyEmailRequest) (VerifyEmailResponse, error)
+}
+
+type verifyEmailRequestHandler struct {
+	UserFinder                     UserFinder
+	UserUpdater                    UserUpdater
+	EmailVerificationTokenConsumer EmailVerificationTokenConsumer
+	EmailVerificationTokensDeleter EmailVerificationTokensDeleter
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrVerifyEmailInvalidOrExpiredToken = errVerifyEmailInvalidOrExpiredToken()
+	ErrVerifyEmailEmailAlreadyExists    = errVerifyEmailEmailAlreadyExists()
+)
+
+func errVerifyEmailInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errVerifyEmailEmailAlreadyExists() error    { return domainuser.ErrEmailAlreadyExists }
+
+func NewVerifyEmailRequestHandler(
+	userFinder UserFinder, userUpdater UserUpdater,
+	emailVerificationTokenConsumer EmailVerificationTokenConsumer, emailVerificationTokensDeleter EmailVerificationTokensDeleter,
+	auditLogger AuditLogger,
+) VerifyEmailRequestHandler {
+	return &verifyEmailRequestHandler{
+		UserFinder:                     userFinder,
+		UserUpdater:                    userUpdater,
+		EmailVerificationTokenConsumer: emailVerificationTokenConsumer,
+		EmailVerificationTokensDeleter: emailVerificationTokensDeleter,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h verifyEmailRequestHandler) Handle(ctx context.Context, request VerifyEmailRequest) (_ VerifyEmailResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeEmailVerify}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	token, err := h.EmailVerificationTokenConsumer.ConsumeEmailVerificationToken(ctx, hashOpaqueToken(request.Token))
+	if errors.Is(err, domainemailverification.ErrTokenNotFound) {
+		err = fmt.Errorf("failed to consume email verification token: %w", err)
+		return VerifyEmailResponse{}, errors.Join(err, ErrVerifyEmailInvalidOrExpiredToken)
+	} else if err != nil {
+		return VerifyEmailResponse{}, fmt.Errorf("failed to consume email verification token: %w", err)
+	}
+	event.UserID = token.UserID
+
+	user, err := h.UserFinder.FindUserByUserID(ctx, token.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return VerifyEmailResponse{}, errors.Join(err, ErrVerifyEmailInvalidOrExpiredToken)
+	} else if err != nil {
+		return VerifyEmailResponse{}, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	// The token is for the user's current email or for the email they are changing to, every change of the email
+	// deletes the tokens issued before it.
+	user.Email, user.EmailVerified = token.Email, true
+	if err := h.UserUpdater.UpdateUser(ctx, user); errors.Is(err, domainuser.ErrEmailAlreadyExists) {
+		err = fmt.Errorf("failed to update user: %w", err)
+		return VerifyEmailResponse{}, errors.Join(err, ErrVerifyEmailEmailAlreadyExists)
+	} else if err != nil {
+		return VerifyEmailResponse{}, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if err := h.EmailVerificationTokensDeleter.DeleteEmailVerificationTokens(ctx, user.ID); err != nil {
+		return VerifyEmailResponse{}, fmt.Errorf("failed to delete email verification tokens: %w", err)
+	}
+	return VerifyEmailResponse{}, nil
+}
+
+type ResendVerificationRequest struct {
+	AccessToken string
+}
+
+type ResendVerificationResponse struct{}
+
+type ResendVerificationRequestHandler interface {
+	Handle(ctx context.Context, request ResendVerificationRequest) (ResendVerificationResponse, error)
+}
+
+type resendVerificationRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	EmailVerificationURL            string
+	EmailVerificationTokenExpiresIn time.Duration
+
+	UserFinder                  UserFinder
+	EmailVerificationTokenSaver EmailVerificationTokenSaver
+
+	Mailer Mailer
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrResendVerificationInvalidToken    = errResendVerificationInvalidToken()
+	ErrResendVerificationNoEmail         = errResendVerificationNoEmail()
+	ErrResendVerificationAlreadyVerified = errResendVerificationAlreadyVerified()
+)
+
+func errResendVerificationInvalidToken() error    { return errors.New("invalid or expired token") }
+func errResendVerificationNoEmail() error         { return errors.New("no email") }
+func errResendVerificationAlreadyVerified() error { return errors.New("already verified") }
+
+func NewResendVerificationRequestHandler(
+	accessTokenParser AccessTokenParser,
+	emailVerificationURL string, emailVerificationTokenExpiresIn time.Duration,
+	userFinder UserFinder, emailVerificationTokenSaver EmailVerificationTokenSaver,
+	mailer Mailer,
+	auditLogger AuditLogger,
+) ResendVerificationRequestHandler {
+	return &resendVerificationRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		EmailVerificationURL:            emailVerificationURL,
+		EmailVerificationTokenExpiresIn: emailVerificationTokenExpiresIn,
+
+		UserFinder:                  userFinder,
+		EmailVerificationTokenSaver: emailVerificationTokenSaver,
+
+		Mailer: mailer,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h resendVerificationRequestHandler) Handle(ctx context.Context, request ResendVerificationRequest) (_ ResendVerificationResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeEmailVerificationResend}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return ResendVerificationResponse{}, errors.Join(err, ErrResendVerificationInvalidToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+
+	user, err := h.UserFinder.FindUserByUserID(ctx, claims.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return ResendVerificationResponse{}, errors.Join(err, ErrResendVerificationInvalidToken)
+	} else if err != nil {
+		return ResendVerificationResponse{}, fmt.Errorf("failed to find user: %w", err)
+	} else if len(user.Email) == 0 {
+		return ResendVerificationResponse{}, ErrResendVerificationNoEmail
+	} else if user.EmailVerified {
+		return ResendVerificationResponse{}, ErrResendVerificationAlreadyVerified
+	}
+
+	if err := sendEmailVerification(ctx,
+		h.EmailVerificationURL, h.EmailVerificationTokenExpiresIn,
+		h.EmailVerificationTokenSaver,
+		h.Mailer,
+		user, user.Email,
+	); err != nil {
+		return ResendVerificationResponse{}, err
+	}
+	return ResendVerificationResponse{}, nil
+}
@@ -0,0 +1,400 @@
+package oauth2
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainidentity "github.com/nazarslota/unotes/auth/internal/domain/identity"
+	domaintotp "github.com/nazarslota/unotes/auth/internal/domain/totp"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+)
+
+// ExternalAuthorizeRequest starts a sign in with an external identity provider. If AccessToken is set, the identity
+// at the provider is linked to the account of the signed in user instead.
+type ExternalAuthorizeRequest struct {
+	Provider    string
+	AccessToken string
+}
+
+// ExternalAuthorizeResponse holds the URL of the identity provider to send the user to.
+type ExternalAuthorizeResponse struct {
+	AuthorizationURL string
+}
+
+type ExternalAuthorizeRequestHandler interface {
+	Handle(ctx context.Context, request ExternalAuthorizeRequest) (ExternalAuthorizeResponse, error)
+}
+
+type externalAuthorizeRequestHandler struct {
+	IdentityProviders      map[string]IdentityProvider
+	IdentityStateExpiresIn time.Duration
+
+	AccessTokenParser  AccessTokenParser
+	IdentityStateSaver IdentityStateSaver
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrExternalAuthorizeUnknownProvider       = errExternalAuthorizeUnknownProvider()
+	ErrExternalAuthorizeInvalidOrExpiredToken = errExternalAuthorizeInvalidOrExpiredToken()
+)
+
+func errExternalAuthorizeUnknownProvider() error       { return errors.New("unknown identity provider") }
+func errExternalAuthorizeInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+
+func NewExternalAuthorizeRequestHandler(
+	identityProviders map[string]IdentityProvider, identityStateExpiresIn time.Duration,
+	accessTokenParser AccessTokenParser, identityStateSaver IdentityStateSaver,
+	auditLogger AuditLogger,
+) ExternalAuthorizeRequestHandler {
+	return &externalAuthorizeRequestHandler{
+		IdentityProviders:      identityProviders,
+		IdentityStateExpiresIn: identityStateExpiresIn,
+
+		AccessTokenParser:  accessTokenParser,
+		IdentityStateSaver: identityStateSaver,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h externalAuthorizeRequestHandler) Handle(
+	ctx context.Context, request ExternalAuthorizeRequest,
+) (_ ExternalAuthorizeResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeExternalAuthorize}
+	if len(request.AccessToken) != 0 {
+		event.Type = domainaudit.TypeIdentityLinkBegin
+	}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	provider, ok := h.IdentityProviders[request.Provider]
+	if !ok {
+		return ExternalAuthorizeResponse{}, ErrExternalAuthorizeUnknownProvider
+	}
+
+	var userID string
+	if len(request.AccessToken) != 0 {
+		claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+		if err != nil {
+			err = fmt.Errorf("failed to parse access token: %w", err)
+			return ExternalAuthorizeResponse{}, errors.Join(err, ErrExternalAuthorizeInvalidOrExpiredToken)
+		}
+		userID = claims.UserID
+		event.UserID, event.SessionID = claims.UserID, claims.SessionID
+	}
+
+	state, err := newOpaqueToken()
+	if err != nil {
+		return ExternalAuthorizeResponse{}, fmt.Errorf("failed to create state: %w", err)
+	}
+	nonce, err := newOpaqueToken()
+	if err != nil {
+		return ExternalAuthorizeResponse{}, fmt.Errorf("failed to create nonce: %w", err)
+	}
+	codeVerifier, err := newOpaqueToken()
+	if err != nil {
+		return ExternalAuthorizeResponse{}, fmt.Errorf("failed to create code verifier: %w", err)
+	}
+
+	err = h.IdentityStateSaver.SaveIdentityState(ctx, domainidentity.State{
+		StateHash:    hashOpaqueToken(state),
+		Provider:     request.Provider,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		UserID:       userID,
+		ExpiresAt:    time.Now().Add(h.IdentityStateExpiresIn),
+	})
+	if err != nil {
+		return ExternalAuthorizeResponse{}, fmt.Errorf("failed to save identity state: %w", err)
+	}
+	return ExternalAuthorizeResponse{AuthorizationURL: provider.AuthCodeURL(state, nonce, codeVerifier)}, nil
+}
+
+// ExternalCallbackRequest holds what the external identity provider redirected the user back with.
+type ExternalCallbackRequest struct {
+	Provider string
+	Code     string
+	State    string
+}
+
+// ExternalCallbackResponse holds, like SignInResponse, either the token pair or a two-factor challenge token. Linked
+// is set instead if the identity was linked to the account of a signed in user.
+type ExternalCallbackResponse struct {
+	AccessToken  string
+	RefreshToken string
+
+	MFARequired bool
+	MFAToken    string
+
+	Linked bool
+}
+
+// ExternalCallbackRequestHandler completes a sign in with an external identity provider. Users signing in with an
+// identity that is not linked yet are created on the fly, with the verified email address from the provider, if any.
+type ExternalCallbackRequestHandler interface {
+	Handle(ctx context.Context, request ExternalCallbackRequest) (ExternalCallbackResponse, error)
+}
+
+type externalCallbackRequestHandler struct {
+	AccessTokenCreator   AccessTokenCreator
+	AccessTokenExpiresIn time.Duration
+
+	RefreshTokenCreator   RefreshTokenCreator
+	RefreshTokenExpiresIn time.Duration
+
+	MFATokenCreator   MFATokenCreator
+	MFATokenExpiresIn time.Duration
+
+	IdentityProviders map[string]IdentityProvider
+
+	PasswordHasher PasswordHasher
+
+	RefreshTokenSaver RefreshTokenSaver
+
+	UserSaver  UserSaver
+	UserFinder UserFinder
+	TOTPFinder TOTPFinder
+
+	IdentitySaver         IdentitySaver
+	IdentityFinder        IdentityFinder
+	IdentityStateConsumer IdentityStateConsumer
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrExternalCallbackUnknownProvider       = errExternalCallbackUnknownProvider()
+	ErrExternalCallbackInvalidState          = errExternalCallbackInvalidState()
+	ErrExternalCallbackInvalidCode           = errExternalCallbackInvalidCode()
+	ErrExternalCallbackIdentityAlreadyLinked = errExternalCallbackIdentityAlreadyLinked()
+	ErrExternalCallbackEmailAlreadyExists    = errExternalCallbackEmailAlreadyExists()
+	ErrExternalCallbackDisabled              = errExternalCallbackDisabled()
+)
+
+func errExternalCallbackUnknownProvider() error       { return errors.New("unknown identity provider") }
+func errExternalCallbackInvalidState() error          { return errors.New("invalid or expired state") }
+func errExternalCallbackInvalidCode() error           { return errors.New("invalid code") }
+func errExternalCallbackIdentityAlreadyLinked() error { return errors.New("identity already linked") }
+func errExternalCallbackEmailAlreadyExists() error    { return domainuser.ErrEmailAlreadyExists }
+func errExternalCallbackDisabled() error              { return errors.New("account disabled") }
+
+func NewExternalCallbackRequestHandler(
+	accessTokenCreator AccessTokenCreator, accessTokenExpiresIn time.Duration,
+	refreshTokenCreator RefreshTokenCreator, refreshTokenExpiresIn time.Duration,
+	mfaTokenCreator MFATokenCreator, mfaTokenExpiresIn time.Duration,
+	identityProviders map[string]IdentityProvider,
+	passwordHasher PasswordHasher,
+	refreshTokenSaver RefreshTokenSaver,
+	userSaver UserSaver, userFinder UserFinder, totpFinder TOTPFinder,
+	identitySaver IdentitySaver, identityFinder IdentityFinder, identityStateConsumer IdentityStateConsumer,
+	auditLogger AuditLogger,
+) ExternalCallbackRequestHandler {
+	return &externalCallbackRequestHandler{
+		AccessTokenCreator:   accessTokenCreator,
+		AccessTokenExpiresIn: accessTokenExpiresIn,
+
+		RefreshTokenCreator:   refreshTokenCreator,
+		RefreshTokenExpiresIn: refreshTokenExpiresIn,
+
+		MFATokenCreator:   mfaTokenCreator,
+		MFATokenExpiresIn: mfaTokenExpiresIn,
+
+		IdentityProviders: identityProviders,
+
+		PasswordHasher: passwordHasher,
+
+		RefreshTokenSaver: refreshTokenSaver,
+
+		UserSaver:  userSaver,
+		UserFinder: userFinder,
+		TOTPFinder: totpFinder,
+
+		IdentitySaver:         identitySaver,
+		IdentityFinder:        identityFinder,
+		IdentityStateConsumer: identityStateConsumer,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h externalCallbackRequestHandler) Handle(
+	ctx context.Context, request ExternalCallbackRequest,
+) (_ ExternalCallbackResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeExternalSignIn}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	provider, ok := h.IdentityProviders[request.Provider]
+	if !ok {
+		return ExternalCallbackResponse{}, ErrExternalCallbackUnknownProvider
+	}
+
+	state, err := h.IdentityStateConsumer.ConsumeIdentityState(ctx, hashOpaqueToken(request.State))
+	if errors.Is(err, domainidentity.ErrStateNotFound) {
+		err = fmt.Errorf("failed to consume identity state: %w", err)
+		return ExternalCallbackResponse{}, errors.Join(err, ErrExternalCallbackInvalidState)
+	} else if err != nil {
+		return ExternalCallbackResponse{}, fmt.Errorf("failed to consume identity state: %w", err)
+	} else if state.Provider != request.Provider {
+		return ExternalCallbackResponse{}, ErrExternalCallbackInvalidState
+	}
+
+	profile, err := provider.Exchange(ctx, request.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		err = fmt.Errorf("failed to exchange code: %w", err)
+		return ExternalCallbackResponse{}, errors.Join(err, ErrExternalCallbackInvalidCode)
+	}
+
+	identity, err := h.IdentityFinder.FindIdentity(ctx, request.Provider, profile.Subject)
+	if err != nil && !errors.Is(err, domainidentity.ErrIdentityNotFound) {
+		return ExternalCallbackResponse{}, fmt.Errorf("failed to find identity: %w", err)
+	}
+	found := err == nil
+
+	if len(state.UserID) != 0 {
+		event.Type, event.UserID = domainaudit.TypeIdentityLink, state.UserID
+		if found && identity.UserID != state.UserID {
+			return ExternalCallbackResponse{}, ErrExternalCallbackIdentityAlreadyLinked
+		} else if !found {
+			err := h.IdentitySaver.SaveIdentity(ctx, domainidentity.Identity{
+				Provider: request.Provider,
+				Subject:  profile.Subject,
+				UserID:   state.UserID,
+			})
+			if errors.Is(err, domainidentity.ErrIdentityAlreadyExists) {
+				err = fmt.Errorf("failed to save identity: %w", err)
+				return ExternalCallbackResponse{}, errors.Join(err, ErrExternalCallbackIdentityAlreadyLinked)
+			} else if err != nil {
+				return ExternalCallbackResponse{}, fmt.Errorf("failed to save identity: %w", err)
+			}
+		}
+		return ExternalCallbackResponse{Linked: true}, nil
+	}
+
+	var user domainuser.User
+	if found {
+		user, err = h.UserFinder.FindUserByUserID(ctx, identity.UserID)
+		if err != nil {
+			return ExternalCallbackResponse{}, fmt.Errorf("failed to find user: %w", err)
+		}
+	} else {
+		user, err = h.provision(ctx, request.Provider, profile)
+		if err != nil {
+			return ExternalCallbackResponse{}, err
+		}
+	}
+	event.UserID = user.ID
+
+	if user.Disabled {
+		return ExternalCallbackResponse{}, ErrExternalCallbackDisabled
+	}
+
+	totp, err := h.TOTPFinder.FindTOTPByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, domaintotp.ErrTOTPNotFound) {
+		return ExternalCallbackResponse{}, fmt.Errorf("failed to find totp: %w", err)
+	} else if err == nil && totp.Enabled {
+		mfaToken, err := newMFAToken(h.MFATokenCreator, h.MFATokenExpiresIn, user)
+		if err != nil {
+			return ExternalCallbackResponse{}, err
+		}
+		event.Reason = "mfa required"
+		return ExternalCallbackResponse{MFARequired: true, MFAToken: mfaToken}, nil
+	}
+
+	event.SessionID = uuid.New().String()
+	accessToken, refreshToken, err := newTokenPair(ctx,
+		h.AccessTokenCreator, h.AccessTokenExpiresIn,
+		h.RefreshTokenCreator, h.RefreshTokenExpiresIn,
+		h.RefreshTokenSaver,
+		user, event.SessionID,
+	)
+	if err != nil {
+		return ExternalCallbackResponse{}, err
+	}
+	return ExternalCallbackResponse{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// provision creates a user for an identity that signs in for the first time and links the identity to it. The user
+// gets a random password, which they can replace by resetting it. An email address already used by another account
+// is not taken over, the owner of that account has to link the identity instead.
+func (h externalCallbackRequestHandler) provision(
+	ctx context.Context, provider string, profile domainidentity.Profile,
+) (domainuser.User, error) {
+	password, err := newOpaqueToken()
+	if err != nil {
+		return domainuser.User{}, fmt.Errorf("failed to create password: %w", err)
+	}
+	passwordHash, err := h.PasswordHasher.Hash(password)
+	if err != nil {
+		return domainuser.User{}, fmt.Errorf("failed to generate password hash: %w", err)
+	}
+
+	user := domainuser.User{ID: uuid.New().String(), PasswordHash: passwordHash, Role: domainuser.RoleUser}
+	if profile.EmailVerified && validEmail(profile.Email) {
+		user.Email, user.EmailVerified = profile.Email, true
+	}
+
+	// The username suggested by the provider may be taken, so a random suffix is added on the next attempts.
+	const attempts = 5
+	for attempt := 0; ; attempt++ {
+		if user.Username, err = externalUsername(profile, attempt > 0); err != nil {
+			return domainuser.User{}, err
+		}
+
+		err = h.UserSaver.SaveUser(ctx, user)
+		if errors.Is(err, domainuser.ErrEmailAlreadyExists) {
+			err = fmt.Errorf("failed to save user: %w", err)
+			return domainuser.User{}, errors.Join(err, ErrExternalCallbackEmailAlreadyExists)
+		} else if errors.Is(err, domainuser.ErrUserAlreadyExists) && attempt < attempts-1 {
+			continue
+		} else if err != nil {
+			return domainuser.User{}, fmt.Errorf("failed to save user: %w", err)
+		}
+		break
+	}
+
+	err = h.IdentitySaver.SaveIdentity(ctx, domainidentity.Identity{
+		Provider: provider,
+		Subject:  profile.Subject,
+		UserID:   user.ID,
+	})
+	if err != nil {
+		return domainuser.User{}, fmt.Errorf("failed to save identity: %w", err)
+	}
+	return user, nil
+}
+
+// usernameDisallowedPattern matches the characters not kept from the username suggested by an identity provider.
+var usernameDisallowedPattern = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// externalUsername returns a username for a user created from an external identity, based on the preferred username
+// or else on the email address from the provider, and fitting the username length limits of sign up.
+func externalUsername(profile domainidentity.Profile, suffix bool) (string, error) {
+	username := profile.PreferredUsername
+	if len(username) == 0 {
+		username, _, _ = strings.Cut(profile.Email, "@")
+	}
+	username = usernameDisallowedPattern.ReplaceAllString(username, "")
+	if len(username) == 0 {
+		username = "user"
+	} else if len(username) > 24 {
+		username = username[:24]
+	}
+
+	if suffix || len(username) < 4 {
+		random := make([]byte, 3)
+		if _, err := rand.Read(random); err != nil {
+			return "", fmt.Errorf("failed to read random bytes: %w", err)
+		}
+		username += "-" + hex.EncodeToString(random)
+	}
+	return username, nil
+}
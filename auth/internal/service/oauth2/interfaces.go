@@ -2,8 +2,21 @@ package oauth2
 
 import (
 	"context"
+	"time"
 
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainauthorizationcode "github.com/nazarslota/unotes/auth/internal/domain/authorizationcode"
+	domainblob "github.com/nazarslota/unotes/auth/internal/domain/blob"
+	domainclient "github.com/nazarslota/unotes/auth/internal/domain/client"
+	domainemailverification "github.com/nazarslota/unotes/auth/internal/domain/emailverification"
+	domainidentity "github.com/nazarslota/unotes/auth/internal/domain/identity"
+	domainmagiclink "github.com/nazarslota/unotes/auth/internal/domain/magiclink"
+	domainoutbox "github.com/nazarslota/unotes/auth/internal/domain/outbox"
+	domainpasskey "github.com/nazarslota/unotes/auth/internal/domain/passkey"
+	domainpasswordreset "github.com/nazarslota/unotes/auth/internal/domain/passwordreset"
+	domainpersonalaccesstoken "github.com/nazarslota/unotes/auth/internal/domain/personalaccesstoken"
 	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	domaintotp "github.com/nazarslota/unotes/auth/internal/domain/totp"
 	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
 	"github.com/nazarslota/unotes/auth/pkg/jwt"
 )
@@ -24,6 +37,22 @@ type RefreshTokenParser interface {
 	Parse(token string) (jwt.RefreshTokenClaims, error)
 }
 
+type MFATokenCreator interface {
+	New(claims jwt.MFATokenClaims) (string, error)
+}
+
+type MFATokenParser interface {
+	Parse(token string) (jwt.MFATokenClaims, error)
+}
+
+type IDTokenCreator interface {
+	New(claims jwt.IDTokenClaims) (string, error)
+}
+
+type IDTokenKeySet interface {
+	KeySet() jwt.KeySet
+}
+
 type RefreshTokenSaver interface {
 	SaveRefreshToken(ctx context.Context, userID string, token domainrefresh.Token) error
 }
@@ -40,10 +69,292 @@ type RefreshTokenGetter interface {
 	GetRefreshTokens(ctx context.Context, userID string) ([]domainrefresh.Token, error)
 }
 
+type RevokedTokenSaver interface {
+	SaveRevokedToken(ctx context.Context, tokenID string, expiresIn time.Duration) error
+}
+
+type RevokedTokenChecker interface {
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+type RevokedTokenPublisher interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+}
+
 type UserSaver interface {
 	SaveUser(ctx context.Context, user domainuser.User) error
 }
 
 type UserFinder interface {
 	FindUserByUsername(ctx context.Context, username string) (domainuser.User, error)
+	FindUserByUserID(ctx context.Context, userID string) (domainuser.User, error)
+	FindUserByEmail(ctx context.Context, email string) (domainuser.User, error)
+}
+
+type UserUpdater interface {
+	UpdateUser(ctx context.Context, user domainuser.User) error
+}
+
+type UsersFinder interface {
+	FindUsers(ctx context.Context, filter domainuser.Filter) ([]domainuser.User, error)
+}
+
+type UserRoleUpdater interface {
+	UpdateUserRole(ctx context.Context, userID, role string) error
+}
+
+type UserDisabledUpdater interface {
+	UpdateUserDisabled(ctx context.Context, userID string, disabled bool) error
+}
+
+// UserDeleter deletes a user and saves the messages describing the deletion to the outbox in the same transaction.
+type UserDeleter interface {
+	DeleteUser(ctx context.Context, userID string, messages ...domainoutbox.Message) error
+}
+
+// DeletedUsersFinder finds which of the given user IDs belong to users that were deleted.
+type DeletedUsersFinder interface {
+	FindDeletedUserIDs(ctx context.Context, userIDs []string) ([]string, error)
+}
+
+type ProfileFinder interface {
+	FindProfile(ctx context.Context, userID string) (domainuser.Profile, error)
+}
+
+type ProfilesFinder interface {
+	FindProfiles(ctx context.Context, userIDs []string) ([]domainuser.Profile, error)
+}
+
+type ProfileUpdater interface {
+	UpdateProfile(ctx context.Context, profile domainuser.Profile) error
+}
+
+type BlobSaver interface {
+	SaveBlob(ctx context.Context, key string, blob domainblob.Blob) error
+}
+
+type BlobFinder interface {
+	FindBlob(ctx context.Context, key string) (domainblob.Blob, error)
+}
+
+type BlobDeleter interface {
+	DeleteBlob(ctx context.Context, key string) error
+}
+
+type PersonalAccessTokenSaver interface {
+	SavePersonalAccessToken(ctx context.Context, token domainpersonalaccesstoken.Token) error
+}
+
+type PersonalAccessTokensFinder interface {
+	FindPersonalAccessTokens(ctx context.Context, userID string) ([]domainpersonalaccesstoken.Token, error)
+}
+
+type PersonalAccessTokenFinder interface {
+	FindPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (domainpersonalaccesstoken.Token, error)
+}
+
+type PersonalAccessTokenUsageUpdater interface {
+	UpdatePersonalAccessTokenLastUsed(ctx context.Context, tokenID string, lastUsedAt time.Time) error
+}
+
+type PersonalAccessTokenDeleter interface {
+	DeletePersonalAccessToken(ctx context.Context, userID, tokenID string) error
+}
+
+type PasskeySaver interface {
+	SavePasskey(ctx context.Context, credential domainpasskey.Credential) error
+}
+
+type PasskeysFinder interface {
+	FindPasskeys(ctx context.Context, userID string) ([]domainpasskey.Credential, error)
+}
+
+type PasskeyFinder interface {
+	FindPasskey(ctx context.Context, credentialID string) (domainpasskey.Credential, error)
+}
+
+type PasskeyUsageUpdater interface {
+	UpdatePasskeyUsage(ctx context.Context, credentialID string, signCount uint32, lastUsedAt time.Time) error
+}
+
+type PasskeyDeleter interface {
+	DeletePasskey(ctx context.Context, userID, credentialID string) error
+}
+
+type PasskeySessionSaver interface {
+	SavePasskeySession(ctx context.Context, session domainpasskey.Session) error
+}
+
+type PasskeySessionConsumer interface {
+	ConsumePasskeySession(ctx context.Context, sessionHash string) (domainpasskey.Session, error)
+}
+
+// PasskeyRelyingParty runs the WebAuthn ceremonies of registering passkeys and signing in with them. Options and
+// responses are the JSON passed to and returned by the WebAuthn API of the browser, sessions are opaque.
+type PasskeyRelyingParty interface {
+	BeginRegistration(
+		user domainuser.User, credentials []domainpasskey.Credential,
+	) (options []byte, session []byte, err error)
+	FinishRegistration(user domainuser.User, session []byte, response []byte) (domainpasskey.Credential, error)
+	BeginLogin(user domainuser.User, credentials []domainpasskey.Credential) (options []byte, session []byte, err error)
+	BeginDiscoverableLogin() (options []byte, session []byte, err error)
+	CredentialID(response []byte) (string, error)
+	FinishLogin(
+		user domainuser.User, credentials []domainpasskey.Credential, session []byte, response []byte,
+	) (domainpasskey.Credential, error)
+}
+
+type PasswordResetTokenSaver interface {
+	SavePasswordResetToken(ctx context.Context, token domainpasswordreset.Token) error
+}
+
+type PasswordResetTokenFinder interface {
+	FindPasswordResetToken(ctx context.Context, tokenHash string) (domainpasswordreset.Token, error)
+}
+
+type PasswordResetTokenConsumer interface {
+	ConsumePasswordResetToken(ctx context.Context, tokenHash string) (domainpasswordreset.Token, error)
+}
+
+type PasswordResetTokensDeleter interface {
+	DeletePasswordResetTokens(ctx context.Context, userID string) error
+}
+
+type MagicLinkTokenSaver interface {
+	SaveMagicLinkToken(ctx context.Context, token domainmagiclink.Token) error
+}
+
+type MagicLinkTokenConsumer interface {
+	ConsumeMagicLinkToken(ctx context.Context, tokenHash string) (domainmagiclink.Token, error)
+}
+
+type MagicLinkTokensDeleter interface {
+	DeleteMagicLinkTokens(ctx context.Context, userID string) error
+}
+
+type EmailVerificationTokenSaver interface {
+	SaveEmailVerificationToken(ctx context.Context, token domainemailverification.Token) error
+}
+
+type EmailVerificationTokenConsumer interface {
+	ConsumeEmailVerificationToken(ctx context.Context, tokenHash string) (domainemailverification.Token, error)
+}
+
+type EmailVerificationTokensDeleter interface {
+	DeleteEmailVerificationTokens(ctx context.Context, userID string) error
+}
+
+type SignInFailureSaver interface {
+	SaveSignInFailure(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+type SignInLockSaver interface {
+	SaveSignInLock(ctx context.Context, key string, duration time.Duration) error
+}
+
+type SignInLockFinder interface {
+	FindSignInLock(ctx context.Context, key string) (time.Duration, error)
+}
+
+type SignInFailuresDeleter interface {
+	DeleteSignInFailures(ctx context.Context, key string) error
+}
+
+type MagicLinkRequestSaver interface {
+	SaveMagicLinkRequest(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+type ClientSaver interface {
+	SaveClient(ctx context.Context, client domainclient.Client) error
+}
+
+type ClientFinder interface {
+	FindClientByClientID(ctx context.Context, clientID string) (domainclient.Client, error)
+}
+
+type AuthorizationCodeSaver interface {
+	SaveAuthorizationCode(ctx context.Context, code domainauthorizationcode.Code) error
+}
+
+type AuthorizationCodeConsumer interface {
+	ConsumeAuthorizationCode(ctx context.Context, codeHash string) (domainauthorizationcode.Code, error)
+}
+
+type IdentitySaver interface {
+	SaveIdentity(ctx context.Context, identity domainidentity.Identity) error
+}
+
+type IdentityFinder interface {
+	FindIdentity(ctx context.Context, provider, subject string) (domainidentity.Identity, error)
+}
+
+type IdentityStateSaver interface {
+	SaveIdentityState(ctx context.Context, state domainidentity.State) error
+}
+
+type IdentityStateConsumer interface {
+	ConsumeIdentityState(ctx context.Context, stateHash string) (domainidentity.State, error)
+}
+
+// IdentityProvider is an external OpenID Connect identity provider users can sign in with.
+type IdentityProvider interface {
+	AuthCodeURL(state, nonce, codeVerifier string) string
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (domainidentity.Profile, error)
+}
+
+// PasswordHasher hashes passwords and verifies them against hashes made by any of the algorithms it supports.
+// NeedsRehash reports whether a hash was made by another algorithm or with other parameters than the ones it hashes with.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, hash string) error
+	NeedsRehash(hash string) bool
+}
+
+type BreachedPasswordChecker interface {
+	Contains(password string) bool
+}
+
+type Mailer interface {
+	SendMail(ctx context.Context, to, subject, body string) error
+}
+
+type TOTPSaver interface {
+	SaveTOTP(ctx context.Context, totp domaintotp.TOTP) error
+}
+
+type TOTPFinder interface {
+	FindTOTPByUserID(ctx context.Context, userID string) (domaintotp.TOTP, error)
+}
+
+type TOTPUpdater interface {
+	UpdateTOTP(ctx context.Context, totp domaintotp.TOTP) error
+}
+
+type TOTPLastUsedStepUpdater interface {
+	UpdateTOTPLastUsedStep(ctx context.Context, userID string, step int64) error
+}
+
+type TOTPDeleter interface {
+	DeleteTOTP(ctx context.Context, userID string) error
+}
+
+type RecoveryCodesSaver interface {
+	SaveRecoveryCodes(ctx context.Context, userID string, codes []domaintotp.RecoveryCode) error
+}
+
+type RecoveryCodeDeleter interface {
+	DeleteRecoveryCode(ctx context.Context, code domaintotp.RecoveryCode) error
+}
+
+type RecoveryCodesDeleter interface {
+	DeleteRecoveryCodes(ctx context.Context, userID string) error
+}
+
+// AuditLogger appends security events to the audit log.
+type AuditLogger interface {
+	SaveAuditEvent(ctx context.Context, event domainaudit.Event) error
+}
+
+type AuditEventsFinder interface {
+	FindAuditEvents(ctx context.Context, filter domainaudit.Filter) ([]domainaudit.Event, error)
 }
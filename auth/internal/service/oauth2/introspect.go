@@ -0,0 +1,245 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domainpersonalaccesstoken "github.com/nazarslota/unotes/auth/internal/domain/personalaccesstoken"
+	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/pkg/pat"
+	"golang.org/x/exp/slices"
+)
+
+// Token type hints a client can give when introspecting or revoking a token.
+const (
+	TokenTypeHintAccessToken  = "access_token"
+	TokenTypeHintRefreshToken = "refresh_token"
+)
+
+// IntrospectRequest is an RFC 7662 token introspection request of a confidential client, usually a resource server.
+// TokenTypeHint only decides which kind of token is tried first. Personal access tokens are introspected like access
+// tokens, regardless of the hint.
+type IntrospectRequest struct {
+	ClientID     string
+	ClientSecret string
+
+	Token         string
+	TokenTypeHint string
+}
+
+// IntrospectResponse describes the token. All other fields are empty unless Active is true. Subject is the user ID,
+// or, for service accounts, the client ID, which is then the same as ClientID. Role is set only for access tokens of
+// users.
+type IntrospectResponse struct {
+	Active        bool
+	TokenType     string
+	TokenID       string
+	Subject       string
+	ClientID      string
+	Scope         string
+	SessionID     string
+	EmailVerified bool
+	Role          string
+	ExpiresAt     time.Time
+}
+
+type IntrospectRequestHandler interface {
+	Handle(ctx context.Context, request IntrospectRequest) (IntrospectResponse, error)
+}
+
+type introspectRequestHandler struct {
+	AccessTokenParser  AccessTokenParser
+	RefreshTokenParser RefreshTokenParser
+
+	RefreshTokenGetter  RefreshTokenGetter
+	RevokedTokenChecker RevokedTokenChecker
+
+	PersonalAccessTokenFinder       PersonalAccessTokenFinder
+	PersonalAccessTokenUsageUpdater PersonalAccessTokenUsageUpdater
+	UserFinder                      UserFinder
+
+	ClientFinder ClientFinder
+}
+
+var (
+	ErrIntrospectInvalidRequest = errIntrospectInvalidRequest()
+	ErrIntrospectInvalidClient  = errIntrospectInvalidClient()
+)
+
+func errIntrospectInvalidRequest() error { return errors.New("invalid request") }
+func errIntrospectInvalidClient() error  { return errors.New("invalid client") }
+
+func NewIntrospectRequestHandler(
+	accessTokenParser AccessTokenParser, refreshTokenParser RefreshTokenParser,
+	refreshTokenGetter RefreshTokenGetter, revokedTokenChecker RevokedTokenChecker,
+	personalAccessTokenFinder PersonalAccessTokenFinder,
+	personalAccessTokenUsageUpdater PersonalAccessTokenUsageUpdater,
+	userFinder UserFinder,
+	clientFinder ClientFinder,
+) IntrospectRequestHandler {
+	return &introspectRequestHandler{
+		AccessTokenParser:  accessTokenParser,
+		RefreshTokenParser: refreshTokenParser,
+
+		RefreshTokenGetter:  refreshTokenGetter,
+		RevokedTokenChecker: revokedTokenChecker,
+
+		PersonalAccessTokenFinder:       personalAccessTokenFinder,
+		PersonalAccessTokenUsageUpdater: personalAccessTokenUsageUpdater,
+		UserFinder:                      userFinder,
+
+		ClientFinder: clientFinder,
+	}
+}
+
+func (h introspectRequestHandler) Handle(ctx context.Context, request IntrospectRequest) (IntrospectResponse, error) {
+	if len(request.Token) == 0 || len(request.ClientID) == 0 {
+		return IntrospectResponse{}, ErrIntrospectInvalidRequest
+	}
+
+	// Public clients can't keep a secret, so anyone could introspect tokens as them.
+	client, err := findClient(ctx, h.ClientFinder, request.ClientID, request.ClientSecret)
+	if errors.Is(err, errClientNotAuthenticated) {
+		return IntrospectResponse{}, errors.Join(err, ErrIntrospectInvalidClient)
+	} else if err != nil {
+		return IntrospectResponse{}, err
+	} else if client.Public {
+		return IntrospectResponse{}, ErrIntrospectInvalidClient
+	}
+
+	// Personal access tokens are opaque, so they are told apart by their prefix rather than by trying to parse them.
+	if pat.IsPersonalAccessToken(request.Token) {
+		return h.personalAccessToken(ctx, request.Token)
+	}
+
+	introspectors := []func(context.Context, string) (IntrospectResponse, error){h.accessToken, h.refreshToken}
+	if request.TokenTypeHint == TokenTypeHintRefreshToken {
+		introspectors[0], introspectors[1] = introspectors[1], introspectors[0]
+	}
+
+	for _, introspect := range introspectors {
+		response, err := introspect(ctx, request.Token)
+		if err != nil {
+			return IntrospectResponse{}, err
+		} else if response.Active {
+			return response, nil
+		}
+	}
+	return IntrospectResponse{Active: false}, nil
+}
+
+// accessToken introspects the token as an access token, which is active until it expires or is revoked.
+func (h introspectRequestHandler) accessToken(ctx context.Context, token string) (IntrospectResponse, error) {
+	claims, err := h.AccessTokenParser.Parse(token)
+	if err != nil {
+		return IntrospectResponse{Active: false}, nil
+	}
+
+	if len(claims.ID) != 0 {
+		revoked, err := h.RevokedTokenChecker.IsTokenRevoked(ctx, claims.ID)
+		if err != nil {
+			return IntrospectResponse{}, fmt.Errorf("failed to check if token is revoked: %w", err)
+		} else if revoked {
+			return IntrospectResponse{Active: false}, nil
+		}
+	}
+
+	// Tokens of service accounts have no user, their subject is the client.
+	subject := claims.UserID
+	if len(subject) == 0 {
+		subject = claims.Subject
+	}
+
+	response := IntrospectResponse{
+		Active:        true,
+		TokenType:     TokenTypeHintAccessToken,
+		TokenID:       claims.ID,
+		Subject:       subject,
+		ClientID:      claims.ClientID,
+		Scope:         claims.Scope,
+		SessionID:     claims.SessionID,
+		EmailVerified: claims.EmailVerified,
+		Role:          claims.Role,
+	}
+	if claims.ExpiresAt != nil {
+		response.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return response, nil
+}
+
+// personalAccessToken introspects a personal access token, which is active until it expires or is revoked, and
+// records that it was used. Since resource servers cache introspection results, the recorded time is approximate.
+func (h introspectRequestHandler) personalAccessToken(ctx context.Context, token string) (IntrospectResponse, error) {
+	personalAccessToken, err := h.PersonalAccessTokenFinder.FindPersonalAccessTokenByHash(ctx, hashOpaqueToken(token))
+	if errors.Is(err, domainpersonalaccesstoken.ErrTokenNotFound) {
+		return IntrospectResponse{Active: false}, nil
+	} else if err != nil {
+		return IntrospectResponse{}, fmt.Errorf("failed to find personal access token: %w", err)
+	}
+
+	now := time.Now()
+	if personalAccessToken.ExpiresAt != nil && !now.Before(*personalAccessToken.ExpiresAt) {
+		return IntrospectResponse{Active: false}, nil
+	}
+
+	user, err := h.UserFinder.FindUserByUserID(ctx, personalAccessToken.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		return IntrospectResponse{Active: false}, nil
+	} else if err != nil {
+		return IntrospectResponse{}, fmt.Errorf("failed to find user: %w", err)
+	} else if user.Disabled {
+		return IntrospectResponse{Active: false}, nil
+	}
+
+	// Failing to record the use doesn't make the token any less valid.
+	_ = h.PersonalAccessTokenUsageUpdater.UpdatePersonalAccessTokenLastUsed(ctx, personalAccessToken.ID, now)
+
+	response := IntrospectResponse{
+		Active:        true,
+		TokenType:     TokenTypeHintAccessToken,
+		TokenID:       personalAccessToken.ID,
+		Subject:       user.ID,
+		Scope:         strings.Join(personalAccessToken.Scopes, " "),
+		EmailVerified: user.EmailVerified,
+		Role:          user.Role,
+	}
+	if personalAccessToken.ExpiresAt != nil {
+		response.ExpiresAt = *personalAccessToken.ExpiresAt
+	}
+	return response, nil
+}
+
+// refreshToken introspects the token as a refresh token, which is active until it expires or is used or revoked.
+func (h introspectRequestHandler) refreshToken(ctx context.Context, token string) (IntrospectResponse, error) {
+	claims, err := h.RefreshTokenParser.Parse(token)
+	if err != nil {
+		return IntrospectResponse{Active: false}, nil
+	}
+
+	tokens, err := h.RefreshTokenGetter.GetRefreshTokens(ctx, claims.UserID)
+	if errors.Is(err, domainrefresh.ErrTokenNotFound) {
+		return IntrospectResponse{Active: false}, nil
+	} else if err != nil {
+		return IntrospectResponse{}, fmt.Errorf("failed to get refresh tokens: %w", err)
+	} else if !slices.Contains(tokens, domainrefresh.Token(token)) {
+		return IntrospectResponse{Active: false}, nil
+	}
+
+	response := IntrospectResponse{
+		Active:    true,
+		TokenType: TokenTypeHintRefreshToken,
+		TokenID:   claims.ID,
+		Subject:   claims.UserID,
+		ClientID:  claims.ClientID,
+		Scope:     claims.Scope,
+		SessionID: claims.SessionID,
+	}
+	if claims.ExpiresAt != nil {
+		response.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return response, nil
+}
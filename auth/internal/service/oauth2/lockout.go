@@ -0,0 +1,191 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domainlockout "github.com/nazarslota/unotes/auth/internal/domain/lockout"
+)
+
+// LockoutPolicy configures how failed sign in attempts are throttled. After every failure sign in is locked for
+// Backoff, doubling with each further failure, and after MaxAttempts failures for a username, or MaxAttemptsPerIP
+// failures from a client IP address, it is locked for Lockout. Failures are forgotten after Window without any.
+type LockoutPolicy struct {
+	MaxAttempts      int
+	MaxAttemptsPerIP int
+	Backoff          time.Duration
+	Lockout          time.Duration
+	Window           time.Duration
+}
+
+// SignInLockedError is returned when sign in is locked after too many failed attempts. It matches ErrSignInLocked.
+type SignInLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e SignInLockedError) Error() string {
+	return fmt.Sprintf("too many failed attempts, retry after %s", e.RetryAfter)
+}
+
+func (e SignInLockedError) Is(target error) bool {
+	return target == ErrSignInLocked
+}
+
+func usernameLockoutKey(username string) string { return "username:" + username }
+func ipLockoutKey(ip string) string             { return "ip:" + ip }
+func mfaUserLockoutKey(userID string) string    { return "mfa-user:" + userID }
+func mfaTokenLockoutKey(token string) string    { return "mfa:" + hashOpaqueToken(token) }
+
+// maxMFATokenAttempts is how many wrong codes an MFA challenge token takes before it is rejected for the rest of its
+// lifetime, so that a code can't be guessed with a single token even below the limits of the lockout policy.
+const maxMFATokenAttempts = 5
+
+// lockoutKeys returns the keys failed sign in attempts are counted under, along with the limit for each key.
+func (p LockoutPolicy) lockoutKeys(username, ip string) map[string]int {
+	keys := map[string]int{usernameLockoutKey(username): p.MaxAttempts}
+	if len(ip) != 0 {
+		keys[ipLockoutKey(ip)] = p.MaxAttemptsPerIP
+	}
+	return keys
+}
+
+// secondFactorLockoutKeys returns the keys wrong second factor codes are counted under, along with the limit for each
+// key. They are counted per user rather than per username, apart from wrong passwords, so that signing in with the
+// right password again doesn't reset them.
+func (p LockoutPolicy) secondFactorLockoutKeys(userID, ip string) map[string]int {
+	keys := map[string]int{mfaUserLockoutKey(userID): p.MaxAttempts}
+	if len(ip) != 0 {
+		keys[ipLockoutKey(ip)] = p.MaxAttemptsPerIP
+	}
+	return keys
+}
+
+// delay returns how long sign in is locked after the given number of failures.
+func (p LockoutPolicy) delay(failures int64, maxAttempts int) time.Duration {
+	if failures >= int64(maxAttempts) {
+		return p.Lockout
+	}
+
+	delay := p.Backoff
+	for i := int64(1); i < failures && delay < p.Lockout; i++ {
+		delay *= 2
+	}
+	if delay > p.Lockout {
+		return p.Lockout
+	}
+	return delay
+}
+
+// checkSignInLock returns a SignInLockedError if sign in is locked for the username or the client IP address.
+func checkSignInLock(ctx context.Context, finder SignInLockFinder, policy LockoutPolicy, username, ip string) error {
+	return checkLocks(ctx, finder, policy.lockoutKeys(username, ip))
+}
+
+// checkSecondFactorLock returns a SignInLockedError if checking second factor codes is locked for the user or the
+// client IP address.
+func checkSecondFactorLock(
+	ctx context.Context,
+	finder SignInLockFinder, policy LockoutPolicy,
+	userID, ip string,
+) error {
+	return checkLocks(ctx, finder, policy.secondFactorLockoutKeys(userID, ip))
+}
+
+// checkLocks returns a SignInLockedError if any of the keys is locked.
+func checkLocks(ctx context.Context, finder SignInLockFinder, keys map[string]int) error {
+	var retryAfter time.Duration
+	for key := range keys {
+		ttl, err := finder.FindSignInLock(ctx, key)
+		if errors.Is(err, domainlockout.ErrLockNotFound) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to find sign in lock: %w", err)
+		}
+
+		if ttl > retryAfter {
+			retryAfter = ttl
+		}
+	}
+
+	if retryAfter > 0 {
+		return SignInLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// registerSignInFailure counts a failed sign in attempt for the username and the client IP address and locks sign in
+// as the policy requires.
+func registerSignInFailure(
+	ctx context.Context,
+	failureSaver SignInFailureSaver, lockSaver SignInLockSaver,
+	policy LockoutPolicy,
+	username, ip string,
+) error {
+	return registerFailures(ctx, failureSaver, lockSaver, policy, policy.lockoutKeys(username, ip))
+}
+
+// registerSecondFactorFailure counts a wrong second factor code for the user and the client IP address and locks
+// checking them as the policy requires.
+func registerSecondFactorFailure(
+	ctx context.Context,
+	failureSaver SignInFailureSaver, lockSaver SignInLockSaver,
+	policy LockoutPolicy,
+	userID, ip string,
+) error {
+	return registerFailures(ctx, failureSaver, lockSaver, policy, policy.secondFactorLockoutKeys(userID, ip))
+}
+
+// registerFailures counts a failure under each of the keys and locks the keys as the policy requires.
+func registerFailures(
+	ctx context.Context,
+	failureSaver SignInFailureSaver, lockSaver SignInLockSaver,
+	policy LockoutPolicy,
+	keys map[string]int,
+) error {
+	for key, maxAttempts := range keys {
+		failures, err := failureSaver.SaveSignInFailure(ctx, key, policy.Window)
+		if err != nil {
+			return fmt.Errorf("failed to save sign in failure: %w", err)
+		}
+
+		if delay := policy.delay(failures, maxAttempts); delay > 0 {
+			if err := lockSaver.SaveSignInLock(ctx, key, delay); err != nil {
+				return fmt.Errorf("failed to save sign in lock: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkMFATokenLock reports whether an MFA challenge token is rejected after too many wrong codes.
+func checkMFATokenLock(ctx context.Context, finder SignInLockFinder, token string) (bool, error) {
+	_, err := finder.FindSignInLock(ctx, mfaTokenLockoutKey(token))
+	if errors.Is(err, domainlockout.ErrLockNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to find sign in lock: %w", err)
+	}
+	return true, nil
+}
+
+// registerMFATokenFailure counts a wrong code for an MFA challenge token that expires in expiresIn, and rejects the
+// token once it reaches maxMFATokenAttempts.
+func registerMFATokenFailure(
+	ctx context.Context,
+	failureSaver SignInFailureSaver, lockSaver SignInLockSaver,
+	token string, expiresIn time.Duration,
+) error {
+	failures, err := failureSaver.SaveSignInFailure(ctx, mfaTokenLockoutKey(token), expiresIn)
+	if err != nil {
+		return fmt.Errorf("failed to save sign in failure: %w", err)
+	}
+
+	if failures >= maxMFATokenAttempts {
+		if err := lockSaver.SaveSignInLock(ctx, mfaTokenLockoutKey(token), expiresIn); err != nil {
+			return fmt.Errorf("failed to save sign in lock: %w", err)
+		}
+	}
+	return nil
+}
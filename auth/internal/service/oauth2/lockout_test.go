@@ -0,0 +1,177 @@
+package oauth2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	storagememory "github.com/nazarslota/unotes/auth/internal/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockoutPolicy_delay(t *testing.T) {
+	policy := LockoutPolicy{MaxAttempts: 5, Backoff: time.Second, Lockout: 10 * time.Second}
+
+	t.Run("should double backoff with each failure", func(t *testing.T) {
+		assert.Equal(t, time.Second, policy.delay(1, policy.MaxAttempts))
+		assert.Equal(t, 2*time.Second, policy.delay(2, policy.MaxAttempts))
+		assert.Equal(t, 4*time.Second, policy.delay(3, policy.MaxAttempts))
+		assert.Equal(t, 8*time.Second, policy.delay(4, policy.MaxAttempts))
+	})
+
+	t.Run("should lock out after max attempts", func(t *testing.T) {
+		assert.Equal(t, 10*time.Second, policy.delay(5, policy.MaxAttempts))
+		assert.Equal(t, 10*time.Second, policy.delay(6, policy.MaxAttempts))
+	})
+
+	t.Run("should cap backoff at lockout", func(t *testing.T) {
+		assert.Equal(t, 10*time.Second, policy.delay(5, 100))
+		assert.Equal(t, 10*time.Second, policy.delay(64, 100))
+	})
+
+	t.Run("should not lock without backoff before max attempts", func(t *testing.T) {
+		policy := LockoutPolicy{MaxAttempts: 3, Lockout: time.Minute}
+		assert.Zero(t, policy.delay(2, policy.MaxAttempts))
+		assert.Equal(t, time.Minute, policy.delay(3, policy.MaxAttempts))
+	})
+}
+
+// newSignInAttemptRepository creates an in-memory repository of sign in attempts on a database of its own.
+func newSignInAttemptRepository(t *testing.T) *storagememory.SignInAttemptRepository {
+	repository, err := storagememory.NewSignInAttemptRepository(storagememory.NewDB())
+	require.NoError(t, err)
+	return repository
+}
+
+func TestSignInLock(t *testing.T) {
+	ctx := context.Background()
+	policy := LockoutPolicy{MaxAttempts: 3, MaxAttemptsPerIP: 5, Lockout: time.Minute, Window: time.Minute}
+
+	// retryAfter returns how long sign in is locked for, or zero if it is not.
+	retryAfter := func(
+		t *testing.T, repository *storagememory.SignInAttemptRepository, username, ip string,
+	) time.Duration {
+		err := checkSignInLock(ctx, repository, policy, username, ip)
+		if err == nil {
+			return 0
+		}
+
+		var locked SignInLockedError
+		require.ErrorAs(t, err, &locked)
+		assert.ErrorIs(t, err, ErrSignInLocked)
+		return locked.RetryAfter
+	}
+
+	t.Run("should lock username after max attempts", func(t *testing.T) {
+		repository := newSignInAttemptRepository(t)
+		for i := 0; i < policy.MaxAttempts-1; i++ {
+			require.NoError(t, registerSignInFailure(ctx, repository, repository, policy, "username", "203.0.113.1"))
+			assert.Zero(t, retryAfter(t, repository, "username", "203.0.113.1"))
+		}
+
+		require.NoError(t, registerSignInFailure(ctx, repository, repository, policy, "username", "203.0.113.1"))
+		assert.InDelta(t, time.Minute, retryAfter(t, repository, "username", "203.0.113.2"), float64(time.Second))
+		assert.Zero(t, retryAfter(t, repository, "other-username", "203.0.113.2"))
+	})
+
+	t.Run("should lock ip address after max attempts per ip", func(t *testing.T) {
+		repository := newSignInAttemptRepository(t)
+		for i := 0; i < policy.MaxAttemptsPerIP; i++ {
+			username := "username-" + string(rune('a'+i))
+			require.NoError(t, registerSignInFailure(ctx, repository, repository, policy, username, "203.0.113.1"))
+		}
+
+		assert.NotZero(t, retryAfter(t, repository, "other-username", "203.0.113.1"))
+		assert.Zero(t, retryAfter(t, repository, "other-username", "203.0.113.2"))
+	})
+
+	t.Run("should back off after each failure", func(t *testing.T) {
+		repository := newSignInAttemptRepository(t)
+		policy := policy
+		policy.Backoff = 10 * time.Second
+
+		require.NoError(t, registerSignInFailure(ctx, repository, repository, policy, "username", ""))
+		assert.InDelta(t, 10*time.Second, retryAfter(t, repository, "username", ""), float64(time.Second))
+
+		require.NoError(t, registerSignInFailure(ctx, repository, repository, policy, "username", ""))
+		assert.InDelta(t, 20*time.Second, retryAfter(t, repository, "username", ""), float64(time.Second))
+	})
+
+	t.Run("should unlock and forget failures after window", func(t *testing.T) {
+		repository := newSignInAttemptRepository(t)
+		policy := LockoutPolicy{MaxAttempts: 2, Lockout: 50 * time.Millisecond, Window: 50 * time.Millisecond}
+
+		require.NoError(t, registerSignInFailure(ctx, repository, repository, policy, "username", ""))
+		require.NoError(t, registerSignInFailure(ctx, repository, repository, policy, "username", ""))
+		assert.NotZero(t, retryAfter(t, repository, "username", ""))
+
+		time.Sleep(60 * time.Millisecond)
+		assert.Zero(t, retryAfter(t, repository, "username", ""))
+
+		// The failures before the window are forgotten, so a single failure doesn't lock again.
+		require.NoError(t, registerSignInFailure(ctx, repository, repository, policy, "username", ""))
+		assert.Zero(t, retryAfter(t, repository, "username", ""))
+	})
+
+	t.Run("should reset failures on success", func(t *testing.T) {
+		repository := newSignInAttemptRepository(t)
+		for i := 0; i < policy.MaxAttempts; i++ {
+			require.NoError(t, registerSignInFailure(ctx, repository, repository, policy, "username", ""))
+		}
+		require.NotZero(t, retryAfter(t, repository, "username", ""))
+
+		require.NoError(t, repository.DeleteSignInFailures(ctx, usernameLockoutKey("username")))
+		assert.Zero(t, retryAfter(t, repository, "username", ""))
+
+		require.NoError(t, registerSignInFailure(ctx, repository, repository, policy, "username", ""))
+		assert.Zero(t, retryAfter(t, repository, "username", ""))
+	})
+
+	t.Run("should count second factor failures per user", func(t *testing.T) {
+		repository := newSignInAttemptRepository(t)
+		for i := 0; i < policy.MaxAttempts; i++ {
+			require.NoError(t, registerSecondFactorFailure(ctx, repository, repository, policy, "user-id", ""))
+		}
+
+		err := checkSecondFactorLock(ctx, repository, policy, "user-id", "")
+		assert.ErrorIs(t, err, ErrSignInLocked)
+		assert.NoError(t, checkSecondFactorLock(ctx, repository, policy, "other-user-id", ""))
+		assert.NoError(t, checkSignInLock(ctx, repository, policy, "user-id", ""))
+	})
+}
+
+func TestMFATokenLock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should reject token after max attempts", func(t *testing.T) {
+		repository := newSignInAttemptRepository(t)
+		for i := 0; i < maxMFATokenAttempts; i++ {
+			locked, err := checkMFATokenLock(ctx, repository, "mfa-token")
+			require.NoError(t, err)
+			assert.False(t, locked)
+
+			require.NoError(t, registerMFATokenFailure(ctx, repository, repository, "mfa-token", time.Minute))
+		}
+
+		locked, err := checkMFATokenLock(ctx, repository, "mfa-token")
+		require.NoError(t, err)
+		assert.True(t, locked)
+
+		locked, err = checkMFATokenLock(ctx, repository, "other-mfa-token")
+		require.NoError(t, err)
+		assert.False(t, locked)
+	})
+
+	t.Run("should unlock token once it expires", func(t *testing.T) {
+		repository := newSignInAttemptRepository(t)
+		for i := 0; i < maxMFATokenAttempts; i++ {
+			require.NoError(t, registerMFATokenFailure(ctx, repository, repository, "mfa-token", 50*time.Millisecond))
+		}
+
+		time.Sleep(60 * time.Millisecond)
+		locked, err := checkMFATokenLock(ctx, repository, "mfa-token")
+		require.NoError(t, err)
+		assert.False(t, locked)
+	})
+}
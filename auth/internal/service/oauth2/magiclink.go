@@ -0,0 +1,313 @@
+package oauth2
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainmagiclink "github.com/nazarslota/unotes/auth/internal/domain/magiclink"
+	domaintotp "github.com/nazarslota/unotes/auth/internal/domain/totp"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+)
+
+// MagicLinkRateLimit limits how many magic links can be requested within Window, MaxRequests for an account and
+// MaxRequestsPerIP from a client IP address.
+type MagicLinkRateLimit struct {
+	MaxRequests      int
+	MaxRequestsPerIP int
+	Window           time.Duration
+}
+
+func magicLinkUserKey(userID string) string { return "user:" + userID }
+func magicLinkIPKey(ip string) string       { return "ip:" + ip }
+
+// RequestMagicLinkRequest identifies the user either by username or by email. IP is the client IP address, requests
+// are counted per account and per IP address. Nonce is a secret kept by the browser that requests the link, the link
+// can only be used together with it.
+type RequestMagicLinkRequest struct {
+	Username string
+	Email    string
+	IP       string
+	Nonce    string
+}
+
+type RequestMagicLinkResponse struct{}
+
+// RequestMagicLinkRequestHandler mails a single-use sign in link to the verified email of the user. It succeeds for
+// unknown users, users without a verified email and accounts that requested too many links as well, so it can not be
+// used to find out which accounts exist.
+type RequestMagicLinkRequestHandler interface {
+	Handle(ctx context.Context, request RequestMagicLinkRequest) (RequestMagicLinkResponse, error)
+}
+
+type requestMagicLinkRequestHandler struct {
+	MagicLinkURL            string
+	MagicLinkTokenExpiresIn time.Duration
+	MagicLinkRateLimit      MagicLinkRateLimit
+
+	UserFinder            UserFinder
+	MagicLinkTokenSaver   MagicLinkTokenSaver
+	MagicLinkRequestSaver MagicLinkRequestSaver
+
+	Mailer Mailer
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrRequestMagicLinkInvalidUser     = errRequestMagicLinkInvalidUser()
+	ErrRequestMagicLinkInvalidNonce    = errRequestMagicLinkInvalidNonce()
+	ErrRequestMagicLinkTooManyRequests = errRequestMagicLinkTooManyRequests()
+)
+
+func errRequestMagicLinkInvalidUser() error     { return errors.New("invalid username or email") }
+func errRequestMagicLinkInvalidNonce() error    { return errors.New("invalid nonce") }
+func errRequestMagicLinkTooManyRequests() error { return errors.New("too many requests") }
+
+func NewRequestMagicLinkRequestHandler(
+	magicLinkURL string, magicLinkTokenExpiresIn time.Duration,
+	magicLinkRateLimit MagicLinkRateLimit,
+	userFinder UserFinder, magicLinkTokenSaver MagicLinkTokenSaver, magicLinkRequestSaver MagicLinkRequestSaver,
+	mailer Mailer,
+	auditLogger AuditLogger,
+) RequestMagicLinkRequestHandler {
+	return &requestMagicLinkRequestHandler{
+		MagicLinkURL:            magicLinkURL,
+		MagicLinkTokenExpiresIn: magicLinkTokenExpiresIn,
+		MagicLinkRateLimit:      magicLinkRateLimit,
+
+		UserFinder:            userFinder,
+		MagicLinkTokenSaver:   magicLinkTokenSaver,
+		MagicLinkRequestSaver: magicLinkRequestSaver,
+
+		Mailer: mailer,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h requestMagicLinkRequestHandler) Handle(ctx context.Context, request RequestMagicLinkRequest) (_ RequestMagicLinkResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeMagicLinkRequest}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	if len(request.Nonce) == 0 {
+		return RequestMagicLinkResponse{}, ErrRequestMagicLinkInvalidNonce
+	}
+
+	if len(request.IP) != 0 {
+		exceeded, err := h.exceeded(ctx, magicLinkIPKey(request.IP), h.MagicLinkRateLimit.MaxRequestsPerIP)
+		if err != nil {
+			return RequestMagicLinkResponse{}, err
+		} else if exceeded {
+			return RequestMagicLinkResponse{}, ErrRequestMagicLinkTooManyRequests
+		}
+	}
+
+	var user domainuser.User
+	if len(request.Username) != 0 {
+		user, err = h.UserFinder.FindUserByUsername(ctx, request.Username)
+	} else if len(request.Email) != 0 {
+		user, err = h.UserFinder.FindUserByEmail(ctx, request.Email)
+	} else {
+		return RequestMagicLinkResponse{}, ErrRequestMagicLinkInvalidUser
+	}
+
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		return RequestMagicLinkResponse{}, nil
+	} else if err != nil {
+		return RequestMagicLinkResponse{}, fmt.Errorf("failed to find user: %w", err)
+	}
+	event.UserID = user.ID
+	if len(user.Email) == 0 || !user.EmailVerified || user.Disabled {
+		return RequestMagicLinkResponse{}, nil
+	}
+
+	// Telling the client that the account requested too many links would tell it that the account exists, so the
+	// request only looks like it succeeded.
+	exceeded, err := h.exceeded(ctx, magicLinkUserKey(user.ID), h.MagicLinkRateLimit.MaxRequests)
+	if err != nil {
+		return RequestMagicLinkResponse{}, err
+	} else if exceeded {
+		event.Reason = "too many requests"
+		return RequestMagicLinkResponse{}, nil
+	}
+
+	token, err := newOpaqueToken()
+	if err != nil {
+		return RequestMagicLinkResponse{}, fmt.Errorf("failed to create magic link token: %w", err)
+	}
+
+	if err := h.MagicLinkTokenSaver.SaveMagicLinkToken(ctx, domainmagiclink.Token{
+		TokenHash: hashOpaqueToken(token),
+		UserID:    user.ID,
+		NonceHash: hashOpaqueToken(request.Nonce),
+		ExpiresAt: time.Now().Add(h.MagicLinkTokenExpiresIn),
+	}); err != nil {
+		return RequestMagicLinkResponse{}, fmt.Errorf("failed to save magic link token: %w", err)
+	}
+
+	link, err := tokenLink(h.MagicLinkURL, token)
+	if err != nil {
+		return RequestMagicLinkResponse{}, err
+	}
+
+	body := fmt.Sprintf("To sign in, follow the link below within %s, in the same browser you requested it from:\n\n"+
+		"%s\n\nIf you did not request to sign in, you can ignore this email.\n", h.MagicLinkTokenExpiresIn, link)
+	if err := h.Mailer.SendMail(ctx, user.Email, "Sign in to your account", body); err != nil {
+		return RequestMagicLinkResponse{}, fmt.Errorf("failed to send mail: %w", err)
+	}
+	return RequestMagicLinkResponse{}, nil
+}
+
+// exceeded counts a request under the key and reports whether there were more than maxRequests within the window.
+func (h requestMagicLinkRequestHandler) exceeded(ctx context.Context, key string, maxRequests int) (bool, error) {
+	requests, err := h.MagicLinkRequestSaver.SaveMagicLinkRequest(ctx, key, h.MagicLinkRateLimit.Window)
+	if err != nil {
+		return false, fmt.Errorf("failed to save magic link request: %w", err)
+	}
+	return requests > int64(maxRequests), nil
+}
+
+// ConfirmMagicLinkRequest holds the token from the link along with the nonce of the browser that follows it.
+type ConfirmMagicLinkRequest struct {
+	Token string
+	Nonce string
+}
+
+// ConfirmMagicLinkResponse holds either the token pair or, when the user has two-factor authentication enabled, a
+// short-lived challenge token that must be exchanged for the token pair with SignInMFARequestHandler.
+type ConfirmMagicLinkResponse struct {
+	AccessToken  string
+	RefreshToken string
+
+	MFARequired bool
+	MFAToken    string
+}
+
+// ConfirmMagicLinkRequestHandler signs the user in with a magic link. The link stands in for the password only, so a
+// user with two-factor authentication enabled still has to pass the second factor.
+type ConfirmMagicLinkRequestHandler interface {
+	Handle(ctx context.Context, request ConfirmMagicLinkRequest) (ConfirmMagicLinkResponse, error)
+}
+
+type confirmMagicLinkRequestHandler struct {
+	AccessTokenCreator   AccessTokenCreator
+	AccessTokenExpiresIn time.Duration
+
+	RefreshTokenCreator   RefreshTokenCreator
+	RefreshTokenExpiresIn time.Duration
+
+	MFATokenCreator   MFATokenCreator
+	MFATokenExpiresIn time.Duration
+
+	RefreshTokenSaver RefreshTokenSaver
+
+	UserFinder             UserFinder
+	TOTPFinder             TOTPFinder
+	MagicLinkTokenConsumer MagicLinkTokenConsumer
+	MagicLinkTokensDeleter MagicLinkTokensDeleter
+
+	AuditLogger AuditLogger
+}
+
+var ErrConfirmMagicLinkInvalidToken = errConfirmMagicLinkInvalidToken()
+
+func errConfirmMagicLinkInvalidToken() error { return errors.New("invalid or expired token") }
+
+func NewConfirmMagicLinkRequestHandler(
+	accessTokenCreator AccessTokenCreator, accessTokenExpiresIn time.Duration,
+	refreshTokenCreator RefreshTokenCreator, refreshTokenExpiresIn time.Duration,
+	mfaTokenCreator MFATokenCreator, mfaTokenExpiresIn time.Duration,
+	refreshTokenSaver RefreshTokenSaver,
+	userFinder UserFinder, totpFinder TOTPFinder,
+	magicLinkTokenConsumer MagicLinkTokenConsumer, magicLinkTokensDeleter MagicLinkTokensDeleter,
+	auditLogger AuditLogger,
+) ConfirmMagicLinkRequestHandler {
+	return &confirmMagicLinkRequestHandler{
+		AccessTokenCreator:   accessTokenCreator,
+		AccessTokenExpiresIn: accessTokenExpiresIn,
+
+		RefreshTokenCreator:   refreshTokenCreator,
+		RefreshTokenExpiresIn: refreshTokenExpiresIn,
+
+		MFATokenCreator:   mfaTokenCreator,
+		MFATokenExpiresIn: mfaTokenExpiresIn,
+
+		RefreshTokenSaver: refreshTokenSaver,
+
+		UserFinder:             userFinder,
+		TOTPFinder:             totpFinder,
+		MagicLinkTokenConsumer: magicLinkTokenConsumer,
+		MagicLinkTokensDeleter: magicLinkTokensDeleter,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h confirmMagicLinkRequestHandler) Handle(ctx context.Context, request ConfirmMagicLinkRequest) (_ ConfirmMagicLinkResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeMagicLinkSignIn}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	if len(request.Token) == 0 {
+		return ConfirmMagicLinkResponse{}, ErrConfirmMagicLinkInvalidToken
+	}
+
+	// The token is consumed before the nonce is compared, so a link that was forwarded to another browser can not be
+	// used by anyone afterwards.
+	token, err := h.MagicLinkTokenConsumer.ConsumeMagicLinkToken(ctx, hashOpaqueToken(request.Token))
+	if errors.Is(err, domainmagiclink.ErrTokenNotFound) {
+		err = fmt.Errorf("failed to consume magic link token: %w", err)
+		return ConfirmMagicLinkResponse{}, errors.Join(err, ErrConfirmMagicLinkInvalidToken)
+	} else if err != nil {
+		return ConfirmMagicLinkResponse{}, fmt.Errorf("failed to consume magic link token: %w", err)
+	}
+	event.UserID = token.UserID
+
+	nonceHash := hashOpaqueToken(request.Nonce)
+	if len(request.Nonce) == 0 || subtle.ConstantTimeCompare([]byte(nonceHash), []byte(token.NonceHash)) != 1 {
+		event.Reason = "nonce mismatch"
+		return ConfirmMagicLinkResponse{}, ErrConfirmMagicLinkInvalidToken
+	}
+
+	user, err := h.UserFinder.FindUserByUserID(ctx, token.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return ConfirmMagicLinkResponse{}, errors.Join(err, ErrConfirmMagicLinkInvalidToken)
+	} else if err != nil {
+		return ConfirmMagicLinkResponse{}, fmt.Errorf("failed to find user: %w", err)
+	} else if user.Disabled {
+		return ConfirmMagicLinkResponse{}, ErrConfirmMagicLinkInvalidToken
+	}
+
+	if err := h.MagicLinkTokensDeleter.DeleteMagicLinkTokens(ctx, user.ID); err != nil {
+		return ConfirmMagicLinkResponse{}, fmt.Errorf("failed to delete magic link tokens: %w", err)
+	}
+
+	totp, err := h.TOTPFinder.FindTOTPByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, domaintotp.ErrTOTPNotFound) {
+		return ConfirmMagicLinkResponse{}, fmt.Errorf("failed to find totp: %w", err)
+	} else if err == nil && totp.Enabled {
+		mfaToken, err := newMFAToken(h.MFATokenCreator, h.MFATokenExpiresIn, user)
+		if err != nil {
+			return ConfirmMagicLinkResponse{}, err
+		}
+		event.Reason = "mfa required"
+		return ConfirmMagicLinkResponse{MFARequired: true, MFAToken: mfaToken}, nil
+	}
+
+	event.SessionID = uuid.New().String()
+	accessToken, refreshToken, err := newTokenPair(ctx,
+		h.AccessTokenCreator, h.AccessTokenExpiresIn,
+		h.RefreshTokenCreator, h.RefreshTokenExpiresIn,
+		h.RefreshTokenSaver,
+		user, event.SessionID,
+	)
+	if err != nil {
+		return ConfirmMagicLinkResponse{}, err
+	}
+	return ConfirmMagicLinkResponse{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
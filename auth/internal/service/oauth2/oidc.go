@@ -0,0 +1,99 @@
+package oauth2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"golang.org/x/exp/slices"
+)
+
+// OpenID Connect scopes. ScopeOpenID makes the token endpoint issue an ID token along with the access token, and
+// ScopeEmail releases the email claims.
+const (
+	ScopeOpenID = "openid"
+	ScopeEmail  = "email"
+)
+
+// UserInfo holds the OpenID Connect claims about a user, which are the same in ID tokens and userinfo responses.
+// Subject is the user ID. PreferredUsername is always released, the email claims only with the email scope and if the
+// user has an email address.
+type UserInfo struct {
+	Subject           string
+	PreferredUsername string
+	Email             string
+	EmailVerified     bool
+}
+
+func newUserInfo(user domainuser.User, scope string) UserInfo {
+	info := UserInfo{Subject: user.ID, PreferredUsername: user.Username}
+	if slices.Contains(strings.Fields(scope), ScopeEmail) && len(user.Email) != 0 {
+		info.Email, info.EmailVerified = user.Email, user.EmailVerified
+	}
+	return info
+}
+
+// newIDToken creates an ID token for the client with the claims about the user the scope releases.
+func newIDToken(
+	idTokenCreator IDTokenCreator, issuer string, expiresIn time.Duration,
+	user domainuser.User, clientID string, scope string, nonce string,
+) (string, error) {
+	info := newUserInfo(user, scope)
+
+	now := time.Now()
+	token, err := idTokenCreator.New(jwt.IDTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   info.Subject,
+			Audience:  gojwt.ClaimStrings{clientID},
+			ExpiresAt: gojwt.NewNumericDate(now.Add(expiresIn)),
+			IssuedAt:  gojwt.NewNumericDate(now),
+		},
+		Nonce:             nonce,
+		PreferredUsername: info.PreferredUsername,
+		Email:             info.Email,
+		EmailVerified:     info.EmailVerified,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create id token: %w", err)
+	}
+	return token, nil
+}
+
+type DiscoveryRequest struct{}
+
+// DiscoveryResponse describes the OpenID Connect provider. Endpoint URLs are relative to Issuer.
+type DiscoveryResponse struct {
+	Issuer              string
+	ScopesSupported     []string
+	GrantTypesSupported []string
+	KeySet              jwt.KeySet
+}
+
+type DiscoveryRequestHandler interface {
+	Handle(ctx context.Context, request DiscoveryRequest) (DiscoveryResponse, error)
+}
+
+type discoveryRequestHandler struct {
+	Issuer        string
+	IDTokenKeySet IDTokenKeySet
+}
+
+func NewDiscoveryRequestHandler(issuer string, idTokenKeySet IDTokenKeySet) DiscoveryRequestHandler {
+	return &discoveryRequestHandler{Issuer: issuer, IDTokenKeySet: idTokenKeySet}
+}
+
+func (h discoveryRequestHandler) Handle(_ context.Context, _ DiscoveryRequest) (DiscoveryResponse, error) {
+	return DiscoveryResponse{
+		Issuer:          h.Issuer,
+		ScopesSupported: []string{ScopeOpenID, ScopeEmail},
+		GrantTypesSupported: []string{
+			GrantTypeAuthorizationCode, GrantTypeRefreshToken, GrantTypePassword, GrantTypeClientCredentials,
+		},
+		KeySet: h.IDTokenKeySet.KeySet(),
+	}, nil
+}
@@ -0,0 +1,420 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainpasskey "github.com/nazarslota/unotes/auth/internal/domain/passkey"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+)
+
+// Passkey describes a passkey of the user, without its public key. LastUsedAt is nil until the passkey is used.
+type Passkey struct {
+	ID         string
+	Name       string
+	Transports []string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+func newPasskey(credential domainpasskey.Credential) Passkey {
+	return Passkey{
+		ID:         credential.ID,
+		Name:       credential.Name,
+		Transports: credential.Transports,
+		CreatedAt:  credential.CreatedAt,
+		LastUsedAt: credential.LastUsedAt,
+	}
+}
+
+// BeginPasskeyRegistrationRequest starts registering a passkey for the signed-in user.
+type BeginPasskeyRegistrationRequest struct {
+	AccessToken string
+}
+
+// BeginPasskeyRegistrationResponse holds the options to pass to navigator.credentials.create and the session token
+// to finish the registration with.
+type BeginPasskeyRegistrationResponse struct {
+	SessionToken string
+	Options      []byte
+}
+
+type BeginPasskeyRegistrationRequestHandler interface {
+	Handle(ctx context.Context, request BeginPasskeyRegistrationRequest) (BeginPasskeyRegistrationResponse, error)
+}
+
+type beginPasskeyRegistrationRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	SessionExpiresIn time.Duration
+	RelyingParty     PasskeyRelyingParty
+
+	UserFinder          UserFinder
+	PasskeysFinder      PasskeysFinder
+	PasskeySessionSaver PasskeySessionSaver
+
+	AuditLogger AuditLogger
+}
+
+var ErrBeginPasskeyRegistrationInvalidOrExpiredToken = errBeginPasskeyRegistrationInvalidOrExpiredToken()
+
+func errBeginPasskeyRegistrationInvalidOrExpiredToken() error {
+	return errors.New("invalid or expired token")
+}
+
+func NewBeginPasskeyRegistrationRequestHandler(
+	accessTokenParser AccessTokenParser,
+	sessionExpiresIn time.Duration, relyingParty PasskeyRelyingParty,
+	userFinder UserFinder, passkeysFinder PasskeysFinder, passkeySessionSaver PasskeySessionSaver,
+	auditLogger AuditLogger,
+) BeginPasskeyRegistrationRequestHandler {
+	return &beginPasskeyRegistrationRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		SessionExpiresIn: sessionExpiresIn,
+		RelyingParty:     relyingParty,
+
+		UserFinder:          userFinder,
+		PasskeysFinder:      passkeysFinder,
+		PasskeySessionSaver: passkeySessionSaver,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h beginPasskeyRegistrationRequestHandler) Handle(
+	ctx context.Context, request BeginPasskeyRegistrationRequest,
+) (_ BeginPasskeyRegistrationResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypePasskeyRegisterBegin}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return BeginPasskeyRegistrationResponse{}, errors.Join(err, ErrBeginPasskeyRegistrationInvalidOrExpiredToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+
+	user, err := h.UserFinder.FindUserByUserID(ctx, claims.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return BeginPasskeyRegistrationResponse{}, errors.Join(err, ErrBeginPasskeyRegistrationInvalidOrExpiredToken)
+	} else if err != nil {
+		return BeginPasskeyRegistrationResponse{}, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	credentials, err := h.PasskeysFinder.FindPasskeys(ctx, user.ID)
+	if err != nil {
+		return BeginPasskeyRegistrationResponse{}, fmt.Errorf("failed to find passkeys: %w", err)
+	}
+
+	options, data, err := h.RelyingParty.BeginRegistration(user, credentials)
+	if err != nil {
+		return BeginPasskeyRegistrationResponse{}, err
+	}
+
+	sessionToken, err := savePasskeySession(ctx,
+		h.PasskeySessionSaver, h.SessionExpiresIn,
+		domainpasskey.PurposeRegistration, user.ID, data,
+	)
+	if err != nil {
+		return BeginPasskeyRegistrationResponse{}, err
+	}
+	return BeginPasskeyRegistrationResponse{SessionToken: sessionToken, Options: options}, nil
+}
+
+// FinishPasskeyRegistrationRequest finishes registering a passkey with the response of the authenticator. Name
+// describes the passkey, such as the device it is on.
+type FinishPasskeyRegistrationRequest struct {
+	AccessToken  string
+	SessionToken string
+	Name         string
+	Credential   []byte
+}
+
+type FinishPasskeyRegistrationResponse struct {
+	Passkey
+}
+
+type FinishPasskeyRegistrationRequestHandler interface {
+	Handle(ctx context.Context, request FinishPasskeyRegistrationRequest) (FinishPasskeyRegistrationResponse, error)
+}
+
+type finishPasskeyRegistrationRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	RelyingParty PasskeyRelyingParty
+
+	UserFinder             UserFinder
+	PasskeySaver           PasskeySaver
+	PasskeySessionConsumer PasskeySessionConsumer
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrFinishPasskeyRegistrationInvalidOrExpiredToken = errFinishPasskeyRegistrationInvalidOrExpiredToken()
+	ErrFinishPasskeyRegistrationInvalidName           = errFinishPasskeyRegistrationInvalidName()
+	ErrFinishPasskeyRegistrationInvalidSession        = errFinishPasskeyRegistrationInvalidSession()
+	ErrFinishPasskeyRegistrationInvalidCredential     = errFinishPasskeyRegistrationInvalidCredential()
+	ErrFinishPasskeyRegistrationAlreadyExists         = errFinishPasskeyRegistrationAlreadyExists()
+)
+
+func errFinishPasskeyRegistrationInvalidOrExpiredToken() error {
+	return errors.New("invalid or expired token")
+}
+func errFinishPasskeyRegistrationInvalidName() error { return errors.New("invalid name") }
+func errFinishPasskeyRegistrationInvalidSession() error {
+	return errors.New("invalid or expired session")
+}
+func errFinishPasskeyRegistrationInvalidCredential() error { return errors.New("invalid credential") }
+func errFinishPasskeyRegistrationAlreadyExists() error {
+	return domainpasskey.ErrCredentialAlreadyExists
+}
+
+func NewFinishPasskeyRegistrationRequestHandler(
+	accessTokenParser AccessTokenParser,
+	relyingParty PasskeyRelyingParty,
+	userFinder UserFinder, passkeySaver PasskeySaver, passkeySessionConsumer PasskeySessionConsumer,
+	auditLogger AuditLogger,
+) FinishPasskeyRegistrationRequestHandler {
+	return &finishPasskeyRegistrationRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		RelyingParty: relyingParty,
+
+		UserFinder:             userFinder,
+		PasskeySaver:           passkeySaver,
+		PasskeySessionConsumer: passkeySessionConsumer,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h finishPasskeyRegistrationRequestHandler) Handle(
+	ctx context.Context, request FinishPasskeyRegistrationRequest,
+) (_ FinishPasskeyRegistrationResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypePasskeyRegister}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return FinishPasskeyRegistrationResponse{}, errors.Join(err, ErrFinishPasskeyRegistrationInvalidOrExpiredToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+
+	name := strings.TrimSpace(request.Name)
+	if len(name) == 0 || utf8.RuneCountInString(name) > 64 || strings.IndexFunc(name, unicode.IsControl) != -1 {
+		return FinishPasskeyRegistrationResponse{}, ErrFinishPasskeyRegistrationInvalidName
+	}
+
+	session, err := consumePasskeySession(ctx,
+		h.PasskeySessionConsumer,
+		request.SessionToken, domainpasskey.PurposeRegistration, claims.UserID,
+	)
+	if errors.Is(err, errPasskeySessionInvalid) {
+		return FinishPasskeyRegistrationResponse{}, errors.Join(err, ErrFinishPasskeyRegistrationInvalidSession)
+	} else if err != nil {
+		return FinishPasskeyRegistrationResponse{}, err
+	}
+
+	user, err := h.UserFinder.FindUserByUserID(ctx, claims.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return FinishPasskeyRegistrationResponse{}, errors.Join(err, ErrFinishPasskeyRegistrationInvalidOrExpiredToken)
+	} else if err != nil {
+		return FinishPasskeyRegistrationResponse{}, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	credential, err := h.RelyingParty.FinishRegistration(user, session.Data, request.Credential)
+	if err != nil {
+		return FinishPasskeyRegistrationResponse{}, errors.Join(err, ErrFinishPasskeyRegistrationInvalidCredential)
+	}
+	credential.Name = name
+	credential.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	err = h.PasskeySaver.SavePasskey(ctx, credential)
+	if errors.Is(err, domainpasskey.ErrCredentialAlreadyExists) {
+		err = fmt.Errorf("failed to save passkey: %w", err)
+		return FinishPasskeyRegistrationResponse{}, errors.Join(err, ErrFinishPasskeyRegistrationAlreadyExists)
+	} else if err != nil {
+		return FinishPasskeyRegistrationResponse{}, fmt.Errorf("failed to save passkey: %w", err)
+	}
+	return FinishPasskeyRegistrationResponse{Passkey: newPasskey(credential)}, nil
+}
+
+type ListPasskeysRequest struct {
+	AccessToken string
+}
+
+type ListPasskeysResponse struct {
+	Passkeys []Passkey
+}
+
+type ListPasskeysRequestHandler interface {
+	Handle(ctx context.Context, request ListPasskeysRequest) (ListPasskeysResponse, error)
+}
+
+type listPasskeysRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	PasskeysFinder PasskeysFinder
+}
+
+var ErrListPasskeysInvalidOrExpiredToken = errListPasskeysInvalidOrExpiredToken()
+
+func errListPasskeysInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+
+func NewListPasskeysRequestHandler(
+	accessTokenParser AccessTokenParser,
+	passkeysFinder PasskeysFinder,
+) ListPasskeysRequestHandler {
+	return &listPasskeysRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		PasskeysFinder: passkeysFinder,
+	}
+}
+
+func (h listPasskeysRequestHandler) Handle(
+	ctx context.Context, request ListPasskeysRequest,
+) (ListPasskeysResponse, error) {
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return ListPasskeysResponse{}, errors.Join(err, ErrListPasskeysInvalidOrExpiredToken)
+	}
+
+	credentials, err := h.PasskeysFinder.FindPasskeys(ctx, claims.UserID)
+	if err != nil {
+		return ListPasskeysResponse{}, fmt.Errorf("failed to find passkeys: %w", err)
+	}
+
+	response := ListPasskeysResponse{Passkeys: make([]Passkey, 0, len(credentials))}
+	for _, credential := range credentials {
+		response.Passkeys = append(response.Passkeys, newPasskey(credential))
+	}
+	return response, nil
+}
+
+type DeletePasskeyRequest struct {
+	AccessToken string
+	ID          string
+}
+
+type DeletePasskeyResponse struct {
+}
+
+type DeletePasskeyRequestHandler interface {
+	Handle(ctx context.Context, request DeletePasskeyRequest) (DeletePasskeyResponse, error)
+}
+
+type deletePasskeyRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	PasskeyDeleter PasskeyDeleter
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrDeletePasskeyInvalidOrExpiredToken = errDeletePasskeyInvalidOrExpiredToken()
+	ErrDeletePasskeyNotFound              = errDeletePasskeyNotFound()
+)
+
+func errDeletePasskeyInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errDeletePasskeyNotFound() error              { return domainpasskey.ErrCredentialNotFound }
+
+func NewDeletePasskeyRequestHandler(
+	accessTokenParser AccessTokenParser,
+	passkeyDeleter PasskeyDeleter,
+	auditLogger AuditLogger,
+) DeletePasskeyRequestHandler {
+	return &deletePasskeyRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		PasskeyDeleter: passkeyDeleter,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h deletePasskeyRequestHandler) Handle(
+	ctx context.Context, request DeletePasskeyRequest,
+) (_ DeletePasskeyResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypePasskeyDelete}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return DeletePasskeyResponse{}, errors.Join(err, ErrDeletePasskeyInvalidOrExpiredToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+
+	err = h.PasskeyDeleter.DeletePasskey(ctx, claims.UserID, request.ID)
+	if errors.Is(err, domainpasskey.ErrCredentialNotFound) {
+		err = fmt.Errorf("failed to delete passkey: %w", err)
+		return DeletePasskeyResponse{}, errors.Join(err, ErrDeletePasskeyNotFound)
+	} else if err != nil {
+		return DeletePasskeyResponse{}, fmt.Errorf("failed to delete passkey: %w", err)
+	}
+	return DeletePasskeyResponse{}, nil
+}
+
+// errPasskeySessionInvalid is returned by consumePasskeySession when the session is not found, has expired or was
+// begun for another purpose or user.
+var errPasskeySessionInvalid = errors.New("invalid passkey session")
+
+// savePasskeySession saves a WebAuthn ceremony in progress under the hash of a new session token and returns the
+// token. UserID is empty when the user is not known yet.
+func savePasskeySession(
+	ctx context.Context,
+	saver PasskeySessionSaver, expiresIn time.Duration,
+	purpose, userID string, data []byte,
+) (string, error) {
+	token, err := newOpaqueToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to create session token: %w", err)
+	}
+
+	err = saver.SavePasskeySession(ctx, domainpasskey.Session{
+		SessionHash: hashOpaqueToken(token),
+		Purpose:     purpose,
+		UserID:      userID,
+		Data:        data,
+		ExpiresAt:   time.Now().Add(expiresIn),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to save passkey session: %w", err)
+	}
+	return token, nil
+}
+
+// consumePasskeySession consumes the WebAuthn ceremony in progress of a session token, so that it can be finished
+// only once, and checks that it was begun for the purpose and the user.
+//
+// Returns errPasskeySessionInvalid if the session is not found, has expired or was begun for another purpose or user.
+func consumePasskeySession(
+	ctx context.Context,
+	consumer PasskeySessionConsumer,
+	token, purpose, userID string,
+) (domainpasskey.Session, error) {
+	session, err := consumer.ConsumePasskeySession(ctx, hashOpaqueToken(token))
+	if errors.Is(err, domainpasskey.ErrSessionNotFound) {
+		err = fmt.Errorf("failed to consume passkey session: %w", err)
+		return domainpasskey.Session{}, errors.Join(err, errPasskeySessionInvalid)
+	} else if err != nil {
+		return domainpasskey.Session{}, fmt.Errorf("failed to consume passkey session: %w", err)
+	} else if session.Purpose != purpose || session.UserID != userID {
+		return domainpasskey.Session{}, errPasskeySessionInvalid
+	}
+	return session, nil
+}
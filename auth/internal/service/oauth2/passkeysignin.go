@@ -0,0 +1,464 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainpasskey "github.com/nazarslota/unotes/auth/internal/domain/passkey"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+)
+
+// BeginPasskeySignInRequest starts signing in with a passkey instead of a password. The user is not asked for, the
+// authenticator offers the passkeys it holds for the service, so nothing tells whether an account exists.
+type BeginPasskeySignInRequest struct {
+}
+
+// BeginPasskeySignInResponse holds the options to pass to navigator.credentials.get and the session token to finish
+// the sign in with.
+type BeginPasskeySignInResponse struct {
+	SessionToken string
+	Options      []byte
+}
+
+type BeginPasskeySignInRequestHandler interface {
+	Handle(ctx context.Context, request BeginPasskeySignInRequest) (BeginPasskeySignInResponse, error)
+}
+
+type beginPasskeySignInRequestHandler struct {
+	SessionExpiresIn time.Duration
+	RelyingParty     PasskeyRelyingParty
+
+	PasskeySessionSaver PasskeySessionSaver
+}
+
+func NewBeginPasskeySignInRequestHandler(
+	sessionExpiresIn time.Duration, relyingParty PasskeyRelyingParty,
+	passkeySessionSaver PasskeySessionSaver,
+) BeginPasskeySignInRequestHandler {
+	return &beginPasskeySignInRequestHandler{
+		SessionExpiresIn: sessionExpiresIn,
+		RelyingParty:     relyingParty,
+
+		PasskeySessionSaver: passkeySessionSaver,
+	}
+}
+
+func (h beginPasskeySignInRequestHandler) Handle(
+	ctx context.Context, _ BeginPasskeySignInRequest,
+) (BeginPasskeySignInResponse, error) {
+	options, data, err := h.RelyingParty.BeginDiscoverableLogin()
+	if err != nil {
+		return BeginPasskeySignInResponse{}, err
+	}
+
+	sessionToken, err := savePasskeySession(ctx,
+		h.PasskeySessionSaver, h.SessionExpiresIn,
+		domainpasskey.PurposeSignIn, "", data,
+	)
+	if err != nil {
+		return BeginPasskeySignInResponse{}, err
+	}
+	return BeginPasskeySignInResponse{SessionToken: sessionToken, Options: options}, nil
+}
+
+// FinishPasskeySignInRequest finishes signing in with a passkey with the response of the authenticator. The passkey
+// verifies the user on its own, so no second factor is asked for.
+type FinishPasskeySignInRequest struct {
+	SessionToken string
+	Credential   []byte
+}
+
+type FinishPasskeySignInResponse struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+type FinishPasskeySignInRequestHandler interface {
+	Handle(ctx context.Context, request FinishPasskeySignInRequest) (FinishPasskeySignInResponse, error)
+}
+
+type finishPasskeySignInRequestHandler struct {
+	AccessTokenCreator   AccessTokenCreator
+	AccessTokenExpiresIn time.Duration
+
+	RefreshTokenCreator   RefreshTokenCreator
+	RefreshTokenExpiresIn time.Duration
+
+	RelyingParty PasskeyRelyingParty
+
+	RefreshTokenSaver RefreshTokenSaver
+
+	UserFinder             UserFinder
+	PasskeyFinder          PasskeyFinder
+	PasskeysFinder         PasskeysFinder
+	PasskeyUsageUpdater    PasskeyUsageUpdater
+	PasskeySessionConsumer PasskeySessionConsumer
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrFinishPasskeySignInInvalidSession    = errFinishPasskeySignInInvalidSession()
+	ErrFinishPasskeySignInInvalidCredential = errFinishPasskeySignInInvalidCredential()
+)
+
+func errFinishPasskeySignInInvalidSession() error    { return errors.New("invalid or expired session") }
+func errFinishPasskeySignInInvalidCredential() error { return errors.New("invalid credential") }
+
+func NewFinishPasskeySignInRequestHandler(
+	accessTokenCreator AccessTokenCreator, accessTokenExpiresIn time.Duration,
+	refreshTokenCreator RefreshTokenCreator, refreshTokenExpiresIn time.Duration,
+	relyingParty PasskeyRelyingParty,
+	refreshTokenSaver RefreshTokenSaver,
+	userFinder UserFinder, passkeyFinder PasskeyFinder, passkeysFinder PasskeysFinder,
+	passkeyUsageUpdater PasskeyUsageUpdater, passkeySessionConsumer PasskeySessionConsumer,
+	auditLogger AuditLogger,
+) FinishPasskeySignInRequestHandler {
+	return &finishPasskeySignInRequestHandler{
+		AccessTokenCreator:   accessTokenCreator,
+		AccessTokenExpiresIn: accessTokenExpiresIn,
+
+		RefreshTokenCreator:   refreshTokenCreator,
+		RefreshTokenExpiresIn: refreshTokenExpiresIn,
+
+		RelyingParty: relyingParty,
+
+		RefreshTokenSaver: refreshTokenSaver,
+
+		UserFinder:             userFinder,
+		PasskeyFinder:          passkeyFinder,
+		PasskeysFinder:         passkeysFinder,
+		PasskeyUsageUpdater:    passkeyUsageUpdater,
+		PasskeySessionConsumer: passkeySessionConsumer,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h finishPasskeySignInRequestHandler) Handle(
+	ctx context.Context, request FinishPasskeySignInRequest,
+) (_ FinishPasskeySignInResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypePasskeySignIn}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	session, err := consumePasskeySession(ctx,
+		h.PasskeySessionConsumer,
+		request.SessionToken, domainpasskey.PurposeSignIn, "",
+	)
+	if errors.Is(err, errPasskeySessionInvalid) {
+		return FinishPasskeySignInResponse{}, errors.Join(err, ErrFinishPasskeySignInInvalidSession)
+	} else if err != nil {
+		return FinishPasskeySignInResponse{}, err
+	}
+
+	// The user is only known from the passkey the authenticator chose, which is verified to be theirs below.
+	credentialID, err := h.RelyingParty.CredentialID(request.Credential)
+	if err != nil {
+		return FinishPasskeySignInResponse{}, errors.Join(err, ErrFinishPasskeySignInInvalidCredential)
+	}
+
+	credential, err := h.PasskeyFinder.FindPasskey(ctx, credentialID)
+	if errors.Is(err, domainpasskey.ErrCredentialNotFound) {
+		err = fmt.Errorf("failed to find passkey: %w", err)
+		return FinishPasskeySignInResponse{}, errors.Join(err, ErrFinishPasskeySignInInvalidCredential)
+	} else if err != nil {
+		return FinishPasskeySignInResponse{}, fmt.Errorf("failed to find passkey: %w", err)
+	}
+	event.UserID = credential.UserID
+
+	user, err := h.UserFinder.FindUserByUserID(ctx, credential.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return FinishPasskeySignInResponse{}, errors.Join(err, ErrFinishPasskeySignInInvalidCredential)
+	} else if err != nil {
+		return FinishPasskeySignInResponse{}, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	err = finishPasskeyLogin(ctx,
+		h.RelyingParty,
+		h.PasskeysFinder, h.PasskeyUsageUpdater,
+		user, session.Data, request.Credential,
+	)
+	if errors.Is(err, errPasskeyInvalid) {
+		return FinishPasskeySignInResponse{}, errors.Join(err, ErrFinishPasskeySignInInvalidCredential)
+	} else if err != nil {
+		return FinishPasskeySignInResponse{}, err
+	} else if user.Disabled {
+		return FinishPasskeySignInResponse{}, ErrFinishPasskeySignInInvalidCredential
+	}
+
+	event.SessionID = uuid.New().String()
+	accessToken, refreshToken, err := newTokenPair(ctx,
+		h.AccessTokenCreator, h.AccessTokenExpiresIn,
+		h.RefreshTokenCreator, h.RefreshTokenExpiresIn,
+		h.RefreshTokenSaver,
+		user, event.SessionID,
+	)
+	if err != nil {
+		return FinishPasskeySignInResponse{}, err
+	}
+	return FinishPasskeySignInResponse{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// BeginPasskeyMFARequest starts verifying the second factor with a passkey instead of a TOTP code, with the challenge
+// token returned by SignInRequestHandler.
+type BeginPasskeyMFARequest struct {
+	MFAToken string
+}
+
+// BeginPasskeyMFAResponse holds the options to pass to navigator.credentials.get and the session token to finish the
+// sign in with.
+type BeginPasskeyMFAResponse struct {
+	SessionToken string
+	Options      []byte
+}
+
+type BeginPasskeyMFARequestHandler interface {
+	Handle(ctx context.Context, request BeginPasskeyMFARequest) (BeginPasskeyMFAResponse, error)
+}
+
+type beginPasskeyMFARequestHandler struct {
+	MFATokenParser MFATokenParser
+
+	SessionExpiresIn time.Duration
+	RelyingParty     PasskeyRelyingParty
+
+	UserFinder          UserFinder
+	PasskeysFinder      PasskeysFinder
+	PasskeySessionSaver PasskeySessionSaver
+}
+
+var (
+	ErrBeginPasskeyMFAInvalidOrExpiredToken = errBeginPasskeyMFAInvalidOrExpiredToken()
+	ErrBeginPasskeyMFANoPasskeys            = errBeginPasskeyMFANoPasskeys()
+)
+
+func errBeginPasskeyMFAInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errBeginPasskeyMFANoPasskeys() error            { return errors.New("no passkeys registered") }
+
+func NewBeginPasskeyMFARequestHandler(
+	mfaTokenParser MFATokenParser,
+	sessionExpiresIn time.Duration, relyingParty PasskeyRelyingParty,
+	userFinder UserFinder, passkeysFinder PasskeysFinder, passkeySessionSaver PasskeySessionSaver,
+) BeginPasskeyMFARequestHandler {
+	return &beginPasskeyMFARequestHandler{
+		MFATokenParser: mfaTokenParser,
+
+		SessionExpiresIn: sessionExpiresIn,
+		RelyingParty:     relyingParty,
+
+		UserFinder:          userFinder,
+		PasskeysFinder:      passkeysFinder,
+		PasskeySessionSaver: passkeySessionSaver,
+	}
+}
+
+func (h beginPasskeyMFARequestHandler) Handle(
+	ctx context.Context, request BeginPasskeyMFARequest,
+) (BeginPasskeyMFAResponse, error) {
+	claims, err := h.MFATokenParser.Parse(request.MFAToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse mfa token: %w", err)
+		return BeginPasskeyMFAResponse{}, errors.Join(err, ErrBeginPasskeyMFAInvalidOrExpiredToken)
+	}
+
+	user, err := h.UserFinder.FindUserByUserID(ctx, claims.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return BeginPasskeyMFAResponse{}, errors.Join(err, ErrBeginPasskeyMFAInvalidOrExpiredToken)
+	} else if err != nil {
+		return BeginPasskeyMFAResponse{}, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	credentials, err := h.PasskeysFinder.FindPasskeys(ctx, user.ID)
+	if err != nil {
+		return BeginPasskeyMFAResponse{}, fmt.Errorf("failed to find passkeys: %w", err)
+	} else if len(credentials) == 0 {
+		return BeginPasskeyMFAResponse{}, ErrBeginPasskeyMFANoPasskeys
+	}
+
+	options, data, err := h.RelyingParty.BeginLogin(user, credentials)
+	if err != nil {
+		return BeginPasskeyMFAResponse{}, err
+	}
+
+	sessionToken, err := savePasskeySession(ctx,
+		h.PasskeySessionSaver, h.SessionExpiresIn,
+		domainpasskey.PurposeMFA, user.ID, data,
+	)
+	if err != nil {
+		return BeginPasskeyMFAResponse{}, err
+	}
+	return BeginPasskeyMFAResponse{SessionToken: sessionToken, Options: options}, nil
+}
+
+// FinishPasskeyMFARequest exchanges the challenge token returned by SignInRequestHandler for a token pair with the
+// response of the authenticator.
+type FinishPasskeyMFARequest struct {
+	MFAToken     string
+	SessionToken string
+	Credential   []byte
+}
+
+type FinishPasskeyMFAResponse struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+type FinishPasskeyMFARequestHandler interface {
+	Handle(ctx context.Context, request FinishPasskeyMFARequest) (FinishPasskeyMFAResponse, error)
+}
+
+type finishPasskeyMFARequestHandler struct {
+	AccessTokenCreator   AccessTokenCreator
+	AccessTokenExpiresIn time.Duration
+
+	RefreshTokenCreator   RefreshTokenCreator
+	RefreshTokenExpiresIn time.Duration
+
+	MFATokenParser MFATokenParser
+
+	RelyingParty PasskeyRelyingParty
+
+	RefreshTokenSaver RefreshTokenSaver
+
+	UserFinder             UserFinder
+	PasskeysFinder         PasskeysFinder
+	PasskeyUsageUpdater    PasskeyUsageUpdater
+	PasskeySessionConsumer PasskeySessionConsumer
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrFinishPasskeyMFAInvalidOrExpiredToken = errFinishPasskeyMFAInvalidOrExpiredToken()
+	ErrFinishPasskeyMFAInvalidSession        = errFinishPasskeyMFAInvalidSession()
+	ErrFinishPasskeyMFAInvalidCredential     = errFinishPasskeyMFAInvalidCredential()
+)
+
+func errFinishPasskeyMFAInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errFinishPasskeyMFAInvalidSession() error        { return errors.New("invalid or expired session") }
+func errFinishPasskeyMFAInvalidCredential() error     { return errors.New("invalid credential") }
+
+func NewFinishPasskeyMFARequestHandler(
+	accessTokenCreator AccessTokenCreator, accessTokenExpiresIn time.Duration,
+	refreshTokenCreator RefreshTokenCreator, refreshTokenExpiresIn time.Duration,
+	mfaTokenParser MFATokenParser,
+	relyingParty PasskeyRelyingParty,
+	refreshTokenSaver RefreshTokenSaver,
+	userFinder UserFinder, passkeysFinder PasskeysFinder,
+	passkeyUsageUpdater PasskeyUsageUpdater, passkeySessionConsumer PasskeySessionConsumer,
+	auditLogger AuditLogger,
+) FinishPasskeyMFARequestHandler {
+	return &finishPasskeyMFARequestHandler{
+		AccessTokenCreator:   accessTokenCreator,
+		AccessTokenExpiresIn: accessTokenExpiresIn,
+
+		RefreshTokenCreator:   refreshTokenCreator,
+		RefreshTokenExpiresIn: refreshTokenExpiresIn,
+
+		MFATokenParser: mfaTokenParser,
+
+		RelyingParty: relyingParty,
+
+		RefreshTokenSaver: refreshTokenSaver,
+
+		UserFinder:             userFinder,
+		PasskeysFinder:         passkeysFinder,
+		PasskeyUsageUpdater:    passkeyUsageUpdater,
+		PasskeySessionConsumer: passkeySessionConsumer,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h finishPasskeyMFARequestHandler) Handle(
+	ctx context.Context, request FinishPasskeyMFARequest,
+) (_ FinishPasskeyMFAResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypePasskeySignInMFA}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.MFATokenParser.Parse(request.MFAToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse mfa token: %w", err)
+		return FinishPasskeyMFAResponse{}, errors.Join(err, ErrFinishPasskeyMFAInvalidOrExpiredToken)
+	}
+	event.UserID = claims.UserID
+
+	session, err := consumePasskeySession(ctx,
+		h.PasskeySessionConsumer,
+		request.SessionToken, domainpasskey.PurposeMFA, claims.UserID,
+	)
+	if errors.Is(err, errPasskeySessionInvalid) {
+		return FinishPasskeyMFAResponse{}, errors.Join(err, ErrFinishPasskeyMFAInvalidSession)
+	} else if err != nil {
+		return FinishPasskeyMFAResponse{}, err
+	}
+
+	user, err := h.UserFinder.FindUserByUserID(ctx, claims.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return FinishPasskeyMFAResponse{}, errors.Join(err, ErrFinishPasskeyMFAInvalidOrExpiredToken)
+	} else if err != nil {
+		return FinishPasskeyMFAResponse{}, fmt.Errorf("failed to find user: %w", err)
+	} else if user.Disabled {
+		return FinishPasskeyMFAResponse{}, ErrFinishPasskeyMFAInvalidOrExpiredToken
+	}
+
+	err = finishPasskeyLogin(ctx,
+		h.RelyingParty,
+		h.PasskeysFinder, h.PasskeyUsageUpdater,
+		user, session.Data, request.Credential,
+	)
+	if errors.Is(err, errPasskeyInvalid) {
+		return FinishPasskeyMFAResponse{}, errors.Join(err, ErrFinishPasskeyMFAInvalidCredential)
+	} else if err != nil {
+		return FinishPasskeyMFAResponse{}, err
+	}
+
+	event.SessionID = uuid.New().String()
+	accessToken, refreshToken, err := newTokenPair(ctx,
+		h.AccessTokenCreator, h.AccessTokenExpiresIn,
+		h.RefreshTokenCreator, h.RefreshTokenExpiresIn,
+		h.RefreshTokenSaver,
+		user, event.SessionID,
+	)
+	if err != nil {
+		return FinishPasskeyMFAResponse{}, err
+	}
+	return FinishPasskeyMFAResponse{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// errPasskeyInvalid is returned by finishPasskeyLogin when the assertion of the authenticator does not verify.
+var errPasskeyInvalid = errors.New("invalid passkey assertion")
+
+// finishPasskeyLogin verifies the response of the authenticator to a login of the user against their passkeys and
+// records the use of the passkey.
+//
+// Returns errPasskeyInvalid if the response does not verify.
+func finishPasskeyLogin(
+	ctx context.Context,
+	relyingParty PasskeyRelyingParty,
+	passkeysFinder PasskeysFinder, passkeyUsageUpdater PasskeyUsageUpdater,
+	user domainuser.User, session []byte, response []byte,
+) error {
+	credentials, err := passkeysFinder.FindPasskeys(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find passkeys: %w", err)
+	}
+
+	credential, err := relyingParty.FinishLogin(user, credentials, session, response)
+	if err != nil {
+		return errors.Join(err, errPasskeyInvalid)
+	}
+
+	err = passkeyUsageUpdater.UpdatePasskeyUsage(ctx, credential.ID, credential.SignCount, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to update passkey usage: %w", err)
+	}
+	return nil
+}
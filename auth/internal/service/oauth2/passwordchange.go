@@ -0,0 +1,124 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+)
+
+// ChangePasswordRequest holds the current and the new password of the signed-in user. RefreshToken is the refresh
+// token of the current session, which is kept, while all other sessions are revoked; if empty, all sessions are.
+type ChangePasswordRequest struct {
+	AccessToken     string
+	RefreshToken    string
+	CurrentPassword string
+	NewPassword     string
+}
+
+type ChangePasswordResponse struct{}
+
+type ChangePasswordRequestHandler interface {
+	Handle(ctx context.Context, request ChangePasswordRequest) (ChangePasswordResponse, error)
+}
+
+type changePasswordRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	PasswordHasher PasswordHasher
+	PasswordPolicy PasswordPolicy
+
+	RefreshTokensDeleter RefreshTokensDeleter
+	RefreshTokenGetter   RefreshTokenGetter
+
+	UserFinder  UserFinder
+	UserUpdater UserUpdater
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrChangePasswordInvalidOrExpiredToken = errChangePasswordInvalidOrExpiredToken()
+	ErrChangePasswordInvalidPassword       = errChangePasswordInvalidPassword()
+	ErrChangePasswordInvalidNewPassword    = errChangePasswordInvalidNewPassword()
+	ErrChangePasswordUserNotFound          = errChangePasswordUserNotFound()
+)
+
+func errChangePasswordInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errChangePasswordInvalidPassword() error       { return errors.New("invalid password") }
+func errChangePasswordInvalidNewPassword() error    { return errors.New("invalid new password") }
+func errChangePasswordUserNotFound() error          { return domainuser.ErrUserNotFound }
+
+func NewChangePasswordRequestHandler(
+	accessTokenParser AccessTokenParser,
+	passwordHasher PasswordHasher, passwordPolicy PasswordPolicy,
+	refreshTokensDeleter RefreshTokensDeleter, refreshTokenGetter RefreshTokenGetter,
+	userFinder UserFinder, userUpdater UserUpdater,
+	auditLogger AuditLogger,
+) ChangePasswordRequestHandler {
+	return &changePasswordRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		PasswordHasher: passwordHasher,
+		PasswordPolicy: passwordPolicy,
+
+		RefreshTokensDeleter: refreshTokensDeleter,
+		RefreshTokenGetter:   refreshTokenGetter,
+
+		UserFinder:  userFinder,
+		UserUpdater: userUpdater,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h changePasswordRequestHandler) Handle(ctx context.Context, request ChangePasswordRequest) (_ ChangePasswordResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypePasswordChange}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return ChangePasswordResponse{}, errors.Join(err, ErrChangePasswordInvalidOrExpiredToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+
+	if len(request.NewPassword) == 0 {
+		return ChangePasswordResponse{}, ErrChangePasswordInvalidNewPassword
+	}
+
+	user, err := h.UserFinder.FindUserByUserID(ctx, claims.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return ChangePasswordResponse{}, errors.Join(err, ErrChangePasswordUserNotFound)
+	} else if err != nil {
+		return ChangePasswordResponse{}, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if err := h.PasswordHasher.Verify(request.CurrentPassword, user.PasswordHash); err != nil {
+		err = fmt.Errorf("failed to compare password hash and password: %w", err)
+		return ChangePasswordResponse{}, errors.Join(err, ErrChangePasswordInvalidPassword)
+	}
+
+	if err := h.PasswordPolicy.check("new_password", user.Username, request.NewPassword); err != nil {
+		return ChangePasswordResponse{}, errors.Join(err, ErrChangePasswordInvalidNewPassword)
+	}
+
+	passwordHash, err := h.PasswordHasher.Hash(request.NewPassword)
+	if err != nil {
+		return ChangePasswordResponse{}, fmt.Errorf("failed to generate password hash: %w", err)
+	}
+
+	user.PasswordHash = passwordHash
+	if err := h.UserUpdater.UpdateUser(ctx, user); err != nil {
+		return ChangePasswordResponse{}, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	err = revokeRefreshTokens(ctx, h.RefreshTokenGetter, h.RefreshTokensDeleter, user.ID, request.RefreshToken)
+	if err != nil {
+		return ChangePasswordResponse{}, err
+	}
+	return ChangePasswordResponse{}, nil
+}
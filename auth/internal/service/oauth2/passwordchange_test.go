@@ -0,0 +1,157 @@
+package oauth2
+
+import (
+	"context"
+	"testing"
+
+	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	storagememory "github.com/nazarslota/unotes/auth/internal/storage/memory"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/nazarslota/unotes/auth/pkg/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordStore holds the in-memory repositories used to change and reset passwords, all on one database.
+type passwordStore struct {
+	*storagememory.UserRepository
+	*storagememory.RefreshTokenRepository
+	*storagememory.PasswordResetTokenRepository
+}
+
+// newPasswordStore creates a password store with a user signed in on two sessions.
+func newPasswordStore(t *testing.T, passwordHasher PasswordHasher) passwordStore {
+	db := storagememory.NewDB()
+	users, err := storagememory.NewUserRepository(db)
+	require.NoError(t, err)
+	refreshTokens, err := storagememory.NewRefreshTokenRepository(db)
+	require.NoError(t, err)
+	passwordResetTokens, err := storagememory.NewPasswordResetTokenRepository(db)
+	require.NoError(t, err)
+	store := passwordStore{users, refreshTokens, passwordResetTokens}
+
+	passwordHash, err := passwordHasher.Hash("current-password")
+	require.NoError(t, err)
+	require.NoError(t, store.SaveUser(context.Background(), domainuser.User{
+		ID: "user-id", Username: "username", PasswordHash: passwordHash,
+	}))
+	require.NoError(t, store.SaveRefreshTokens(context.Background(), "user-id", []domainrefresh.Token{
+		"current-session", "other-session",
+	}))
+	return store
+}
+
+// assertPassword asserts that the user signs in with the password.
+func assertPassword(t *testing.T, store passwordStore, passwordHasher PasswordHasher, password string) {
+	user, err := store.FindUserByUserID(context.Background(), "user-id")
+	require.NoError(t, err)
+	assert.NoError(t, passwordHasher.Verify(password, user.PasswordHash))
+}
+
+func TestChangePasswordRequestHandler(t *testing.T) {
+	ctx := context.Background()
+	policy := PasswordPolicy{MinEntropy: 40}
+
+	passwordHasher, err := password.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	accessToken, err := accessTokenManager.New(jwt.AccessTokenClaims{UserID: "user-id"})
+	require.NoError(t, err)
+
+	newHandler := func(store passwordStore) ChangePasswordRequestHandler {
+		return NewChangePasswordRequestHandler(
+			accessTokenManager,
+			passwordHasher, policy,
+			store, store,
+			store, store,
+			nil,
+		)
+	}
+
+	t.Run("should change password and revoke other sessions", func(t *testing.T) {
+		store := newPasswordStore(t, passwordHasher)
+		_, err := newHandler(store).Handle(ctx, ChangePasswordRequest{
+			AccessToken:     accessToken,
+			RefreshToken:    "current-session",
+			CurrentPassword: "current-password",
+			NewPassword:     "Tr0ub4dor&3-horse-staple",
+		})
+		require.NoError(t, err)
+		assertPassword(t, store, passwordHasher, "Tr0ub4dor&3-horse-staple")
+
+		tokens, err := store.GetRefreshTokens(ctx, "user-id")
+		require.NoError(t, err)
+		assert.Equal(t, []domainrefresh.Token{"current-session"}, tokens)
+	})
+
+	t.Run("should revoke all sessions without refresh token", func(t *testing.T) {
+		store := newPasswordStore(t, passwordHasher)
+		_, err := newHandler(store).Handle(ctx, ChangePasswordRequest{
+			AccessToken:     accessToken,
+			CurrentPassword: "current-password",
+			NewPassword:     "Tr0ub4dor&3-horse-staple",
+		})
+		require.NoError(t, err)
+
+		_, err = store.GetRefreshTokens(ctx, "user-id")
+		assert.ErrorIs(t, err, domainrefresh.ErrTokenNotFound)
+	})
+
+	t.Run("should reject wrong current password", func(t *testing.T) {
+		store := newPasswordStore(t, passwordHasher)
+		_, err := newHandler(store).Handle(ctx, ChangePasswordRequest{
+			AccessToken:     accessToken,
+			RefreshToken:    "current-session",
+			CurrentPassword: "wrong-password",
+			NewPassword:     "Tr0ub4dor&3-horse-staple",
+		})
+		assert.ErrorIs(t, err, ErrChangePasswordInvalidPassword)
+		assertPassword(t, store, passwordHasher, "current-password")
+
+		tokens, err := store.GetRefreshTokens(ctx, "user-id")
+		require.NoError(t, err)
+		assert.Len(t, tokens, 2)
+	})
+
+	t.Run("should reject new password violating policy", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			newPassword string
+			reason      string
+		}{
+			{name: "too weak", newPassword: "abc", reason: PasswordViolationTooWeak},
+			{name: "contains username", newPassword: "Tr0ub4dor&3-USERNAME", reason: PasswordViolationContainsUsername},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				store := newPasswordStore(t, passwordHasher)
+				_, err := newHandler(store).Handle(ctx, ChangePasswordRequest{
+					AccessToken:     accessToken,
+					CurrentPassword: "current-password",
+					NewPassword:     tt.newPassword,
+				})
+				assert.ErrorIs(t, err, ErrChangePasswordInvalidNewPassword)
+
+				var policyError PasswordPolicyError
+				require.ErrorAs(t, err, &policyError)
+				require.Len(t, policyError.Violations, 1)
+				assert.Equal(t, "new_password", policyError.Violations[0].Field)
+				assert.Equal(t, tt.reason, policyError.Violations[0].Reason)
+				assertPassword(t, store, passwordHasher, "current-password")
+			})
+		}
+	})
+
+	t.Run("should reject invalid access token", func(t *testing.T) {
+		store := newPasswordStore(t, passwordHasher)
+		_, err := newHandler(store).Handle(ctx, ChangePasswordRequest{
+			AccessToken:     "invalid",
+			CurrentPassword: "current-password",
+			NewPassword:     "Tr0ub4dor&3-horse-staple",
+		})
+		assert.ErrorIs(t, err, ErrChangePasswordInvalidOrExpiredToken)
+	})
+}
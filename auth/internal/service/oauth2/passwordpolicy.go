@@ -0,0 +1,74 @@
+package oauth2
+
+import (
+	"strings"
+
+	"github.com/nazarslota/unotes/auth/pkg/password"
+)
+
+// PasswordPolicy configures which new passwords are accepted. A password must have an estimated entropy of at least
+// MinEntropy bits, must not contain the username and, if BreachedPasswords is set, must not be in it.
+type PasswordPolicy struct {
+	MinEntropy        float64
+	BreachedPasswords BreachedPasswordChecker
+}
+
+// Reasons a password violates the password policy.
+const (
+	PasswordViolationTooWeak          = "too_weak"
+	PasswordViolationContainsUsername = "contains_username"
+	PasswordViolationBreached         = "breached"
+)
+
+// PasswordViolation describes why the password in Field of a request violates the password policy.
+type PasswordViolation struct {
+	Field       string
+	Reason      string
+	Description string
+}
+
+// PasswordPolicyError is returned, joined with the invalid password error of the request handler, when a new
+// password violates the password policy.
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e PasswordPolicyError) Error() string {
+	descriptions := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		descriptions = append(descriptions, violation.Description)
+	}
+	return "password violates the policy: " + strings.Join(descriptions, ", ")
+}
+
+// check returns a PasswordPolicyError if the new password of the user, given in field of the request, violates the
+// policy.
+func (p PasswordPolicy) check(field, username, newPassword string) error {
+	var violations []PasswordViolation
+	if entropy := password.Entropy(newPassword); entropy < p.MinEntropy {
+		violations = append(violations, PasswordViolation{
+			Field:       field,
+			Reason:      PasswordViolationTooWeak,
+			Description: "password is too weak, add more or different characters",
+		})
+	}
+	if len(username) != 0 && strings.Contains(strings.ToLower(newPassword), strings.ToLower(username)) {
+		violations = append(violations, PasswordViolation{
+			Field:       field,
+			Reason:      PasswordViolationContainsUsername,
+			Description: "password must not contain the username",
+		})
+	}
+	if p.BreachedPasswords != nil && p.BreachedPasswords.Contains(newPassword) {
+		violations = append(violations, PasswordViolation{
+			Field:       field,
+			Reason:      PasswordViolationBreached,
+			Description: "password is known from a data breach",
+		})
+	}
+
+	if len(violations) != 0 {
+		return PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainpasswordreset "github.com/nazarslota/unotes/auth/internal/domain/passwordreset"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+)
+
+// RequestPasswordResetRequest identifies the user either by username or by email.
+type RequestPasswordResetRequest struct {
+	Username string
+	Email    string
+}
+
+type RequestPasswordResetResponse struct{}
+
+// RequestPasswordResetRequestHandler mails a single-use password reset link to the verified email of the user. It
+// succeeds for unknown users and users without a verified email as well, so it can not be used to find out which
+// accounts exist.
+type RequestPasswordResetRequestHandler interface {
+	Handle(ctx context.Context, request RequestPasswordResetRequest) (RequestPasswordResetResponse, error)
+}
+
+type requestPasswordResetRequestHandler struct {
+	PasswordResetURL            string
+	PasswordResetTokenExpiresIn time.Duration
+
+	UserFinder              UserFinder
+	PasswordResetTokenSaver PasswordResetTokenSaver
+
+	Mailer Mailer
+
+	AuditLogger AuditLogger
+}
+
+var ErrRequestPasswordResetInvalidUser = errRequestPasswordResetInvalidUser()
+
+func errRequestPasswordResetInvalidUser() error { return errors.New("invalid username or email") }
+
+func NewRequestPasswordResetRequestHandler(
+	passwordResetURL string, passwordResetTokenExpiresIn time.Duration,
+	userFinder UserFinder, passwordResetTokenSaver PasswordResetTokenSaver,
+	mailer Mailer,
+	auditLogger AuditLogger,
+) RequestPasswordResetRequestHandler {
+	return &requestPasswordResetRequestHandler{
+		PasswordResetURL:            passwordResetURL,
+		PasswordResetTokenExpiresIn: passwordResetTokenExpiresIn,
+
+		UserFinder:              userFinder,
+		PasswordResetTokenSaver: passwordResetTokenSaver,
+
+		Mailer: mailer,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h requestPasswordResetRequestHandler) Handle(ctx context.Context, request RequestPasswordResetRequest) (_ RequestPasswordResetResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypePasswordResetRequest}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	var user domainuser.User
+	if len(request.Username) != 0 {
+		user, err = h.UserFinder.FindUserByUsername(ctx, request.Username)
+	} else if len(request.Email) != 0 {
+		user, err = h.UserFinder.FindUserByEmail(ctx, request.Email)
+	} else {
+		return RequestPasswordResetResponse{}, ErrRequestPasswordResetInvalidUser
+	}
+
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		return RequestPasswordResetResponse{}, nil
+	} else if err != nil {
+		return RequestPasswordResetResponse{}, fmt.Errorf("failed to find user: %w", err)
+	}
+	event.UserID = user.ID
+	if len(user.Email) == 0 || !user.EmailVerified {
+		return RequestPasswordResetResponse{}, nil
+	}
+
+	token, err := newOpaqueToken()
+	if err != nil {
+		return RequestPasswordResetResponse{}, fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	if err := h.PasswordResetTokenSaver.SavePasswordResetToken(ctx, domainpasswordreset.Token{
+		TokenHash: hashOpaqueToken(token),
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(h.PasswordResetTokenExpiresIn),
+	}); err != nil {
+		return RequestPasswordResetResponse{}, fmt.Errorf("failed to save password reset token: %w", err)
+	}
+
+	link, err := tokenLink(h.PasswordResetURL, token)
+	if err != nil {
+		return RequestPasswordResetResponse{}, err
+	}
+
+	body := fmt.Sprintf("To reset your password, follow the link below within %s:\n\n%s\n\n"+
+		"If you did not request a password reset, you can ignore this email.\n", h.PasswordResetTokenExpiresIn, link)
+	if err := h.Mailer.SendMail(ctx, user.Email, "Reset your password", body); err != nil {
+		return RequestPasswordResetResponse{}, fmt.Errorf("failed to send mail: %w", err)
+	}
+	return RequestPasswordResetResponse{}, nil
+}
+
+type ConfirmPasswordResetRequest struct {
+	Token       string
+	NewPassword string
+}
+
+type ConfirmPasswordResetResponse struct{}
+
+type ConfirmPasswordResetRequestHandler interface {
+	Handle(ctx context.Context, request ConfirmPasswordResetRequest) (ConfirmPasswordResetResponse, error)
+}
+
+type confirmPasswordResetRequestHandler struct {
+	PasswordHasher PasswordHasher
+	PasswordPolicy PasswordPolicy
+
+	RefreshTokensDeleter RefreshTokensDeleter
+	RefreshTokenGetter   RefreshTokenGetter
+
+	UserFinder                 UserFinder
+	UserUpdater                UserUpdater
+	PasswordResetTokenFinder   PasswordResetTokenFinder
+	PasswordResetTokenConsumer PasswordResetTokenConsumer
+	PasswordResetTokensDeleter PasswordResetTokensDeleter
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrConfirmPasswordResetInvalidToken       = errConfirmPasswordResetInvalidToken()
+	ErrConfirmPasswordResetInvalidNewPassword = errConfirmPasswordResetInvalidNewPassword()
+)
+
+func errConfirmPasswordResetInvalidToken() error       { return errors.New("invalid or expired token") }
+func errConfirmPasswordResetInvalidNewPassword() error { return errors.New("invalid new password") }
+
+func NewConfirmPasswordResetRequestHandler(
+	passwordHasher PasswordHasher, passwordPolicy PasswordPolicy,
+	refreshTokensDeleter RefreshTokensDeleter, refreshTokenGetter RefreshTokenGetter,
+	userFinder UserFinder, userUpdater UserUpdater, passwordResetTokenFinder PasswordResetTokenFinder,
+	passwordResetTokenConsumer PasswordResetTokenConsumer, passwordResetTokensDeleter PasswordResetTokensDeleter,
+	auditLogger AuditLogger,
+) ConfirmPasswordResetRequestHandler {
+	return &confirmPasswordResetRequestHandler{
+		PasswordHasher: passwordHasher,
+		PasswordPolicy: passwordPolicy,
+
+		RefreshTokensDeleter: refreshTokensDeleter,
+		RefreshTokenGetter:   refreshTokenGetter,
+
+		UserFinder:                 userFinder,
+		UserUpdater:                userUpdater,
+		PasswordResetTokenFinder:   passwordResetTokenFinder,
+		PasswordResetTokenConsumer: passwordResetTokenConsumer,
+		PasswordResetTokensDeleter: passwordResetTokensDeleter,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h confirmPasswordResetRequestHandler) Handle(ctx context.Context, request ConfirmPasswordResetRequest) (_ ConfirmPasswordResetResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypePasswordReset}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	if len(request.NewPassword) == 0 {
+		return ConfirmPasswordResetResponse{}, ErrConfirmPasswordResetInvalidNewPassword
+	}
+
+	// The token is only looked up until the new password passes the policy, so that a rejected password doesn't use
+	// up the link.
+	tokenHash := hashOpaqueToken(request.Token)
+	token, err := h.PasswordResetTokenFinder.FindPasswordResetToken(ctx, tokenHash)
+	if errors.Is(err, domainpasswordreset.ErrTokenNotFound) {
+		err = fmt.Errorf("failed to find password reset token: %w", err)
+		return ConfirmPasswordResetResponse{}, errors.Join(err, ErrConfirmPasswordResetInvalidToken)
+	} else if err != nil {
+		return ConfirmPasswordResetResponse{}, fmt.Errorf("failed to find password reset token: %w", err)
+	}
+	event.UserID = token.UserID
+
+	user, err := h.UserFinder.FindUserByUserID(ctx, token.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return ConfirmPasswordResetResponse{}, errors.Join(err, ErrConfirmPasswordResetInvalidToken)
+	} else if err != nil {
+		return ConfirmPasswordResetResponse{}, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if err := h.PasswordPolicy.check("new_password", user.Username, request.NewPassword); err != nil {
+		return ConfirmPasswordResetResponse{}, errors.Join(err, ErrConfirmPasswordResetInvalidNewPassword)
+	}
+
+	// Consuming the token is what makes it single use, a concurrent request with the same token that got here first
+	// has consumed it already.
+	_, err = h.PasswordResetTokenConsumer.ConsumePasswordResetToken(ctx, tokenHash)
+	if errors.Is(err, domainpasswordreset.ErrTokenNotFound) {
+		err = fmt.Errorf("failed to consume password reset token: %w", err)
+		return ConfirmPasswordResetResponse{}, errors.Join(err, ErrConfirmPasswordResetInvalidToken)
+	} else if err != nil {
+		return ConfirmPasswordResetResponse{}, fmt.Errorf("failed to consume password reset token: %w", err)
+	}
+
+	passwordHash, err := h.PasswordHasher.Hash(request.NewPassword)
+	if err != nil {
+		return ConfirmPasswordResetResponse{}, fmt.Errorf("failed to generate password hash: %w", err)
+	}
+
+	user.PasswordHash = passwordHash
+	if err := h.UserUpdater.UpdateUser(ctx, user); err != nil {
+		return ConfirmPasswordResetResponse{}, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if err := h.PasswordResetTokensDeleter.DeletePasswordResetTokens(ctx, user.ID); err != nil {
+		return ConfirmPasswordResetResponse{}, fmt.Errorf("failed to delete password reset tokens: %w", err)
+	}
+
+	err = revokeRefreshTokens(ctx, h.RefreshTokenGetter, h.RefreshTokensDeleter, user.ID, "")
+	if err != nil {
+		return ConfirmPasswordResetResponse{}, err
+	}
+	return ConfirmPasswordResetResponse{}, nil
+}
@@ -0,0 +1,121 @@
+package oauth2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainpasswordreset "github.com/nazarslota/unotes/auth/internal/domain/passwordreset"
+	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	"github.com/nazarslota/unotes/auth/pkg/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestConfirmPasswordResetRequestHandler(t *testing.T) {
+	ctx := context.Background()
+	policy := PasswordPolicy{MinEntropy: 40}
+
+	passwordHasher, err := password.NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+
+	newHandler := func(store passwordStore) ConfirmPasswordResetRequestHandler {
+		return NewConfirmPasswordResetRequestHandler(
+			passwordHasher, policy,
+			store, store,
+			store, store, store,
+			store, store,
+			nil,
+		)
+	}
+
+	// saveToken saves a password reset token of the user expiring in expiresIn and returns it.
+	saveToken := func(t *testing.T, store passwordStore, expiresIn time.Duration) string {
+		token, err := newOpaqueToken()
+		require.NoError(t, err)
+		require.NoError(t, store.SavePasswordResetToken(ctx, domainpasswordreset.Token{
+			TokenHash: hashOpaqueToken(token),
+			UserID:    "user-id",
+			ExpiresAt: time.Now().Add(expiresIn),
+		}))
+		return token
+	}
+
+	t.Run("should reset password and revoke all sessions", func(t *testing.T) {
+		store := newPasswordStore(t, passwordHasher)
+		token := saveToken(t, store, time.Hour)
+		other := saveToken(t, store, time.Hour)
+
+		_, err := newHandler(store).Handle(ctx, ConfirmPasswordResetRequest{
+			Token: token, NewPassword: "Tr0ub4dor&3-horse-staple",
+		})
+		require.NoError(t, err)
+		assertPassword(t, store, passwordHasher, "Tr0ub4dor&3-horse-staple")
+
+		_, err = store.GetRefreshTokens(ctx, "user-id")
+		assert.ErrorIs(t, err, domainrefresh.ErrTokenNotFound)
+
+		_, err = store.FindPasswordResetToken(ctx, hashOpaqueToken(other))
+		assert.ErrorIs(t, err, domainpasswordreset.ErrTokenNotFound)
+	})
+
+	t.Run("should not accept token twice", func(t *testing.T) {
+		store := newPasswordStore(t, passwordHasher)
+		token := saveToken(t, store, time.Hour)
+
+		_, err := newHandler(store).Handle(ctx, ConfirmPasswordResetRequest{
+			Token: token, NewPassword: "Tr0ub4dor&3-horse-staple",
+		})
+		require.NoError(t, err)
+
+		_, err = newHandler(store).Handle(ctx, ConfirmPasswordResetRequest{
+			Token: token, NewPassword: "another-Tr0ub4dor&3-staple",
+		})
+		assert.ErrorIs(t, err, ErrConfirmPasswordResetInvalidToken)
+		assertPassword(t, store, passwordHasher, "Tr0ub4dor&3-horse-staple")
+	})
+
+	t.Run("should reject expired token", func(t *testing.T) {
+		store := newPasswordStore(t, passwordHasher)
+		token := saveToken(t, store, -time.Second)
+
+		_, err := newHandler(store).Handle(ctx, ConfirmPasswordResetRequest{
+			Token: token, NewPassword: "Tr0ub4dor&3-horse-staple",
+		})
+		assert.ErrorIs(t, err, ErrConfirmPasswordResetInvalidToken)
+		assertPassword(t, store, passwordHasher, "current-password")
+
+		tokens, err := store.GetRefreshTokens(ctx, "user-id")
+		require.NoError(t, err)
+		assert.Len(t, tokens, 2)
+	})
+
+	t.Run("should reject unknown token", func(t *testing.T) {
+		store := newPasswordStore(t, passwordHasher)
+		_, err := newHandler(store).Handle(ctx, ConfirmPasswordResetRequest{
+			Token: "unknown", NewPassword: "Tr0ub4dor&3-horse-staple",
+		})
+		assert.ErrorIs(t, err, ErrConfirmPasswordResetInvalidToken)
+	})
+
+	t.Run("should keep token when new password violates policy", func(t *testing.T) {
+		store := newPasswordStore(t, passwordHasher)
+		token := saveToken(t, store, time.Hour)
+
+		_, err := newHandler(store).Handle(ctx, ConfirmPasswordResetRequest{Token: token, NewPassword: "abc"})
+		assert.ErrorIs(t, err, ErrConfirmPasswordResetInvalidNewPassword)
+
+		var policyError PasswordPolicyError
+		require.ErrorAs(t, err, &policyError)
+		require.Len(t, policyError.Violations, 1)
+		assert.Equal(t, PasswordViolationTooWeak, policyError.Violations[0].Reason)
+		assertPassword(t, store, passwordHasher, "current-password")
+
+		_, err = newHandler(store).Handle(ctx, ConfirmPasswordResetRequest{
+			Token: token, NewPassword: "Tr0ub4dor&3-horse-staple",
+		})
+		require.NoError(t, err)
+		assertPassword(t, store, passwordHasher, "Tr0ub4dor&3-horse-staple")
+	})
+}
@@ -0,0 +1,289 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainpersonalaccesstoken "github.com/nazarslota/unotes/auth/internal/domain/personalaccesstoken"
+	"github.com/nazarslota/unotes/auth/pkg/pat"
+	"golang.org/x/exp/slices"
+)
+
+// personalAccessTokenScopes are the scopes a personal access token can be created with.
+var personalAccessTokenScopes = []string{ScopeNotesRead, ScopeNotesWrite}
+
+// PersonalAccessToken describes a personal access token of the user, without the token itself. ExpiresAt is nil for
+// tokens that never expire and LastUsedAt is nil until the token is used.
+type PersonalAccessToken struct {
+	ID         string
+	Name       string
+	Scopes     []string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+}
+
+func newPersonalAccessToken(token domainpersonalaccesstoken.Token) PersonalAccessToken {
+	return PersonalAccessToken{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scopes:     token.Scopes,
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+	}
+}
+
+// CreatePersonalAccessTokenRequest creates a personal access token for the signed-in user. Name describes what the
+// token is used for and Scopes limits what it can be used for. The token expires after ExpiresIn, or never if
+// ExpiresIn is zero.
+type CreatePersonalAccessTokenRequest struct {
+	AccessToken string
+	Name        string
+	Scopes      []string
+	ExpiresIn   time.Duration
+}
+
+// CreatePersonalAccessTokenResponse holds the token, which is only stored as a hash and can't be shown again.
+type CreatePersonalAccessTokenResponse struct {
+	PersonalAccessToken
+	Token string
+}
+
+type CreatePersonalAccessTokenRequestHandler interface {
+	Handle(ctx context.Context, request CreatePersonalAccessTokenRequest) (CreatePersonalAccessTokenResponse, error)
+}
+
+type createPersonalAccessTokenRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	PersonalAccessTokenSaver PersonalAccessTokenSaver
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrCreatePersonalAccessTokenInvalidOrExpiredToken = errCreatePersonalAccessTokenInvalidOrExpiredToken()
+	ErrCreatePersonalAccessTokenInvalidName           = errCreatePersonalAccessTokenInvalidName()
+	ErrCreatePersonalAccessTokenInvalidScope          = errCreatePersonalAccessTokenInvalidScope()
+	ErrCreatePersonalAccessTokenInvalidExpiresIn      = errCreatePersonalAccessTokenInvalidExpiresIn()
+)
+
+func errCreatePersonalAccessTokenInvalidOrExpiredToken() error {
+	return errors.New("invalid or expired token")
+}
+func errCreatePersonalAccessTokenInvalidName() error      { return errors.New("invalid name") }
+func errCreatePersonalAccessTokenInvalidScope() error     { return errors.New("invalid scope") }
+func errCreatePersonalAccessTokenInvalidExpiresIn() error { return errors.New("invalid expires in") }
+
+func NewCreatePersonalAccessTokenRequestHandler(
+	accessTokenParser AccessTokenParser,
+	personalAccessTokenSaver PersonalAccessTokenSaver,
+	auditLogger AuditLogger,
+) CreatePersonalAccessTokenRequestHandler {
+	return &createPersonalAccessTokenRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		PersonalAccessTokenSaver: personalAccessTokenSaver,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h createPersonalAccessTokenRequestHandler) Handle(
+	ctx context.Context, request CreatePersonalAccessTokenRequest,
+) (_ CreatePersonalAccessTokenResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypePersonalAccessTokenCreate}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return CreatePersonalAccessTokenResponse{}, errors.Join(err, ErrCreatePersonalAccessTokenInvalidOrExpiredToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+
+	name := strings.TrimSpace(request.Name)
+	if len(name) == 0 || utf8.RuneCountInString(name) > 64 || strings.IndexFunc(name, unicode.IsControl) != -1 {
+		return CreatePersonalAccessTokenResponse{}, ErrCreatePersonalAccessTokenInvalidName
+	}
+
+	scopes := make([]string, 0, len(request.Scopes))
+	for _, scope := range request.Scopes {
+		if !slices.Contains(personalAccessTokenScopes, scope) {
+			return CreatePersonalAccessTokenResponse{}, ErrCreatePersonalAccessTokenInvalidScope
+		} else if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return CreatePersonalAccessTokenResponse{}, ErrCreatePersonalAccessTokenInvalidScope
+	}
+
+	if request.ExpiresIn < 0 {
+		return CreatePersonalAccessTokenResponse{}, ErrCreatePersonalAccessTokenInvalidExpiresIn
+	}
+
+	secret, err := newOpaqueToken()
+	if err != nil {
+		return CreatePersonalAccessTokenResponse{}, fmt.Errorf("failed to create personal access token: %w", err)
+	}
+	token := pat.Prefix + secret
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	personalAccessToken := domainpersonalaccesstoken.Token{
+		ID:        uuid.New().String(),
+		UserID:    claims.UserID,
+		Name:      name,
+		TokenHash: hashOpaqueToken(token),
+		Scopes:    scopes,
+		CreatedAt: now,
+	}
+	if request.ExpiresIn > 0 {
+		expiresAt := now.Add(request.ExpiresIn)
+		personalAccessToken.ExpiresAt = &expiresAt
+	}
+
+	if err := h.PersonalAccessTokenSaver.SavePersonalAccessToken(ctx, personalAccessToken); err != nil {
+		return CreatePersonalAccessTokenResponse{}, fmt.Errorf("failed to save personal access token: %w", err)
+	}
+	return CreatePersonalAccessTokenResponse{
+		PersonalAccessToken: newPersonalAccessToken(personalAccessToken),
+		Token:               token,
+	}, nil
+}
+
+type ListPersonalAccessTokensRequest struct {
+	AccessToken string
+}
+
+type ListPersonalAccessTokensResponse struct {
+	Tokens []PersonalAccessToken
+}
+
+type ListPersonalAccessTokensRequestHandler interface {
+	Handle(ctx context.Context, request ListPersonalAccessTokensRequest) (ListPersonalAccessTokensResponse, error)
+}
+
+type listPersonalAccessTokensRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	PersonalAccessTokensFinder PersonalAccessTokensFinder
+}
+
+var ErrListPersonalAccessTokensInvalidOrExpiredToken = errListPersonalAccessTokensInvalidOrExpiredToken()
+
+func errListPersonalAccessTokensInvalidOrExpiredToken() error {
+	return errors.New("invalid or expired token")
+}
+
+func NewListPersonalAccessTokensRequestHandler(
+	accessTokenParser AccessTokenParser,
+	personalAccessTokensFinder PersonalAccessTokensFinder,
+) ListPersonalAccessTokensRequestHandler {
+	return &listPersonalAccessTokensRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		PersonalAccessTokensFinder: personalAccessTokensFinder,
+	}
+}
+
+func (h listPersonalAccessTokensRequestHandler) Handle(
+	ctx context.Context, request ListPersonalAccessTokensRequest,
+) (ListPersonalAccessTokensResponse, error) {
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return ListPersonalAccessTokensResponse{}, errors.Join(err, ErrListPersonalAccessTokensInvalidOrExpiredToken)
+	}
+
+	tokens, err := h.PersonalAccessTokensFinder.FindPersonalAccessTokens(ctx, claims.UserID)
+	if err != nil {
+		return ListPersonalAccessTokensResponse{}, fmt.Errorf("failed to find personal access tokens: %w", err)
+	}
+
+	response := ListPersonalAccessTokensResponse{Tokens: make([]PersonalAccessToken, 0, len(tokens))}
+	for _, token := range tokens {
+		response.Tokens = append(response.Tokens, newPersonalAccessToken(token))
+	}
+	return response, nil
+}
+
+type RevokePersonalAccessTokenRequest struct {
+	AccessToken string
+	ID          string
+}
+
+type RevokePersonalAccessTokenResponse struct {
+}
+
+type RevokePersonalAccessTokenRequestHandler interface {
+	Handle(ctx context.Context, request RevokePersonalAccessTokenRequest) (RevokePersonalAccessTokenResponse, error)
+}
+
+type revokePersonalAccessTokenRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	PersonalAccessTokenDeleter PersonalAccessTokenDeleter
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrRevokePersonalAccessTokenInvalidOrExpiredToken = errRevokePersonalAccessTokenInvalidOrExpiredToken()
+	ErrRevokePersonalAccessTokenNotFound              = errRevokePersonalAccessTokenNotFound()
+)
+
+func errRevokePersonalAccessTokenInvalidOrExpiredToken() error {
+	return errors.New("invalid or expired token")
+}
+func errRevokePersonalAccessTokenNotFound() error { return domainpersonalaccesstoken.ErrTokenNotFound }
+
+func NewRevokePersonalAccessTokenRequestHandler(
+	accessTokenParser AccessTokenParser,
+	personalAccessTokenDeleter PersonalAccessTokenDeleter,
+	auditLogger AuditLogger,
+) RevokePersonalAccessTokenRequestHandler {
+	return &revokePersonalAccessTokenRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		PersonalAccessTokenDeleter: personalAccessTokenDeleter,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h revokePersonalAccessTokenRequestHandler) Handle(
+	ctx context.Context, request RevokePersonalAccessTokenRequest,
+) (_ RevokePersonalAccessTokenResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypePersonalAccessTokenRevoke}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return RevokePersonalAccessTokenResponse{}, errors.Join(err, ErrRevokePersonalAccessTokenInvalidOrExpiredToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+
+	// Tokens are looked up by ID, which is a UUID, so anything else can't be one of them.
+	if _, err := uuid.Parse(request.ID); err != nil {
+		return RevokePersonalAccessTokenResponse{}, ErrRevokePersonalAccessTokenNotFound
+	}
+
+	err = h.PersonalAccessTokenDeleter.DeletePersonalAccessToken(ctx, claims.UserID, request.ID)
+	if errors.Is(err, domainpersonalaccesstoken.ErrTokenNotFound) {
+		err = fmt.Errorf("failed to delete personal access token: %w", err)
+		return RevokePersonalAccessTokenResponse{}, errors.Join(err, ErrRevokePersonalAccessTokenNotFound)
+	} else if err != nil {
+		return RevokePersonalAccessTokenResponse{}, fmt.Errorf("failed to delete personal access token: %w", err)
+	}
+	return RevokePersonalAccessTokenResponse{}, nil
+}
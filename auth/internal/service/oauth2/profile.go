@@ -0,0 +1,473 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainblob "github.com/nazarslota/unotes/auth/internal/domain/blob"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"golang.org/x/exp/slices"
+	"golang.org/x/text/language"
+)
+
+// UserProfile is the profile of a user as it is shown to the user, other users and services. AvatarURL is empty if the
+// user has not uploaded an avatar.
+type UserProfile struct {
+	UserID      string
+	Username    string
+	DisplayName string
+	AvatarURL   string
+	Locale      string
+	Timezone    string
+}
+
+// newUserProfile returns the profile of a user, with the avatar key resolved against the URL avatars are served at.
+func newUserProfile(profile domainuser.Profile, avatarURL string) UserProfile {
+	var url string
+	if len(profile.Avatar) != 0 {
+		url = strings.TrimSuffix(avatarURL, "/") + "/" + profile.Avatar
+	}
+
+	return UserProfile{
+		UserID:      profile.UserID,
+		Username:    profile.Username,
+		DisplayName: profile.DisplayName,
+		AvatarURL:   url,
+		Locale:      profile.Locale,
+		Timezone:    profile.Timezone,
+	}
+}
+
+type GetProfileRequest struct {
+	AccessToken string
+}
+
+type GetProfileResponse struct {
+	UserProfile
+}
+
+type GetProfileRequestHandler interface {
+	Handle(ctx context.Context, request GetProfileRequest) (GetProfileResponse, error)
+}
+
+type getProfileRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	AvatarURL string
+
+	ProfileFinder ProfileFinder
+}
+
+var (
+	ErrGetProfileInvalidOrExpiredToken = errGetProfileInvalidOrExpiredToken()
+	ErrGetProfileUserNotFound          = errGetProfileUserNotFound()
+)
+
+func errGetProfileInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errGetProfileUserNotFound() error          { return domainuser.ErrUserNotFound }
+
+func NewGetProfileRequestHandler(
+	accessTokenParser AccessTokenParser,
+	avatarURL string,
+	profileFinder ProfileFinder,
+) GetProfileRequestHandler {
+	return &getProfileRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		AvatarURL: avatarURL,
+
+		ProfileFinder: profileFinder,
+	}
+}
+
+func (h getProfileRequestHandler) Handle(ctx context.Context, request GetProfileRequest) (GetProfileResponse, error) {
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return GetProfileResponse{}, errors.Join(err, ErrGetProfileInvalidOrExpiredToken)
+	}
+
+	profile, err := h.ProfileFinder.FindProfile(ctx, claims.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find profile: %w", err)
+		return GetProfileResponse{}, errors.Join(err, ErrGetProfileUserNotFound)
+	} else if err != nil {
+		return GetProfileResponse{}, fmt.Errorf("failed to find profile: %w", err)
+	}
+	return GetProfileResponse{UserProfile: newUserProfile(profile, h.AvatarURL)}, nil
+}
+
+// UpdateProfileRequest replaces the display name, locale and timezone of the signed-in user. DisplayName may be empty,
+// Locale must be a BCP 47 language tag and Timezone a name from the IANA time zone database.
+type UpdateProfileRequest struct {
+	AccessToken string
+	DisplayName string
+	Locale      string
+	Timezone    string
+}
+
+type UpdateProfileResponse struct {
+	UserProfile
+}
+
+type UpdateProfileRequestHandler interface {
+	Handle(ctx context.Context, request UpdateProfileRequest) (UpdateProfileResponse, error)
+}
+
+type updateProfileRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	AvatarURL string
+
+	ProfileFinder  ProfileFinder
+	ProfileUpdater ProfileUpdater
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrUpdateProfileInvalidOrExpiredToken = errUpdateProfileInvalidOrExpiredToken()
+	ErrUpdateProfileInvalidDisplayName    = errUpdateProfileInvalidDisplayName()
+	ErrUpdateProfileInvalidLocale         = errUpdateProfileInvalidLocale()
+	ErrUpdateProfileInvalidTimezone       = errUpdateProfileInvalidTimezone()
+	ErrUpdateProfileUserNotFound          = errUpdateProfileUserNotFound()
+)
+
+func errUpdateProfileInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errUpdateProfileInvalidDisplayName() error    { return errors.New("invalid display name") }
+func errUpdateProfileInvalidLocale() error         { return errors.New("invalid locale") }
+func errUpdateProfileInvalidTimezone() error       { return errors.New("invalid timezone") }
+func errUpdateProfileUserNotFound() error          { return domainuser.ErrUserNotFound }
+
+// maxDisplayNameLength is the maximum length of a display name in characters, which matches the users table.
+const maxDisplayNameLength = 64
+
+func NewUpdateProfileRequestHandler(
+	accessTokenParser AccessTokenParser,
+	avatarURL string,
+	profileFinder ProfileFinder, profileUpdater ProfileUpdater,
+	auditLogger AuditLogger,
+) UpdateProfileRequestHandler {
+	return &updateProfileRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		AvatarURL: avatarURL,
+
+		ProfileFinder:  profileFinder,
+		ProfileUpdater: profileUpdater,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h updateProfileRequestHandler) Handle(ctx context.Context, request UpdateProfileRequest) (_ UpdateProfileResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeProfileUpdate}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return UpdateProfileResponse{}, errors.Join(err, ErrUpdateProfileInvalidOrExpiredToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+
+	displayName := strings.TrimSpace(request.DisplayName)
+	if utf8.RuneCountInString(displayName) > maxDisplayNameLength || strings.IndexFunc(displayName, unicode.IsControl) != -1 {
+		return UpdateProfileResponse{}, ErrUpdateProfileInvalidDisplayName
+	}
+
+	tag, err := language.Parse(request.Locale)
+	if err != nil {
+		err = fmt.Errorf("failed to parse locale: %w", err)
+		return UpdateProfileResponse{}, errors.Join(err, ErrUpdateProfileInvalidLocale)
+	}
+
+	// An empty name and "Local" load the UTC and the local location of the server, neither of which is a timezone the
+	// user lives in.
+	if len(request.Timezone) == 0 || request.Timezone == "Local" {
+		return UpdateProfileResponse{}, ErrUpdateProfileInvalidTimezone
+	} else if _, err := time.LoadLocation(request.Timezone); err != nil {
+		err = fmt.Errorf("failed to load location: %w", err)
+		return UpdateProfileResponse{}, errors.Join(err, ErrUpdateProfileInvalidTimezone)
+	}
+
+	profile, err := h.ProfileFinder.FindProfile(ctx, claims.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find profile: %w", err)
+		return UpdateProfileResponse{}, errors.Join(err, ErrUpdateProfileUserNotFound)
+	} else if err != nil {
+		return UpdateProfileResponse{}, fmt.Errorf("failed to find profile: %w", err)
+	}
+
+	profile.DisplayName = displayName
+	profile.Locale = tag.String()
+	profile.Timezone = request.Timezone
+	if err := h.ProfileUpdater.UpdateProfile(ctx, profile); err != nil {
+		return UpdateProfileResponse{}, fmt.Errorf("failed to update profile: %w", err)
+	}
+	return UpdateProfileResponse{UserProfile: newUserProfile(profile, h.AvatarURL)}, nil
+}
+
+// UploadAvatarRequest replaces the avatar of the signed-in user with the image in Data, which must be a PNG, JPEG, GIF
+// or WebP image of at most the maximum avatar size.
+type UploadAvatarRequest struct {
+	AccessToken string
+	Data        []byte
+}
+
+type UploadAvatarResponse struct {
+	UserProfile
+}
+
+type UploadAvatarRequestHandler interface {
+	Handle(ctx context.Context, request UploadAvatarRequest) (UploadAvatarResponse, error)
+}
+
+type uploadAvatarRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	AvatarURL     string
+	AvatarMaxSize int64
+
+	ProfileFinder  ProfileFinder
+	ProfileUpdater ProfileUpdater
+
+	BlobSaver   BlobSaver
+	BlobDeleter BlobDeleter
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrUploadAvatarInvalidOrExpiredToken = errUploadAvatarInvalidOrExpiredToken()
+	ErrUploadAvatarTooLarge              = errUploadAvatarTooLarge()
+	ErrUploadAvatarUnsupportedType       = errUploadAvatarUnsupportedType()
+	ErrUploadAvatarUserNotFound          = errUploadAvatarUserNotFound()
+)
+
+func errUploadAvatarInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errUploadAvatarTooLarge() error              { return errors.New("avatar is too large") }
+func errUploadAvatarUnsupportedType() error       { return errors.New("unsupported avatar type") }
+func errUploadAvatarUserNotFound() error          { return domainuser.ErrUserNotFound }
+
+// avatarContentTypes are the content types of images accepted as avatars, as detected by `http.DetectContentType`.
+var avatarContentTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp"}
+
+func NewUploadAvatarRequestHandler(
+	accessTokenParser AccessTokenParser,
+	avatarURL string, avatarMaxSize int64,
+	profileFinder ProfileFinder, profileUpdater ProfileUpdater,
+	blobSaver BlobSaver, blobDeleter BlobDeleter,
+	auditLogger AuditLogger,
+) UploadAvatarRequestHandler {
+	return &uploadAvatarRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		AvatarURL:     avatarURL,
+		AvatarMaxSize: avatarMaxSize,
+
+		ProfileFinder:  profileFinder,
+		ProfileUpdater: profileUpdater,
+
+		BlobSaver:   blobSaver,
+		BlobDeleter: blobDeleter,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h uploadAvatarRequestHandler) Handle(ctx context.Context, request UploadAvatarRequest) (_ UploadAvatarResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeAvatarUpload}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return UploadAvatarResponse{}, errors.Join(err, ErrUploadAvatarInvalidOrExpiredToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+
+	if int64(len(request.Data)) > h.AvatarMaxSize {
+		return UploadAvatarResponse{}, ErrUploadAvatarTooLarge
+	}
+
+	// The content type is detected rather than taken from the request, so that nothing but images is ever served.
+	contentType := http.DetectContentType(request.Data)
+	if !slices.Contains(avatarContentTypes, contentType) {
+		return UploadAvatarResponse{}, ErrUploadAvatarUnsupportedType
+	}
+
+	profile, err := h.ProfileFinder.FindProfile(ctx, claims.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find profile: %w", err)
+		return UploadAvatarResponse{}, errors.Join(err, ErrUploadAvatarUserNotFound)
+	} else if err != nil {
+		return UploadAvatarResponse{}, fmt.Errorf("failed to find profile: %w", err)
+	}
+
+	// Every upload gets a new key, so avatars can be cached for as long as they are served.
+	token, err := newOpaqueToken()
+	if err != nil {
+		return UploadAvatarResponse{}, fmt.Errorf("failed to generate avatar key: %w", err)
+	}
+	key := profile.UserID + "-" + token
+
+	blob := domainblob.Blob{ContentType: contentType, Data: request.Data}
+	if err := h.BlobSaver.SaveBlob(ctx, key, blob); err != nil {
+		return UploadAvatarResponse{}, fmt.Errorf("failed to save blob: %w", err)
+	}
+
+	previous := profile.Avatar
+	profile.Avatar = key
+	if err := h.ProfileUpdater.UpdateProfile(ctx, profile); err != nil {
+		return UploadAvatarResponse{}, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	// The new avatar is already in use, so failing to delete the previous one only leaves an unused blob behind.
+	if len(previous) != 0 {
+		_ = h.BlobDeleter.DeleteBlob(ctx, previous)
+	}
+	return UploadAvatarResponse{UserProfile: newUserProfile(profile, h.AvatarURL)}, nil
+}
+
+type GetAvatarRequest struct {
+	Key string
+}
+
+type GetAvatarResponse struct {
+	ContentType string
+	Data        []byte
+}
+
+type GetAvatarRequestHandler interface {
+	Handle(ctx context.Context, request GetAvatarRequest) (GetAvatarResponse, error)
+}
+
+type getAvatarRequestHandler struct {
+	BlobFinder BlobFinder
+}
+
+var ErrGetAvatarNotFound = errGetAvatarNotFound()
+
+func errGetAvatarNotFound() error { return domainblob.ErrBlobNotFound }
+
+func NewGetAvatarRequestHandler(blobFinder BlobFinder) GetAvatarRequestHandler {
+	return &getAvatarRequestHandler{BlobFinder: blobFinder}
+}
+
+func (h getAvatarRequestHandler) Handle(ctx context.Context, request GetAvatarRequest) (GetAvatarResponse, error) {
+	blob, err := h.BlobFinder.FindBlob(ctx, request.Key)
+	if errors.Is(err, domainblob.ErrBlobNotFound) {
+		err = fmt.Errorf("failed to find blob: %w", err)
+		return GetAvatarResponse{}, errors.Join(err, ErrGetAvatarNotFound)
+	} else if err != nil {
+		return GetAvatarResponse{}, fmt.Errorf("failed to find blob: %w", err)
+	}
+	return GetAvatarResponse{ContentType: blob.ContentType, Data: blob.Data}, nil
+}
+
+// GetUsersRequest looks up the profiles of several users at once. It is meant for other services, which authenticate
+// with the credentials of a confidential client.
+type GetUsersRequest struct {
+	ClientID     string
+	ClientSecret string
+	UserIDs      []string
+}
+
+// GetUsersResponse holds the profiles of the users that were found, ordered by username. Users that were not found are
+// left out, and the IDs of those among them that are known to be deleted are listed in DeletedUserIDs, so that other
+// services can tell a deleted user from one they looked up by mistake.
+type GetUsersResponse struct {
+	Users          []UserProfile
+	DeletedUserIDs []string
+}
+
+type GetUsersRequestHandler interface {
+	Handle(ctx context.Context, request GetUsersRequest) (GetUsersResponse, error)
+}
+
+type getUsersRequestHandler struct {
+	AvatarURL string
+
+	ClientFinder       ClientFinder
+	ProfilesFinder     ProfilesFinder
+	DeletedUsersFinder DeletedUsersFinder
+}
+
+var (
+	ErrGetUsersInvalidClient  = errGetUsersInvalidClient()
+	ErrGetUsersTooManyUserIDs = errGetUsersTooManyUserIDs()
+)
+
+func errGetUsersInvalidClient() error  { return errors.New("invalid client") }
+func errGetUsersTooManyUserIDs() error { return errors.New("too many user ids") }
+
+// maxGetUsersUserIDs is the maximum number of users that can be looked up at once.
+const maxGetUsersUserIDs = 100
+
+func NewGetUsersRequestHandler(
+	avatarURL string,
+	clientFinder ClientFinder, profilesFinder ProfilesFinder, deletedUsersFinder DeletedUsersFinder,
+) GetUsersRequestHandler {
+	return &getUsersRequestHandler{
+		AvatarURL: avatarURL,
+
+		ClientFinder:       clientFinder,
+		ProfilesFinder:     profilesFinder,
+		DeletedUsersFinder: deletedUsersFinder,
+	}
+}
+
+func (h getUsersRequestHandler) Handle(ctx context.Context, request GetUsersRequest) (GetUsersResponse, error) {
+	client, err := findClient(ctx, h.ClientFinder, request.ClientID, request.ClientSecret)
+	if errors.Is(err, errClientNotAuthenticated) {
+		return GetUsersResponse{}, errors.Join(err, ErrGetUsersInvalidClient)
+	} else if err != nil {
+		return GetUsersResponse{}, err
+	}
+
+	// Public clients have no secret, so anyone could look up users with their ID.
+	if client.Public {
+		return GetUsersResponse{}, ErrGetUsersInvalidClient
+	}
+
+	if len(request.UserIDs) > maxGetUsersUserIDs {
+		return GetUsersResponse{}, ErrGetUsersTooManyUserIDs
+	}
+
+	// IDs that are not UUIDs cannot belong to any user.
+	userIDs := make([]string, 0, len(request.UserIDs))
+	for _, userID := range request.UserIDs {
+		if _, err := uuid.Parse(userID); err == nil {
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	users := make([]UserProfile, 0, len(userIDs))
+	if len(userIDs) == 0 {
+		return GetUsersResponse{Users: users, DeletedUserIDs: []string{}}, nil
+	}
+
+	profiles, err := h.ProfilesFinder.FindProfiles(ctx, userIDs)
+	if err != nil {
+		return GetUsersResponse{}, fmt.Errorf("failed to find profiles: %w", err)
+	}
+
+	deletedUserIDs, err := h.DeletedUsersFinder.FindDeletedUserIDs(ctx, userIDs)
+	if err != nil {
+		return GetUsersResponse{}, fmt.Errorf("failed to find deleted users: %w", err)
+	}
+
+	for _, profile := range profiles {
+		users = append(users, newUserProfile(profile, h.AvatarURL))
+	}
+	return GetUsersResponse{Users: users, DeletedUserIDs: deletedUserIDs}, nil
+}
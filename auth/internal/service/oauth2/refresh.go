@@ -7,7 +7,10 @@ import (
 	"time"
 
 	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
 	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
 	"github.com/nazarslota/unotes/auth/pkg/jwt"
 	"golang.org/x/exp/slices"
 )
@@ -37,6 +40,10 @@ type refreshRequestHandler struct {
 	RefreshTokenSaver   RefreshTokenSaver
 	RefreshTokenDeleter RefreshTokenDeleter
 	RefreshTokenGetter  RefreshTokenGetter
+
+	UserFinder UserFinder
+
+	AuditLogger AuditLogger
 }
 
 var (
@@ -49,6 +56,8 @@ func NewRefreshRequestHandler(
 	accessTokenCreator AccessTokenCreator, accessTokenParser AccessTokenParser, accessTokenExpiresIn time.Duration,
 	refreshTokenCreator RefreshTokenCreator, refreshTokenParser RefreshTokenParser, refreshTokenExpiresIn time.Duration,
 	refreshTokenSaver RefreshTokenSaver, refreshTokenDeleter RefreshTokenDeleter, refreshTokenGetter RefreshTokenGetter,
+	userFinder UserFinder,
+	auditLogger AuditLogger,
 ) RefreshRequestHandler {
 	return &refreshRequestHandler{
 		AccessTokenCreator:   accessTokenCreator,
@@ -62,15 +71,26 @@ func NewRefreshRequestHandler(
 		RefreshTokenSaver:   refreshTokenSaver,
 		RefreshTokenDeleter: refreshTokenDeleter,
 		RefreshTokenGetter:  refreshTokenGetter,
+
+		UserFinder: userFinder,
+
+		AuditLogger: auditLogger,
 	}
 }
 
-func (h refreshRequestHandler) Handle(ctx context.Context, request RefreshRequest) (RefreshResponse, error) {
+func (h refreshRequestHandler) Handle(ctx context.Context, request RefreshRequest) (_ RefreshResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeRefresh}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
 	claims, err := h.RefreshTokenParser.Parse(request.RefreshToken)
 	if err != nil {
 		err = fmt.Errorf("failed to parse refresh accessToken: %w", err)
 		return RefreshResponse{}, errors.Join(err, ErrRefreshInvalidOrExpiredToken)
+	} else if len(claims.ClientID) != 0 {
+		// Tokens of OAuth2 clients are refreshed through the token endpoint, which keeps them limited to their scope.
+		return RefreshResponse{}, ErrRefreshInvalidOrExpiredToken
 	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
 
 	tokens, err := h.RefreshTokenGetter.GetRefreshTokens(ctx, claims.UserID)
 	if errors.Is(err, domainrefresh.ErrTokenNotFound) {
@@ -82,20 +102,45 @@ func (h refreshRequestHandler) Handle(ctx context.Context, request RefreshReques
 		return RefreshResponse{}, ErrRefreshInvalidOrExpiredToken
 	}
 
+	user, err := h.UserFinder.FindUserByUserID(ctx, claims.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return RefreshResponse{}, errors.Join(err, ErrRefreshInvalidOrExpiredToken)
+	} else if err != nil {
+		return RefreshResponse{}, fmt.Errorf("failed to find user: %w", err)
+	} else if user.Disabled {
+		return RefreshResponse{}, ErrRefreshInvalidOrExpiredToken
+	}
+
+	// Tokens issued before sessions were tracked don't have one, so they start a new session.
+	sessionID := claims.SessionID
+	if len(sessionID) == 0 {
+		sessionID = uuid.New().String()
+	}
+	event.SessionID = sessionID
+
 	accessToken, err := h.AccessTokenCreator.New(jwt.AccessTokenClaims{
 		RegisteredClaims: gojwt.RegisteredClaims{
+			ID:        uuid.New().String(),
 			ExpiresAt: gojwt.NewNumericDate(time.Now().Add(h.AccessTokenExpiresIn)),
 		},
-		UserID: claims.UserID,
+		UserID:        user.ID,
+		EmailVerified: user.EmailVerified,
+		Role:          user.Role,
+		Scope:         firstPartyScope,
+		SessionID:     sessionID,
 	})
 	if err != nil {
 		return RefreshResponse{}, fmt.Errorf("failed to create new access token: %w", err)
 	}
 	refreshToken, err := h.RefreshTokenCreator.New(jwt.RefreshTokenClaims{
 		RegisteredClaims: gojwt.RegisteredClaims{
+			ID:        uuid.New().String(),
 			ExpiresAt: gojwt.NewNumericDate(time.Now().Add(h.RefreshTokenExpiresIn)),
 		},
-		UserID: claims.UserID,
+		UserID:    claims.UserID,
+		Scope:     firstPartyScope,
+		SessionID: sessionID,
 	})
 	if err != nil {
 		return RefreshResponse{}, fmt.Errorf("failed to create new refresh token: %w", err)
@@ -0,0 +1,134 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+)
+
+// RevokeRequest is an RFC 7009 token revocation request of a client. TokenTypeHint only decides which kind of token
+// is tried first. ClientSecret is empty for public clients.
+type RevokeRequest struct {
+	ClientID     string
+	ClientSecret string
+
+	Token         string
+	TokenTypeHint string
+}
+
+type RevokeResponse struct{}
+
+// RevokeRequestHandler revokes a token issued to the client. Access tokens are added to a denylist until they expire,
+// refresh tokens are deleted. Invalid, expired and already revoked tokens are not an error.
+type RevokeRequestHandler interface {
+	Handle(ctx context.Context, request RevokeRequest) (RevokeResponse, error)
+}
+
+type revokeRequestHandler struct {
+	AccessTokenParser  AccessTokenParser
+	RefreshTokenParser RefreshTokenParser
+
+	RefreshTokenDeleter   RefreshTokenDeleter
+	RevokedTokenSaver     RevokedTokenSaver
+	RevokedTokenPublisher RevokedTokenPublisher
+
+	ClientFinder ClientFinder
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrRevokeInvalidRequest     = errRevokeInvalidRequest()
+	ErrRevokeInvalidClient      = errRevokeInvalidClient()
+	ErrRevokeUnauthorizedClient = errRevokeUnauthorizedClient()
+)
+
+func errRevokeInvalidRequest() error     { return errors.New("invalid request") }
+func errRevokeInvalidClient() error      { return errors.New("invalid client") }
+func errRevokeUnauthorizedClient() error { return errors.New("unauthorized client") }
+
+func NewRevokeRequestHandler(
+	accessTokenParser AccessTokenParser, refreshTokenParser RefreshTokenParser,
+	refreshTokenDeleter RefreshTokenDeleter,
+	revokedTokenSaver RevokedTokenSaver, revokedTokenPublisher RevokedTokenPublisher,
+	clientFinder ClientFinder,
+	auditLogger AuditLogger,
+) RevokeRequestHandler {
+	return &revokeRequestHandler{
+		AccessTokenParser:  accessTokenParser,
+		RefreshTokenParser: refreshTokenParser,
+
+		RefreshTokenDeleter:   refreshTokenDeleter,
+		RevokedTokenSaver:     revokedTokenSaver,
+		RevokedTokenPublisher: revokedTokenPublisher,
+
+		ClientFinder: clientFinder,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h revokeRequestHandler) Handle(ctx context.Context, request RevokeRequest) (_ RevokeResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeTokenRevoke}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	if len(request.Token) == 0 || len(request.ClientID) == 0 {
+		return RevokeResponse{}, ErrRevokeInvalidRequest
+	}
+
+	client, err := findClient(ctx, h.ClientFinder, request.ClientID, request.ClientSecret)
+	if errors.Is(err, errClientNotAuthenticated) {
+		return RevokeResponse{}, errors.Join(err, ErrRevokeInvalidClient)
+	} else if err != nil {
+		return RevokeResponse{}, err
+	}
+
+	revokers := []func(context.Context, string, string) (bool, error){h.accessToken, h.refreshToken}
+	if request.TokenTypeHint == TokenTypeHintRefreshToken {
+		revokers[0], revokers[1] = revokers[1], revokers[0]
+	}
+
+	for _, revoke := range revokers {
+		if found, err := revoke(ctx, client.ID, request.Token); err != nil {
+			return RevokeResponse{}, err
+		} else if found {
+			return RevokeResponse{}, nil
+		}
+	}
+	return RevokeResponse{}, nil
+}
+
+// accessToken adds the token to the denylist for the rest of its lifetime. Reports false if the token is not a valid
+// access token.
+func (h revokeRequestHandler) accessToken(ctx context.Context, clientID string, token string) (bool, error) {
+	claims, err := h.AccessTokenParser.Parse(token)
+	if err != nil {
+		return false, nil
+	} else if claims.ClientID != clientID {
+		return true, ErrRevokeUnauthorizedClient
+	}
+
+	if err := revokeAccessToken(ctx, h.RevokedTokenSaver, h.RevokedTokenPublisher, claims); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// refreshToken deletes the token. Reports false if the token is not a valid refresh token.
+func (h revokeRequestHandler) refreshToken(ctx context.Context, clientID string, token string) (bool, error) {
+	claims, err := h.RefreshTokenParser.Parse(token)
+	if err != nil {
+		return false, nil
+	} else if claims.ClientID != clientID {
+		return true, ErrRevokeUnauthorizedClient
+	}
+
+	err = h.RefreshTokenDeleter.DeleteRefreshToken(ctx, claims.UserID, domainrefresh.Token(token))
+	if err != nil && !errors.Is(err, domainrefresh.ErrTokenNotFound) {
+		return true, fmt.Errorf("failed to delete refresh token: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,229 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+)
+
+// SecurityEvent is an event of the audit log, such as a sign in or a password change, see the types in the audit
+// domain.
+type SecurityEvent struct {
+	ID        string
+	Type      string
+	UserID    string
+	IP        string
+	UserAgent string
+	SessionID string
+	Outcome   string
+	Reason    string
+	CreatedAt time.Time
+}
+
+func newSecurityEvent(event domainaudit.Event) SecurityEvent {
+	return SecurityEvent{
+		ID:        event.ID,
+		Type:      event.Type,
+		UserID:    event.UserID,
+		IP:        event.IP,
+		UserAgent: event.UserAgent,
+		SessionID: event.SessionID,
+		Outcome:   event.Outcome,
+		Reason:    event.Reason,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+// Limits of the number of events listed at once.
+const (
+	defaultSecurityEventsLimit = 50
+	maxSecurityEventsLimit     = 500
+)
+
+// securityEventsLimit returns the number of events to list for the requested limit, 0 means the default.
+func securityEventsLimit(limit int) (int, bool) {
+	if limit == 0 {
+		return defaultSecurityEventsLimit, true
+	}
+	return limit, limit > 0 && limit <= maxSecurityEventsLimit
+}
+
+// ListSecurityEventsRequest lists the events of the account of the signed-in user, newest first. Before pages
+// through older events: only events created before it are listed, the zero time lists the newest events. Limit is
+// the maximum number of events, 0 means the default.
+type ListSecurityEventsRequest struct {
+	AccessToken string
+	Before      time.Time
+	Limit       int
+}
+
+type ListSecurityEventsResponse struct {
+	Events []SecurityEvent
+}
+
+type ListSecurityEventsRequestHandler interface {
+	Handle(ctx context.Context, request ListSecurityEventsRequest) (ListSecurityEventsResponse, error)
+}
+
+type listSecurityEventsRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	AuditEventsFinder AuditEventsFinder
+}
+
+var (
+	ErrListSecurityEventsInvalidOrExpiredToken = errListSecurityEventsInvalidOrExpiredToken()
+	ErrListSecurityEventsInvalidLimit          = errListSecurityEventsInvalidLimit()
+)
+
+func errListSecurityEventsInvalidOrExpiredToken() error {
+	return errors.New("invalid or expired token")
+}
+
+func errListSecurityEventsInvalidLimit() error { return errors.New("invalid limit") }
+
+func NewListSecurityEventsRequestHandler(
+	accessTokenParser AccessTokenParser,
+	auditEventsFinder AuditEventsFinder,
+) ListSecurityEventsRequestHandler {
+	return &listSecurityEventsRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		AuditEventsFinder: auditEventsFinder,
+	}
+}
+
+func (h listSecurityEventsRequestHandler) Handle(
+	ctx context.Context, request ListSecurityEventsRequest,
+) (ListSecurityEventsResponse, error) {
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return ListSecurityEventsResponse{}, errors.Join(err, ErrListSecurityEventsInvalidOrExpiredToken)
+	}
+
+	limit, ok := securityEventsLimit(request.Limit)
+	if !ok {
+		return ListSecurityEventsResponse{}, ErrListSecurityEventsInvalidLimit
+	}
+
+	events, err := h.AuditEventsFinder.FindAuditEvents(ctx, domainaudit.Filter{
+		UserID: claims.UserID,
+		Until:  request.Before,
+		Limit:  limit,
+	})
+	if err != nil {
+		return ListSecurityEventsResponse{}, fmt.Errorf("failed to find audit events: %w", err)
+	}
+
+	response := ListSecurityEventsResponse{Events: make([]SecurityEvent, 0, len(events))}
+	for _, event := range events {
+		response.Events = append(response.Events, newSecurityEvent(event))
+	}
+	return response, nil
+}
+
+// ListAuditEventsRequest lists the events of all users selected by the filters that are set, newest first. Since is
+// inclusive and Until is exclusive. Limit is the maximum number of events, 0 means the default. It is available to
+// administrators only.
+type ListAuditEventsRequest struct {
+	AccessToken string
+
+	UserID  string
+	Type    string
+	Outcome string
+	IP      string
+	Since   time.Time
+	Until   time.Time
+	Limit   int
+}
+
+type ListAuditEventsResponse struct {
+	Events []SecurityEvent
+}
+
+type ListAuditEventsRequestHandler interface {
+	Handle(ctx context.Context, request ListAuditEventsRequest) (ListAuditEventsResponse, error)
+}
+
+type listAuditEventsRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	AuditEventsFinder AuditEventsFinder
+}
+
+var (
+	ErrListAuditEventsInvalidOrExpiredToken = errListAuditEventsInvalidOrExpiredToken()
+	ErrListAuditEventsPermissionDenied      = errListAuditEventsPermissionDenied()
+	ErrListAuditEventsInvalidLimit          = errListAuditEventsInvalidLimit()
+	ErrListAuditEventsInvalidOutcome        = errListAuditEventsInvalidOutcome()
+)
+
+func errListAuditEventsInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errListAuditEventsPermissionDenied() error      { return errors.New("permission denied") }
+func errListAuditEventsInvalidLimit() error          { return errors.New("invalid limit") }
+func errListAuditEventsInvalidOutcome() error        { return errors.New("invalid outcome") }
+
+func NewListAuditEventsRequestHandler(
+	accessTokenParser AccessTokenParser,
+	auditEventsFinder AuditEventsFinder,
+) ListAuditEventsRequestHandler {
+	return &listAuditEventsRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		AuditEventsFinder: auditEventsFinder,
+	}
+}
+
+func (h listAuditEventsRequestHandler) Handle(
+	ctx context.Context, request ListAuditEventsRequest,
+) (ListAuditEventsResponse, error) {
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return ListAuditEventsResponse{}, errors.Join(err, ErrListAuditEventsInvalidOrExpiredToken)
+	} else if !isAdmin(claims) {
+		return ListAuditEventsResponse{}, ErrListAuditEventsPermissionDenied
+	}
+
+	limit, ok := securityEventsLimit(request.Limit)
+	if !ok {
+		return ListAuditEventsResponse{}, ErrListAuditEventsInvalidLimit
+	}
+
+	switch request.Outcome {
+	case "", domainaudit.OutcomeSuccess, domainaudit.OutcomeFailure:
+	default:
+		return ListAuditEventsResponse{}, ErrListAuditEventsInvalidOutcome
+	}
+
+	// The user ID column is a UUID, anything else can't match any event.
+	if len(request.UserID) != 0 {
+		if _, err := uuid.Parse(request.UserID); err != nil {
+			return ListAuditEventsResponse{Events: []SecurityEvent{}}, nil
+		}
+	}
+
+	events, err := h.AuditEventsFinder.FindAuditEvents(ctx, domainaudit.Filter{
+		UserID:  request.UserID,
+		Type:    request.Type,
+		Outcome: request.Outcome,
+		IP:      request.IP,
+		Since:   request.Since,
+		Until:   request.Until,
+		Limit:   limit,
+	})
+	if err != nil {
+		return ListAuditEventsResponse{}, fmt.Errorf("failed to find audit events: %w", err)
+	}
+
+	response := ListAuditEventsResponse{Events: make([]SecurityEvent, 0, len(events))}
+	for _, event := range events {
+		response.Events = append(response.Events, newSecurityEvent(event))
+	}
+	return response, nil
+}
@@ -6,21 +6,29 @@ import (
 	"fmt"
 	"time"
 
-	gojwt "github.com/golang-jwt/jwt/v4"
-	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	"github.com/google/uuid"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domaintotp "github.com/nazarslota/unotes/auth/internal/domain/totp"
 	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
-	"github.com/nazarslota/unotes/auth/pkg/jwt"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/nazarslota/unotes/auth/pkg/password"
 )
 
+// SignInRequest holds the credentials of the user. IP is the client IP address, failed attempts are counted per
+// username and per IP address.
 type SignInRequest struct {
 	Username string
 	Password string
+	IP       string
 }
 
+// SignInResponse holds either the token pair or, when the user has two-factor authentication enabled, a short-lived
+// challenge token that must be exchanged for the token pair with SignInMFARequestHandler.
 type SignInResponse struct {
 	AccessToken  string
 	RefreshToken string
+
+	MFARequired bool
+	MFAToken    string
 }
 
 type SignInRequestHandler interface {
@@ -34,26 +42,51 @@ type signInRequestHandler struct {
 	RefreshTokenCreator   RefreshTokenCreator
 	RefreshTokenExpiresIn time.Duration
 
+	MFATokenCreator   MFATokenCreator
+	MFATokenExpiresIn time.Duration
+
+	PasswordHasher PasswordHasher
+
 	RefreshTokenSaver RefreshTokenSaver
 
-	UserFinder UserFinder
+	UserFinder  UserFinder
+	UserUpdater UserUpdater
+	TOTPFinder  TOTPFinder
+
+	LockoutPolicy         LockoutPolicy
+	SignInFailureSaver    SignInFailureSaver
+	SignInLockSaver       SignInLockSaver
+	SignInLockFinder      SignInLockFinder
+	SignInFailuresDeleter SignInFailuresDeleter
+
+	AuditLogger AuditLogger
 }
 
 var (
 	ErrSignInInvalidUsername = errSignInInvalidUsername()
 	ErrSignInInvalidPassword = errSignInInvalidPassword()
 	ErrSignInUserNotFound    = errSignInUserNotFound()
+	ErrSignInLocked          = errSignInLocked()
+	ErrSignInDisabled        = errSignInDisabled()
 )
 
 func errSignInInvalidUsername() error { return errors.New("invalid username") }
 func errSignInInvalidPassword() error { return errors.New("invalid password") }
 func errSignInUserNotFound() error    { return domainuser.ErrUserNotFound }
+func errSignInLocked() error          { return errors.New("too many failed attempts") }
+func errSignInDisabled() error        { return errors.New("account disabled") }
 
 func NewSignInRequestHandler(
 	accessTokenCreator AccessTokenCreator, accessTokenExpiresIn time.Duration,
 	refreshTokenCreator RefreshTokenCreator, refreshTokenExpiresIn time.Duration,
+	mfaTokenCreator MFATokenCreator, mfaTokenExpiresIn time.Duration,
+	passwordHasher PasswordHasher,
 	refreshTokenSaver RefreshTokenSaver,
-	userFinder UserFinder,
+	userFinder UserFinder, userUpdater UserUpdater, totpFinder TOTPFinder,
+	lockoutPolicy LockoutPolicy,
+	signInFailureSaver SignInFailureSaver, signInLockSaver SignInLockSaver,
+	signInLockFinder SignInLockFinder, signInFailuresDeleter SignInFailuresDeleter,
+	auditLogger AuditLogger,
 ) SignInRequestHandler {
 	return &signInRequestHandler{
 		AccessTokenCreator:   accessTokenCreator,
@@ -62,49 +95,103 @@ func NewSignInRequestHandler(
 		RefreshTokenCreator:   refreshTokenCreator,
 		RefreshTokenExpiresIn: refreshTokenExpiresIn,
 
+		MFATokenCreator:   mfaTokenCreator,
+		MFATokenExpiresIn: mfaTokenExpiresIn,
+
+		PasswordHasher: passwordHasher,
+
 		RefreshTokenSaver: refreshTokenSaver,
 
-		UserFinder: userFinder,
+		UserFinder:  userFinder,
+		UserUpdater: userUpdater,
+		TOTPFinder:  totpFinder,
+
+		LockoutPolicy:         lockoutPolicy,
+		SignInFailureSaver:    signInFailureSaver,
+		SignInLockSaver:       signInLockSaver,
+		SignInLockFinder:      signInLockFinder,
+		SignInFailuresDeleter: signInFailuresDeleter,
+
+		AuditLogger: auditLogger,
 	}
 }
 
-func (h signInRequestHandler) Handle(ctx context.Context, request SignInRequest) (SignInResponse, error) {
+func (h signInRequestHandler) Handle(ctx context.Context, request SignInRequest) (_ SignInResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeSignIn}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	if err := checkSignInLock(ctx, h.SignInLockFinder, h.LockoutPolicy, request.Username, request.IP); err != nil {
+		return SignInResponse{}, err
+	}
+
 	user, err := h.UserFinder.FindUserByUsername(ctx, request.Username)
-	if err != nil {
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = errors.Join(fmt.Errorf("failed to find user: %w", err), ErrSignInInvalidUsername)
+		return SignInResponse{}, h.failure(ctx, request, err)
+	} else if err != nil {
 		err = fmt.Errorf("failed to find user: %w", err)
 		return SignInResponse{}, errors.Join(err, ErrSignInInvalidUsername)
 	}
+	event.UserID = user.ID
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(request.Password))
-	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-		err = fmt.Errorf("failed to compare passwords: %w", err)
-		return SignInResponse{}, errors.Join(err, ErrSignInInvalidPassword)
+	err = h.PasswordHasher.Verify(request.Password, user.PasswordHash)
+	if errors.Is(err, password.ErrMismatchedHashAndPassword) {
+		err = errors.Join(fmt.Errorf("failed to compare passwords: %w", err), ErrSignInInvalidPassword)
+		return SignInResponse{}, h.failure(ctx, request, err)
 	} else if err != nil {
 		return SignInResponse{}, fmt.Errorf("failed to compare passwords: %w", err)
 	}
 
-	accessToken, err := h.AccessTokenCreator.New(jwt.AccessTokenClaims{
-		RegisteredClaims: gojwt.RegisteredClaims{
-			ExpiresAt: gojwt.NewNumericDate(time.Now().Add(h.AccessTokenExpiresIn)),
-		},
-		UserID: user.ID,
-	})
-	if err != nil {
-		return SignInResponse{}, fmt.Errorf("failed to create access token: %w", err)
+	// Only once the password is verified, so that it doesn't reveal which accounts are disabled.
+	if user.Disabled {
+		return SignInResponse{}, ErrSignInDisabled
+	}
+
+	// The password is only known here, so an outdated hash is replaced now. If that fails, the hash still verifies,
+	// so the sign in goes on and the password is rehashed the next time.
+	if h.PasswordHasher.NeedsRehash(user.PasswordHash) {
+		if passwordHash, err := h.PasswordHasher.Hash(request.Password); err == nil {
+			user.PasswordHash = passwordHash
+			_ = h.UserUpdater.UpdateUser(ctx, user)
+		}
 	}
-	refreshToken, err := h.RefreshTokenCreator.New(jwt.RefreshTokenClaims{
-		RegisteredClaims: gojwt.RegisteredClaims{
-			ExpiresAt: gojwt.NewNumericDate(time.Now().Add(h.RefreshTokenExpiresIn)),
-		},
-		UserID: user.ID,
-	})
+
+	err = h.SignInFailuresDeleter.DeleteSignInFailures(ctx, usernameLockoutKey(request.Username))
 	if err != nil {
-		return SignInResponse{}, fmt.Errorf("failed to create refresh token: %w", err)
+		return SignInResponse{}, fmt.Errorf("failed to delete sign in failures: %w", err)
+	}
+
+	totp, err := h.TOTPFinder.FindTOTPByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, domaintotp.ErrTOTPNotFound) {
+		return SignInResponse{}, fmt.Errorf("failed to find totp: %w", err)
+	} else if err == nil && totp.Enabled {
+		mfaToken, err := newMFAToken(h.MFATokenCreator, h.MFATokenExpiresIn, user)
+		if err != nil {
+			return SignInResponse{}, err
+		}
+		// The sign in is recorded once the second factor is verified, this only records that the password was.
+		event.Reason = "mfa required"
+		return SignInResponse{MFARequired: true, MFAToken: mfaToken}, nil
 	}
 
-	err = h.RefreshTokenSaver.SaveRefreshToken(ctx, user.ID, domainrefresh.Token(refreshToken))
+	event.SessionID = uuid.New().String()
+	accessToken, refreshToken, err := newTokenPair(ctx,
+		h.AccessTokenCreator, h.AccessTokenExpiresIn,
+		h.RefreshTokenCreator, h.RefreshTokenExpiresIn,
+		h.RefreshTokenSaver,
+		user, event.SessionID,
+	)
 	if err != nil {
-		return SignInResponse{}, fmt.Errorf("failed to save refresh token: %w", err)
+		return SignInResponse{}, err
 	}
 	return SignInResponse{AccessToken: accessToken, RefreshToken: refreshToken}, nil
 }
+
+// failure registers a failed sign in attempt and returns err, joined with any error that occurred while registering.
+func (h signInRequestHandler) failure(ctx context.Context, request SignInRequest, err error) error {
+	return errors.Join(err, registerSignInFailure(ctx,
+		h.SignInFailureSaver, h.SignInLockSaver,
+		h.LockoutPolicy,
+		request.Username, request.IP,
+	))
+}
@@ -0,0 +1,180 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domaintotp "github.com/nazarslota/unotes/auth/internal/domain/totp"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+)
+
+// SignInMFARequest exchanges the challenge token returned by SignInRequestHandler for a token pair. The code is
+// either the current TOTP code or one of the unused recovery codes. IP is the client IP address, wrong codes are
+// counted per user and per IP address.
+type SignInMFARequest struct {
+	MFAToken string
+	Code     string
+	IP       string
+}
+
+type SignInMFAResponse struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+// SignInMFARequestHandler exchanges the challenge token for a token pair. Besides its own errors it returns a
+// SignInLockedError after too many wrong codes. A challenge token is rejected after maxMFATokenAttempts wrong codes.
+type SignInMFARequestHandler interface {
+	Handle(ctx context.Context, request SignInMFARequest) (SignInMFAResponse, error)
+}
+
+type signInMFARequestHandler struct {
+	AccessTokenCreator   AccessTokenCreator
+	AccessTokenExpiresIn time.Duration
+
+	RefreshTokenCreator   RefreshTokenCreator
+	RefreshTokenExpiresIn time.Duration
+
+	MFATokenParser MFATokenParser
+
+	RefreshTokenSaver RefreshTokenSaver
+
+	UserFinder              UserFinder
+	TOTPFinder              TOTPFinder
+	TOTPLastUsedStepUpdater TOTPLastUsedStepUpdater
+	RecoveryCodeDeleter     RecoveryCodeDeleter
+
+	LockoutPolicy         LockoutPolicy
+	SignInFailureSaver    SignInFailureSaver
+	SignInLockSaver       SignInLockSaver
+	SignInLockFinder      SignInLockFinder
+	SignInFailuresDeleter SignInFailuresDeleter
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrSignInMFAInvalidOrExpiredToken = errSignInMFAInvalidOrExpiredToken()
+	ErrSignInMFAInvalidCode           = errSignInMFAInvalidCode()
+)
+
+func errSignInMFAInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errSignInMFAInvalidCode() error           { return errors.New("invalid code") }
+
+func NewSignInMFARequestHandler(
+	accessTokenCreator AccessTokenCreator, accessTokenExpiresIn time.Duration,
+	refreshTokenCreator RefreshTokenCreator, refreshTokenExpiresIn time.Duration,
+	mfaTokenParser MFATokenParser,
+	refreshTokenSaver RefreshTokenSaver,
+	userFinder UserFinder, totpFinder TOTPFinder,
+	totpLastUsedStepUpdater TOTPLastUsedStepUpdater, recoveryCodeDeleter RecoveryCodeDeleter,
+	lockoutPolicy LockoutPolicy,
+	signInFailureSaver SignInFailureSaver, signInLockSaver SignInLockSaver,
+	signInLockFinder SignInLockFinder, signInFailuresDeleter SignInFailuresDeleter,
+	auditLogger AuditLogger,
+) SignInMFARequestHandler {
+	return &signInMFARequestHandler{
+		AccessTokenCreator:   accessTokenCreator,
+		AccessTokenExpiresIn: accessTokenExpiresIn,
+
+		RefreshTokenCreator:   refreshTokenCreator,
+		RefreshTokenExpiresIn: refreshTokenExpiresIn,
+
+		MFATokenParser: mfaTokenParser,
+
+		RefreshTokenSaver: refreshTokenSaver,
+
+		UserFinder:              userFinder,
+		TOTPFinder:              totpFinder,
+		TOTPLastUsedStepUpdater: totpLastUsedStepUpdater,
+		RecoveryCodeDeleter:     recoveryCodeDeleter,
+
+		LockoutPolicy:         lockoutPolicy,
+		SignInFailureSaver:    signInFailureSaver,
+		SignInLockSaver:       signInLockSaver,
+		SignInLockFinder:      signInLockFinder,
+		SignInFailuresDeleter: signInFailuresDeleter,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h signInMFARequestHandler) Handle(ctx context.Context, request SignInMFARequest) (_ SignInMFAResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeSignInMFA}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.MFATokenParser.Parse(request.MFAToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse mfa token: %w", err)
+		return SignInMFAResponse{}, errors.Join(err, ErrSignInMFAInvalidOrExpiredToken)
+	}
+	event.UserID = claims.UserID
+
+	if locked, err := checkMFATokenLock(ctx, h.SignInLockFinder, request.MFAToken); err != nil {
+		return SignInMFAResponse{}, err
+	} else if locked {
+		return SignInMFAResponse{}, ErrSignInMFAInvalidOrExpiredToken
+	}
+
+	user, err := h.UserFinder.FindUserByUserID(ctx, claims.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return SignInMFAResponse{}, errors.Join(err, ErrSignInMFAInvalidOrExpiredToken)
+	} else if err != nil {
+		return SignInMFAResponse{}, fmt.Errorf("failed to find user: %w", err)
+	} else if user.Disabled {
+		return SignInMFAResponse{}, ErrSignInMFAInvalidOrExpiredToken
+	}
+
+	if err := checkSecondFactorLock(ctx, h.SignInLockFinder, h.LockoutPolicy, user.ID, request.IP); err != nil {
+		return SignInMFAResponse{}, err
+	}
+
+	totp, err := h.TOTPFinder.FindTOTPByUserID(ctx, claims.UserID)
+	if errors.Is(err, domaintotp.ErrTOTPNotFound) {
+		err = fmt.Errorf("failed to find totp: %w", err)
+		return SignInMFAResponse{}, errors.Join(err, ErrSignInMFAInvalidOrExpiredToken)
+	} else if err != nil {
+		return SignInMFAResponse{}, fmt.Errorf("failed to find totp: %w", err)
+	} else if !totp.Enabled {
+		return SignInMFAResponse{}, ErrSignInMFAInvalidOrExpiredToken
+	}
+
+	valid, err := verifySecondFactor(ctx, h.TOTPLastUsedStepUpdater, h.RecoveryCodeDeleter, totp, request.Code)
+	if err != nil {
+		return SignInMFAResponse{}, err
+	} else if !valid {
+		expiresIn := h.LockoutPolicy.Window
+		if claims.ExpiresAt != nil {
+			expiresIn = time.Until(claims.ExpiresAt.Time)
+		}
+
+		err := errors.Join(
+			ErrSignInMFAInvalidCode,
+			registerSecondFactorFailure(ctx,
+				h.SignInFailureSaver, h.SignInLockSaver, h.LockoutPolicy, user.ID, request.IP),
+			registerMFATokenFailure(ctx, h.SignInFailureSaver, h.SignInLockSaver, request.MFAToken, expiresIn),
+		)
+		return SignInMFAResponse{}, err
+	}
+
+	if err := h.SignInFailuresDeleter.DeleteSignInFailures(ctx, mfaUserLockoutKey(user.ID)); err != nil {
+		return SignInMFAResponse{}, fmt.Errorf("failed to delete sign in failures: %w", err)
+	}
+
+	event.SessionID = uuid.New().String()
+	accessToken, refreshToken, err := newTokenPair(ctx,
+		h.AccessTokenCreator, h.AccessTokenExpiresIn,
+		h.RefreshTokenCreator, h.RefreshTokenExpiresIn,
+		h.RefreshTokenSaver,
+		user, event.SessionID,
+	)
+	if err != nil {
+		return SignInMFAResponse{}, err
+	}
+	return SignInMFAResponse{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
@@ -0,0 +1,88 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+)
+
+// UnlockSignInRequest resets the failed sign in attempts of a username, a client IP address, or both. It is
+// available to administrators only.
+type UnlockSignInRequest struct {
+	AccessToken string
+	Username    string
+	IP          string
+}
+
+type UnlockSignInResponse struct{}
+
+type UnlockSignInRequestHandler interface {
+	Handle(ctx context.Context, request UnlockSignInRequest) (UnlockSignInResponse, error)
+}
+
+type unlockSignInRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	SignInFailuresDeleter SignInFailuresDeleter
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrUnlockSignInInvalidOrExpiredToken = errUnlockSignInInvalidOrExpiredToken()
+	ErrUnlockSignInPermissionDenied      = errUnlockSignInPermissionDenied()
+	ErrUnlockSignInInvalidRequest        = errUnlockSignInInvalidRequest()
+)
+
+func errUnlockSignInInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errUnlockSignInPermissionDenied() error      { return errors.New("permission denied") }
+func errUnlockSignInInvalidRequest() error        { return errors.New("username or ip is required") }
+
+func NewUnlockSignInRequestHandler(
+	accessTokenParser AccessTokenParser,
+	signInFailuresDeleter SignInFailuresDeleter,
+	auditLogger AuditLogger,
+) UnlockSignInRequestHandler {
+	return &unlockSignInRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		SignInFailuresDeleter: signInFailuresDeleter,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h unlockSignInRequestHandler) Handle(ctx context.Context, request UnlockSignInRequest) (_ UnlockSignInResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeSignInUnlock}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return UnlockSignInResponse{}, errors.Join(err, ErrUnlockSignInInvalidOrExpiredToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+	if !isAdmin(claims) {
+		return UnlockSignInResponse{}, ErrUnlockSignInPermissionDenied
+	}
+
+	var keys []string
+	if len(request.Username) != 0 {
+		keys = append(keys, usernameLockoutKey(request.Username))
+	}
+	if len(request.IP) != 0 {
+		keys = append(keys, ipLockoutKey(request.IP))
+	}
+	if len(keys) == 0 {
+		return UnlockSignInResponse{}, ErrUnlockSignInInvalidRequest
+	}
+
+	for _, key := range keys {
+		if err := h.SignInFailuresDeleter.DeleteSignInFailures(ctx, key); err != nil {
+			return UnlockSignInResponse{}, fmt.Errorf("failed to delete sign in failures: %w", err)
+		}
+	}
+	return UnlockSignInResponse{}, nil
+}
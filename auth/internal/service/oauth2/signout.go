@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
 	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
 )
 
@@ -23,6 +24,11 @@ type signOutRequestHandler struct {
 
 	RefreshTokensDeleter RefreshTokensDeleter
 	RefreshTokenGetter   RefreshTokenGetter
+
+	RevokedTokenSaver     RevokedTokenSaver
+	RevokedTokenPublisher RevokedTokenPublisher
+
+	AuditLogger AuditLogger
 }
 
 var ErrSignOutInvalidOrExpiredToken = errSignOutInvalidOrExpiredToken()
@@ -32,21 +38,37 @@ func errSignOutInvalidOrExpiredToken() error { return errors.New("invalid or exp
 func NewSignOutRequestHandler(
 	accessTokenParser AccessTokenParser,
 	refreshTokensDeleter RefreshTokensDeleter, refreshTokenGetter RefreshTokenGetter,
+	revokedTokenSaver RevokedTokenSaver, revokedTokenPublisher RevokedTokenPublisher,
+	auditLogger AuditLogger,
 ) LogOutRequestHandler {
 	return &signOutRequestHandler{
 		AccessTokenParser: accessTokenParser,
 
 		RefreshTokensDeleter: refreshTokensDeleter,
 		RefreshTokenGetter:   refreshTokenGetter,
+
+		RevokedTokenSaver:     revokedTokenSaver,
+		RevokedTokenPublisher: revokedTokenPublisher,
+
+		AuditLogger: auditLogger,
 	}
 }
 
-func (h signOutRequestHandler) Handle(ctx context.Context, request SignOutRequest) (SignOutResponse, error) {
+func (h signOutRequestHandler) Handle(ctx context.Context, request SignOutRequest) (_ SignOutResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeSignOut}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
 	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
 	if err != nil {
 		err = fmt.Errorf("failed to parse access token: %w", err)
 		return SignOutResponse{}, errors.Join(err, ErrSignOutInvalidOrExpiredToken)
 	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+
+	// The access token used to sign out is revoked, so that it stops working before it expires.
+	if err := revokeAccessToken(ctx, h.RevokedTokenSaver, h.RevokedTokenPublisher, claims); err != nil {
+		return SignOutResponse{}, err
+	}
 
 	tokens, err := h.RefreshTokenGetter.GetRefreshTokens(ctx, claims.UserID)
 	if errors.Is(err, domainrefresh.ErrTokenNotFound) {
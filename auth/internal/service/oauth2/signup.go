@@ -4,15 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
 	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
-	"golang.org/x/crypto/bcrypt"
 )
 
+// SignUpRequest holds the credentials of a new user. Email is optional, if set, a verification link is mailed to it.
 type SignUpRequest struct {
 	Username string
 	Password string
+	Email    string
 }
 
 type SignUpResponse struct{}
@@ -22,45 +25,101 @@ type SignUpRequestHandler interface {
 }
 
 type signUpRequestHandler struct {
-	UserSaver UserSaver
+	EmailVerificationURL            string
+	EmailVerificationTokenExpiresIn time.Duration
+
+	PasswordHasher PasswordHasher
+	PasswordPolicy PasswordPolicy
+
+	UserSaver                   UserSaver
+	EmailVerificationTokenSaver EmailVerificationTokenSaver
+
+	Mailer Mailer
+
+	AuditLogger AuditLogger
 }
 
 var (
-	ErrSignUpInvalidUsername   = errSignUpInvalidUsername()
-	ErrSignUpInvalidPassword   = errSignUpInvalidPassword()
-	ErrSignUpUserAlreadyExists = errSignUpUserAlreadyExists()
+	ErrSignUpInvalidUsername    = errSignUpInvalidUsername()
+	ErrSignUpInvalidPassword    = errSignUpInvalidPassword()
+	ErrSignUpInvalidEmail       = errSignUpInvalidEmail()
+	ErrSignUpUserAlreadyExists  = errSignUpUserAlreadyExists()
+	ErrSignUpEmailAlreadyExists = errSignUpEmailAlreadyExists()
 )
 
-func errSignUpInvalidUsername() error   { return errors.New("invalid username") }
-func errSignUpInvalidPassword() error   { return errors.New("invalid password") }
-func errSignUpUserAlreadyExists() error { return domainuser.ErrUserAlreadyExists }
+func errSignUpInvalidUsername() error    { return errors.New("invalid username") }
+func errSignUpInvalidPassword() error    { return errors.New("invalid password") }
+func errSignUpInvalidEmail() error       { return errors.New("invalid email") }
+func errSignUpUserAlreadyExists() error  { return domainuser.ErrUserAlreadyExists }
+func errSignUpEmailAlreadyExists() error { return domainuser.ErrEmailAlreadyExists }
 
 func NewSignUpRequestHandler(
-	userSaver UserSaver,
+	emailVerificationURL string, emailVerificationTokenExpiresIn time.Duration,
+	passwordHasher PasswordHasher, passwordPolicy PasswordPolicy,
+	userSaver UserSaver, emailVerificationTokenSaver EmailVerificationTokenSaver,
+	mailer Mailer,
+	auditLogger AuditLogger,
 ) SignUpRequestHandler {
 	return &signUpRequestHandler{
-		UserSaver: userSaver,
+		EmailVerificationURL:            emailVerificationURL,
+		EmailVerificationTokenExpiresIn: emailVerificationTokenExpiresIn,
+
+		PasswordHasher: passwordHasher,
+		PasswordPolicy: passwordPolicy,
+
+		UserSaver:                   userSaver,
+		EmailVerificationTokenSaver: emailVerificationTokenSaver,
+
+		Mailer: mailer,
+
+		AuditLogger: auditLogger,
 	}
 }
 
-func (h signUpRequestHandler) Handler(ctx context.Context, request SignUpRequest) (SignUpResponse, error) {
+func (h signUpRequestHandler) Handler(ctx context.Context, request SignUpRequest) (_ SignUpResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeSignUp}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
 	if len(request.Username) == 0 {
 		return SignUpResponse{}, ErrSignUpInvalidUsername
 	} else if len(request.Password) == 0 {
 		return SignUpResponse{}, ErrSignUpInvalidPassword
+	} else if len(request.Email) != 0 && !validEmail(request.Email) {
+		return SignUpResponse{}, ErrSignUpInvalidEmail
+	}
+
+	if err := h.PasswordPolicy.check("password", request.Username, request.Password); err != nil {
+		return SignUpResponse{}, errors.Join(err, ErrSignUpInvalidPassword)
 	}
 
-	passwordHash, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.MinCost)
+	passwordHash, err := h.PasswordHasher.Hash(request.Password)
 	if err != nil {
 		return SignUpResponse{}, fmt.Errorf("failed to generate password hash: %w", err)
 	}
 
-	if err := h.UserSaver.SaveUser(ctx, domainuser.User{
+	user := domainuser.User{
 		ID:           uuid.New().String(),
 		Username:     request.Username,
-		PasswordHash: string(passwordHash),
-	}); err != nil {
+		PasswordHash: passwordHash,
+		Email:        request.Email,
+		Role:         domainuser.RoleUser,
+	}
+	if err := h.UserSaver.SaveUser(ctx, user); err != nil {
 		return SignUpResponse{}, fmt.Errorf("failed to save user: %w", err)
 	}
+	event.UserID = user.ID
+
+	if len(user.Email) == 0 {
+		return SignUpResponse{}, nil
+	}
+
+	if err := sendEmailVerification(ctx,
+		h.EmailVerificationURL, h.EmailVerificationTokenExpiresIn,
+		h.EmailVerificationTokenSaver,
+		h.Mailer,
+		user, user.Email,
+	); err != nil {
+		return SignUpResponse{}, err
+	}
 	return SignUpResponse{}, nil
 }
@@ -0,0 +1,397 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainauthorizationcode "github.com/nazarslota/unotes/auth/internal/domain/authorizationcode"
+	domainclient "github.com/nazarslota/unotes/auth/internal/domain/client"
+	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"golang.org/x/exp/slices"
+)
+
+// TokenRequest is an OAuth2 access token request of a client. Which fields are used depends on the grant type: Code,
+// RedirectURI and CodeVerifier for "authorization_code", RefreshToken for "refresh_token", Username and Password for
+// "password", and none for "client_credentials". ClientSecret is empty for public clients.
+type TokenRequest struct {
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+
+	RefreshToken string
+
+	Username string
+	Password string
+	IP       string
+
+	Scope string
+}
+
+// TokenResponse holds the issued tokens. IDToken is set only if the openid scope is granted.
+type TokenResponse struct {
+	AccessToken  string
+	TokenType    string
+	ExpiresIn    time.Duration
+	RefreshToken string
+	Scope        string
+	IDToken      string
+}
+
+// TokenRequestHandler issues tokens to OAuth2 clients. Its errors correspond to the error codes of RFC 6749, besides
+// a SignInLockedError for the password grant when sign in is locked.
+type TokenRequestHandler interface {
+	Handle(ctx context.Context, request TokenRequest) (TokenResponse, error)
+}
+
+type tokenRequestHandler struct {
+	AccessTokenCreator   AccessTokenCreator
+	AccessTokenExpiresIn time.Duration
+
+	IDTokenCreator IDTokenCreator
+	Issuer         string
+
+	RefreshTokenCreator   RefreshTokenCreator
+	RefreshTokenParser    RefreshTokenParser
+	RefreshTokenExpiresIn time.Duration
+
+	LockoutPolicy  LockoutPolicy
+	PasswordHasher PasswordHasher
+
+	RefreshTokenSaver   RefreshTokenSaver
+	RefreshTokenDeleter RefreshTokenDeleter
+	RefreshTokenGetter  RefreshTokenGetter
+
+	ClientFinder              ClientFinder
+	AuthorizationCodeConsumer AuthorizationCodeConsumer
+
+	UserFinder UserFinder
+	TOTPFinder TOTPFinder
+
+	SignInFailureSaver    SignInFailureSaver
+	SignInLockSaver       SignInLockSaver
+	SignInLockFinder      SignInLockFinder
+	SignInFailuresDeleter SignInFailuresDeleter
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrTokenInvalidRequest       = errTokenInvalidRequest()
+	ErrTokenInvalidClient        = errTokenInvalidClient()
+	ErrTokenInvalidGrant         = errTokenInvalidGrant()
+	ErrTokenUnauthorizedClient   = errTokenUnauthorizedClient()
+	ErrTokenUnsupportedGrantType = errTokenUnsupportedGrantType()
+	ErrTokenInvalidScope         = errTokenInvalidScope()
+)
+
+func errTokenInvalidRequest() error       { return errors.New("invalid request") }
+func errTokenInvalidClient() error        { return errors.New("invalid client") }
+func errTokenInvalidGrant() error         { return errors.New("invalid grant") }
+func errTokenUnauthorizedClient() error   { return errors.New("unauthorized client") }
+func errTokenUnsupportedGrantType() error { return errors.New("unsupported grant type") }
+func errTokenInvalidScope() error         { return errors.New("invalid scope") }
+
+func NewTokenRequestHandler(
+	accessTokenCreator AccessTokenCreator, accessTokenExpiresIn time.Duration,
+	idTokenCreator IDTokenCreator, issuer string,
+	refreshTokenCreator RefreshTokenCreator, refreshTokenParser RefreshTokenParser, refreshTokenExpiresIn time.Duration,
+	lockoutPolicy LockoutPolicy, passwordHasher PasswordHasher,
+	refreshTokenSaver RefreshTokenSaver, refreshTokenDeleter RefreshTokenDeleter, refreshTokenGetter RefreshTokenGetter,
+	clientFinder ClientFinder, authorizationCodeConsumer AuthorizationCodeConsumer,
+	userFinder UserFinder, totpFinder TOTPFinder,
+	signInFailureSaver SignInFailureSaver, signInLockSaver SignInLockSaver,
+	signInLockFinder SignInLockFinder, signInFailuresDeleter SignInFailuresDeleter,
+	auditLogger AuditLogger,
+) TokenRequestHandler {
+	return &tokenRequestHandler{
+		AccessTokenCreator:   accessTokenCreator,
+		AccessTokenExpiresIn: accessTokenExpiresIn,
+
+		IDTokenCreator: idTokenCreator,
+		Issuer:         issuer,
+
+		RefreshTokenCreator:   refreshTokenCreator,
+		RefreshTokenParser:    refreshTokenParser,
+		RefreshTokenExpiresIn: refreshTokenExpiresIn,
+
+		LockoutPolicy:  lockoutPolicy,
+		PasswordHasher: passwordHasher,
+
+		RefreshTokenSaver:   refreshTokenSaver,
+		RefreshTokenDeleter: refreshTokenDeleter,
+		RefreshTokenGetter:  refreshTokenGetter,
+
+		ClientFinder:              clientFinder,
+		AuthorizationCodeConsumer: authorizationCodeConsumer,
+
+		UserFinder: userFinder,
+		TOTPFinder: totpFinder,
+
+		SignInFailureSaver:    signInFailureSaver,
+		SignInLockSaver:       signInLockSaver,
+		SignInLockFinder:      signInLockFinder,
+		SignInFailuresDeleter: signInFailuresDeleter,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h tokenRequestHandler) Handle(ctx context.Context, request TokenRequest) (_ TokenResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeToken}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	if len(request.GrantType) == 0 || len(request.ClientID) == 0 {
+		return TokenResponse{}, ErrTokenInvalidRequest
+	}
+
+	client, err := findClient(ctx, h.ClientFinder, request.ClientID, request.ClientSecret)
+	if errors.Is(err, errClientNotAuthenticated) {
+		return TokenResponse{}, errors.Join(err, ErrTokenInvalidClient)
+	} else if err != nil {
+		return TokenResponse{}, err
+	}
+
+	switch request.GrantType {
+	case GrantTypeAuthorizationCode, GrantTypeRefreshToken, GrantTypePassword, GrantTypeClientCredentials:
+		if !slices.Contains(client.GrantTypes, request.GrantType) {
+			return TokenResponse{}, ErrTokenUnauthorizedClient
+		}
+	default:
+		return TokenResponse{}, ErrTokenUnsupportedGrantType
+	}
+
+	if request.GrantType == GrantTypeClientCredentials {
+		return h.clientCredentials(client, request)
+	}
+
+	var g grant
+	switch request.GrantType {
+	case GrantTypeAuthorizationCode:
+		g, err = h.authorizationCode(ctx, client, request)
+	case GrantTypeRefreshToken:
+		g, err = h.refreshToken(ctx, client, request)
+	case GrantTypePassword:
+		g, err = h.password(ctx, client, request)
+	}
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	event.UserID, event.SessionID = g.User.ID, g.SessionID
+
+	// A refresh token is issued and saved only to clients that are allowed to use it.
+	var accessToken, refreshToken string
+	if slices.Contains(client.GrantTypes, GrantTypeRefreshToken) {
+		accessToken, refreshToken, err = newClientTokenPair(ctx,
+			h.AccessTokenCreator, h.AccessTokenExpiresIn,
+			h.RefreshTokenCreator, h.RefreshTokenExpiresIn,
+			h.RefreshTokenSaver,
+			g.User, client.ID, g.Scope, g.SessionID,
+		)
+	} else {
+		accessToken, err = newClientAccessToken(h.AccessTokenCreator, h.AccessTokenExpiresIn,
+			g.User, client.ID, g.Scope, g.SessionID,
+		)
+	}
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	response := TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    h.AccessTokenExpiresIn,
+		RefreshToken: refreshToken,
+		Scope:        g.Scope,
+	}
+
+	if slices.Contains(strings.Fields(g.Scope), ScopeOpenID) {
+		response.IDToken, err = newIDToken(h.IDTokenCreator, h.Issuer, h.AccessTokenExpiresIn,
+			g.User, client.ID, g.Scope, g.Nonce,
+		)
+		if err != nil {
+			return TokenResponse{}, err
+		}
+	}
+	return response, nil
+}
+
+// grant is what a grant authorizes tokens for: the user, the scope, the session the tokens belong to and, for the
+// authorization code grant, the OpenID Connect nonce.
+type grant struct {
+	User      domainuser.User
+	Scope     string
+	SessionID string
+	Nonce     string
+}
+
+// authorizationCode exchanges an authorization code, checking that it was issued to the same client and that the
+// client answers the PKCE challenge.
+func (h tokenRequestHandler) authorizationCode(ctx context.Context, client domainclient.Client, request TokenRequest) (
+	grant, error,
+) {
+	if len(request.Code) == 0 || len(request.CodeVerifier) == 0 {
+		return grant{}, ErrTokenInvalidRequest
+	}
+
+	code, err := h.AuthorizationCodeConsumer.ConsumeAuthorizationCode(ctx, hashOpaqueToken(request.Code))
+	if errors.Is(err, domainauthorizationcode.ErrCodeNotFound) {
+		err = fmt.Errorf("failed to consume authorization code: %w", err)
+		return grant{}, errors.Join(err, ErrTokenInvalidGrant)
+	} else if err != nil {
+		return grant{}, fmt.Errorf("failed to consume authorization code: %w", err)
+	}
+
+	if code.ClientID != client.ID {
+		return grant{}, ErrTokenInvalidGrant
+	} else if len(code.RedirectURI) != 0 && request.RedirectURI != code.RedirectURI {
+		// RFC 6749 section 4.1.3: a redirect URI sent with the authorization request must be sent again, unchanged.
+		return grant{}, ErrTokenInvalidGrant
+	} else if !verifyCodeChallenge(request.CodeVerifier, code.CodeChallenge) {
+		return grant{}, ErrTokenInvalidGrant
+	}
+
+	user, err := h.findUser(ctx, code.UserID)
+	if err != nil {
+		return grant{}, err
+	}
+	return grant{User: user, Scope: code.Scope, SessionID: uuid.New().String(), Nonce: code.Nonce}, nil
+}
+
+// refreshToken rotates a refresh token issued to the same client, keeping its session. The requested scope may narrow
+// the original one, but never widen it.
+func (h tokenRequestHandler) refreshToken(ctx context.Context, client domainclient.Client, request TokenRequest) (
+	grant, error,
+) {
+	if len(request.RefreshToken) == 0 {
+		return grant{}, ErrTokenInvalidRequest
+	}
+
+	claims, err := h.RefreshTokenParser.Parse(request.RefreshToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse refresh token: %w", err)
+		return grant{}, errors.Join(err, ErrTokenInvalidGrant)
+	} else if claims.ClientID != client.ID {
+		return grant{}, ErrTokenInvalidGrant
+	}
+
+	scope, ok := grantedScope(strings.Fields(claims.Scope), request.Scope)
+	if !ok {
+		return grant{}, ErrTokenInvalidScope
+	}
+
+	tokens, err := h.RefreshTokenGetter.GetRefreshTokens(ctx, claims.UserID)
+	if errors.Is(err, domainrefresh.ErrTokenNotFound) {
+		err = fmt.Errorf("failed to get refresh tokens: %w", err)
+		return grant{}, errors.Join(err, ErrTokenInvalidGrant)
+	} else if err != nil {
+		return grant{}, fmt.Errorf("failed to get refresh tokens: %w", err)
+	} else if !slices.Contains(tokens, domainrefresh.Token(request.RefreshToken)) {
+		return grant{}, ErrTokenInvalidGrant
+	}
+
+	user, err := h.findUser(ctx, claims.UserID)
+	if err != nil {
+		return grant{}, err
+	}
+
+	err = h.RefreshTokenDeleter.DeleteRefreshToken(ctx, claims.UserID, domainrefresh.Token(request.RefreshToken))
+	if err != nil {
+		return grant{}, fmt.Errorf("failed to delete refresh token: %w", err)
+	}
+
+	// Tokens issued before sessions were tracked don't have one, so they start a new session.
+	sessionID := claims.SessionID
+	if len(sessionID) == 0 {
+		sessionID = uuid.New().String()
+	}
+	return grant{User: user, Scope: scope, SessionID: sessionID}, nil
+}
+
+// password authenticates the user with their username and password. Users with two-factor authentication enabled
+// can't use this grant, since there is no way to ask them for a code.
+func (h tokenRequestHandler) password(ctx context.Context, client domainclient.Client, request TokenRequest) (
+	grant, error,
+) {
+	if len(request.Username) == 0 || len(request.Password) == 0 {
+		return grant{}, ErrTokenInvalidRequest
+	}
+
+	scope, ok := grantedScope(client.Scopes, request.Scope)
+	if !ok {
+		return grant{}, ErrTokenInvalidScope
+	}
+
+	user, err := authenticateUser(ctx,
+		h.PasswordHasher,
+		h.UserFinder,
+		h.LockoutPolicy,
+		h.SignInFailureSaver, h.SignInLockSaver,
+		h.SignInLockFinder, h.SignInFailuresDeleter,
+		request.Username, request.Password, request.IP,
+	)
+	if errors.Is(err, errInvalidCredentials) {
+		return grant{}, errors.Join(err, ErrTokenInvalidGrant)
+	} else if err != nil {
+		return grant{}, err
+	}
+
+	if _, enabled, err := totpEnabled(ctx, h.TOTPFinder, user.ID); err != nil {
+		return grant{}, err
+	} else if enabled {
+		return grant{}, ErrTokenInvalidGrant
+	}
+	return grant{User: user, Scope: scope, SessionID: uuid.New().String()}, nil
+}
+
+// clientCredentials issues an access token to a service account, whose subject is the client itself. The token has no
+// user ID, since it acts on behalf of no user, so services must not take the client for the owner of user data. No
+// refresh token is issued, since the client can always request a new access token.
+func (h tokenRequestHandler) clientCredentials(client domainclient.Client, request TokenRequest) (TokenResponse, error) {
+	if client.Public {
+		return TokenResponse{}, ErrTokenUnauthorizedClient
+	}
+
+	scope, ok := grantedScope(client.Scopes, request.Scope)
+	if !ok {
+		return TokenResponse{}, ErrTokenInvalidScope
+	}
+
+	accessToken, err := h.AccessTokenCreator.New(jwt.AccessTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Subject:   client.ID,
+			ExpiresAt: gojwt.NewNumericDate(time.Now().Add(h.AccessTokenExpiresIn)),
+		},
+		ClientID: client.ID,
+		Scope:    scope,
+	})
+	if err != nil {
+		return TokenResponse{}, fmt.Errorf("failed to create access token: %w", err)
+	}
+	return TokenResponse{AccessToken: accessToken, TokenType: "Bearer", ExpiresIn: h.AccessTokenExpiresIn, Scope: scope}, nil
+}
+
+func (h tokenRequestHandler) findUser(ctx context.Context, userID string) (domainuser.User, error) {
+	user, err := h.UserFinder.FindUserByUserID(ctx, userID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return domainuser.User{}, errors.Join(err, ErrTokenInvalidGrant)
+	} else if err != nil {
+		return domainuser.User{}, fmt.Errorf("failed to find user: %w", err)
+	} else if user.Disabled {
+		return domainuser.User{}, ErrTokenInvalidGrant
+	}
+	return user, nil
+}
@@ -0,0 +1,249 @@
+package oauth2
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	domainauthorizationcode "github.com/nazarslota/unotes/auth/internal/domain/authorizationcode"
+	domainclient "github.com/nazarslota/unotes/auth/internal/domain/client"
+	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clientFinder finds the clients in the map by their ID.
+type clientFinder map[string]domainclient.Client
+
+func (f clientFinder) FindClientByClientID(_ context.Context, clientID string) (domainclient.Client, error) {
+	client, ok := f[clientID]
+	if !ok {
+		return domainclient.Client{}, domainclient.ErrClientNotFound
+	}
+	return client, nil
+}
+
+// authorizationCodeConsumer consumes the codes in the map by their hash.
+type authorizationCodeConsumer map[string]domainauthorizationcode.Code
+
+func (c authorizationCodeConsumer) ConsumeAuthorizationCode(
+	_ context.Context, codeHash string,
+) (domainauthorizationcode.Code, error) {
+	code, ok := c[codeHash]
+	if !ok {
+		return domainauthorizationcode.Code{}, domainauthorizationcode.ErrCodeNotFound
+	}
+	delete(c, codeHash)
+	return code, nil
+}
+
+// userFinder finds the users in the map by their ID only.
+type userFinder map[string]domainuser.User
+
+func (f userFinder) FindUserByUsername(context.Context, string) (domainuser.User, error) {
+	return domainuser.User{}, domainuser.ErrUserNotFound
+}
+
+func (f userFinder) FindUserByUserID(_ context.Context, userID string) (domainuser.User, error) {
+	user, ok := f[userID]
+	if !ok {
+		return domainuser.User{}, domainuser.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (f userFinder) FindUserByEmail(context.Context, string) (domainuser.User, error) {
+	return domainuser.User{}, domainuser.ErrUserNotFound
+}
+
+// refreshTokenSaver keeps the saved refresh tokens by user ID.
+type refreshTokenSaver map[string][]domainrefresh.Token
+
+func (s refreshTokenSaver) SaveRefreshToken(_ context.Context, userID string, token domainrefresh.Token) error {
+	s[userID] = append(s[userID], token)
+	return nil
+}
+
+func TestTokenRequestHandler_AuthorizationCode(t *testing.T) {
+	const (
+		redirectURI = "https://client.example/callback"
+		verifier    = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	)
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	clients := clientFinder{
+		"web": {
+			ID:           "web",
+			SecretHash:   hashOpaqueToken("secret"),
+			RedirectURIs: []string{redirectURI},
+			Scopes:       []string{"notes:read"},
+			GrantTypes:   []string{GrantTypeAuthorizationCode, GrantTypeRefreshToken},
+		},
+		"no-refresh": {
+			ID:           "no-refresh",
+			SecretHash:   hashOpaqueToken("secret"),
+			RedirectURIs: []string{redirectURI},
+			Scopes:       []string{"notes:read"},
+			GrantTypes:   []string{GrantTypeAuthorizationCode},
+		},
+	}
+	codes := authorizationCodeConsumer{}
+	refreshTokens := refreshTokenSaver{}
+
+	h := tokenRequestHandler{
+		AccessTokenCreator:    jwt.NewAccessTokenManagerHMAC("access-token-secret"),
+		AccessTokenExpiresIn:  time.Minute,
+		RefreshTokenCreator:   jwt.NewRefreshTokenManagerHMAC("refresh-token-secret"),
+		RefreshTokenExpiresIn: time.Hour,
+
+		RefreshTokenSaver:         refreshTokens,
+		ClientFinder:              clients,
+		AuthorizationCodeConsumer: codes,
+		UserFinder:                userFinder{"user-id": {ID: "user-id", Username: "username"}},
+	}
+
+	// exchange issues a code to the client for the redirect URI of the authorization request and exchanges it with
+	// the redirect URI of the token request.
+	exchange := func(clientID, authorizeRedirectURI, tokenRedirectURI string) (TokenResponse, error) {
+		codes[hashOpaqueToken("code")] = domainauthorizationcode.Code{
+			CodeHash:      hashOpaqueToken("code"),
+			ClientID:      clientID,
+			UserID:        "user-id",
+			RedirectURI:   authorizeRedirectURI,
+			Scope:         "notes:read",
+			CodeChallenge: challenge,
+			ExpiresAt:     time.Now().Add(time.Minute),
+		}
+		return h.Handle(context.Background(), TokenRequest{
+			GrantType:    GrantTypeAuthorizationCode,
+			ClientID:     clientID,
+			ClientSecret: "secret",
+			Code:         "code",
+			RedirectURI:  tokenRedirectURI,
+			CodeVerifier: verifier,
+		})
+	}
+
+	t.Run("should require redirect uri sent with authorization request", func(t *testing.T) {
+		_, err := exchange("web", redirectURI, "")
+		assert.ErrorIs(t, err, ErrTokenInvalidGrant)
+
+		_, err = exchange("web", redirectURI, "https://client.example/other")
+		assert.ErrorIs(t, err, ErrTokenInvalidGrant)
+
+		response, err := exchange("web", redirectURI, redirectURI)
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.AccessToken)
+		assert.NotEmpty(t, response.RefreshToken)
+		assert.Len(t, refreshTokens["user-id"], 1)
+	})
+
+	t.Run("should not require redirect uri left out of authorization request", func(t *testing.T) {
+		response, err := exchange("web", "", "")
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.AccessToken)
+	})
+
+	t.Run("should not issue or save refresh token of client without refresh token grant", func(t *testing.T) {
+		delete(refreshTokens, "user-id")
+
+		response, err := exchange("no-refresh", redirectURI, redirectURI)
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.AccessToken)
+		assert.Empty(t, response.RefreshToken)
+		assert.Empty(t, refreshTokens["user-id"])
+	})
+}
+
+func TestTokenRequestHandler_ClientCredentials(t *testing.T) {
+	accessTokenManager := jwt.NewAccessTokenManagerHMAC("access-token-secret")
+	clients := clientFinder{
+		"service": {
+			ID:         "service",
+			SecretHash: hashOpaqueToken("secret"),
+			Scopes:     []string{"notes:read", "notes:write"},
+			GrantTypes: []string{GrantTypeClientCredentials, GrantTypeRefreshToken},
+		},
+		"public": {
+			ID:         "public",
+			Scopes:     []string{"notes:read"},
+			GrantTypes: []string{GrantTypeClientCredentials},
+			Public:     true,
+		},
+		"web": {
+			ID:         "web",
+			SecretHash: hashOpaqueToken("secret"),
+			Scopes:     []string{"notes:read"},
+			GrantTypes: []string{GrantTypeAuthorizationCode, GrantTypeRefreshToken},
+		},
+	}
+
+	h := tokenRequestHandler{
+		AccessTokenCreator:   accessTokenManager,
+		AccessTokenExpiresIn: time.Minute,
+		ClientFinder:         clients,
+	}
+
+	request := func(clientID, clientSecret, scope string) TokenRequest {
+		return TokenRequest{
+			GrantType:    GrantTypeClientCredentials,
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Scope:        scope,
+		}
+	}
+
+	t.Run("should issue access token to client without refresh token", func(t *testing.T) {
+		response, err := h.Handle(context.Background(), request("service", "secret", ""))
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer", response.TokenType)
+		assert.Equal(t, "notes:read notes:write", response.Scope)
+		assert.Empty(t, response.RefreshToken)
+		assert.Empty(t, response.IDToken)
+
+		claims, err := accessTokenManager.Parse(response.AccessToken)
+		require.NoError(t, err)
+		assert.Empty(t, claims.UserID)
+		assert.Equal(t, "service", claims.Subject)
+		assert.Equal(t, "service", claims.ClientID)
+		assert.Equal(t, "notes:read notes:write", claims.Scope)
+	})
+
+	t.Run("should grant requested scope of client", func(t *testing.T) {
+		response, err := h.Handle(context.Background(), request("service", "secret", "notes:read"))
+		require.NoError(t, err)
+		assert.Equal(t, "notes:read", response.Scope)
+
+		claims, err := accessTokenManager.Parse(response.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "notes:read", claims.Scope)
+	})
+
+	t.Run("should reject scope not allowed to client", func(t *testing.T) {
+		_, err := h.Handle(context.Background(), request("service", "secret", "notes:read openid"))
+		assert.ErrorIs(t, err, ErrTokenInvalidScope)
+	})
+
+	t.Run("should reject public client", func(t *testing.T) {
+		_, err := h.Handle(context.Background(), request("public", "", ""))
+		assert.ErrorIs(t, err, ErrTokenUnauthorizedClient)
+	})
+
+	t.Run("should reject client without grant type", func(t *testing.T) {
+		_, err := h.Handle(context.Background(), request("web", "secret", ""))
+		assert.ErrorIs(t, err, ErrTokenUnauthorizedClient)
+	})
+
+	t.Run("should reject invalid client secret", func(t *testing.T) {
+		_, err := h.Handle(context.Background(), request("service", "wrong-secret", ""))
+		assert.ErrorIs(t, err, ErrTokenInvalidClient)
+
+		_, err = h.Handle(context.Background(), request("service", "", ""))
+		assert.ErrorIs(t, err, ErrTokenInvalidClient)
+	})
+}
@@ -0,0 +1,247 @@
+package oauth2
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/nazarslota/unotes/auth/api/events"
+	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+)
+
+// Scopes of the note service, which OAuth2 clients can be granted.
+const (
+	ScopeNotesRead  = "notes:read"
+	ScopeNotesWrite = "notes:write"
+)
+
+// firstPartyScope is the scope of the tokens a user gets by signing in directly, which allows everything the user can
+// do.
+const firstPartyScope = ScopeNotesRead + " " + ScopeNotesWrite
+
+// revokedTokenCheckTimeout bounds checking whether an access token is revoked while it is parsed.
+const revokedTokenCheckTimeout = 5 * time.Second
+
+// newTokenPair creates a new access and refresh token pair for the user in the given session, which is new for every
+// sign in, and saves the refresh token, so that every way of signing in ends up issuing tokens in the same way.
+func newTokenPair(
+	ctx context.Context,
+	accessTokenCreator AccessTokenCreator, accessTokenExpiresIn time.Duration,
+	refreshTokenCreator RefreshTokenCreator, refreshTokenExpiresIn time.Duration,
+	refreshTokenSaver RefreshTokenSaver,
+	user domainuser.User, sessionID string,
+) (accessToken string, refreshToken string, err error) {
+	return newClientTokenPair(ctx,
+		accessTokenCreator, accessTokenExpiresIn,
+		refreshTokenCreator, refreshTokenExpiresIn,
+		refreshTokenSaver,
+		user, "", firstPartyScope, sessionID,
+	)
+}
+
+// newClientTokenPair works like newTokenPair, but issues tokens to an OAuth2 client that are limited to the scope
+// granted to it, in the given session.
+func newClientTokenPair(
+	ctx context.Context,
+	accessTokenCreator AccessTokenCreator, accessTokenExpiresIn time.Duration,
+	refreshTokenCreator RefreshTokenCreator, refreshTokenExpiresIn time.Duration,
+	refreshTokenSaver RefreshTokenSaver,
+	user domainuser.User, clientID string, scope string, sessionID string,
+) (accessToken string, refreshToken string, err error) {
+	accessToken, err = newClientAccessToken(accessTokenCreator, accessTokenExpiresIn, user, clientID, scope, sessionID)
+	if err != nil {
+		return "", "", err
+	}
+	refreshToken, err = refreshTokenCreator.New(jwt.RefreshTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: gojwt.NewNumericDate(time.Now().Add(refreshTokenExpiresIn)),
+		},
+		UserID:    user.ID,
+		ClientID:  clientID,
+		Scope:     scope,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	err = refreshTokenSaver.SaveRefreshToken(ctx, user.ID, domainrefresh.Token(refreshToken))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	return accessToken, refreshToken, nil
+}
+
+// newClientAccessToken creates only the access token of newClientTokenPair, for clients that are not allowed to
+// refresh their tokens.
+func newClientAccessToken(
+	accessTokenCreator AccessTokenCreator, accessTokenExpiresIn time.Duration,
+	user domainuser.User, clientID string, scope string, sessionID string,
+) (string, error) {
+	accessToken, err := accessTokenCreator.New(jwt.AccessTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: gojwt.NewNumericDate(time.Now().Add(accessTokenExpiresIn)),
+		},
+		UserID:        user.ID,
+		EmailVerified: user.EmailVerified,
+		Role:          user.Role,
+		ClientID:      clientID,
+		Scope:         scope,
+		SessionID:     sessionID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create access token: %w", err)
+	}
+	return accessToken, nil
+}
+
+// newMFAToken creates a short-lived challenge token for a user with two-factor authentication enabled, which the
+// user exchanges for a token pair by passing the second factor, whichever way they signed in with.
+func newMFAToken(
+	mfaTokenCreator MFATokenCreator, mfaTokenExpiresIn time.Duration,
+	user domainuser.User,
+) (string, error) {
+	mfaToken, err := mfaTokenCreator.New(jwt.MFATokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: gojwt.NewNumericDate(time.Now().Add(mfaTokenExpiresIn)),
+		},
+		UserID: user.ID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create mfa token: %w", err)
+	}
+	return mfaToken, nil
+}
+
+// firstPartyAccessTokenParser rejects access tokens issued to OAuth2 clients, so that a client acting on behalf of a
+// user can't manage the user's account, and access tokens revoked by signing out.
+type firstPartyAccessTokenParser struct {
+	AccessTokenParser   AccessTokenParser
+	RevokedTokenChecker RevokedTokenChecker
+}
+
+// NewFirstPartyAccessTokenParser wraps an access token parser so that it only accepts tokens the user got by signing
+// in directly and that are not revoked. Revocations are not checked if the checker is nil.
+func NewFirstPartyAccessTokenParser(parser AccessTokenParser, checker RevokedTokenChecker) AccessTokenParser {
+	return &firstPartyAccessTokenParser{AccessTokenParser: parser, RevokedTokenChecker: checker}
+}
+
+func (p firstPartyAccessTokenParser) Parse(token string) (jwt.AccessTokenClaims, error) {
+	claims, err := p.AccessTokenParser.Parse(token)
+	if err != nil {
+		return jwt.AccessTokenClaims{}, err
+	} else if len(claims.ClientID) != 0 {
+		return jwt.AccessTokenClaims{}, errors.New("token is issued to a client")
+	}
+
+	if p.RevokedTokenChecker == nil || len(claims.ID) == 0 {
+		return claims, nil
+	}
+
+	// Parse has no context of its own, so the check is bounded by a timeout instead.
+	ctx, cancel := context.WithTimeout(context.Background(), revokedTokenCheckTimeout)
+	defer cancel()
+
+	revoked, err := p.RevokedTokenChecker.IsTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return jwt.AccessTokenClaims{}, fmt.Errorf("failed to check if token is revoked: %w", err)
+	} else if revoked {
+		return jwt.AccessTokenClaims{}, errors.New("token is revoked")
+	}
+	return claims, nil
+}
+
+// newOpaqueToken returns a new random URL-safe token, used where the token is looked up in storage rather than
+// verified by signature, such as password reset tokens.
+func newOpaqueToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// hashOpaqueToken returns the hash of an opaque token under which it is stored, so that a leaked database does not
+// leak usable tokens.
+func hashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// revokeRefreshTokens deletes all refresh tokens of the user except the kept one, which may be empty.
+func revokeRefreshTokens(
+	ctx context.Context,
+	refreshTokenGetter RefreshTokenGetter, refreshTokensDeleter RefreshTokensDeleter,
+	userID string, keep string,
+) error {
+	tokens, err := refreshTokenGetter.GetRefreshTokens(ctx, userID)
+	if errors.Is(err, domainrefresh.ErrTokenNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get refresh tokens: %w", err)
+	}
+
+	revoked := make([]domainrefresh.Token, 0, len(tokens))
+	for _, token := range tokens {
+		if len(keep) == 0 || token != domainrefresh.Token(keep) {
+			revoked = append(revoked, token)
+		}
+	}
+	if len(revoked) == 0 {
+		return nil
+	}
+
+	if err := refreshTokensDeleter.DeleteRefreshTokens(ctx, userID, revoked); err != nil {
+		return fmt.Errorf("failed to delete refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// revokeAccessToken adds the access token to the denylist for the rest of its lifetime and publishes the revocation,
+// so that services validating access tokens themselves reject it right away. Revocations are not published if the
+// publisher is nil. Tokens issued before token IDs were introduced can't be revoked one by one and are left alone.
+func revokeAccessToken(
+	ctx context.Context,
+	revokedTokenSaver RevokedTokenSaver, revokedTokenPublisher RevokedTokenPublisher,
+	claims jwt.AccessTokenClaims,
+) error {
+	if len(claims.ID) == 0 || claims.ExpiresAt == nil {
+		return nil
+	}
+
+	expiresIn := time.Until(claims.ExpiresAt.Time)
+	if expiresIn <= 0 {
+		return nil
+	}
+
+	if err := revokedTokenSaver.SaveRevokedToken(ctx, claims.ID, expiresIn); err != nil {
+		return fmt.Errorf("failed to save revoked token: %w", err)
+	}
+
+	if revokedTokenPublisher == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(events.TokenRevoked{TokenID: claims.ID, ExpiresAt: claims.ExpiresAt.Time.UTC()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal token revoked event: %w", err)
+	}
+
+	if err := revokedTokenPublisher.Publish(ctx, events.SubjectTokenRevoked, payload); err != nil {
+		return fmt.Errorf("failed to publish token revoked event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package oauth2
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domaintotp "github.com/nazarslota/unotes/auth/internal/domain/totp"
+	"github.com/nazarslota/unotes/auth/pkg/totp"
+)
+
+const recoveryCodesCount = 10
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newRecoveryCodes generates a fresh set of recovery codes, returning both the codes to show to the user once and
+// their hashes to store.
+func newRecoveryCodes(userID string) ([]string, []domaintotp.RecoveryCode, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]domaintotp.RecoveryCode, 0, recoveryCodesCount)
+	for i := 0; i < recoveryCodesCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("failed to read random bytes: %w", err)
+		}
+
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+		code = code[:4] + "-" + code[4:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, domaintotp.RecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code)})
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes a recovery code ignoring case, spaces and dashes, so that it can be typed back in any form.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// validateTOTP checks a code against the user's TOTP secret and rejects codes from an already used time step. The
+// matched time step is remembered only if it is still later than the last used one, so the same code can't be
+// replayed, not even by concurrent requests.
+func validateTOTP(ctx context.Context, updater TOTPLastUsedStepUpdater, t domaintotp.TOTP, code string) (bool, error) {
+	step, ok, err := totp.Validate(t.Secret, code, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to validate code: %w", err)
+	} else if !ok || step <= t.LastUsedStep {
+		return false, nil
+	}
+
+	err = updater.UpdateTOTPLastUsedStep(ctx, t.UserID, step)
+	if errors.Is(err, domaintotp.ErrTOTPStepUsed) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to update totp: %w", err)
+	}
+	return true, nil
+}
+
+// verifySecondFactor checks a code against the user's TOTP secret or, failing that, uses it up as one of the user's
+// recovery codes.
+func verifySecondFactor(
+	ctx context.Context,
+	updater TOTPLastUsedStepUpdater, deleter RecoveryCodeDeleter,
+	t domaintotp.TOTP, code string,
+) (bool, error) {
+	if valid, err := validateTOTP(ctx, updater, t, code); err != nil || valid {
+		return valid, err
+	}
+
+	err := deleter.DeleteRecoveryCode(ctx, domaintotp.RecoveryCode{UserID: t.UserID, CodeHash: hashRecoveryCode(code)})
+	if errors.Is(err, domaintotp.ErrRecoveryCodeNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	return true, nil
+}
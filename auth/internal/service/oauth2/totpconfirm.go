@@ -0,0 +1,108 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domaintotp "github.com/nazarslota/unotes/auth/internal/domain/totp"
+	"github.com/nazarslota/unotes/auth/pkg/totp"
+)
+
+type TOTPConfirmRequest struct {
+	AccessToken string
+	Code        string
+}
+
+// TOTPConfirmResponse holds the recovery codes, which are shown to the user only once and stored hashed.
+type TOTPConfirmResponse struct {
+	RecoveryCodes []string
+}
+
+type TOTPConfirmRequestHandler interface {
+	Handle(ctx context.Context, request TOTPConfirmRequest) (TOTPConfirmResponse, error)
+}
+
+type totpConfirmRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	TOTPFinder         TOTPFinder
+	TOTPUpdater        TOTPUpdater
+	RecoveryCodesSaver RecoveryCodesSaver
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrTOTPConfirmInvalidOrExpiredToken = errTOTPConfirmInvalidOrExpiredToken()
+	ErrTOTPConfirmNotEnrolled           = errTOTPConfirmNotEnrolled()
+	ErrTOTPConfirmAlreadyEnabled        = errTOTPConfirmAlreadyEnabled()
+	ErrTOTPConfirmInvalidCode           = errTOTPConfirmInvalidCode()
+)
+
+func errTOTPConfirmInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errTOTPConfirmNotEnrolled() error           { return errors.New("not enrolled") }
+func errTOTPConfirmAlreadyEnabled() error        { return errors.New("already enabled") }
+func errTOTPConfirmInvalidCode() error           { return errors.New("invalid code") }
+
+func NewTOTPConfirmRequestHandler(
+	accessTokenParser AccessTokenParser,
+	totpFinder TOTPFinder, totpUpdater TOTPUpdater, recoveryCodesSaver RecoveryCodesSaver,
+	auditLogger AuditLogger,
+) TOTPConfirmRequestHandler {
+	return &totpConfirmRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		TOTPFinder:         totpFinder,
+		TOTPUpdater:        totpUpdater,
+		RecoveryCodesSaver: recoveryCodesSaver,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h totpConfirmRequestHandler) Handle(ctx context.Context, request TOTPConfirmRequest) (_ TOTPConfirmResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeTOTPConfirm}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return TOTPConfirmResponse{}, errors.Join(err, ErrTOTPConfirmInvalidOrExpiredToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+
+	t, err := h.TOTPFinder.FindTOTPByUserID(ctx, claims.UserID)
+	if errors.Is(err, domaintotp.ErrTOTPNotFound) {
+		err = fmt.Errorf("failed to find totp: %w", err)
+		return TOTPConfirmResponse{}, errors.Join(err, ErrTOTPConfirmNotEnrolled)
+	} else if err != nil {
+		return TOTPConfirmResponse{}, fmt.Errorf("failed to find totp: %w", err)
+	} else if t.Enabled {
+		return TOTPConfirmResponse{}, ErrTOTPConfirmAlreadyEnabled
+	}
+
+	step, ok, err := totp.Validate(t.Secret, request.Code, time.Now())
+	if err != nil {
+		return TOTPConfirmResponse{}, fmt.Errorf("failed to validate code: %w", err)
+	} else if !ok {
+		return TOTPConfirmResponse{}, ErrTOTPConfirmInvalidCode
+	}
+
+	codes, hashes, err := newRecoveryCodes(claims.UserID)
+	if err != nil {
+		return TOTPConfirmResponse{}, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+
+	if err := h.RecoveryCodesSaver.SaveRecoveryCodes(ctx, claims.UserID, hashes); err != nil {
+		return TOTPConfirmResponse{}, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+
+	t.Enabled, t.LastUsedStep = true, step
+	if err := h.TOTPUpdater.UpdateTOTP(ctx, t); err != nil {
+		return TOTPConfirmResponse{}, fmt.Errorf("failed to update totp: %w", err)
+	}
+	return TOTPConfirmResponse{RecoveryCodes: codes}, nil
+}
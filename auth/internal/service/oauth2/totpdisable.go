@@ -0,0 +1,126 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domaintotp "github.com/nazarslota/unotes/auth/internal/domain/totp"
+)
+
+// TOTPDisableRequest disables two-factor authentication with the current TOTP code. IP is the client IP address,
+// wrong codes are counted per user and per IP address like those of SignInMFARequest.
+type TOTPDisableRequest struct {
+	AccessToken string
+	Code        string
+	IP          string
+}
+
+type TOTPDisableResponse struct{}
+
+// TOTPDisableRequestHandler disables two-factor authentication. Besides its own errors it returns a SignInLockedError
+// after too many wrong codes.
+type TOTPDisableRequestHandler interface {
+	Handle(ctx context.Context, request TOTPDisableRequest) (TOTPDisableResponse, error)
+}
+
+type totpDisableRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	TOTPFinder              TOTPFinder
+	TOTPLastUsedStepUpdater TOTPLastUsedStepUpdater
+	TOTPDeleter             TOTPDeleter
+	RecoveryCodesDeleter    RecoveryCodesDeleter
+
+	LockoutPolicy         LockoutPolicy
+	SignInFailureSaver    SignInFailureSaver
+	SignInLockSaver       SignInLockSaver
+	SignInLockFinder      SignInLockFinder
+	SignInFailuresDeleter SignInFailuresDeleter
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrTOTPDisableInvalidOrExpiredToken = errTOTPDisableInvalidOrExpiredToken()
+	ErrTOTPDisableNotEnabled            = errTOTPDisableNotEnabled()
+	ErrTOTPDisableInvalidCode           = errTOTPDisableInvalidCode()
+)
+
+func errTOTPDisableInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errTOTPDisableNotEnabled() error            { return errors.New("not enabled") }
+func errTOTPDisableInvalidCode() error           { return errors.New("invalid code") }
+
+func NewTOTPDisableRequestHandler(
+	accessTokenParser AccessTokenParser,
+	totpFinder TOTPFinder, totpLastUsedStepUpdater TOTPLastUsedStepUpdater,
+	totpDeleter TOTPDeleter, recoveryCodesDeleter RecoveryCodesDeleter,
+	lockoutPolicy LockoutPolicy,
+	signInFailureSaver SignInFailureSaver, signInLockSaver SignInLockSaver,
+	signInLockFinder SignInLockFinder, signInFailuresDeleter SignInFailuresDeleter,
+	auditLogger AuditLogger,
+) TOTPDisableRequestHandler {
+	return &totpDisableRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		TOTPFinder:              totpFinder,
+		TOTPLastUsedStepUpdater: totpLastUsedStepUpdater,
+		TOTPDeleter:             totpDeleter,
+		RecoveryCodesDeleter:    recoveryCodesDeleter,
+
+		LockoutPolicy:         lockoutPolicy,
+		SignInFailureSaver:    signInFailureSaver,
+		SignInLockSaver:       signInLockSaver,
+		SignInLockFinder:      signInLockFinder,
+		SignInFailuresDeleter: signInFailuresDeleter,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h totpDisableRequestHandler) Handle(ctx context.Context, request TOTPDisableRequest) (_ TOTPDisableResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeTOTPDisable}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return TOTPDisableResponse{}, errors.Join(err, ErrTOTPDisableInvalidOrExpiredToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+
+	if err := checkSecondFactorLock(ctx, h.SignInLockFinder, h.LockoutPolicy, claims.UserID, request.IP); err != nil {
+		return TOTPDisableResponse{}, err
+	}
+
+	totp, err := h.TOTPFinder.FindTOTPByUserID(ctx, claims.UserID)
+	if errors.Is(err, domaintotp.ErrTOTPNotFound) {
+		err = fmt.Errorf("failed to find totp: %w", err)
+		return TOTPDisableResponse{}, errors.Join(err, ErrTOTPDisableNotEnabled)
+	} else if err != nil {
+		return TOTPDisableResponse{}, fmt.Errorf("failed to find totp: %w", err)
+	} else if !totp.Enabled {
+		return TOTPDisableResponse{}, ErrTOTPDisableNotEnabled
+	}
+
+	if valid, err := validateTOTP(ctx, h.TOTPLastUsedStepUpdater, totp, request.Code); err != nil {
+		return TOTPDisableResponse{}, err
+	} else if !valid {
+		err := registerSecondFactorFailure(ctx,
+			h.SignInFailureSaver, h.SignInLockSaver, h.LockoutPolicy, claims.UserID, request.IP)
+		return TOTPDisableResponse{}, errors.Join(ErrTOTPDisableInvalidCode, err)
+	}
+
+	if err := h.SignInFailuresDeleter.DeleteSignInFailures(ctx, mfaUserLockoutKey(claims.UserID)); err != nil {
+		return TOTPDisableResponse{}, fmt.Errorf("failed to delete sign in failures: %w", err)
+	}
+
+	if err := h.RecoveryCodesDeleter.DeleteRecoveryCodes(ctx, claims.UserID); err != nil {
+		return TOTPDisableResponse{}, fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	if err := h.TOTPDeleter.DeleteTOTP(ctx, claims.UserID); err != nil {
+		return TOTPDisableResponse{}, fmt.Errorf("failed to delete totp: %w", err)
+	}
+	return TOTPDisableResponse{}, nil
+}
@@ -0,0 +1,100 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domaintotp "github.com/nazarslota/unotes/auth/internal/domain/totp"
+	"github.com/nazarslota/unotes/auth/pkg/totp"
+)
+
+type TOTPEnrollRequest struct {
+	AccessToken string
+}
+
+// TOTPEnrollResponse holds the new secret and the otpauth:// URI to show to the user. Two-factor authentication is
+// not enabled until the enrollment is confirmed with a code through TOTPConfirmRequestHandler.
+type TOTPEnrollResponse struct {
+	Secret string
+	URI    string
+}
+
+type TOTPEnrollRequestHandler interface {
+	Handle(ctx context.Context, request TOTPEnrollRequest) (TOTPEnrollResponse, error)
+}
+
+type totpEnrollRequestHandler struct {
+	AccessTokenParser AccessTokenParser
+
+	Issuer string
+
+	UserFinder UserFinder
+	TOTPFinder TOTPFinder
+	TOTPSaver  TOTPSaver
+
+	AuditLogger AuditLogger
+}
+
+var (
+	ErrTOTPEnrollInvalidOrExpiredToken = errTOTPEnrollInvalidOrExpiredToken()
+	ErrTOTPEnrollAlreadyEnabled        = errTOTPEnrollAlreadyEnabled()
+)
+
+func errTOTPEnrollInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errTOTPEnrollAlreadyEnabled() error        { return errors.New("already enabled") }
+
+func NewTOTPEnrollRequestHandler(
+	accessTokenParser AccessTokenParser,
+	issuer string,
+	userFinder UserFinder, totpFinder TOTPFinder, totpSaver TOTPSaver,
+	auditLogger AuditLogger,
+) TOTPEnrollRequestHandler {
+	return &totpEnrollRequestHandler{
+		AccessTokenParser: accessTokenParser,
+
+		Issuer: issuer,
+
+		UserFinder: userFinder,
+		TOTPFinder: totpFinder,
+		TOTPSaver:  totpSaver,
+
+		AuditLogger: auditLogger,
+	}
+}
+
+func (h totpEnrollRequestHandler) Handle(ctx context.Context, request TOTPEnrollRequest) (_ TOTPEnrollResponse, err error) {
+	event := domainaudit.Event{Type: domainaudit.TypeTOTPEnroll}
+	defer func() { recordAuditEvent(ctx, h.AuditLogger, event, err) }()
+
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return TOTPEnrollResponse{}, errors.Join(err, ErrTOTPEnrollInvalidOrExpiredToken)
+	}
+	event.UserID, event.SessionID = claims.UserID, claims.SessionID
+
+	existing, err := h.TOTPFinder.FindTOTPByUserID(ctx, claims.UserID)
+	if err != nil && !errors.Is(err, domaintotp.ErrTOTPNotFound) {
+		return TOTPEnrollResponse{}, fmt.Errorf("failed to find totp: %w", err)
+	} else if err == nil && existing.Enabled {
+		return TOTPEnrollResponse{}, ErrTOTPEnrollAlreadyEnabled
+	}
+
+	user, err := h.UserFinder.FindUserByUserID(ctx, claims.UserID)
+	if err != nil {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return TOTPEnrollResponse{}, errors.Join(err, ErrTOTPEnrollInvalidOrExpiredToken)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return TOTPEnrollResponse{}, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	if err := h.TOTPSaver.SaveTOTP(ctx, domaintotp.TOTP{UserID: user.ID, Secret: secret}); err != nil {
+		return TOTPEnrollResponse{}, fmt.Errorf("failed to save totp: %w", err)
+	}
+	return TOTPEnrollResponse{Secret: secret, URI: totp.URI(h.Issuer, user.Username, secret)}, nil
+}
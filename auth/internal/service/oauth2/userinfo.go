@@ -0,0 +1,87 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"golang.org/x/exp/slices"
+)
+
+type UserInfoRequest struct {
+	AccessToken string
+}
+
+type UserInfoResponse struct {
+	UserInfo
+}
+
+// UserInfoRequestHandler is the OpenID Connect userinfo endpoint. Tokens issued to a client need the openid scope, and
+// the scope decides which claims are released, the same way as for ID tokens.
+type UserInfoRequestHandler interface {
+	Handle(ctx context.Context, request UserInfoRequest) (UserInfoResponse, error)
+}
+
+type userInfoRequestHandler struct {
+	AccessTokenParser   AccessTokenParser
+	RevokedTokenChecker RevokedTokenChecker
+
+	UserFinder UserFinder
+}
+
+var (
+	ErrUserInfoInvalidOrExpiredToken = errUserInfoInvalidOrExpiredToken()
+	ErrUserInfoInsufficientScope     = errUserInfoInsufficientScope()
+)
+
+func errUserInfoInvalidOrExpiredToken() error { return errors.New("invalid or expired token") }
+func errUserInfoInsufficientScope() error     { return errors.New("insufficient scope") }
+
+func NewUserInfoRequestHandler(
+	accessTokenParser AccessTokenParser, revokedTokenChecker RevokedTokenChecker,
+	userFinder UserFinder,
+) UserInfoRequestHandler {
+	return &userInfoRequestHandler{
+		AccessTokenParser:   accessTokenParser,
+		RevokedTokenChecker: revokedTokenChecker,
+
+		UserFinder: userFinder,
+	}
+}
+
+func (h userInfoRequestHandler) Handle(ctx context.Context, request UserInfoRequest) (UserInfoResponse, error) {
+	claims, err := h.AccessTokenParser.Parse(request.AccessToken)
+	if err != nil {
+		err = fmt.Errorf("failed to parse access token: %w", err)
+		return UserInfoResponse{}, errors.Join(err, ErrUserInfoInvalidOrExpiredToken)
+	}
+
+	if len(claims.ID) != 0 {
+		revoked, err := h.RevokedTokenChecker.IsTokenRevoked(ctx, claims.ID)
+		if err != nil {
+			return UserInfoResponse{}, fmt.Errorf("failed to check if token is revoked: %w", err)
+		} else if revoked {
+			return UserInfoResponse{}, ErrUserInfoInvalidOrExpiredToken
+		}
+	}
+
+	// Tokens the user got by signing in directly are not limited by scope.
+	scope := claims.Scope
+	if len(claims.ClientID) == 0 {
+		scope = strings.Join([]string{ScopeOpenID, ScopeEmail}, " ")
+	} else if !slices.Contains(strings.Fields(scope), ScopeOpenID) {
+		return UserInfoResponse{}, ErrUserInfoInsufficientScope
+	}
+
+	// Service accounts are not users, so their tokens have no user to describe.
+	user, err := h.UserFinder.FindUserByUserID(ctx, claims.UserID)
+	if errors.Is(err, domainuser.ErrUserNotFound) {
+		err = fmt.Errorf("failed to find user: %w", err)
+		return UserInfoResponse{}, errors.Join(err, ErrUserInfoInvalidOrExpiredToken)
+	} else if err != nil {
+		return UserInfoResponse{}, fmt.Errorf("failed to find user: %w", err)
+	}
+	return UserInfoResponse{UserInfo: newUserInfo(user, scope)}, nil
+}
@@ -7,10 +7,73 @@ import (
 )
 
 type OAuth2Service struct {
-	SignUpRequestHandler  oauth2.SignUpRequestHandler
-	SingInRequestHandler  oauth2.SignInRequestHandler
-	RefreshRequestHandler oauth2.RefreshRequestHandler
-	SignOutRequestHandler oauth2.LogOutRequestHandler
+	SignUpRequestHandler    oauth2.SignUpRequestHandler
+	SingInRequestHandler    oauth2.SignInRequestHandler
+	SignInMFARequestHandler oauth2.SignInMFARequestHandler
+	RefreshRequestHandler   oauth2.RefreshRequestHandler
+	SignOutRequestHandler   oauth2.LogOutRequestHandler
+
+	RequestMagicLinkRequestHandler oauth2.RequestMagicLinkRequestHandler
+	ConfirmMagicLinkRequestHandler oauth2.ConfirmMagicLinkRequestHandler
+
+	BeginPasskeySignInRequestHandler  oauth2.BeginPasskeySignInRequestHandler
+	FinishPasskeySignInRequestHandler oauth2.FinishPasskeySignInRequestHandler
+	BeginPasskeyMFARequestHandler     oauth2.BeginPasskeyMFARequestHandler
+	FinishPasskeyMFARequestHandler    oauth2.FinishPasskeyMFARequestHandler
+
+	UnlockSignInRequestHandler oauth2.UnlockSignInRequestHandler
+
+	TOTPEnrollRequestHandler  oauth2.TOTPEnrollRequestHandler
+	TOTPConfirmRequestHandler oauth2.TOTPConfirmRequestHandler
+	TOTPDisableRequestHandler oauth2.TOTPDisableRequestHandler
+
+	BeginPasskeyRegistrationRequestHandler  oauth2.BeginPasskeyRegistrationRequestHandler
+	FinishPasskeyRegistrationRequestHandler oauth2.FinishPasskeyRegistrationRequestHandler
+	ListPasskeysRequestHandler              oauth2.ListPasskeysRequestHandler
+	DeletePasskeyRequestHandler             oauth2.DeletePasskeyRequestHandler
+
+	ChangePasswordRequestHandler       oauth2.ChangePasswordRequestHandler
+	RequestPasswordResetRequestHandler oauth2.RequestPasswordResetRequestHandler
+	ConfirmPasswordResetRequestHandler oauth2.ConfirmPasswordResetRequestHandler
+
+	ChangeEmailRequestHandler        oauth2.ChangeEmailRequestHandler
+	VerifyEmailRequestHandler        oauth2.VerifyEmailRequestHandler
+	ResendVerificationRequestHandler oauth2.ResendVerificationRequestHandler
+
+	AuthorizeRequestHandler      oauth2.AuthorizeRequestHandler
+	ConsentRequestHandler        oauth2.ConsentRequestHandler
+	TokenRequestHandler          oauth2.TokenRequestHandler
+	RegisterClientRequestHandler oauth2.RegisterClientRequestHandler
+	IntrospectRequestHandler     oauth2.IntrospectRequestHandler
+	RevokeRequestHandler         oauth2.RevokeRequestHandler
+
+	DiscoveryRequestHandler oauth2.DiscoveryRequestHandler
+	UserInfoRequestHandler  oauth2.UserInfoRequestHandler
+
+	ExternalAuthorizeRequestHandler oauth2.ExternalAuthorizeRequestHandler
+	ExternalCallbackRequestHandler  oauth2.ExternalCallbackRequestHandler
+
+	GetProfileRequestHandler    oauth2.GetProfileRequestHandler
+	UpdateProfileRequestHandler oauth2.UpdateProfileRequestHandler
+	UploadAvatarRequestHandler  oauth2.UploadAvatarRequestHandler
+	GetAvatarRequestHandler     oauth2.GetAvatarRequestHandler
+	GetUsersRequestHandler      oauth2.GetUsersRequestHandler
+
+	DeleteAccountRequestHandler oauth2.DeleteAccountRequestHandler
+
+	CreatePersonalAccessTokenRequestHandler oauth2.CreatePersonalAccessTokenRequestHandler
+	ListPersonalAccessTokensRequestHandler  oauth2.ListPersonalAccessTokensRequestHandler
+	RevokePersonalAccessTokenRequestHandler oauth2.RevokePersonalAccessTokenRequestHandler
+
+	ListSecurityEventsRequestHandler oauth2.ListSecurityEventsRequestHandler
+	ListAuditEventsRequestHandler    oauth2.ListAuditEventsRequestHandler
+
+	ListUsersRequestHandler          oauth2.ListUsersRequestHandler
+	ListUserSessionsRequestHandler   oauth2.ListUserSessionsRequestHandler
+	SetUserDisabledRequestHandler    oauth2.SetUserDisabledRequestHandler
+	ForcePasswordResetRequestHandler oauth2.ForcePasswordResetRequestHandler
+	RevokeUserSessionsRequestHandler oauth2.RevokeUserSessionsRequestHandler
+	SetUserRoleRequestHandler        oauth2.SetUserRoleRequestHandler
 }
 
 type OAuth2ServiceOptions struct {
@@ -22,23 +85,152 @@ type OAuth2ServiceOptions struct {
 	RefreshTokenParser    oauth2.RefreshTokenParser
 	RefreshTokenExpiresIn time.Duration
 
+	MFATokenCreator   oauth2.MFATokenCreator
+	MFATokenParser    oauth2.MFATokenParser
+	MFATokenExpiresIn time.Duration
+
+	IDTokenCreator oauth2.IDTokenCreator
+	IDTokenKeySet  oauth2.IDTokenKeySet
+	Issuer         string
+
+	TOTPIssuer string
+
+	LockoutPolicy oauth2.LockoutPolicy
+
+	PasswordHasher oauth2.PasswordHasher
+	PasswordPolicy oauth2.PasswordPolicy
+
+	PasswordResetURL            string
+	PasswordResetTokenExpiresIn time.Duration
+
+	MagicLinkURL            string
+	MagicLinkTokenExpiresIn time.Duration
+	MagicLinkRateLimit      oauth2.MagicLinkRateLimit
+
+	EmailVerificationURL            string
+	EmailVerificationTokenExpiresIn time.Duration
+
+	AuthorizationCodeExpiresIn time.Duration
+
+	IdentityProviders      map[string]oauth2.IdentityProvider
+	IdentityStateExpiresIn time.Duration
+
+	PasskeyRelyingParty     oauth2.PasskeyRelyingParty
+	PasskeySessionExpiresIn time.Duration
+
+	AvatarURL     string
+	AvatarMaxSize int64
+
 	RefreshTokenSaver    oauth2.RefreshTokenSaver
 	RefreshTokenDeleter  oauth2.RefreshTokenDeleter
 	RefreshTokensDeleter oauth2.RefreshTokensDeleter
 	RefreshTokenGetter   oauth2.RefreshTokenGetter
 
-	UserSaver  oauth2.UserSaver
-	UserFinder oauth2.UserFinder
+	RevokedTokenSaver     oauth2.RevokedTokenSaver
+	RevokedTokenChecker   oauth2.RevokedTokenChecker
+	RevokedTokenPublisher oauth2.RevokedTokenPublisher
+
+	UserSaver   oauth2.UserSaver
+	UserFinder  oauth2.UserFinder
+	UserUpdater oauth2.UserUpdater
+	UserDeleter oauth2.UserDeleter
+
+	UsersFinder         oauth2.UsersFinder
+	UserRoleUpdater     oauth2.UserRoleUpdater
+	UserDisabledUpdater oauth2.UserDisabledUpdater
+
+	ProfileFinder  oauth2.ProfileFinder
+	ProfilesFinder oauth2.ProfilesFinder
+	ProfileUpdater oauth2.ProfileUpdater
+
+	DeletedUsersFinder oauth2.DeletedUsersFinder
+
+	BlobSaver   oauth2.BlobSaver
+	BlobFinder  oauth2.BlobFinder
+	BlobDeleter oauth2.BlobDeleter
+
+	TOTPSaver               oauth2.TOTPSaver
+	TOTPFinder              oauth2.TOTPFinder
+	TOTPUpdater             oauth2.TOTPUpdater
+	TOTPLastUsedStepUpdater oauth2.TOTPLastUsedStepUpdater
+	TOTPDeleter             oauth2.TOTPDeleter
+	RecoveryCodesSaver      oauth2.RecoveryCodesSaver
+	RecoveryCodeDeleter     oauth2.RecoveryCodeDeleter
+	RecoveryCodesDeleter    oauth2.RecoveryCodesDeleter
+
+	SignInFailureSaver    oauth2.SignInFailureSaver
+	SignInLockSaver       oauth2.SignInLockSaver
+	SignInLockFinder      oauth2.SignInLockFinder
+	SignInFailuresDeleter oauth2.SignInFailuresDeleter
+
+	MagicLinkRequestSaver oauth2.MagicLinkRequestSaver
+
+	PersonalAccessTokenSaver        oauth2.PersonalAccessTokenSaver
+	PersonalAccessTokensFinder      oauth2.PersonalAccessTokensFinder
+	PersonalAccessTokenFinder       oauth2.PersonalAccessTokenFinder
+	PersonalAccessTokenUsageUpdater oauth2.PersonalAccessTokenUsageUpdater
+	PersonalAccessTokenDeleter      oauth2.PersonalAccessTokenDeleter
+
+	PasskeySaver           oauth2.PasskeySaver
+	PasskeysFinder         oauth2.PasskeysFinder
+	PasskeyFinder          oauth2.PasskeyFinder
+	PasskeyUsageUpdater    oauth2.PasskeyUsageUpdater
+	PasskeyDeleter         oauth2.PasskeyDeleter
+	PasskeySessionSaver    oauth2.PasskeySessionSaver
+	PasskeySessionConsumer oauth2.PasskeySessionConsumer
+
+	AuditLogger       oauth2.AuditLogger
+	AuditEventsFinder oauth2.AuditEventsFinder
+
+	PasswordResetTokenSaver    oauth2.PasswordResetTokenSaver
+	PasswordResetTokenFinder   oauth2.PasswordResetTokenFinder
+	PasswordResetTokenConsumer oauth2.PasswordResetTokenConsumer
+	PasswordResetTokensDeleter oauth2.PasswordResetTokensDeleter
+
+	MagicLinkTokenSaver    oauth2.MagicLinkTokenSaver
+	MagicLinkTokenConsumer oauth2.MagicLinkTokenConsumer
+	MagicLinkTokensDeleter oauth2.MagicLinkTokensDeleter
+
+	EmailVerificationTokenSaver    oauth2.EmailVerificationTokenSaver
+	EmailVerificationTokenConsumer oauth2.EmailVerificationTokenConsumer
+	EmailVerificationTokensDeleter oauth2.EmailVerificationTokensDeleter
+
+	ClientSaver               oauth2.ClientSaver
+	ClientFinder              oauth2.ClientFinder
+	AuthorizationCodeSaver    oauth2.AuthorizationCodeSaver
+	AuthorizationCodeConsumer oauth2.AuthorizationCodeConsumer
+
+	IdentitySaver         oauth2.IdentitySaver
+	IdentityFinder        oauth2.IdentityFinder
+	IdentityStateSaver    oauth2.IdentityStateSaver
+	IdentityStateConsumer oauth2.IdentityStateConsumer
+
+	Mailer oauth2.Mailer
 }
 
 func NewOAuth2Service(options OAuth2ServiceOptions) OAuth2Service {
+	// Tokens issued to OAuth2 clients must not be usable to manage the account of the user they act on behalf of, and
+	// tokens revoked by signing out must not be usable at all.
+	accessTokenParser := oauth2.NewFirstPartyAccessTokenParser(options.AccessTokenParser, options.RevokedTokenChecker)
+
 	return OAuth2Service{
 		SignUpRequestHandler: oauth2.NewSignUpRequestHandler(
+			options.EmailVerificationURL,
+			options.EmailVerificationTokenExpiresIn,
+
+			options.PasswordHasher,
+			options.PasswordPolicy,
+
 			options.UserSaver,
+			options.EmailVerificationTokenSaver,
+
+			options.Mailer,
+
+			options.AuditLogger,
 		),
 		RefreshRequestHandler: oauth2.NewRefreshRequestHandler(
 			options.AccessTokenCreator,
-			options.AccessTokenParser,
+			accessTokenParser,
 			options.AccessTokenExpiresIn,
 
 			options.RefreshTokenCreator,
@@ -48,6 +240,10 @@ func NewOAuth2Service(options OAuth2ServiceOptions) OAuth2Service {
 			options.RefreshTokenSaver,
 			options.RefreshTokenDeleter,
 			options.RefreshTokenGetter,
+
+			options.UserFinder,
+
+			options.AuditLogger,
 		),
 		SingInRequestHandler: oauth2.NewSignInRequestHandler(
 			options.AccessTokenCreator,
@@ -56,15 +252,575 @@ func NewOAuth2Service(options OAuth2ServiceOptions) OAuth2Service {
 			options.RefreshTokenCreator,
 			options.RefreshTokenExpiresIn,
 
+			options.MFATokenCreator,
+			options.MFATokenExpiresIn,
+
+			options.PasswordHasher,
+
+			options.RefreshTokenSaver,
+
+			options.UserFinder,
+			options.UserUpdater,
+			options.TOTPFinder,
+
+			options.LockoutPolicy,
+			options.SignInFailureSaver,
+			options.SignInLockSaver,
+			options.SignInLockFinder,
+			options.SignInFailuresDeleter,
+
+			options.AuditLogger,
+		),
+		RequestMagicLinkRequestHandler: oauth2.NewRequestMagicLinkRequestHandler(
+			options.MagicLinkURL,
+			options.MagicLinkTokenExpiresIn,
+			options.MagicLinkRateLimit,
+
+			options.UserFinder,
+			options.MagicLinkTokenSaver,
+			options.MagicLinkRequestSaver,
+
+			options.Mailer,
+
+			options.AuditLogger,
+		),
+		ConfirmMagicLinkRequestHandler: oauth2.NewConfirmMagicLinkRequestHandler(
+			options.AccessTokenCreator,
+			options.AccessTokenExpiresIn,
+
+			options.RefreshTokenCreator,
+			options.RefreshTokenExpiresIn,
+
+			options.MFATokenCreator,
+			options.MFATokenExpiresIn,
+
 			options.RefreshTokenSaver,
 
 			options.UserFinder,
+			options.TOTPFinder,
+			options.MagicLinkTokenConsumer,
+			options.MagicLinkTokensDeleter,
+
+			options.AuditLogger,
+		),
+		SignInMFARequestHandler: oauth2.NewSignInMFARequestHandler(
+			options.AccessTokenCreator,
+			options.AccessTokenExpiresIn,
+
+			options.RefreshTokenCreator,
+			options.RefreshTokenExpiresIn,
+
+			options.MFATokenParser,
+
+			options.RefreshTokenSaver,
+
+			options.UserFinder,
+			options.TOTPFinder,
+			options.TOTPLastUsedStepUpdater,
+			options.RecoveryCodeDeleter,
+
+			options.LockoutPolicy,
+			options.SignInFailureSaver,
+			options.SignInLockSaver,
+			options.SignInLockFinder,
+			options.SignInFailuresDeleter,
+
+			options.AuditLogger,
+		),
+		BeginPasskeySignInRequestHandler: oauth2.NewBeginPasskeySignInRequestHandler(
+			options.PasskeySessionExpiresIn,
+			options.PasskeyRelyingParty,
+
+			options.PasskeySessionSaver,
+		),
+		FinishPasskeySignInRequestHandler: oauth2.NewFinishPasskeySignInRequestHandler(
+			options.AccessTokenCreator,
+			options.AccessTokenExpiresIn,
+
+			options.RefreshTokenCreator,
+			options.RefreshTokenExpiresIn,
+
+			options.PasskeyRelyingParty,
+
+			options.RefreshTokenSaver,
+
+			options.UserFinder,
+			options.PasskeyFinder,
+			options.PasskeysFinder,
+			options.PasskeyUsageUpdater,
+			options.PasskeySessionConsumer,
+
+			options.AuditLogger,
+		),
+		BeginPasskeyMFARequestHandler: oauth2.NewBeginPasskeyMFARequestHandler(
+			options.MFATokenParser,
+
+			options.PasskeySessionExpiresIn,
+			options.PasskeyRelyingParty,
+
+			options.UserFinder,
+			options.PasskeysFinder,
+			options.PasskeySessionSaver,
+		),
+		FinishPasskeyMFARequestHandler: oauth2.NewFinishPasskeyMFARequestHandler(
+			options.AccessTokenCreator,
+			options.AccessTokenExpiresIn,
+
+			options.RefreshTokenCreator,
+			options.RefreshTokenExpiresIn,
+
+			options.MFATokenParser,
+
+			options.PasskeyRelyingParty,
+
+			options.RefreshTokenSaver,
+
+			options.UserFinder,
+			options.PasskeysFinder,
+			options.PasskeyUsageUpdater,
+			options.PasskeySessionConsumer,
+
+			options.AuditLogger,
 		),
 		SignOutRequestHandler: oauth2.NewSignOutRequestHandler(
-			options.AccessTokenParser,
+			accessTokenParser,
 
 			options.RefreshTokensDeleter,
 			options.RefreshTokenGetter,
+
+			options.RevokedTokenSaver,
+			options.RevokedTokenPublisher,
+
+			options.AuditLogger,
+		),
+		UnlockSignInRequestHandler: oauth2.NewUnlockSignInRequestHandler(
+			accessTokenParser,
+
+			options.SignInFailuresDeleter,
+
+			options.AuditLogger,
+		),
+		TOTPEnrollRequestHandler: oauth2.NewTOTPEnrollRequestHandler(
+			accessTokenParser,
+
+			options.TOTPIssuer,
+
+			options.UserFinder,
+			options.TOTPFinder,
+			options.TOTPSaver,
+
+			options.AuditLogger,
+		),
+		TOTPConfirmRequestHandler: oauth2.NewTOTPConfirmRequestHandler(
+			accessTokenParser,
+
+			options.TOTPFinder,
+			options.TOTPUpdater,
+			options.RecoveryCodesSaver,
+
+			options.AuditLogger,
+		),
+		TOTPDisableRequestHandler: oauth2.NewTOTPDisableRequestHandler(
+			accessTokenParser,
+
+			options.TOTPFinder,
+			options.TOTPLastUsedStepUpdater,
+			options.TOTPDeleter,
+			options.RecoveryCodesDeleter,
+
+			options.LockoutPolicy,
+			options.SignInFailureSaver,
+			options.SignInLockSaver,
+			options.SignInLockFinder,
+			options.SignInFailuresDeleter,
+
+			options.AuditLogger,
+		),
+
+		BeginPasskeyRegistrationRequestHandler: oauth2.NewBeginPasskeyRegistrationRequestHandler(
+			accessTokenParser,
+
+			options.PasskeySessionExpiresIn,
+			options.PasskeyRelyingParty,
+
+			options.UserFinder,
+			options.PasskeysFinder,
+			options.PasskeySessionSaver,
+
+			options.AuditLogger,
+		),
+		FinishPasskeyRegistrationRequestHandler: oauth2.NewFinishPasskeyRegistrationRequestHandler(
+			accessTokenParser,
+
+			options.PasskeyRelyingParty,
+
+			options.UserFinder,
+			options.PasskeySaver,
+			options.PasskeySessionConsumer,
+
+			options.AuditLogger,
+		),
+		ListPasskeysRequestHandler: oauth2.NewListPasskeysRequestHandler(
+			accessTokenParser,
+
+			options.PasskeysFinder,
+		),
+		DeletePasskeyRequestHandler: oauth2.NewDeletePasskeyRequestHandler(
+			accessTokenParser,
+
+			options.PasskeyDeleter,
+
+			options.AuditLogger,
+		),
+
+		ChangePasswordRequestHandler: oauth2.NewChangePasswordRequestHandler(
+			accessTokenParser,
+
+			options.PasswordHasher,
+			options.PasswordPolicy,
+
+			options.RefreshTokensDeleter,
+			options.RefreshTokenGetter,
+
+			options.UserFinder,
+			options.UserUpdater,
+
+			options.AuditLogger,
+		),
+		RequestPasswordResetRequestHandler: oauth2.NewRequestPasswordResetRequestHandler(
+			options.PasswordResetURL,
+			options.PasswordResetTokenExpiresIn,
+
+			options.UserFinder,
+			options.PasswordResetTokenSaver,
+
+			options.Mailer,
+
+			options.AuditLogger,
+		),
+		ConfirmPasswordResetRequestHandler: oauth2.NewConfirmPasswordResetRequestHandler(
+			options.PasswordHasher,
+			options.PasswordPolicy,
+
+			options.RefreshTokensDeleter,
+			options.RefreshTokenGetter,
+
+			options.UserFinder,
+			options.UserUpdater,
+			options.PasswordResetTokenFinder,
+			options.PasswordResetTokenConsumer,
+			options.PasswordResetTokensDeleter,
+
+			options.AuditLogger,
+		),
+		ChangeEmailRequestHandler: oauth2.NewChangeEmailRequestHandler(
+			accessTokenParser,
+			options.PasswordHasher,
+
+			options.EmailVerificationURL,
+			options.EmailVerificationTokenExpiresIn,
+
+			options.UserFinder,
+			options.UserUpdater,
+			options.EmailVerificationTokenSaver,
+			options.EmailVerificationTokensDeleter,
+
+			options.Mailer,
+
+			options.AuditLogger,
+		),
+		VerifyEmailRequestHandler: oauth2.NewVerifyEmailRequestHandler(
+			options.UserFinder,
+			options.UserUpdater,
+			options.EmailVerificationTokenConsumer,
+			options.EmailVerificationTokensDeleter,
+
+			options.AuditLogger,
+		),
+		ResendVerificationRequestHandler: oauth2.NewResendVerificationRequestHandler(
+			accessTokenParser,
+
+			options.EmailVerificationURL,
+			options.EmailVerificationTokenExpiresIn,
+
+			options.UserFinder,
+			options.EmailVerificationTokenSaver,
+
+			options.Mailer,
+
+			options.AuditLogger,
+		),
+		AuthorizeRequestHandler: oauth2.NewAuthorizeRequestHandler(
+			options.ClientFinder,
+		),
+		ConsentRequestHandler: oauth2.NewConsentRequestHandler(
+			options.AuthorizationCodeExpiresIn,
+
+			options.LockoutPolicy,
+			options.PasswordHasher,
+
+			options.ClientFinder,
+			options.AuthorizationCodeSaver,
+
+			options.UserFinder,
+			options.TOTPFinder,
+			options.TOTPLastUsedStepUpdater,
+			options.RecoveryCodeDeleter,
+
+			options.SignInFailureSaver,
+			options.SignInLockSaver,
+			options.SignInLockFinder,
+			options.SignInFailuresDeleter,
+
+			options.AuditLogger,
+		),
+		TokenRequestHandler: oauth2.NewTokenRequestHandler(
+			options.AccessTokenCreator,
+			options.AccessTokenExpiresIn,
+
+			options.IDTokenCreator,
+			options.Issuer,
+
+			options.RefreshTokenCreator,
+			options.RefreshTokenParser,
+			options.RefreshTokenExpiresIn,
+
+			options.LockoutPolicy,
+			options.PasswordHasher,
+
+			options.RefreshTokenSaver,
+			options.RefreshTokenDeleter,
+			options.RefreshTokenGetter,
+
+			options.ClientFinder,
+			options.AuthorizationCodeConsumer,
+
+			options.UserFinder,
+			options.TOTPFinder,
+
+			options.SignInFailureSaver,
+			options.SignInLockSaver,
+			options.SignInLockFinder,
+			options.SignInFailuresDeleter,
+
+			options.AuditLogger,
+		),
+		RegisterClientRequestHandler: oauth2.NewRegisterClientRequestHandler(
+			accessTokenParser,
+
+			options.ClientSaver,
+
+			options.AuditLogger,
+		),
+		// Introspection and revocation deal with tokens of clients, so they use the unwrapped parser.
+		IntrospectRequestHandler: oauth2.NewIntrospectRequestHandler(
+			options.AccessTokenParser, options.RefreshTokenParser,
+			options.RefreshTokenGetter, options.RevokedTokenChecker,
+			options.PersonalAccessTokenFinder, options.PersonalAccessTokenUsageUpdater,
+			options.UserFinder,
+			options.ClientFinder,
+		),
+		RevokeRequestHandler: oauth2.NewRevokeRequestHandler(
+			options.AccessTokenParser, options.RefreshTokenParser,
+			options.RefreshTokenDeleter,
+			options.RevokedTokenSaver, options.RevokedTokenPublisher,
+			options.ClientFinder,
+
+			options.AuditLogger,
+		),
+
+		DiscoveryRequestHandler: oauth2.NewDiscoveryRequestHandler(options.Issuer, options.IDTokenKeySet),
+		// The userinfo endpoint is meant for clients, so it uses the unwrapped parser as well.
+		UserInfoRequestHandler: oauth2.NewUserInfoRequestHandler(
+			options.AccessTokenParser, options.RevokedTokenChecker,
+			options.UserFinder,
+		),
+
+		ExternalAuthorizeRequestHandler: oauth2.NewExternalAuthorizeRequestHandler(
+			options.IdentityProviders,
+			options.IdentityStateExpiresIn,
+
+			accessTokenParser,
+			options.IdentityStateSaver,
+
+			options.AuditLogger,
+		),
+		ExternalCallbackRequestHandler: oauth2.NewExternalCallbackRequestHandler(
+			options.AccessTokenCreator,
+			options.AccessTokenExpiresIn,
+
+			options.RefreshTokenCreator,
+			options.RefreshTokenExpiresIn,
+
+			options.MFATokenCreator,
+			options.MFATokenExpiresIn,
+
+			options.IdentityProviders,
+
+			options.PasswordHasher,
+
+			options.RefreshTokenSaver,
+
+			options.UserSaver,
+			options.UserFinder,
+			options.TOTPFinder,
+
+			options.IdentitySaver,
+			options.IdentityFinder,
+			options.IdentityStateConsumer,
+
+			options.AuditLogger,
+		),
+
+		GetProfileRequestHandler: oauth2.NewGetProfileRequestHandler(
+			accessTokenParser,
+
+			options.AvatarURL,
+
+			options.ProfileFinder,
+		),
+		UpdateProfileRequestHandler: oauth2.NewUpdateProfileRequestHandler(
+			accessTokenParser,
+
+			options.AvatarURL,
+
+			options.ProfileFinder,
+			options.ProfileUpdater,
+
+			options.AuditLogger,
+		),
+		UploadAvatarRequestHandler: oauth2.NewUploadAvatarRequestHandler(
+			accessTokenParser,
+
+			options.AvatarURL,
+			options.AvatarMaxSize,
+
+			options.ProfileFinder,
+			options.ProfileUpdater,
+
+			options.BlobSaver,
+			options.BlobDeleter,
+
+			options.AuditLogger,
+		),
+		GetAvatarRequestHandler: oauth2.NewGetAvatarRequestHandler(options.BlobFinder),
+		// Other services look users up with the credentials of their client rather than a token of a user.
+		GetUsersRequestHandler: oauth2.NewGetUsersRequestHandler(
+			options.AvatarURL,
+
+			options.ClientFinder,
+			options.ProfilesFinder,
+			options.DeletedUsersFinder,
+		),
+
+		DeleteAccountRequestHandler: oauth2.NewDeleteAccountRequestHandler(
+			accessTokenParser,
+
+			options.PasswordHasher,
+
+			options.RefreshTokensDeleter,
+			options.RefreshTokenGetter,
+			options.RevokedTokenSaver,
+			options.RevokedTokenPublisher,
+
+			options.UserFinder,
+			options.UserDeleter,
+			options.ProfileFinder,
+			options.BlobDeleter,
+
+			options.AuditLogger,
+		),
+
+		CreatePersonalAccessTokenRequestHandler: oauth2.NewCreatePersonalAccessTokenRequestHandler(
+			accessTokenParser,
+
+			options.PersonalAccessTokenSaver,
+
+			options.AuditLogger,
+		),
+		ListPersonalAccessTokensRequestHandler: oauth2.NewListPersonalAccessTokensRequestHandler(
+			accessTokenParser,
+
+			options.PersonalAccessTokensFinder,
+		),
+		RevokePersonalAccessTokenRequestHandler: oauth2.NewRevokePersonalAccessTokenRequestHandler(
+			accessTokenParser,
+
+			options.PersonalAccessTokenDeleter,
+
+			options.AuditLogger,
+		),
+		ListSecurityEventsRequestHandler: oauth2.NewListSecurityEventsRequestHandler(
+			accessTokenParser,
+
+			options.AuditEventsFinder,
+		),
+		ListAuditEventsRequestHandler: oauth2.NewListAuditEventsRequestHandler(
+			accessTokenParser,
+
+			options.AuditEventsFinder,
+		),
+
+		ListUsersRequestHandler: oauth2.NewListUsersRequestHandler(
+			accessTokenParser,
+
+			options.UsersFinder,
+		),
+		ListUserSessionsRequestHandler: oauth2.NewListUserSessionsRequestHandler(
+			accessTokenParser,
+			options.RefreshTokenParser,
+
+			options.RefreshTokenGetter,
+
+			options.UserFinder,
+		),
+		SetUserDisabledRequestHandler: oauth2.NewSetUserDisabledRequestHandler(
+			accessTokenParser,
+
+			options.RefreshTokensDeleter,
+			options.RefreshTokenGetter,
+
+			options.UserFinder,
+			options.UserDisabledUpdater,
+
+			options.AuditLogger,
+		),
+		ForcePasswordResetRequestHandler: oauth2.NewForcePasswordResetRequestHandler(
+			options.PasswordResetURL,
+			options.PasswordResetTokenExpiresIn,
+
+			accessTokenParser,
+
+			options.PasswordHasher,
+
+			options.RefreshTokensDeleter,
+			options.RefreshTokenGetter,
+
+			options.UserFinder,
+			options.UserUpdater,
+			options.PasswordResetTokenSaver,
+
+			options.Mailer,
+
+			options.AuditLogger,
+		),
+		RevokeUserSessionsRequestHandler: oauth2.NewRevokeUserSessionsRequestHandler(
+			accessTokenParser,
+
+			options.RefreshTokensDeleter,
+			options.RefreshTokenGetter,
+
+			options.UserFinder,
+
+			options.AuditLogger,
+		),
+		SetUserRoleRequestHandler: oauth2.NewSetUserRoleRequestHandler(
+			accessTokenParser,
+
+			options.UserFinder,
+			options.UserRoleUpdater,
+
+			options.AuditLogger,
 		),
 	}
 }
@@ -0,0 +1,106 @@
+// Package filesystem implements repositories that keep their data in files in a local directory.
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	domain "github.com/nazarslota/unotes/auth/internal/domain/blob"
+)
+
+// BlobRepository provides an implementation of the blob repository that keeps every blob in a file named after its
+// key in a directory. The content type is not stored but detected from the data when the blob is read.
+type BlobRepository struct {
+	dir string
+}
+
+// NewBlobRepository creates a new instance of the BlobRepository that keeps blobs in the provided directory, creating
+// it if it does not exist.
+//
+// If dir is empty or cannot be created, returns an error.
+func NewBlobRepository(dir string) (*BlobRepository, error) {
+	if len(dir) == 0 {
+		return nil, fmt.Errorf("dir is empty")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create dir: %w", err)
+	}
+	return &BlobRepository{dir: dir}, nil
+}
+
+// validKey matches the keys blobs can be stored under, which keeps them from naming files outside the directory.
+var validKey = regexp.MustCompile(`^[A-Za-z0-9_-]{1,255}$`)
+
+// SaveBlob saves a blob under the key, replacing the blob already saved under it. The file is written under a
+// temporary name first, so a blob that is read while being saved is never incomplete.
+func (r BlobRepository) SaveBlob(ctx context.Context, key string, blob domain.Blob) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	} else if !validKey.MatchString(key) {
+		return fmt.Errorf("invalid key %q", key)
+	}
+
+	file, err := os.CreateTemp(r.dir, ".blob-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	if _, err := file.Write(blob.Data); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	} else if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), filepath.Join(r.dir, key)); err != nil {
+		return fmt.Errorf("failed to rename file: %w", err)
+	}
+	return nil
+}
+
+// FindBlob finds the blob saved under the key.
+//
+// If the blob is not found, returns `blob.ErrBlobNotFound`.
+func (r BlobRepository) FindBlob(ctx context.Context, key string) (domain.Blob, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.Blob{}, err
+	} else if !validKey.MatchString(key) {
+		return domain.Blob{}, domain.ErrBlobNotFound
+	}
+
+	data, err := os.ReadFile(filepath.Join(r.dir, key))
+	if errors.Is(err, fs.ErrNotExist) {
+		err = fmt.Errorf("failed to read file: %w", err)
+		return domain.Blob{}, errors.Join(err, domain.ErrBlobNotFound)
+	} else if err != nil {
+		return domain.Blob{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	return domain.Blob{ContentType: http.DetectContentType(data), Data: data}, nil
+}
+
+// DeleteBlob deletes the blob saved under the key.
+//
+// If the blob is not found, returns `blob.ErrBlobNotFound`.
+func (r BlobRepository) DeleteBlob(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	} else if !validKey.MatchString(key) {
+		return domain.ErrBlobNotFound
+	}
+
+	err := os.Remove(filepath.Join(r.dir, key))
+	if errors.Is(err, fs.ErrNotExist) {
+		err = fmt.Errorf("failed to remove file: %w", err)
+		return errors.Join(err, domain.ErrBlobNotFound)
+	} else if err != nil {
+		return fmt.Errorf("failed to remove file: %w", err)
+	}
+	return nil
+}
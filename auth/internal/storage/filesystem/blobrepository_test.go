@@ -0,0 +1,66 @@
+package filesystem
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/nazarslota/unotes/auth/internal/domain/blob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// png is the signature of a PNG image, which is enough for its content type to be detected.
+var png = []byte("\x89PNG\r\n\x1a\n")
+
+func TestNewBlobRepository(t *testing.T) {
+	t.Run("should create directory", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "blobs")
+		repository, err := NewBlobRepository(dir)
+		assert.NoError(t, err)
+		assert.NotNil(t, repository)
+		assert.DirExists(t, dir)
+	})
+
+	t.Run("should return error when dir is empty", func(t *testing.T) {
+		repository, err := NewBlobRepository("")
+		assert.EqualError(t, err, "dir is empty")
+		assert.Nil(t, repository)
+	})
+}
+
+func TestBlobRepository(t *testing.T) {
+	repository, err := NewBlobRepository(t.TempDir())
+	require.NoError(t, err)
+
+	t.Run("should save, find and delete blob", func(t *testing.T) {
+		err := repository.SaveBlob(context.Background(), "key", blob.Blob{ContentType: "image/png", Data: png})
+		require.NoError(t, err)
+
+		result, err := repository.FindBlob(context.Background(), "key")
+		require.NoError(t, err)
+		assert.Equal(t, blob.Blob{ContentType: "image/png", Data: png}, result)
+
+		err = repository.DeleteBlob(context.Background(), "key")
+		require.NoError(t, err)
+
+		_, err = repository.FindBlob(context.Background(), "key")
+		assert.ErrorIs(t, err, blob.ErrBlobNotFound)
+	})
+
+	t.Run("should not accept keys outside of directory", func(t *testing.T) {
+		err := repository.SaveBlob(context.Background(), "../key", blob.Blob{Data: png})
+		assert.Error(t, err)
+
+		_, err = repository.FindBlob(context.Background(), "../key")
+		assert.ErrorIs(t, err, blob.ErrBlobNotFound)
+	})
+
+	t.Run("should return error if context is invalid", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := repository.FindBlob(ctx, "key")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
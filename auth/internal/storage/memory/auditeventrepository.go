@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	domain "github.com/nazarslota/unotes/auth/internal/domain/audit"
+)
+
+// AuditEventRepository provides an implementation of the append-only repository of the events of the audit log for
+// an in-memory database.
+type AuditEventRepository struct {
+	db *DB
+}
+
+// NewAuditEventRepository creates a new instance of the AuditEventRepository with the provided in-memory database.
+//
+// If db is nil, returns an error.
+func NewAuditEventRepository(db *DB) (*AuditEventRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &AuditEventRepository{db: db}, nil
+}
+
+// SaveAuditEvent appends an event to the audit log in the in-memory database.
+func (r AuditEventRepository) SaveAuditEvent(ctx context.Context, event domain.Event) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.db.auditEvents = append(r.db.auditEvents, event)
+	return nil
+}
+
+// FindAuditEvents finds the events selected by the filter in the in-memory database, newest first.
+func (r AuditEventRepository) FindAuditEvents(ctx context.Context, filter domain.Filter) ([]domain.Event, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	events := make([]domain.Event, 0)
+	for _, event := range r.db.auditEvents {
+		if matchAuditEvent(event, filter) {
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].CreatedAt.After(events[j].CreatedAt)
+		}
+		return events[i].ID < events[j].ID
+	})
+
+	if filter.Limit > 0 && len(events) > filter.Limit {
+		events = events[:filter.Limit]
+	}
+	return events, nil
+}
+
+// DeleteAuditEventsBefore deletes the events created before the given time from the in-memory database and returns
+// the number of deleted events.
+func (r AuditEventRepository) DeleteAuditEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	kept := r.db.auditEvents[:0]
+	for _, event := range r.db.auditEvents {
+		if !event.CreatedAt.Before(before) {
+			kept = append(kept, event)
+		}
+	}
+
+	deleted := int64(len(r.db.auditEvents) - len(kept))
+	r.db.auditEvents = kept
+	return deleted, nil
+}
+
+// matchAuditEvent reports whether the event is selected by the filter.
+func matchAuditEvent(event domain.Event, filter domain.Filter) bool {
+	switch {
+	case len(filter.UserID) != 0 && event.UserID != filter.UserID:
+		return false
+	case len(filter.Type) != 0 && event.Type != filter.Type:
+		return false
+	case len(filter.Outcome) != 0 && event.Outcome != filter.Outcome:
+		return false
+	case len(filter.IP) != 0 && event.IP != filter.IP:
+		return false
+	case !filter.Since.IsZero() && event.CreatedAt.Before(filter.Since):
+		return false
+	case !filter.Until.IsZero() && !event.CreatedAt.Before(filter.Until):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "github.com/nazarslota/unotes/auth/internal/domain/authorizationcode"
+)
+
+// AuthorizationCodeRepository provides an implementation of the OAuth2 authorization code repository for an
+// in-memory database. Only hashes of the codes are stored.
+type AuthorizationCodeRepository struct {
+	db *DB
+}
+
+// NewAuthorizationCodeRepository creates a new instance of the AuthorizationCodeRepository with the provided
+// in-memory database.
+//
+// If db is nil, returns an error.
+func NewAuthorizationCodeRepository(db *DB) (*AuthorizationCodeRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &AuthorizationCodeRepository{db: db}, nil
+}
+
+// SaveAuthorizationCode saves an authorization code to the in-memory database, pruning expired codes along the way.
+func (r AuthorizationCodeRepository) SaveAuthorizationCode(ctx context.Context, code domain.Code) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	now := time.Now()
+	for codeHash, saved := range r.db.authorizationCodes {
+		if !saved.ExpiresAt.After(now) {
+			delete(r.db.authorizationCodes, codeHash)
+		}
+	}
+
+	r.db.authorizationCodes[code.CodeHash] = code
+	return nil
+}
+
+// ConsumeAuthorizationCode deletes an authorization code that has not expired yet and returns it, so every code can
+// be exchanged only once.
+//
+// If the code is not found or has expired, returns `authorizationcode.ErrCodeNotFound`.
+func (r AuthorizationCodeRepository) ConsumeAuthorizationCode(ctx context.Context, codeHash string) (domain.Code, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.Code{}, err
+	}
+	defer unlock()
+
+	code, ok := r.db.authorizationCodes[codeHash]
+	if !ok || !code.ExpiresAt.After(time.Now()) {
+		return domain.Code{}, domain.ErrCodeNotFound
+	}
+	delete(r.db.authorizationCodes, codeHash)
+	return code, nil
+}
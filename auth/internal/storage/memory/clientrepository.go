@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	domain "github.com/nazarslota/unotes/auth/internal/domain/client"
+	"golang.org/x/exp/slices"
+)
+
+// ClientRepository provides an implementation of the OAuth2 client repository for an in-memory database. Only hashes
+// of the client secrets are stored.
+type ClientRepository struct {
+	db *DB
+}
+
+// NewClientRepository creates a new instance of the ClientRepository with the provided in-memory database.
+//
+// If db is nil, returns an error.
+func NewClientRepository(db *DB) (*ClientRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &ClientRepository{db: db}, nil
+}
+
+// SaveClient saves a client to the in-memory database.
+//
+// If a client with the same ID already exists, returns `client.ErrClientAlreadyExists`.
+func (r ClientRepository) SaveClient(ctx context.Context, client domain.Client) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, ok := r.db.clients[client.ID]; ok {
+		return domain.ErrClientAlreadyExists
+	}
+	r.db.clients[client.ID] = cloneClient(client)
+	return nil
+}
+
+// FindClientByClientID finds a client in the in-memory database.
+//
+// If the client is not found, returns `client.ErrClientNotFound`.
+func (r ClientRepository) FindClientByClientID(ctx context.Context, clientID string) (domain.Client, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.Client{}, err
+	}
+	defer unlock()
+
+	client, ok := r.db.clients[clientID]
+	if !ok {
+		return domain.Client{}, domain.ErrClientNotFound
+	}
+	return cloneClient(client), nil
+}
+
+// cloneClient returns a copy of client that shares no slices with it, so that callers can't change a saved client.
+func cloneClient(client domain.Client) domain.Client {
+	client.RedirectURIs = slices.Clone(client.RedirectURIs)
+	client.Scopes = slices.Clone(client.Scopes)
+	client.GrantTypes = slices.Clone(client.GrantTypes)
+	return client
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "github.com/nazarslota/unotes/auth/internal/domain/emailverification"
+)
+
+// EmailVerificationTokenRepository provides an implementation of the email verification token repository for an
+// in-memory database. Only hashes of the tokens are stored.
+type EmailVerificationTokenRepository struct {
+	db *DB
+}
+
+// NewEmailVerificationTokenRepository creates a new instance of the EmailVerificationTokenRepository with the provided
+// in-memory database.
+//
+// If db is nil, returns an error.
+func NewEmailVerificationTokenRepository(db *DB) (*EmailVerificationTokenRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &EmailVerificationTokenRepository{db: db}, nil
+}
+
+// SaveEmailVerificationToken saves an email verification token to the in-memory database.
+func (r EmailVerificationTokenRepository) SaveEmailVerificationToken(ctx context.Context, token domain.Token) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.db.emailVerificationTokens[token.TokenHash] = token
+	return nil
+}
+
+// ConsumeEmailVerificationToken deletes an email verification token that has not expired yet and returns it, so
+// every token can be used only once.
+//
+// If the token is not found or has expired, returns `emailverification.ErrTokenNotFound`.
+func (r EmailVerificationTokenRepository) ConsumeEmailVerificationToken(ctx context.Context, tokenHash string) (domain.Token, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.Token{}, err
+	}
+	defer unlock()
+
+	token, ok := r.db.emailVerificationTokens[tokenHash]
+	if !ok || !token.ExpiresAt.After(time.Now()) {
+		return domain.Token{}, domain.ErrTokenNotFound
+	}
+	delete(r.db.emailVerificationTokens, tokenHash)
+	return token, nil
+}
+
+// DeleteEmailVerificationTokens deletes all email verification tokens of a user, pruning expired tokens of every user
+// along the way.
+func (r EmailVerificationTokenRepository) DeleteEmailVerificationTokens(ctx context.Context, userID string) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	now := time.Now()
+	for tokenHash, token := range r.db.emailVerificationTokens {
+		if token.UserID == userID || !token.ExpiresAt.After(now) {
+			delete(r.db.emailVerificationTokens, tokenHash)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "github.com/nazarslota/unotes/auth/internal/domain/identity"
+)
+
+// identityKey is the primary key of an identity, the provider and the subject at the provider.
+type identityKey struct {
+	provider string
+	subject  string
+}
+
+// IdentityRepository provides an implementation of the repository of identities at external identity providers
+// linked to users, and of the sign ins with them in progress, for an in-memory database.
+type IdentityRepository struct {
+	db *DB
+}
+
+// NewIdentityRepository creates a new instance of the IdentityRepository with the provided in-memory database.
+//
+// If db is nil, returns an error.
+func NewIdentityRepository(db *DB) (*IdentityRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &IdentityRepository{db: db}, nil
+}
+
+// SaveIdentity links an identity to a user in the in-memory database.
+//
+// If the identity is already linked, or the user already has an identity at the provider, returns
+// `identity.ErrIdentityAlreadyExists`.
+func (r IdentityRepository) SaveIdentity(ctx context.Context, identity domain.Identity) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for key, saved := range r.db.identities {
+		if key == (identityKey{identity.Provider, identity.Subject}) {
+			return domain.ErrIdentityAlreadyExists
+		} else if saved.Provider == identity.Provider && saved.UserID == identity.UserID {
+			return domain.ErrIdentityAlreadyExists
+		}
+	}
+
+	r.db.identities[identityKey{identity.Provider, identity.Subject}] = identity
+	return nil
+}
+
+// FindIdentity finds an identity in the in-memory database by the provider and the subject at the provider.
+//
+// If the identity is not found, returns `identity.ErrIdentityNotFound`.
+func (r IdentityRepository) FindIdentity(ctx context.Context, provider, subject string) (domain.Identity, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.Identity{}, err
+	}
+	defer unlock()
+
+	identity, ok := r.db.identities[identityKey{provider, subject}]
+	if !ok {
+		return domain.Identity{}, domain.ErrIdentityNotFound
+	}
+	return identity, nil
+}
+
+// SaveIdentityState saves a sign in with an external identity provider in progress to the in-memory database,
+// pruning expired ones along the way.
+func (r IdentityRepository) SaveIdentityState(ctx context.Context, state domain.State) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	now := time.Now()
+	for stateHash, saved := range r.db.identityStates {
+		if !saved.ExpiresAt.After(now) {
+			delete(r.db.identityStates, stateHash)
+		}
+	}
+
+	r.db.identityStates[state.StateHash] = state
+	return nil
+}
+
+// ConsumeIdentityState deletes a sign in with an external identity provider that has not expired yet and returns it,
+// so every state can be used only once.
+//
+// If the state is not found or has expired, returns `identity.ErrStateNotFound`.
+func (r IdentityRepository) ConsumeIdentityState(ctx context.Context, stateHash string) (domain.State, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.State{}, err
+	}
+	defer unlock()
+
+	state, ok := r.db.identityStates[stateHash]
+	if !ok || !state.ExpiresAt.After(time.Now()) {
+		return domain.State{}, domain.ErrStateNotFound
+	}
+	delete(r.db.identityStates, stateHash)
+	return state, nil
+}
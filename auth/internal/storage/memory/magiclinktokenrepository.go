@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "github.com/nazarslota/unotes/auth/internal/domain/magiclink"
+)
+
+// MagicLinkTokenRepository provides an implementation of the magic link token repository for an in-memory
+// database. Only hashes of the tokens are stored.
+type MagicLinkTokenRepository struct {
+	db *DB
+}
+
+// NewMagicLinkTokenRepository creates a new instance of the MagicLinkTokenRepository with the provided
+// in-memory database.
+//
+// If db is nil, returns an error.
+func NewMagicLinkTokenRepository(db *DB) (*MagicLinkTokenRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &MagicLinkTokenRepository{db: db}, nil
+}
+
+// SaveMagicLinkToken saves a magic link token to the in-memory database.
+func (r MagicLinkTokenRepository) SaveMagicLinkToken(ctx context.Context, token domain.Token) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.db.magicLinkTokens[token.TokenHash] = token
+	return nil
+}
+
+// ConsumeMagicLinkToken deletes a magic link token that has not expired yet and returns it, so every token
+// can be used only once.
+//
+// If the token is not found or has expired, returns `magiclink.ErrTokenNotFound`.
+func (r MagicLinkTokenRepository) ConsumeMagicLinkToken(ctx context.Context, tokenHash string) (domain.Token, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.Token{}, err
+	}
+	defer unlock()
+
+	token, ok := r.db.magicLinkTokens[tokenHash]
+	if !ok || !token.ExpiresAt.After(time.Now()) {
+		return domain.Token{}, domain.ErrTokenNotFound
+	}
+	delete(r.db.magicLinkTokens, tokenHash)
+	return token, nil
+}
+
+// DeleteMagicLinkTokens deletes all magic link tokens of a user, pruning expired tokens of every user along
+// the way.
+func (r MagicLinkTokenRepository) DeleteMagicLinkTokens(ctx context.Context, userID string) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	now := time.Now()
+	for tokenHash, token := range r.db.magicLinkTokens {
+		if token.UserID == userID || !token.ExpiresAt.After(now) {
+			delete(r.db.magicLinkTokens, tokenHash)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+// Package memory provides in-memory implementations of the repositories, so that the service can run without any
+// database, for local development and tests. Nothing is persisted, everything is lost when the process exits.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	domainaudit "github.com/nazarslota/unotes/auth/internal/domain/audit"
+	domainauthorizationcode "github.com/nazarslota/unotes/auth/internal/domain/authorizationcode"
+	domainclient "github.com/nazarslota/unotes/auth/internal/domain/client"
+	domainemailverification "github.com/nazarslota/unotes/auth/internal/domain/emailverification"
+	domainidentity "github.com/nazarslota/unotes/auth/internal/domain/identity"
+	domainmagiclink "github.com/nazarslota/unotes/auth/internal/domain/magiclink"
+	domainoutbox "github.com/nazarslota/unotes/auth/internal/domain/outbox"
+	domainpasskey "github.com/nazarslota/unotes/auth/internal/domain/passkey"
+	domainpasswordreset "github.com/nazarslota/unotes/auth/internal/domain/passwordreset"
+	domainpersonalaccesstoken "github.com/nazarslota/unotes/auth/internal/domain/personalaccesstoken"
+	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	domaintotp "github.com/nazarslota/unotes/auth/internal/domain/totp"
+)
+
+// DB is an in-memory database shared by the repositories of this package, the counterpart of a database handle. All
+// repositories lock the whole database, so a change that spans several tables, such as deleting a user together with
+// everything that belongs to them, is atomic.
+type DB struct {
+	mu sync.Mutex
+
+	users                   map[string]userRecord
+	deletedUsers            map[string]time.Time
+	refreshTokens           map[string]map[domainrefresh.Token]struct{}
+	totps                   map[string]domaintotp.TOTP
+	recoveryCodes           map[string]map[string]struct{}
+	passwordResetTokens     map[string]domainpasswordreset.Token
+	magicLinkTokens         map[string]domainmagiclink.Token
+	emailVerificationTokens map[string]domainemailverification.Token
+	clients                 map[string]domainclient.Client
+	authorizationCodes      map[string]domainauthorizationcode.Code
+	identities              map[identityKey]domainidentity.Identity
+	identityStates          map[string]domainidentity.State
+	outboxMessages          map[string]domainoutbox.Message
+	personalAccessTokens    map[string]domainpersonalaccesstoken.Token
+	passkeys                map[string]domainpasskey.Credential
+	passkeySessions         map[string]domainpasskey.Session
+	auditEvents             []domainaudit.Event
+	revokedTokens           map[string]time.Time
+	signInFailures          map[string]requests
+	signInLocks             map[string]time.Time
+	magicLinkRequests       map[string]requests
+}
+
+// NewDB creates a new empty DB.
+func NewDB() *DB {
+	return &DB{
+		users:                   make(map[string]userRecord),
+		deletedUsers:            make(map[string]time.Time),
+		refreshTokens:           make(map[string]map[domainrefresh.Token]struct{}),
+		totps:                   make(map[string]domaintotp.TOTP),
+		recoveryCodes:           make(map[string]map[string]struct{}),
+		passwordResetTokens:     make(map[string]domainpasswordreset.Token),
+		magicLinkTokens:         make(map[string]domainmagiclink.Token),
+		emailVerificationTokens: make(map[string]domainemailverification.Token),
+		clients:                 make(map[string]domainclient.Client),
+		authorizationCodes:      make(map[string]domainauthorizationcode.Code),
+		identities:              make(map[identityKey]domainidentity.Identity),
+		identityStates:          make(map[string]domainidentity.State),
+		outboxMessages:          make(map[string]domainoutbox.Message),
+		personalAccessTokens:    make(map[string]domainpersonalaccesstoken.Token),
+		passkeys:                make(map[string]domainpasskey.Credential),
+		passkeySessions:         make(map[string]domainpasskey.Session),
+		revokedTokens:           make(map[string]time.Time),
+		signInFailures:          make(map[string]requests),
+		signInLocks:             make(map[string]time.Time),
+		magicLinkRequests:       make(map[string]requests),
+	}
+}
+
+// lock locks the database, unless ctx is already done, like a database driver refuses to run a query with a done
+// context. The caller must call the returned function to unlock it.
+func (db *DB) lock(ctx context.Context) (func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to lock db: %w", err)
+	}
+
+	db.mu.Lock()
+	return db.mu.Unlock, nil
+}
+
+// deleteUserData deletes everything that belongs to a user, like the foreign keys of the PostgreSQL schema cascade
+// the deletion of the user. The caller must hold the lock.
+func (db *DB) deleteUserData(userID string) {
+	delete(db.totps, userID)
+	delete(db.recoveryCodes, userID)
+	for tokenHash, token := range db.passwordResetTokens {
+		if token.UserID == userID {
+			delete(db.passwordResetTokens, tokenHash)
+		}
+	}
+	for tokenHash, token := range db.magicLinkTokens {
+		if token.UserID == userID {
+			delete(db.magicLinkTokens, tokenHash)
+		}
+	}
+	for tokenHash, token := range db.emailVerificationTokens {
+		if token.UserID == userID {
+			delete(db.emailVerificationTokens, tokenHash)
+		}
+	}
+	for codeHash, code := range db.authorizationCodes {
+		if code.UserID == userID {
+			delete(db.authorizationCodes, codeHash)
+		}
+	}
+	for key, identity := range db.identities {
+		if identity.UserID == userID {
+			delete(db.identities, key)
+		}
+	}
+	for stateHash, state := range db.identityStates {
+		if state.UserID == userID {
+			delete(db.identityStates, stateHash)
+		}
+	}
+	for tokenID, token := range db.personalAccessTokens {
+		if token.UserID == userID {
+			delete(db.personalAccessTokens, tokenID)
+		}
+	}
+	for credentialID, credential := range db.passkeys {
+		if credential.UserID == userID {
+			delete(db.passkeys, credentialID)
+		}
+	}
+	for sessionHash, session := range db.passkeySessions {
+		if session.UserID == userID {
+			delete(db.passkeySessions, sessionHash)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/nazarslota/unotes/auth/internal/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository(t *testing.T) {
+	repository, err := NewUserRepository(NewDB())
+	require.NoError(t, err)
+
+	storagetest.TestUserRepository(t, repository)
+}
+
+func TestRefreshTokenRepository(t *testing.T) {
+	repository, err := NewRefreshTokenRepository(NewDB())
+	require.NoError(t, err)
+
+	storagetest.TestRefreshTokenRepository(t, repository)
+}
+
+func TestNewRepositories(t *testing.T) {
+	t.Run("should return error when db is nil", func(t *testing.T) {
+		_, err := NewUserRepository(nil)
+		assert.EqualError(t, err, "db is nil")
+
+		_, err = NewRefreshTokenRepository(nil)
+		assert.EqualError(t, err, "db is nil")
+	})
+}
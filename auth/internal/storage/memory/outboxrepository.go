@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	domain "github.com/nazarslota/unotes/auth/internal/domain/outbox"
+)
+
+// OutboxRepository provides an implementation of the repository of outbox messages for an in-memory database.
+// Messages are saved by the repositories of the changes they describe, at once with the change.
+type OutboxRepository struct {
+	db *DB
+}
+
+// NewOutboxRepository creates a new instance of the OutboxRepository with the provided in-memory database.
+//
+// If db is nil, returns an error.
+func NewOutboxRepository(db *DB) (*OutboxRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &OutboxRepository{db: db}, nil
+}
+
+// FindOutboxMessages finds at most limit messages in the in-memory database, oldest first.
+func (r OutboxRepository) FindOutboxMessages(ctx context.Context, limit int) ([]domain.Message, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	messages := make([]domain.Message, 0, len(r.db.outboxMessages))
+	for _, message := range r.db.outboxMessages {
+		messages = append(messages, message)
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		if !messages[i].CreatedAt.Equal(messages[j].CreatedAt) {
+			return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+		}
+		return messages[i].ID < messages[j].ID
+	})
+
+	if len(messages) > limit {
+		messages = messages[:limit]
+	}
+	return messages, nil
+}
+
+// DeleteOutboxMessage deletes a published message from the in-memory database.
+//
+// If the message is not found, returns `outbox.ErrMessageNotFound`.
+func (r OutboxRepository) DeleteOutboxMessage(ctx context.Context, messageID string) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, ok := r.db.outboxMessages[messageID]; !ok {
+		return domain.ErrMessageNotFound
+	}
+	delete(r.db.outboxMessages, messageID)
+	return nil
+}
@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	domain "github.com/nazarslota/unotes/auth/internal/domain/passkey"
+	"golang.org/x/exp/slices"
+)
+
+// PasskeyRepository provides an implementation of the passkey repository for an in-memory database. It stores both
+// the passkeys of users and the WebAuthn ceremonies in progress.
+type PasskeyRepository struct {
+	db *DB
+}
+
+// NewPasskeyRepository creates a new instance of the PasskeyRepository with the provided in-memory database.
+//
+// If db is nil, returns an error.
+func NewPasskeyRepository(db *DB) (*PasskeyRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &PasskeyRepository{db: db}, nil
+}
+
+// SavePasskey saves a passkey of a user to the in-memory database.
+//
+// If a passkey with the same credential ID is already registered, returns `passkey.ErrCredentialAlreadyExists`.
+func (r PasskeyRepository) SavePasskey(ctx context.Context, credential domain.Credential) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, ok := r.db.passkeys[credential.ID]; ok {
+		return domain.ErrCredentialAlreadyExists
+	}
+
+	credential.LastUsedAt = nil
+	r.db.passkeys[credential.ID] = clonePasskey(credential)
+	return nil
+}
+
+// FindPasskeys finds all passkeys of a user in the in-memory database, oldest first.
+func (r PasskeyRepository) FindPasskeys(ctx context.Context, userID string) ([]domain.Credential, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	credentials := make([]domain.Credential, 0)
+	for _, credential := range r.db.passkeys {
+		if credential.UserID == userID {
+			credentials = append(credentials, clonePasskey(credential))
+		}
+	}
+
+	sort.Slice(credentials, func(i, j int) bool {
+		if !credentials[i].CreatedAt.Equal(credentials[j].CreatedAt) {
+			return credentials[i].CreatedAt.Before(credentials[j].CreatedAt)
+		}
+		return credentials[i].ID < credentials[j].ID
+	})
+	return credentials, nil
+}
+
+// FindPasskey finds a passkey in the in-memory database by the credential ID.
+//
+// If the passkey is not found, returns `passkey.ErrCredentialNotFound`.
+func (r PasskeyRepository) FindPasskey(ctx context.Context, credentialID string) (domain.Credential, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.Credential{}, err
+	}
+	defer unlock()
+
+	credential, ok := r.db.passkeys[credentialID]
+	if !ok {
+		return domain.Credential{}, domain.ErrCredentialNotFound
+	}
+	return clonePasskey(credential), nil
+}
+
+// UpdatePasskeyUsage sets the signature counter of a passkey and the time it was last used in the in-memory
+// database.
+//
+// If the passkey is not found, returns `passkey.ErrCredentialNotFound`.
+func (r PasskeyRepository) UpdatePasskeyUsage(ctx context.Context, credentialID string, signCount uint32, lastUsedAt time.Time) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	credential, ok := r.db.passkeys[credentialID]
+	if !ok {
+		return domain.ErrCredentialNotFound
+	}
+
+	credential.SignCount = signCount
+	credential.LastUsedAt = &lastUsedAt
+	r.db.passkeys[credentialID] = credential
+	return nil
+}
+
+// DeletePasskey deletes a passkey of a user from the in-memory database.
+//
+// If the user has no passkey with the credential ID, returns `passkey.ErrCredentialNotFound`.
+func (r PasskeyRepository) DeletePasskey(ctx context.Context, userID, credentialID string) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if credential, ok := r.db.passkeys[credentialID]; !ok || credential.UserID != userID {
+		return domain.ErrCredentialNotFound
+	}
+	delete(r.db.passkeys, credentialID)
+	return nil
+}
+
+// SavePasskeySession saves a WebAuthn ceremony in progress to the in-memory database, pruning expired ones along the
+// way.
+func (r PasskeyRepository) SavePasskeySession(ctx context.Context, session domain.Session) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	now := time.Now()
+	for sessionHash, saved := range r.db.passkeySessions {
+		if !saved.ExpiresAt.After(now) {
+			delete(r.db.passkeySessions, sessionHash)
+		}
+	}
+
+	session.Data = slices.Clone(session.Data)
+	r.db.passkeySessions[session.SessionHash] = session
+	return nil
+}
+
+// ConsumePasskeySession deletes a WebAuthn ceremony in progress that has not expired yet and returns it, so every
+// ceremony can be finished only once.
+//
+// If the session is not found or has expired, returns `passkey.ErrSessionNotFound`.
+func (r PasskeyRepository) ConsumePasskeySession(ctx context.Context, sessionHash string) (domain.Session, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.Session{}, err
+	}
+	defer unlock()
+
+	session, ok := r.db.passkeySessions[sessionHash]
+	if !ok || !session.ExpiresAt.After(time.Now()) {
+		return domain.Session{}, domain.ErrSessionNotFound
+	}
+	delete(r.db.passkeySessions, sessionHash)
+	return session, nil
+}
+
+// clonePasskey returns a copy of credential that shares no memory with it, so that callers can't change a saved
+// passkey.
+func clonePasskey(credential domain.Credential) domain.Credential {
+	credential.PublicKey = slices.Clone(credential.PublicKey)
+	credential.Transports = slices.Clone(credential.Transports)
+	if credential.LastUsedAt != nil {
+		lastUsedAt := *credential.LastUsedAt
+		credential.LastUsedAt = &lastUsedAt
+	}
+	return credential
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "github.com/nazarslota/unotes/auth/internal/domain/passwordreset"
+)
+
+// PasswordResetTokenRepository provides an implementation of the password reset token repository for an in-memory
+// database. Only hashes of the tokens are stored.
+type PasswordResetTokenRepository struct {
+	db *DB
+}
+
+// NewPasswordResetTokenRepository creates a new instance of the PasswordResetTokenRepository with the provided
+// in-memory database.
+//
+// If db is nil, returns an error.
+func NewPasswordResetTokenRepository(db *DB) (*PasswordResetTokenRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &PasswordResetTokenRepository{db: db}, nil
+}
+
+// SavePasswordResetToken saves a password reset token to the in-memory database.
+func (r PasswordResetTokenRepository) SavePasswordResetToken(ctx context.Context, token domain.Token) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.db.passwordResetTokens[token.TokenHash] = token
+	return nil
+}
+
+// FindPasswordResetToken finds a password reset token that has not expired yet, without consuming it.
+//
+// If the token is not found or has expired, returns `passwordreset.ErrTokenNotFound`.
+func (r PasswordResetTokenRepository) FindPasswordResetToken(ctx context.Context, tokenHash string) (domain.Token, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.Token{}, err
+	}
+	defer unlock()
+
+	token, ok := r.db.passwordResetTokens[tokenHash]
+	if !ok || !token.ExpiresAt.After(time.Now()) {
+		return domain.Token{}, domain.ErrTokenNotFound
+	}
+	return token, nil
+}
+
+// ConsumePasswordResetToken deletes a password reset token that has not expired yet and returns it, so every token
+// can be used only once.
+//
+// If the token is not found or has expired, returns `passwordreset.ErrTokenNotFound`.
+func (r PasswordResetTokenRepository) ConsumePasswordResetToken(ctx context.Context, tokenHash string) (domain.Token, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.Token{}, err
+	}
+	defer unlock()
+
+	token, ok := r.db.passwordResetTokens[tokenHash]
+	if !ok || !token.ExpiresAt.After(time.Now()) {
+		return domain.Token{}, domain.ErrTokenNotFound
+	}
+	delete(r.db.passwordResetTokens, tokenHash)
+	return token, nil
+}
+
+// DeletePasswordResetTokens deletes all password reset tokens of a user, pruning expired tokens of every user along
+// the way.
+func (r PasswordResetTokenRepository) DeletePasswordResetTokens(ctx context.Context, userID string) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	now := time.Now()
+	for tokenHash, token := range r.db.passwordResetTokens {
+		if token.UserID == userID || !token.ExpiresAt.After(now) {
+			delete(r.db.passwordResetTokens, tokenHash)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	domain "github.com/nazarslota/unotes/auth/internal/domain/personalaccesstoken"
+	"golang.org/x/exp/slices"
+)
+
+// PersonalAccessTokenRepository provides an implementation of the personal access token repository for an in-memory
+// database. Only hashes of the tokens are stored.
+type PersonalAccessTokenRepository struct {
+	db *DB
+}
+
+// NewPersonalAccessTokenRepository creates a new instance of the PersonalAccessTokenRepository with the provided
+// in-memory database.
+//
+// If db is nil, returns an error.
+func NewPersonalAccessTokenRepository(db *DB) (*PersonalAccessTokenRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &PersonalAccessTokenRepository{db: db}, nil
+}
+
+// SavePersonalAccessToken saves a personal access token to the in-memory database.
+//
+// If a token with the same ID or hash already exists, returns an error.
+func (r PersonalAccessTokenRepository) SavePersonalAccessToken(ctx context.Context, token domain.Token) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for _, saved := range r.db.personalAccessTokens {
+		if saved.ID == token.ID || saved.TokenHash == token.TokenHash {
+			return fmt.Errorf("personal access token already exists")
+		}
+	}
+
+	token.LastUsedAt = nil
+	r.db.personalAccessTokens[token.ID] = clonePersonalAccessToken(token)
+	return nil
+}
+
+// FindPersonalAccessTokens finds all personal access tokens of a user in the in-memory database, oldest first.
+func (r PersonalAccessTokenRepository) FindPersonalAccessTokens(ctx context.Context, userID string) ([]domain.Token, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	tokens := make([]domain.Token, 0)
+	for _, token := range r.db.personalAccessTokens {
+		if token.UserID == userID {
+			tokens = append(tokens, clonePersonalAccessToken(token))
+		}
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		if !tokens[i].CreatedAt.Equal(tokens[j].CreatedAt) {
+			return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+		}
+		return tokens[i].ID < tokens[j].ID
+	})
+	return tokens, nil
+}
+
+// FindPersonalAccessTokenByHash finds a personal access token in the in-memory database by the hash of the token.
+//
+// If the token is not found, returns `personalaccesstoken.ErrTokenNotFound`.
+func (r PersonalAccessTokenRepository) FindPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (domain.Token, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.Token{}, err
+	}
+	defer unlock()
+
+	for _, token := range r.db.personalAccessTokens {
+		if token.TokenHash == tokenHash {
+			return clonePersonalAccessToken(token), nil
+		}
+	}
+	return domain.Token{}, domain.ErrTokenNotFound
+}
+
+// UpdatePersonalAccessTokenLastUsed sets the time a personal access token was last used in the in-memory database.
+//
+// If the token is not found, returns `personalaccesstoken.ErrTokenNotFound`.
+func (r PersonalAccessTokenRepository) UpdatePersonalAccessTokenLastUsed(ctx context.Context, tokenID string, lastUsedAt time.Time) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	token, ok := r.db.personalAccessTokens[tokenID]
+	if !ok {
+		return domain.ErrTokenNotFound
+	}
+
+	token.LastUsedAt = &lastUsedAt
+	r.db.personalAccessTokens[tokenID] = token
+	return nil
+}
+
+// DeletePersonalAccessToken deletes a personal access token of a user from the in-memory database.
+//
+// If the user has no token with the ID, returns `personalaccesstoken.ErrTokenNotFound`.
+func (r PersonalAccessTokenRepository) DeletePersonalAccessToken(ctx context.Context, userID, tokenID string) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if token, ok := r.db.personalAccessTokens[tokenID]; !ok || token.UserID != userID {
+		return domain.ErrTokenNotFound
+	}
+	delete(r.db.personalAccessTokens, tokenID)
+	return nil
+}
+
+// clonePersonalAccessToken returns a copy of token that shares no memory with it, so that callers can't change a
+// saved token.
+func clonePersonalAccessToken(token domain.Token) domain.Token {
+	token.Scopes = slices.Clone(token.Scopes)
+	if token.ExpiresAt != nil {
+		expiresAt := *token.ExpiresAt
+		token.ExpiresAt = &expiresAt
+	}
+	if token.LastUsedAt != nil {
+		lastUsedAt := *token.LastUsedAt
+		token.LastUsedAt = &lastUsedAt
+	}
+	return token
+}
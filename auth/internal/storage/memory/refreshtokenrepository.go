@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	domain "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+)
+
+// RefreshTokenRepository is an in-memory repository for managing refresh tokens.
+type RefreshTokenRepository struct {
+	db *DB
+}
+
+// NewRefreshTokenRepository creates a new RefreshTokenRepository with the provided in-memory database.
+//
+// Returns an error if the db is nil.
+func NewRefreshTokenRepository(db *DB) (*RefreshTokenRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &RefreshTokenRepository{db: db}, nil
+}
+
+// SaveRefreshToken saves the given refresh token associated with the specified user ID.
+func (r RefreshTokenRepository) SaveRefreshToken(ctx context.Context, userID string, token domain.Token) error {
+	return r.SaveRefreshTokens(ctx, userID, []domain.Token{token})
+}
+
+// DeleteRefreshToken removes a single refresh token for the given user ID.
+//
+// If the specified token cannot be found, an error of type `refresh.ErrTokenNotFound` is returned.
+func (r RefreshTokenRepository) DeleteRefreshToken(ctx context.Context, userID string, token domain.Token) error {
+	return r.DeleteRefreshTokens(ctx, userID, []domain.Token{token})
+}
+
+// GetRefreshToken returns the refresh token for the specified user ID and token value.
+//
+// If the token is not found, an error value of `refresh.ErrTokenNotFound` is returned.
+func (r RefreshTokenRepository) GetRefreshToken(ctx context.Context, userID string, token domain.Token) (domain.Token, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	if _, ok := r.db.refreshTokens[userID][token]; !ok {
+		return "", domain.ErrTokenNotFound
+	}
+	return token, nil
+}
+
+// SaveRefreshTokens saves the given refresh tokens associated with the specified user ID.
+func (r RefreshTokenRepository) SaveRefreshTokens(ctx context.Context, userID string, tokens []domain.Token) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	set, ok := r.db.refreshTokens[userID]
+	if !ok {
+		set = make(map[domain.Token]struct{}, len(tokens))
+		r.db.refreshTokens[userID] = set
+	}
+
+	for _, token := range tokens {
+		set[token] = struct{}{}
+	}
+	return nil
+}
+
+// DeleteRefreshTokens removes the given refresh tokens associated with the specified user ID.
+//
+// If none of the tokens can be found, this method returns an error of type `refresh.ErrTokenNotFound`.
+func (r RefreshTokenRepository) DeleteRefreshTokens(ctx context.Context, userID string, tokens []domain.Token) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	set := r.db.refreshTokens[userID]
+
+	deleted := 0
+	for _, token := range tokens {
+		if _, ok := set[token]; ok {
+			delete(set, token)
+			deleted++
+		}
+	}
+
+	if len(set) == 0 {
+		delete(r.db.refreshTokens, userID)
+	}
+
+	if deleted == 0 {
+		return domain.ErrTokenNotFound
+	}
+	return nil
+}
+
+// GetRefreshTokens returns a slice of all the refresh tokens associated with the given user ID.
+//
+// If no tokens are found, this method returns an error of type `refresh.ErrTokenNotFound`.
+func (r RefreshTokenRepository) GetRefreshTokens(ctx context.Context, userID string) ([]domain.Token, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	set := r.db.refreshTokens[userID]
+	if len(set) == 0 {
+		return nil, domain.ErrTokenNotFound
+	}
+
+	tokens := make([]domain.Token, 0, len(set))
+	for token := range set {
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
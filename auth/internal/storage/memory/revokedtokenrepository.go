@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RevokedTokenRepository is an in-memory denylist of revoked tokens, identified by their token ID (jti). Entries
+// expire together with the tokens, so the denylist only holds tokens that would otherwise still be valid.
+type RevokedTokenRepository struct {
+	db *DB
+}
+
+// NewRevokedTokenRepository creates a new RevokedTokenRepository with the provided in-memory database.
+//
+// Returns an error if the db is nil.
+func NewRevokedTokenRepository(db *DB) (*RevokedTokenRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &RevokedTokenRepository{db: db}, nil
+}
+
+// SaveRevokedToken adds the token ID to the denylist until the token expires in expiresIn, pruning expired entries
+// along the way.
+func (r RevokedTokenRepository) SaveRevokedToken(ctx context.Context, tokenID string, expiresIn time.Duration) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	now := time.Now()
+	for id, expiresAt := range r.db.revokedTokens {
+		if !expiresAt.After(now) {
+			delete(r.db.revokedTokens, id)
+		}
+	}
+
+	r.db.revokedTokens[tokenID] = now.Add(expiresIn)
+	return nil
+}
+
+// IsTokenRevoked reports whether the token ID is in the denylist.
+func (r RevokedTokenRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	expiresAt, ok := r.db.revokedTokens[tokenID]
+	return ok && expiresAt.After(time.Now()), nil
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domain "github.com/nazarslota/unotes/auth/internal/domain/lockout"
+)
+
+// requests is the number of requests for a key, such as failed sign in attempts, which is reset once it expires.
+type requests struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// SignInAttemptRepository is an in-memory repository for counting failed sign in attempts and locking sign in, as
+// well as for counting magic link requests. Keys are arbitrary strings, such as a username or a client IP address.
+type SignInAttemptRepository struct {
+	db *DB
+}
+
+// NewSignInAttemptRepository creates a new SignInAttemptRepository with the provided in-memory database.
+//
+// Returns an error if the db is nil.
+func NewSignInAttemptRepository(db *DB) (*SignInAttemptRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &SignInAttemptRepository{db: db}, nil
+}
+
+// SaveSignInFailure increments the number of failed sign in attempts for the key and returns the new number. The
+// counter expires after window has passed without failures.
+func (r SignInAttemptRepository) SaveSignInFailure(ctx context.Context, key string, window time.Duration) (int64, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	return r.db.count(r.db.signInFailures, key, window), nil
+}
+
+// SaveSignInLock locks sign in for the key for the given duration.
+func (r SignInAttemptRepository) SaveSignInLock(ctx context.Context, key string, duration time.Duration) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.db.signInLocks[key] = time.Now().Add(duration)
+	return nil
+}
+
+// FindSignInLock returns the time left until sign in for the key is unlocked.
+//
+// If sign in is not locked, returns `lockout.ErrLockNotFound`.
+func (r SignInAttemptRepository) FindSignInLock(ctx context.Context, key string) (time.Duration, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	expiresAt, ok := r.db.signInLocks[key]
+	if !ok {
+		return 0, domain.ErrLockNotFound
+	}
+
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		delete(r.db.signInLocks, key)
+		return 0, domain.ErrLockNotFound
+	}
+	return ttl, nil
+}
+
+// DeleteSignInFailures resets the number of failed sign in attempts for the key and unlocks it.
+func (r SignInAttemptRepository) DeleteSignInFailures(ctx context.Context, key string) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	delete(r.db.signInFailures, key)
+	delete(r.db.signInLocks, key)
+	return nil
+}
+
+// SaveMagicLinkRequest increments the number of magic link requests for the key and returns the new number. The
+// counter expires after window has passed without requests.
+func (r SignInAttemptRepository) SaveMagicLinkRequest(ctx context.Context, key string, window time.Duration) (int64, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	return r.db.count(r.db.magicLinkRequests, key, window), nil
+}
+
+// count increments the number of requests for the key in counters and returns the new number. The db must be locked.
+func (db *DB) count(counters map[string]requests, key string, window time.Duration) int64 {
+	now := time.Now()
+	counter := counters[key]
+	if !counter.expiresAt.After(now) {
+		counter.count = 0
+	}
+
+	counter.count++
+	counter.expiresAt = now.Add(window)
+	counters[key] = counter
+	return counter.count
+}
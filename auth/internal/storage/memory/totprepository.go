@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	domain "github.com/nazarslota/unotes/auth/internal/domain/totp"
+)
+
+// TOTPRepository provides an implementation of the TOTP and recovery code repository for an in-memory database.
+type TOTPRepository struct {
+	db *DB
+}
+
+// NewTOTPRepository creates a new instance of the TOTPRepository with the provided in-memory database.
+//
+// If db is nil, returns an error.
+func NewTOTPRepository(db *DB) (*TOTPRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &TOTPRepository{db: db}, nil
+}
+
+// SaveTOTP saves a user's TOTP secret to the in-memory database, replacing any previously saved one.
+func (r TOTPRepository) SaveTOTP(ctx context.Context, totp domain.TOTP) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.db.totps[totp.UserID] = totp
+	return nil
+}
+
+// FindTOTPByUserID finds a user's TOTP secret in the in-memory database.
+//
+// If the TOTP secret is not found, returns `totp.ErrTOTPNotFound`.
+func (r TOTPRepository) FindTOTPByUserID(ctx context.Context, userID string) (domain.TOTP, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.TOTP{}, err
+	}
+	defer unlock()
+
+	totp, ok := r.db.totps[userID]
+	if !ok {
+		return domain.TOTP{}, domain.ErrTOTPNotFound
+	}
+	return totp, nil
+}
+
+// UpdateTOTP updates the state and the last used time step of a user's TOTP secret.
+//
+// If the TOTP secret is not found, returns `totp.ErrTOTPNotFound`.
+func (r TOTPRepository) UpdateTOTP(ctx context.Context, totp domain.TOTP) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	saved, ok := r.db.totps[totp.UserID]
+	if !ok {
+		return domain.ErrTOTPNotFound
+	}
+
+	saved.Enabled, saved.LastUsedStep = totp.Enabled, totp.LastUsedStep
+	r.db.totps[totp.UserID] = saved
+	return nil
+}
+
+// UpdateTOTPLastUsedStep sets the last used time step of a user's TOTP secret to step only if it is later than the
+// last used one, so that concurrent sign ins can't both use the same code.
+//
+// If the TOTP secret is not found, returns `totp.ErrTOTPNotFound`. If the time step is not later than the last used
+// one, returns `totp.ErrTOTPStepUsed`.
+func (r TOTPRepository) UpdateTOTPLastUsedStep(ctx context.Context, userID string, step int64) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	saved, ok := r.db.totps[userID]
+	if !ok {
+		return domain.ErrTOTPNotFound
+	} else if step <= saved.LastUsedStep {
+		return domain.ErrTOTPStepUsed
+	}
+
+	saved.LastUsedStep = step
+	r.db.totps[userID] = saved
+	return nil
+}
+
+// DeleteTOTP deletes a user's TOTP secret from the in-memory database.
+//
+// If the TOTP secret is not found, returns `totp.ErrTOTPNotFound`.
+func (r TOTPRepository) DeleteTOTP(ctx context.Context, userID string) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, ok := r.db.totps[userID]; !ok {
+		return domain.ErrTOTPNotFound
+	}
+	delete(r.db.totps, userID)
+	return nil
+}
+
+// SaveRecoveryCodes replaces all recovery codes of a user with the given ones at once.
+func (r TOTPRepository) SaveRecoveryCodes(ctx context.Context, userID string, codes []domain.RecoveryCode) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	set := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		set[code.CodeHash] = struct{}{}
+	}
+	r.db.recoveryCodes[userID] = set
+	return nil
+}
+
+// DeleteRecoveryCode deletes a single recovery code, which makes it unusable for any later sign in.
+//
+// If the recovery code is not found, returns `totp.ErrRecoveryCodeNotFound`.
+func (r TOTPRepository) DeleteRecoveryCode(ctx context.Context, code domain.RecoveryCode) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, ok := r.db.recoveryCodes[code.UserID][code.CodeHash]; !ok {
+		return domain.ErrRecoveryCodeNotFound
+	}
+	delete(r.db.recoveryCodes[code.UserID], code.CodeHash)
+	return nil
+}
+
+// DeleteRecoveryCodes deletes all recovery codes of a user.
+func (r TOTPRepository) DeleteRecoveryCodes(ctx context.Context, userID string) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	delete(r.db.recoveryCodes, userID)
+	return nil
+}
@@ -0,0 +1,327 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	domainoutbox "github.com/nazarslota/unotes/auth/internal/domain/outbox"
+	domain "github.com/nazarslota/unotes/auth/internal/domain/user"
+)
+
+// userRecord is a row of the users table, the user together with their profile.
+type userRecord struct {
+	user    domain.User
+	profile domain.Profile
+}
+
+// UserRepository provides an implementation of the user repository for an in-memory database.
+type UserRepository struct {
+	db *DB
+}
+
+// NewUserRepository creates a new instance of the UserRepository with the provided in-memory database.
+//
+// If db is nil, returns an error.
+func NewUserRepository(db *DB) (*UserRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &UserRepository{db: db}, nil
+}
+
+// SaveUser saves a user to the in-memory database. A user without a role is saved with the user role, with the
+// default profile.
+//
+// If the user already exists, returns `user.ErrUserAlreadyExists`.
+// If the email is already used by another user, returns `user.ErrEmailAlreadyExists`.
+func (r UserRepository) SaveUser(ctx context.Context, user domain.User) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, ok := r.db.users[user.ID]; ok {
+		return domain.ErrUserAlreadyExists
+	} else if err := r.checkUnique(user); err != nil {
+		return err
+	}
+
+	if len(user.Role) == 0 {
+		user.Role = domain.RoleUser
+	}
+	r.db.users[user.ID] = userRecord{
+		user:    user,
+		profile: domain.Profile{UserID: user.ID, Username: user.Username, Locale: "en", Timezone: "UTC"},
+	}
+	return nil
+}
+
+// FindUserByUserID finds a user in the in-memory database by their user ID.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) FindUserByUserID(ctx context.Context, userID string) (domain.User, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.User{}, err
+	}
+	defer unlock()
+
+	record, ok := r.db.users[userID]
+	if !ok {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+	return record.user, nil
+}
+
+// FindUserByUsername finds a user in the in-memory database by their username.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) FindUserByUsername(ctx context.Context, username string) (domain.User, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.User{}, err
+	}
+	defer unlock()
+
+	for _, record := range r.db.users {
+		if record.user.Username == username {
+			return record.user, nil
+		}
+	}
+	return domain.User{}, domain.ErrUserNotFound
+}
+
+// FindUserByEmail finds a user in the in-memory database by their email, ignoring case.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) FindUserByEmail(ctx context.Context, email string) (domain.User, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.User{}, err
+	}
+	defer unlock()
+
+	for _, record := range r.db.users {
+		if len(record.user.Email) != 0 && strings.EqualFold(record.user.Email, email) {
+			return record.user, nil
+		}
+	}
+	return domain.User{}, domain.ErrUserNotFound
+}
+
+// UpdateUser updates the username, the password hash and the email of a user in the in-memory database.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+// If the new username is already taken, returns `user.ErrUserAlreadyExists`.
+// If the new email is already used by another user, returns `user.ErrEmailAlreadyExists`.
+func (r UserRepository) UpdateUser(ctx context.Context, user domain.User) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.db.users[user.ID]
+	if !ok {
+		return domain.ErrUserNotFound
+	} else if err := r.checkUnique(user); err != nil {
+		return err
+	}
+
+	record.user.Username = user.Username
+	record.user.PasswordHash = user.PasswordHash
+	record.user.Email = user.Email
+	record.user.EmailVerified = user.EmailVerified
+	record.profile.Username = user.Username
+	r.db.users[user.ID] = record
+	return nil
+}
+
+// FindUsers finds the users selected by the filter in the in-memory database, ordered by username.
+func (r UserRepository) FindUsers(ctx context.Context, filter domain.Filter) ([]domain.User, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	query := strings.ToLower(filter.Query)
+	users := make([]domain.User, 0)
+	for _, record := range r.db.users {
+		user := record.user
+		if user.Username <= filter.After {
+			continue
+		} else if !strings.Contains(strings.ToLower(user.Username), query) &&
+			!strings.Contains(strings.ToLower(user.Email), query) {
+			continue
+		}
+		users = append(users, user)
+	}
+
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	if filter.Limit > 0 && len(users) > filter.Limit {
+		users = users[:filter.Limit]
+	}
+	return users, nil
+}
+
+// UpdateUserRole updates the role of a user in the in-memory database.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) UpdateUserRole(ctx context.Context, userID, role string) error {
+	return r.updateUser(ctx, userID, func(user *domain.User) { user.Role = role })
+}
+
+// UpdateUserDisabled disables or enables a user in the in-memory database.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) UpdateUserDisabled(ctx context.Context, userID string, disabled bool) error {
+	return r.updateUser(ctx, userID, func(user *domain.User) { user.Disabled = disabled })
+}
+
+// updateUser applies the update to a user in the in-memory database.
+func (r UserRepository) updateUser(ctx context.Context, userID string, update func(user *domain.User)) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.db.users[userID]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+
+	update(&record.user)
+	r.db.users[userID] = record
+	return nil
+}
+
+// DeleteUser deletes a user from the in-memory database, together with everything that belongs to them, remembers
+// the user as deleted and saves the messages describing the deletion to the outbox at once.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) DeleteUser(ctx context.Context, userID string, messages ...domainoutbox.Message) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, ok := r.db.users[userID]; !ok {
+		return domain.ErrUserNotFound
+	}
+
+	delete(r.db.users, userID)
+	r.db.deleteUserData(userID)
+	r.db.deletedUsers[userID] = time.Now()
+	for _, message := range messages {
+		r.db.outboxMessages[message.ID] = message
+	}
+	return nil
+}
+
+// FindDeletedUserIDs finds which of the given user IDs belong to users that were deleted from the in-memory database.
+// User IDs that never belonged to a user, or belong to a user again, are left out.
+func (r UserRepository) FindDeletedUserIDs(ctx context.Context, userIDs []string) ([]string, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	deleted := make([]string, 0, len(userIDs))
+	seen := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		_, exists := r.db.users[userID]
+		if _, ok := r.db.deletedUsers[userID]; ok && !exists && !seen[userID] {
+			deleted = append(deleted, userID)
+			seen[userID] = true
+		}
+	}
+	return deleted, nil
+}
+
+// FindProfile finds the profile of a user in the in-memory database by their user ID.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) FindProfile(ctx context.Context, userID string) (domain.Profile, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return domain.Profile{}, err
+	}
+	defer unlock()
+
+	record, ok := r.db.users[userID]
+	if !ok {
+		return domain.Profile{}, domain.ErrUserNotFound
+	}
+	return record.profile, nil
+}
+
+// FindProfiles finds the profiles of the users with the given user IDs in the in-memory database, ordered by
+// username. Users that are not found are left out, so fewer profiles than user IDs may be returned.
+func (r UserRepository) FindProfiles(ctx context.Context, userIDs []string) ([]domain.Profile, error) {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	profiles := make([]domain.Profile, 0, len(userIDs))
+	seen := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if record, ok := r.db.users[userID]; ok && !seen[userID] {
+			profiles = append(profiles, record.profile)
+			seen[userID] = true
+		}
+	}
+
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Username < profiles[j].Username })
+	return profiles, nil
+}
+
+// UpdateProfile updates the display name, the avatar, the locale and the timezone of a user in the in-memory
+// database. The username is not changed.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) UpdateProfile(ctx context.Context, profile domain.Profile) error {
+	unlock, err := r.db.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.db.users[profile.UserID]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+
+	record.profile.DisplayName = profile.DisplayName
+	record.profile.Avatar = profile.Avatar
+	record.profile.Locale = profile.Locale
+	record.profile.Timezone = profile.Timezone
+	r.db.users[profile.UserID] = record
+	return nil
+}
+
+// checkUnique checks the unique constraints of the users table against the other users: the username, and the email
+// ignoring case.
+func (r UserRepository) checkUnique(user domain.User) error {
+	for id, record := range r.db.users {
+		if id == user.ID {
+			continue
+		}
+
+		if record.user.Username == user.Username {
+			return domain.ErrUserAlreadyExists
+		} else if len(user.Email) != 0 && strings.EqualFold(record.user.Email, user.Email) {
+			return domain.ErrEmailAlreadyExists
+		}
+	}
+	return nil
+}
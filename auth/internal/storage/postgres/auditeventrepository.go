@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	domain "github.com/nazarslota/unotes/auth/internal/domain/audit"
+)
+
+// AuditEventRepository provides an implementation of the audit log for a PostgreSQL database. The auth_events table
+// is append-only, events are never updated, only deleted once they are older than the retention period.
+type AuditEventRepository struct {
+	db *sqlx.DB
+}
+
+// NewAuditEventRepository creates a new instance of the AuditEventRepository with the provided handle to the
+// PostgreSQL database.
+//
+// If db is nil, returns an error.
+func NewAuditEventRepository(db *sqlx.DB) (*AuditEventRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &AuditEventRepository{db: db}, nil
+}
+
+// SaveAuditEvent appends an event to the audit log in the PostgreSQL database.
+func (r AuditEventRepository) SaveAuditEvent(ctx context.Context, event domain.Event) error {
+	query := fmt.Sprintf(`INSERT INTO auth_events (id, type, user_id, ip, user_agent, session_id, outcome, reason, created_at)
+VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5, $6, $7, $8, $9)`)
+
+	_, err := r.db.ExecContext(ctx, query,
+		event.ID, event.Type, event.UserID, event.IP, event.UserAgent, event.SessionID, event.Outcome, event.Reason,
+		event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
+
+// FindAuditEvents finds the events selected by the filter in the PostgreSQL database, newest first.
+func (r AuditEventRepository) FindAuditEvents(ctx context.Context, filter domain.Filter) ([]domain.Event, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if len(filter.UserID) != 0 {
+		where("user_id = $%d::uuid", filter.UserID)
+	}
+	if len(filter.Type) != 0 {
+		where("type = $%d", filter.Type)
+	}
+	if len(filter.Outcome) != 0 {
+		where("outcome = $%d", filter.Outcome)
+	}
+	if len(filter.IP) != 0 {
+		where("ip = $%d", filter.IP)
+	}
+	if !filter.Since.IsZero() {
+		where("created_at >= $%d", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		where("created_at < $%d", filter.Until)
+	}
+
+	query := `SELECT id, type, COALESCE(user_id::text, '') AS user_id, ip, user_agent, session_id, outcome, reason, created_at
+FROM auth_events`
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	events := make([]domain.Event, 0)
+	if err := r.db.SelectContext(ctx, &events, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return events, nil
+}
+
+// DeleteAuditEventsBefore deletes the events created before the given time from the PostgreSQL database and returns
+// the number of deleted events.
+func (r AuditEventRepository) DeleteAuditEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM auth_events WHERE created_at < $1`)
+
+	res, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve rows affected: %w", err)
+	}
+	return affected, nil
+}
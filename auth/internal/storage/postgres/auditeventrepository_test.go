@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nazarslota/unotes/auth/internal/domain/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var auditEventRepository *AuditEventRepository
+
+func init() {
+	db, err := NewPostgreSQL(context.Background(), Config{
+		Host:     "localhost",
+		Port:     "5432",
+		Username: "postgres",
+		Password: "postgres",
+		DBName:   "postgres",
+		SSLMode:  "disable",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	auditEventRepository, err = NewAuditEventRepository(db)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestNewAuditEventRepository(t *testing.T) {
+	t.Run("should return error when db is nil", func(t *testing.T) {
+		repository, err := NewAuditEventRepository(nil)
+		assert.EqualError(t, err, "db is nil")
+		assert.Nil(t, repository)
+	})
+}
+
+func TestAuditEventRepository(t *testing.T) {
+	t.Run("should save, find and delete events", func(t *testing.T) {
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		success := audit.Event{
+			ID:        "0f6a2c3e-8b1d-4c5f-9e7a-2b3c4d5e6f70",
+			Type:      audit.TypeSignIn,
+			UserID:    "8e6b9e26-8a5b-4bb8-9e0e-54b4d6a4e6f1",
+			IP:        "192.0.2.1",
+			UserAgent: "curl/8.0",
+			SessionID: "session-id",
+			Outcome:   audit.OutcomeSuccess,
+			CreatedAt: now.Add(-time.Minute),
+		}
+		failure := audit.Event{
+			ID:        "1f6a2c3e-8b1d-4c5f-9e7a-2b3c4d5e6f71",
+			Type:      audit.TypeSignIn,
+			IP:        "192.0.2.2",
+			Outcome:   audit.OutcomeFailure,
+			Reason:    "invalid username",
+			CreatedAt: now,
+		}
+		require.NoError(t, auditEventRepository.SaveAuditEvent(context.Background(), success))
+		require.NoError(t, auditEventRepository.SaveAuditEvent(context.Background(), failure))
+
+		events, err := auditEventRepository.FindAuditEvents(context.Background(), audit.Filter{UserID: success.UserID})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, success.SessionID, events[0].SessionID)
+		assert.True(t, success.CreatedAt.Equal(events[0].CreatedAt))
+
+		events, err = auditEventRepository.FindAuditEvents(context.Background(), audit.Filter{
+			Type:    audit.TypeSignIn,
+			Outcome: audit.OutcomeFailure,
+			Since:   now,
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "", events[0].UserID)
+		assert.Equal(t, failure.Reason, events[0].Reason)
+
+		deleted, err := auditEventRepository.DeleteAuditEventsBefore(context.Background(), now.Add(time.Second))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, deleted, int64(2))
+
+		events, err = auditEventRepository.FindAuditEvents(context.Background(), audit.Filter{Limit: 1})
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+}
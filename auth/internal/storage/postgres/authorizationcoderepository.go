@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	domain "github.com/nazarslota/unotes/auth/internal/domain/authorizationcode"
+)
+
+// AuthorizationCodeRepository provides an implementation of the OAuth2 authorization code repository for a
+// PostgreSQL database. Only hashes of the codes are stored.
+type AuthorizationCodeRepository struct {
+	db *sqlx.DB
+}
+
+// NewAuthorizationCodeRepository creates a new instance of the AuthorizationCodeRepository with the provided handle to
+// the PostgreSQL database.
+//
+// If db is nil, returns an error.
+func NewAuthorizationCodeRepository(db *sqlx.DB) (*AuthorizationCodeRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &AuthorizationCodeRepository{db: db}, nil
+}
+
+// SaveAuthorizationCode saves an authorization code to the PostgreSQL database, pruning expired codes along the way.
+func (r AuthorizationCodeRepository) SaveAuthorizationCode(ctx context.Context, code domain.Code) error {
+	query := fmt.Sprintf(`DELETE FROM oauth2_authorization_codes WHERE expires_at <= now()`)
+	if _, err := r.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	query = fmt.Sprintf(`INSERT INTO oauth2_authorization_codes
+(code_hash, client_id, user_id, redirect_uri, scope, code_challenge, nonce, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`)
+
+	_, err := r.db.ExecContext(ctx, query,
+		code.CodeHash, code.ClientID, code.UserID, code.RedirectURI, code.Scope, code.CodeChallenge, code.Nonce,
+		code.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
+
+// ConsumeAuthorizationCode deletes an authorization code that has not expired yet and returns it, so every code can
+// be exchanged only once.
+//
+// If the code is not found or has expired, returns `authorizationcode.ErrCodeNotFound`.
+func (r AuthorizationCodeRepository) ConsumeAuthorizationCode(ctx context.Context, codeHash string) (code domain.Code, err error) {
+	query := fmt.Sprintf(`DELETE FROM oauth2_authorization_codes WHERE code_hash = $1 AND expires_at > now() RETURNING *`)
+	if err := r.db.GetContext(ctx, &code, query, codeHash); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.Code{}, errors.Join(err, domain.ErrCodeNotFound)
+	} else if err != nil {
+		return domain.Code{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return code, nil
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	domain "github.com/nazarslota/unotes/auth/internal/domain/client"
+)
+
+// ClientRepository provides an implementation of the OAuth2 client repository for a PostgreSQL database. Only hashes
+// of the client secrets are stored.
+type ClientRepository struct {
+	db *sqlx.DB
+}
+
+// clientRow is a row of the oauth2_clients table, which stores lists as PostgreSQL arrays.
+type clientRow struct {
+	ID           string         `db:"id"`
+	SecretHash   string         `db:"secret_hash"`
+	Name         string         `db:"name"`
+	RedirectURIs pq.StringArray `db:"redirect_uris"`
+	Scopes       pq.StringArray `db:"scopes"`
+	GrantTypes   pq.StringArray `db:"grant_types"`
+	Public       bool           `db:"public"`
+}
+
+// NewClientRepository creates a new instance of the ClientRepository with the provided handle to the PostgreSQL
+// database.
+//
+// If db is nil, returns an error.
+func NewClientRepository(db *sqlx.DB) (*ClientRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &ClientRepository{db: db}, nil
+}
+
+// SaveClient saves a client to the PostgreSQL database.
+//
+// If a client with the same ID already exists, returns `client.ErrClientAlreadyExists`.
+func (r ClientRepository) SaveClient(ctx context.Context, client domain.Client) error {
+	query := fmt.Sprintf(`INSERT INTO oauth2_clients (id, secret_hash, name, redirect_uris, scopes, grant_types, public)
+VALUES ($1, $2, $3, $4, $5, $6, $7)`)
+
+	_, err := r.db.ExecContext(ctx, query,
+		client.ID, client.SecretHash, client.Name,
+		pq.StringArray(client.RedirectURIs), pq.StringArray(client.Scopes), pq.StringArray(client.GrantTypes),
+		client.Public,
+	)
+
+	pqErr := new(pq.Error)
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return errors.Join(err, domain.ErrClientAlreadyExists)
+	} else if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
+
+// FindClientByClientID finds a client in the PostgreSQL database.
+//
+// If the client is not found, returns `client.ErrClientNotFound`.
+func (r ClientRepository) FindClientByClientID(ctx context.Context, clientID string) (domain.Client, error) {
+	query := fmt.Sprintf(`SELECT * FROM oauth2_clients WHERE id = $1`)
+
+	var row clientRow
+	if err := r.db.GetContext(ctx, &row, query, clientID); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.Client{}, errors.Join(err, domain.ErrClientNotFound)
+	} else if err != nil {
+		return domain.Client{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return domain.Client{
+		ID:           row.ID,
+		SecretHash:   row.SecretHash,
+		Name:         row.Name,
+		RedirectURIs: row.RedirectURIs,
+		Scopes:       row.Scopes,
+		GrantTypes:   row.GrantTypes,
+		Public:       row.Public,
+	}, nil
+}
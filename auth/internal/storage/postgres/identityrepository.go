@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	domain "github.com/nazarslota/unotes/auth/internal/domain/identity"
+)
+
+// IdentityRepository provides an implementation of the repository of identities at external identity providers
+// linked to users, and of the sign ins with them in progress, for a PostgreSQL database.
+type IdentityRepository struct {
+	db *sqlx.DB
+}
+
+// NewIdentityRepository creates a new instance of the IdentityRepository with the provided handle to the PostgreSQL
+// database.
+//
+// If db is nil, returns an error.
+func NewIdentityRepository(db *sqlx.DB) (*IdentityRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &IdentityRepository{db: db}, nil
+}
+
+// SaveIdentity links an identity to a user in the PostgreSQL database.
+//
+// If the identity is already linked, or the user already has an identity at the provider, returns
+// `identity.ErrIdentityAlreadyExists`.
+func (r IdentityRepository) SaveIdentity(ctx context.Context, identity domain.Identity) error {
+	query := fmt.Sprintf(`INSERT INTO identities (provider, subject, user_id) VALUES ($1, $2, $3)`)
+
+	_, err := r.db.ExecContext(ctx, query, identity.Provider, identity.Subject, identity.UserID)
+
+	pqErr := new(pq.Error)
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return errors.Join(err, domain.ErrIdentityAlreadyExists)
+	} else if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
+
+// FindIdentity finds an identity in the PostgreSQL database by the provider and the subject at the provider.
+//
+// If the identity is not found, returns `identity.ErrIdentityNotFound`.
+func (r IdentityRepository) FindIdentity(ctx context.Context, provider, subject string) (identity domain.Identity, err error) {
+	query := fmt.Sprintf(`SELECT provider, subject, user_id FROM identities WHERE provider = $1 AND subject = $2`)
+	if err := r.db.GetContext(ctx, &identity, query, provider, subject); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.Identity{}, errors.Join(err, domain.ErrIdentityNotFound)
+	} else if err != nil {
+		return domain.Identity{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return identity, nil
+}
+
+// SaveIdentityState saves a sign in with an external identity provider in progress to the PostgreSQL database,
+// pruning expired ones along the way.
+func (r IdentityRepository) SaveIdentityState(ctx context.Context, state domain.State) error {
+	query := fmt.Sprintf(`DELETE FROM identity_states WHERE expires_at <= now()`)
+	if _, err := r.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	query = fmt.Sprintf(`INSERT INTO identity_states (state_hash, provider, nonce, code_verifier, user_id, expires_at)
+VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, $6)`)
+
+	_, err := r.db.ExecContext(ctx, query,
+		state.StateHash, state.Provider, state.Nonce, state.CodeVerifier, state.UserID, state.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
+
+// ConsumeIdentityState deletes a sign in with an external identity provider that has not expired yet and returns it,
+// so every state can be used only once.
+//
+// If the state is not found or has expired, returns `identity.ErrStateNotFound`.
+func (r IdentityRepository) ConsumeIdentityState(ctx context.Context, stateHash string) (state domain.State, err error) {
+	query := fmt.Sprintf(`DELETE FROM identity_states WHERE state_hash = $1 AND expires_at > now()
+RETURNING state_hash, provider, nonce, code_verifier, COALESCE(user_id::text, '') AS user_id, expires_at`)
+	if err := r.db.GetContext(ctx, &state, query, stateHash); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.State{}, errors.Join(err, domain.ErrStateNotFound)
+	} else if err != nil {
+		return domain.State{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return state, nil
+}
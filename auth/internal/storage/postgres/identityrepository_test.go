@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nazarslota/unotes/auth/internal/domain/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var identityRepository *IdentityRepository
+
+func init() {
+	db, err := NewPostgreSQL(context.Background(), Config{
+		Host:     "localhost",
+		Port:     "5432",
+		Username: "postgres",
+		Password: "postgres",
+		DBName:   "postgres",
+		SSLMode:  "disable",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	identityRepository, err = NewIdentityRepository(db)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestNewIdentityRepository(t *testing.T) {
+	t.Run("should return error when db is nil", func(t *testing.T) {
+		repository, err := NewIdentityRepository(nil)
+		assert.EqualError(t, err, "db is nil")
+		assert.Nil(t, repository)
+	})
+}
+
+func TestIdentityRepository_FindIdentity(t *testing.T) {
+	t.Run("should find saved identity", func(t *testing.T) {
+		saveUserA(t)
+
+		expected := identity.Identity{Provider: "provider", Subject: "subject", UserID: userA.ID}
+		err := identityRepository.SaveIdentity(context.Background(), expected)
+		require.NoError(t, err)
+
+		actual, err := identityRepository.FindIdentity(context.Background(), expected.Provider, expected.Subject)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("should return error when identity is not found", func(t *testing.T) {
+		_, err := identityRepository.FindIdentity(context.Background(), "provider", "unknown")
+		assert.ErrorIs(t, err, identity.ErrIdentityNotFound)
+	})
+}
+
+func TestIdentityRepository_SaveIdentity(t *testing.T) {
+	t.Run("should return error when user already has an identity at the provider", func(t *testing.T) {
+		saveUserA(t)
+
+		err := identityRepository.SaveIdentity(context.Background(), identity.Identity{
+			Provider: "provider", Subject: "subject-a", UserID: userA.ID,
+		})
+		require.NoError(t, err)
+
+		err = identityRepository.SaveIdentity(context.Background(), identity.Identity{
+			Provider: "provider", Subject: "subject-b", UserID: userA.ID,
+		})
+		assert.ErrorIs(t, err, identity.ErrIdentityAlreadyExists)
+	})
+}
+
+func TestIdentityRepository_ConsumeIdentityState(t *testing.T) {
+	t.Run("should consume state only once", func(t *testing.T) {
+		saveUserA(t)
+
+		state := identity.State{
+			StateHash:    "state-hash",
+			Provider:     "provider",
+			Nonce:        "nonce",
+			CodeVerifier: "code-verifier",
+			UserID:       userA.ID,
+			ExpiresAt:    time.Now().Add(time.Hour),
+		}
+		err := identityRepository.SaveIdentityState(context.Background(), state)
+		require.NoError(t, err)
+
+		result, err := identityRepository.ConsumeIdentityState(context.Background(), state.StateHash)
+		assert.NoError(t, err)
+		assert.Equal(t, state.Nonce, result.Nonce)
+		assert.Equal(t, state.CodeVerifier, result.CodeVerifier)
+		assert.Equal(t, state.UserID, result.UserID)
+
+		_, err = identityRepository.ConsumeIdentityState(context.Background(), state.StateHash)
+		assert.ErrorIs(t, err, identity.ErrStateNotFound)
+	})
+
+	t.Run("should consume state without user", func(t *testing.T) {
+		state := identity.State{
+			StateHash:    "state-hash",
+			Provider:     "provider",
+			Nonce:        "nonce",
+			CodeVerifier: "code-verifier",
+			ExpiresAt:    time.Now().Add(time.Hour),
+		}
+		err := identityRepository.SaveIdentityState(context.Background(), state)
+		require.NoError(t, err)
+
+		result, err := identityRepository.ConsumeIdentityState(context.Background(), state.StateHash)
+		assert.NoError(t, err)
+		assert.Empty(t, result.UserID)
+	})
+
+	t.Run("should not consume expired state", func(t *testing.T) {
+		state := identity.State{
+			StateHash:    "state-hash",
+			Provider:     "provider",
+			Nonce:        "nonce",
+			CodeVerifier: "code-verifier",
+			ExpiresAt:    time.Now().Add(-time.Hour),
+		}
+		err := identityRepository.SaveIdentityState(context.Background(), state)
+		require.NoError(t, err)
+
+		_, err = identityRepository.ConsumeIdentityState(context.Background(), state.StateHash)
+		assert.ErrorIs(t, err, identity.ErrStateNotFound)
+	})
+}
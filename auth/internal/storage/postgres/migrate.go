@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	migratepostgres "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/jmoiron/sqlx"
+	"github.com/nazarslota/unotes/auth/pkg/migrate"
+	"github.com/nazarslota/unotes/auth/schema"
+)
+
+// NewMigrator returns a new migrator that applies the migrations of the schema to db. It holds a connection of db
+// until it is closed, the migrations are applied under a PostgreSQL advisory lock taken on that connection.
+func NewMigrator(ctx context.Context, db *sqlx.DB) (*migrate.Migrator, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	driver, err := migratepostgres.WithConnection(ctx, conn, &migratepostgres.Config{})
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to create migrate driver: %w", err)
+	}
+
+	migrator, err := migrate.NewMigrator(schema.Migrations, "postgres", driver)
+	if err != nil {
+		_ = driver.Close()
+		return nil, err
+	}
+	return migrator, nil
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	domain "github.com/nazarslota/unotes/auth/internal/domain/outbox"
+)
+
+// OutboxRepository provides an implementation of the repository of outbox messages for a PostgreSQL database. Messages
+// are saved by the repositories of the changes they describe, in the same transaction, with saveOutboxMessages.
+type OutboxRepository struct {
+	db *sqlx.DB
+}
+
+// NewOutboxRepository creates a new instance of the OutboxRepository with the provided handle to the PostgreSQL
+// database.
+//
+// If db is nil, returns an error.
+func NewOutboxRepository(db *sqlx.DB) (*OutboxRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &OutboxRepository{db: db}, nil
+}
+
+// FindOutboxMessages finds at most limit messages in the PostgreSQL database, oldest first.
+func (r OutboxRepository) FindOutboxMessages(ctx context.Context, limit int) ([]domain.Message, error) {
+	query := fmt.Sprintf(`SELECT id, subject, payload, created_at FROM outbox_messages ORDER BY created_at, id LIMIT $1`)
+
+	messages := make([]domain.Message, 0, limit)
+	if err := r.db.SelectContext(ctx, &messages, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return messages, nil
+}
+
+// DeleteOutboxMessage deletes a published message from the PostgreSQL database.
+//
+// If the message is not found, returns `outbox.ErrMessageNotFound`.
+func (r OutboxRepository) DeleteOutboxMessage(ctx context.Context, messageID string) error {
+	query := fmt.Sprintf(`DELETE FROM outbox_messages WHERE id = $1`)
+
+	res, err := r.db.ExecContext(ctx, query, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rows affected: %w", err)
+	} else if affected == 0 {
+		return domain.ErrMessageNotFound
+	}
+	return nil
+}
+
+// saveOutboxMessages saves messages to the outbox in the transaction of the change they describe.
+func saveOutboxMessages(ctx context.Context, tx *sqlx.Tx, messages []domain.Message) error {
+	query := fmt.Sprintf(`INSERT INTO outbox_messages (id, subject, payload, created_at) VALUES ($1, $2, $3, $4)`)
+	for _, message := range messages {
+		if _, err := tx.ExecContext(ctx, query, message.ID, message.Subject, message.Payload, message.CreatedAt); err != nil {
+			return fmt.Errorf("failed to execute query: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nazarslota/unotes/auth/internal/domain/outbox"
+	"github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var outboxRepository *OutboxRepository
+
+func init() {
+	db, err := NewPostgreSQL(context.Background(), Config{
+		Host:     "localhost",
+		Port:     "5432",
+		Username: "postgres",
+		Password: "postgres",
+		DBName:   "postgres",
+		SSLMode:  "disable",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	outboxRepository, err = NewOutboxRepository(db)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestNewOutboxRepository(t *testing.T) {
+	t.Run("should return error when db is nil", func(t *testing.T) {
+		repository, err := NewOutboxRepository(nil)
+		assert.EqualError(t, err, "db is nil")
+		assert.Nil(t, repository)
+	})
+}
+
+func TestOutboxRepository(t *testing.T) {
+	t.Run("should save message when user is deleted", func(t *testing.T) {
+		saveUserA(t)
+
+		message := outbox.Message{
+			ID:        "0f8fad5b-d9cb-469f-a165-70867728950e",
+			Subject:   "user.deleted",
+			Payload:   []byte(`{"user_id":"` + userA.ID + `"}`),
+			CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+		}
+		err := repository.DeleteUser(context.Background(), userA.ID, message)
+		require.NoError(t, err)
+
+		_, err = repository.FindUserByUserID(context.Background(), userA.ID)
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+
+		messages, err := outboxRepository.FindOutboxMessages(context.Background(), 10)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Equal(t, message.ID, messages[0].ID)
+		assert.Equal(t, message.Payload, messages[0].Payload)
+		assert.True(t, message.CreatedAt.Equal(messages[0].CreatedAt))
+
+		err = outboxRepository.DeleteOutboxMessage(context.Background(), message.ID)
+		require.NoError(t, err)
+
+		err = outboxRepository.DeleteOutboxMessage(context.Background(), message.ID)
+		assert.ErrorIs(t, err, outbox.ErrMessageNotFound)
+	})
+
+	t.Run("should not save message when user does not exist", func(t *testing.T) {
+		message := outbox.Message{ID: "1f8fad5b-d9cb-469f-a165-70867728950e", Subject: "user.deleted", Payload: []byte(`{}`)}
+		err := repository.DeleteUser(context.Background(), userA.ID, message)
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+
+		messages, err := outboxRepository.FindOutboxMessages(context.Background(), 10)
+		require.NoError(t, err)
+		assert.Empty(t, messages)
+	})
+}
@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	domain "github.com/nazarslota/unotes/auth/internal/domain/passkey"
+)
+
+// PasskeyRepository provides an implementation of the passkey repository for a PostgreSQL database. It stores both
+// the passkeys of users and the WebAuthn ceremonies in progress.
+type PasskeyRepository struct {
+	db *sqlx.DB
+}
+
+// passkeyRow is a row of the passkeys table, which stores the transports as a PostgreSQL array.
+type passkeyRow struct {
+	ID         string         `db:"id"`
+	UserID     string         `db:"user_id"`
+	Name       string         `db:"name"`
+	PublicKey  []byte         `db:"public_key"`
+	SignCount  int64          `db:"sign_count"`
+	Transports pq.StringArray `db:"transports"`
+	CreatedAt  time.Time      `db:"created_at"`
+	LastUsedAt *time.Time     `db:"last_used_at"`
+}
+
+func (row passkeyRow) credential() domain.Credential {
+	return domain.Credential{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		Name:       row.Name,
+		PublicKey:  row.PublicKey,
+		SignCount:  uint32(row.SignCount),
+		Transports: row.Transports,
+		CreatedAt:  row.CreatedAt,
+		LastUsedAt: row.LastUsedAt,
+	}
+}
+
+// NewPasskeyRepository creates a new instance of the PasskeyRepository with the provided handle to the PostgreSQL
+// database.
+//
+// If db is nil, returns an error.
+func NewPasskeyRepository(db *sqlx.DB) (*PasskeyRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &PasskeyRepository{db: db}, nil
+}
+
+// SavePasskey saves a passkey of a user to the PostgreSQL database.
+//
+// If a passkey with the same credential ID is already registered, returns `passkey.ErrCredentialAlreadyExists`.
+func (r PasskeyRepository) SavePasskey(ctx context.Context, credential domain.Credential) error {
+	query := fmt.Sprintf(`INSERT INTO passkeys (id, user_id, name, public_key, sign_count, transports, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)`)
+
+	_, err := r.db.ExecContext(ctx, query,
+		credential.ID, credential.UserID, credential.Name, credential.PublicKey, int64(credential.SignCount),
+		pq.StringArray(credential.Transports), credential.CreatedAt,
+	)
+
+	pqErr := new(pq.Error)
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return errors.Join(err, domain.ErrCredentialAlreadyExists)
+	} else if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
+
+// FindPasskeys finds all passkeys of a user in the PostgreSQL database, oldest first.
+func (r PasskeyRepository) FindPasskeys(ctx context.Context, userID string) ([]domain.Credential, error) {
+	query := fmt.Sprintf(`SELECT * FROM passkeys WHERE user_id = $1 ORDER BY created_at, id`)
+
+	var rows []passkeyRow
+	if err := r.db.SelectContext(ctx, &rows, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	credentials := make([]domain.Credential, 0, len(rows))
+	for _, row := range rows {
+		credentials = append(credentials, row.credential())
+	}
+	return credentials, nil
+}
+
+// FindPasskey finds a passkey in the PostgreSQL database by the credential ID.
+//
+// If the passkey is not found, returns `passkey.ErrCredentialNotFound`.
+func (r PasskeyRepository) FindPasskey(ctx context.Context, credentialID string) (domain.Credential, error) {
+	query := fmt.Sprintf(`SELECT * FROM passkeys WHERE id = $1`)
+
+	var row passkeyRow
+	if err := r.db.GetContext(ctx, &row, query, credentialID); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.Credential{}, errors.Join(err, domain.ErrCredentialNotFound)
+	} else if err != nil {
+		return domain.Credential{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return row.credential(), nil
+}
+
+// UpdatePasskeyUsage sets the signature counter of a passkey and the time it was last used in the PostgreSQL
+// database.
+//
+// If the passkey is not found, returns `passkey.ErrCredentialNotFound`.
+func (r PasskeyRepository) UpdatePasskeyUsage(ctx context.Context, credentialID string, signCount uint32, lastUsedAt time.Time) error {
+	query := fmt.Sprintf(`UPDATE passkeys SET sign_count = $2, last_used_at = $3 WHERE id = $1`)
+
+	res, err := r.db.ExecContext(ctx, query, credentialID, int64(signCount), lastUsedAt)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rows affected: %w", err)
+	} else if affected == 0 {
+		return domain.ErrCredentialNotFound
+	}
+	return nil
+}
+
+// DeletePasskey deletes a passkey of a user from the PostgreSQL database.
+//
+// If the user has no passkey with the credential ID, returns `passkey.ErrCredentialNotFound`.
+func (r PasskeyRepository) DeletePasskey(ctx context.Context, userID, credentialID string) error {
+	query := fmt.Sprintf(`DELETE FROM passkeys WHERE id = $1 AND user_id = $2`)
+
+	res, err := r.db.ExecContext(ctx, query, credentialID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rows affected: %w", err)
+	} else if affected == 0 {
+		return domain.ErrCredentialNotFound
+	}
+	return nil
+}
+
+// SavePasskeySession saves a WebAuthn ceremony in progress to the PostgreSQL database, pruning expired ones along the
+// way.
+func (r PasskeyRepository) SavePasskeySession(ctx context.Context, session domain.Session) error {
+	query := fmt.Sprintf(`DELETE FROM passkey_sessions WHERE expires_at <= now()`)
+	if _, err := r.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	query = fmt.Sprintf(`INSERT INTO passkey_sessions (session_hash, purpose, user_id, data, expires_at)
+VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5)`)
+
+	_, err := r.db.ExecContext(ctx, query,
+		session.SessionHash, session.Purpose, session.UserID, session.Data, session.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
+
+// ConsumePasskeySession deletes a WebAuthn ceremony in progress that has not expired yet and returns it, so every
+// ceremony can be finished only once.
+//
+// If the session is not found or has expired, returns `passkey.ErrSessionNotFound`.
+func (r PasskeyRepository) ConsumePasskeySession(ctx context.Context, sessionHash string) (session domain.Session, err error) {
+	query := fmt.Sprintf(`DELETE FROM passkey_sessions WHERE session_hash = $1 AND expires_at > now()
+RETURNING session_hash, purpose, COALESCE(user_id::text, '') AS user_id, data, expires_at`)
+	if err := r.db.GetContext(ctx, &session, query, sessionHash); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.Session{}, errors.Join(err, domain.ErrSessionNotFound)
+	} else if err != nil {
+		return domain.Session{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return session, nil
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nazarslota/unotes/auth/internal/domain/passkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var passkeyRepository *PasskeyRepository
+
+func init() {
+	db, err := NewPostgreSQL(context.Background(), Config{
+		Host:     "localhost",
+		Port:     "5432",
+		Username: "postgres",
+		Password: "postgres",
+		DBName:   "postgres",
+		SSLMode:  "disable",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	passkeyRepository, err = NewPasskeyRepository(db)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestNewPasskeyRepository(t *testing.T) {
+	t.Run("should return error when db is nil", func(t *testing.T) {
+		repository, err := NewPasskeyRepository(nil)
+		assert.EqualError(t, err, "db is nil")
+		assert.Nil(t, repository)
+	})
+}
+
+func TestPasskeyRepository(t *testing.T) {
+	t.Run("should save, find, use and delete passkey", func(t *testing.T) {
+		saveUserA(t)
+
+		expected := passkey.Credential{
+			ID:         "Y3JlZGVudGlhbC1pZA",
+			UserID:     userA.ID,
+			Name:       "laptop",
+			PublicKey:  []byte("public-key"),
+			SignCount:  1,
+			Transports: []string{"internal", "hybrid"},
+			CreatedAt:  time.Now().UTC().Truncate(time.Microsecond),
+		}
+		err := passkeyRepository.SavePasskey(context.Background(), expected)
+		require.NoError(t, err)
+
+		err = passkeyRepository.SavePasskey(context.Background(), expected)
+		assert.ErrorIs(t, err, passkey.ErrCredentialAlreadyExists)
+
+		actual, err := passkeyRepository.FindPasskey(context.Background(), expected.ID)
+		require.NoError(t, err)
+		assert.Equal(t, expected.UserID, actual.UserID)
+		assert.Equal(t, expected.PublicKey, actual.PublicKey)
+		assert.Equal(t, expected.Transports, actual.Transports)
+		assert.Nil(t, actual.LastUsedAt)
+
+		lastUsedAt := time.Now().UTC().Truncate(time.Microsecond)
+		err = passkeyRepository.UpdatePasskeyUsage(context.Background(), expected.ID, 2, lastUsedAt)
+		require.NoError(t, err)
+
+		credentials, err := passkeyRepository.FindPasskeys(context.Background(), userA.ID)
+		require.NoError(t, err)
+		require.Len(t, credentials, 1)
+		assert.Equal(t, uint32(2), credentials[0].SignCount)
+		require.NotNil(t, credentials[0].LastUsedAt)
+		assert.True(t, lastUsedAt.Equal(*credentials[0].LastUsedAt))
+
+		err = passkeyRepository.DeletePasskey(context.Background(), userA.ID, expected.ID)
+		require.NoError(t, err)
+
+		_, err = passkeyRepository.FindPasskey(context.Background(), expected.ID)
+		assert.ErrorIs(t, err, passkey.ErrCredentialNotFound)
+	})
+
+	t.Run("should return error when passkey does not exist", func(t *testing.T) {
+		err := passkeyRepository.DeletePasskey(context.Background(), userA.ID, "Y3JlZGVudGlhbC1pZA")
+		assert.ErrorIs(t, err, passkey.ErrCredentialNotFound)
+	})
+
+	t.Run("should consume session only once", func(t *testing.T) {
+		saveUserA(t)
+
+		session := passkey.Session{
+			SessionHash: "session-hash",
+			Purpose:     passkey.PurposeRegistration,
+			UserID:      userA.ID,
+			Data:        []byte(`{"challenge":"challenge"}`),
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}
+		err := passkeyRepository.SavePasskeySession(context.Background(), session)
+		require.NoError(t, err)
+
+		result, err := passkeyRepository.ConsumePasskeySession(context.Background(), session.SessionHash)
+		assert.NoError(t, err)
+		assert.Equal(t, session.Purpose, result.Purpose)
+		assert.Equal(t, session.UserID, result.UserID)
+		assert.Equal(t, session.Data, result.Data)
+
+		_, err = passkeyRepository.ConsumePasskeySession(context.Background(), session.SessionHash)
+		assert.ErrorIs(t, err, passkey.ErrSessionNotFound)
+	})
+
+	t.Run("should consume session without user", func(t *testing.T) {
+		session := passkey.Session{
+			SessionHash: "session-hash",
+			Purpose:     passkey.PurposeSignIn,
+			Data:        []byte(`{"challenge":"challenge"}`),
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}
+		err := passkeyRepository.SavePasskeySession(context.Background(), session)
+		require.NoError(t, err)
+
+		result, err := passkeyRepository.ConsumePasskeySession(context.Background(), session.SessionHash)
+		assert.NoError(t, err)
+		assert.Empty(t, result.UserID)
+	})
+
+	t.Run("should not consume expired session", func(t *testing.T) {
+		session := passkey.Session{
+			SessionHash: "session-hash",
+			Purpose:     passkey.PurposeSignIn,
+			Data:        []byte(`{"challenge":"challenge"}`),
+			ExpiresAt:   time.Now().Add(-time.Hour),
+		}
+		err := passkeyRepository.SavePasskeySession(context.Background(), session)
+		require.NoError(t, err)
+
+		_, err = passkeyRepository.ConsumePasskeySession(context.Background(), session.SessionHash)
+		assert.ErrorIs(t, err, passkey.ErrSessionNotFound)
+	})
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	domain "github.com/nazarslota/unotes/auth/internal/domain/passwordreset"
+)
+
+// PasswordResetTokenRepository provides an implementation of the password reset token repository for a PostgreSQL
+// database. Only hashes of the tokens are stored.
+type PasswordResetTokenRepository struct {
+	db *sqlx.DB
+}
+
+// NewPasswordResetTokenRepository creates a new instance of the PasswordResetTokenRepository with the provided handle
+// to the PostgreSQL database.
+//
+// If db is nil, returns an error.
+func NewPasswordResetTokenRepository(db *sqlx.DB) (*PasswordResetTokenRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &PasswordResetTokenRepository{db: db}, nil
+}
+
+// SavePasswordResetToken saves a password reset token to the PostgreSQL database.
+func (r PasswordResetTokenRepository) SavePasswordResetToken(ctx context.Context, token domain.Token) error {
+	query := fmt.Sprintf(`INSERT INTO password_reset_tokens (token_hash, user_id, expires_at) VALUES ($1, $2, $3)`)
+	if _, err := r.db.ExecContext(ctx, query, token.TokenHash, token.UserID, token.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
+
+// FindPasswordResetToken finds a password reset token that has not expired yet, without consuming it.
+//
+// If the token is not found or has expired, returns `passwordreset.ErrTokenNotFound`.
+func (r PasswordResetTokenRepository) FindPasswordResetToken(ctx context.Context, tokenHash string) (token domain.Token, err error) {
+	query := fmt.Sprintf(`SELECT * FROM password_reset_tokens WHERE token_hash = $1 AND expires_at > now()`)
+	if err := r.db.GetContext(ctx, &token, query, tokenHash); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.Token{}, errors.Join(err, domain.ErrTokenNotFound)
+	} else if err != nil {
+		return domain.Token{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return token, nil
+}
+
+// ConsumePasswordResetToken deletes a password reset token that has not expired yet and returns it, so every token
+// can be used only once.
+//
+// If the token is not found or has expired, returns `passwordreset.ErrTokenNotFound`.
+func (r PasswordResetTokenRepository) ConsumePasswordResetToken(ctx context.Context, tokenHash string) (token domain.Token, err error) {
+	query := fmt.Sprintf(`DELETE FROM password_reset_tokens WHERE token_hash = $1 AND expires_at > now() RETURNING *`)
+	if err := r.db.GetContext(ctx, &token, query, tokenHash); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.Token{}, errors.Join(err, domain.ErrTokenNotFound)
+	} else if err != nil {
+		return domain.Token{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return token, nil
+}
+
+// DeletePasswordResetTokens deletes all password reset tokens of a user, pruning expired tokens of every user along
+// the way.
+func (r PasswordResetTokenRepository) DeletePasswordResetTokens(ctx context.Context, userID string) error {
+	query := fmt.Sprintf(`DELETE FROM password_reset_tokens WHERE user_id = $1 OR expires_at <= now()`)
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
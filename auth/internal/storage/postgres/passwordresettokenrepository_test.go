@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nazarslota/unotes/auth/internal/domain/passwordreset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var passwordResetTokenRepository *PasswordResetTokenRepository
+
+func init() {
+	db, err := NewPostgreSQL(context.Background(), Config{
+		Host:     "localhost",
+		Port:     "5432",
+		Username: "postgres",
+		Password: "postgres",
+		DBName:   "postgres",
+		SSLMode:  "disable",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	passwordResetTokenRepository, err = NewPasswordResetTokenRepository(db)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestNewPasswordResetTokenRepository(t *testing.T) {
+	t.Run("should return error when db is nil", func(t *testing.T) {
+		repository, err := NewPasswordResetTokenRepository(nil)
+		assert.EqualError(t, err, "db is nil")
+		assert.Nil(t, repository)
+	})
+}
+
+func TestPasswordResetTokenRepository_FindPasswordResetToken(t *testing.T) {
+	t.Run("should find token without consuming it", func(t *testing.T) {
+		saveUserA(t)
+
+		token := passwordreset.Token{TokenHash: "token-hash", UserID: userA.ID, ExpiresAt: time.Now().Add(time.Hour)}
+		err := passwordResetTokenRepository.SavePasswordResetToken(context.Background(), token)
+		require.NoError(t, err)
+
+		result, err := passwordResetTokenRepository.FindPasswordResetToken(context.Background(), token.TokenHash)
+		assert.NoError(t, err)
+		assert.Equal(t, token.UserID, result.UserID)
+
+		_, err = passwordResetTokenRepository.ConsumePasswordResetToken(context.Background(), token.TokenHash)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should not find expired token", func(t *testing.T) {
+		saveUserA(t)
+
+		token := passwordreset.Token{TokenHash: "token-hash", UserID: userA.ID, ExpiresAt: time.Now().Add(-time.Hour)}
+		err := passwordResetTokenRepository.SavePasswordResetToken(context.Background(), token)
+		require.NoError(t, err)
+
+		_, err = passwordResetTokenRepository.FindPasswordResetToken(context.Background(), token.TokenHash)
+		assert.ErrorIs(t, err, passwordreset.ErrTokenNotFound)
+	})
+}
+
+func TestPasswordResetTokenRepository_ConsumePasswordResetToken(t *testing.T) {
+	t.Run("should consume token only once", func(t *testing.T) {
+		saveUserA(t)
+
+		token := passwordreset.Token{TokenHash: "token-hash", UserID: userA.ID, ExpiresAt: time.Now().Add(time.Hour)}
+		err := passwordResetTokenRepository.SavePasswordResetToken(context.Background(), token)
+		require.NoError(t, err)
+
+		result, err := passwordResetTokenRepository.ConsumePasswordResetToken(context.Background(), token.TokenHash)
+		assert.NoError(t, err)
+		assert.Equal(t, token.UserID, result.UserID)
+
+		_, err = passwordResetTokenRepository.ConsumePasswordResetToken(context.Background(), token.TokenHash)
+		assert.ErrorIs(t, err, passwordreset.ErrTokenNotFound)
+	})
+
+	t.Run("should not consume expired token", func(t *testing.T) {
+		saveUserA(t)
+
+		token := passwordreset.Token{TokenHash: "token-hash", UserID: userA.ID, ExpiresAt: time.Now().Add(-time.Hour)}
+		err := passwordResetTokenRepository.SavePasswordResetToken(context.Background(), token)
+		require.NoError(t, err)
+
+		_, err = passwordResetTokenRepository.ConsumePasswordResetToken(context.Background(), token.TokenHash)
+		assert.ErrorIs(t, err, passwordreset.ErrTokenNotFound)
+	})
+}
+
+func TestPasswordResetTokenRepository_DeletePasswordResetTokens(t *testing.T) {
+	t.Run("should delete all tokens of user", func(t *testing.T) {
+		saveUserA(t)
+
+		token := passwordreset.Token{TokenHash: "token-hash", UserID: userA.ID, ExpiresAt: time.Now().Add(time.Hour)}
+		err := passwordResetTokenRepository.SavePasswordResetToken(context.Background(), token)
+		require.NoError(t, err)
+
+		err = passwordResetTokenRepository.DeletePasswordResetTokens(context.Background(), userA.ID)
+		assert.NoError(t, err)
+
+		_, err = passwordResetTokenRepository.ConsumePasswordResetToken(context.Background(), token.TokenHash)
+		assert.ErrorIs(t, err, passwordreset.ErrTokenNotFound)
+	})
+}
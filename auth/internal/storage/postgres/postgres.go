@@ -9,6 +9,9 @@ import (
 	_ "github.com/lib/pq"
 )
 
+// uniqueViolation is the PostgreSQL error code of a unique constraint violation.
+const uniqueViolation = "23505"
+
 // Config stores the configuration information required to connect to a PostgreSQL database.
 type Config struct {
 	Host     string // Host is the address of the PostgreSQL server.
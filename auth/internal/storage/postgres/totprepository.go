@@ -0,0 +1,172 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	domain "github.com/nazarslota/unotes/auth/internal/domain/totp"
+)
+
+// TOTPRepository provides an implementation of the TOTP and recovery code repository for a PostgreSQL database.
+type TOTPRepository struct {
+	db *sqlx.DB
+}
+
+// NewTOTPRepository creates a new instance of the TOTPRepository with the provided handle to the PostgreSQL database.
+//
+// If db is nil, returns an error.
+func NewTOTPRepository(db *sqlx.DB) (*TOTPRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &TOTPRepository{db: db}, nil
+}
+
+// SaveTOTP saves a user's TOTP secret to the PostgreSQL database, replacing any previously saved one.
+func (r TOTPRepository) SaveTOTP(ctx context.Context, totp domain.TOTP) error {
+	query := fmt.Sprintf(`INSERT INTO user_totp (user_id, secret, enabled, last_used_step) VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, enabled = EXCLUDED.enabled, last_used_step = EXCLUDED.last_used_step`)
+
+	if _, err := r.db.ExecContext(ctx, query, totp.UserID, totp.Secret, totp.Enabled, totp.LastUsedStep); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
+
+// FindTOTPByUserID finds a user's TOTP secret in the PostgreSQL database.
+//
+// If the TOTP secret is not found, returns `totp.ErrTOTPNotFound`.
+func (r TOTPRepository) FindTOTPByUserID(ctx context.Context, userID string) (totp domain.TOTP, err error) {
+	query := fmt.Sprintf(`SELECT * FROM user_totp WHERE user_id = $1`)
+	if err := r.db.GetContext(ctx, &totp, query, userID); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.TOTP{}, errors.Join(err, domain.ErrTOTPNotFound)
+	} else if err != nil {
+		return domain.TOTP{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return totp, nil
+}
+
+// UpdateTOTP updates the state and the last used time step of a user's TOTP secret.
+//
+// If the TOTP secret is not found, returns `totp.ErrTOTPNotFound`.
+func (r TOTPRepository) UpdateTOTP(ctx context.Context, totp domain.TOTP) error {
+	query := fmt.Sprintf(`UPDATE user_totp SET enabled = $2, last_used_step = $3 WHERE user_id = $1`)
+
+	res, err := r.db.ExecContext(ctx, query, totp.UserID, totp.Enabled, totp.LastUsedStep)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rows affected: %w", err)
+	} else if affected == 0 {
+		return domain.ErrTOTPNotFound
+	}
+	return nil
+}
+
+// UpdateTOTPLastUsedStep sets the last used time step of a user's TOTP secret to step only if it is later than the
+// last used one, so that concurrent sign ins can't both use the same code.
+//
+// If the TOTP secret is not found, returns `totp.ErrTOTPNotFound`. If the time step is not later than the last used
+// one, returns `totp.ErrTOTPStepUsed`.
+func (r TOTPRepository) UpdateTOTPLastUsedStep(ctx context.Context, userID string, step int64) error {
+	query := fmt.Sprintf(`UPDATE user_totp SET last_used_step = $2 WHERE user_id = $1 AND last_used_step < $2`)
+
+	res, err := r.db.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rows affected: %w", err)
+	} else if affected != 0 {
+		return nil
+	}
+
+	if _, err := r.FindTOTPByUserID(ctx, userID); err != nil {
+		return err
+	}
+	return domain.ErrTOTPStepUsed
+}
+
+// DeleteTOTP deletes a user's TOTP secret from the PostgreSQL database.
+//
+// If the TOTP secret is not found, returns `totp.ErrTOTPNotFound`.
+func (r TOTPRepository) DeleteTOTP(ctx context.Context, userID string) error {
+	query := fmt.Sprintf(`DELETE FROM user_totp WHERE user_id = $1`)
+
+	res, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rows affected: %w", err)
+	} else if affected == 0 {
+		return domain.ErrTOTPNotFound
+	}
+	return nil
+}
+
+// SaveRecoveryCodes replaces all recovery codes of a user with the given ones in a single transaction.
+func (r TOTPRepository) SaveRecoveryCodes(ctx context.Context, userID string, codes []domain.RecoveryCode) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf(`DELETE FROM user_recovery_codes WHERE user_id = $1`)
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	query = fmt.Sprintf(`INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2)`)
+	for _, code := range codes {
+		if _, err := tx.ExecContext(ctx, query, userID, code.CodeHash); err != nil {
+			return fmt.Errorf("failed to execute query: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// DeleteRecoveryCode deletes a single recovery code, which makes it unusable for any later sign in.
+//
+// If the recovery code is not found, returns `totp.ErrRecoveryCodeNotFound`.
+func (r TOTPRepository) DeleteRecoveryCode(ctx context.Context, code domain.RecoveryCode) error {
+	query := fmt.Sprintf(`DELETE FROM user_recovery_codes WHERE user_id = $1 AND code_hash = $2`)
+
+	res, err := r.db.ExecContext(ctx, query, code.UserID, code.CodeHash)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rows affected: %w", err)
+	} else if affected == 0 {
+		return domain.ErrRecoveryCodeNotFound
+	}
+	return nil
+}
+
+// DeleteRecoveryCodes deletes all recovery codes of a user.
+func (r TOTPRepository) DeleteRecoveryCodes(ctx context.Context, userID string) error {
+	query := fmt.Sprintf(`DELETE FROM user_recovery_codes WHERE user_id = $1`)
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
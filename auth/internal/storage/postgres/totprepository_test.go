@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nazarslota/unotes/auth/internal/domain/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	userATOTP = totp.TOTP{
+		UserID:       userA.ID,
+		Secret:       "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		Enabled:      false,
+		LastUsedStep: 0,
+	}
+	userARecoveryCodeA = totp.RecoveryCode{UserID: userA.ID, CodeHash: "user-a-recovery-code-a-hash"}
+	userARecoveryCodeB = totp.RecoveryCode{UserID: userA.ID, CodeHash: "user-a-recovery-code-b-hash"}
+)
+
+var totpRepository *TOTPRepository
+
+func init() {
+	db, err := NewPostgreSQL(context.Background(), Config{
+		Host:     "localhost",
+		Port:     "5432",
+		Username: "postgres",
+		Password: "postgres",
+		DBName:   "postgres",
+		SSLMode:  "disable",
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	totpRepository, err = NewTOTPRepository(db)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func saveUserA(t *testing.T) {
+	query := fmt.Sprintf(`INSERT INTO users (id, username, password_hash) VALUES ($1, $2, $3) ON CONFLICT (username) DO NOTHING`)
+	_, err := totpRepository.db.Exec(query, userA.ID, userA.Username, userA.PasswordHash)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		query := fmt.Sprintf(`DELETE FROM users WHERE id = $1`)
+		_, _ = totpRepository.db.Exec(query, userA.ID)
+	})
+}
+
+func TestNewTOTPRepository(t *testing.T) {
+	t.Run("should return error when db is nil", func(t *testing.T) {
+		repository, err := NewTOTPRepository(nil)
+		assert.EqualError(t, err, "db is nil")
+		assert.Nil(t, repository)
+	})
+}
+
+func TestTOTPRepository_SaveTOTP(t *testing.T) {
+	t.Run("should successfully save and replace totp", func(t *testing.T) {
+		saveUserA(t)
+
+		err := totpRepository.SaveTOTP(context.Background(), userATOTP)
+		assert.NoError(t, err)
+
+		replaced := userATOTP
+		replaced.Secret = "JBSWY3DPEHPK3PXP"
+		err = totpRepository.SaveTOTP(context.Background(), replaced)
+		assert.NoError(t, err)
+
+		result, err := totpRepository.FindTOTPByUserID(context.Background(), userA.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, replaced, result)
+	})
+
+	t.Run("should return error if context is invalid", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := totpRepository.SaveTOTP(ctx, userATOTP)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestTOTPRepository_FindTOTPByUserID(t *testing.T) {
+	t.Run("should return error if totp does not exist", func(t *testing.T) {
+		result, err := totpRepository.FindTOTPByUserID(context.Background(), userA.ID)
+		assert.ErrorIs(t, err, totp.ErrTOTPNotFound)
+		assert.Empty(t, result)
+	})
+}
+
+func TestTOTPRepository_UpdateTOTP(t *testing.T) {
+	t.Run("should successfully update totp", func(t *testing.T) {
+		saveUserA(t)
+
+		err := totpRepository.SaveTOTP(context.Background(), userATOTP)
+		require.NoError(t, err)
+
+		updated := userATOTP
+		updated.Enabled, updated.LastUsedStep = true, 42
+		err = totpRepository.UpdateTOTP(context.Background(), updated)
+		assert.NoError(t, err)
+
+		result, err := totpRepository.FindTOTPByUserID(context.Background(), userA.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, updated, result)
+	})
+
+	t.Run("should return error if totp does not exist", func(t *testing.T) {
+		err := totpRepository.UpdateTOTP(context.Background(), userATOTP)
+		assert.ErrorIs(t, err, totp.ErrTOTPNotFound)
+	})
+}
+
+func TestTOTPRepository_UpdateTOTPLastUsedStep(t *testing.T) {
+	t.Run("should update only to later time step", func(t *testing.T) {
+		saveUserA(t)
+
+		err := totpRepository.SaveTOTP(context.Background(), userATOTP)
+		require.NoError(t, err)
+
+		err = totpRepository.UpdateTOTPLastUsedStep(context.Background(), userA.ID, 42)
+		assert.NoError(t, err)
+
+		err = totpRepository.UpdateTOTPLastUsedStep(context.Background(), userA.ID, 42)
+		assert.ErrorIs(t, err, totp.ErrTOTPStepUsed)
+
+		result, err := totpRepository.FindTOTPByUserID(context.Background(), userA.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), result.LastUsedStep)
+	})
+
+	t.Run("should return error if totp does not exist", func(t *testing.T) {
+		err := totpRepository.UpdateTOTPLastUsedStep(context.Background(), userA.ID, 42)
+		assert.ErrorIs(t, err, totp.ErrTOTPNotFound)
+	})
+}
+
+func TestTOTPRepository_DeleteTOTP(t *testing.T) {
+	t.Run("should successfully delete totp", func(t *testing.T) {
+		saveUserA(t)
+
+		err := totpRepository.SaveTOTP(context.Background(), userATOTP)
+		require.NoError(t, err)
+
+		err = totpRepository.DeleteTOTP(context.Background(), userA.ID)
+		assert.NoError(t, err)
+
+		_, err = totpRepository.FindTOTPByUserID(context.Background(), userA.ID)
+		assert.ErrorIs(t, err, totp.ErrTOTPNotFound)
+	})
+
+	t.Run("should return error if totp does not exist", func(t *testing.T) {
+		err := totpRepository.DeleteTOTP(context.Background(), userA.ID)
+		assert.ErrorIs(t, err, totp.ErrTOTPNotFound)
+	})
+}
+
+func TestTOTPRepository_RecoveryCodes(t *testing.T) {
+	t.Run("should save and consume recovery codes only once", func(t *testing.T) {
+		saveUserA(t)
+
+		err := totpRepository.SaveRecoveryCodes(context.Background(), userA.ID, []totp.RecoveryCode{userARecoveryCodeA, userARecoveryCodeB})
+		require.NoError(t, err)
+
+		err = totpRepository.DeleteRecoveryCode(context.Background(), userARecoveryCodeA)
+		assert.NoError(t, err)
+
+		err = totpRepository.DeleteRecoveryCode(context.Background(), userARecoveryCodeA)
+		assert.ErrorIs(t, err, totp.ErrRecoveryCodeNotFound)
+
+		err = totpRepository.DeleteRecoveryCodes(context.Background(), userA.ID)
+		assert.NoError(t, err)
+
+		err = totpRepository.DeleteRecoveryCode(context.Background(), userARecoveryCodeB)
+		assert.ErrorIs(t, err, totp.ErrRecoveryCodeNotFound)
+	})
+
+	t.Run("should replace previously saved recovery codes", func(t *testing.T) {
+		saveUserA(t)
+
+		err := totpRepository.SaveRecoveryCodes(context.Background(), userA.ID, []totp.RecoveryCode{userARecoveryCodeA})
+		require.NoError(t, err)
+
+		err = totpRepository.SaveRecoveryCodes(context.Background(), userA.ID, []totp.RecoveryCode{userARecoveryCodeB})
+		require.NoError(t, err)
+
+		err = totpRepository.DeleteRecoveryCode(context.Background(), userARecoveryCodeA)
+		assert.ErrorIs(t, err, totp.ErrRecoveryCodeNotFound)
+
+		err = totpRepository.DeleteRecoveryCode(context.Background(), userARecoveryCodeB)
+		assert.NoError(t, err)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/nazarslota/unotes/auth/internal/storage/storagetest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -16,6 +17,7 @@ var (
 		ID:           "867620c3-d77a-4d96-821c-ca65cbca8318",
 		Username:     "user-a-username",
 		PasswordHash: "user-a-password-hash",
+		Role:         user.RoleUser,
 	}
 )
 
@@ -70,7 +72,7 @@ func TestUserRepository_SaveUser(t *testing.T) {
 		err := repository.SaveUser(context.Background(), userA)
 		assert.NoError(t, err)
 
-		query := fmt.Sprintf(`SELECT * FROM users WHERE users.id = $1`)
+		query := fmt.Sprintf(`SELECT %s FROM users WHERE users.id = $1`, userColumns)
 
 		var result user.User
 		err = repository.db.Get(&result, query, userA.ID)
@@ -190,3 +192,126 @@ func TestUserRepository_FindUserByUsername(t *testing.T) {
 		assert.Empty(t, result)
 	})
 }
+
+func TestUserRepository_UpdateUser(t *testing.T) {
+	t.Run("should successfully update user", func(t *testing.T) {
+		query := fmt.Sprintf(`INSERT INTO users (id, username, password_hash) VALUES ($1, $2, $3) ON CONFLICT (username) DO NOTHING`)
+		_, err := repository.db.Exec(query, userA.ID, userA.Username, userA.PasswordHash)
+		require.NoError(t, err)
+
+		updated := userA
+		updated.PasswordHash = "user-a-new-password-hash"
+		err = repository.UpdateUser(context.Background(), updated)
+		assert.NoError(t, err)
+
+		result, err := repository.FindUserByUserID(context.Background(), userA.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, updated, result)
+
+		t.Cleanup(func() {
+			query := fmt.Sprintf(`DELETE FROM users WHERE id = $1`)
+			_, _ = repository.db.Exec(query, userA.ID)
+		})
+	})
+
+	t.Run("should return error if context is invalid", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := repository.UpdateUser(ctx, userA)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("should return error if user does not exist", func(t *testing.T) {
+		err := repository.UpdateUser(context.Background(), userA)
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+	})
+}
+
+func TestUserRepository_FindUserByEmail(t *testing.T) {
+	t.Run("should find user by email ignoring case", func(t *testing.T) {
+		withEmail := userA
+		withEmail.Email, withEmail.EmailVerified = "User-A@example.com", true
+		err := repository.SaveUser(context.Background(), withEmail)
+		require.NoError(t, err)
+
+		result, err := repository.FindUserByEmail(context.Background(), "user-a@EXAMPLE.com")
+		assert.NoError(t, err)
+		assert.Equal(t, withEmail, result)
+
+		t.Cleanup(func() {
+			query := fmt.Sprintf(`DELETE FROM users WHERE id = $1`)
+			_, _ = repository.db.Exec(query, userA.ID)
+		})
+	})
+
+	t.Run("should return error if email is already used", func(t *testing.T) {
+		withEmail := userA
+		withEmail.Email = "user-a@example.com"
+		err := repository.SaveUser(context.Background(), withEmail)
+		require.NoError(t, err)
+
+		other := user.User{
+			ID:           "5d0c6b7e-3f0c-4c69-9a0e-0c2d8b3f7a51",
+			Username:     "user-b-username",
+			PasswordHash: "user-b-password-hash",
+			Email:        "USER-A@example.com",
+		}
+		err = repository.SaveUser(context.Background(), other)
+		assert.ErrorIs(t, err, user.ErrEmailAlreadyExists)
+
+		t.Cleanup(func() {
+			query := fmt.Sprintf(`DELETE FROM users WHERE id = $1 OR id = $2`)
+			_, _ = repository.db.Exec(query, userA.ID, other.ID)
+		})
+	})
+
+	t.Run("should return error if user does not exist", func(t *testing.T) {
+		result, err := repository.FindUserByEmail(context.Background(), "nobody@example.com")
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+		assert.Empty(t, result)
+	})
+}
+
+func TestUserRepository_Profile(t *testing.T) {
+	t.Run("should find and update profile", func(t *testing.T) {
+		saveUserA(t)
+
+		result, err := repository.FindProfile(context.Background(), userA.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.Profile{UserID: userA.ID, Username: userA.Username, Locale: "en", Timezone: "UTC"}, result)
+
+		updated := user.Profile{
+			UserID:      userA.ID,
+			Username:    userA.Username,
+			DisplayName: "User A",
+			Avatar:      userA.ID + "-avatar",
+			Locale:      "uk-UA",
+			Timezone:    "Europe/Kyiv",
+		}
+		err = repository.UpdateProfile(context.Background(), updated)
+		require.NoError(t, err)
+
+		result, err = repository.FindProfile(context.Background(), userA.ID)
+		require.NoError(t, err)
+		assert.Equal(t, updated, result)
+
+		profiles, err := repository.FindProfiles(context.Background(), []string{
+			userA.ID, "5d0c6b7e-3f0c-4c69-9a0e-0c2d8b3f7a51",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []user.Profile{updated}, profiles)
+	})
+
+	t.Run("should return error if user does not exist", func(t *testing.T) {
+		_, err := repository.FindProfile(context.Background(), userA.ID)
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+
+		err = repository.UpdateProfile(context.Background(), user.Profile{UserID: userA.ID})
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+	})
+}
+
+func TestUserRepository_Conformance(t *testing.T) {
+	storagetest.TestUserRepository(t, repository)
+}
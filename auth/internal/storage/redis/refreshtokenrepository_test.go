@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	"github.com/nazarslota/unotes/auth/internal/storage/storagetest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -363,3 +364,7 @@ func TestRefreshTokenRepository_GetRefreshTokens(t *testing.T) {
 		})
 	})
 }
+
+func TestRefreshTokenRepository_Conformance(t *testing.T) {
+	storagetest.TestRefreshTokenRepository(t, repository)
+}
@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+)
+
+// RevokedTokenRepository is a Redis denylist of revoked tokens, identified by their token ID (jti). Entries expire
+// together with the tokens, so the denylist only holds tokens that would otherwise still be valid.
+type RevokedTokenRepository struct {
+	db *redis.Client
+}
+
+// NewRevokedTokenRepository creates a new RevokedTokenRepository with the provided Redis db.
+//
+// Returns an error if the db is nil.
+func NewRevokedTokenRepository(db *redis.Client) (*RevokedTokenRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("redis db is nil")
+	}
+	return &RevokedTokenRepository{db: db}, nil
+}
+
+// SaveRevokedToken adds the token ID to the denylist until the token expires in expiresIn.
+func (r RevokedTokenRepository) SaveRevokedToken(ctx context.Context, tokenID string, expiresIn time.Duration) error {
+	if err := r.db.Set(ctx, revokedTokenKey(tokenID), 1, expiresIn).Err(); err != nil {
+		return fmt.Errorf("failed to execute set command: %w", err)
+	}
+	return nil
+}
+
+// IsTokenRevoked reports whether the token ID is in the denylist.
+func (r RevokedTokenRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	exists, err := r.db.Exists(ctx, revokedTokenKey(tokenID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to execute exists command: %w", err)
+	}
+	return exists != 0, nil
+}
+
+const revokedTokenPrefix = "revoked-token"
+
+func revokedTokenKey(tokenID string) string {
+	return fmt.Sprintf("%s:%s", revokedTokenPrefix, tokenID)
+}
@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var revokedTokenRepository *RevokedTokenRepository
+
+func init() {
+	db, err := NewRedis(context.Background(), Config{})
+	if err != nil {
+		panic(err)
+	}
+
+	revokedTokenRepository, err = NewRevokedTokenRepository(db)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestNewRevokedTokenRepository(t *testing.T) {
+	t.Run("should return an error if db is nil", func(t *testing.T) {
+		repository, err := NewRevokedTokenRepository(nil)
+		assert.EqualError(t, err, "redis db is nil")
+		assert.Nil(t, repository)
+	})
+}
+
+func TestRevokedTokenRepository_IsTokenRevoked(t *testing.T) {
+	t.Run("should report revoked tokens until they expire", func(t *testing.T) {
+		tokenID := uuid.New().String()
+
+		revoked, err := revokedTokenRepository.IsTokenRevoked(context.Background(), tokenID)
+		require.NoError(t, err)
+		assert.False(t, revoked)
+
+		err = revokedTokenRepository.SaveRevokedToken(context.Background(), tokenID, 100*time.Millisecond)
+		require.NoError(t, err)
+
+		revoked, err = revokedTokenRepository.IsTokenRevoked(context.Background(), tokenID)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+
+		time.Sleep(200 * time.Millisecond)
+
+		revoked, err = revokedTokenRepository.IsTokenRevoked(context.Background(), tokenID)
+		require.NoError(t, err)
+		assert.False(t, revoked)
+	})
+}
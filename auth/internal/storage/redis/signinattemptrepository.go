@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v9"
+	domain "github.com/nazarslota/unotes/auth/internal/domain/lockout"
+)
+
+// SignInAttemptRepository is a Redis repository for counting failed sign in attempts and locking sign in, as well as
+// for counting magic link requests. Keys are arbitrary strings, such as a username or a client IP address.
+type SignInAttemptRepository struct {
+	db *redis.Client
+}
+
+// NewSignInAttemptRepository creates a new SignInAttemptRepository with the provided Redis db.
+//
+// Returns an error if the db is nil.
+func NewSignInAttemptRepository(db *redis.Client) (*SignInAttemptRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("redis db is nil")
+	}
+	return &SignInAttemptRepository{db: db}, nil
+}
+
+// SaveSignInFailure increments the number of failed sign in attempts for the key and returns the new number. The
+// counter expires after window has passed without failures.
+func (r SignInAttemptRepository) SaveSignInFailure(ctx context.Context, key string, window time.Duration) (int64, error) {
+	return r.count(ctx, signInFailuresKey(key), window)
+}
+
+// SaveSignInLock locks sign in for the key for the given duration.
+func (r SignInAttemptRepository) SaveSignInLock(ctx context.Context, key string, duration time.Duration) error {
+	if err := r.db.Set(ctx, signInLockKey(key), 1, duration).Err(); err != nil {
+		return fmt.Errorf("failed to execute set command: %w", err)
+	}
+	return nil
+}
+
+// FindSignInLock returns the time left until sign in for the key is unlocked.
+//
+// If sign in is not locked, returns `lockout.ErrLockNotFound`.
+func (r SignInAttemptRepository) FindSignInLock(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.db.PTTL(ctx, signInLockKey(key)).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, domain.ErrLockNotFound
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to execute pttl command: %w", err)
+	} else if ttl <= 0 {
+		return 0, domain.ErrLockNotFound
+	}
+	return ttl, nil
+}
+
+// DeleteSignInFailures resets the number of failed sign in attempts for the key and unlocks it.
+func (r SignInAttemptRepository) DeleteSignInFailures(ctx context.Context, key string) error {
+	if err := r.db.Del(ctx, signInFailuresKey(key), signInLockKey(key)).Err(); err != nil {
+		return fmt.Errorf("failed to execute del command: %w", err)
+	}
+	return nil
+}
+
+// SaveMagicLinkRequest increments the number of magic link requests for the key and returns the new number. The
+// counter expires after window has passed without requests.
+func (r SignInAttemptRepository) SaveMagicLinkRequest(ctx context.Context, key string, window time.Duration) (int64, error) {
+	return r.count(ctx, magicLinkRequestsKey(key), window)
+}
+
+// count increments the counter stored under the key and sets it to expire after window.
+func (r SignInAttemptRepository) count(ctx context.Context, key string, window time.Duration) (int64, error) {
+	pipe := r.db.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, window)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to execute pipeline: %w", err)
+	}
+	return incr.Val(), nil
+}
+
+const (
+	signInFailuresPrefix    = "sign-in-failures"
+	signInLockPrefix        = "sign-in-lock"
+	magicLinkRequestsPrefix = "magic-link-requests"
+)
+
+func signInFailuresKey(key string) string {
+	return fmt.Sprintf("%s:%s", signInFailuresPrefix, key)
+}
+
+func signInLockKey(key string) string {
+	return fmt.Sprintf("%s:%s", signInLockPrefix, key)
+}
+
+func magicLinkRequestsKey(key string) string {
+	return fmt.Sprintf("%s:%s", magicLinkRequestsPrefix, key)
+}
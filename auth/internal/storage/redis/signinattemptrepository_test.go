@@ -0,0 +1,93 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nazarslota/unotes/auth/internal/domain/lockout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var signInAttemptRepository *SignInAttemptRepository
+
+func init() {
+	db, err := NewRedis(context.Background(), Config{})
+	if err != nil {
+		panic(err)
+	}
+
+	signInAttemptRepository, err = NewSignInAttemptRepository(db)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestNewSignInAttemptRepository(t *testing.T) {
+	t.Run("should return an error if db is nil", func(t *testing.T) {
+		repository, err := NewSignInAttemptRepository(nil)
+		assert.EqualError(t, err, "redis db is nil")
+		assert.Nil(t, repository)
+	})
+}
+
+func TestSignInAttemptRepository_SaveSignInFailure(t *testing.T) {
+	t.Run("should count failures until they are deleted", func(t *testing.T) {
+		t.Cleanup(func() { _ = signInAttemptRepository.DeleteSignInFailures(context.Background(), userAID) })
+
+		failures, err := signInAttemptRepository.SaveSignInFailure(context.Background(), userAID, time.Minute)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, failures)
+
+		failures, err = signInAttemptRepository.SaveSignInFailure(context.Background(), userAID, time.Minute)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, failures)
+
+		err = signInAttemptRepository.DeleteSignInFailures(context.Background(), userAID)
+		require.NoError(t, err)
+
+		failures, err = signInAttemptRepository.SaveSignInFailure(context.Background(), userAID, time.Minute)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, failures)
+	})
+}
+
+func TestSignInAttemptRepository_SaveMagicLinkRequest(t *testing.T) {
+	t.Run("should count requests apart from sign in failures", func(t *testing.T) {
+		key := uuid.New().String()
+		t.Cleanup(func() { _ = signInAttemptRepository.DeleteSignInFailures(context.Background(), key) })
+
+		requests, err := signInAttemptRepository.SaveMagicLinkRequest(context.Background(), key, time.Minute)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, requests)
+
+		failures, err := signInAttemptRepository.SaveSignInFailure(context.Background(), key, time.Minute)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, failures)
+
+		requests, err = signInAttemptRepository.SaveMagicLinkRequest(context.Background(), key, time.Minute)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, requests)
+	})
+}
+
+func TestSignInAttemptRepository_FindSignInLock(t *testing.T) {
+	t.Run("should find lock until it is deleted", func(t *testing.T) {
+		t.Cleanup(func() { _ = signInAttemptRepository.DeleteSignInFailures(context.Background(), userAID) })
+
+		err := signInAttemptRepository.SaveSignInLock(context.Background(), userAID, time.Minute)
+		require.NoError(t, err)
+
+		retryAfter, err := signInAttemptRepository.FindSignInLock(context.Background(), userAID)
+		assert.NoError(t, err)
+		assert.InDelta(t, time.Minute, retryAfter, float64(time.Second))
+
+		err = signInAttemptRepository.DeleteSignInFailures(context.Background(), userAID)
+		require.NoError(t, err)
+
+		_, err = signInAttemptRepository.FindSignInLock(context.Background(), userAID)
+		assert.ErrorIs(t, err, lockout.ErrLockNotFound)
+	})
+}
@@ -5,14 +5,65 @@ import (
 	"github.com/go-redis/redis/v9"
 	"github.com/jmoiron/sqlx"
 
+	storagefilesystem "github.com/nazarslota/unotes/auth/internal/storage/filesystem"
+	storagememory "github.com/nazarslota/unotes/auth/internal/storage/memory"
 	storagepostgres "github.com/nazarslota/unotes/auth/internal/storage/postgres"
 	storageredis "github.com/nazarslota/unotes/auth/internal/storage/redis"
+	storagesqlite "github.com/nazarslota/unotes/auth/internal/storage/sqlite"
 )
 
-// RepositoryProvider is a provider for the PostgresUserRepository and RedisRefreshTokenRepository.
+// RepositoryProvider is a provider for the PostgresUserRepository, PostgresTOTPRepository,
+// PostgresPasswordResetTokenRepository, PostgresMagicLinkTokenRepository, PostgresEmailVerificationTokenRepository,
+// PostgresClientRepository, PostgresAuthorizationCodeRepository, PostgresIdentityRepository, PostgresOutboxRepository,
+// PostgresPersonalAccessTokenRepository, PostgresPasskeyRepository, PostgresAuditEventRepository,
+// RedisRefreshTokenRepository, RedisSignInAttemptRepository, RedisRevokedTokenRepository and
+// FilesystemBlobRepository, for the SQLite repositories that take the place of the PostgreSQL ones when
+// AUTH_DATABASE_DRIVER is sqlite, and for the Memory repositories that take the place of the PostgreSQL and Redis ones
+// when the service runs without databases.
 type RepositoryProvider struct {
-	PostgresUserRepository      *storagepostgres.UserRepository
-	RedisRefreshTokenRepository *storageredis.RefreshTokenRepository
+	PostgresUserRepository                   *storagepostgres.UserRepository
+	PostgresTOTPRepository                   *storagepostgres.TOTPRepository
+	PostgresPasswordResetTokenRepository     *storagepostgres.PasswordResetTokenRepository
+	PostgresMagicLinkTokenRepository         *storagepostgres.MagicLinkTokenRepository
+	PostgresEmailVerificationTokenRepository *storagepostgres.EmailVerificationTokenRepository
+	PostgresClientRepository                 *storagepostgres.ClientRepository
+	PostgresAuthorizationCodeRepository      *storagepostgres.AuthorizationCodeRepository
+	PostgresIdentityRepository               *storagepostgres.IdentityRepository
+	PostgresOutboxRepository                 *storagepostgres.OutboxRepository
+	PostgresPersonalAccessTokenRepository    *storagepostgres.PersonalAccessTokenRepository
+	PostgresPasskeyRepository                *storagepostgres.PasskeyRepository
+	PostgresAuditEventRepository             *storagepostgres.AuditEventRepository
+	SQLiteUserRepository                     *storagesqlite.UserRepository
+	SQLiteTOTPRepository                     *storagesqlite.TOTPRepository
+	SQLitePasswordResetTokenRepository       *storagesqlite.PasswordResetTokenRepository
+	SQLiteMagicLinkTokenRepository           *storagesqlite.MagicLinkTokenRepository
+	SQLiteEmailVerificationTokenRepository   *storagesqlite.EmailVerificationTokenRepository
+	SQLiteClientRepository                   *storagesqlite.ClientRepository
+	SQLiteAuthorizationCodeRepository        *storagesqlite.AuthorizationCodeRepository
+	SQLiteIdentityRepository                 *storagesqlite.IdentityRepository
+	SQLiteOutboxRepository                   *storagesqlite.OutboxRepository
+	SQLitePersonalAccessTokenRepository      *storagesqlite.PersonalAccessTokenRepository
+	SQLitePasskeyRepository                  *storagesqlite.PasskeyRepository
+	SQLiteAuditEventRepository               *storagesqlite.AuditEventRepository
+	RedisRefreshTokenRepository              *storageredis.RefreshTokenRepository
+	RedisSignInAttemptRepository             *storageredis.SignInAttemptRepository
+	RedisRevokedTokenRepository              *storageredis.RevokedTokenRepository
+	FilesystemBlobRepository                 *storagefilesystem.BlobRepository
+	MemoryUserRepository                     *storagememory.UserRepository
+	MemoryTOTPRepository                     *storagememory.TOTPRepository
+	MemoryPasswordResetTokenRepository       *storagememory.PasswordResetTokenRepository
+	MemoryMagicLinkTokenRepository           *storagememory.MagicLinkTokenRepository
+	MemoryEmailVerificationTokenRepository   *storagememory.EmailVerificationTokenRepository
+	MemoryClientRepository                   *storagememory.ClientRepository
+	MemoryAuthorizationCodeRepository        *storagememory.AuthorizationCodeRepository
+	MemoryIdentityRepository                 *storagememory.IdentityRepository
+	MemoryOutboxRepository                   *storagememory.OutboxRepository
+	MemoryPersonalAccessTokenRepository      *storagememory.PersonalAccessTokenRepository
+	MemoryPasskeyRepository                  *storagememory.PasskeyRepository
+	MemoryAuditEventRepository               *storagememory.AuditEventRepository
+	MemoryRefreshTokenRepository             *storagememory.RefreshTokenRepository
+	MemorySignInAttemptRepository            *storagememory.SignInAttemptRepository
+	MemoryRevokedTokenRepository             *storagememory.RevokedTokenRepository
 }
 
 // RepositoryProviderOption is a functional option for the RepositoryProvider.
@@ -36,6 +87,193 @@ func WithPostgreSQLUserRepository(db *sqlx.DB) RepositoryProviderOption {
 	}
 }
 
+// WithPostgreSQLTOTPRepository is a functional option that sets the PostgresTOTPRepository
+// of the RepositoryProvider to a new instance of `postgres.TOTPRepository`.
+func WithPostgreSQLTOTPRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.PostgresTOTPRepository, _ = storagepostgres.NewTOTPRepository(db)
+	}
+}
+
+// WithPostgreSQLPasswordResetTokenRepository is a functional option that sets the PostgresPasswordResetTokenRepository
+// of the RepositoryProvider to a new instance of `postgres.PasswordResetTokenRepository`.
+func WithPostgreSQLPasswordResetTokenRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.PostgresPasswordResetTokenRepository, _ = storagepostgres.NewPasswordResetTokenRepository(db)
+	}
+}
+
+// WithPostgreSQLMagicLinkTokenRepository is a functional option that sets the PostgresMagicLinkTokenRepository of the
+// RepositoryProvider to a new instance of `postgres.MagicLinkTokenRepository`.
+func WithPostgreSQLMagicLinkTokenRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.PostgresMagicLinkTokenRepository, _ = storagepostgres.NewMagicLinkTokenRepository(db)
+	}
+}
+
+// WithPostgreSQLEmailVerificationTokenRepository is a functional option that sets the
+// PostgresEmailVerificationTokenRepository of the RepositoryProvider to a new instance of
+// `postgres.EmailVerificationTokenRepository`.
+func WithPostgreSQLEmailVerificationTokenRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.PostgresEmailVerificationTokenRepository, _ = storagepostgres.NewEmailVerificationTokenRepository(db)
+	}
+}
+
+// WithPostgreSQLClientRepository is a functional option that sets the PostgresClientRepository
+// of the RepositoryProvider to a new instance of `postgres.ClientRepository`.
+func WithPostgreSQLClientRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.PostgresClientRepository, _ = storagepostgres.NewClientRepository(db)
+	}
+}
+
+// WithPostgreSQLAuthorizationCodeRepository is a functional option that sets the PostgresAuthorizationCodeRepository
+// of the RepositoryProvider to a new instance of `postgres.AuthorizationCodeRepository`.
+func WithPostgreSQLAuthorizationCodeRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.PostgresAuthorizationCodeRepository, _ = storagepostgres.NewAuthorizationCodeRepository(db)
+	}
+}
+
+// WithPostgreSQLIdentityRepository is a functional option that sets the PostgresIdentityRepository
+// of the RepositoryProvider to a new instance of `postgres.IdentityRepository`.
+func WithPostgreSQLIdentityRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.PostgresIdentityRepository, _ = storagepostgres.NewIdentityRepository(db)
+	}
+}
+
+// WithPostgreSQLOutboxRepository is a functional option that sets the PostgresOutboxRepository
+// of the RepositoryProvider to a new instance of `postgres.OutboxRepository`.
+func WithPostgreSQLOutboxRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.PostgresOutboxRepository, _ = storagepostgres.NewOutboxRepository(db)
+	}
+}
+
+// WithPostgreSQLPersonalAccessTokenRepository is a functional option that sets the
+// PostgresPersonalAccessTokenRepository of the RepositoryProvider to a new instance of
+// `postgres.PersonalAccessTokenRepository`.
+func WithPostgreSQLPersonalAccessTokenRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.PostgresPersonalAccessTokenRepository, _ = storagepostgres.NewPersonalAccessTokenRepository(db)
+	}
+}
+
+// WithPostgreSQLPasskeyRepository is a functional option that sets the PostgresPasskeyRepository of the
+// RepositoryProvider to a new instance of `postgres.PasskeyRepository`.
+func WithPostgreSQLPasskeyRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.PostgresPasskeyRepository, _ = storagepostgres.NewPasskeyRepository(db)
+	}
+}
+
+// WithPostgreSQLAuditEventRepository is a functional option that sets the PostgresAuditEventRepository of the
+// RepositoryProvider to a new instance of `postgres.AuditEventRepository`.
+func WithPostgreSQLAuditEventRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.PostgresAuditEventRepository, _ = storagepostgres.NewAuditEventRepository(db)
+	}
+}
+
+// WithSQLiteUserRepository is a functional option that sets the SQLiteUserRepository of the RepositoryProvider to a new
+// instance of `sqlite.UserRepository`.
+func WithSQLiteUserRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.SQLiteUserRepository, _ = storagesqlite.NewUserRepository(db)
+	}
+}
+
+// WithSQLiteTOTPRepository is a functional option that sets the SQLiteTOTPRepository of the RepositoryProvider to a new
+// instance of `sqlite.TOTPRepository`.
+func WithSQLiteTOTPRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.SQLiteTOTPRepository, _ = storagesqlite.NewTOTPRepository(db)
+	}
+}
+
+// WithSQLitePasswordResetTokenRepository is a functional option that sets the SQLitePasswordResetTokenRepository of the
+// RepositoryProvider to a new instance of `sqlite.PasswordResetTokenRepository`.
+func WithSQLitePasswordResetTokenRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.SQLitePasswordResetTokenRepository, _ = storagesqlite.NewPasswordResetTokenRepository(db)
+	}
+}
+
+// WithSQLiteMagicLinkTokenRepository is a functional option that sets the SQLiteMagicLinkTokenRepository of the
+// RepositoryProvider to a new instance of `sqlite.MagicLinkTokenRepository`.
+func WithSQLiteMagicLinkTokenRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.SQLiteMagicLinkTokenRepository, _ = storagesqlite.NewMagicLinkTokenRepository(db)
+	}
+}
+
+// WithSQLiteEmailVerificationTokenRepository is a functional option that sets the
+// SQLiteEmailVerificationTokenRepository of the RepositoryProvider to a new instance of
+// `sqlite.EmailVerificationTokenRepository`.
+func WithSQLiteEmailVerificationTokenRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.SQLiteEmailVerificationTokenRepository, _ = storagesqlite.NewEmailVerificationTokenRepository(db)
+	}
+}
+
+// WithSQLiteClientRepository is a functional option that sets the SQLiteClientRepository of the RepositoryProvider to a
+// new instance of `sqlite.ClientRepository`.
+func WithSQLiteClientRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.SQLiteClientRepository, _ = storagesqlite.NewClientRepository(db)
+	}
+}
+
+// WithSQLiteAuthorizationCodeRepository is a functional option that sets the SQLiteAuthorizationCodeRepository of the
+// RepositoryProvider to a new instance of `sqlite.AuthorizationCodeRepository`.
+func WithSQLiteAuthorizationCodeRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.SQLiteAuthorizationCodeRepository, _ = storagesqlite.NewAuthorizationCodeRepository(db)
+	}
+}
+
+// WithSQLiteIdentityRepository is a functional option that sets the SQLiteIdentityRepository of the RepositoryProvider
+// to a new instance of `sqlite.IdentityRepository`.
+func WithSQLiteIdentityRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.SQLiteIdentityRepository, _ = storagesqlite.NewIdentityRepository(db)
+	}
+}
+
+// WithSQLiteOutboxRepository is a functional option that sets the SQLiteOutboxRepository of the RepositoryProvider to a
+// new instance of `sqlite.OutboxRepository`.
+func WithSQLiteOutboxRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.SQLiteOutboxRepository, _ = storagesqlite.NewOutboxRepository(db)
+	}
+}
+
+// WithSQLitePersonalAccessTokenRepository is a functional option that sets the SQLitePersonalAccessTokenRepository of
+// the RepositoryProvider to a new instance of `sqlite.PersonalAccessTokenRepository`.
+func WithSQLitePersonalAccessTokenRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.SQLitePersonalAccessTokenRepository, _ = storagesqlite.NewPersonalAccessTokenRepository(db)
+	}
+}
+
+// WithSQLitePasskeyRepository is a functional option that sets the SQLitePasskeyRepository of the
+// RepositoryProvider to a new instance of `sqlite.PasskeyRepository`.
+func WithSQLitePasskeyRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.SQLitePasskeyRepository, _ = storagesqlite.NewPasskeyRepository(db)
+	}
+}
+
+// WithSQLiteAuditEventRepository is a functional option that sets the SQLiteAuditEventRepository of the
+// RepositoryProvider to a new instance of `sqlite.AuditEventRepository`.
+func WithSQLiteAuditEventRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.SQLiteAuditEventRepository, _ = storagesqlite.NewAuditEventRepository(db)
+	}
+}
+
 // WithRedisRefreshTokenRepository is a functional option that sets the RedisRefreshTokenRepository
 // of the RepositoryProvider to a new instance of `redis.RefreshTokenRepository`.
 func WithRedisRefreshTokenRepository(db *redis.Client) RepositoryProviderOption {
@@ -43,3 +281,148 @@ func WithRedisRefreshTokenRepository(db *redis.Client) RepositoryProviderOption
 		rp.RedisRefreshTokenRepository, _ = storageredis.NewRefreshTokenRepository(db)
 	}
 }
+
+// WithRedisSignInAttemptRepository is a functional option that sets the RedisSignInAttemptRepository
+// of the RepositoryProvider to a new instance of `redis.SignInAttemptRepository`.
+func WithRedisSignInAttemptRepository(db *redis.Client) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.RedisSignInAttemptRepository, _ = storageredis.NewSignInAttemptRepository(db)
+	}
+}
+
+// WithRedisRevokedTokenRepository is a functional option that sets the RedisRevokedTokenRepository
+// of the RepositoryProvider to a new instance of `redis.RevokedTokenRepository`.
+func WithRedisRevokedTokenRepository(db *redis.Client) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.RedisRevokedTokenRepository, _ = storageredis.NewRevokedTokenRepository(db)
+	}
+}
+
+// WithFilesystemBlobRepository is a functional option that sets the FilesystemBlobRepository
+// of the RepositoryProvider to a new instance of `filesystem.BlobRepository`.
+func WithFilesystemBlobRepository(dir string) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.FilesystemBlobRepository, _ = storagefilesystem.NewBlobRepository(dir)
+	}
+}
+
+// WithMemoryUserRepository is a functional option that sets the MemoryUserRepository
+// of the RepositoryProvider to a new instance of `memory.UserRepository`.
+func WithMemoryUserRepository(db *storagememory.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemoryUserRepository, _ = storagememory.NewUserRepository(db)
+	}
+}
+
+// WithMemoryTOTPRepository is a functional option that sets the MemoryTOTPRepository
+// of the RepositoryProvider to a new instance of `memory.TOTPRepository`.
+func WithMemoryTOTPRepository(db *storagememory.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemoryTOTPRepository, _ = storagememory.NewTOTPRepository(db)
+	}
+}
+
+// WithMemoryPasswordResetTokenRepository is a functional option that sets the MemoryPasswordResetTokenRepository
+// of the RepositoryProvider to a new instance of `memory.PasswordResetTokenRepository`.
+func WithMemoryPasswordResetTokenRepository(db *storagememory.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemoryPasswordResetTokenRepository, _ = storagememory.NewPasswordResetTokenRepository(db)
+	}
+}
+
+// WithMemoryMagicLinkTokenRepository is a functional option that sets the MemoryMagicLinkTokenRepository of the
+// RepositoryProvider to a new instance of `memory.MagicLinkTokenRepository`.
+func WithMemoryMagicLinkTokenRepository(db *storagememory.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemoryMagicLinkTokenRepository, _ = storagememory.NewMagicLinkTokenRepository(db)
+	}
+}
+
+// WithMemoryEmailVerificationTokenRepository is a functional option that sets the
+// MemoryEmailVerificationTokenRepository of the RepositoryProvider to a new instance of
+// `memory.EmailVerificationTokenRepository`.
+func WithMemoryEmailVerificationTokenRepository(db *storagememory.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemoryEmailVerificationTokenRepository, _ = storagememory.NewEmailVerificationTokenRepository(db)
+	}
+}
+
+// WithMemoryClientRepository is a functional option that sets the MemoryClientRepository
+// of the RepositoryProvider to a new instance of `memory.ClientRepository`.
+func WithMemoryClientRepository(db *storagememory.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemoryClientRepository, _ = storagememory.NewClientRepository(db)
+	}
+}
+
+// WithMemoryAuthorizationCodeRepository is a functional option that sets the MemoryAuthorizationCodeRepository
+// of the RepositoryProvider to a new instance of `memory.AuthorizationCodeRepository`.
+func WithMemoryAuthorizationCodeRepository(db *storagememory.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemoryAuthorizationCodeRepository, _ = storagememory.NewAuthorizationCodeRepository(db)
+	}
+}
+
+// WithMemoryIdentityRepository is a functional option that sets the MemoryIdentityRepository
+// of the RepositoryProvider to a new instance of `memory.IdentityRepository`.
+func WithMemoryIdentityRepository(db *storagememory.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemoryIdentityRepository, _ = storagememory.NewIdentityRepository(db)
+	}
+}
+
+// WithMemoryOutboxRepository is a functional option that sets the MemoryOutboxRepository
+// of the RepositoryProvider to a new instance of `memory.OutboxRepository`.
+func WithMemoryOutboxRepository(db *storagememory.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemoryOutboxRepository, _ = storagememory.NewOutboxRepository(db)
+	}
+}
+
+// WithMemoryPersonalAccessTokenRepository is a functional option that sets the MemoryPersonalAccessTokenRepository
+// of the RepositoryProvider to a new instance of `memory.PersonalAccessTokenRepository`.
+func WithMemoryPersonalAccessTokenRepository(db *storagememory.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemoryPersonalAccessTokenRepository, _ = storagememory.NewPersonalAccessTokenRepository(db)
+	}
+}
+
+// WithMemoryPasskeyRepository is a functional option that sets the MemoryPasskeyRepository of the
+// RepositoryProvider to a new instance of `memory.PasskeyRepository`.
+func WithMemoryPasskeyRepository(db *storagememory.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemoryPasskeyRepository, _ = storagememory.NewPasskeyRepository(db)
+	}
+}
+
+// WithMemoryAuditEventRepository is a functional option that sets the MemoryAuditEventRepository
+// of the RepositoryProvider to a new instance of `memory.AuditEventRepository`.
+func WithMemoryAuditEventRepository(db *storagememory.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemoryAuditEventRepository, _ = storagememory.NewAuditEventRepository(db)
+	}
+}
+
+// WithMemoryRefreshTokenRepository is a functional option that sets the MemoryRefreshTokenRepository
+// of the RepositoryProvider to a new instance of `memory.RefreshTokenRepository`.
+func WithMemoryRefreshTokenRepository(db *storagememory.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemoryRefreshTokenRepository, _ = storagememory.NewRefreshTokenRepository(db)
+	}
+}
+
+// WithMemorySignInAttemptRepository is a functional option that sets the MemorySignInAttemptRepository
+// of the RepositoryProvider to a new instance of `memory.SignInAttemptRepository`.
+func WithMemorySignInAttemptRepository(db *storagememory.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemorySignInAttemptRepository, _ = storagememory.NewSignInAttemptRepository(db)
+	}
+}
+
+// WithMemoryRevokedTokenRepository is a functional option that sets the MemoryRevokedTokenRepository
+// of the RepositoryProvider to a new instance of `memory.RevokedTokenRepository`.
+func WithMemoryRevokedTokenRepository(db *storagememory.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemoryRevokedTokenRepository, _ = storagememory.NewRevokedTokenRepository(db)
+	}
+}
@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nazarslota/unotes/auth/internal/domain/authorizationcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var authorizationCodeRepository *AuthorizationCodeRepository
+
+func init() {
+	var err error
+	authorizationCodeRepository, err = NewAuthorizationCodeRepository(testDB)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestNewAuthorizationCodeRepository(t *testing.T) {
+	t.Run("should return error when db is nil", func(t *testing.T) {
+		repository, err := NewAuthorizationCodeRepository(nil)
+		assert.EqualError(t, err, "db is nil")
+		assert.Nil(t, repository)
+	})
+}
+
+func TestAuthorizationCodeRepository_ConsumeAuthorizationCode(t *testing.T) {
+	t.Run("should consume code only once", func(t *testing.T) {
+		saveUserA(t)
+		saveClientA(t)
+
+		code := authorizationcode.Code{
+			CodeHash:      "code-hash",
+			ClientID:      clientA.ID,
+			UserID:        userA.ID,
+			RedirectURI:   clientA.RedirectURIs[0],
+			Scope:         "notes:read",
+			CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+			Nonce:         "nonce",
+			ExpiresAt:     time.Now().Add(time.Hour).Truncate(time.Microsecond),
+		}
+		err := authorizationCodeRepository.SaveAuthorizationCode(context.Background(), code)
+		require.NoError(t, err)
+
+		result, err := authorizationCodeRepository.ConsumeAuthorizationCode(context.Background(), code.CodeHash)
+		assert.NoError(t, err)
+		assert.Equal(t, code.Scope, result.Scope)
+		assert.Equal(t, code.CodeChallenge, result.CodeChallenge)
+		assert.Equal(t, code.Nonce, result.Nonce)
+
+		_, err = authorizationCodeRepository.ConsumeAuthorizationCode(context.Background(), code.CodeHash)
+		assert.ErrorIs(t, err, authorizationcode.ErrCodeNotFound)
+	})
+
+	t.Run("should not consume expired code", func(t *testing.T) {
+		saveUserA(t)
+		saveClientA(t)
+
+		code := authorizationcode.Code{
+			CodeHash:      "code-hash",
+			ClientID:      clientA.ID,
+			UserID:        userA.ID,
+			RedirectURI:   clientA.RedirectURIs[0],
+			CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+			ExpiresAt:     time.Now().Add(-time.Hour),
+		}
+		err := authorizationCodeRepository.SaveAuthorizationCode(context.Background(), code)
+		require.NoError(t, err)
+
+		_, err = authorizationCodeRepository.ConsumeAuthorizationCode(context.Background(), code.CodeHash)
+		assert.ErrorIs(t, err, authorizationcode.ErrCodeNotFound)
+	})
+}
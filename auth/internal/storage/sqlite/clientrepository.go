@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	domain "github.com/nazarslota/unotes/auth/internal/domain/client"
+)
+
+// ClientRepository provides an implementation of the OAuth2 client repository for a SQLite database. Only hashes
+// of the client secrets are stored.
+type ClientRepository struct {
+	db *sqlx.DB
+}
+
+// clientRow is a row of the oauth2_clients table, which stores lists as JSON arrays.
+type clientRow struct {
+	ID           string     `db:"id"`
+	SecretHash   string     `db:"secret_hash"`
+	Name         string     `db:"name"`
+	RedirectURIs stringList `db:"redirect_uris"`
+	Scopes       stringList `db:"scopes"`
+	GrantTypes   stringList `db:"grant_types"`
+	Public       bool       `db:"public"`
+}
+
+// NewClientRepository creates a new instance of the ClientRepository with the provided handle to the SQLite
+// database.
+//
+// If db is nil, returns an error.
+func NewClientRepository(db *sqlx.DB) (*ClientRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &ClientRepository{db: db}, nil
+}
+
+// SaveClient saves a client to the SQLite database.
+//
+// If a client with the same ID already exists, returns `client.ErrClientAlreadyExists`.
+func (r ClientRepository) SaveClient(ctx context.Context, client domain.Client) error {
+	query := fmt.Sprintf(`INSERT INTO oauth2_clients (id, secret_hash, name, redirect_uris, scopes, grant_types, public)
+VALUES ($1, $2, $3, $4, $5, $6, $7)`)
+
+	_, err := r.db.ExecContext(ctx, query,
+		client.ID, client.SecretHash, client.Name,
+		stringList(client.RedirectURIs), stringList(client.Scopes), stringList(client.GrantTypes),
+		client.Public,
+	)
+
+	if isUniqueViolation(err) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return errors.Join(err, domain.ErrClientAlreadyExists)
+	} else if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
+
+// FindClientByClientID finds a client in the SQLite database.
+//
+// If the client is not found, returns `client.ErrClientNotFound`.
+func (r ClientRepository) FindClientByClientID(ctx context.Context, clientID string) (domain.Client, error) {
+	query := fmt.Sprintf(`SELECT * FROM oauth2_clients WHERE id = $1`)
+
+	var row clientRow
+	if err := r.db.GetContext(ctx, &row, query, clientID); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.Client{}, errors.Join(err, domain.ErrClientNotFound)
+	} else if err != nil {
+		return domain.Client{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	return domain.Client{
+		ID:           row.ID,
+		SecretHash:   row.SecretHash,
+		Name:         row.Name,
+		RedirectURIs: row.RedirectURIs,
+		Scopes:       row.Scopes,
+		GrantTypes:   row.GrantTypes,
+		Public:       row.Public,
+	}, nil
+}
@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nazarslota/unotes/auth/internal/domain/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	clientA = client.Client{
+		ID:           "client-a",
+		SecretHash:   "client-a-secret-hash",
+		Name:         "Client A",
+		RedirectURIs: []string{"https://client-a.example.com/callback"},
+		Scopes:       []string{"notes:read", "notes:write"},
+		GrantTypes:   []string{"authorization_code", "refresh_token"},
+		Public:       false,
+	}
+)
+
+var clientRepository *ClientRepository
+
+func init() {
+	var err error
+	clientRepository, err = NewClientRepository(testDB)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func saveClientA(t *testing.T) {
+	err := clientRepository.SaveClient(context.Background(), clientA)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		query := fmt.Sprintf(`DELETE FROM oauth2_clients WHERE id = $1`)
+		_, _ = clientRepository.db.Exec(query, clientA.ID)
+	})
+}
+
+func TestNewClientRepository(t *testing.T) {
+	t.Run("should return error when db is nil", func(t *testing.T) {
+		repository, err := NewClientRepository(nil)
+		assert.EqualError(t, err, "db is nil")
+		assert.Nil(t, repository)
+	})
+}
+
+func TestClientRepository_SaveClient(t *testing.T) {
+	t.Run("should return error when client already exists", func(t *testing.T) {
+		saveClientA(t)
+
+		err := clientRepository.SaveClient(context.Background(), clientA)
+		assert.ErrorIs(t, err, client.ErrClientAlreadyExists)
+	})
+}
+
+func TestClientRepository_FindClientByClientID(t *testing.T) {
+	t.Run("should find client", func(t *testing.T) {
+		saveClientA(t)
+
+		result, err := clientRepository.FindClientByClientID(context.Background(), clientA.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, clientA, result)
+	})
+
+	t.Run("should return error when client is not found", func(t *testing.T) {
+		_, err := clientRepository.FindClientByClientID(context.Background(), "unknown-client")
+		assert.ErrorIs(t, err, client.ErrClientNotFound)
+	})
+}
@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	domain "github.com/nazarslota/unotes/auth/internal/domain/emailverification"
+)
+
+// EmailVerificationTokenRepository provides an implementation of the email verification token repository for a
+// SQLite database. Only hashes of the tokens are stored.
+type EmailVerificationTokenRepository struct {
+	db *sqlx.DB
+}
+
+// NewEmailVerificationTokenRepository creates a new instance of the EmailVerificationTokenRepository with the provided
+// handle to the SQLite database.
+//
+// If db is nil, returns an error.
+func NewEmailVerificationTokenRepository(db *sqlx.DB) (*EmailVerificationTokenRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &EmailVerificationTokenRepository{db: db}, nil
+}
+
+// SaveEmailVerificationToken saves an email verification token to the SQLite database.
+func (r EmailVerificationTokenRepository) SaveEmailVerificationToken(ctx context.Context, token domain.Token) error {
+	query := fmt.Sprintf(`INSERT INTO email_verification_tokens (token_hash, user_id, email, expires_at) VALUES ($1, $2, $3, $4)`)
+	_, err := r.db.ExecContext(ctx, query, token.TokenHash, token.UserID, token.Email, token.ExpiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
+
+// ConsumeEmailVerificationToken deletes an email verification token that has not expired yet and returns it, so
+// every token can be used only once.
+//
+// If the token is not found or has expired, returns `emailverification.ErrTokenNotFound`.
+func (r EmailVerificationTokenRepository) ConsumeEmailVerificationToken(ctx context.Context, tokenHash string) (token domain.Token, err error) {
+	query := fmt.Sprintf(`DELETE FROM email_verification_tokens WHERE token_hash = $1 AND expires_at > $2 RETURNING *`)
+	if err := r.db.GetContext(ctx, &token, query, tokenHash, now()); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.Token{}, errors.Join(err, domain.ErrTokenNotFound)
+	} else if err != nil {
+		return domain.Token{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return token, nil
+}
+
+// DeleteEmailVerificationTokens deletes all email verification tokens of a user, pruning expired tokens of every user
+// along the way.
+func (r EmailVerificationTokenRepository) DeleteEmailVerificationTokens(ctx context.Context, userID string) error {
+	query := fmt.Sprintf(`DELETE FROM email_verification_tokens WHERE user_id = $1 OR expires_at <= $2`)
+	if _, err := r.db.ExecContext(ctx, query, userID, now()); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
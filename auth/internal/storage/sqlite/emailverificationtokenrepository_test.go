@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nazarslota/unotes/auth/internal/domain/emailverification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var emailVerificationTokenRepository *EmailVerificationTokenRepository
+
+func init() {
+	var err error
+	emailVerificationTokenRepository, err = NewEmailVerificationTokenRepository(testDB)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestNewEmailVerificationTokenRepository(t *testing.T) {
+	t.Run("should return error when db is nil", func(t *testing.T) {
+		repository, err := NewEmailVerificationTokenRepository(nil)
+		assert.EqualError(t, err, "db is nil")
+		assert.Nil(t, repository)
+	})
+}
+
+func TestEmailVerificationTokenRepository_ConsumeEmailVerificationToken(t *testing.T) {
+	t.Run("should consume token only once", func(t *testing.T) {
+		saveUserA(t)
+
+		token := emailverification.Token{TokenHash: "token-hash", UserID: userA.ID, Email: "user-a@example.com", ExpiresAt: time.Now().Add(time.Hour)}
+		err := emailVerificationTokenRepository.SaveEmailVerificationToken(context.Background(), token)
+		require.NoError(t, err)
+
+		result, err := emailVerificationTokenRepository.ConsumeEmailVerificationToken(context.Background(), token.TokenHash)
+		assert.NoError(t, err)
+		assert.Equal(t, token.UserID, result.UserID)
+		assert.Equal(t, token.Email, result.Email)
+
+		_, err = emailVerificationTokenRepository.ConsumeEmailVerificationToken(context.Background(), token.TokenHash)
+		assert.ErrorIs(t, err, emailverification.ErrTokenNotFound)
+	})
+
+	t.Run("should not consume expired token", func(t *testing.T) {
+		saveUserA(t)
+
+		token := emailverification.Token{TokenHash: "token-hash", UserID: userA.ID, Email: "user-a@example.com", ExpiresAt: time.Now().Add(-time.Hour)}
+		err := emailVerificationTokenRepository.SaveEmailVerificationToken(context.Background(), token)
+		require.NoError(t, err)
+
+		_, err = emailVerificationTokenRepository.ConsumeEmailVerificationToken(context.Background(), token.TokenHash)
+		assert.ErrorIs(t, err, emailverification.ErrTokenNotFound)
+	})
+}
+
+func TestEmailVerificationTokenRepository_DeleteEmailVerificationTokens(t *testing.T) {
+	t.Run("should delete all tokens of user", func(t *testing.T) {
+		saveUserA(t)
+
+		token := emailverification.Token{TokenHash: "token-hash", UserID: userA.ID, Email: "user-a@example.com", ExpiresAt: time.Now().Add(time.Hour)}
+		err := emailVerificationTokenRepository.SaveEmailVerificationToken(context.Background(), token)
+		require.NoError(t, err)
+
+		err = emailVerificationTokenRepository.DeleteEmailVerificationTokens(context.Background(), userA.ID)
+		assert.NoError(t, err)
+
+		_, err = emailVerificationTokenRepository.ConsumeEmailVerificationToken(context.Background(), token.TokenHash)
+		assert.ErrorIs(t, err, emailverification.ErrTokenNotFound)
+	})
+}
@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	domain "github.com/nazarslota/unotes/auth/internal/domain/magiclink"
+)
+
+// MagicLinkTokenRepository provides an implementation of the magic link token repository for a SQLite
+// database. Only hashes of the tokens are stored.
+type MagicLinkTokenRepository struct {
+	db *sqlx.DB
+}
+
+// NewMagicLinkTokenRepository creates a new instance of the MagicLinkTokenRepository with the provided handle
+// to the SQLite database.
+//
+// If db is nil, returns an error.
+func NewMagicLinkTokenRepository(db *sqlx.DB) (*MagicLinkTokenRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &MagicLinkTokenRepository{db: db}, nil
+}
+
+// SaveMagicLinkToken saves a magic link token to the SQLite database.
+func (r MagicLinkTokenRepository) SaveMagicLinkToken(ctx context.Context, token domain.Token) error {
+	query := fmt.Sprintf(`INSERT INTO magic_link_tokens (token_hash, user_id, nonce_hash, expires_at)
+VALUES ($1, $2, $3, $4)`)
+
+	_, err := r.db.ExecContext(ctx, query, token.TokenHash, token.UserID, token.NonceHash, token.ExpiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
+
+// ConsumeMagicLinkToken deletes a magic link token that has not expired yet and returns it, so every token
+// can be used only once.
+//
+// If the token is not found or has expired, returns `magiclink.ErrTokenNotFound`.
+func (r MagicLinkTokenRepository) ConsumeMagicLinkToken(ctx context.Context, tokenHash string) (token domain.Token, err error) {
+	query := fmt.Sprintf(`DELETE FROM magic_link_tokens WHERE token_hash = $1 AND expires_at > $2 RETURNING *`)
+	if err := r.db.GetContext(ctx, &token, query, tokenHash, now()); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.Token{}, errors.Join(err, domain.ErrTokenNotFound)
+	} else if err != nil {
+		return domain.Token{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return token, nil
+}
+
+// DeleteMagicLinkTokens deletes all magic link tokens of a user, pruning expired tokens of every user along
+// the way.
+func (r MagicLinkTokenRepository) DeleteMagicLinkTokens(ctx context.Context, userID string) error {
+	query := fmt.Sprintf(`DELETE FROM magic_link_tokens WHERE user_id = $1 OR expires_at <= $2`)
+	if _, err := r.db.ExecContext(ctx, query, userID, now()); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nazarslota/unotes/auth/internal/domain/magiclink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var magicLinkTokenRepository *MagicLinkTokenRepository
+
+func init() {
+	var err error
+	magicLinkTokenRepository, err = NewMagicLinkTokenRepository(testDB)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestNewMagicLinkTokenRepository(t *testing.T) {
+	t.Run("should return error when db is nil", func(t *testing.T) {
+		repository, err := NewMagicLinkTokenRepository(nil)
+		assert.EqualError(t, err, "db is nil")
+		assert.Nil(t, repository)
+	})
+}
+
+func TestMagicLinkTokenRepository_ConsumeMagicLinkToken(t *testing.T) {
+	t.Run("should consume token only once", func(t *testing.T) {
+		saveUserA(t)
+
+		token := magiclink.Token{TokenHash: "token-hash", UserID: userA.ID, ExpiresAt: time.Now().Add(time.Hour)}
+		err := magicLinkTokenRepository.SaveMagicLinkToken(context.Background(), token)
+		require.NoError(t, err)
+
+		result, err := magicLinkTokenRepository.ConsumeMagicLinkToken(context.Background(), token.TokenHash)
+		assert.NoError(t, err)
+		assert.Equal(t, token.UserID, result.UserID)
+
+		_, err = magicLinkTokenRepository.ConsumeMagicLinkToken(context.Background(), token.TokenHash)
+		assert.ErrorIs(t, err, magiclink.ErrTokenNotFound)
+	})
+
+	t.Run("should not consume expired token", func(t *testing.T) {
+		saveUserA(t)
+
+		token := magiclink.Token{TokenHash: "token-hash", UserID: userA.ID, ExpiresAt: time.Now().Add(-time.Hour)}
+		err := magicLinkTokenRepository.SaveMagicLinkToken(context.Background(), token)
+		require.NoError(t, err)
+
+		_, err = magicLinkTokenRepository.ConsumeMagicLinkToken(context.Background(), token.TokenHash)
+		assert.ErrorIs(t, err, magiclink.ErrTokenNotFound)
+	})
+}
+
+func TestMagicLinkTokenRepository_DeleteMagicLinkTokens(t *testing.T) {
+	t.Run("should delete all tokens of user", func(t *testing.T) {
+		saveUserA(t)
+
+		token := magiclink.Token{TokenHash: "token-hash", UserID: userA.ID, ExpiresAt: time.Now().Add(time.Hour)}
+		err := magicLinkTokenRepository.SaveMagicLinkToken(context.Background(), token)
+		require.NoError(t, err)
+
+		err = magicLinkTokenRepository.DeleteMagicLinkTokens(context.Background(), userA.ID)
+		assert.NoError(t, err)
+
+		_, err = magicLinkTokenRepository.ConsumeMagicLinkToken(context.Background(), token.TokenHash)
+		assert.ErrorIs(t, err, magiclink.ErrTokenNotFound)
+	})
+}
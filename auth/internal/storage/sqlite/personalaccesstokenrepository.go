@@ -0,0 +1,141 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	domain "github.com/nazarslota/unotes/auth/internal/domain/personalaccesstoken"
+)
+
+// PersonalAccessTokenRepository provides an implementation of the personal access token repository for a SQLite
+// database. Only hashes of the tokens are stored.
+type PersonalAccessTokenRepository struct {
+	db *sqlx.DB
+}
+
+// personalAccessTokenRow is a row of the personal_access_tokens table, which stores the scopes as a JSON array.
+type personalAccessTokenRow struct {
+	ID         string     `db:"id"`
+	UserID     string     `db:"user_id"`
+	Name       string     `db:"name"`
+	TokenHash  string     `db:"token_hash"`
+	Scopes     stringList `db:"scopes"`
+	CreatedAt  time.Time  `db:"created_at"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+}
+
+func (row personalAccessTokenRow) token() domain.Token {
+	return domain.Token{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		Name:       row.Name,
+		TokenHash:  row.TokenHash,
+		Scopes:     row.Scopes,
+		CreatedAt:  row.CreatedAt,
+		ExpiresAt:  row.ExpiresAt,
+		LastUsedAt: row.LastUsedAt,
+	}
+}
+
+// NewPersonalAccessTokenRepository creates a new instance of the PersonalAccessTokenRepository with the provided
+// handle to the SQLite database.
+//
+// If db is nil, returns an error.
+func NewPersonalAccessTokenRepository(db *sqlx.DB) (*PersonalAccessTokenRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &PersonalAccessTokenRepository{db: db}, nil
+}
+
+// SavePersonalAccessToken saves a personal access token to the SQLite database.
+func (r PersonalAccessTokenRepository) SavePersonalAccessToken(ctx context.Context, token domain.Token) error {
+	query := fmt.Sprintf(`INSERT INTO personal_access_tokens (id, user_id, name, token_hash, scopes, created_at, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)`)
+
+	_, err := r.db.ExecContext(ctx, query,
+		token.ID, token.UserID, token.Name, token.TokenHash, stringList(token.Scopes), token.CreatedAt.UTC(),
+		utc(token.ExpiresAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+	return nil
+}
+
+// FindPersonalAccessTokens finds all personal access tokens of a user in the SQLite database, oldest first.
+func (r PersonalAccessTokenRepository) FindPersonalAccessTokens(ctx context.Context, userID string) ([]domain.Token, error) {
+	query := fmt.Sprintf(`SELECT * FROM personal_access_tokens WHERE user_id = $1 ORDER BY created_at, id`)
+
+	var rows []personalAccessTokenRow
+	if err := r.db.SelectContext(ctx, &rows, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	tokens := make([]domain.Token, 0, len(rows))
+	for _, row := range rows {
+		tokens = append(tokens, row.token())
+	}
+	return tokens, nil
+}
+
+// FindPersonalAccessTokenByHash finds a personal access token in the SQLite database by the hash of the token.
+//
+// If the token is not found, returns `personalaccesstoken.ErrTokenNotFound`.
+func (r PersonalAccessTokenRepository) FindPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (domain.Token, error) {
+	query := fmt.Sprintf(`SELECT * FROM personal_access_tokens WHERE token_hash = $1`)
+
+	var row personalAccessTokenRow
+	if err := r.db.GetContext(ctx, &row, query, tokenHash); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.Token{}, errors.Join(err, domain.ErrTokenNotFound)
+	} else if err != nil {
+		return domain.Token{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return row.token(), nil
+}
+
+// UpdatePersonalAccessTokenLastUsed sets the time a personal access token was last used in the SQLite database.
+//
+// If the token is not found, returns `personalaccesstoken.ErrTokenNotFound`.
+func (r PersonalAccessTokenRepository) UpdatePersonalAccessTokenLastUsed(ctx context.Context, tokenID string, lastUsedAt time.Time) error {
+	query := fmt.Sprintf(`UPDATE personal_access_tokens SET last_used_at = $2 WHERE id = $1`)
+
+	res, err := r.db.ExecContext(ctx, query, tokenID, lastUsedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rows affected: %w", err)
+	} else if affected == 0 {
+		return domain.ErrTokenNotFound
+	}
+	return nil
+}
+
+// DeletePersonalAccessToken deletes a personal access token of a user from the SQLite database.
+//
+// If the user has no token with the ID, returns `personalaccesstoken.ErrTokenNotFound`.
+func (r PersonalAccessTokenRepository) DeletePersonalAccessToken(ctx context.Context, userID, tokenID string) error {
+	query := fmt.Sprintf(`DELETE FROM personal_access_tokens WHERE id = $1 AND user_id = $2`)
+
+	res, err := r.db.ExecContext(ctx, query, tokenID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rows affected: %w", err)
+	} else if affected == 0 {
+		return domain.ErrTokenNotFound
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nazarslota/unotes/auth/internal/domain/personalaccesstoken"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var personalAccessTokenRepository *PersonalAccessTokenRepository
+
+func init() {
+	var err error
+	personalAccessTokenRepository, err = NewPersonalAccessTokenRepository(testDB)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestNewPersonalAccessTokenRepository(t *testing.T) {
+	t.Run("should return error when db is nil", func(t *testing.T) {
+		repository, err := NewPersonalAccessTokenRepository(nil)
+		assert.EqualError(t, err, "db is nil")
+		assert.Nil(t, repository)
+	})
+}
+
+func TestPersonalAccessTokenRepository(t *testing.T) {
+	t.Run("should save, find, use and delete token", func(t *testing.T) {
+		saveUserA(t)
+
+		expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Microsecond)
+		expected := personalaccesstoken.Token{
+			ID:        "5b0e2c1a-7d3f-4e8b-9a6c-1f2e3d4c5b6a",
+			UserID:    userA.ID,
+			Name:      "backup script",
+			TokenHash: "token-hash",
+			Scopes:    []string{"notes:read"},
+			CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+			ExpiresAt: &expiresAt,
+		}
+		err := personalAccessTokenRepository.SavePersonalAccessToken(context.Background(), expected)
+		require.NoError(t, err)
+
+		actual, err := personalAccessTokenRepository.FindPersonalAccessTokenByHash(context.Background(), "token-hash")
+		require.NoError(t, err)
+		assert.Equal(t, expected.ID, actual.ID)
+		assert.Equal(t, expected.Scopes, actual.Scopes)
+		assert.True(t, expected.ExpiresAt.Equal(*actual.ExpiresAt))
+		assert.Nil(t, actual.LastUsedAt)
+
+		lastUsedAt := time.Now().UTC().Truncate(time.Microsecond)
+		err = personalAccessTokenRepository.UpdatePersonalAccessTokenLastUsed(context.Background(), expected.ID, lastUsedAt)
+		require.NoError(t, err)
+
+		tokens, err := personalAccessTokenRepository.FindPersonalAccessTokens(context.Background(), userA.ID)
+		require.NoError(t, err)
+		require.Len(t, tokens, 1)
+		require.NotNil(t, tokens[0].LastUsedAt)
+		assert.True(t, lastUsedAt.Equal(*tokens[0].LastUsedAt))
+
+		err = personalAccessTokenRepository.DeletePersonalAccessToken(context.Background(), userA.ID, expected.ID)
+		require.NoError(t, err)
+
+		_, err = personalAccessTokenRepository.FindPersonalAccessTokenByHash(context.Background(), "token-hash")
+		assert.ErrorIs(t, err, personalaccesstoken.ErrTokenNotFound)
+	})
+
+	t.Run("should return error when token does not exist", func(t *testing.T) {
+		err := personalAccessTokenRepository.DeletePersonalAccessToken(context.Background(), userA.ID,
+			"5b0e2c1a-7d3f-4e8b-9a6c-1f2e3d4c5b6a")
+		assert.ErrorIs(t, err, personalaccesstoken.ErrTokenNotFound)
+	})
+}
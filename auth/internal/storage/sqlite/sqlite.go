@@ -0,0 +1,108 @@
+// Package sqlite provides a SQLite repository implementation for storing and managing users, for deployments that
+// don't want to operate a PostgreSQL server. Times are stored as text in UTC, so that they compare in order.
+package sqlite
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Config stores the configuration information required to open a SQLite database.
+type Config struct {
+	Path string // Path is the path of the database file, which is created if it does not exist.
+}
+
+// NewSQLite opens the SQLite database using the provided configuration. Foreign keys are enforced, and transactions
+// take the write lock as they begin, waiting for other writers instead of failing.
+func NewSQLite(ctx context.Context, config Config) (*sqlx.DB, error) {
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("invalid context: %w", ctx.Err())
+	default:
+	}
+
+	if len(config.Path) == 0 {
+		return nil, fmt.Errorf("path is empty")
+	}
+
+	query := url.Values{}
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Add("_pragma", "busy_timeout(5000)")
+	query.Add("_pragma", "journal_mode(WAL)")
+	query.Set("_time_format", "sqlite")
+	query.Set("_txlock", "immediate")
+	dsn := fmt.Sprintf("file:%s?%s", config.Path, query.Encode())
+
+	db, err := sqlx.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return db, nil
+}
+
+// now returns the current time in UTC, the time zone all times are stored in.
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+// utc returns t in UTC, or nil if t is nil.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// isUniqueViolation reports whether err is a violation of a unique or primary key constraint.
+func isUniqueViolation(err error) bool {
+	sqliteErr := new(sqlite.Error)
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+// stringList is a list of strings stored as a JSON array, SQLite has no array type.
+type stringList []string
+
+// Value implements the driver.Valuer interface.
+func (l stringList) Value() (driver.Value, error) {
+	if l == nil {
+		l = stringList{}
+	}
+
+	b, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (l *stringList) Scan(src any) error {
+	switch src := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(src), l)
+	case []byte:
+		return json.Unmarshal(src, l)
+	default:
+		return fmt.Errorf("unsupported type %T of string list", src)
+	}
+}
@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	userA = user.User{
+		ID:           "867620c3-d77a-4d96-821c-ca65cbca8318",
+		Username:     "user-a-username",
+		PasswordHash: "user-a-password-hash",
+		Role:         user.RoleUser,
+	}
+)
+
+// testDir is the temporary directory of testDB, removed once the tests are run.
+var testDir, testDB = mustOpenTestDB()
+
+func mustOpenTestDB() (string, *sqlx.DB) {
+	dir, err := os.MkdirTemp("", "unotes-auth-sqlite")
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := NewSQLite(context.Background(), Config{Path: filepath.Join(dir, "auth.db")})
+	if err != nil {
+		panic(err)
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		panic(err)
+	}
+	if err := migrator.Up(); err != nil {
+		panic(err)
+	}
+	if err := migrator.Close(); err != nil {
+		panic(err)
+	}
+	return dir, db
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	_ = testDB.Close()
+	_ = os.RemoveAll(testDir)
+	os.Exit(code)
+}
+
+// newTestDB opens a new SQLite database in a temporary directory and applies the migrations of the schema to it.
+func newTestDB(t *testing.T) *sqlx.DB {
+	db, err := NewSQLite(context.Background(), Config{Path: filepath.Join(t.TempDir(), "auth.db")})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Up())
+	require.NoError(t, migrator.Close())
+	return db
+}
+
+func TestNewSQLite(t *testing.T) {
+	t.Run("should open new database", func(t *testing.T) {
+		db, err := NewSQLite(context.Background(), Config{Path: filepath.Join(t.TempDir(), "auth.db")})
+		require.NoError(t, err)
+		assert.NoError(t, db.Close())
+	})
+
+	t.Run("should return error if context is invalid", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		db, err := NewSQLite(ctx, Config{Path: filepath.Join(t.TempDir(), "auth.db")})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, db)
+	})
+
+	t.Run("should return error if path is invalid", func(t *testing.T) {
+		db, err := NewSQLite(context.Background(), Config{Path: filepath.Join(t.TempDir(), "missing", "auth.db")})
+		assert.Error(t, err)
+		assert.Nil(t, db)
+	})
+}
+
+func TestNewMigrator(t *testing.T) {
+	t.Run("should apply and roll back all migrations", func(t *testing.T) {
+		db := newTestDB(t)
+
+		migrator, err := NewMigrator(db)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = migrator.Close() })
+
+		status, err := migrator.Status()
+		require.NoError(t, err)
+		assert.Equal(t, uint(16), status.Version)
+
+		require.NoError(t, migrator.Down(int(status.Version)))
+		require.NoError(t, migrator.Up())
+		assert.NoError(t, db.Ping(), "closing the migrator must leave the database open")
+	})
+
+	t.Run("should return error when db is nil", func(t *testing.T) {
+		migrator, err := NewMigrator(nil)
+		assert.EqualError(t, err, "db is nil")
+		assert.Nil(t, migrator)
+	})
+}
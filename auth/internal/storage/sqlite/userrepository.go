@@ -0,0 +1,291 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	domainoutbox "github.com/nazarslota/unotes/auth/internal/domain/outbox"
+	domain "github.com/nazarslota/unotes/auth/internal/domain/user"
+)
+
+// UserRepository provides an implementation of the user repository for a SQLite database.
+type UserRepository struct {
+	db *sqlx.DB
+}
+
+// NewUserRepository creates a new instance of the UserRepository with the provided handle to the SQLite database.
+//
+// If db is nil, returns an error.
+func NewUserRepository(db *sqlx.DB) (*UserRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+	return &UserRepository{db: db}, nil
+}
+
+// userColumns lists the columns of the users table, the optional email is selected as an empty string when not set.
+const userColumns = `id, username, password_hash, COALESCE(email, '') AS email, email_verified, role, disabled`
+
+// SaveUser saves a user to the SQLite database. A user without a role is saved with the user role.
+//
+// If the user already exists, returns `user.ErrUserAlreadyExists`.
+// If the email is already used by another user, returns `user.ErrEmailAlreadyExists`.
+func (r UserRepository) SaveUser(ctx context.Context, user domain.User) error {
+	query := fmt.Sprintf(`INSERT INTO users (id, username, password_hash, email, email_verified, role)
+VALUES ($1, $2, $3, NULLIF($4, ''), $5, COALESCE(NULLIF($6, ''), 'user'))
+ON CONFLICT (username) DO NOTHING`)
+
+	res, err := r.db.ExecContext(ctx, query,
+		user.ID, user.Username, user.PasswordHash, user.Email, user.EmailVerified, user.Role)
+	if err != nil {
+		return uniqueViolationError(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rows affected: %w", err)
+	} else if affected == 0 {
+		return domain.ErrUserAlreadyExists
+	}
+	return nil
+}
+
+// FindUserByUserID finds a user in the SQLite database by their user ID.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) FindUserByUserID(ctx context.Context, userID string) (user domain.User, err error) {
+	query := fmt.Sprintf(`SELECT %s FROM users WHERE id = $1`, userColumns)
+	if err := r.db.GetContext(ctx, &user, query, userID); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.User{}, errors.Join(err, domain.ErrUserNotFound)
+	} else if err != nil {
+		return domain.User{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return user, nil
+}
+
+// FindUserByUsername finds a user in the SQLite database by their username.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) FindUserByUsername(ctx context.Context, username string) (user domain.User, err error) {
+	query := fmt.Sprintf(`SELECT %s FROM users WHERE username = $1`, userColumns)
+	if err := r.db.GetContext(ctx, &user, query, username); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.User{}, errors.Join(err, domain.ErrUserNotFound)
+	} else if err != nil {
+		return domain.User{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return user, nil
+}
+
+// FindUserByEmail finds a user in the SQLite database by their email, ignoring case.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) FindUserByEmail(ctx context.Context, email string) (user domain.User, err error) {
+	query := fmt.Sprintf(`SELECT %s FROM users WHERE email = $1 COLLATE NOCASE`, userColumns)
+	if err := r.db.GetContext(ctx, &user, query, email); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.User{}, errors.Join(err, domain.ErrUserNotFound)
+	} else if err != nil {
+		return domain.User{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return user, nil
+}
+
+// UpdateUser updates the username, the password hash and the email of a user in the SQLite database.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+// If the new username is already taken, returns `user.ErrUserAlreadyExists`.
+// If the new email is already used by another user, returns `user.ErrEmailAlreadyExists`.
+func (r UserRepository) UpdateUser(ctx context.Context, user domain.User) error {
+	query := fmt.Sprintf(`UPDATE users SET username = $2, password_hash = $3, email = NULLIF($4, ''), email_verified = $5 WHERE id = $1`)
+
+	res, err := r.db.ExecContext(ctx, query, user.ID, user.Username, user.PasswordHash, user.Email, user.EmailVerified)
+	if err != nil {
+		return uniqueViolationError(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rows affected: %w", err)
+	} else if affected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// FindUsers finds the users selected by the filter in the SQLite database, ordered by username.
+func (r UserRepository) FindUsers(ctx context.Context, filter domain.Filter) ([]domain.User, error) {
+	query := fmt.Sprintf(`SELECT %s FROM users
+WHERE ($1 = '' OR instr(lower(username), lower($1)) > 0 OR instr(lower(COALESCE(email, '')), lower($1)) > 0) AND username > $2
+ORDER BY username`, userColumns)
+	args := []any{filter.Query, filter.After}
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	users := make([]domain.User, 0)
+	if err := r.db.SelectContext(ctx, &users, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return users, nil
+}
+
+// UpdateUserRole updates the role of a user in the SQLite database.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) UpdateUserRole(ctx context.Context, userID, role string) error {
+	query := `UPDATE users SET role = $2 WHERE id = $1`
+	return r.updateUser(ctx, query, userID, role)
+}
+
+// UpdateUserDisabled disables or enables a user in the SQLite database.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) UpdateUserDisabled(ctx context.Context, userID string, disabled bool) error {
+	query := `UPDATE users SET disabled = $2 WHERE id = $1`
+	return r.updateUser(ctx, query, userID, disabled)
+}
+
+// updateUser executes a query updating a single column of a user, given the user ID and the new value.
+func (r UserRepository) updateUser(ctx context.Context, query, userID string, value any) error {
+	res, err := r.db.ExecContext(ctx, query, userID, value)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rows affected: %w", err)
+	} else if affected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// DeleteUser deletes a user from the SQLite database, together with everything that belongs to them, remembers
+// the user as deleted and saves the messages describing the deletion to the outbox in the same transaction.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) DeleteUser(ctx context.Context, userID string, messages ...domainoutbox.Message) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := fmt.Sprintf(`DELETE FROM users WHERE id = $1`)
+	res, err := tx.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rows affected: %w", err)
+	} else if affected == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	query = fmt.Sprintf(`INSERT INTO deleted_users (user_id, deleted_at) VALUES ($1, $2)
+ON CONFLICT (user_id) DO NOTHING`)
+	if _, err := tx.ExecContext(ctx, query, userID, now()); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	if err := saveOutboxMessages(ctx, tx, messages); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// FindDeletedUserIDs finds which of the given user IDs belong to users that were deleted from the SQLite
+// database. User IDs that never belonged to a user, or belong to a user again, are left out.
+func (r UserRepository) FindDeletedUserIDs(ctx context.Context, userIDs []string) ([]string, error) {
+	query := fmt.Sprintf(`SELECT user_id FROM deleted_users
+WHERE user_id IN (SELECT value FROM json_each($1)) AND user_id NOT IN (SELECT id FROM users)`)
+
+	deleted := make([]string, 0, len(userIDs))
+	if err := r.db.SelectContext(ctx, &deleted, query, stringList(userIDs)); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return deleted, nil
+}
+
+// profileColumns lists the columns of the users table that make up the profile of a user.
+const profileColumns = `id, username, display_name, avatar, locale, timezone`
+
+// FindProfile finds the profile of a user in the SQLite database by their user ID.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) FindProfile(ctx context.Context, userID string) (profile domain.Profile, err error) {
+	query := fmt.Sprintf(`SELECT %s FROM users WHERE id = $1`, profileColumns)
+	if err := r.db.GetContext(ctx, &profile, query, userID); errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("failed to execute query: %w", err)
+		return domain.Profile{}, errors.Join(err, domain.ErrUserNotFound)
+	} else if err != nil {
+		return domain.Profile{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return profile, nil
+}
+
+// FindProfiles finds the profiles of the users with the given user IDs in the SQLite database. Users that are not
+// found are left out, so fewer profiles than user IDs may be returned.
+func (r UserRepository) FindProfiles(ctx context.Context, userIDs []string) ([]domain.Profile, error) {
+	query := fmt.Sprintf(`SELECT %s FROM users WHERE id IN (SELECT value FROM json_each($1)) ORDER BY username`,
+		profileColumns)
+
+	profiles := make([]domain.Profile, 0, len(userIDs))
+	if err := r.db.SelectContext(ctx, &profiles, query, stringList(userIDs)); err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return profiles, nil
+}
+
+// UpdateProfile updates the display name, the avatar, the locale and the timezone of a user in the SQLite
+// database. The username is not changed.
+//
+// If the user is not found, returns `user.ErrUserNotFound`.
+func (r UserRepository) UpdateProfile(ctx context.Context, profile domain.Profile) error {
+	query := fmt.Sprintf(`UPDATE users SET display_name = $2, avatar = $3, locale = $4, timezone = $5 WHERE id = $1`)
+
+	res, err := r.db.ExecContext(ctx, query,
+		profile.UserID, profile.DisplayName, profile.Avatar, profile.Locale, profile.Timezone,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve rows affected: %w", err)
+	} else if affected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// uniqueViolationError wraps a failed query error, joining it with the domain error of the violated unique constraint
+// if there is one.
+func uniqueViolationError(err error) error {
+	if !isUniqueViolation(err) {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	// SQLite reports the columns of the violated constraint rather than its name.
+	violated := err.Error()
+	err = fmt.Errorf("failed to execute query: %w", err)
+	if strings.Contains(violated, "users.email") {
+		return errors.Join(err, domain.ErrEmailAlreadyExists)
+	}
+	return errors.Join(err, domain.ErrUserAlreadyExists)
+}
@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/nazarslota/unotes/auth/internal/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+)
+
+var repository *UserRepository
+
+func init() {
+	var err error
+	repository, err = NewUserRepository(testDB)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestNewUserRepository(t *testing.T) {
+	t.Run("should create new user repository", func(t *testing.T) {
+		repository, err := NewUserRepository(newTestDB(t))
+		assert.NoError(t, err)
+		assert.NotNil(t, repository)
+	})
+
+	t.Run("should return error when db is nil", func(t *testing.T) {
+		repository, err := NewUserRepository(nil)
+		assert.EqualError(t, err, "db is nil")
+		assert.Nil(t, repository)
+	})
+}
+
+func TestUserRepository_Conformance(t *testing.T) {
+	storagetest.TestUserRepository(t, repository)
+}
@@ -0,0 +1,384 @@
+// Package storagetest provides conformance tests for the repositories of the storage backends. Every backend runs
+// the same tests from its own test files, so that all of them behave the same way, down to the errors they return.
+package storagetest
+
+import (
+	"context"
+	"testing"
+
+	domainoutbox "github.com/nazarslota/unotes/auth/internal/domain/outbox"
+	domainrefresh "github.com/nazarslota/unotes/auth/internal/domain/refresh"
+	domainuser "github.com/nazarslota/unotes/auth/internal/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// UserRepository is the repository of users run by TestUserRepository.
+type UserRepository interface {
+	SaveUser(ctx context.Context, user domainuser.User) error
+	FindUserByUserID(ctx context.Context, userID string) (domainuser.User, error)
+	FindUserByUsername(ctx context.Context, username string) (domainuser.User, error)
+	FindUserByEmail(ctx context.Context, email string) (domainuser.User, error)
+	UpdateUser(ctx context.Context, user domainuser.User) error
+	DeleteUser(ctx context.Context, userID string, messages ...domainoutbox.Message) error
+	FindDeletedUserIDs(ctx context.Context, userIDs []string) ([]string, error)
+	FindProfile(ctx context.Context, userID string) (domainuser.Profile, error)
+	FindProfiles(ctx context.Context, userIDs []string) ([]domainuser.Profile, error)
+	UpdateProfile(ctx context.Context, profile domainuser.Profile) error
+	FindUsers(ctx context.Context, filter domainuser.Filter) ([]domainuser.User, error)
+	UpdateUserRole(ctx context.Context, userID, role string) error
+	UpdateUserDisabled(ctx context.Context, userID string, disabled bool) error
+}
+
+// RefreshTokenRepository is the repository of refresh tokens run by TestRefreshTokenRepository.
+type RefreshTokenRepository interface {
+	SaveRefreshToken(ctx context.Context, userID string, token domainrefresh.Token) error
+	SaveRefreshTokens(ctx context.Context, userID string, tokens []domainrefresh.Token) error
+	GetRefreshToken(ctx context.Context, userID string, token domainrefresh.Token) (domainrefresh.Token, error)
+	GetRefreshTokens(ctx context.Context, userID string) ([]domainrefresh.Token, error)
+	DeleteRefreshToken(ctx context.Context, userID string, token domainrefresh.Token) error
+	DeleteRefreshTokens(ctx context.Context, userID string, tokens []domainrefresh.Token) error
+}
+
+var (
+	userA = domainuser.User{
+		ID:           "0b8f3d4e-2a51-4c1e-9d3b-6f0e7a2c9d41",
+		Username:     "storagetest-user-a",
+		PasswordHash: "user-a-password-hash",
+		Email:        "storagetest-user-a@example.com",
+		Role:         domainuser.RoleUser,
+	}
+	userB = domainuser.User{
+		ID:           "7c2e9a1f-5b34-4d8e-a6f2-1e9b3c7d5a08",
+		Username:     "storagetest-user-b",
+		PasswordHash: "user-b-password-hash",
+		Role:         domainuser.RoleAdmin,
+	}
+)
+
+// TestUserRepository tests that repository saves, finds, updates and deletes users and their profiles. The users it
+// saves are deleted once each test is done, so it may run against a database that is shared with other tests.
+func TestUserRepository(t *testing.T, repository UserRepository) {
+	ctx := context.Background()
+
+	saveUser := func(t *testing.T, user domainuser.User) {
+		require.NoError(t, repository.SaveUser(ctx, user))
+		t.Cleanup(func() { _ = repository.DeleteUser(ctx, user.ID) })
+	}
+
+	t.Run("should save and find user", func(t *testing.T) {
+		saveUser(t, userA)
+
+		result, err := repository.FindUserByUserID(ctx, userA.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, userA, result)
+
+		result, err = repository.FindUserByUsername(ctx, userA.Username)
+		assert.NoError(t, err)
+		assert.Equal(t, userA, result)
+
+		result, err = repository.FindUserByEmail(ctx, "STORAGETEST-User-A@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, userA, result)
+	})
+
+	t.Run("should save user without role as user", func(t *testing.T) {
+		withoutRole := userA
+		withoutRole.Role = ""
+		saveUser(t, withoutRole)
+
+		result, err := repository.FindUserByUserID(ctx, userA.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domainuser.RoleUser, result.Role)
+	})
+
+	t.Run("should return error if user already exists", func(t *testing.T) {
+		saveUser(t, userA)
+
+		err := repository.SaveUser(ctx, userA)
+		assert.ErrorIs(t, err, domainuser.ErrUserAlreadyExists)
+
+		sameUsername := userB
+		sameUsername.Username = userA.Username
+		err = repository.SaveUser(ctx, sameUsername)
+		assert.ErrorIs(t, err, domainuser.ErrUserAlreadyExists)
+		t.Cleanup(func() { _ = repository.DeleteUser(ctx, userB.ID) })
+	})
+
+	t.Run("should return error if email is already used", func(t *testing.T) {
+		saveUser(t, userA)
+
+		sameEmail := userB
+		sameEmail.Email = "Storagetest-User-A@EXAMPLE.com"
+		err := repository.SaveUser(ctx, sameEmail)
+		assert.ErrorIs(t, err, domainuser.ErrEmailAlreadyExists)
+		t.Cleanup(func() { _ = repository.DeleteUser(ctx, userB.ID) })
+	})
+
+	t.Run("should return error if user does not exist", func(t *testing.T) {
+		_, err := repository.FindUserByUserID(ctx, userA.ID)
+		assert.ErrorIs(t, err, domainuser.ErrUserNotFound)
+
+		_, err = repository.FindUserByUsername(ctx, userA.Username)
+		assert.ErrorIs(t, err, domainuser.ErrUserNotFound)
+
+		_, err = repository.FindUserByEmail(ctx, userA.Email)
+		assert.ErrorIs(t, err, domainuser.ErrUserNotFound)
+
+		err = repository.UpdateUser(ctx, userA)
+		assert.ErrorIs(t, err, domainuser.ErrUserNotFound)
+
+		err = repository.DeleteUser(ctx, userA.ID)
+		assert.ErrorIs(t, err, domainuser.ErrUserNotFound)
+
+		_, err = repository.FindProfile(ctx, userA.ID)
+		assert.ErrorIs(t, err, domainuser.ErrUserNotFound)
+
+		err = repository.UpdateProfile(ctx, domainuser.Profile{UserID: userA.ID})
+		assert.ErrorIs(t, err, domainuser.ErrUserNotFound)
+
+		err = repository.UpdateUserRole(ctx, userA.ID, domainuser.RoleAdmin)
+		assert.ErrorIs(t, err, domainuser.ErrUserNotFound)
+
+		err = repository.UpdateUserDisabled(ctx, userA.ID, true)
+		assert.ErrorIs(t, err, domainuser.ErrUserNotFound)
+	})
+
+	t.Run("should update user but not role", func(t *testing.T) {
+		saveUser(t, userA)
+
+		updated := userA
+		updated.Username = "storagetest-user-a-renamed"
+		updated.PasswordHash = "user-a-new-password-hash"
+		updated.EmailVerified = true
+		updated.Role = domainuser.RoleAdmin
+		require.NoError(t, repository.UpdateUser(ctx, updated))
+
+		result, err := repository.FindUserByUsername(ctx, updated.Username)
+		assert.NoError(t, err)
+
+		updated.Role = domainuser.RoleUser
+		assert.Equal(t, updated, result)
+	})
+
+	t.Run("should update role and disabled separately from user", func(t *testing.T) {
+		saveUser(t, userA)
+
+		require.NoError(t, repository.UpdateUserRole(ctx, userA.ID, domainuser.RoleAdmin))
+		require.NoError(t, repository.UpdateUserDisabled(ctx, userA.ID, true))
+
+		updated := userA
+		updated.Role = domainuser.RoleAdmin
+		updated.Disabled = true
+		result, err := repository.FindUserByUserID(ctx, userA.ID)
+		require.NoError(t, err)
+		assert.Equal(t, updated, result)
+
+		require.NoError(t, repository.UpdateUser(ctx, userA))
+		result, err = repository.FindUserByUserID(ctx, userA.ID)
+		require.NoError(t, err)
+		assert.Equal(t, updated, result)
+
+		require.NoError(t, repository.UpdateUserDisabled(ctx, userA.ID, false))
+		result, err = repository.FindUserByUserID(ctx, userA.ID)
+		require.NoError(t, err)
+		assert.False(t, result.Disabled)
+	})
+
+	t.Run("should find users by username or email", func(t *testing.T) {
+		saveUser(t, userA)
+		saveUser(t, userB)
+
+		users, err := repository.FindUsers(ctx, domainuser.Filter{Query: "STORAGETEST-USER-"})
+		require.NoError(t, err)
+		assert.Equal(t, []domainuser.User{userA, userB}, users)
+
+		users, err = repository.FindUsers(ctx, domainuser.Filter{Query: "user-a@EXAMPLE"})
+		require.NoError(t, err)
+		assert.Equal(t, []domainuser.User{userA}, users)
+
+		users, err = repository.FindUsers(ctx, domainuser.Filter{Query: "storagetest-user-", Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []domainuser.User{userA}, users)
+
+		users, err = repository.FindUsers(ctx, domainuser.Filter{Query: "storagetest-user-", After: userA.Username})
+		require.NoError(t, err)
+		assert.Equal(t, []domainuser.User{userB}, users)
+	})
+
+	t.Run("should delete user", func(t *testing.T) {
+		saveUser(t, userA)
+		saveUser(t, userB)
+
+		require.NoError(t, repository.DeleteUser(ctx, userA.ID))
+
+		_, err := repository.FindUserByUserID(ctx, userA.ID)
+		assert.ErrorIs(t, err, domainuser.ErrUserNotFound)
+
+		result, err := repository.FindUserByUserID(ctx, userB.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, userB, result)
+	})
+
+	t.Run("should find deleted users", func(t *testing.T) {
+		saveUser(t, userA)
+		saveUser(t, userB)
+
+		require.NoError(t, repository.DeleteUser(ctx, userA.ID))
+
+		unknown := "5d0c6b7e-3f0c-4c69-9a0e-0c2d8b3f7a51"
+		deleted, err := repository.FindDeletedUserIDs(ctx, []string{userA.ID, userB.ID, unknown})
+		require.NoError(t, err)
+		assert.Equal(t, []string{userA.ID}, deleted)
+
+		saveUser(t, userA)
+
+		deleted, err = repository.FindDeletedUserIDs(ctx, []string{userA.ID, userB.ID, unknown})
+		require.NoError(t, err)
+		assert.Empty(t, deleted)
+	})
+
+	t.Run("should find and update profiles", func(t *testing.T) {
+		saveUser(t, userA)
+		saveUser(t, userB)
+
+		result, err := repository.FindProfile(ctx, userA.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domainuser.Profile{UserID: userA.ID, Username: userA.Username, Locale: "en", Timezone: "UTC"}, result)
+
+		updated := domainuser.Profile{
+			UserID:      userA.ID,
+			Username:    userA.Username,
+			DisplayName: "User A",
+			Avatar:      userA.ID + "-avatar",
+			Locale:      "uk-UA",
+			Timezone:    "Europe/Kyiv",
+		}
+		require.NoError(t, repository.UpdateProfile(ctx, updated))
+
+		result, err = repository.FindProfile(ctx, userA.ID)
+		require.NoError(t, err)
+		assert.Equal(t, updated, result)
+
+		profiles, err := repository.FindProfiles(ctx, []string{userB.ID, userA.ID, "5d0c6b7e-3f0c-4c69-9a0e-0c2d8b3f7a51"})
+		require.NoError(t, err)
+		assert.Equal(t, []domainuser.Profile{updated, {
+			UserID: userB.ID, Username: userB.Username, Locale: "en", Timezone: "UTC",
+		}}, profiles)
+	})
+
+	t.Run("should return error if context is invalid", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := repository.SaveUser(canceled, userA)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = repository.FindUserByUserID(canceled, userA.ID)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		err = repository.UpdateUser(canceled, userA)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		err = repository.DeleteUser(canceled, userA.ID)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+const (
+	userATokenA domainrefresh.Token = "storagetest-user-a-token-a"
+	userATokenB domainrefresh.Token = "storagetest-user-a-token-b"
+	userBTokenA domainrefresh.Token = "storagetest-user-b-token-a"
+)
+
+// TestRefreshTokenRepository tests that repository saves, gets and deletes the refresh tokens of users. The tokens it
+// saves are deleted once each test is done.
+func TestRefreshTokenRepository(t *testing.T, repository RefreshTokenRepository) {
+	ctx := context.Background()
+
+	saveTokens := func(t *testing.T, userID string, tokens ...domainrefresh.Token) {
+		require.NoError(t, repository.SaveRefreshTokens(ctx, userID, tokens))
+		t.Cleanup(func() { _ = repository.DeleteRefreshTokens(ctx, userID, tokens) })
+	}
+
+	t.Run("should save and get refresh tokens", func(t *testing.T) {
+		saveTokens(t, userA.ID, userATokenA, userATokenB)
+		saveTokens(t, userB.ID, userBTokenA)
+
+		token, err := repository.GetRefreshToken(ctx, userA.ID, userATokenA)
+		assert.NoError(t, err)
+		assert.Equal(t, userATokenA, token)
+
+		tokens, err := repository.GetRefreshTokens(ctx, userA.ID)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []domainrefresh.Token{userATokenA, userATokenB}, tokens)
+
+		tokens, err = repository.GetRefreshTokens(ctx, userB.ID)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []domainrefresh.Token{userBTokenA}, tokens)
+	})
+
+	t.Run("should save the same refresh token once", func(t *testing.T) {
+		saveTokens(t, userA.ID, userATokenA)
+		require.NoError(t, repository.SaveRefreshToken(ctx, userA.ID, userATokenA))
+
+		tokens, err := repository.GetRefreshTokens(ctx, userA.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []domainrefresh.Token{userATokenA}, tokens)
+	})
+
+	t.Run("should delete refresh tokens", func(t *testing.T) {
+		saveTokens(t, userA.ID, userATokenA, userATokenB)
+		saveTokens(t, userB.ID, userBTokenA)
+
+		require.NoError(t, repository.DeleteRefreshToken(ctx, userA.ID, userATokenA))
+
+		_, err := repository.GetRefreshToken(ctx, userA.ID, userATokenA)
+		assert.ErrorIs(t, err, domainrefresh.ErrTokenNotFound)
+
+		require.NoError(t, repository.DeleteRefreshTokens(ctx, userA.ID, []domainrefresh.Token{userATokenB}))
+
+		_, err = repository.GetRefreshTokens(ctx, userA.ID)
+		assert.ErrorIs(t, err, domainrefresh.ErrTokenNotFound)
+
+		tokens, err := repository.GetRefreshTokens(ctx, userB.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []domainrefresh.Token{userBTokenA}, tokens)
+	})
+
+	t.Run("should return error if refresh token is not found", func(t *testing.T) {
+		saveTokens(t, userB.ID, userBTokenA)
+
+		_, err := repository.GetRefreshToken(ctx, userA.ID, userATokenA)
+		assert.ErrorIs(t, err, domainrefresh.ErrTokenNotFound)
+
+		_, err = repository.GetRefreshToken(ctx, userA.ID, userBTokenA)
+		assert.ErrorIs(t, err, domainrefresh.ErrTokenNotFound)
+
+		_, err = repository.GetRefreshTokens(ctx, userA.ID)
+		assert.ErrorIs(t, err, domainrefresh.ErrTokenNotFound)
+
+		err = repository.DeleteRefreshToken(ctx, userA.ID, userATokenA)
+		assert.ErrorIs(t, err, domainrefresh.ErrTokenNotFound)
+
+		err = repository.DeleteRefreshTokens(ctx, userA.ID, []domainrefresh.Token{userATokenA, userATokenB})
+		assert.ErrorIs(t, err, domainrefresh.ErrTokenNotFound)
+	})
+
+	t.Run("should return error if context is invalid", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := repository.SaveRefreshToken(canceled, userA.ID, userATokenA)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = repository.GetRefreshTokens(canceled, userA.ID)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		err = repository.DeleteRefreshToken(canceled, userA.ID, userATokenA)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = repository.GetRefreshTokens(ctx, userA.ID)
+		assert.ErrorIs(t, err, domainrefresh.ErrTokenNotFound)
+	})
+}
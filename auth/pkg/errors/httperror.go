@@ -7,11 +7,12 @@ import (
 )
 
 // HTTPError is a custom error type that holds information about an HTTP error.
-// It contains the HTTP status code, a message, and an internal error if there is one.
+// It contains the HTTP status code, a message, details if there are any, and an internal error if there is one.
 type HTTPError struct {
-	Code     int   `json:"code"`    // HTTP status code.
-	Message  any   `json:"message"` // Error message.
-	Internal error `json:"-"`       // Internal error.
+	Code     int   `json:"code"`              // HTTP status code.
+	Message  any   `json:"message"`           // Error message.
+	Details  any   `json:"details,omitempty"` // Error details, like the fields of the request that are invalid.
+	Internal error `json:"-"`                 // Internal error.
 }
 
 // ErrHTTPInternalServerError is a predefined HTTPError with status code 500 and message "Internal Server Error".
@@ -41,6 +42,12 @@ func (e *HTTPError) Unwrap() error {
 	return e.Internal
 }
 
+// SetDetails sets the details of the HTTPError.
+func (e *HTTPError) SetDetails(details any) *HTTPError {
+	e.Details = details
+	return e
+}
+
 // SetInternal sets the internal error of the HTTPError.
 func (e *HTTPError) SetInternal(err error) *HTTPError {
 	e.Internal = err
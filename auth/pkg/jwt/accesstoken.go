@@ -4,10 +4,21 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
-// AccessTokenClaims represents the claims in an access token.
+// AccessTokenClaims represents the claims in an access token. EmailVerified reports whether the user has a verified
+// email address, so that services can require verified accounts. Role is the role of the user, "user" or "admin".
+// Scope is space-delimited and lists what the token may be used for. ClientID is set only for tokens issued to an
+// OAuth2 client acting on behalf of the user, whose scope is what was granted to the client. For a service account
+// acting on its own behalf, UserID and the subject are the client ID and there is no role. SessionID identifies the
+// sign in the token descends from and is kept when tokens are refreshed; the token ID (jti) is unique to every token,
+// so that it can be revoked.
 type AccessTokenClaims struct {
 	jwt.RegisteredClaims
-	UserID string `json:"user_id"`
+	UserID        string `json:"user_id"`
+	EmailVerified bool   `json:"email_verified,omitempty"`
+	Role          string `json:"role,omitempty"`
+	ClientID      string `json:"client_id,omitempty"`
+	Scope         string `json:"scope,omitempty"`
+	SessionID     string `json:"sid,omitempty"`
 }
 
 // AccessTokenManagerHMAC is a struct for managing access tokens using HMAC algorithm.
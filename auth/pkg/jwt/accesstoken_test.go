@@ -44,6 +44,56 @@ func TestAccessTokenManagerHMAC_Parse(t *testing.T) {
 		assert.Equal(t, AccessTokenClaims{UserID: "e10adb24-7179-468f-911d-cc90aacb7410"}, claims)
 	})
 
+	t.Run("should parse email verified claim", func(t *testing.T) {
+		tm := NewAccessTokenManagerHMAC("secret")
+		require.NotNil(t, tm)
+
+		expected := AccessTokenClaims{UserID: "e10adb24-7179-468f-911d-cc90aacb7410", EmailVerified: true}
+		token, err := tm.New(expected)
+		require.NoError(t, err)
+
+		claims, err := tm.Parse(token)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, claims)
+	})
+
+	t.Run("should parse client claims", func(t *testing.T) {
+		tm := NewAccessTokenManagerHMAC("secret")
+		require.NotNil(t, tm)
+
+		expected := AccessTokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{ID: "3b1f3c1e-5f4a-4a8e-9f0e-2f8f1c1d7a10"},
+			UserID:           "e10adb24-7179-468f-911d-cc90aacb7410",
+			ClientID:         "client-id",
+			Scope:            "notes:read notes:write",
+			SessionID:        "0c5b4a3e-2d1f-4e6a-8b7c-9d0e1f2a3b4c",
+		}
+		token, err := tm.New(expected)
+		require.NoError(t, err)
+
+		claims, err := tm.Parse(token)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, claims)
+	})
+
+	t.Run("should parse role and scope claims", func(t *testing.T) {
+		tm := NewAccessTokenManagerHMAC("secret")
+		require.NotNil(t, tm)
+
+		expected := AccessTokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{ID: "8d2e4f6a-1b3c-4d5e-8f7a-9b0c1d2e3f4a"},
+			UserID:           "e10adb24-7179-468f-911d-cc90aacb7410",
+			Role:             "admin",
+			Scope:            "notes:read notes:write",
+		}
+		token, err := tm.New(expected)
+		require.NoError(t, err)
+
+		claims, err := tm.Parse(token)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, claims)
+	})
+
 	t.Run("should return an error if token is expired", func(t *testing.T) {
 		tm := NewAccessTokenManagerHMAC("secret")
 		require.NotNil(t, tm)
@@ -0,0 +1,109 @@
+package jwt
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// IDTokenClaims represents the claims in an OpenID Connect ID token. Nonce echoes the nonce of the authentication
+// request, if it had one.
+type IDTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string `json:"nonce,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     bool   `json:"email_verified,omitempty"`
+}
+
+// IDTokenManagerRSA is a struct for managing ID tokens using the RS256 algorithm, so that relying parties can verify
+// them with the public key alone.
+type IDTokenManagerRSA struct {
+	PrivateKey *rsa.PrivateKey
+	KeyID      string
+}
+
+// NewIDTokenManagerRSA creates and returns a new IDTokenManagerRSA with the given private key. The key ID is the
+// RFC 7638 thumbprint of the public key.
+func NewIDTokenManagerRSA(privateKey *rsa.PrivateKey) *IDTokenManagerRSA {
+	return &IDTokenManagerRSA{PrivateKey: privateKey, KeyID: thumbprint(&privateKey.PublicKey)}
+}
+
+// New creates and signs a new ID token with the given claims.
+func (m *IDTokenManagerRSA) New(claims IDTokenClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = m.KeyID
+
+	signed, err := token.SignedString(m.PrivateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signed, nil
+}
+
+// Parse parses and validates the signature and claims of an ID token.
+func (m *IDTokenManagerRSA) Parse(token string) (IDTokenClaims, error) {
+	claims := new(IDTokenClaims)
+	parsed, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return &m.PrivateKey.PublicKey, nil
+	})
+	if err != nil {
+		return IDTokenClaims{}, fmt.Errorf("failed to parse token: %w", err)
+	} else if !parsed.Valid {
+		return IDTokenClaims{}, jwt.ErrTokenInvalidClaims
+	}
+	return *claims, nil
+}
+
+// KeySet returns the JSON Web Key Set with the public key ID tokens are verified with.
+func (m *IDTokenManagerRSA) KeySet() KeySet {
+	return KeySet{Keys: []Key{newRSAKey(m.KeyID, &m.PrivateKey.PublicKey)}}
+}
+
+// KeySet is a JSON Web Key Set as described in RFC 7517.
+type KeySet struct {
+	Keys []Key `json:"keys"`
+}
+
+// Key is a public RSA JSON Web Key as described in RFC 7517 and RFC 7518.
+type Key struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+func newRSAKey(keyID string, key *rsa.PublicKey) Key {
+	return Key{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: jwt.SigningMethodRS256.Alg(),
+		KeyID:     keyID,
+		Modulus:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// thumbprint returns the RFC 7638 thumbprint of an RSA public key, the hash of its required members in lexicographic
+// order.
+func thumbprint(key *rsa.PublicKey) string {
+	k := newRSAKey("", key)
+	members, _ := json.Marshal(struct {
+		E   string `json:"e"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+	}{E: k.Exponent, Kty: k.KeyType, N: k.Modulus})
+
+	sum := sha256.Sum256(members)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,82 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRSAKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func TestNewIDTokenManagerRSA(t *testing.T) {
+	key := newTestRSAKey(t)
+
+	tm := NewIDTokenManagerRSA(key)
+	assert.NotNil(t, tm)
+	assert.Equal(t, key, tm.PrivateKey)
+	assert.NotEmpty(t, tm.KeyID)
+	assert.Equal(t, tm.KeyID, NewIDTokenManagerRSA(key).KeyID)
+}
+
+func TestIDTokenManagerRSA_Parse(t *testing.T) {
+	t.Run("should parse ID token", func(t *testing.T) {
+		tm := NewIDTokenManagerRSA(newTestRSAKey(t))
+
+		expected := IDTokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "https://unotes.example.com/api",
+				Subject:   "e10adb24-7179-468f-911d-cc90aacb7410",
+				Audience:  jwt.ClaimStrings{"client-id"},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour).Truncate(time.Second)),
+				IssuedAt:  jwt.NewNumericDate(time.Now().Truncate(time.Second)),
+			},
+			Nonce:             "nonce",
+			PreferredUsername: "username",
+		}
+		token, err := tm.New(expected)
+		require.NoError(t, err)
+
+		claims, err := tm.Parse(token)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, claims)
+	})
+
+	t.Run("should return an error if token is signed with another key", func(t *testing.T) {
+		token, err := NewIDTokenManagerRSA(newTestRSAKey(t)).New(IDTokenClaims{})
+		require.NoError(t, err)
+
+		claims, err := NewIDTokenManagerRSA(newTestRSAKey(t)).Parse(token)
+		assert.ErrorIs(t, err, rsa.ErrVerification)
+		assert.Empty(t, claims)
+	})
+}
+
+func TestIDTokenManagerRSA_KeySet(t *testing.T) {
+	key := newTestRSAKey(t)
+	tm := NewIDTokenManagerRSA(key)
+
+	keys := tm.KeySet().Keys
+	require.Len(t, keys, 1)
+	assert.Equal(t, "RSA", keys[0].KeyType)
+	assert.Equal(t, "RS256", keys[0].Algorithm)
+	assert.Equal(t, tm.KeyID, keys[0].KeyID)
+
+	n, err := base64.RawURLEncoding.DecodeString(keys[0].Modulus)
+	require.NoError(t, err)
+	assert.Equal(t, key.N, new(big.Int).SetBytes(n))
+
+	e, err := base64.RawURLEncoding.DecodeString(keys[0].Exponent)
+	require.NoError(t, err)
+	assert.Equal(t, int64(key.E), new(big.Int).SetBytes(e).Int64())
+}
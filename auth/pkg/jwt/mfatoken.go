@@ -0,0 +1,32 @@
+package jwt
+
+import (
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// MFATokenClaims represents the claims in a multi-factor authentication challenge token, which is issued after a
+// successful password check and exchanged for an access and refresh token pair once the second factor is verified.
+type MFATokenClaims struct {
+	jwt.RegisteredClaims
+	UserID string `json:"user_id"`
+}
+
+// MFATokenManagerHMAC is a struct for managing multi-factor authentication challenge tokens using HMAC algorithm.
+type MFATokenManagerHMAC struct {
+	MFATokenSecret string
+}
+
+// NewMFATokenManagerHMAC creates and returns a new MFATokenManagerHMAC with the given challenge token secret.
+func NewMFATokenManagerHMAC(mfaTokenSecret string) *MFATokenManagerHMAC {
+	return &MFATokenManagerHMAC{MFATokenSecret: mfaTokenSecret}
+}
+
+// New creates and signs a new challenge token with the given claims.
+func (h *MFATokenManagerHMAC) New(claims MFATokenClaims) (string, error) {
+	return NewHMAC(h.MFATokenSecret, claims)
+}
+
+// Parse parses and validates the signature and claims of a challenge token.
+func (h *MFATokenManagerHMAC) Parse(token string) (MFATokenClaims, error) {
+	return ParseHMAC[MFATokenClaims](h.MFATokenSecret, token)
+}
@@ -4,10 +4,15 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
-// RefreshTokenClaims represents the claims in a refresh token.
+// RefreshTokenClaims represents the claims in a refresh token. ClientID and Scope are set only for tokens issued to an
+// OAuth2 client, so that refreshing can't widen what the client was granted. SessionID is passed on to the tokens
+// issued when refreshing.
 type RefreshTokenClaims struct {
 	jwt.RegisteredClaims
-	UserID string `json:"user_id"`
+	UserID    string `json:"user_id"`
+	ClientID  string `json:"client_id,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	SessionID string `json:"sid,omitempty"`
 }
 
 // RefreshTokenManagerHMAC is a struct for managing refresh tokens using HMAC algorithm.
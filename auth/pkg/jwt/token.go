@@ -1,5 +1,5 @@
-// Package jwt provides functionality for creating and parsing JSON Web Tokens (JWTs) using HMAC-SHA256 for signature
-// validation.
+// Package jwt provides functionality for creating and parsing JSON Web Tokens (JWTs), using HMAC-SHA256 for signature
+// validation, besides OpenID Connect ID tokens, which are signed with RS256.
 package jwt
 
 import (
@@ -0,0 +1,154 @@
+// Package messagebus provides a publish-subscribe message bus in the style of NATS: messages are published under a
+// subject and delivered to everyone subscribed to that subject at the time. Delivery is at most once, messages
+// published while a subscriber is not connected are lost to it, so subscribers must have another way to catch up.
+package messagebus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/go-redis/redis/v9"
+)
+
+// Message is a message delivered to subscribers of its subject.
+type Message struct {
+	Subject string
+	Data    []byte
+}
+
+// Handler handles the messages of a subscription. Messages of a subscription are handled one at a time.
+type Handler func(ctx context.Context, message Message)
+
+// Subscription is a subscription to a subject, which delivers messages until it is unsubscribed.
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// MemoryBus is a message bus within a single process. Publish calls the handlers of all subscriptions to the subject
+// before it returns.
+type MemoryBus struct {
+	mu            sync.Mutex
+	subscriptions map[string][]*memorySubscription
+}
+
+type memorySubscription struct {
+	bus     *MemoryBus
+	subject string
+
+	mu      sync.Mutex
+	handler Handler
+}
+
+// NewMemoryBus creates a new MemoryBus without subscriptions.
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{subscriptions: make(map[string][]*memorySubscription)}
+}
+
+// Publish delivers a message to all subscriptions to the subject.
+func (b *MemoryBus) Publish(ctx context.Context, subject string, data []byte) error {
+	b.mu.Lock()
+	subscriptions := append([]*memorySubscription(nil), b.subscriptions[subject]...)
+	b.mu.Unlock()
+
+	for _, subscription := range subscriptions {
+		subscription.deliver(ctx, Message{Subject: subject, Data: data})
+	}
+	return nil
+}
+
+// Subscribe subscribes the handler to the messages published under the subject.
+func (b *MemoryBus) Subscribe(_ context.Context, subject string, handler Handler) (Subscription, error) {
+	subscription := &memorySubscription{bus: b, subject: subject, handler: handler}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscriptions[subject] = append(b.subscriptions[subject], subscription)
+	return subscription, nil
+}
+
+func (s *memorySubscription) deliver(ctx context.Context, message Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.handler != nil {
+		s.handler(ctx, message)
+	}
+}
+
+func (s *memorySubscription) Unsubscribe() error {
+	s.bus.mu.Lock()
+	subscriptions := s.bus.subscriptions[s.subject]
+	for i, subscription := range subscriptions {
+		if subscription == s {
+			s.bus.subscriptions[s.subject] = append(subscriptions[:i:i], subscriptions[i+1:]...)
+			break
+		}
+	}
+	s.bus.mu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handler = nil
+	return nil
+}
+
+// RedisBus is a message bus between processes on top of Redis Pub/Sub.
+type RedisBus struct {
+	db *redis.Client
+}
+
+// NewRedisBus creates a new RedisBus with the provided Redis db.
+//
+// Returns an error if the db is nil.
+func NewRedisBus(db *redis.Client) (*RedisBus, error) {
+	if db == nil {
+		return nil, errors.New("redis db is nil")
+	}
+	return &RedisBus{db: db}, nil
+}
+
+// Publish publishes a message to the Redis channel named after the subject.
+func (b *RedisBus) Publish(ctx context.Context, subject string, data []byte) error {
+	if err := b.db.Publish(ctx, subject, data).Err(); err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+	return nil
+}
+
+// Subscribe subscribes the handler to the Redis channel named after the subject. The subscription is confirmed
+// before Subscribe returns, so messages published afterwards are delivered. Messages are handled in a separate
+// goroutine.
+func (b *RedisBus) Subscribe(ctx context.Context, subject string, handler Handler) (Subscription, error) {
+	pubsub := b.db.Subscribe(ctx, subject)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	subscription := &redisSubscription{pubsub: pubsub, done: make(chan struct{})}
+	go func() {
+		defer close(subscription.done)
+		for message := range pubsub.Channel() {
+			handler(context.Background(), Message{Subject: message.Channel, Data: []byte(message.Payload)})
+		}
+	}()
+	return subscription, nil
+}
+
+type redisSubscription struct {
+	pubsub *redis.PubSub
+	done   chan struct{}
+}
+
+// Unsubscribe closes the subscription and waits for the message being handled, if any.
+func (s *redisSubscription) Unsubscribe() error {
+	if err := s.pubsub.Close(); err != nil {
+		return fmt.Errorf("failed to close subscription: %w", err)
+	}
+	<-s.done
+	return nil
+}
@@ -0,0 +1,58 @@
+package messagebus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBus(t *testing.T) {
+	t.Run("should deliver messages to subscriptions of subject", func(t *testing.T) {
+		bus := NewMemoryBus()
+
+		var a, b []Message
+		subscriptionA, err := bus.Subscribe(context.Background(), "subject", func(_ context.Context, message Message) {
+			a = append(a, message)
+		})
+		require.NoError(t, err)
+		_, err = bus.Subscribe(context.Background(), "other", func(_ context.Context, message Message) {
+			b = append(b, message)
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, bus.Publish(context.Background(), "subject", []byte("first")))
+		require.NoError(t, subscriptionA.Unsubscribe())
+		require.NoError(t, bus.Publish(context.Background(), "subject", []byte("second")))
+
+		assert.Equal(t, []Message{{Subject: "subject", Data: []byte("first")}}, a)
+		assert.Empty(t, b)
+	})
+
+	t.Run("should allow handlers to publish", func(t *testing.T) {
+		bus := NewMemoryBus()
+
+		var received []string
+		_, err := bus.Subscribe(context.Background(), "first", func(ctx context.Context, message Message) {
+			received = append(received, message.Subject)
+			_ = bus.Publish(ctx, "second", message.Data)
+		})
+		require.NoError(t, err)
+		_, err = bus.Subscribe(context.Background(), "second", func(_ context.Context, message Message) {
+			received = append(received, message.Subject)
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, bus.Publish(context.Background(), "first", nil))
+		assert.Equal(t, []string{"first", "second"}, received)
+	})
+}
+
+func TestNewRedisBus(t *testing.T) {
+	t.Run("should return error when db is nil", func(t *testing.T) {
+		bus, err := NewRedisBus(nil)
+		assert.EqualError(t, err, "redis db is nil")
+		assert.Nil(t, bus)
+	})
+}
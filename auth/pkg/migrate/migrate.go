@@ -0,0 +1,159 @@
+// Package migrate applies the migrations of a database schema that are embedded in the binary, with the migrate
+// subcommand of a service or at its startup.
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// ErrUsage is returned by Run when the arguments of the migrate subcommand are invalid.
+var ErrUsage = errors.New("usage: migrate up [N] | down [N] | status | force VERSION")
+
+// Status is the state of the schema of a database.
+type Status struct {
+	Version uint // Version is the version of the last applied migration, 0 if none is applied.
+	Dirty   bool // Dirty reports whether the last migration failed halfway and has to be fixed and forced.
+}
+
+// Migrator applies the migrations of a schema to a database. The database driver takes a lock for the time
+// migrations are applied, so that replicas that start at the same time don't apply them concurrently.
+type Migrator struct {
+	migrate *migrate.Migrate
+}
+
+// NewMigrator creates a new Migrator that applies the migrations found at the root of migrations, named like
+// 000001_init.up.sql, to the database of driver. The migrator owns driver and closes it on Close.
+func NewMigrator(migrations fs.FS, databaseName string, driver database.Driver) (*Migrator, error) {
+	source, err := iofs.New(migrations, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, databaseName, driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrate: %w", err)
+	}
+	return &Migrator{migrate: m}, nil
+}
+
+// Up applies the migrations that are not applied yet. It does nothing if the schema is up to date.
+func (m *Migrator) Up() error {
+	if err := m.migrate.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	return nil
+}
+
+// UpSteps applies the next n migrations.
+func (m *Migrator) UpSteps(n int) error {
+	if err := m.migrate.Steps(n); err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	return nil
+}
+
+// Down rolls back the last n applied migrations.
+func (m *Migrator) Down(n int) error {
+	if err := m.migrate.Steps(-n); err != nil {
+		return fmt.Errorf("failed to roll back migrations: %w", err)
+	}
+	return nil
+}
+
+// Status returns the state of the schema of the database.
+func (m *Migrator) Status() (Status, error) {
+	version, dirty, err := m.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return Status{}, nil
+	} else if err != nil {
+		return Status{}, fmt.Errorf("failed to get version: %w", err)
+	}
+	return Status{Version: version, Dirty: dirty}, nil
+}
+
+// Force sets the version of the schema without applying any migration and clears the dirty state. It is used once a
+// failed migration is fixed by hand, or to adopt a database whose schema was created without the migrator.
+func (m *Migrator) Force(version int) error {
+	if err := m.migrate.Force(version); err != nil {
+		return fmt.Errorf("failed to force version: %w", err)
+	}
+	return nil
+}
+
+// Close closes the migrations and the database driver.
+func (m *Migrator) Close() error {
+	sourceErr, databaseErr := m.migrate.Close()
+	return errors.Join(sourceErr, databaseErr)
+}
+
+// Run runs the migrate subcommand with args, the arguments that follow it, and writes the resulting status to out:
+//
+//	up [N]         applies all the migrations that are not applied yet, or the next N
+//	down [N]       rolls back the last applied migration, or the last N
+//	status         prints the version of the schema
+//	force VERSION  sets the version of the schema without applying any migration
+//
+// It returns ErrUsage if args are invalid.
+func Run(m *Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+
+	var err error
+	switch command, args := args[0], args[1:]; {
+	case command == "up" && len(args) == 0:
+		err = m.Up()
+	case command == "up" && len(args) == 1:
+		n, ok := parsePositive(args[0])
+		if !ok {
+			return ErrUsage
+		}
+		err = m.UpSteps(n)
+	case command == "down" && len(args) <= 1:
+		n := 1
+		if len(args) == 1 {
+			var ok bool
+			if n, ok = parsePositive(args[0]); !ok {
+				return ErrUsage
+			}
+		}
+		err = m.Down(n)
+	case command == "status" && len(args) == 0:
+	case command == "force" && len(args) == 1:
+		version, parseErr := strconv.Atoi(args[0])
+		if parseErr != nil || version < -1 {
+			return ErrUsage
+		}
+		err = m.Force(version)
+	default:
+		return ErrUsage
+	}
+	if err != nil {
+		return err
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	if status.Dirty {
+		_, err = fmt.Fprintf(out, "version: %d (dirty)\n", status.Version)
+	} else {
+		_, err = fmt.Fprintf(out, "version: %d\n", status.Version)
+	}
+	return err
+}
+
+func parsePositive(s string) (int, bool) {
+	n, err := strconv.Atoi(s)
+	return n, err == nil && n > 0
+}
@@ -0,0 +1,85 @@
+package migrate
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/golang-migrate/migrate/v4/database/stub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStubMigrator(t *testing.T) (*Migrator, *stub.Stub) {
+	migrations := fstest.MapFS{
+		"000001_init.up.sql":     {Data: []byte("1 up")},
+		"000001_init.down.sql":   {Data: []byte("1 down")},
+		"000002_users.up.sql":    {Data: []byte("2 up")},
+		"000002_users.down.sql":  {Data: []byte("2 down")},
+		"000003_events.up.sql":   {Data: []byte("3 up")},
+		"000003_events.down.sql": {Data: []byte("3 down")},
+	}
+
+	driver, err := stub.WithInstance(nil, &stub.Config{})
+	require.NoError(t, err)
+
+	migrator, err := NewMigrator(migrations, "stub", driver)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = migrator.Close() })
+	return migrator, driver.(*stub.Stub)
+}
+
+func TestRun(t *testing.T) {
+	t.Run("should apply and roll back migrations", func(t *testing.T) {
+		migrator, driver := newStubMigrator(t)
+
+		var out bytes.Buffer
+		require.NoError(t, Run(migrator, []string{"status"}, &out))
+		assert.Equal(t, "version: 0\n", out.String())
+
+		out.Reset()
+		require.NoError(t, Run(migrator, []string{"up", "1"}, &out))
+		assert.Equal(t, "version: 1\n", out.String())
+
+		out.Reset()
+		require.NoError(t, Run(migrator, []string{"up"}, &out))
+		assert.Equal(t, "version: 3\n", out.String())
+		assert.Equal(t, []string{"1 up", "2 up", "3 up"}, driver.MigrationSequence)
+
+		// The schema is up to date, up does nothing.
+		require.NoError(t, Run(migrator, []string{"up"}, &out))
+
+		out.Reset()
+		require.NoError(t, Run(migrator, []string{"down"}, &out))
+		assert.Equal(t, "version: 2\n", out.String())
+
+		out.Reset()
+		require.NoError(t, Run(migrator, []string{"down", "2"}, &out))
+		assert.Equal(t, "version: 0\n", out.String())
+		assert.Equal(t, []string{"1 up", "2 up", "3 up", "3 down", "2 down", "1 down"}, driver.MigrationSequence)
+	})
+
+	t.Run("should force the version", func(t *testing.T) {
+		migrator, driver := newStubMigrator(t)
+		driver.CurrentVersion, driver.IsDirty = 2, true
+
+		var out bytes.Buffer
+		require.NoError(t, Run(migrator, []string{"status"}, &out))
+		assert.Equal(t, "version: 2 (dirty)\n", out.String())
+
+		out.Reset()
+		require.NoError(t, Run(migrator, []string{"force", "1"}, &out))
+		assert.Equal(t, "version: 1\n", out.String())
+		assert.Empty(t, driver.MigrationSequence)
+	})
+
+	t.Run("should return usage error when arguments are invalid", func(t *testing.T) {
+		migrator, _ := newStubMigrator(t)
+
+		for _, args := range [][]string{
+			nil, {"sideways"}, {"up", "0"}, {"up", "x"}, {"down", "1", "2"}, {"force"}, {"status", "1"},
+		} {
+			assert.ErrorIs(t, Run(migrator, args, &bytes.Buffer{}), ErrUsage, args)
+		}
+	})
+}
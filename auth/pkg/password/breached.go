@@ -0,0 +1,81 @@
+package password
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// BreachedList is a list of passwords known from data breaches, kept in memory as the first 8 bytes of their SHA-1
+// hashes. With prefixes this long, a password is wrongly reported as breached with a negligible probability, while the
+// list takes 8 bytes per password.
+type BreachedList struct {
+	prefixes []uint64
+}
+
+// LoadBreachedList loads a breached password list from a gzip-compressed file of hex-encoded SHA-1 hashes of the
+// passwords, one per line. Anything after a colon is ignored, so the "<hash>:<count>" lines of the Have I Been Pwned
+// password lists can be used as they are, and it is enough for lines to hold the first 16 characters of the hashes.
+func LoadBreachedList(name string) (*BreachedList, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	return NewBreachedList(reader)
+}
+
+// NewBreachedList reads a breached password list, in the format LoadBreachedList describes, without compression.
+func NewBreachedList(r io.Reader) (*BreachedList, error) {
+	var prefixes []uint64
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		hash, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if len(hash) == 0 {
+			continue
+		} else if len(hash) < 16 {
+			return nil, fmt.Errorf("invalid hash on line %d", line)
+		}
+
+		prefix, err := hex.DecodeString(hash[:16])
+		if err != nil {
+			return nil, fmt.Errorf("invalid hash on line %d: %w", line, err)
+		}
+		prefixes = append(prefixes, binary.BigEndian.Uint64(prefix))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read list: %w", err)
+	}
+
+	sort.Slice(prefixes, func(i, j int) bool { return prefixes[i] < prefixes[j] })
+	return &BreachedList{prefixes: prefixes}, nil
+}
+
+// Len returns the number of passwords in the list.
+func (l *BreachedList) Len() int {
+	return len(l.prefixes)
+}
+
+// Contains reports whether the password is in the list.
+func (l *BreachedList) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	prefix := binary.BigEndian.Uint64(sum[:8])
+
+	i := sort.Search(len(l.prefixes), func(i int) bool { return l.prefixes[i] >= prefix })
+	return i < len(l.prefixes) && l.prefixes[i] == prefix
+}
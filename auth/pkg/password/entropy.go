@@ -0,0 +1,53 @@
+package password
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Entropy estimates the entropy of the password in bits, as the length of the password times the bits needed for a
+// character of the character classes it uses. Repeated characters and runs of consecutive characters, like "aaa" or
+// "abc", count only for the first two characters.
+func Entropy(password string) float64 {
+	var lower, upper, digit, symbol, other bool
+	var length int
+
+	runes := []rune(password)
+	for i, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < unicode.MaxASCII && strings.ContainsRune(" !\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", r):
+			symbol = true
+		default:
+			other = true
+		}
+
+		if i > 0 && r == runes[i-1] {
+			continue
+		} else if i > 1 && r-runes[i-1] == runes[i-1]-runes[i-2] && (r-runes[i-1] == 1 || r-runes[i-1] == -1) {
+			continue
+		}
+		length++
+	}
+
+	var pool int
+	for _, class := range []struct {
+		used bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if class.used {
+			pool += class.size
+		}
+	}
+
+	if pool == 0 {
+		return 0
+	}
+	return float64(length) * math.Log2(float64(pool))
+}
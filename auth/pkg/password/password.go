@@ -0,0 +1,177 @@
+// Package password implements password hashing with argon2id and bcrypt. Argon2id hashes are PHC string format
+// hashes, "$argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>", and bcrypt hashes are in their usual "$2a$<cost>$..." format,
+// so the algorithm and its parameters can be told from every stored hash and any of them can be verified. It also
+// estimates the strength of passwords and checks them against lists of breached passwords.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrMismatchedHashAndPassword is returned by Verify when the password does not match the hash.
+	ErrMismatchedHashAndPassword = errors.New("hash and password mismatch")
+	// ErrUnknownAlgorithm is returned by Verify when the hash was made by an algorithm that is not supported.
+	ErrUnknownAlgorithm = errors.New("unknown hash algorithm")
+	// ErrInvalidHash is returned by Verify when the hash is malformed.
+	ErrInvalidHash = errors.New("invalid hash")
+)
+
+// Verify checks the password against a hash made by any of the supported algorithms.
+//
+// If the password does not match, returns ErrMismatchedHashAndPassword.
+func Verify(password, hash string) error {
+	switch {
+	case strings.HasPrefix(hash, "$argon2id$"):
+		return verifyArgon2id(password, hash)
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrMismatchedHashAndPassword
+		} else if err != nil {
+			return errors.Join(err, ErrInvalidHash)
+		}
+		return nil
+	default:
+		return ErrUnknownAlgorithm
+	}
+}
+
+// Argon2idParams are the parameters of argon2id. Memory is in KiB.
+type Argon2idParams struct {
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+}
+
+const (
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+)
+
+// Argon2idHasher hashes passwords with argon2id.
+type Argon2idHasher struct {
+	params Argon2idParams
+}
+
+// NewArgon2idHasher creates a new Argon2idHasher with the provided parameters.
+//
+// Returns an error if any of the parameters is zero.
+func NewArgon2idHasher(params Argon2idParams) (*Argon2idHasher, error) {
+	if params.Memory == 0 {
+		return nil, fmt.Errorf("memory is zero")
+	} else if params.Time == 0 {
+		return nil, fmt.Errorf("time is zero")
+	} else if params.Threads == 0 {
+		return nil, fmt.Errorf("threads is zero")
+	}
+	return &Argon2idHasher{params: params}, nil
+}
+
+// Hash returns the PHC string format argon2id hash of the password with a new random salt.
+func (h Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2idSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Time, h.params.Memory, h.params.Threads, argon2idKeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.params.Memory, h.params.Time, h.params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks the password against a hash made by any of the supported algorithms.
+func (h Argon2idHasher) Verify(password, hash string) error {
+	return Verify(password, hash)
+}
+
+// NeedsRehash reports whether the hash was not made by argon2id with the parameters of the hasher.
+func (h Argon2idHasher) NeedsRehash(hash string) bool {
+	params, _, _, err := parseArgon2id(hash)
+	return err != nil || params != h.params
+}
+
+func verifyArgon2id(password, hash string) error {
+	params, salt, key, err := parseArgon2id(hash)
+	if err != nil {
+		return err
+	}
+
+	actual := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return ErrMismatchedHashAndPassword
+	}
+	return nil
+}
+
+func parseArgon2id(hash string) (params Argon2idParams, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Argon2idParams{}, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return Argon2idParams{}, nil, nil, errors.Join(err, ErrInvalidHash)
+	} else if version != argon2.Version {
+		return Argon2idParams{}, nil, nil, ErrUnknownAlgorithm
+	}
+
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads)
+	if err != nil {
+		return Argon2idParams{}, nil, nil, errors.Join(err, ErrInvalidHash)
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return Argon2idParams{}, nil, nil, errors.Join(err, ErrInvalidHash)
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(key) == 0 {
+		return Argon2idParams{}, nil, nil, errors.Join(err, ErrInvalidHash)
+	}
+	return params, salt, key, nil
+}
+
+// BcryptHasher hashes passwords with bcrypt.
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher creates a new BcryptHasher with the provided cost.
+//
+// Returns an error if the cost is out of the range bcrypt supports.
+func NewBcryptHasher(cost int) (*BcryptHasher, error) {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("cost is out of range")
+	}
+	return &BcryptHasher{cost: cost}, nil
+}
+
+// Hash returns the bcrypt hash of the password.
+func (h BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate hash: %w", err)
+	}
+	return string(hash), nil
+}
+
+// Verify checks the password against a hash made by any of the supported algorithms.
+func (h BcryptHasher) Verify(password, hash string) error {
+	return Verify(password, hash)
+}
+
+// NeedsRehash reports whether the hash was not made by bcrypt with the cost of the hasher.
+func (h BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.cost
+}
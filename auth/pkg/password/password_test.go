@@ -0,0 +1,125 @@
+package password
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// params are small argon2id parameters that keep the tests fast.
+var params = Argon2idParams{Memory: 1024, Time: 1, Threads: 1}
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher, err := NewArgon2idHasher(params)
+	require.NoError(t, err)
+
+	hash, err := hasher.Hash("password")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+	other, err := hasher.Hash("password")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+
+	assert.NoError(t, hasher.Verify("password", hash))
+	assert.ErrorIs(t, hasher.Verify("wrong", hash), ErrMismatchedHashAndPassword)
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	stronger, err := NewArgon2idHasher(Argon2idParams{Memory: 2048, Time: 1, Threads: 1})
+	require.NoError(t, err)
+	assert.True(t, stronger.NeedsRehash(hash))
+	assert.NoError(t, stronger.Verify("password", hash))
+
+	_, err = NewArgon2idHasher(Argon2idParams{Time: 1, Threads: 1})
+	assert.Error(t, err)
+}
+
+func TestBcryptHasher(t *testing.T) {
+	hasher, err := NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+
+	hash, err := hasher.Hash("password")
+	require.NoError(t, err)
+
+	assert.NoError(t, hasher.Verify("password", hash))
+	assert.ErrorIs(t, hasher.Verify("wrong", hash), ErrMismatchedHashAndPassword)
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	stronger, err := NewBcryptHasher(bcrypt.MinCost + 1)
+	require.NoError(t, err)
+	assert.True(t, stronger.NeedsRehash(hash))
+
+	_, err = NewBcryptHasher(bcrypt.MaxCost + 1)
+	assert.Error(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	argon2id, err := NewArgon2idHasher(params)
+	require.NoError(t, err)
+	bcryptHasher, err := NewBcryptHasher(bcrypt.MinCost)
+	require.NoError(t, err)
+
+	bcryptHash, err := bcryptHasher.Hash("password")
+	require.NoError(t, err)
+
+	// A bcrypt hash verifies with the argon2id hasher and has to be rehashed.
+	assert.NoError(t, argon2id.Verify("password", bcryptHash))
+	assert.True(t, argon2id.NeedsRehash(bcryptHash))
+
+	assert.ErrorIs(t, Verify("password", "plain"), ErrUnknownAlgorithm)
+	assert.ErrorIs(t, Verify("password", "$argon2id$v=19$m=1024"), ErrInvalidHash)
+	assert.ErrorIs(t, Verify("password", "$argon2id$v=19$m=1024,t=1,p=1$!$!"), ErrInvalidHash)
+}
+
+func TestEntropy(t *testing.T) {
+	tests := []struct {
+		password string
+		min, max float64
+	}{
+		{password: "", min: 0, max: 0},
+		{password: "aaaaaaaaaaaa", min: 4, max: 5},
+		{password: "abcdefghijkl", min: 9, max: 10},
+		{password: "password", min: 32, max: 33},
+		{password: "Tr0ub4dor&3", min: 72, max: 73},
+		{password: "correct horse battery staple", min: 150, max: 160},
+	}
+
+	for _, test := range tests {
+		entropy := Entropy(test.password)
+		assert.GreaterOrEqual(t, entropy, test.min, test.password)
+		assert.LessOrEqual(t, entropy, test.max, test.password)
+	}
+}
+
+func TestBreachedList(t *testing.T) {
+	// SHA-1 hashes of "password", with a count like in the Have I Been Pwned lists, and of "Password1!", cut short.
+	list := "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:10434004\n\n32CA9FC1A0F5B633\n"
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err := writer.Write([]byte(list))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	name := filepath.Join(t.TempDir(), "breached.txt.gz")
+	require.NoError(t, os.WriteFile(name, compressed.Bytes(), 0o600))
+
+	breached, err := LoadBreachedList(name)
+	require.NoError(t, err)
+	assert.Equal(t, 2, breached.Len())
+	assert.True(t, breached.Contains("password"))
+	assert.True(t, breached.Contains("Password1!"))
+	assert.False(t, breached.Contains("correct horse battery staple"))
+
+	_, err = NewBreachedList(strings.NewReader("5BAA61E4\n"))
+	assert.Error(t, err)
+	_, err = NewBreachedList(strings.NewReader("ZZZZZZZZZZZZZZZZ\n"))
+	assert.Error(t, err)
+}
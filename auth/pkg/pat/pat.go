@@ -0,0 +1,13 @@
+// Package pat recognizes personal access tokens. They are opaque and can only be resolved by the auth service, so
+// services accepting both them and JWT access tokens tell them apart by their prefix.
+package pat
+
+import "strings"
+
+// Prefix starts every personal access token.
+const Prefix = "unp_"
+
+// IsPersonalAccessToken reports whether the token is a personal access token rather than a JWT.
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, Prefix)
+}
@@ -0,0 +1,62 @@
+// Package ratelimit provides an in-memory token bucket rate limiter keyed by an arbitrary string, such as a client IP
+// address or a username.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limiter limits how often an event may happen for each key. Limiters of keys that have not been seen for a while
+// are dropped to keep memory bounded.
+type Limiter struct {
+	limit rate.Limit
+	burst int
+	ttl   time.Duration
+
+	mu       sync.Mutex
+	limiters map[string]*entry
+	cleaned  time.Time
+}
+
+type entry struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// NewLimiter creates a new Limiter that allows up to n events per period for each key, with bursts of up to n events.
+func NewLimiter(n int, period time.Duration) *Limiter {
+	return &Limiter{
+		limit:    rate.Every(period / time.Duration(n)),
+		burst:    n,
+		ttl:      period,
+		limiters: make(map[string]*entry),
+		cleaned:  time.Now(),
+	}
+}
+
+// Allow reports whether an event for the key may happen now.
+func (l *Limiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.cleaned) > l.ttl {
+		for k, e := range l.limiters {
+			if now.Sub(e.seen) > l.ttl {
+				delete(l.limiters, k)
+			}
+		}
+		l.cleaned = now
+	}
+
+	e, ok := l.limiters[key]
+	if !ok {
+		e = &entry{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[key] = e
+	}
+	e.seen = now
+	return e.limiter.AllowN(now, 1)
+}
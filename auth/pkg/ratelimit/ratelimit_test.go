@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	t.Run("should allow up to n events per key", func(t *testing.T) {
+		limiter := NewLimiter(3, time.Minute)
+
+		assert.True(t, limiter.Allow("a"))
+		assert.True(t, limiter.Allow("a"))
+		assert.True(t, limiter.Allow("a"))
+		assert.False(t, limiter.Allow("a"))
+
+		assert.True(t, limiter.Allow("b"))
+	})
+
+	t.Run("should allow events again after the period", func(t *testing.T) {
+		limiter := NewLimiter(1, 50*time.Millisecond)
+
+		assert.True(t, limiter.Allow("a"))
+		assert.False(t, limiter.Allow("a"))
+
+		time.Sleep(60 * time.Millisecond)
+		assert.True(t, limiter.Allow("a"))
+	})
+
+	t.Run("should drop limiters of keys not seen for a while", func(t *testing.T) {
+		limiter := NewLimiter(1, 10*time.Millisecond)
+		limiter.Allow("a")
+
+		time.Sleep(25 * time.Millisecond)
+		limiter.Allow("b")
+
+		assert.NotContains(t, limiter.limiters, "a")
+		assert.Contains(t, limiter.limiters, "b")
+	})
+}
@@ -0,0 +1,104 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// secret is the RFC 6238 SHA-1 test secret "12345678901234567890" encoded in base32.
+const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, a, 32)
+
+	b, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, a, b)
+}
+
+func TestGenerateCode(t *testing.T) {
+	tests := []struct {
+		time     int64
+		expected string
+	}{
+		{time: 59, expected: "287082"},
+		{time: 1111111109, expected: "081804"},
+		{time: 1111111111, expected: "050471"},
+		{time: 1234567890, expected: "005924"},
+		{time: 2000000000, expected: "279037"},
+		{time: 20000000000, expected: "353130"},
+	}
+
+	for _, test := range tests {
+		code, err := GenerateCode(secret, time.Unix(test.time, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, code)
+	}
+
+	t.Run("should return an error if secret is invalid", func(t *testing.T) {
+		code, err := GenerateCode("not base32!", time.Now())
+		assert.ErrorIs(t, err, ErrInvalidSecret)
+		assert.Empty(t, code)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	t.Run("should accept the current code", func(t *testing.T) {
+		step, ok, err := Validate(secret, "050471", now)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, Step(now), step)
+	})
+
+	t.Run("should accept codes within the allowed skew", func(t *testing.T) {
+		previous, err := GenerateCode(secret, now.Add(-Period))
+		require.NoError(t, err)
+
+		step, ok, err := Validate(secret, previous, now)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, Step(now)-1, step)
+	})
+
+	t.Run("should reject codes outside the allowed skew", func(t *testing.T) {
+		old, err := GenerateCode(secret, now.Add(-3*Period))
+		require.NoError(t, err)
+
+		_, ok, err := Validate(secret, old, now)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("should reject codes of the wrong length", func(t *testing.T) {
+		_, ok, err := Validate(secret, "12345", now)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("should return an error if secret is invalid", func(t *testing.T) {
+		_, ok, err := Validate("", "050471", now)
+		assert.ErrorIs(t, err, ErrInvalidSecret)
+		assert.False(t, ok)
+	})
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(URI("unotes", "username", secret))
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/unotes:username", uri.Path)
+	assert.Equal(t, secret, uri.Query().Get("secret"))
+	assert.Equal(t, "unotes", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.Equal(t, "30", uri.Query().Get("period"))
+}
@@ -0,0 +1,17 @@
+CREATE TABLE "user_totp"
+(
+    "user_id"        uuid        NOT NULL,
+    "secret"         varchar(64) NOT NULL,
+    "enabled"        boolean     NOT NULL DEFAULT FALSE,
+    "last_used_step" bigint      NOT NULL DEFAULT 0,
+    CONSTRAINT "user_totp_pk" PRIMARY KEY ("user_id"),
+    CONSTRAINT "user_totp_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+) WITH (OIDS = FALSE);
+
+CREATE TABLE "user_recovery_codes"
+(
+    "user_id"   uuid        NOT NULL,
+    "code_hash" varchar(64) NOT NULL,
+    CONSTRAINT "user_recovery_codes_pk" PRIMARY KEY ("user_id", "code_hash"),
+    CONSTRAINT "user_recovery_codes_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+) WITH (OIDS = FALSE);
@@ -0,0 +1,10 @@
+CREATE TABLE "password_reset_tokens"
+(
+    "token_hash" varchar(64) NOT NULL,
+    "user_id"    uuid        NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    CONSTRAINT "password_reset_tokens_pk" PRIMARY KEY ("token_hash"),
+    CONSTRAINT "password_reset_tokens_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+) WITH (OIDS = FALSE);
+
+CREATE INDEX "password_reset_tokens_user_id_idx" ON "password_reset_tokens" ("user_id");
@@ -0,0 +1,7 @@
+DROP TABLE "email_verification_tokens";
+
+DROP INDEX "users_email_key";
+
+ALTER TABLE "users"
+    DROP COLUMN "email_verified",
+    DROP COLUMN "email";
@@ -0,0 +1,17 @@
+ALTER TABLE "users"
+    ADD COLUMN "email"          varchar(255),
+    ADD COLUMN "email_verified" boolean NOT NULL DEFAULT FALSE;
+
+CREATE UNIQUE INDEX "users_email_key" ON "users" (lower("email"));
+
+CREATE TABLE "email_verification_tokens"
+(
+    "token_hash" varchar(64)  NOT NULL,
+    "user_id"    uuid         NOT NULL,
+    "email"      varchar(255) NOT NULL,
+    "expires_at" timestamptz  NOT NULL,
+    CONSTRAINT "email_verification_tokens_pk" PRIMARY KEY ("token_hash"),
+    CONSTRAINT "email_verification_tokens_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+) WITH (OIDS = FALSE);
+
+CREATE INDEX "email_verification_tokens_user_id_idx" ON "email_verification_tokens" ("user_id");
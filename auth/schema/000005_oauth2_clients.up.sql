@@ -0,0 +1,25 @@
+CREATE TABLE "oauth2_clients"
+(
+    "id"            varchar(64)  NOT NULL,
+    "secret_hash"   varchar(64)  NOT NULL DEFAULT '',
+    "name"          varchar(255) NOT NULL,
+    "redirect_uris" text[]       NOT NULL DEFAULT '{}',
+    "scopes"        text[]       NOT NULL DEFAULT '{}',
+    "grant_types"   text[]       NOT NULL DEFAULT '{}',
+    "public"        boolean      NOT NULL DEFAULT FALSE,
+    CONSTRAINT "oauth2_clients_pk" PRIMARY KEY ("id")
+) WITH (OIDS = FALSE);
+
+CREATE TABLE "oauth2_authorization_codes"
+(
+    "code_hash"      varchar(64)  NOT NULL,
+    "client_id"      varchar(64)  NOT NULL,
+    "user_id"        uuid         NOT NULL,
+    "redirect_uri"   text         NOT NULL,
+    "scope"          text         NOT NULL DEFAULT '',
+    "code_challenge" varchar(128) NOT NULL,
+    "expires_at"     timestamptz  NOT NULL,
+    CONSTRAINT "oauth2_authorization_codes_pk" PRIMARY KEY ("code_hash"),
+    CONSTRAINT "oauth2_authorization_codes_clients_fk" FOREIGN KEY ("client_id") REFERENCES "oauth2_clients" ("id") ON DELETE CASCADE,
+    CONSTRAINT "oauth2_authorization_codes_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+) WITH (OIDS = FALSE);
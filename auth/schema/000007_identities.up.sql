@@ -0,0 +1,21 @@
+CREATE TABLE "identities"
+(
+    "provider" varchar(64)  NOT NULL,
+    "subject"  varchar(255) NOT NULL,
+    "user_id"  uuid         NOT NULL,
+    CONSTRAINT "identities_pk" PRIMARY KEY ("provider", "subject"),
+    CONSTRAINT "identities_provider_user_id_key" UNIQUE ("provider", "user_id"),
+    CONSTRAINT "identities_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+) WITH (OIDS = FALSE);
+
+CREATE TABLE "identity_states"
+(
+    "state_hash"    varchar(64) NOT NULL,
+    "provider"      varchar(64) NOT NULL,
+    "nonce"         text        NOT NULL,
+    "code_verifier" text        NOT NULL,
+    "user_id"       uuid,
+    "expires_at"    timestamptz NOT NULL,
+    CONSTRAINT "identity_states_pk" PRIMARY KEY ("state_hash"),
+    CONSTRAINT "identity_states_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+) WITH (OIDS = FALSE);
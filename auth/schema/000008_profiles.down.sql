@@ -0,0 +1,5 @@
+ALTER TABLE "users"
+    DROP COLUMN "timezone",
+    DROP COLUMN "locale",
+    DROP COLUMN "avatar",
+    DROP COLUMN "display_name";
@@ -0,0 +1,5 @@
+ALTER TABLE "users"
+    ADD COLUMN "display_name" varchar(64)  NOT NULL DEFAULT '',
+    ADD COLUMN "avatar"       varchar(255) NOT NULL DEFAULT '',
+    ADD COLUMN "locale"       varchar(35)  NOT NULL DEFAULT 'en',
+    ADD COLUMN "timezone"     varchar(64)  NOT NULL DEFAULT 'UTC';
@@ -0,0 +1,10 @@
+CREATE TABLE "outbox_messages"
+(
+    "id"         uuid         NOT NULL,
+    "subject"    varchar(255) NOT NULL,
+    "payload"    bytea        NOT NULL,
+    "created_at" timestamptz  NOT NULL DEFAULT now(),
+    CONSTRAINT "outbox_messages_pk" PRIMARY KEY ("id")
+) WITH (OIDS = FALSE);
+
+CREATE INDEX "outbox_messages_created_at_idx" ON "outbox_messages" ("created_at");
@@ -0,0 +1,16 @@
+CREATE TABLE "personal_access_tokens"
+(
+    "id"           uuid        NOT NULL,
+    "user_id"      uuid        NOT NULL,
+    "name"         varchar(64) NOT NULL,
+    "token_hash"   varchar(64) NOT NULL,
+    "scopes"       text[]      NOT NULL,
+    "created_at"   timestamptz NOT NULL,
+    "expires_at"   timestamptz,
+    "last_used_at" timestamptz,
+    CONSTRAINT "personal_access_tokens_pk" PRIMARY KEY ("id"),
+    CONSTRAINT "personal_access_tokens_token_hash_key" UNIQUE ("token_hash"),
+    CONSTRAINT "personal_access_tokens_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+) WITH (OIDS = FALSE);
+
+CREATE INDEX "personal_access_tokens_user_id_idx" ON "personal_access_tokens" ("user_id");
@@ -0,0 +1,20 @@
+CREATE TABLE "auth_events"
+(
+    "id"         uuid        NOT NULL,
+    "type"       varchar(64) NOT NULL,
+    "user_id"    uuid,
+    "ip"         varchar(45) NOT NULL DEFAULT '',
+    "user_agent" text        NOT NULL DEFAULT '',
+    "session_id" varchar(64) NOT NULL DEFAULT '',
+    "outcome"    varchar(16) NOT NULL CHECK ("outcome" IN ('success', 'failure')),
+    "reason"     text        NOT NULL DEFAULT '',
+    "created_at" timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT "auth_events_pk" PRIMARY KEY ("id")
+) WITH (OIDS = FALSE);
+
+CREATE INDEX "auth_events_user_id_created_at_idx" ON "auth_events" ("user_id", "created_at");
+CREATE INDEX "auth_events_created_at_idx" ON "auth_events" ("created_at");
+
+-- Events are append-only, they are only ever deleted once they are older than the retention period. The user ID is
+-- kept when the user is deleted, so there is no foreign key.
+CREATE RULE "auth_events_no_update" AS ON UPDATE TO "auth_events" DO INSTEAD NOTHING;
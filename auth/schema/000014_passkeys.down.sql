@@ -0,0 +1,2 @@
+DROP TABLE "passkey_sessions";
+DROP TABLE "passkeys";
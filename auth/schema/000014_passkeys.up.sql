@@ -0,0 +1,26 @@
+CREATE TABLE "passkeys"
+(
+    "id"           varchar(1366) NOT NULL,
+    "user_id"      uuid          NOT NULL,
+    "name"         varchar(64)   NOT NULL,
+    "public_key"   bytea         NOT NULL,
+    "sign_count"   bigint        NOT NULL,
+    "transports"   text[]        NOT NULL,
+    "created_at"   timestamptz   NOT NULL,
+    "last_used_at" timestamptz,
+    CONSTRAINT "passkeys_pk" PRIMARY KEY ("id"),
+    CONSTRAINT "passkeys_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+) WITH (OIDS = FALSE);
+
+CREATE INDEX "passkeys_user_id_idx" ON "passkeys" ("user_id");
+
+CREATE TABLE "passkey_sessions"
+(
+    "session_hash" varchar(64) NOT NULL,
+    "purpose"      varchar(16) NOT NULL,
+    "user_id"      uuid,
+    "data"         bytea       NOT NULL,
+    "expires_at"   timestamptz NOT NULL,
+    CONSTRAINT "passkey_sessions_pk" PRIMARY KEY ("session_hash"),
+    CONSTRAINT "passkey_sessions_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+) WITH (OIDS = FALSE);
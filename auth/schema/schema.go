@@ -0,0 +1,9 @@
+// Package schema embeds the migrations of the PostgreSQL schema of the service, so that the binary can apply them.
+package schema
+
+import "embed"
+
+// Migrations are the migrations of the schema, named like 000001_init.up.sql.
+//
+//go:embed *.sql
+var Migrations embed.FS
@@ -0,0 +1,7 @@
+CREATE TABLE "users"
+(
+    "id"            text NOT NULL,
+    "username"      text NOT NULL UNIQUE,
+    "password_hash" text NOT NULL,
+    CONSTRAINT "users_pk" PRIMARY KEY ("id")
+);
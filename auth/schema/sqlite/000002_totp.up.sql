@@ -0,0 +1,17 @@
+CREATE TABLE "user_totp"
+(
+    "user_id"        text    NOT NULL,
+    "secret"         text    NOT NULL,
+    "enabled"        boolean NOT NULL DEFAULT FALSE,
+    "last_used_step" integer NOT NULL DEFAULT 0,
+    CONSTRAINT "user_totp_pk" PRIMARY KEY ("user_id"),
+    CONSTRAINT "user_totp_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
+
+CREATE TABLE "user_recovery_codes"
+(
+    "user_id"   text NOT NULL,
+    "code_hash" text NOT NULL,
+    CONSTRAINT "user_recovery_codes_pk" PRIMARY KEY ("user_id", "code_hash"),
+    CONSTRAINT "user_recovery_codes_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
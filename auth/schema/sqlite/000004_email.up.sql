@@ -0,0 +1,18 @@
+ALTER TABLE "users"
+    ADD COLUMN "email" text;
+ALTER TABLE "users"
+    ADD COLUMN "email_verified" boolean NOT NULL DEFAULT FALSE;
+
+CREATE UNIQUE INDEX "users_email_key" ON "users" ("email" COLLATE NOCASE);
+
+CREATE TABLE "email_verification_tokens"
+(
+    "token_hash" text     NOT NULL,
+    "user_id"    text     NOT NULL,
+    "email"      text     NOT NULL,
+    "expires_at" datetime NOT NULL,
+    CONSTRAINT "email_verification_tokens_pk" PRIMARY KEY ("token_hash"),
+    CONSTRAINT "email_verification_tokens_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "email_verification_tokens_user_id_idx" ON "email_verification_tokens" ("user_id");
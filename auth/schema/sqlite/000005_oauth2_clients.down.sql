@@ -0,0 +1,2 @@
+DROP TABLE "oauth2_authorization_codes";
+DROP TABLE "oauth2_clients";
@@ -0,0 +1,26 @@
+-- Lists are stored as JSON arrays.
+CREATE TABLE "oauth2_clients"
+(
+    "id"            text    NOT NULL,
+    "secret_hash"   text    NOT NULL DEFAULT '',
+    "name"          text    NOT NULL,
+    "redirect_uris" text    NOT NULL DEFAULT '[]',
+    "scopes"        text    NOT NULL DEFAULT '[]',
+    "grant_types"   text    NOT NULL DEFAULT '[]',
+    "public"        boolean NOT NULL DEFAULT FALSE,
+    CONSTRAINT "oauth2_clients_pk" PRIMARY KEY ("id")
+);
+
+CREATE TABLE "oauth2_authorization_codes"
+(
+    "code_hash"      text     NOT NULL,
+    "client_id"      text     NOT NULL,
+    "user_id"        text     NOT NULL,
+    "redirect_uri"   text     NOT NULL,
+    "scope"          text     NOT NULL DEFAULT '',
+    "code_challenge" text     NOT NULL,
+    "expires_at"     datetime NOT NULL,
+    CONSTRAINT "oauth2_authorization_codes_pk" PRIMARY KEY ("code_hash"),
+    CONSTRAINT "oauth2_authorization_codes_clients_fk" FOREIGN KEY ("client_id") REFERENCES "oauth2_clients" ("id") ON DELETE CASCADE,
+    CONSTRAINT "oauth2_authorization_codes_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
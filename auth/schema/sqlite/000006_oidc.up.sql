@@ -0,0 +1,2 @@
+ALTER TABLE "oauth2_authorization_codes"
+    ADD COLUMN "nonce" text NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP TABLE "identity_states";
+DROP TABLE "identities";
@@ -0,0 +1,21 @@
+CREATE TABLE "identities"
+(
+    "provider" text NOT NULL,
+    "subject"  text NOT NULL,
+    "user_id"  text NOT NULL,
+    CONSTRAINT "identities_pk" PRIMARY KEY ("provider", "subject"),
+    CONSTRAINT "identities_provider_user_id_key" UNIQUE ("provider", "user_id"),
+    CONSTRAINT "identities_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
+
+CREATE TABLE "identity_states"
+(
+    "state_hash"    text     NOT NULL,
+    "provider"      text     NOT NULL,
+    "nonce"         text     NOT NULL,
+    "code_verifier" text     NOT NULL,
+    "user_id"       text,
+    "expires_at"    datetime NOT NULL,
+    CONSTRAINT "identity_states_pk" PRIMARY KEY ("state_hash"),
+    CONSTRAINT "identity_states_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
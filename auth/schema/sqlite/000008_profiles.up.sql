@@ -0,0 +1,8 @@
+ALTER TABLE "users"
+    ADD COLUMN "display_name" text NOT NULL DEFAULT '';
+ALTER TABLE "users"
+    ADD COLUMN "avatar" text NOT NULL DEFAULT '';
+ALTER TABLE "users"
+    ADD COLUMN "locale" text NOT NULL DEFAULT 'en';
+ALTER TABLE "users"
+    ADD COLUMN "timezone" text NOT NULL DEFAULT 'UTC';
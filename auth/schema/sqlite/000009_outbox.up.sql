@@ -0,0 +1,10 @@
+CREATE TABLE "outbox_messages"
+(
+    "id"         text     NOT NULL,
+    "subject"    text     NOT NULL,
+    "payload"    blob     NOT NULL,
+    "created_at" datetime NOT NULL,
+    CONSTRAINT "outbox_messages_pk" PRIMARY KEY ("id")
+);
+
+CREATE INDEX "outbox_messages_created_at_idx" ON "outbox_messages" ("created_at");
@@ -0,0 +1,17 @@
+-- Scopes are stored as a JSON array.
+CREATE TABLE "personal_access_tokens"
+(
+    "id"           text     NOT NULL,
+    "user_id"      text     NOT NULL,
+    "name"         text     NOT NULL,
+    "token_hash"   text     NOT NULL,
+    "scopes"       text     NOT NULL,
+    "created_at"   datetime NOT NULL,
+    "expires_at"   datetime,
+    "last_used_at" datetime,
+    CONSTRAINT "personal_access_tokens_pk" PRIMARY KEY ("id"),
+    CONSTRAINT "personal_access_tokens_token_hash_key" UNIQUE ("token_hash"),
+    CONSTRAINT "personal_access_tokens_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "personal_access_tokens_user_id_idx" ON "personal_access_tokens" ("user_id");
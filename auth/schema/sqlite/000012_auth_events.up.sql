@@ -0,0 +1,20 @@
+CREATE TABLE "auth_events"
+(
+    "id"         text     NOT NULL,
+    "type"       text     NOT NULL,
+    "user_id"    text,
+    "ip"         text     NOT NULL DEFAULT '',
+    "user_agent" text     NOT NULL DEFAULT '',
+    "session_id" text     NOT NULL DEFAULT '',
+    "outcome"    text     NOT NULL CHECK ("outcome" IN ('success', 'failure')),
+    "reason"     text     NOT NULL DEFAULT '',
+    "created_at" datetime NOT NULL,
+    CONSTRAINT "auth_events_pk" PRIMARY KEY ("id")
+);
+
+CREATE INDEX "auth_events_user_id_created_at_idx" ON "auth_events" ("user_id", "created_at");
+CREATE INDEX "auth_events_created_at_idx" ON "auth_events" ("created_at");
+
+-- Events are append-only, they are only ever deleted once they are older than the retention period. The user ID is
+-- kept when the user is deleted, so there is no foreign key.
+CREATE TRIGGER "auth_events_no_update" BEFORE UPDATE ON "auth_events" BEGIN SELECT RAISE(IGNORE); END;
@@ -0,0 +1,11 @@
+CREATE TABLE "magic_link_tokens"
+(
+    "token_hash" text     NOT NULL,
+    "user_id"    text     NOT NULL,
+    "nonce_hash" text     NOT NULL,
+    "expires_at" datetime NOT NULL,
+    CONSTRAINT "magic_link_tokens_pk" PRIMARY KEY ("token_hash"),
+    CONSTRAINT "magic_link_tokens_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "magic_link_tokens_user_id_idx" ON "magic_link_tokens" ("user_id");
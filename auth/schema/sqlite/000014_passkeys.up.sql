@@ -0,0 +1,27 @@
+-- Transports are stored as a JSON array.
+CREATE TABLE "passkeys"
+(
+    "id"           text     NOT NULL,
+    "user_id"      text     NOT NULL,
+    "name"         text     NOT NULL,
+    "public_key"   blob     NOT NULL,
+    "sign_count"   integer  NOT NULL,
+    "transports"   text     NOT NULL,
+    "created_at"   datetime NOT NULL,
+    "last_used_at" datetime,
+    CONSTRAINT "passkeys_pk" PRIMARY KEY ("id"),
+    CONSTRAINT "passkeys_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "passkeys_user_id_idx" ON "passkeys" ("user_id");
+
+CREATE TABLE "passkey_sessions"
+(
+    "session_hash" text     NOT NULL,
+    "purpose"      text     NOT NULL,
+    "user_id"      text,
+    "data"         blob     NOT NULL,
+    "expires_at"   datetime NOT NULL,
+    CONSTRAINT "passkey_sessions_pk" PRIMARY KEY ("session_hash"),
+    CONSTRAINT "passkey_sessions_users_fk" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
@@ -0,0 +1,8 @@
+-- Deleted users are remembered, so that other services can tell a deleted user from one they failed to look up and
+-- delete what belongs to them.
+CREATE TABLE "deleted_users"
+(
+    "user_id"    text     NOT NULL,
+    "deleted_at" datetime NOT NULL,
+    CONSTRAINT "deleted_users_pk" PRIMARY KEY ("user_id")
+);
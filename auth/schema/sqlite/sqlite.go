@@ -0,0 +1,10 @@
+// Package sqlite embeds the migrations of the SQLite schema of the service, so that the binary can apply them. They
+// mirror the migrations of the PostgreSQL schema one to one, so a version means the same schema on both databases.
+package sqlite
+
+import "embed"
+
+// Migrations are the migrations of the schema, named like 000001_init.up.sql.
+//
+//go:embed *.sql
+var Migrations embed.FS
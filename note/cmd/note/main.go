@@ -7,13 +7,21 @@ import (
 	"os"
 	"time"
 
+	authpb "github.com/nazarslota/unotes/auth/api/proto"
 	"github.com/nazarslota/unotes/auth/pkg/logger"
+	"github.com/nazarslota/unotes/auth/pkg/messagebus"
 	"github.com/nazarslota/unotes/auth/pkg/utils"
 	"github.com/nazarslota/unotes/note/internal/config"
 	"github.com/nazarslota/unotes/note/internal/handler"
 	"github.com/nazarslota/unotes/note/internal/service"
+	servicejwt "github.com/nazarslota/unotes/note/internal/service/jwt"
 	"github.com/nazarslota/unotes/note/internal/storage"
 	"github.com/nazarslota/unotes/note/internal/storage/mongo"
+	"github.com/nazarslota/unotes/note/internal/storage/redis"
+	"github.com/nazarslota/unotes/note/internal/worker"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 var log logger.Logger
@@ -29,33 +37,91 @@ func init() {
 }
 
 func main() {
-	log.Info("Attempting to establish a connection with a MongoDB...")
-	database, err := mongo.NewMongoDB(context.Background(), mongo.Config{
-		Host:     config.C().MongoDB.Host,
-		Port:     config.C().MongoDB.Port,
-		Username: config.C().MongoDB.Username,
-		Password: config.C().MongoDB.Password,
-		Database: config.C().MongoDB.Database,
-	})
-	if err != nil {
-		log.FatalFields("Failed to establish a connection with MongoDB.", map[string]any{"error": err})
-	} else {
-		log.Info("The connection to the database was successfully established.")
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
 	}
 
-	repositories := storage.NewRepositoryProvider(
-		storage.WithMongoNoteRepository(database),
-	)
+	noteServiceOptions := service.NoteServiceOptions{}
+	closeStorage := openStorage(&noteServiceOptions)
 
-	services := service.NewServices(
-		service.JWTServiceOptions{AccessTokenSecret: config.C().Note.AccessTokenSecret},
-		service.NoteServiceOptions{
-			NoteSaver:   repositories.MongoNoteRepository,
-			NoteFinder:  repositories.MongoNoteRepository,
-			NoteUpdater: repositories.MongoNoteRepository,
-			NoteDeleter: repositories.MongoNoteRepository,
-		},
-	)
+	var repositoryOptions []storage.RepositoryProviderOption
+	jwtServiceOptions := service.JWTServiceOptions{AccessTokenSecret: config.C().Note.AccessTokenSecret}
+
+	// Personal access tokens are opaque, so they can only be accepted if they can be introspected.
+	if config.C().Auth.PersonalAccessTokens && len(config.C().Auth.IntrospectionAddr) == 0 {
+		log.Fatal("Personal access tokens are enabled, but NOTE_AUTH_INTROSPECTION_ADDR is not set.")
+	}
+
+	var authConn *grpc.ClientConn
+	if addr := config.C().Auth.IntrospectionAddr; len(addr) != 0 {
+		var err error
+		authConn, err = grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			log.FatalFields("Failed to connect to the auth service.", map[string]any{"error": err})
+		}
+
+		authClient := authpb.NewOAuth2ServiceClient(authConn)
+		jwtServiceOptions.OAuth2ServiceClient = authClient
+		jwtServiceOptions.ClientID = config.C().Auth.ClientID
+		jwtServiceOptions.ClientSecret = config.C().Auth.ClientSecret
+		jwtServiceOptions.IntrospectionCacheTTL = config.C().Auth.IntrospectionCacheTTL
+		jwtServiceOptions.PersonalAccessTokens = config.C().Auth.PersonalAccessTokens
+		log.InfoFields("Access tokens are validated by introspection.", map[string]any{"address": addr})
+
+		repositoryOptions = append(repositoryOptions, storage.WithAuthUserRepository(
+			authClient, config.C().Auth.ClientID, config.C().Auth.ClientSecret,
+		))
+	}
+
+	// Notes of deleted users are purged when their user deleted events arrive, and, since the message bus delivers
+	// at most once, by reconciling the notes with the users at the auth service. Revoked access tokens are added to
+	// the denylist when their token revoked events arrive.
+	var subscriber worker.Subscriber
+	var busDB io.Closer
+	if addr := config.C().MessageBus.RedisAddr; len(addr) != 0 {
+		log.Info("Connecting to the message bus...")
+		redisDB, err := redis.NewRedis(context.Background(), redis.Config{
+			Addr:     addr,
+			Password: config.C().MessageBus.RedisPassword,
+			DB:       config.C().MessageBus.RedisDB,
+		})
+		if err != nil {
+			log.FatalFields("Failed to connect to the message bus.", map[string]any{"error": err})
+		}
+
+		bus, err := messagebus.NewRedisBus(redisDB)
+		if err != nil {
+			log.FatalFields("Failed to create the message bus.", map[string]any{"error": err})
+		}
+		subscriber, busDB = bus, redisDB
+		log.InfoFields("Successfully connected to the message bus.", map[string]any{"address": addr})
+
+		// Access tokens revoked at the auth service are rejected as soon as their revocations arrive.
+		jwtServiceOptions.RevokedTokens = servicejwt.NewRevokedTokens()
+	}
+
+	repositories := storage.NewRepositoryProvider(repositoryOptions...)
+
+	noteServiceOptions.UserFinder = repositories.AuthUserRepository
+	services := service.NewServices(jwtServiceOptions, noteServiceOptions)
+
+	var reconcileInterval time.Duration
+	if repositories.AuthUserRepository != nil {
+		reconcileInterval = config.C().MessageBus.ReconcileInterval
+	}
+
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		if subscriber == nil && reconcileInterval <= 0 {
+			return
+		}
+
+		if err := worker.NewWorker(services, subscriber, reconcileInterval, log).Run(workerCtx); err != nil {
+			log.ErrorFields("Error occurred while running the worker.", map[string]any{"error": err})
+		}
+	}()
 
 	grpcServerAddr := net.JoinHostPort(
 		config.C().Note.HostGRPC,
@@ -110,12 +176,33 @@ func main() {
 		log.Info("REST server was successfully shut down.")
 	}
 
-	log.Info("Disconnecting from MongoDB...")
-	if err := database.Client().Disconnect(context.Background()); err != nil {
-		log.ErrorFields("Error during disconnecting for MongoDB.", map[string]any{"error": err})
-	} else {
-		log.Info("Successfully disconnected from MongoDB.")
+	stopWorker()
+	<-workerDone
+
+	if busDB != nil {
+		if err := busDB.Close(); err != nil {
+			log.ErrorFields("Error during disconnecting from the message bus.", map[string]any{"error": err})
+		}
 	}
 
+	if authConn != nil {
+		if err := authConn.Close(); err != nil {
+			log.ErrorFields("Error during disconnecting from the auth service.", map[string]any{"error": err})
+		}
+	}
+
+	closeStorage()
+
 	log.Info("Shutdown completed successfully.")
 }
+
+// connectMongoDB connects to the MongoDB database of the configuration.
+func connectMongoDB() (*mongodriver.Database, error) {
+	return mongo.NewMongoDB(context.Background(), mongo.Config{
+		Host:     config.C().MongoDB.Host,
+		Port:     config.C().MongoDB.Port,
+		Username: config.C().MongoDB.Username,
+		Password: config.C().MongoDB.Password,
+		Database: config.C().MongoDB.Database,
+	})
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nazarslota/unotes/auth/pkg/migrate"
+	"github.com/nazarslota/unotes/note/internal/config"
+	"github.com/nazarslota/unotes/note/internal/storage/mongo"
+	"github.com/nazarslota/unotes/note/internal/storage/sqlite"
+)
+
+// runMigrate runs the migrate subcommand, `note migrate up|down|status|force`, with args, the arguments that follow
+// it, and returns the exit code of the process.
+func runMigrate(args []string) int {
+	var migrator *migrate.Migrator
+	var err error
+	switch config.C().Note.Storage {
+	case "memory":
+		log.Error("The in-memory storage has no schema to migrate.")
+		return 1
+	case "sqlite":
+		db, connectErr := connectSQLite()
+		if connectErr != nil {
+			log.ErrorFields("Failed to open the SQLite database.", map[string]any{"error": connectErr})
+			return 1
+		}
+		defer func() { _ = db.Close() }()
+
+		migrator, err = sqlite.NewMigrator(db)
+	default:
+		database, connectErr := connectMongoDB()
+		if connectErr != nil {
+			log.ErrorFields("Failed to establish a connection with MongoDB.", map[string]any{"error": connectErr})
+			return 1
+		}
+		defer func() { _ = database.Client().Disconnect(context.Background()) }()
+
+		migrator, err = mongo.NewMigrator(database)
+	}
+	if err != nil {
+		log.ErrorFields("Failed to create the migrator.", map[string]any{"error": err})
+		return 1
+	}
+	defer func() { _ = migrator.Close() }()
+
+	if err := migrate.Run(migrator, args, os.Stdout); errors.Is(err, migrate.ErrUsage) {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 2
+	} else if err != nil {
+		log.ErrorFields("Failed to migrate the database.", map[string]any{"error": err})
+		return 1
+	}
+	return 0
+}
+
+// autoMigrate applies the migrations of migrator that are not applied yet at startup, and closes it. Replicas started
+// at the same time wait for each other, the migrations are applied under a lock.
+func autoMigrate(migrator *migrate.Migrator) error {
+	defer func() { _ = migrator.Close() }()
+
+	if err := migrator.Up(); err != nil {
+		return err
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	log.InfoFields("The database schema is up to date.", map[string]any{"version": status.Version})
+	return nil
+}
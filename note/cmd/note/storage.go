@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nazarslota/unotes/note/internal/config"
+	"github.com/nazarslota/unotes/note/internal/service"
+	"github.com/nazarslota/unotes/note/internal/storage"
+	"github.com/nazarslota/unotes/note/internal/storage/mongo"
+	"github.com/nazarslota/unotes/note/internal/storage/sqlite"
+)
+
+// openStorage opens the storage chosen by NOTE_STORAGE, sets the note repositories of options to it and returns the
+// function that closes it.
+func openStorage(options *service.NoteServiceOptions) func() {
+	switch config.C().Note.Storage {
+	case "memory":
+		return openMemoryStorage(options)
+	case "sqlite":
+		return openSQLiteStorage(options)
+	default:
+		return openMongoStorage(options)
+	}
+}
+
+// openMongoStorage connects to the MongoDB database, applies the migrations if enabled and sets the note repositories
+// of options to it.
+func openMongoStorage(options *service.NoteServiceOptions) func() {
+	log.Info("Attempting to establish a connection with a MongoDB...")
+	database, err := connectMongoDB()
+	if err != nil {
+		log.FatalFields("Failed to establish a connection with MongoDB.", map[string]any{"error": err})
+	} else {
+		log.Info("The connection to the database was successfully established.")
+	}
+
+	if config.C().Note.AutoMigrate {
+		log.Info("Migrating the database schema...")
+		migrator, err := mongo.NewMigrator(database)
+		if err != nil {
+			log.FatalFields("Failed to create the migrator.", map[string]any{"error": err})
+		}
+		if err := autoMigrate(migrator); err != nil {
+			log.FatalFields("Failed to migrate the database schema.", map[string]any{"error": err})
+		}
+	}
+
+	repositories := storage.NewRepositoryProvider(storage.WithMongoNoteRepository(database))
+	options.NoteSaver = repositories.MongoNoteRepository
+	options.NoteFinder = repositories.MongoNoteRepository
+	options.NoteUpdater = repositories.MongoNoteRepository
+	options.NoteDeleter = repositories.MongoNoteRepository
+	options.NotesDeleter = repositories.MongoNoteRepository
+	options.NoteOwnersFinder = repositories.MongoNoteRepository
+
+	return func() {
+		log.Info("Disconnecting from MongoDB...")
+		if err := database.Client().Disconnect(context.Background()); err != nil {
+			log.ErrorFields("Error during disconnecting for MongoDB.", map[string]any{"error": err})
+		} else {
+			log.Info("Successfully disconnected from MongoDB.")
+		}
+	}
+}
+
+// openSQLiteStorage opens the SQLite database, applies the migrations if enabled and sets the note repositories of
+// options to it.
+func openSQLiteStorage(options *service.NoteServiceOptions) func() {
+	log.Info("Opening the SQLite database...")
+	db, err := connectSQLite()
+	if err != nil {
+		log.FatalFields("Failed to open the SQLite database.", map[string]any{"error": err})
+	} else {
+		log.InfoFields("The SQLite database was successfully opened.", map[string]any{"path": config.C().SQLite.Path})
+	}
+
+	if config.C().Note.AutoMigrate {
+		log.Info("Migrating the database schema...")
+		migrator, err := sqlite.NewMigrator(db)
+		if err != nil {
+			log.FatalFields("Failed to create the migrator.", map[string]any{"error": err})
+		}
+		if err := autoMigrate(migrator); err != nil {
+			log.FatalFields("Failed to migrate the database schema.", map[string]any{"error": err})
+		}
+	}
+
+	repositories := storage.NewRepositoryProvider(storage.WithSQLiteNoteRepository(db))
+	options.NoteSaver = repositories.SQLiteNoteRepository
+	options.NoteFinder = repositories.SQLiteNoteRepository
+	options.NoteUpdater = repositories.SQLiteNoteRepository
+	options.NoteDeleter = repositories.SQLiteNoteRepository
+	options.NotesDeleter = repositories.SQLiteNoteRepository
+	options.NoteOwnersFinder = repositories.SQLiteNoteRepository
+
+	return func() {
+		log.Info("Closing the SQLite database...")
+		if err := db.Close(); err != nil {
+			log.ErrorFields("Error during closing the SQLite database.", map[string]any{"error": err})
+		} else {
+			log.Info("The SQLite database was successfully closed.")
+		}
+	}
+}
+
+// openMemoryStorage sets the note repositories of options to an in-memory repository, so that the service runs
+// without any database. All the notes are lost once the service stops.
+func openMemoryStorage(options *service.NoteServiceOptions) func() {
+	log.Warn("The in-memory storage is used, all the notes are lost once the service stops.")
+
+	repositories := storage.NewRepositoryProvider(storage.WithMemoryNoteRepository())
+	options.NoteSaver = repositories.MemoryNoteRepository
+	options.NoteFinder = repositories.MemoryNoteRepository
+	options.NoteUpdater = repositories.MemoryNoteRepository
+	options.NoteDeleter = repositories.MemoryNoteRepository
+	options.NotesDeleter = repositories.MemoryNoteRepository
+	options.NoteOwnersFinder = repositories.MemoryNoteRepository
+
+	return func() {}
+}
+
+// connectSQLite opens the SQLite database of the configuration.
+func connectSQLite() (*sqlx.DB, error) {
+	return sqlite.NewSQLite(context.Background(), sqlite.Config{Path: config.C().SQLite.Path})
+}
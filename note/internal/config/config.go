@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"sync"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
@@ -17,6 +18,21 @@ type Config struct {
 		Debug             bool   `mapstructure:"NOTE_DEBUG"`
 		Log               string `mapstructure:"NOTE_LOG"`
 		AccessTokenSecret string `mapstructure:"NOTE_ACCESS_TOKEN_SECRET"`
+		AutoMigrate       bool   `mapstructure:"NOTE_AUTO_MIGRATE"`
+		Storage           string `mapstructure:"NOTE_STORAGE" validate:"oneof=mongo memory sqlite"`
+	} `mapstructure:",squash"`
+	Auth struct {
+		IntrospectionAddr     string        `mapstructure:"NOTE_AUTH_INTROSPECTION_ADDR"`
+		ClientID              string        `mapstructure:"NOTE_AUTH_CLIENT_ID"`
+		ClientSecret          string        `mapstructure:"NOTE_AUTH_CLIENT_SECRET"`
+		IntrospectionCacheTTL time.Duration `mapstructure:"NOTE_AUTH_INTROSPECTION_CACHE_TTL"`
+		PersonalAccessTokens  bool          `mapstructure:"NOTE_AUTH_PERSONAL_ACCESS_TOKENS"`
+	} `mapstructure:",squash"`
+	MessageBus struct {
+		RedisAddr         string        `mapstructure:"NOTE_MESSAGE_BUS_REDIS_ADDR"`
+		RedisPassword     string        `mapstructure:"NOTE_MESSAGE_BUS_REDIS_PASSWORD"`
+		RedisDB           int           `mapstructure:"NOTE_MESSAGE_BUS_REDIS_DB"`
+		ReconcileInterval time.Duration `mapstructure:"NOTE_RECONCILE_INTERVAL"`
 	} `mapstructure:",squash"`
 	MongoDB struct {
 		Host     string `mapstructure:"NOTE_MONGODB_HOST"`
@@ -25,6 +41,9 @@ type Config struct {
 		Password string `mapstructure:"NOTE_MONGODB_PASSWORD"`
 		Database string `mapstructure:"NOTE_MONGODB_DATABASE"`
 	} `mapstructure:",squash"`
+	SQLite struct {
+		Path string `mapstructure:"NOTE_SQLITE_PATH"`
+	} `mapstructure:",squash"`
 }
 
 var (
@@ -77,9 +96,25 @@ func C() *Config {
 
 func bindEnv(v *viper.Viper) {
 	_ = v.BindEnv("NOTE_ACCESS_TOKEN_SECRET")
+	bindEnvAuth(v)
+	bindEnvMessageBus(v)
 	bindEnvMongoDB(v)
 }
 
+func bindEnvAuth(v *viper.Viper) {
+	_ = v.BindEnv("NOTE_AUTH_INTROSPECTION_ADDR")
+	_ = v.BindEnv("NOTE_AUTH_CLIENT_ID")
+	_ = v.BindEnv("NOTE_AUTH_CLIENT_SECRET")
+	_ = v.BindEnv("NOTE_AUTH_INTROSPECTION_CACHE_TTL")
+	_ = v.BindEnv("NOTE_AUTH_PERSONAL_ACCESS_TOKENS")
+}
+
+func bindEnvMessageBus(v *viper.Viper) {
+	_ = v.BindEnv("NOTE_MESSAGE_BUS_REDIS_ADDR")
+	_ = v.BindEnv("NOTE_MESSAGE_BUS_REDIS_PASSWORD")
+	_ = v.BindEnv("NOTE_MESSAGE_BUS_REDIS_DB")
+}
+
 func bindEnvMongoDB(v *viper.Viper) {
 	_ = v.BindEnv("NOTE_MONGODB_HOST")
 	_ = v.BindEnv("NOTE_MONGODB_PORT")
@@ -42,6 +42,12 @@ func (h *Handler) Server() Server {
 	return newServer(h.grpcAddr, h.restAddr, h.grpcServer(), h.restServer())
 }
 
+// Scopes the auth service grants for the notes, which the methods of the note service require.
+const (
+	scopeNotesRead  = "notes:read"
+	scopeNotesWrite = "notes:write"
+)
+
 func (h *Handler) grpcServer() *grpc.Server {
 	logger := newLoggerInterceptor(loggerInterceptorOptions{
 		Logger: h.grpcLogger,
@@ -49,7 +55,14 @@ func (h *Handler) grpcServer() *grpc.Server {
 
 	auth := newAuthInterceptor(authInterceptorOptions{
 		AccessTokenValidator: h.services.JWTService.AccessTokenValidator,
-		Methods:              []string{"/NoteService/*"},
+		Scopes: map[string][]string{
+			pb.NoteService_CreateNote_FullMethodName: {scopeNotesWrite},
+			pb.NoteService_GetNote_FullMethodName:    {scopeNotesRead},
+			pb.NoteService_GetNotes_FullMethodName:   {scopeNotesRead},
+			pb.NoteService_UpdateNote_FullMethodName: {scopeNotesWrite},
+			pb.NoteService_DeleteNote_FullMethodName: {scopeNotesWrite},
+		},
+		Services: []string{pb.NoteService_ServiceDesc.ServiceName},
 	})
 
 	server := grpc.NewServer(
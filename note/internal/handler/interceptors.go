@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"golang.org/x/exp/slices"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -66,30 +67,39 @@ type accessTokenValidator interface {
 	Validate(token string) (jwt.AccessTokenClaims, error)
 }
 
+// authInterceptor authenticates and authorizes requests by their access token. Scopes lists, for each method that
+// requires authentication, the scopes the token must have been granted; all of them are required and an empty list
+// requires authentication only. Every method of the services named in Services requires authentication, the ones
+// that are not listed in Scopes require authentication only, so that a method added without a scope is not public.
+// Methods of other services that are not listed don't require authentication.
 type authInterceptor struct {
 	AccessTokenValidator accessTokenValidator
-	Methods              []string
+	Scopes               map[string][]string
+	Services             []string
 }
 
 type authInterceptorOptions struct {
 	AccessTokenValidator accessTokenValidator
-	Methods              []string
+	Scopes               map[string][]string
+	Services             []string
 }
 
 func newAuthInterceptor(options authInterceptorOptions) *authInterceptor {
 	return &authInterceptor{
 		AccessTokenValidator: options.AccessTokenValidator,
-		Methods:              options.Methods,
+		Scopes:               options.Scopes,
+		Services:             options.Services,
 	}
 }
 
 func (i *authInterceptor) Unary() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-		if !i.allowed(info.FullMethod) {
+		scopes, ok := i.required(info.FullMethod)
+		if !ok {
 			return handler(ctx, req)
 		}
 
-		ctx, err := i.authorize(ctx)
+		ctx, err := i.authorize(ctx, scopes)
 		if err != nil {
 			return nil, err
 		}
@@ -99,11 +109,12 @@ func (i *authInterceptor) Unary() grpc.UnaryServerInterceptor {
 
 func (i *authInterceptor) Stream() grpc.StreamServerInterceptor {
 	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		if !i.allowed(info.FullMethod) {
+		scopes, ok := i.required(info.FullMethod)
+		if !ok {
 			return handler(srv, stream)
 		}
 
-		ctx, err := i.authorize(stream.Context())
+		ctx, err := i.authorize(stream.Context(), scopes)
 		if err != nil {
 			return err
 		}
@@ -111,9 +122,21 @@ func (i *authInterceptor) Stream() grpc.StreamServerInterceptor {
 	}
 }
 
-func (i *authInterceptor) allowed(method string) bool { return allowed(method, i.Methods) }
+// required returns the scopes the method, given by its full name, requires, and whether it requires authentication.
+func (i *authInterceptor) required(method string) ([]string, bool) {
+	if scopes, ok := i.Scopes[method]; ok {
+		return scopes, true
+	}
+
+	for _, service := range i.Services {
+		if strings.HasPrefix(method, "/"+service+"/") {
+			return nil, true
+		}
+	}
+	return nil, false
+}
 
-func (i *authInterceptor) authorize(ctx context.Context) (context.Context, error) {
+func (i *authInterceptor) authorize(ctx context.Context, scopes []string) (context.Context, error) {
 	tokens := metadata.ValueFromIncomingContext(ctx, "authorization")
 	if len(tokens) == 0 {
 		return nil, status.Error(codes.Unauthenticated, "authorization header is not provided")
@@ -129,9 +152,24 @@ func (i *authInterceptor) authorize(ctx context.Context) (context.Context, error
 	if err != nil {
 		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
 	}
+
+	if !granted(scopes, claims.Scope) {
+		return nil, status.Error(codes.PermissionDenied, "insufficient scope")
+	}
 	return context.WithValue(ctx, "claims", claims), nil
 }
 
+// granted reports whether all the required scopes are in the space-delimited scope.
+func granted(required []string, scope string) bool {
+	fields := strings.Fields(scope)
+	for _, r := range required {
+		if !slices.Contains(fields, r) {
+			return false
+		}
+	}
+	return true
+}
+
 // Helpers
 
 type streamServerWrapper struct {
@@ -144,15 +182,3 @@ func newStreamServerWrapper(stream grpc.ServerStream, ctx context.Context) *stre
 }
 
 func (s streamServerWrapper) Context() context.Context { return s.ctx }
-
-func allowed(method string, allowed []string) bool {
-	for _, v := range allowed {
-		if v == "*" || v == method {
-			return true
-		}
-		if len(v) > 0 && v[len(v)-1] == '*' && strings.HasPrefix(method, v[:len(v)-1]) {
-			return true
-		}
-	}
-	return false
-}
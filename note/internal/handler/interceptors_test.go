@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// staticAccessTokenValidator accepts the tokens in Claims.
+type staticAccessTokenValidator struct {
+	Claims map[string]jwt.AccessTokenClaims
+}
+
+func (v staticAccessTokenValidator) Validate(token string) (jwt.AccessTokenClaims, error) {
+	claims, ok := v.Claims[token]
+	if !ok {
+		return jwt.AccessTokenClaims{}, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func TestAuthInterceptor_Unary(t *testing.T) {
+	interceptor := newAuthInterceptor(authInterceptorOptions{
+		AccessTokenValidator: staticAccessTokenValidator{Claims: map[string]jwt.AccessTokenClaims{
+			"user":      {UserID: "user-id", Role: "user", Scope: "notes:read notes:write"},
+			"read-only": {UserID: "user-id", ClientID: "client-id", Scope: "notes:read"},
+			"unscoped":  {UserID: "user-id"},
+		}},
+		Scopes: map[string][]string{
+			"/NoteService/Read":  {scopeNotesRead},
+			"/NoteService/Write": {scopeNotesWrite},
+			"/NoteService/Any":   {},
+		},
+		Services: []string{"NoteService"},
+	}).Unary()
+
+	call := func(method, token string) codes.Code {
+		ctx := context.Background()
+		if len(token) != 0 {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+		}
+
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req any) (any, error) { return nil, nil })
+		return status.Code(err)
+	}
+
+	assert.Equal(t, codes.OK, call("/OtherService/Public", ""))
+	assert.Equal(t, codes.Unauthenticated, call("/NoteService/Unlisted", ""))
+	assert.Equal(t, codes.Unauthenticated, call("/NoteService/Unlisted", "invalid"))
+	assert.Equal(t, codes.OK, call("/NoteService/Unlisted", "unscoped"))
+	assert.Equal(t, codes.Unauthenticated, call("/NoteService/Read", ""))
+	assert.Equal(t, codes.Unauthenticated, call("/NoteService/Read", "invalid"))
+
+	assert.Equal(t, codes.OK, call("/NoteService/Read", "user"))
+	assert.Equal(t, codes.OK, call("/NoteService/Write", "user"))
+
+	assert.Equal(t, codes.OK, call("/NoteService/Read", "read-only"))
+	assert.Equal(t, codes.PermissionDenied, call("/NoteService/Write", "read-only"))
+
+	assert.Equal(t, codes.PermissionDenied, call("/NoteService/Read", "unscoped"))
+	assert.Equal(t, codes.OK, call("/NoteService/Any", "unscoped"))
+}
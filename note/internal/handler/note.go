@@ -28,9 +28,9 @@ func (s noteServiceServer) CreateNote(ctx context.Context, in *pb.CreateNoteRequ
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	claims, ok := s.authorized(ctx)
-	if !ok {
-		return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+	claims, err := s.owner(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	request := servicenote.CreateNoteRequest{
@@ -91,9 +91,9 @@ func (s noteServiceServer) GetNotes(in *pb.GetNotesRequest, server pb.NoteServic
 		return status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	claims, ok := s.authorized(server.Context())
-	if !ok {
-		return status.Error(codes.Unauthenticated, "unauthenticated")
+	claims, err := s.owner(server.Context())
+	if err != nil {
+		return err
 	}
 
 	request := servicenote.GetNotesAsyncRequest{UserID: claims.UserID}
@@ -187,3 +187,15 @@ func (s noteServiceServer) authorized(ctx context.Context) (jwt.AccessTokenClaim
 	}
 	return claims, true
 }
+
+// owner returns the claims of a token that acts on behalf of a user, who owns the notes it creates and lists. Tokens
+// of service accounts have no user, so they are denied rather than having their client own notes.
+func (s noteServiceServer) owner(ctx context.Context) (jwt.AccessTokenClaims, error) {
+	claims, ok := s.authorized(ctx)
+	if !ok {
+		return jwt.AccessTokenClaims{}, status.Error(codes.Unauthenticated, "unauthenticated")
+	} else if len(claims.UserID) == 0 {
+		return jwt.AccessTokenClaims{}, status.Error(codes.PermissionDenied, "token has no user")
+	}
+	return claims, nil
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	pb "github.com/nazarslota/unotes/note/api/proto"
+	"github.com/nazarslota/unotes/note/internal/service"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// getNotesServer is a stream of GetNotes responses that is never sent to.
+type getNotesServer struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s getNotesServer) Context() context.Context        { return s.ctx }
+func (s getNotesServer) Send(*pb.GetNotesResponse) error { return nil }
+
+func TestNoteServiceServer_Owner(t *testing.T) {
+	// The services are empty, so a call that gets past the owner check panics.
+	s := newNoteServiceServer(service.Services{})
+
+	serviceAccount := jwt.AccessTokenClaims{
+		RegisteredClaims: gojwt.RegisteredClaims{Subject: "service"},
+		ClientID:         "service",
+		Scope:            "notes:read notes:write",
+	}
+	ctx := context.WithValue(context.Background(), "claims", serviceAccount)
+
+	t.Run("should deny service account to create note", func(t *testing.T) {
+		_, err := s.CreateNote(ctx, &pb.CreateNoteRequest{Title: "title", Content: "content"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("should deny service account to get notes", func(t *testing.T) {
+		err := s.GetNotes(&pb.GetNotesRequest{}, getNotesServer{ctx: ctx})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("should deny request without token", func(t *testing.T) {
+		_, err := s.CreateNote(context.Background(), &pb.CreateNoteRequest{Title: "title", Content: "content"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
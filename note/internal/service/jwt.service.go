@@ -1,17 +1,56 @@
 package service
 
-import servicejwt "github.com/nazarslota/unotes/note/internal/service/jwt"
+import (
+	"time"
+
+	authpb "github.com/nazarslota/unotes/auth/api/proto"
+	servicejwt "github.com/nazarslota/unotes/note/internal/service/jwt"
+)
 
 type JWTService struct {
 	AccessTokenValidator servicejwt.AccessTokenValidator
+	RevokedTokens        *servicejwt.RevokedTokens
 }
 
+// JWTServiceOptions configures how access tokens are validated. JWT access tokens are verified locally with
+// AccessTokenSecret. If OAuth2ServiceClient is set, tokens are introspected at the auth service with the ClientID and
+// ClientSecret of a confidential client and the results are cached for IntrospectionCacheTTL: personal access tokens,
+// which are opaque, if PersonalAccessTokens is set, and JWT access tokens too if AccessTokenSecret is not set, so that
+// it doesn't have to be shared. If RevokedTokens is set, the tokens in it are rejected either way.
 type JWTServiceOptions struct {
 	AccessTokenSecret string
+	RevokedTokens     *servicejwt.RevokedTokens
+
+	OAuth2ServiceClient   authpb.OAuth2ServiceClient
+	ClientID              string
+	ClientSecret          string
+	IntrospectionCacheTTL time.Duration
+	PersonalAccessTokens  bool
 }
 
 func NewJWTService(options JWTServiceOptions) JWTService {
-	return JWTService{
-		AccessTokenValidator: servicejwt.NewAccessTokenValidator(options.AccessTokenSecret),
+	var validator servicejwt.AccessTokenValidator
+	if options.OAuth2ServiceClient != nil {
+		introspector := servicejwt.NewIntrospectionAccessTokenValidator(
+			options.OAuth2ServiceClient,
+			options.ClientID, options.ClientSecret,
+			options.IntrospectionCacheTTL,
+		)
+
+		validator = introspector
+		if len(options.AccessTokenSecret) != 0 {
+			validator = servicejwt.NewAccessTokenValidator(options.AccessTokenSecret)
+		}
+		if options.PersonalAccessTokens {
+			validator = servicejwt.NewPersonalAccessTokenAccessTokenValidator(validator, introspector)
+		}
+	} else {
+		validator = servicejwt.NewAccessTokenValidator(options.AccessTokenSecret)
+	}
+
+	// Introspection rejects revoked tokens too, but only once its cached result expires.
+	if options.RevokedTokens != nil {
+		validator = servicejwt.NewRevocationAccessTokenValidator(validator, options.RevokedTokens)
 	}
+	return JWTService{AccessTokenValidator: validator, RevokedTokens: options.RevokedTokens}
 }
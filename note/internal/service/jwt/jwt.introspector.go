@@ -0,0 +1,147 @@
+package jwt
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	authpb "github.com/nazarslota/unotes/auth/api/proto"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+)
+
+// ErrTokenInactive is returned by the introspection validator for tokens the auth service reports as inactive, that
+// is invalid, expired or revoked.
+var ErrTokenInactive = errors.New("token is inactive")
+
+const (
+	introspectionTimeout   = 5 * time.Second
+	introspectionCacheSize = 10000
+)
+
+// introspectionAccessTokenValidator validates access tokens by introspecting them at the auth service, so that
+// revoked tokens are rejected and the token secret doesn't have to be shared. Personal access tokens are opaque and
+// can only be validated this way. Results are cached for a short time, so a revoked token may still be accepted until
+// its cache entry expires.
+type introspectionAccessTokenValidator struct {
+	Client       authpb.OAuth2ServiceClient
+	ClientID     string
+	ClientSecret string
+	CacheTTL     time.Duration
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]introspectionResult
+}
+
+type introspectionResult struct {
+	claims    jwt.AccessTokenClaims
+	err       error
+	expiresAt time.Time
+}
+
+// NewIntrospectionAccessTokenValidator creates a validator that introspects tokens with the auth service client,
+// authenticating as the given confidential client, and caches the results for cacheTTL.
+func NewIntrospectionAccessTokenValidator(
+	client authpb.OAuth2ServiceClient, clientID, clientSecret string, cacheTTL time.Duration,
+) AccessTokenValidator {
+	return &introspectionAccessTokenValidator{
+		Client:       client,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		CacheTTL:     cacheTTL,
+		cache:        make(map[[sha256.Size]byte]introspectionResult),
+	}
+}
+
+func (v *introspectionAccessTokenValidator) Validate(token string) (jwt.AccessTokenClaims, error) {
+	key := sha256.Sum256([]byte(token))
+	if result, ok := v.cached(key); ok {
+		return result.claims, result.err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), introspectionTimeout)
+	defer cancel()
+
+	response, err := v.Client.Introspect(ctx, &authpb.IntrospectRequest{
+		ClientId:      v.ClientID,
+		ClientSecret:  v.ClientSecret,
+		Token:         token,
+		TokenTypeHint: "access_token",
+	})
+	if err != nil {
+		// Failures of the auth service are not cached, so that tokens are accepted again as soon as it recovers.
+		return jwt.AccessTokenClaims{}, fmt.Errorf("failed to introspect token: %w", err)
+	}
+
+	result := introspectionResult{expiresAt: time.Now().Add(v.CacheTTL)}
+
+	// The token may expire between being introspected and the response arriving, and the clocks may differ, so an
+	// active response is not taken for a valid token once its expiry has passed.
+	expired := response.Exp != 0 && !time.Unix(response.Exp, 0).After(time.Now())
+	if !response.Active || response.TokenType != "access_token" || expired {
+		result.err = ErrTokenInactive
+	} else {
+		result.claims = jwt.AccessTokenClaims{
+			RegisteredClaims: gojwt.RegisteredClaims{
+				ID:      response.Jti,
+				Subject: response.Sub,
+			},
+			EmailVerified: response.EmailVerified,
+			Role:          response.Role,
+			ClientID:      response.ClientId,
+			Scope:         response.Scope,
+			SessionID:     response.SessionId,
+		}
+
+		// The subject of a service account is the client itself, such a token acts on behalf of no user.
+		if response.Sub != response.ClientId {
+			result.claims.UserID = response.Sub
+		}
+
+		// Personal access tokens may never expire, then there is no expiry to cap the cache entry at.
+		if response.Exp != 0 {
+			expiresAt := time.Unix(response.Exp, 0)
+			result.claims.ExpiresAt = gojwt.NewNumericDate(expiresAt)
+			if expiresAt.Before(result.expiresAt) {
+				result.expiresAt = expiresAt
+			}
+		}
+	}
+
+	v.store(key, result)
+	return result.claims, result.err
+}
+
+func (v *introspectionAccessTokenValidator) cached(key [sha256.Size]byte) (introspectionResult, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	result, ok := v.cache[key]
+	if !ok || time.Now().After(result.expiresAt) {
+		return introspectionResult{}, false
+	}
+	return result, true
+}
+
+func (v *introspectionAccessTokenValidator) store(key [sha256.Size]byte, result introspectionResult) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if len(v.cache) >= introspectionCacheSize {
+		now := time.Now()
+		for k, r := range v.cache {
+			if now.After(r.expiresAt) {
+				delete(v.cache, k)
+			}
+		}
+
+		// Every entry is still fresh, so drop them all rather than let the cache grow without bound.
+		if len(v.cache) >= introspectionCacheSize {
+			v.cache = make(map[[sha256.Size]byte]introspectionResult)
+		}
+	}
+	v.cache[key] = result
+}
@@ -0,0 +1,98 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authpb "github.com/nazarslota/unotes/auth/api/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// introspectionClient answers introspection requests with Responses and counts them.
+type introspectionClient struct {
+	authpb.OAuth2ServiceClient
+	Responses map[string]*authpb.IntrospectResponse
+	Calls     int
+}
+
+func (c *introspectionClient) Introspect(
+	_ context.Context, in *authpb.IntrospectRequest, _ ...grpc.CallOption,
+) (*authpb.IntrospectResponse, error) {
+	c.Calls++
+	if response, ok := c.Responses[in.Token]; ok {
+		return response, nil
+	}
+	return &authpb.IntrospectResponse{Active: false}, nil
+}
+
+func TestIntrospectionAccessTokenValidator_Validate(t *testing.T) {
+	t.Run("should accept and cache personal access token without expiry", func(t *testing.T) {
+		client := &introspectionClient{Responses: map[string]*authpb.IntrospectResponse{
+			"unp_token": {Active: true, TokenType: "access_token", Jti: "token-id", Sub: "user-id", Scope: "notes:read"},
+		}}
+		validator := NewIntrospectionAccessTokenValidator(client, "note", "secret", time.Minute)
+
+		for i := 0; i < 2; i++ {
+			claims, err := validator.Validate("unp_token")
+			require.NoError(t, err)
+			assert.Equal(t, "user-id", claims.UserID)
+			assert.Equal(t, "notes:read", claims.Scope)
+			assert.Nil(t, claims.ExpiresAt)
+		}
+		assert.Equal(t, 1, client.Calls)
+	})
+
+	t.Run("should reject active token past its expiry", func(t *testing.T) {
+		client := &introspectionClient{Responses: map[string]*authpb.IntrospectResponse{
+			"expired": {Active: true, TokenType: "access_token", Sub: "user-id", Exp: time.Now().Add(-time.Second).Unix()},
+		}}
+		validator := NewIntrospectionAccessTokenValidator(client, "note", "secret", time.Minute)
+
+		claims, err := validator.Validate("expired")
+		assert.ErrorIs(t, err, ErrTokenInactive)
+		assert.Empty(t, claims)
+	})
+
+	t.Run("should not cache token past its expiry", func(t *testing.T) {
+		client := &introspectionClient{Responses: map[string]*authpb.IntrospectResponse{
+			"expiring": {Active: true, TokenType: "access_token", Sub: "user-id", Exp: time.Now().Add(time.Second).Unix()},
+		}}
+		validator := NewIntrospectionAccessTokenValidator(client, "note", "secret", time.Minute)
+
+		_, err := validator.Validate("expiring")
+		require.NoError(t, err)
+
+		time.Sleep(time.Until(time.Unix(client.Responses["expiring"].Exp, 0)) + 10*time.Millisecond)
+		_, err = validator.Validate("expiring")
+		assert.ErrorIs(t, err, ErrTokenInactive)
+		assert.Equal(t, 2, client.Calls)
+	})
+
+	t.Run("should not take client of service account for user", func(t *testing.T) {
+		client := &introspectionClient{Responses: map[string]*authpb.IntrospectResponse{
+			"service": {Active: true, TokenType: "access_token", Sub: "service", ClientId: "service"},
+			"user":    {Active: true, TokenType: "access_token", Sub: "user-id", ClientId: "web"},
+		}}
+		validator := NewIntrospectionAccessTokenValidator(client, "note", "secret", time.Minute)
+
+		claims, err := validator.Validate("service")
+		require.NoError(t, err)
+		assert.Empty(t, claims.UserID)
+		assert.Equal(t, "service", claims.Subject)
+		assert.Equal(t, "service", claims.ClientID)
+
+		claims, err = validator.Validate("user")
+		require.NoError(t, err)
+		assert.Equal(t, "user-id", claims.UserID)
+	})
+
+	t.Run("should reject inactive token", func(t *testing.T) {
+		validator := NewIntrospectionAccessTokenValidator(&introspectionClient{}, "note", "secret", time.Minute)
+
+		_, err := validator.Validate("unp_revoked")
+		assert.ErrorIs(t, err, ErrTokenInactive)
+	})
+}
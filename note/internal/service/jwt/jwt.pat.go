@@ -0,0 +1,31 @@
+package jwt
+
+import (
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/nazarslota/unotes/auth/pkg/pat"
+)
+
+// personalAccessTokenAccessTokenValidator validates personal access tokens, which are opaque, with
+// PersonalAccessTokenValidator and every other token, that is JWT access tokens, with Validator.
+type personalAccessTokenAccessTokenValidator struct {
+	Validator                    AccessTokenValidator
+	PersonalAccessTokenValidator AccessTokenValidator
+}
+
+// NewPersonalAccessTokenAccessTokenValidator wraps the validator, so that personal access tokens, told apart by their
+// prefix, are validated by personalAccessTokenValidator instead, usually by introspecting them at the auth service.
+func NewPersonalAccessTokenAccessTokenValidator(
+	validator, personalAccessTokenValidator AccessTokenValidator,
+) AccessTokenValidator {
+	return &personalAccessTokenAccessTokenValidator{
+		Validator:                    validator,
+		PersonalAccessTokenValidator: personalAccessTokenValidator,
+	}
+}
+
+func (v *personalAccessTokenAccessTokenValidator) Validate(token string) (jwt.AccessTokenClaims, error) {
+	if pat.IsPersonalAccessToken(token) {
+		return v.PersonalAccessTokenValidator.Validate(token)
+	}
+	return v.Validator.Validate(token)
+}
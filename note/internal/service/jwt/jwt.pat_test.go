@@ -0,0 +1,46 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	authpb "github.com/nazarslota/unotes/auth/api/proto"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonalAccessTokenAccessTokenValidator_Validate(t *testing.T) {
+	client := &introspectionClient{Responses: map[string]*authpb.IntrospectResponse{
+		"unp_token": {Active: true, TokenType: "access_token", Sub: "pat-user-id", ClientId: "cli"},
+	}}
+	validator := NewPersonalAccessTokenAccessTokenValidator(
+		NewAccessTokenValidator("secret"),
+		NewIntrospectionAccessTokenValidator(client, "note", "secret", time.Minute),
+	)
+
+	t.Run("should introspect personal access token", func(t *testing.T) {
+		claims, err := validator.Validate("unp_token")
+		require.NoError(t, err)
+		assert.Equal(t, "pat-user-id", claims.UserID)
+		assert.Equal(t, 1, client.Calls)
+	})
+
+	t.Run("should validate jwt locally", func(t *testing.T) {
+		token, err := jwt.NewAccessTokenManagerHMAC("secret").New(jwt.AccessTokenClaims{
+			RegisteredClaims: gojwt.RegisteredClaims{ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Minute))},
+			UserID:           "user-id",
+		})
+		require.NoError(t, err)
+
+		claims, err := validator.Validate(token)
+		require.NoError(t, err)
+		assert.Equal(t, "user-id", claims.UserID)
+		assert.Equal(t, 1, client.Calls)
+
+		_, err = validator.Validate("invalid-token")
+		assert.Error(t, err)
+		assert.Equal(t, 1, client.Calls)
+	})
+}
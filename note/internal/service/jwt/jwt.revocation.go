@@ -0,0 +1,82 @@
+package jwt
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+)
+
+// ErrTokenRevoked is returned by the revocation validator for tokens that were revoked at the auth service.
+var ErrTokenRevoked = errors.New("token is revoked")
+
+// revokedTokensPruneSize is the number of revoked tokens above which expired ones are pruned when another is added.
+const revokedTokensPruneSize = 10000
+
+// RevokedTokens is the local denylist of access tokens revoked at the auth service, identified by their token ID (jti).
+// It is filled from the revocations the auth service publishes, which are delivered at most once, so a token revoked
+// while the service was not subscribed stays valid until it expires. Tokens are kept until they expire.
+type RevokedTokens struct {
+	mu     sync.Mutex
+	tokens map[string]time.Time
+}
+
+// NewRevokedTokens creates a new empty RevokedTokens.
+func NewRevokedTokens() *RevokedTokens {
+	return &RevokedTokens{tokens: make(map[string]time.Time)}
+}
+
+// Revoke adds the token ID to the denylist until the token expires at expiresAt.
+func (r *RevokedTokens) Revoke(tokenID string, expiresAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if !expiresAt.After(now) {
+		return
+	}
+
+	// Entries can't be dropped before their tokens expire without accepting revoked tokens again, so only the expired
+	// ones are pruned.
+	if len(r.tokens) >= revokedTokensPruneSize {
+		for id, e := range r.tokens {
+			if !e.After(now) {
+				delete(r.tokens, id)
+			}
+		}
+	}
+	r.tokens[tokenID] = expiresAt
+}
+
+// IsRevoked reports whether the token ID is in the denylist.
+func (r *RevokedTokens) IsRevoked(tokenID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expiresAt, ok := r.tokens[tokenID]
+	return ok && expiresAt.After(time.Now())
+}
+
+// revocationAccessTokenValidator rejects the access tokens in the denylist, after they were validated by Validator.
+type revocationAccessTokenValidator struct {
+	Validator     AccessTokenValidator
+	RevokedTokens *RevokedTokens
+}
+
+// NewRevocationAccessTokenValidator wraps the validator, so that tokens in the denylist are rejected as well.
+func NewRevocationAccessTokenValidator(validator AccessTokenValidator, revoked *RevokedTokens) AccessTokenValidator {
+	return &revocationAccessTokenValidator{Validator: validator, RevokedTokens: revoked}
+}
+
+func (v *revocationAccessTokenValidator) Validate(token string) (jwt.AccessTokenClaims, error) {
+	claims, err := v.Validator.Validate(token)
+	if err != nil {
+		return jwt.AccessTokenClaims{}, err
+	}
+
+	if len(claims.ID) != 0 && v.RevokedTokens.IsRevoked(claims.ID) {
+		return jwt.AccessTokenClaims{}, ErrTokenRevoked
+	}
+	return claims, nil
+}
@@ -0,0 +1,54 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/nazarslota/unotes/auth/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevocationAccessTokenValidator_Validate(t *testing.T) {
+	manager := jwt.NewAccessTokenManagerHMAC("secret")
+	newToken := func(t *testing.T, tokenID string) string {
+		token, err := manager.New(jwt.AccessTokenClaims{
+			RegisteredClaims: gojwt.RegisteredClaims{
+				ID:        tokenID,
+				ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+			UserID: "user-id",
+		})
+		require.NoError(t, err)
+		return token
+	}
+
+	revoked := NewRevokedTokens()
+	validator := NewRevocationAccessTokenValidator(NewAccessTokenValidator("secret"), revoked)
+
+	t.Run("should accept token that is not revoked", func(t *testing.T) {
+		claims, err := validator.Validate(newToken(t, "token-id"))
+		require.NoError(t, err)
+		assert.Equal(t, "user-id", claims.UserID)
+	})
+
+	t.Run("should reject revoked token", func(t *testing.T) {
+		revoked.Revoke("revoked-token-id", time.Now().Add(time.Minute))
+
+		claims, err := validator.Validate(newToken(t, "revoked-token-id"))
+		assert.ErrorIs(t, err, ErrTokenRevoked)
+		assert.Empty(t, claims)
+	})
+
+	t.Run("should forget revocation once token expires", func(t *testing.T) {
+		revoked.Revoke("expired-token-id", time.Now().Add(-time.Second))
+		assert.False(t, revoked.IsRevoked("expired-token-id"))
+	})
+
+	t.Run("should return validation error", func(t *testing.T) {
+		_, err := validator.Validate("invalid-token")
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrTokenRevoked)
+	})
+}
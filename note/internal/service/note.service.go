@@ -5,12 +5,14 @@ import (
 )
 
 type NoteService struct {
-	CreateNoteRequestHandler    servicenote.CreateNoteRequestHandler
-	GetNoteRequestHandler       servicenote.GetNoteRequestHandler
-	GetNotesRequestHandler      servicenote.GetNotesRequestHandler
-	UpdateNoteRequestHandler    servicenote.UpdateNoteRequestHandler
-	DeleteNoteRequestHandler    servicenote.DeleteNoteRequestHandler
-	GetNotesAsyncRequestHandler servicenote.GetNotesAsyncRequestHandler
+	CreateNoteRequestHandler     servicenote.CreateNoteRequestHandler
+	GetNoteRequestHandler        servicenote.GetNoteRequestHandler
+	GetNotesRequestHandler       servicenote.GetNotesRequestHandler
+	UpdateNoteRequestHandler     servicenote.UpdateNoteRequestHandler
+	DeleteNoteRequestHandler     servicenote.DeleteNoteRequestHandler
+	GetNotesAsyncRequestHandler  servicenote.GetNotesAsyncRequestHandler
+	PurgeNotesRequestHandler     servicenote.PurgeNotesRequestHandler
+	ReconcileNotesRequestHandler servicenote.ReconcileNotesRequestHandler
 }
 
 type NoteServiceOptions struct {
@@ -18,6 +20,10 @@ type NoteServiceOptions struct {
 	NoteFinder  servicenote.NoteFinder
 	NoteUpdater servicenote.NoteUpdater
 	NoteDeleter servicenote.NoteDeleter
+
+	NotesDeleter     servicenote.NotesDeleter
+	NoteOwnersFinder servicenote.NoteOwnersFinder
+	UserFinder       servicenote.UserFinder
 }
 
 func NewNoteService(options NoteServiceOptions) NoteService {
@@ -28,5 +34,9 @@ func NewNoteService(options NoteServiceOptions) NoteService {
 		UpdateNoteRequestHandler:    servicenote.NewUpdateNoteRequestHandler(options.NoteUpdater),
 		DeleteNoteRequestHandler:    servicenote.NewDeleteNoteRequestHandler(options.NoteDeleter),
 		GetNotesAsyncRequestHandler: servicenote.NewGetNotesAsyncRequestHandler(options.NoteFinder),
+		PurgeNotesRequestHandler:    servicenote.NewPurgeNotesRequestHandler(options.NotesDeleter),
+		ReconcileNotesRequestHandler: servicenote.NewReconcileNotesRequestHandler(
+			options.NoteOwnersFinder, options.NotesDeleter, options.UserFinder,
+		),
 	}
 }
@@ -23,3 +23,17 @@ type NoteUpdater interface {
 type NoteDeleter interface {
 	DeleteOne(ctx context.Context, noteID string) error
 }
+
+type NotesDeleter interface {
+	DeleteMany(ctx context.Context, userID string) (int64, error)
+}
+
+type NoteOwnersFinder interface {
+	FindUserIDs(ctx context.Context) ([]string, error)
+}
+
+// UserFinder looks users up at the auth service. FindDeletedUserIDs returns the IDs of the given users that are known
+// to be deleted.
+type UserFinder interface {
+	FindDeletedUserIDs(ctx context.Context, userIDs []string) ([]string, error)
+}
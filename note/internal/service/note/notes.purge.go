@@ -0,0 +1,35 @@
+package note
+
+import (
+	"context"
+	"fmt"
+)
+
+// PurgeNotesRequest deletes all notes of a user, after the user deleted their account.
+type PurgeNotesRequest struct {
+	UserID string
+}
+
+type PurgeNotesResponse struct {
+	Deleted int64
+}
+
+type PurgeNotesRequestHandler interface {
+	Handle(ctx context.Context, request PurgeNotesRequest) (PurgeNotesResponse, error)
+}
+
+type purgeNotesRequestHandler struct {
+	NotesDeleter NotesDeleter
+}
+
+func NewPurgeNotesRequestHandler(notesDeleter NotesDeleter) PurgeNotesRequestHandler {
+	return &purgeNotesRequestHandler{NotesDeleter: notesDeleter}
+}
+
+func (h purgeNotesRequestHandler) Handle(ctx context.Context, request PurgeNotesRequest) (PurgeNotesResponse, error) {
+	deleted, err := h.NotesDeleter.DeleteMany(ctx, request.UserID)
+	if err != nil {
+		return PurgeNotesResponse{}, fmt.Errorf("failed to delete notes: %w", err)
+	}
+	return PurgeNotesResponse{Deleted: deleted}, nil
+}
@@ -0,0 +1,81 @@
+package note
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ReconcileNotesRequest purges the notes of users that were deleted, whose deletion events were missed. Only owners
+// that the auth service reports as deleted are purged: an owner it does not know, like a client or a user it failed to
+// look up, keeps their notes.
+type ReconcileNotesRequest struct{}
+
+type ReconcileNotesResponse struct {
+	PurgedUserIDs []string
+}
+
+type ReconcileNotesRequestHandler interface {
+	Handle(ctx context.Context, request ReconcileNotesRequest) (ReconcileNotesResponse, error)
+}
+
+type reconcileNotesRequestHandler struct {
+	NoteOwnersFinder NoteOwnersFinder
+	NotesDeleter     NotesDeleter
+	UserFinder       UserFinder
+}
+
+// reconcileBatchSize is the number of users looked up at the auth service at once, which is the most it allows.
+const reconcileBatchSize = 100
+
+func NewReconcileNotesRequestHandler(
+	noteOwnersFinder NoteOwnersFinder, notesDeleter NotesDeleter, userFinder UserFinder,
+) ReconcileNotesRequestHandler {
+	return &reconcileNotesRequestHandler{
+		NoteOwnersFinder: noteOwnersFinder,
+		NotesDeleter:     notesDeleter,
+		UserFinder:       userFinder,
+	}
+}
+
+func (h reconcileNotesRequestHandler) Handle(ctx context.Context, _ ReconcileNotesRequest) (ReconcileNotesResponse, error) {
+	owners, err := h.NoteOwnersFinder.FindUserIDs(ctx)
+	if err != nil {
+		return ReconcileNotesResponse{}, fmt.Errorf("failed to find note owners: %w", err)
+	}
+
+	// The auth service only knows users by UUID, so notes of other owners are left alone rather than taken for notes
+	// of deleted users.
+	userIDs := make([]string, 0, len(owners))
+	for _, owner := range owners {
+		if _, err := uuid.Parse(owner); err == nil {
+			userIDs = append(userIDs, owner)
+		}
+	}
+
+	purged := make([]string, 0)
+	for start := 0; start < len(userIDs); start += reconcileBatchSize {
+		batch := userIDs[start:min(start+reconcileBatchSize, len(userIDs))]
+
+		deleted, err := h.UserFinder.FindDeletedUserIDs(ctx, batch)
+		if err != nil {
+			return ReconcileNotesResponse{PurgedUserIDs: purged}, fmt.Errorf("failed to find deleted users: %w", err)
+		}
+
+		for _, userID := range deleted {
+			if _, err := h.NotesDeleter.DeleteMany(ctx, userID); err != nil {
+				return ReconcileNotesResponse{PurgedUserIDs: purged}, fmt.Errorf("failed to delete notes: %w", err)
+			}
+			purged = append(purged, userID)
+		}
+	}
+	return ReconcileNotesResponse{PurgedUserIDs: purged}, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,39 @@
+// Package auth provides repositories backed by the auth service.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	authpb "github.com/nazarslota/unotes/auth/api/proto"
+)
+
+// UserRepository looks users up at the auth service, authenticating as a confidential client.
+type UserRepository struct {
+	client       authpb.OAuth2ServiceClient
+	clientID     string
+	clientSecret string
+}
+
+// NewUserRepository creates a new UserRepository instance with an auth service client.
+func NewUserRepository(client authpb.OAuth2ServiceClient, clientID, clientSecret string) (*UserRepository, error) {
+	if client == nil {
+		return nil, errors.New("client is nil")
+	}
+	return &UserRepository{client: client, clientID: clientID, clientSecret: clientSecret}, nil
+}
+
+// FindDeletedUserIDs returns the IDs of the given users that the auth service reports as deleted. Users it does not
+// know are left out.
+func (r UserRepository) FindDeletedUserIDs(ctx context.Context, userIDs []string) ([]string, error) {
+	response, err := r.client.GetUsers(ctx, &authpb.GetUsersRequest{
+		ClientId:     r.clientID,
+		ClientSecret: r.clientSecret,
+		UserIds:      userIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding users failed: %w", err)
+	}
+	return response.DeletedUserIds, nil
+}
@@ -0,0 +1,211 @@
+// Package memory provides an in-memory note repository implementation, so that the service can run without any
+// database, for local development and tests. Nothing is persisted, everything is lost when the process exits.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	domain "github.com/nazarslota/unotes/note/internal/domain/note"
+)
+
+// NoteRepository is a struct that provides methods for interacting with the notes kept in memory.
+type NoteRepository struct {
+	mu    *sync.Mutex
+	notes map[string]domain.Note
+}
+
+// NewNoteRepository creates a new empty NoteRepository.
+func NewNoteRepository() *NoteRepository {
+	return &NoteRepository{mu: new(sync.Mutex), notes: make(map[string]domain.Note)}
+}
+
+// SaveOne saves a note to the memory.
+// If a note with the same ID already exists, returns an error.
+func (r NoteRepository) SaveOne(ctx context.Context, note domain.Note) error {
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return fmt.Errorf("saving note failed: %w", err)
+	}
+	defer unlock()
+
+	if _, ok := r.notes[note.ID]; ok {
+		return fmt.Errorf("saving note failed: %w", domain.ErrNoteAlreadyExist)
+	}
+	r.notes[note.ID] = clone(note)
+	return nil
+}
+
+// FindOne finds a note with a specific ID in the memory.
+// If no note is found, returns an error.
+func (r NoteRepository) FindOne(ctx context.Context, noteID string) (domain.Note, error) {
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return domain.Note{}, fmt.Errorf("finding note failed: %w", err)
+	}
+	defer unlock()
+
+	note, ok := r.notes[noteID]
+	if !ok {
+		return domain.Note{}, fmt.Errorf("finding note failed: %w", domain.ErrNoteNotFound)
+	}
+	return clone(note), nil
+}
+
+// FindMany finds all notes associated with a specific user in the memory, oldest first.
+// If no notes are found, returns an error.
+func (r NoteRepository) FindMany(ctx context.Context, userID string) ([]domain.Note, error) {
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("finding collection failed: %w", err)
+	}
+	defer unlock()
+
+	var notes []domain.Note
+	for _, note := range r.notes {
+		if note.UserID == userID {
+			notes = append(notes, clone(note))
+		}
+	}
+
+	if len(notes) == 0 {
+		return nil, fmt.Errorf("finding collection failed: %w", domain.ErrNoteNotFound)
+	}
+
+	sort.Slice(notes, func(i, j int) bool {
+		if !notes[i].CreatedAt.Equal(notes[j].CreatedAt) {
+			return notes[i].CreatedAt.Before(notes[j].CreatedAt)
+		}
+		return notes[i].ID < notes[j].ID
+	})
+	return notes, nil
+}
+
+// FindManyAsync finds all notes associated with a specific user in the memory and sends them on the returned notes
+// channel. Once the notes are sent, both channels are closed; if no notes are found or the search fails, the error is
+// sent on the errors channel first. Sending stops when ctx is done.
+func (r NoteRepository) FindManyAsync(ctx context.Context, userID string) (<-chan domain.Note, <-chan error) {
+	notes, errs := make(chan domain.Note), make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(notes)
+
+		found, err := r.FindMany(ctx, userID)
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		for _, note := range found {
+			select {
+			case notes <- note:
+			case <-ctx.Done():
+				errs <- fmt.Errorf("finding collection failed: %w", ctx.Err())
+				return
+			}
+		}
+	}()
+	return notes, errs
+}
+
+// UpdateOne updates the title, content, priority and completion time of a note in the memory.
+// If no note with the specified ID is found, returns an error.
+func (r NoteRepository) UpdateOne(ctx context.Context, note domain.Note) error {
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return fmt.Errorf("updating note failed: %w", err)
+	}
+	defer unlock()
+
+	stored, ok := r.notes[note.ID]
+	if !ok {
+		return fmt.Errorf("updating note failed: %w", domain.ErrNoteNotFound)
+	}
+
+	stored.Title = note.Title
+	stored.Content = note.Content
+	stored.Priority = note.Priority
+	stored.CompletionTime = note.CompletionTime
+	r.notes[note.ID] = clone(stored)
+	return nil
+}
+
+// DeleteOne deletes a note from the memory.
+// If no note with the specified ID is found, returns an error.
+func (r NoteRepository) DeleteOne(ctx context.Context, noteID string) error {
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return fmt.Errorf("deleting note failed: %w", err)
+	}
+	defer unlock()
+
+	if _, ok := r.notes[noteID]; !ok {
+		return fmt.Errorf("deleting note failed: %w", domain.ErrNoteNotFound)
+	}
+	delete(r.notes, noteID)
+	return nil
+}
+
+// DeleteMany deletes all notes associated with a specific user from the memory and returns the number of deleted
+// notes. Deleting the notes of a user without notes is not an error.
+func (r NoteRepository) DeleteMany(ctx context.Context, userID string) (int64, error) {
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("deleting notes failed: %w", err)
+	}
+	defer unlock()
+
+	var deleted int64
+	for id, note := range r.notes {
+		if note.UserID == userID {
+			delete(r.notes, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// FindUserIDs finds the IDs of all users that have notes in the memory.
+func (r NoteRepository) FindUserIDs(ctx context.Context) ([]string, error) {
+	unlock, err := r.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("finding user ids failed: %w", err)
+	}
+	defer unlock()
+
+	seen := make(map[string]struct{})
+	userIDs := make([]string, 0)
+	for _, note := range r.notes {
+		if _, ok := seen[note.UserID]; !ok {
+			seen[note.UserID] = struct{}{}
+			userIDs = append(userIDs, note.UserID)
+		}
+	}
+	sort.Strings(userIDs)
+	return userIDs, nil
+}
+
+// lock locks the repository and returns the function that unlocks it. If ctx is done, the repository is not locked
+// and the error of ctx is returned, as a database would fail the query.
+func (r NoteRepository) lock(ctx context.Context) (func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	return r.mu.Unlock, nil
+}
+
+// clone returns a copy of note that shares no memory with it, so that the caller can't change a stored note.
+func clone(note domain.Note) domain.Note {
+	if note.Priority != nil {
+		priority := *note.Priority
+		note.Priority = &priority
+	}
+	if note.CompletionTime != nil {
+		completionTime := *note.CompletionTime
+		note.CompletionTime = &completionTime
+	}
+	return note
+}
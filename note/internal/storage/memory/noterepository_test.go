@@ -0,0 +1,11 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/nazarslota/unotes/note/internal/storage/storagetest"
+)
+
+func TestNoteRepository_Conformance(t *testing.T) {
+	storagetest.TestNoteRepository(t, NewNoteRepository())
+}
@@ -0,0 +1,40 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4/database"
+	migratemongodb "github.com/golang-migrate/migrate/v4/database/mongodb"
+	"github.com/nazarslota/unotes/auth/pkg/migrate"
+	"github.com/nazarslota/unotes/note/schema"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// migrateLockTimeout is the number of seconds to wait for the migrations lock held by another replica.
+const migrateLockTimeout = 60
+
+// NewMigrator returns a new migrator that applies the migrations of the schema to db. The migrations are applied
+// under a lock, a document of the migrate_advisory_lock collection, so replicas don't apply them concurrently.
+func NewMigrator(db *mongo.Database) (*migrate.Migrator, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	driver, err := migratemongodb.WithInstance(db.Client(), &migratemongodb.Config{
+		DatabaseName: db.Name(),
+		Locking:      migratemongodb.Locking{Enabled: true, Timeout: migrateLockTimeout},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrate driver: %w", err)
+	}
+	return migrate.NewMigrator(schema.Migrations, "mongodb", clientKeeper{driver})
+}
+
+// clientKeeper keeps the client of the driver connected when the migrator is closed, the client belongs to the
+// caller.
+type clientKeeper struct {
+	database.Driver
+}
+
+func (clientKeeper) Close() error { return nil }
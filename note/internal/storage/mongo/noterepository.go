@@ -111,6 +111,32 @@ func (r NoteRepository) DeleteOne(ctx context.Context, noteID string) error {
 	return nil
 }
 
+// DeleteMany deletes all notes associated with a specific user from the MongoDB collection and returns the number
+// of deleted notes. Deleting the notes of a user without notes is not an error.
+func (r NoteRepository) DeleteMany(ctx context.Context, userID string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, fmt.Errorf("deleting notes failed: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
+// FindUserIDs finds the IDs of all users that have notes in the MongoDB collection.
+func (r NoteRepository) FindUserIDs(ctx context.Context) ([]string, error) {
+	values, err := r.collection.Distinct(ctx, "user_id", bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("finding user ids failed: %w", err)
+	}
+
+	userIDs := make([]string, 0, len(values))
+	for _, value := range values {
+		if userID, ok := value.(string); ok {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs, nil
+}
+
 func (r NoteRepository) FindManyAsync(ctx context.Context, userID string) (<-chan domain.Note, <-chan error) { // TODO: Documentation.
 	notes, errs := make(chan domain.Note), make(chan error)
 	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID})
@@ -2,13 +2,22 @@
 package storage
 
 import (
+	"github.com/jmoiron/sqlx"
+	authpb "github.com/nazarslota/unotes/auth/api/proto"
+	storageauth "github.com/nazarslota/unotes/note/internal/storage/auth"
+	storagememory "github.com/nazarslota/unotes/note/internal/storage/memory"
 	storagemongo "github.com/nazarslota/unotes/note/internal/storage/mongo"
+	storagesqlite "github.com/nazarslota/unotes/note/internal/storage/sqlite"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-// RepositoryProvider is a provider for the note repository.
+// RepositoryProvider is a provider for the note repositories, one per storage the service can run with, and the auth
+// service backed user repository.
 type RepositoryProvider struct {
-	MongoNoteRepository *storagemongo.NoteRepository
+	MongoNoteRepository  *storagemongo.NoteRepository
+	MemoryNoteRepository *storagememory.NoteRepository
+	SQLiteNoteRepository *storagesqlite.NoteRepository
+	AuthUserRepository   *storageauth.UserRepository
 }
 
 // RepositoryProviderOption is a functional option for the RepositoryProvider.
@@ -31,3 +40,27 @@ func WithMongoNoteRepository(db *mongo.Database) RepositoryProviderOption {
 		rp.MongoNoteRepository, _ = storagemongo.NewNoteRepository(db)
 	}
 }
+
+// WithMemoryNoteRepository is a functional option that sets the MemoryNoteRepository
+// of the RepositoryProvider to a new instance of `memory.NoteRepository`.
+func WithMemoryNoteRepository() RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.MemoryNoteRepository = storagememory.NewNoteRepository()
+	}
+}
+
+// WithSQLiteNoteRepository is a functional option that sets the SQLiteNoteRepository
+// of the RepositoryProvider to a new instance of `sqlite.NoteRepository`.
+func WithSQLiteNoteRepository(db *sqlx.DB) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.SQLiteNoteRepository, _ = storagesqlite.NewNoteRepository(db)
+	}
+}
+
+// WithAuthUserRepository is a functional option that sets the AuthUserRepository
+// of the RepositoryProvider to a new instance of `auth.UserRepository`.
+func WithAuthUserRepository(client authpb.OAuth2ServiceClient, clientID, clientSecret string) RepositoryProviderOption {
+	return func(rp *RepositoryProvider) {
+		rp.AuthUserRepository, _ = storageauth.NewUserRepository(client, clientID, clientSecret)
+	}
+}
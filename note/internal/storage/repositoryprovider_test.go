@@ -13,7 +13,13 @@ func TestNewRepositoryProvider(t *testing.T) {
 	t.Run("should create a new repository provider", func(t *testing.T) {
 		provider := NewRepositoryProvider()
 		assert.NotNil(t, provider)
-		assert.Nil(t, provider.NoteRepository)
+		assert.Nil(t, provider.MongoNoteRepository)
+	})
+
+	t.Run("should create a new repository provider with memory note repository", func(t *testing.T) {
+		provider := NewRepositoryProvider(WithMemoryNoteRepository())
+		assert.NotNil(t, provider)
+		assert.NotNil(t, provider.MemoryNoteRepository)
 	})
 
 	t.Run("should not create a new repository provider with given options", func(t *testing.T) {
@@ -29,7 +35,7 @@ func TestNewRepositoryProvider(t *testing.T) {
 
 		provider := NewRepositoryProvider(WithMongoNoteRepository(db))
 		assert.NotNil(t, provider)
-		assert.NotNil(t, provider.NoteRepository)
+		assert.NotNil(t, provider.MongoNoteRepository)
 
 		t.Cleanup(func() { _ = db.Drop(context.Background()) })
 	})
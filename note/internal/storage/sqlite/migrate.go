@@ -0,0 +1,33 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4/database"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/jmoiron/sqlx"
+	"github.com/nazarslota/unotes/auth/pkg/migrate"
+	schemasqlite "github.com/nazarslota/unotes/note/schema/sqlite"
+)
+
+// NewMigrator returns a new migrator that applies the migrations of the schema to db. Closing the migrator leaves db
+// open.
+func NewMigrator(db *sqlx.DB) (*migrate.Migrator, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+
+	driver, err := migratesqlite.WithInstance(db.DB, &migratesqlite.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrate driver: %w", err)
+	}
+	return migrate.NewMigrator(schemasqlite.Migrations, "sqlite", dbKeeper{driver})
+}
+
+// dbKeeper keeps the database of the driver open when the migrator is closed, the database belongs to the caller.
+type dbKeeper struct {
+	database.Driver
+}
+
+func (dbKeeper) Close() error { return nil }
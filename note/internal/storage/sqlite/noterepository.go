@@ -0,0 +1,229 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	domain "github.com/nazarslota/unotes/note/internal/domain/note"
+)
+
+// NoteRepository is a struct that provides methods for interacting with the SQLite database.
+type NoteRepository struct {
+	db *sqlx.DB
+}
+
+// noteRow is a row of the notes table.
+type noteRow struct {
+	ID             string         `db:"id"`
+	Title          string         `db:"title"`
+	Content        string         `db:"content"`
+	UserID         string         `db:"user_id"`
+	CreatedAt      time.Time      `db:"created_at"`
+	Priority       sql.NullString `db:"priority"`
+	CompletionTime sql.NullTime   `db:"completion_time"`
+}
+
+func (row noteRow) note() domain.Note {
+	note := domain.Note{
+		ID:        row.ID,
+		Title:     row.Title,
+		Content:   row.Content,
+		UserID:    row.UserID,
+		CreatedAt: row.CreatedAt.UTC(),
+	}
+	if row.Priority.Valid {
+		note.Priority = &row.Priority.String
+	}
+	if row.CompletionTime.Valid {
+		completionTime := row.CompletionTime.Time.UTC()
+		note.CompletionTime = &completionTime
+	}
+	return note
+}
+
+// NewNoteRepository creates a new NoteRepository instance with a SQLite database.
+func NewNoteRepository(db *sqlx.DB) (*NoteRepository, error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	return &NoteRepository{db: db}, nil
+}
+
+// SaveOne saves a note to the SQLite database.
+// If a note with the same ID already exists, returns an error.
+func (r NoteRepository) SaveOne(ctx context.Context, note domain.Note) error {
+	query := `INSERT INTO notes (id, title, content, user_id, created_at, priority, completion_time)
+VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		note.ID, note.Title, note.Content, note.UserID, note.CreatedAt.UTC(), note.Priority, utc(note.CompletionTime),
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("saving note failed: %w", domain.ErrNoteAlreadyExist)
+	} else if err != nil {
+		return fmt.Errorf("saving note failed: %w", err)
+	}
+	return nil
+}
+
+// FindOne finds a note with a specific ID in the SQLite database.
+// If no note is found, returns an error.
+func (r NoteRepository) FindOne(ctx context.Context, noteID string) (domain.Note, error) {
+	query := `SELECT * FROM notes WHERE id = $1`
+
+	var row noteRow
+	if err := r.db.GetContext(ctx, &row, query, noteID); errors.Is(err, sql.ErrNoRows) {
+		return domain.Note{}, fmt.Errorf("finding note failed: %w", domain.ErrNoteNotFound)
+	} else if err != nil {
+		return domain.Note{}, fmt.Errorf("finding note failed: %w", err)
+	}
+	return row.note(), nil
+}
+
+// FindMany finds all notes associated with a specific user in the SQLite database, oldest first.
+// If no notes are found, returns an error.
+func (r NoteRepository) FindMany(ctx context.Context, userID string) ([]domain.Note, error) {
+	query := `SELECT * FROM notes WHERE user_id = $1 ORDER BY created_at, id`
+
+	var rows []noteRow
+	if err := r.db.SelectContext(ctx, &rows, query, userID); err != nil {
+		return nil, fmt.Errorf("finding collection failed: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("finding collection failed: %w", domain.ErrNoteNotFound)
+	}
+
+	notes := make([]domain.Note, 0, len(rows))
+	for _, row := range rows {
+		notes = append(notes, row.note())
+	}
+	return notes, nil
+}
+
+// FindManyAsync finds all notes associated with a specific user in the SQLite database and sends them on the
+// returned notes channel as they are read. Once the notes are sent, both channels are closed; if no notes are found
+// or reading fails, the error is sent on the errors channel first. Reading stops when ctx is done.
+func (r NoteRepository) FindManyAsync(ctx context.Context, userID string) (<-chan domain.Note, <-chan error) {
+	notes, errs := make(chan domain.Note), make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(notes)
+
+		if err := r.sendNotes(ctx, userID, notes); err != nil {
+			errs <- err
+		}
+	}()
+	return notes, errs
+}
+
+func (r NoteRepository) sendNotes(ctx context.Context, userID string, notes chan<- domain.Note) error {
+	query := `SELECT * FROM notes WHERE user_id = $1 ORDER BY created_at, id`
+
+	rows, err := r.db.QueryxContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("finding collection failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	found := false
+	for rows.Next() {
+		var row noteRow
+		if err := rows.StructScan(&row); err != nil {
+			return fmt.Errorf("finding collection failed: %w", err)
+		}
+
+		select {
+		case notes <- row.note():
+			found = true
+		case <-ctx.Done():
+			return fmt.Errorf("finding collection failed: %w", ctx.Err())
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("finding collection failed: %w", err)
+	} else if !found {
+		return fmt.Errorf("finding collection failed: %w", domain.ErrNoteNotFound)
+	}
+	return nil
+}
+
+// UpdateOne updates the title, content, priority and completion time of a note in the SQLite database.
+// If no note with the specified ID is found, returns an error.
+func (r NoteRepository) UpdateOne(ctx context.Context, note domain.Note) error {
+	query := `UPDATE notes SET title = $2, content = $3, priority = $4, completion_time = $5 WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query,
+		note.ID, note.Title, note.Content, note.Priority, utc(note.CompletionTime),
+	)
+	if err != nil {
+		return fmt.Errorf("updating note failed: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("updating note failed: %w", err)
+	} else if affected == 0 {
+		return fmt.Errorf("updating note failed: %w", domain.ErrNoteNotFound)
+	}
+	return nil
+}
+
+// DeleteOne deletes a note from the SQLite database.
+// If no note with the specified ID is found, returns an error.
+func (r NoteRepository) DeleteOne(ctx context.Context, noteID string) error {
+	query := `DELETE FROM notes WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, noteID)
+	if err != nil {
+		return fmt.Errorf("deleting note failed: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("deleting note failed: %w", err)
+	} else if affected == 0 {
+		return fmt.Errorf("deleting note failed: %w", domain.ErrNoteNotFound)
+	}
+	return nil
+}
+
+// DeleteMany deletes all notes associated with a specific user from the SQLite database and returns the number of
+// deleted notes. Deleting the notes of a user without notes is not an error.
+func (r NoteRepository) DeleteMany(ctx context.Context, userID string) (int64, error) {
+	query := `DELETE FROM notes WHERE user_id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("deleting notes failed: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("deleting notes failed: %w", err)
+	}
+	return deleted, nil
+}
+
+// FindUserIDs finds the IDs of all users that have notes in the SQLite database.
+func (r NoteRepository) FindUserIDs(ctx context.Context) ([]string, error) {
+	query := `SELECT DISTINCT user_id FROM notes ORDER BY user_id`
+
+	userIDs := make([]string, 0)
+	if err := r.db.SelectContext(ctx, &userIDs, query); err != nil {
+		return nil, fmt.Errorf("finding user ids failed: %w", err)
+	}
+	return userIDs, nil
+}
+
+// utc returns t in UTC, or nil if t is nil.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
@@ -0,0 +1,30 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/nazarslota/unotes/note/internal/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNoteRepository(t *testing.T) {
+	t.Run("should create new note repository", func(t *testing.T) {
+		repository, err := NewNoteRepository(newTestDB(t))
+		assert.NoError(t, err)
+		assert.NotNil(t, repository)
+	})
+
+	t.Run("should return error if db is nil", func(t *testing.T) {
+		repository, err := NewNoteRepository(nil)
+		assert.Error(t, err)
+		assert.Nil(t, repository)
+	})
+}
+
+func TestNoteRepository_Conformance(t *testing.T) {
+	repository, err := NewNoteRepository(newTestDB(t))
+	require.NoError(t, err)
+
+	storagetest.TestNoteRepository(t, repository)
+}
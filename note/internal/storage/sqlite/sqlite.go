@@ -0,0 +1,57 @@
+// Package sqlite provides a SQLite database repository implementation, for deployments that don't want to operate a
+// MongoDB server. Times are stored as text in UTC.
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Config represents SQLite configuration.
+type Config struct {
+	Path string // Path specifies the path of the database file, which is created if it does not exist.
+}
+
+// NewSQLite returns a new instance of the *sqlx.DB type using the provided configuration.
+func NewSQLite(ctx context.Context, config Config) (*sqlx.DB, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("invalid context: %w", err)
+	}
+
+	if len(config.Path) == 0 {
+		return nil, fmt.Errorf("path is empty")
+	}
+
+	query := url.Values{}
+	query.Add("_pragma", "busy_timeout(5000)")
+	query.Add("_pragma", "journal_mode(WAL)")
+	query.Set("_time_format", "sqlite")
+	dsn := fmt.Sprintf("file:%s?%s", config.Path, query.Encode())
+
+	db, err := sqlx.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	return db, nil
+}
+
+// isUniqueViolation reports whether err is a violation of a unique or primary key constraint.
+func isUniqueViolation(err error) bool {
+	sqliteErr := new(sqlite.Error)
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDB opens a new SQLite database in a temporary directory and applies the migrations of the schema to it.
+func newTestDB(t *testing.T) *sqlx.DB {
+	db, err := NewSQLite(context.Background(), Config{Path: filepath.Join(t.TempDir(), "note.db")})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Up())
+	require.NoError(t, migrator.Close())
+	return db
+}
+
+func TestNewSQLite(t *testing.T) {
+	t.Run("should create new sqlite database", func(t *testing.T) {
+		db, err := NewSQLite(context.Background(), Config{Path: filepath.Join(t.TempDir(), "note.db")})
+		require.NoError(t, err)
+		assert.NoError(t, db.Close())
+	})
+
+	t.Run("should return error if context is invalid", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		db, err := NewSQLite(ctx, Config{Path: filepath.Join(t.TempDir(), "note.db")})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, db)
+	})
+
+	t.Run("should return error if path is invalid", func(t *testing.T) {
+		db, err := NewSQLite(context.Background(), Config{Path: filepath.Join(t.TempDir(), "missing", "note.db")})
+		assert.Error(t, err)
+		assert.Nil(t, db)
+	})
+}
+
+func TestNewMigrator(t *testing.T) {
+	t.Run("should apply and roll back all migrations", func(t *testing.T) {
+		db := newTestDB(t)
+
+		migrator, err := NewMigrator(db)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = migrator.Close() })
+
+		status, err := migrator.Status()
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), status.Version)
+
+		require.NoError(t, migrator.Down(int(status.Version)))
+		require.NoError(t, migrator.Up())
+		assert.NoError(t, db.Ping(), "closing the migrator must leave the database open")
+	})
+
+	t.Run("should return error if db is nil", func(t *testing.T) {
+		migrator, err := NewMigrator(nil)
+		assert.EqualError(t, err, "db is nil")
+		assert.Nil(t, migrator)
+	})
+}
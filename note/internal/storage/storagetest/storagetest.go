@@ -0,0 +1,333 @@
+// Package storagetest provides the conformance suite of the note repository, which every implementation runs so that
+// the service behaves the same whichever storage it is configured with.
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "github.com/nazarslota/unotes/note/internal/domain/note"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// NoteRepository is the note repository under test.
+type NoteRepository interface {
+	SaveOne(ctx context.Context, note domain.Note) error
+	FindOne(ctx context.Context, noteID string) (domain.Note, error)
+	FindMany(ctx context.Context, userID string) ([]domain.Note, error)
+	FindManyAsync(ctx context.Context, userID string) (<-chan domain.Note, <-chan error)
+	UpdateOne(ctx context.Context, note domain.Note) error
+	DeleteOne(ctx context.Context, noteID string) error
+	DeleteMany(ctx context.Context, userID string) (int64, error)
+	FindUserIDs(ctx context.Context) ([]string, error)
+}
+
+var (
+	noteAA = domain.Note{
+		ID:        "note-a-id",
+		Title:     "note-a-title",
+		Content:   "note-a-content",
+		UserID:    "user-a-id",
+		CreatedAt: time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	noteAB = domain.Note{
+		ID:             "note-b-id",
+		Title:          "note-b-title",
+		Content:        "note-b-content",
+		UserID:         "user-a-id",
+		CreatedAt:      time.Date(2023, time.March, 2, 12, 0, 0, 0, time.UTC),
+		Priority:       func() *string { priority := "high"; return &priority }(),
+		CompletionTime: func() *time.Time { t := time.Date(2023, time.March, 9, 18, 30, 0, 0, time.UTC); return &t }(),
+	}
+
+	noteBA = domain.Note{
+		ID:        "note-c-id",
+		Title:     "note-c-title",
+		UserID:    "user-b-id",
+		CreatedAt: time.Date(2023, time.March, 3, 12, 0, 0, 0, time.UTC),
+	}
+)
+
+// TestNoteRepository runs the conformance suite against repository, which must start without notes of the users of
+// the suite. The notes saved by a case are deleted once it is run.
+func TestNoteRepository(t *testing.T, repository NoteRepository) {
+	t.Run("SaveOne", func(t *testing.T) { testSaveOne(t, repository) })
+	t.Run("FindOne", func(t *testing.T) { testFindOne(t, repository) })
+	t.Run("FindMany", func(t *testing.T) { testFindMany(t, repository) })
+	t.Run("UpdateOne", func(t *testing.T) { testUpdateOne(t, repository) })
+	t.Run("DeleteOne", func(t *testing.T) { testDeleteOne(t, repository) })
+	t.Run("DeleteMany", func(t *testing.T) { testDeleteMany(t, repository) })
+	t.Run("FindManyAsync", func(t *testing.T) { testFindManyAsync(t, repository) })
+}
+
+// saveNotes saves notes to repository and deletes the notes of their users once the test is run.
+func saveNotes(t *testing.T, repository NoteRepository, notes ...domain.Note) {
+	t.Helper()
+	for _, note := range notes {
+		require.NoError(t, repository.SaveOne(context.Background(), note))
+	}
+	cleanup(t, repository)
+}
+
+// cleanup deletes the notes of the users of the suite once the test is run.
+func cleanup(t *testing.T, repository NoteRepository) {
+	t.Cleanup(func() {
+		for _, userID := range []string{noteAA.UserID, noteBA.UserID} {
+			_, _ = repository.DeleteMany(context.Background(), userID)
+		}
+	})
+}
+
+func testSaveOne(t *testing.T, repository NoteRepository) {
+	t.Run("should successfully save a note", func(t *testing.T) {
+		cleanup(t, repository)
+
+		err := repository.SaveOne(context.Background(), noteAB)
+		assert.NoError(t, err)
+
+		note, err := repository.FindOne(context.Background(), noteAB.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, noteAB, note)
+	})
+
+	t.Run("should return an error if context is invalid", func(t *testing.T) {
+		cleanup(t, repository)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := repository.SaveOne(ctx, noteAA)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = repository.FindOne(context.Background(), noteAA.ID)
+		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+	})
+
+	t.Run("should return an error if note already exists", func(t *testing.T) {
+		saveNotes(t, repository, noteAA)
+
+		err := repository.SaveOne(context.Background(), noteAA)
+		assert.ErrorIs(t, err, domain.ErrNoteAlreadyExist)
+	})
+}
+
+func testFindOne(t *testing.T, repository NoteRepository) {
+	t.Run("should return note", func(t *testing.T) {
+		saveNotes(t, repository, noteAA)
+
+		result, err := repository.FindOne(context.Background(), noteAA.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, noteAA, result)
+	})
+
+	t.Run("should return an error if context is invalid", func(t *testing.T) {
+		saveNotes(t, repository, noteAA)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		result, err := repository.FindOne(ctx, noteAA.ID)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, result)
+	})
+
+	t.Run("should return an error if note does not exist", func(t *testing.T) {
+		result, err := repository.FindOne(context.Background(), "invalid-note-id")
+		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+		assert.Empty(t, result)
+	})
+}
+
+func testFindMany(t *testing.T, repository NoteRepository) {
+	t.Run("should return notes", func(t *testing.T) {
+		saveNotes(t, repository, noteAA, noteAB, noteBA)
+
+		result, err := repository.FindMany(context.Background(), noteAA.UserID)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []domain.Note{noteAA, noteAB}, result)
+	})
+
+	t.Run("should return an error if context is invalid", func(t *testing.T) {
+		saveNotes(t, repository, noteAA, noteAB)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		result, err := repository.FindMany(ctx, noteAA.UserID)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, result)
+	})
+
+	t.Run("should return an error if notes does not exist", func(t *testing.T) {
+		result, err := repository.FindMany(context.Background(), "invalid-user-id")
+		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+		assert.Nil(t, result)
+	})
+}
+
+func testUpdateOne(t *testing.T, repository NoteRepository) {
+	t.Run("should update note", func(t *testing.T) {
+		saveNotes(t, repository, noteAA)
+
+		updated := noteAB
+		updated.ID, updated.UserID, updated.CreatedAt = noteAA.ID, noteAA.UserID, noteAA.CreatedAt
+
+		err := repository.UpdateOne(context.Background(), updated)
+		assert.NoError(t, err)
+
+		note, err := repository.FindOne(context.Background(), noteAA.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, updated, note)
+	})
+
+	t.Run("should not update owner and creation time", func(t *testing.T) {
+		saveNotes(t, repository, noteAA)
+
+		updated := noteAA
+		updated.UserID, updated.CreatedAt = noteBA.UserID, noteBA.CreatedAt
+
+		err := repository.UpdateOne(context.Background(), updated)
+		assert.NoError(t, err)
+
+		note, err := repository.FindOne(context.Background(), noteAA.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, noteAA, note)
+	})
+
+	t.Run("should return an error if context is invalid", func(t *testing.T) {
+		saveNotes(t, repository, noteAA)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		updated := noteAA
+		updated.Content = "updated-note-content"
+
+		err := repository.UpdateOne(ctx, updated)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		note, err := repository.FindOne(context.Background(), noteAA.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, noteAA, note)
+	})
+
+	t.Run("should return en error if note does not exist", func(t *testing.T) {
+		cleanup(t, repository)
+
+		err := repository.UpdateOne(context.Background(), noteAA)
+		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+
+		_, err = repository.FindOne(context.Background(), noteAA.ID)
+		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+	})
+}
+
+func testDeleteOne(t *testing.T, repository NoteRepository) {
+	t.Run("should successfully delete note", func(t *testing.T) {
+		saveNotes(t, repository, noteAA)
+
+		err := repository.DeleteOne(context.Background(), noteAA.ID)
+		assert.NoError(t, err)
+
+		_, err = repository.FindOne(context.Background(), noteAA.ID)
+		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+	})
+
+	t.Run("should return an error if context is invalid", func(t *testing.T) {
+		saveNotes(t, repository, noteAA)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := repository.DeleteOne(ctx, noteAA.ID)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = repository.FindOne(context.Background(), noteAA.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return an error if note does not exist", func(t *testing.T) {
+		err := repository.DeleteOne(context.Background(), "invalid-note-id")
+		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+	})
+}
+
+func testDeleteMany(t *testing.T, repository NoteRepository) {
+	t.Run("should delete all notes of user", func(t *testing.T) {
+		saveNotes(t, repository, noteAA, noteAB, noteBA)
+
+		deleted, err := repository.DeleteMany(context.Background(), noteAA.UserID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		userIDs, err := repository.FindUserIDs(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{noteBA.UserID}, userIDs)
+	})
+
+	t.Run("should not return an error if user has no notes", func(t *testing.T) {
+		deleted, err := repository.DeleteMany(context.Background(), "invalid-user-id")
+		assert.NoError(t, err)
+		assert.Zero(t, deleted)
+	})
+}
+
+func testFindManyAsync(t *testing.T, repository NoteRepository) {
+	t.Run("should successfully find notes", func(t *testing.T) {
+		saveNotes(t, repository, noteAA, noteAB, noteBA)
+
+		notes, errs := repository.FindManyAsync(context.Background(), noteAA.UserID)
+
+		note, ok := <-notes
+		assert.True(t, ok)
+		assert.Contains(t, []domain.Note{noteAA, noteAB}, note)
+
+		note, ok = <-notes
+		assert.True(t, ok)
+		assert.Contains(t, []domain.Note{noteAA, noteAB}, note)
+
+		note, ok = <-notes
+		assert.False(t, ok)
+		assert.Empty(t, note)
+
+		err, ok := <-errs
+		assert.False(t, ok)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return an error if context is invalid", func(t *testing.T) {
+		saveNotes(t, repository, noteAA, noteAB)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		notes, errs := repository.FindManyAsync(ctx, noteAA.UserID)
+
+		note, ok := <-notes
+		assert.False(t, ok)
+		assert.Empty(t, note)
+
+		err, ok := <-errs
+		assert.True(t, ok)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		err, ok = <-errs
+		assert.False(t, ok)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return an error if note does not exist", func(t *testing.T) {
+		notes, errs := repository.FindManyAsync(context.Background(), "invalid-user-id")
+
+		note, ok := <-notes
+		assert.False(t, ok)
+		assert.Empty(t, note)
+
+		err, ok := <-errs
+		assert.True(t, ok)
+		assert.ErrorIs(t, err, domain.ErrNoteNotFound)
+	})
+}
@@ -0,0 +1,133 @@
+// Package worker purges the notes of deleted users, when their deletion events arrive and periodically for users
+// whose events were missed, and keeps the denylist of revoked access tokens up to date.
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nazarslota/unotes/auth/api/events"
+	"github.com/nazarslota/unotes/auth/pkg/messagebus"
+	"github.com/nazarslota/unotes/note/internal/service"
+	servicenote "github.com/nazarslota/unotes/note/internal/service/note"
+)
+
+type Subscriber interface {
+	Subscribe(ctx context.Context, subject string, handler messagebus.Handler) (messagebus.Subscription, error)
+}
+
+type Logger interface {
+	InfoFields(msg string, fields map[string]any)
+	WarnFields(msg string, fields map[string]any)
+}
+
+// Worker consumes user deletion events from the message bus and purges the notes of the deleted users. The message
+// bus delivers at most once, so the Worker also reconciles the notes with the users at the auth service every
+// ReconcileInterval. If the services keep a denylist of revoked access tokens, the Worker also consumes token
+// revocation events and adds the tokens to it.
+type Worker struct {
+	Services   service.Services
+	Subscriber Subscriber
+
+	ReconcileInterval time.Duration
+	Logger            Logger
+}
+
+// NewWorker creates a new Worker. Events are not consumed if subscriber is nil, reconciliation is disabled if
+// reconcileInterval is not positive.
+func NewWorker(
+	services service.Services, subscriber Subscriber, reconcileInterval time.Duration, logger Logger,
+) *Worker {
+	return &Worker{
+		Services:   services,
+		Subscriber: subscriber,
+
+		ReconcileInterval: reconcileInterval,
+		Logger:            logger,
+	}
+}
+
+// Run consumes events and reconciles until the context is done. Failures are logged, failed reconciliations are
+// retried at the next interval.
+func (w *Worker) Run(ctx context.Context) error {
+	if w.Subscriber != nil {
+		subscription, err := w.Subscriber.Subscribe(ctx, events.SubjectUserDeleted, w.handleUserDeleted)
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", events.SubjectUserDeleted, err)
+		}
+		defer func() { _ = subscription.Unsubscribe() }()
+
+		if w.Services.JWTService.RevokedTokens != nil {
+			subscription, err := w.Subscriber.Subscribe(ctx, events.SubjectTokenRevoked, w.handleTokenRevoked)
+			if err != nil {
+				return fmt.Errorf("failed to subscribe to %s: %w", events.SubjectTokenRevoked, err)
+			}
+			defer func() { _ = subscription.Unsubscribe() }()
+		}
+	}
+
+	if w.ReconcileInterval <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(w.ReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		w.reconcile(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) handleUserDeleted(ctx context.Context, message messagebus.Message) {
+	var event events.UserDeleted
+	if err := json.Unmarshal(message.Data, &event); err != nil || len(event.UserID) == 0 {
+		w.Logger.WarnFields("Invalid user deleted event.", map[string]any{"error": err})
+		return
+	}
+
+	response, err := w.Services.NoteService.PurgeNotesRequestHandler.Handle(ctx, servicenote.PurgeNotesRequest{
+		UserID: event.UserID,
+	})
+	if err != nil {
+		// The notes are purged by the next reconciliation.
+		w.Logger.WarnFields("Failed to purge notes of deleted user.", map[string]any{
+			"user_id": event.UserID,
+			"error":   err,
+		})
+		return
+	}
+	w.Logger.InfoFields("Purged notes of deleted user.", map[string]any{
+		"user_id": event.UserID,
+		"deleted": response.Deleted,
+	})
+}
+
+func (w *Worker) handleTokenRevoked(_ context.Context, message messagebus.Message) {
+	var event events.TokenRevoked
+	if err := json.Unmarshal(message.Data, &event); err != nil || len(event.TokenID) == 0 {
+		w.Logger.WarnFields("Invalid token revoked event.", map[string]any{"error": err})
+		return
+	}
+	w.Services.JWTService.RevokedTokens.Revoke(event.TokenID, event.ExpiresAt)
+}
+
+func (w *Worker) reconcile(ctx context.Context) {
+	response, err := w.Services.NoteService.ReconcileNotesRequestHandler.Handle(ctx, servicenote.ReconcileNotesRequest{})
+	if len(response.PurgedUserIDs) != 0 {
+		w.Logger.InfoFields("Purged notes of users deleted while no event was received.", map[string]any{
+			"user_ids": response.PurgedUserIDs,
+		})
+	}
+	if err != nil && ctx.Err() == nil {
+		w.Logger.WarnFields("Failed to reconcile notes.", map[string]any{"error": err})
+	}
+}
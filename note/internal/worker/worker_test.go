@@ -0,0 +1,156 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nazarslota/unotes/auth/api/events"
+	"github.com/nazarslota/unotes/auth/pkg/logger"
+	"github.com/nazarslota/unotes/auth/pkg/messagebus"
+	"github.com/nazarslota/unotes/note/internal/service"
+	servicejwt "github.com/nazarslota/unotes/note/internal/service/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryNotes keeps the number of notes of each user and the users that the auth service reports as deleted.
+type memoryNotes struct {
+	mu      sync.Mutex
+	notes   map[string]int
+	deleted map[string]bool
+}
+
+func (n *memoryNotes) DeleteMany(_ context.Context, userID string) (int64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	deleted := n.notes[userID]
+	delete(n.notes, userID)
+	return int64(deleted), nil
+}
+
+func (n *memoryNotes) FindUserIDs(_ context.Context) ([]string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	userIDs := make([]string, 0, len(n.notes))
+	for userID := range n.notes {
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, nil
+}
+
+func (n *memoryNotes) FindDeletedUserIDs(_ context.Context, userIDs []string) ([]string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var deleted []string
+	for _, userID := range userIDs {
+		if n.deleted[userID] {
+			deleted = append(deleted, userID)
+		}
+	}
+	return deleted, nil
+}
+
+func (n *memoryNotes) count(userID string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.notes[userID]
+}
+
+// notifyingSubscriber closes subscribed once the worker subscribed.
+type notifyingSubscriber struct {
+	bus        *messagebus.MemoryBus
+	subscribed chan struct{}
+}
+
+func (s notifyingSubscriber) Subscribe(
+	ctx context.Context, subject string, handler messagebus.Handler,
+) (messagebus.Subscription, error) {
+	defer close(s.subscribed)
+	return s.bus.Subscribe(ctx, subject, handler)
+}
+
+const (
+	deletedUserID  = "3f1c8d3e-6f8a-4d1e-9c43-1a2b3c4d5e6f"
+	existingUserID = "7a9e2b1c-0d4f-4e8a-b5c6-d7e8f9a0b1c2"
+	unknownOwnerID = "c4d2e6f1-8b3a-4f7e-9d1c-5a6b7c8d9e0f"
+)
+
+func newTestWorker(notes *memoryNotes, subscriber Subscriber) *Worker {
+	services := service.NewServices(service.JWTServiceOptions{}, service.NoteServiceOptions{
+		NotesDeleter:     notes,
+		NoteOwnersFinder: notes,
+		UserFinder:       notes,
+	})
+	return NewWorker(services, subscriber, 0, logger.NewLogger(io.Discard))
+}
+
+func TestWorker_Run(t *testing.T) {
+	notes := &memoryNotes{
+		notes:   map[string]int{deletedUserID: 2, existingUserID: 1},
+		deleted: map[string]bool{deletedUserID: true},
+	}
+
+	bus := messagebus.NewMemoryBus()
+	subscriber := notifyingSubscriber{bus: bus, subscribed: make(chan struct{})}
+	w := newTestWorker(notes, subscriber)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	select {
+	case <-subscriber.subscribed:
+	case <-time.After(time.Second):
+		t.Fatal("worker did not subscribe")
+	}
+
+	data, err := json.Marshal(events.UserDeleted{UserID: deletedUserID, DeletedAt: time.Now()})
+	require.NoError(t, err)
+	require.NoError(t, bus.Publish(context.Background(), events.SubjectUserDeleted, data))
+
+	// Invalid events are ignored.
+	require.NoError(t, bus.Publish(context.Background(), events.SubjectUserDeleted, []byte("{")))
+
+	assert.Equal(t, 0, notes.count(deletedUserID))
+	assert.Equal(t, 1, notes.count(existingUserID))
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestWorker_reconcile(t *testing.T) {
+	notes := &memoryNotes{
+		notes:   map[string]int{deletedUserID: 2, existingUserID: 1, unknownOwnerID: 1, "not-a-uuid": 1},
+		deleted: map[string]bool{deletedUserID: true},
+	}
+	w := newTestWorker(notes, nil)
+
+	w.reconcile(context.Background())
+	assert.Equal(t, 0, notes.count(deletedUserID))
+	assert.Equal(t, 1, notes.count(existingUserID))
+	// Owners the auth service does not report as deleted, like clients, keep their notes.
+	assert.Equal(t, 1, notes.count(unknownOwnerID))
+	assert.Equal(t, 1, notes.count("not-a-uuid"))
+}
+
+func TestWorker_handleTokenRevoked(t *testing.T) {
+	revoked := servicejwt.NewRevokedTokens()
+	services := service.NewServices(service.JWTServiceOptions{RevokedTokens: revoked}, service.NoteServiceOptions{})
+	w := NewWorker(services, nil, 0, logger.NewLogger(io.Discard))
+
+	data, err := json.Marshal(events.TokenRevoked{TokenID: "token-id", ExpiresAt: time.Now().Add(time.Minute)})
+	require.NoError(t, err)
+	w.handleTokenRevoked(context.Background(), messagebus.Message{Subject: events.SubjectTokenRevoked, Data: data})
+	assert.True(t, revoked.IsRevoked("token-id"))
+
+	// Invalid events are ignored.
+	w.handleTokenRevoked(context.Background(), messagebus.Message{Subject: events.SubjectTokenRevoked, Data: []byte("{")})
+	assert.False(t, revoked.IsRevoked("other-token-id"))
+}
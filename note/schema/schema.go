@@ -0,0 +1,10 @@
+// Package schema embeds the migrations of the MongoDB collections of the service, their indexes and validators, so
+// that the binary can apply them. A migration is a JSON array of database commands.
+package schema
+
+import "embed"
+
+// Migrations are the migrations of the schema, named like 000001_notes.up.json.
+//
+//go:embed *.json
+var Migrations embed.FS
@@ -0,0 +1,13 @@
+CREATE TABLE "notes"
+(
+    "id"              text     NOT NULL,
+    "title"           text     NOT NULL,
+    "content"         text     NOT NULL DEFAULT '',
+    "user_id"         text     NOT NULL,
+    "created_at"      datetime NOT NULL,
+    "priority"        text,
+    "completion_time" datetime,
+    CONSTRAINT "notes_pk" PRIMARY KEY ("id")
+);
+
+CREATE INDEX "notes_user_id_idx" ON "notes" ("user_id");
@@ -1,10 +1,10 @@
 import React from "react";
 import {Link} from "react-router-dom";
-import {Cookies, withCookies} from "react-cookie";
+import AuthService from "../services/AuthService";
 
 import utils from "../utils/utils";
 
-export default withCookies(class NavBar extends React.Component<NavBarT.Props, NavBarT.State> {
+export default class NavBar extends React.Component<NavBarT.Props, NavBarT.State> {
     constructor(props: NavBarT.Props) {
         super(props);
         this.state = {
@@ -47,18 +47,27 @@ export default withCookies(class NavBar extends React.Component<NavBarT.Props, N
         </div>
     </>);
 
-    private onSignOut = (): void => {
+    private onSignOut = async (): Promise<void> => {
+        try {
+            await AuthService.signOut({
+                access_token: localStorage.getItem("access_token") ?? "",
+            }, {
+                csrf_token: localStorage.getItem("csrf_token") ?? "",
+            });
+        } catch (e) {
+            // The session is forgotten locally even if the auth service fails to end it.
+        }
+
         localStorage.removeItem("access_token");
-        this.props.cookies.remove('refresh_token');
+        localStorage.removeItem("csrf_token");
 
         window.location.reload();
     }
-});
+}
 
 export module NavBarT {
     export type Props = {
         className?: string;
-        cookies: Cookies;
     };
 
     export type State = {
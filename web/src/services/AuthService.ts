@@ -15,22 +15,26 @@ type SignOutRequest = {
     access_token: string;
 };
 
+type SignOutHeaders = {
+    csrf_token: string;
+};
+
 type RefreshRequest = {
-    refresh_token: string;
+    csrf_token: string;
 };
 
 type SignUpResponse = {};
 
 type SignInResponse = {
     access_token: string;
-    refresh_token: string;
+    csrf_token: string;
 };
 
 type SignOutResponse = {};
 
 type RefreshResponse = {
     access_token: string;
-    refresh_token: string;
+    csrf_token: string;
 };
 
 // AuthService
@@ -44,12 +48,16 @@ class AuthService {
         return $api.post("/oauth2/sign-in", request).then(response => response);
     };
 
-    static signOut = async (request: SignOutRequest): Promise<AxiosResponse<SignOutResponse>> => {
-        return $api.post("/oauth2/sign-out", request).then(response => response);
+    static signOut = async (request: SignOutRequest, headers: SignOutHeaders): Promise<AxiosResponse<SignOutResponse>> => {
+        return $api.post("/oauth2/sign-out", request, {
+            headers: {"X-CSRF-Token": headers.csrf_token},
+        }).then(response => response);
     };
 
     static refresh = async (request: RefreshRequest): Promise<AxiosResponse<RefreshResponse>> => {
-        return $api.get(`/oauth2/refresh?t=${request["refresh_token"]}`).then(response => response);
+        return $api.get("/oauth2/refresh", {
+            headers: {"X-CSRF-Token": request.csrf_token},
+        }).then(response => response);
     };
 }
 
@@ -2,8 +2,10 @@ import axios from "axios";
 
 const AUTH_SERVICE_API_URL = `${process.env.REACT_APP_AUTH_URL}/api`;
 
+// The refresh token is kept in an HttpOnly cookie by the auth service, so the requests must be sent with credentials.
 const $api = axios.create({
     baseURL: AUTH_SERVICE_API_URL,
+    withCredentials: true,
 });
 
 export default $api;
@@ -1,6 +1,5 @@
 import React from "react";
 import {Link} from "react-router-dom";
-import {Cookies, withCookies} from "react-cookie";
 
 import axios from "axios";
 
@@ -13,7 +12,7 @@ import NoteService from "../services/NoteService";
 import * as models from "../models/models";
 import utils from "../utils/utils";
 
-export default withCookies(class Home extends React.Component<HomeT.Props, HomeT.State> {
+export default class Home extends React.Component<HomeT.Props, HomeT.State> {
     constructor(props: HomeT.Props) {
         super(props);
         this.state = {
@@ -26,11 +25,11 @@ export default withCookies(class Home extends React.Component<HomeT.Props, HomeT
         if (!await utils.isUserSignedIn()) {
             try {
                 const {data} = await AuthService.refresh({
-                    refresh_token: this.props.cookies.get("refresh_token"),
+                    csrf_token: localStorage.getItem("csrf_token") ?? "",
                 });
 
                 localStorage.setItem("access_token", data.access_token);
-                this.props.cookies.set("refresh_token", data.refresh_token);
+                localStorage.setItem("csrf_token", data.csrf_token);
 
                 window.location.reload();
             } catch (e) {
@@ -137,12 +136,10 @@ export default withCookies(class Home extends React.Component<HomeT.Props, HomeT
             }
         })
     }
-});
+}
 
 export module HomeT {
-    export type Props = {
-        cookies: Cookies;
-    };
+    export type Props = {};
 
     export type State = {
         isUserSignedIn: boolean;
@@ -1,7 +1,6 @@
 import React from "react";
 import {Link} from "react-router-dom";
 import {ErrorMessage, Field, Form, Formik, FormikErrors, FormikHelpers} from "formik";
-import {Cookies, withCookies} from "react-cookie";
 
 import axios from "axios";
 import * as yup from "yup";
@@ -9,7 +8,7 @@ import * as yup from "yup";
 import AuthService from "../services/AuthService";
 import utils from "../utils/utils";
 
-export default withCookies(class SignIn extends React.Component<SignInT.Props, SignInT.State> {
+export default class SignIn extends React.Component<SignInT.Props, SignInT.State> {
     constructor(props: SignInT.Props) {
         super(props);
         this.state = {
@@ -99,7 +98,7 @@ export default withCookies(class SignIn extends React.Component<SignInT.Props, S
     private fromSubmit = (values: SignInT.FormValues, actions: FormikHelpers<SignInT.FormValues>): void => {
         AuthService.signIn({username: values.username, password: values.password}).then(r => {
             localStorage.setItem("access_token", r.data["access_token"]);
-            this.props.cookies.set("refresh_token", r.data["refresh_token"]);
+            localStorage.setItem("csrf_token", r.data["csrf_token"]);
 
             window.location.reload();
         }).catch(e => {
@@ -120,12 +119,10 @@ export default withCookies(class SignIn extends React.Component<SignInT.Props, S
             actions.setErrors({err: errors.err});
         })
     }
-});
+}
 
 export module SignInT {
-    export type Props = {
-        cookies: Cookies
-    };
+    export type Props = {};
 
     export type State = {
         isUserSignedIn: boolean;